@@ -0,0 +1,75 @@
+package cmd
+
+import (
+	"fmt"
+
+	"github.com/OlaHulleberg/clauderock/internal/accessibility"
+	"github.com/spf13/cobra"
+)
+
+var accessibilityCmd = &cobra.Command{
+	Use:   "accessibility",
+	Short: "Manage accessible mode for screen readers",
+	Long: `Manage accessible mode.
+
+In accessible mode, selectors and confirmations become numbered, line-by-line
+prompts, and stats are rendered without colour, emoji, borders, or progress
+bars. CLAUDEROCK_ACCESSIBLE=1 (or 0) overrides the saved setting for one run.`,
+}
+
+var accessibilityStatusCmd = &cobra.Command{
+	Use:   "status",
+	Short: "Show whether accessible mode is enabled",
+	RunE: func(cmd *cobra.Command, args []string) error {
+		settings, err := accessibility.LoadSettings()
+		if err != nil {
+			return err
+		}
+
+		state := "disabled"
+		if settings.Enabled {
+			state = "enabled"
+		}
+		if on, set := accessibility.EnvOverride(); set {
+			override := "disabled"
+			if on {
+				override = "enabled"
+			}
+			state += fmt.Sprintf(" (%s by %s for this run)", override, accessibility.EnvVar)
+		}
+		fmt.Printf("Accessible mode: %s\n", state)
+		return nil
+	},
+}
+
+var accessibilityEnableCmd = &cobra.Command{
+	Use:   "enable",
+	Short: "Use plain-text, screen-reader friendly prompts and output",
+	RunE: func(cmd *cobra.Command, args []string) error {
+		if err := accessibility.SetEnabled(true); err != nil {
+			return err
+		}
+		fmt.Println("Accessible mode enabled")
+		return nil
+	},
+}
+
+var accessibilityDisableCmd = &cobra.Command{
+	Use:   "disable",
+	Short: "Return to the full-screen selectors and styled output",
+	RunE: func(cmd *cobra.Command, args []string) error {
+		if err := accessibility.SetEnabled(false); err != nil {
+			return err
+		}
+		fmt.Println("Accessible mode disabled")
+		return nil
+	},
+}
+
+func init() {
+	// Registered by manage.go
+
+	accessibilityCmd.AddCommand(accessibilityStatusCmd)
+	accessibilityCmd.AddCommand(accessibilityEnableCmd)
+	accessibilityCmd.AddCommand(accessibilityDisableCmd)
+}
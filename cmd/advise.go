@@ -0,0 +1,347 @@
+package cmd
+
+import (
+	"fmt"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/OlaHulleberg/clauderock/internal/currency"
+	"github.com/OlaHulleberg/clauderock/internal/pricing"
+	"github.com/OlaHulleberg/clauderock/internal/usage"
+	"github.com/spf13/cobra"
+)
+
+var (
+	adviseProfile string
+	adviseDays    int
+)
+
+// Thresholds used by the advisor
+const (
+	adviseAlternativeModel = "anthropic.claude-sonnet-4-5"
+	adviseMinSavings       = 1.00   // Dollars; smaller savings are not worth a recommendation
+	adviseSmallSession     = 50_000 // Total tokens below which a session counts as a quick task
+	adviseLowCacheHitRate  = 30.0   // Percent
+	adviseMinSessions      = 3      // Sessions needed before a project's cache behaviour is judged
+	adviseMinFastShare     = 10.0   // Percent of requests below which the fast model is barely used
+	adviseMinMixRequests   = 200    // Requests needed before the model mix is judged
+)
+
+var adviseCmd = &cobra.Command{
+	Use:   "advise",
+	Short: "Recommend ways to reduce cost based on tracked usage",
+	Long: `Analyze tracked sessions and print concrete recommendations.
+
+Looks at the mix of requests between the fast and main models, model choice
+versus session size, cache hit rates per project, and cache writes that are
+never read back, and estimates what each change would have saved over the
+analyzed period. Each session's saving is counted once, against the same
+estimated cost 'manage stats' shows, so the combined total can be added up.
+
+Examples:
+  clauderock manage advise
+  clauderock manage advise --days 90
+  clauderock manage advise --profile work-dev`,
+	RunE: runAdvise,
+}
+
+func init() {
+	// Registered by manage.go
+
+	adviseCmd.Flags().StringVar(&adviseProfile, "profile", "", "Only analyze sessions from this profile")
+	adviseCmd.Flags().IntVar(&adviseDays, "days", 30, "Number of days of history to analyze")
+}
+
+// recommendation is a single piece of advice, optionally with an estimated saving
+type recommendation struct {
+	Title   string
+	Detail  string
+	Savings float64
+}
+
+func runAdvise(cmd *cobra.Command, args []string) error {
+	if adviseDays <= 0 {
+		return fmt.Errorf("--days must be positive")
+	}
+
+	db, err := usage.NewDatabase()
+	if err != nil {
+		return fmt.Errorf("failed to open usage database: %w", err)
+	}
+	defer db.Close()
+
+	now := time.Now()
+	filter := usage.QueryFilter{
+		ProfileName: adviseProfile,
+		StartDate:   now.AddDate(0, 0, -adviseDays),
+		EndDate:     now,
+	}
+	sessions, err := db.QuerySessions(filter)
+	if err != nil {
+		return fmt.Errorf("failed to query sessions: %w", err)
+	}
+
+	fmt.Println(headerStyle.Render("💡 Cost Advisor") + " " + mutedStyle.Render(fmt.Sprintf("(last %d days)", adviseDays)))
+	fmt.Println()
+
+	if len(sessions) == 0 {
+		fmt.Println(mutedStyle.Render("No sessions found to analyze."))
+		return nil
+	}
+
+	modelUsage, err := db.QueryModelUsage(filter)
+	if err != nil {
+		return fmt.Errorf("failed to query model usage: %w", err)
+	}
+	costs := usage.CostsByModel(modelUsage)
+	displayModelMix(costs)
+
+	var recs []recommendation
+	recs = append(recs, adviseModelChoice(sessions, adviseDays)...)
+	recs = append(recs, adviseFastModelShare(costs)...)
+	recs = append(recs, adviseCacheHitRate(sessions)...)
+	recs = append(recs, adviseCacheWrites(sessions)...)
+
+	if len(recs) == 0 {
+		fmt.Println(highlightStyle.Render("✓ No recommendations — usage looks efficient."))
+		return nil
+	}
+
+	// Biggest savings first; advice without an estimate goes last
+	sort.SliceStable(recs, func(i, j int) bool {
+		return recs[i].Savings > recs[j].Savings
+	})
+
+	var combined float64
+	for i, rec := range recs {
+		fmt.Printf("%s %s\n", mutedStyle.Render(fmt.Sprintf("%d.", i+1)), valueStyle.Render(rec.Title))
+		fmt.Printf("   %s\n", labelStyle.Render(rec.Detail))
+		if rec.Savings > 0 {
+			fmt.Printf("   %s %s\n", labelStyle.Render("Estimated savings:"), costStyle.Render(currency.Format(rec.Savings)))
+		}
+		fmt.Println()
+		combined += rec.Savings
+	}
+
+	// The estimates cover disjoint sessions, so they add up
+	if combined > 0 {
+		var spent float64
+		for _, s := range sessions {
+			spent += usage.SessionCost(s)
+		}
+		fmt.Printf("%s %s %s\n", labelStyle.Render("Combined estimated savings:"), costStyle.Render(currency.Format(combined)),
+			mutedStyle.Render(fmt.Sprintf("(%.0f%% of %s spent)", combined/spent*100, currency.Format(spent))))
+	}
+
+	return nil
+}
+
+// adviseModelChoice estimates savings from running opus-tier sessions on the alternative model
+// Savings are the difference in SessionCost with the main model swapped, so tokens sent to the
+// heavy model and the prompt cache are priced the same way as everywhere else. A model gets one
+// recommendation: switching its main sessions, or failing that, only its quick ones
+func adviseModelChoice(sessions []usage.Session, days int) []recommendation {
+	type modelUsage struct {
+		cost, savings   float64
+		smallSavings    float64
+		sessions, small int
+	}
+	byModel := make(map[string]*modelUsage)
+
+	for _, s := range sessions {
+		key := usage.PriceKey(s.Model)
+		if !strings.Contains(key, "opus") {
+			continue
+		}
+		if _, ok := pricing.GetModelPrice(key); !ok {
+			continue
+		}
+
+		u, ok := byModel[key]
+		if !ok {
+			u = &modelUsage{}
+			byModel[key] = u
+		}
+		alt := s
+		alt.Model = adviseAlternativeModel
+		cost := usage.SessionCost(s)
+		savings := cost - usage.SessionCost(alt)
+		u.cost += cost
+		u.savings += savings
+		u.sessions++
+		if s.TotalInputTokens+s.TotalOutputTokens < adviseSmallSession {
+			u.smallSavings += savings
+			u.small++
+		}
+	}
+
+	var recs []recommendation
+	for model, u := range byModel {
+		switch {
+		case u.savings >= adviseMinSavings:
+			detail := fmt.Sprintf("%d sessions cost %s on %s; keep it as the heavy model for hard problems instead",
+				u.sessions, currency.Format(u.cost), model)
+			if u.small > 0 {
+				detail += fmt.Sprintf(" (%d of them were quick tasks under %s tokens)", u.small, formatNumber(adviseSmallSession))
+			}
+			recs = append(recs, recommendation{
+				Title: fmt.Sprintf("Switching main from %s to %s would have saved %s in the last %d days",
+					model, adviseAlternativeModel, currency.Format(u.savings), days),
+				Detail:  detail,
+				Savings: u.savings,
+			})
+		case u.small > 0 && u.smallSavings >= adviseMinSavings:
+			recs = append(recs, recommendation{
+				Title: fmt.Sprintf("%d quick sessions ran on %s", u.small, model),
+				Detail: fmt.Sprintf("Sessions under %s tokens rarely need the heaviest model; use a %s profile for quick tasks",
+					formatNumber(adviseSmallSession), adviseAlternativeModel),
+				Savings: u.smallSavings,
+			})
+		}
+	}
+	return recs
+}
+
+// isFastModel reports whether a pricing key is a fast, Haiku-class model
+func isFastModel(key string) bool {
+	return strings.Contains(key, "haiku")
+}
+
+// displayModelMix shows how requests and cost split between the fast model and the main and
+// heavy models, from the calls recorded per model
+func displayModelMix(costs []usage.ModelCost) {
+	var fast, other usage.ModelCost
+	for _, m := range costs {
+		target := &other
+		if isFastModel(m.Model) {
+			target = &fast
+		}
+		target.Requests += m.Requests
+		target.TokenCost += m.TokenCost
+		target.CacheCost += m.CacheCost
+	}
+	requests := fast.Requests + other.Requests
+	cost := fast.Cost() + other.Cost()
+	if requests == 0 {
+		return
+	}
+
+	fmt.Println(sectionHeading("Model Mix"))
+	for _, row := range []struct {
+		label string
+		m     usage.ModelCost
+	}{
+		{"Fast models:", fast},
+		{"Main and heavy models:", other},
+	} {
+		costShare := 0.0
+		if cost > 0 {
+			costShare = row.m.Cost() / cost * 100
+		}
+		fmt.Printf("  %s %s %s\n",
+			labelStyle.Render(fmt.Sprintf("%-23s", row.label)),
+			valueStyle.Render(fmt.Sprintf("%5.1f%% of requests", float64(row.m.Requests)/float64(requests)*100)),
+			mutedStyle.Render(fmt.Sprintf("(%s, %.1f%% of cost)", currency.Format(row.m.Cost()), costShare)))
+	}
+	fmt.Println()
+}
+
+// adviseFastModelShare flags usage where the fast model handles almost none of the requests,
+// which happens when the fast slot is set to a main-class model
+func adviseFastModelShare(costs []usage.ModelCost) []recommendation {
+	var fast, requests int64
+	for _, m := range costs {
+		requests += m.Requests
+		if isFastModel(m.Model) {
+			fast += m.Requests
+		}
+	}
+	if requests < adviseMinMixRequests {
+		return nil
+	}
+	share := float64(fast) / float64(requests) * 100
+	if share >= adviseMinFastShare {
+		return nil
+	}
+	return []recommendation{{
+		Title: fmt.Sprintf("Only %.1f%% of requests went to a fast model", share),
+		Detail: "Claude Code sends background work such as titles and summaries to the fast model; " +
+			"set fast-model to a Haiku model with 'clauderock manage config set fast-model'",
+	}}
+}
+
+// adviseCacheHitRate flags projects whose prompt cache is rarely hit
+func adviseCacheHitRate(sessions []usage.Session) []recommendation {
+	type projectUsage struct {
+		weightedRate float64
+		requests     int
+		sessions     int
+	}
+	byProject := make(map[string]*projectUsage)
+
+	for _, s := range sessions {
+		if s.WorkingDirectory == "" || s.TotalRequests == 0 {
+			continue
+		}
+		p, ok := byProject[s.WorkingDirectory]
+		if !ok {
+			p = &projectUsage{}
+			byProject[s.WorkingDirectory] = p
+		}
+		p.weightedRate += s.CacheHitRate * float64(s.TotalRequests)
+		p.requests += s.TotalRequests
+		p.sessions++
+	}
+
+	var recs []recommendation
+	for dir, p := range byProject {
+		if p.sessions < adviseMinSessions || p.requests == 0 {
+			continue
+		}
+		rate := p.weightedRate / float64(p.requests)
+		if rate >= adviseLowCacheHitRate {
+			continue
+		}
+		recs = append(recs, recommendation{
+			Title: fmt.Sprintf("Cache hit rate is low in project %s (%.1f%%)", filepath.Base(dir), rate),
+			Detail: fmt.Sprintf("%s across %d sessions; fewer, longer sessions and a stable CLAUDE.md let the prompt cache be reused",
+				dir, p.sessions),
+		})
+	}
+	sort.Slice(recs, func(i, j int) bool { return recs[i].Title < recs[j].Title })
+	return recs
+}
+
+// adviseCacheWrites flags profiles that write to the prompt cache far more than they read from it
+func adviseCacheWrites(sessions []usage.Session) []recommendation {
+	type cacheUsage struct {
+		reads, writes int64
+	}
+	byProfile := make(map[string]*cacheUsage)
+
+	for _, s := range sessions {
+		c, ok := byProfile[s.ProfileName]
+		if !ok {
+			c = &cacheUsage{}
+			byProfile[s.ProfileName] = c
+		}
+		c.reads += s.CacheReadTokens
+		c.writes += s.CacheCreationTokens
+	}
+
+	var recs []recommendation
+	for profile, c := range byProfile {
+		if c.writes == 0 || c.reads >= c.writes {
+			continue
+		}
+		recs = append(recs, recommendation{
+			Title: fmt.Sprintf("Profile %s writes more to the prompt cache than it reads", profile),
+			Detail: fmt.Sprintf("%s tokens written vs %s read; sessions often end before cached context pays off",
+				formatNumber(c.writes), formatNumber(c.reads)),
+		})
+	}
+	sort.Slice(recs, func(i, j int) bool { return recs[i].Title < recs[j].Title })
+	return recs
+}
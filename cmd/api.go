@@ -0,0 +1,123 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/OlaHulleberg/clauderock/internal/api"
+	"github.com/OlaHulleberg/clauderock/internal/keyring"
+	"github.com/OlaHulleberg/clauderock/internal/profiles"
+	"github.com/spf13/cobra"
+)
+
+// maxCheckDetail caps how much of a gateway's error response a report line shows
+const maxCheckDetail = 120
+
+var (
+	apiProfile string
+	apiModel   string
+)
+
+var apiCmd = &cobra.Command{
+	Use:   "api",
+	Short: "Tools for API profiles and the gateways they use",
+}
+
+var apiConformanceCmd = &cobra.Command{
+	Use:   "conformance",
+	Short: "Check which Claude Code features an API gateway supports",
+	Long: `Send the kinds of requests Claude Code makes to an API profile's gateway and
+report which ones work: listing models, a small message, a streamed message,
+and a tool call. Use it to tell a proxy incompatibility from a clauderock
+problem.
+
+Requests use the profile's fast model unless --model is given, with tiny token
+limits, so a run costs a fraction of a cent.
+
+Examples:
+  clauderock manage api conformance
+  clauderock manage api conformance --profile gateway --model anthropic/claude-haiku-4-5`,
+	Args: cobra.NoArgs,
+	RunE: runAPIConformance,
+}
+
+func init() {
+	// Registered by manage.go
+	apiConformanceCmd.Flags().StringVar(&apiProfile, "profile", "", "Profile to check (defaults to the active profile)")
+	apiConformanceCmd.Flags().StringVar(&apiModel, "model", "", "Model to send requests to (defaults to the profile's fast model)")
+	apiCmd.AddCommand(apiConformanceCmd)
+}
+
+func runAPIConformance(cmd *cobra.Command, args []string) error {
+	mgr, err := profiles.NewManager()
+	if err != nil {
+		return fmt.Errorf("failed to create profile manager: %w", err)
+	}
+
+	name := apiProfile
+	if name == "" {
+		name, err = mgr.GetCurrent()
+		if err != nil {
+			return fmt.Errorf("failed to get current profile: %w", err)
+		}
+	}
+
+	cfg, err := mgr.Load(name)
+	if err != nil {
+		return fmt.Errorf("failed to load profile '%s': %w", name, err)
+	}
+	if cfg.ProfileType != "api" {
+		return fmt.Errorf("profile '%s' is a %s profile; conformance checks only apply to api profiles", name, cfg.ProfileType)
+	}
+
+	apiKey, err := keyring.Get(cfg.APIKeyID)
+	if err != nil {
+		return fmt.Errorf("failed to retrieve API key from keyring: %w", err)
+	}
+
+	model := apiModel
+	if model == "" {
+		model = cfg.FastModel
+	}
+
+	fmt.Printf("Checking gateway %s for profile '%s' with model %s\n\n", api.NormalizeBaseURL(cfg.BaseURL), name, model)
+	checks := api.RunConformance(cfg.BaseURL, apiKey, model)
+
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	var failed, degraded []string
+	for _, check := range checks {
+		status := "✓"
+		switch {
+		case !check.Passed && check.Required:
+			status = "✗"
+			failed = append(failed, check.Feature)
+		case !check.Passed:
+			status = "!"
+			degraded = append(degraded, check.Feature)
+		}
+		fmt.Fprintf(w, "  %s %s\t%dms\t%s\n", status, check.Name, check.Latency.Milliseconds(), shortDetail(check.Detail))
+	}
+	if err := w.Flush(); err != nil {
+		return err
+	}
+
+	if len(degraded) > 0 {
+		fmt.Printf("\nLimited: %s\n", strings.Join(degraded, "; "))
+	}
+	if len(failed) > 0 {
+		return fmt.Errorf("the gateway doesn't support requests Claude Code needs for: %s", strings.Join(failed, "; "))
+	}
+	fmt.Println("\nThe gateway supports the requests Claude Code makes.")
+	return nil
+}
+
+// shortDetail keeps the first line of a check's detail, cut to maxCheckDetail characters
+func shortDetail(detail string) string {
+	detail, _, _ = strings.Cut(detail, "\n")
+	if runes := []rune(detail); len(runes) > maxCheckDetail {
+		return string(runes[:maxCheckDetail-1]) + "…"
+	}
+	return detail
+}
@@ -2,27 +2,80 @@ package cmd
 
 import (
 	"fmt"
+	"strings"
 
 	"github.com/OlaHulleberg/clauderock/internal/aws"
+	"github.com/OlaHulleberg/clauderock/internal/config"
 	"github.com/OlaHulleberg/clauderock/internal/interactive"
 	"github.com/OlaHulleberg/clauderock/internal/profiles"
+	"github.com/OlaHulleberg/clauderock/internal/vertex"
 	"github.com/spf13/cobra"
 )
 
+var (
+	configAcceptRecommended bool
+	configAnswersFile       string
+	configInspectProject    bool
+	configRefresh           bool
+)
+
 var configCmd = &cobra.Command{
 	Use:   "config",
 	Short: "Manage clauderock configuration",
 	Long: `Manage clauderock configuration.
 
 When run without subcommands, starts an interactive configuration wizard.
-You can also use subcommands to set, get, or list configuration values.`,
+You can also use subcommands to set, get, or list configuration values.
+
+Use --accept-recommended to skip the model screens and use the recommended
+models. Inside any model selector, press Tab to accept the recommended model.
+
+Use --inspect-project to look at the repository in the current directory
+(languages, size, monorepo markers) and tailor the recommended models to it,
+e.g. a heavier main model for large monorepos.
+
+Use --answers to supply wizard answers from a YAML file; only missing answers
+are prompted for. Example answers.yaml:
+
+  profile-type: bedrock
+  profile: default
+  region: us-east-1
+  cross-region: global
+  model: anthropic.claude-sonnet-4-5
+  fast-model: anthropic.claude-haiku-4-5
+  heavy-model: anthropic.claude-opus-4-1
+
+For API profiles use base-url, and api-key-env to name the environment
+variable holding the API key (keys are never read from the file). Set
+smoke-test to true or false to run or skip the post-setup test request
+without being asked.
+
+Bedrock model listings are cached per region for a few hours under
+~/.clauderock/cache, so the wizard stays fast and works briefly offline.
+Use --refresh to fetch them from Bedrock again.`,
 	RunE: func(cmd *cobra.Command, args []string) error {
 		// If no subcommand specified, run interactive config
+		if configRefresh {
+			aws.IgnoreCachedModelLists()
+		}
+
 		mgr, err := profiles.NewManager()
 		if err != nil {
 			return fmt.Errorf("failed to create profile manager: %w", err)
 		}
-		return interactive.RunInteractiveConfig(Version, mgr)
+
+		opts := interactive.WizardOptions{
+			AcceptRecommended: configAcceptRecommended,
+			InspectProject:    configInspectProject,
+		}
+		if configAnswersFile != "" {
+			opts.Answers, err = interactive.LoadAnswers(configAnswersFile)
+			if err != nil {
+				return err
+			}
+		}
+
+		return interactive.RunInteractiveConfigWithOptions(Version, mgr, opts)
 	},
 }
 
@@ -31,11 +84,45 @@ var configSetCmd = &cobra.Command{
 	Short: "Set a configuration value in the current profile",
 	Long: `Set a configuration value in the current profile. Valid keys:
   profile      - AWS profile name
+  credentials-source - Where AWS credentials come from: profile (default, the
+                 AWS profile above), environment (AWS_* variables) or
+                 default-chain (the SDK's default chain, e.g. IRSA)
   region       - AWS region (e.g., us-east-1)
   cross-region - Cross-region setting (us, eu, global)
+  gcp-project  - Google Cloud project ID (vertex)
+  vertex-region - Vertex AI region, e.g. us-east5, or global (vertex)
   model        - Main model name (e.g., anthropic.claude-sonnet-4-5)
   fast-model   - Fast model name (e.g., anthropic.claude-haiku-4-5)
-  heavy-model  - Heavy model name (e.g., anthropic.claude-opus-4-1)`,
+  heavy-model  - Heavy model name (e.g., anthropic.claude-opus-4-1)
+  env-policy   - Environment passed to Claude: inherit (default) or minimal
+  env-allowlist - Comma-separated extra variables passed with env-policy minimal
+  integration-mode - How models reach Claude: env (default) or settings
+                 (writes .claude/settings.local.json in the project, removed on exit;
+                 API keys are passed in the environment, never the file)
+  allowed-dirs - Comma-separated directories this profile may be launched from
+                 (subdirectories included; empty allows any)
+  dir-policy   - Launching outside allowed-dirs: refuse (default) or warn
+  budget-daily-usd   - Daily spending limit in USD for this profile (0 disables)
+  budget-monthly-usd - Monthly spending limit in USD for this profile (0 disables)
+  budget-warn-at     - Comma-separated percentages of a budget to warn at (default 80)
+  budget-policy      - Launching over budget: warn (default) or refuse
+  auto-upgrade       - Move to newer models automatically: off (default), minor
+                       (newer snapshots) or major (also newer family versions)
+  auto-upgrade-mode  - prompt (default, asks at launch) or silent
+  pricing-tier       - How usage is billed, for cost estimates: standard (default),
+                       batch (batch inference discount) or provisioned
+  provisioned-hourly-usd - Hourly cost of the Provisioned Throughput commitment
+                       (pricing-tier provisioned)
+  provisioned-since  - Date the commitment started (YYYY-MM-DD); stats don't
+                       count its hours before then
+  encrypted          - true stores the whole profile in the encrypted keyring
+                       instead of plaintext JSON (default false)
+  team-sync          - Upload anonymized session records to a shared
+                       s3://bucket/prefix or dynamodb://table (off disables)
+  fast-model-region  - AWS region for fast model requests, if it differs from
+                       region (bedrock only; empty uses region)
+  max-output-tokens  - Maximum output tokens per response (0 for Claude Code's default)
+  max-thinking-tokens - Extended thinking budget, at least 1024 (0 for Claude Code's default)`,
 	Args: cobra.ExactArgs(2),
 	RunE: func(cmd *cobra.Command, args []string) error {
 		key, value := args[0], args[1]
@@ -51,16 +138,25 @@ var configSetCmd = &cobra.Command{
 		}
 
 		// Special handling for model and fast-model and heavy-model: resolve to full profile ID
-		if key == "model" || key == "fast-model" || key == "heavy-model" {
+		if _, ok := config.SlotForKey(key); ok && cfg.ProfileType == "vertex" {
+			fmt.Println("Validating model...")
+			if err := vertex.ValidateModels(cfg.GCPProject, cfg.VertexRegion, value); err != nil {
+				return fmt.Errorf("invalid model: %w", err)
+			}
+		} else if slot, ok := config.SlotForKey(key); ok {
 			fmt.Println("Validating model and resolving profile ID...")
-			fullID, err := aws.ResolveModelToProfileID(cfg.Profile, cfg.Region, cfg.CrossRegion, value)
+			fullID, unpinned, err := aws.ResolveSlotModel(cfg, slot, value)
 			if err != nil {
 				return fmt.Errorf("invalid model: %w", err)
 			}
+			if unpinned {
+				fmt.Printf("Note: pinned snapshot not available for %s, %s slot unpinned\n", value, slot)
+			}
 			value = fullID
 			fmt.Printf("✓ Resolved to: %s\n", fullID)
 		}
 
+		// Check the value before saving, so a bad one is reported as such
 		if err := cfg.Set(key, value); err != nil {
 			return err
 		}
@@ -70,7 +166,10 @@ var configSetCmd = &cobra.Command{
 			return fmt.Errorf("failed to get current profile: %w", err)
 		}
 
-		if err := mgr.Save(current, cfg); err != nil {
+		_, err = mgr.Update(current, func(cfg *config.Config) error {
+			return cfg.Set(key, value)
+		})
+		if err != nil {
 			return fmt.Errorf("failed to save config: %w", err)
 		}
 
@@ -126,20 +225,79 @@ var configListCmd = &cobra.Command{
 		}
 
 		fmt.Printf("Configuration (profile: %s):\n", current)
-		fmt.Printf("  profile:      %s\n", cfg.Profile)
-		fmt.Printf("  region:       %s\n", cfg.Region)
-		fmt.Printf("  cross-region: %s\n", cfg.CrossRegion)
+		if cfg.ProfileType == "vertex" {
+			fmt.Printf("  gcp-project:   %s\n", cfg.GCPProject)
+			fmt.Printf("  vertex-region: %s\n", cfg.VertexRegion)
+		} else {
+			fmt.Printf("  profile:      %s\n", cfg.Profile)
+			if !cfg.UsesSharedProfile() {
+				fmt.Printf("  credentials-source: %s\n", cfg.CredentialsSource)
+			}
+			fmt.Printf("  region:       %s\n", cfg.Region)
+			fmt.Printf("  cross-region: %s\n", cfg.CrossRegion)
+		}
 		fmt.Printf("  model:        %s\n", cfg.Model)
 		fmt.Printf("  fast-model:   %s\n", cfg.FastModel)
 		fmt.Printf("  heavy-model:  %s\n", cfg.HeavyModel)
+		if cfg.EnvPolicy == config.EnvPolicyMinimal {
+			fmt.Printf("  env-policy:   %s\n", cfg.EnvPolicy)
+			if len(cfg.EnvAllowlist) > 0 {
+				fmt.Printf("  env-allowlist: %s\n", strings.Join(cfg.EnvAllowlist, ","))
+			}
+		}
+		if cfg.IntegrationMode == config.IntegrationModeSettings {
+			fmt.Printf("  integration-mode: %s\n", cfg.IntegrationMode)
+		}
+		if len(cfg.AllowedDirs) > 0 {
+			dirPolicy, _ := cfg.Get("dir-policy")
+			fmt.Printf("  allowed-dirs: %s (%s elsewhere)\n", strings.Join(cfg.AllowedDirs, ","), dirPolicy)
+		}
+		if cfg.BudgetDailyUSD > 0 || cfg.BudgetMonthlyUSD > 0 {
+			fmt.Printf("  budget:       %s\n", budgetSummary(cfg))
+		}
+		if cfg.AutoUpgrade != "" && cfg.AutoUpgrade != config.AutoUpgradeOff {
+			mode, _ := cfg.Get("auto-upgrade-mode")
+			fmt.Printf("  auto-upgrade: %s (%s)\n", cfg.AutoUpgrade, mode)
+		}
+		if cfg.PricingTier != "" && cfg.PricingTier != config.PricingTierStandard {
+			fmt.Printf("  pricing-tier: %s\n", pricingTierSummary(cfg))
+		}
+		if cfg.Encrypted {
+			fmt.Println("  encrypted:    true")
+		}
+		if cfg.TeamSync != "" {
+			fmt.Printf("  team-sync:    %s\n", cfg.TeamSync)
+		}
+		if cfg.FastModelRegion != "" {
+			fmt.Printf("  fast-model-region: %s\n", cfg.FastModelRegion)
+		}
+		if cfg.MaxOutputTokens > 0 {
+			fmt.Printf("  max-output-tokens: %d\n", cfg.MaxOutputTokens)
+		}
+		if cfg.MaxThinkingTokens > 0 {
+			fmt.Printf("  max-thinking-tokens: %d\n", cfg.MaxThinkingTokens)
+		}
+		for _, slot := range config.ModelSlots {
+			if version := cfg.PinnedVersion(slot); version != "" {
+				fmt.Printf("  pinned %-6s %s\n", slot+":", version)
+			}
+		}
 		return nil
 	},
 }
 
 func init() {
 	// Registered by manage.go
+	configCmd.Flags().BoolVar(&configAcceptRecommended, "accept-recommended", false, "Skip the model screens and use the recommended models")
+	configCmd.Flags().BoolVar(&configInspectProject, "inspect-project", false, "Tailor recommended models to the repository in the current directory")
+	configCmd.Flags().StringVar(&configAnswersFile, "answers", "", "YAML file with wizard answers; only missing answers are prompted for")
+	configCmd.Flags().BoolVar(&configRefresh, "refresh", false, "Re-fetch the model list from Bedrock instead of using the cache")
+
 	configCmd.AddCommand(configSetCmd)
 	configCmd.AddCommand(configGetCmd)
 	configCmd.AddCommand(configListCmd)
 	configCmd.AddCommand(configModelsCmd)
+	configCmd.AddCommand(configBindCmd)
+	configCmd.AddCommand(configUnbindCmd)
+	configCmd.AddCommand(configDoctorCmd)
 }
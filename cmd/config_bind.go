@@ -0,0 +1,75 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/OlaHulleberg/clauderock/internal/profiles"
+	"github.com/spf13/cobra"
+)
+
+var configBindCmd = &cobra.Command{
+	Use:   "bind [profile]",
+	Short: "Bind the current directory to a profile",
+	Long: `Write a .clauderock file in the current directory naming a profile. Launching
+clauderock from this directory or any subdirectory then uses that profile, so
+work and personal Bedrock accounts stay separated without passing
+--clauderock-profile every time.
+
+The profile defaults to the active one. --clauderock-profile, --clauderock-group
+and CLAUDEROCK_PROFILE still take precedence over a binding.
+
+Examples:
+  clauderock manage config bind work
+  clauderock manage config unbind`,
+	Args: cobra.MaximumNArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		mgr, err := profiles.NewManager()
+		if err != nil {
+			return fmt.Errorf("failed to create profile manager: %w", err)
+		}
+
+		var name string
+		if len(args) == 1 {
+			name = args[0]
+		} else if name, err = mgr.GetCurrent(); err != nil {
+			return fmt.Errorf("failed to get current profile: %w", err)
+		}
+
+		dir, err := os.Getwd()
+		if err != nil {
+			return fmt.Errorf("failed to get working directory: %w", err)
+		}
+
+		path, err := mgr.BindProject(dir, name)
+		if err != nil {
+			return err
+		}
+		fmt.Printf("Bound %s to profile '%s' (%s)\n", dir, name, path)
+		return nil
+	},
+}
+
+var configUnbindCmd = &cobra.Command{
+	Use:   "unbind",
+	Short: "Remove the current directory's profile binding",
+	Args:  cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		dir, err := os.Getwd()
+		if err != nil {
+			return fmt.Errorf("failed to get working directory: %w", err)
+		}
+
+		path, err := profiles.UnbindProject(dir)
+		if err != nil {
+			return err
+		}
+		fmt.Printf("Removed %s\n", path)
+
+		// A parent directory's binding now applies instead
+		if binding, err := profiles.FindProjectBinding(dir); err == nil && binding != nil {
+			fmt.Printf("%s still binds this directory to profile '%s'\n", binding.Path, binding.Profile)
+		}
+		return nil
+	},
+}
@@ -0,0 +1,418 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/OlaHulleberg/clauderock/internal/api"
+	"github.com/OlaHulleberg/clauderock/internal/aws"
+	"github.com/OlaHulleberg/clauderock/internal/config"
+	"github.com/OlaHulleberg/clauderock/internal/keyring"
+	"github.com/OlaHulleberg/clauderock/internal/launcher"
+	"github.com/OlaHulleberg/clauderock/internal/policy"
+	"github.com/OlaHulleberg/clauderock/internal/profiles"
+	"github.com/OlaHulleberg/clauderock/internal/vertex"
+	"github.com/spf13/cobra"
+)
+
+var (
+	configDoctorProfile string
+	configDoctorOffline bool
+	configDoctorSchema  bool
+)
+
+var configDoctorCmd = &cobra.Command{
+	Use:   "doctor",
+	Short: "Check a profile and everything it depends on, with fixes",
+	Long: `Check a profile and everything a launch depends on, and print a fix for
+each problem found:
+
+  - the profile file against clauderock's JSON Schema, and its settings
+  - the organisation policy, if one is installed
+  - AWS credentials, including the SSO login (bedrock)
+  - that the profile's models still exist in Bedrock or at the API endpoint
+  - the installed Claude Code version
+  - access to the keyring, and the profile's API key (api)
+
+Use --offline to skip the checks that call AWS or the API endpoint, and
+--schema to print the JSON Schema, e.g. for editor completion.
+
+Examples:
+  clauderock manage config doctor
+  clauderock manage config doctor --profile work
+  clauderock manage config doctor --schema > profile.schema.json`,
+	Args: cobra.NoArgs,
+	RunE: runConfigDoctor,
+}
+
+func init() {
+	// Registered by config.go
+
+	configDoctorCmd.Flags().StringVar(&configDoctorProfile, "profile", "", "Profile to check (defaults to the active profile)")
+	configDoctorCmd.Flags().BoolVar(&configDoctorOffline, "offline", false, "Skip checks that need the network")
+	configDoctorCmd.Flags().BoolVar(&configDoctorSchema, "schema", false, "Print the profile JSON Schema and exit")
+}
+
+// doctorCheck is the outcome of one config doctor check
+type doctorCheck struct {
+	name   string
+	status doctorStatus
+	detail string
+	fix    string // What to do about a warning or failure
+}
+
+type doctorStatus int
+
+const (
+	doctorOK doctorStatus = iota
+	doctorWarn
+	doctorFail
+	doctorSkipped
+)
+
+func (s doctorStatus) symbol() string {
+	switch s {
+	case doctorOK:
+		return "✓"
+	case doctorWarn:
+		return "!"
+	case doctorFail:
+		return "✗"
+	default:
+		return "-"
+	}
+}
+
+func runConfigDoctor(cmd *cobra.Command, args []string) error {
+	if configDoctorSchema {
+		_, err := os.Stdout.Write(config.ProfileSchema)
+		return err
+	}
+
+	mgr, err := profiles.NewManager()
+	if err != nil {
+		return fmt.Errorf("failed to create profile manager: %w", err)
+	}
+
+	name := configDoctorProfile
+	if name == "" {
+		name, err = mgr.GetCurrent()
+		if err != nil {
+			return fmt.Errorf("failed to get current profile: %w", err)
+		}
+	}
+	data, err := mgr.ReadRaw(name)
+	if err != nil {
+		return err
+	}
+
+	// Failed checks are reported above the error, so usage would only bury them
+	cmd.SilenceUsage = true
+	fmt.Printf("Checking profile '%s'\n\n", name)
+
+	file := fmt.Sprintf("~/.clauderock/profiles/%s.json", name)
+	checks := []doctorCheck{checkProfileSchema(data, file)}
+
+	cfg, err := mgr.Load(name)
+	if err != nil {
+		checks = append(checks, doctorCheck{name: "settings", status: doctorFail, detail: err.Error(),
+			fix: fmt.Sprintf("Fix the JSON in %s, or recreate the profile with: clauderock manage config", file)})
+		return printDoctorChecks(checks)
+	}
+	settings := checkProfileSettings(cfg, file)
+	checks = append(checks, settings, checkProfilePolicy(cfg))
+
+	// Network checks are skipped for this reason, if any
+	skip := ""
+	if configDoctorOffline {
+		skip = "--offline"
+	} else if settings.status == doctorFail {
+		// An incomplete profile would fail them for the same reason
+		skip = "profile incomplete"
+	}
+
+	switch cfg.ProfileType {
+	case "bedrock":
+		checks = append(checks, checkAWSCredentials(cfg, skip))
+		checks = append(checks, checkBedrockModels(cfg, skip))
+		checks = append(checks, checkKeyring())
+	case "api":
+		keyCheck, apiKey := checkAPIKey(cfg)
+		checks = append(checks, keyCheck, checkAPIModels(cfg, apiKey, skip))
+	case "vertex":
+		credCheck, ok := checkGoogleCredentials(skip)
+		if skip == "" && !ok {
+			skip = "no Google Cloud credentials"
+		}
+		checks = append(checks, credCheck, checkVertexModels(cfg, skip))
+	}
+	checks = append(checks, checkClaudeBinary())
+
+	return printDoctorChecks(checks)
+}
+
+// printDoctorChecks prints the checks with their fixes, failing when any check failed
+func printDoctorChecks(checks []doctorCheck) error {
+	failed, warned := 0, 0
+	for _, c := range checks {
+		fmt.Printf("  %s %s: %s\n", c.status.symbol(), c.name, c.detail)
+		if c.fix != "" && (c.status == doctorWarn || c.status == doctorFail) {
+			fmt.Printf("      %s\n", mutedStyle.Render("→ "+c.fix))
+		}
+		switch c.status {
+		case doctorFail:
+			failed++
+		case doctorWarn:
+			warned++
+		}
+	}
+	fmt.Println()
+
+	if failed > 0 {
+		return fmt.Errorf("%d check(s) failed", failed)
+	}
+	if warned > 0 {
+		fmt.Printf("No problems that stop a launch, but %d warning(s) above.\n", warned)
+		return nil
+	}
+	fmt.Println(highlightStyle.Render("✓ Everything looks good."))
+	return nil
+}
+
+func checkProfileSchema(data []byte, file string) doctorCheck {
+	check := doctorCheck{name: "schema"}
+	errs, err := config.ValidateSchema(data)
+	if err != nil {
+		check.status, check.detail = doctorFail, err.Error()
+		return check
+	}
+	if len(errs) == 0 {
+		check.detail = "profile file matches the schema"
+		return check
+	}
+
+	check.status = doctorFail
+	var details []string
+	for _, e := range errs {
+		details = append(details, e.Error())
+	}
+	check.detail = strings.Join(details, "; ")
+	check.fix = fmt.Sprintf("Edit %s, or change the values with 'clauderock manage config set'", file)
+	return check
+}
+
+func checkProfileSettings(cfg *config.Config, file string) doctorCheck {
+	check := doctorCheck{name: "settings"}
+	if err := cfg.Validate(); err != nil {
+		check.status, check.detail = doctorFail, err.Error()
+		check.fix = fmt.Sprintf("Run 'clauderock manage config' to complete the profile, or edit %s", file)
+		return check
+	}
+	check.detail = fmt.Sprintf("%s profile is complete", cfg.ProfileType)
+	if cfg.Unverified {
+		check.status = doctorWarn
+		check.detail += ", but its models came from the offline catalog and haven't been checked yet"
+		check.fix = "Launch once with network access, or pick models with: clauderock manage config models"
+	}
+	return check
+}
+
+func checkProfilePolicy(cfg *config.Config) doctorCheck {
+	check := doctorCheck{name: "policy"}
+	p, err := policy.Load()
+	if err != nil {
+		check.status, check.detail = doctorFail, err.Error()
+		check.fix = "Check the organisation policy file, or ask whoever installed it"
+		return check
+	}
+	if p == nil {
+		check.status, check.detail = doctorSkipped, "no organisation policy installed"
+		return check
+	}
+	if err := p.Err(cfg); err != nil {
+		check.status, check.detail = doctorFail, err.Error()
+		check.fix = "Change the settings named above, or ask your administrator about the policy (see 'clauderock manage policy')"
+		return check
+	}
+	check.detail = "complies with the organisation policy"
+	return check
+}
+
+func checkAWSCredentials(cfg *config.Config, skip string) doctorCheck {
+	check := doctorCheck{name: "AWS credentials"}
+	if skip != "" {
+		check.status, check.detail = doctorSkipped, "skipped ("+skip+")"
+		return check
+	}
+
+	if cfg.CredentialsSource == config.CredentialsSourceEnvironment {
+		aws.UseEnvironmentCredentials()
+		if err := launcher.CheckEnvironmentCredentials(os.Environ()); err != nil {
+			check.status, check.detail = doctorFail, "no AWS credentials in the environment"
+			check.fix = "Make AWS credentials available: " + launcher.CredentialsFix(cfg)
+			return check
+		}
+	}
+
+	status, err := aws.CheckSSOSession(cfg.AWSProfile())
+	if err != nil {
+		check.status, check.detail = doctorFail, err.Error()
+		check.fix = fmt.Sprintf("Add the profile to ~/.aws/config, e.g. with: aws configure sso --profile %s", cfg.Profile)
+		return check
+	}
+	if status != nil && status.Expired {
+		check.status = doctorFail
+		check.detail = fmt.Sprintf("the SSO login for AWS profile '%s' has expired", cfg.Profile)
+		check.fix = fmt.Sprintf("Log in with: aws sso login --profile %s", status.LoginProfile)
+		return check
+	}
+
+	arn, err := aws.CallerIdentity(cfg.AWSProfile(), cfg.Region)
+	if err != nil {
+		check.status, check.detail = doctorFail, err.Error()
+		if cfg.UsesSharedProfile() {
+			check.fix = fmt.Sprintf("Check the credentials of AWS profile '%s' in ~/.aws/config and ~/.aws/credentials", cfg.Profile)
+		} else {
+			check.fix = "Make AWS credentials available: " + launcher.CredentialsFix(cfg)
+		}
+		return check
+	}
+	check.detail = fmt.Sprintf("%s is %s", cfg.CredentialsLabel(), arn)
+	return check
+}
+
+func checkBedrockModels(cfg *config.Config, skip string) doctorCheck {
+	check := doctorCheck{name: "models"}
+	if skip != "" {
+		check.status, check.detail = doctorSkipped, "skipped ("+skip+")"
+		return check
+	}
+
+	// The cached model lists can be up to a day old; ask Bedrock
+	aws.IgnoreCachedModelLists()
+
+	var ids []string
+	for _, slot := range config.ModelSlots {
+		model, _ := cfg.ModelForSlot(slot)
+		resolveCfg := *cfg
+		id, _, err := aws.ResolveSlotModel(&resolveCfg, slot, model)
+		if err != nil {
+			check.status, check.detail = doctorFail, fmt.Sprintf("%s model '%s': %v", slot, model, err)
+			check.fix = "Pick available models with: clauderock manage config models"
+			return check
+		}
+		ids = append(ids, id)
+	}
+	if err := aws.ValidateProfileIDs(cfg.AWSProfile(), cfg.Region, ids...); err != nil {
+		check.status, check.detail = doctorFail, err.Error()
+		check.fix = "Pick available models with: clauderock manage config models"
+		return check
+	}
+	check.detail = fmt.Sprintf("all available in %s", cfg.Region)
+	return check
+}
+
+func checkKeyring() doctorCheck {
+	check := doctorCheck{name: "keyring"}
+	keys, err := keyring.Check()
+	if err != nil {
+		// Bedrock profiles don't need the keyring, but API profiles created later would
+		check.status, check.detail = doctorWarn, err.Error()
+		check.fix = "Check the permissions of ~/.clauderock/keyring"
+		return check
+	}
+	check.detail = fmt.Sprintf("accessible (%d key(s) stored)", keys)
+	return check
+}
+
+// checkAPIKey reads the profile's API key from the keyring, returning it for the model check
+func checkAPIKey(cfg *config.Config) (doctorCheck, string) {
+	check := doctorCheck{name: "keyring"}
+	if _, err := keyring.Check(); err != nil {
+		check.status, check.detail = doctorFail, err.Error()
+		check.fix = "Check the permissions of ~/.clauderock/keyring"
+		return check, ""
+	}
+	apiKey, err := keyring.Get(cfg.APIKeyID)
+	if err != nil {
+		check.status, check.detail = doctorFail, err.Error()
+		check.fix = "The keyring is tied to this machine and user; enter the API key again with: clauderock manage config"
+		return check, ""
+	}
+	check.detail = "API key found"
+	return check, apiKey
+}
+
+func checkAPIModels(cfg *config.Config, apiKey, skip string) doctorCheck {
+	check := doctorCheck{name: "models"}
+	if skip == "" && apiKey == "" {
+		skip = "no API key"
+	}
+	if skip != "" {
+		check.status, check.detail = doctorSkipped, "skipped ("+skip+")"
+		return check
+	}
+
+	if err := api.ValidateModels(cfg.BaseURL, apiKey, cfg.Model, cfg.FastModel, cfg.HeavyModel); err != nil {
+		check.status, check.detail = doctorFail, err.Error()
+		check.fix = fmt.Sprintf("Check that %s is reachable and the key is valid, then pick models with: clauderock manage config models", cfg.BaseURL)
+		return check
+	}
+	check.detail = fmt.Sprintf("all available at %s", cfg.BaseURL)
+	return check
+}
+
+// checkGoogleCredentials reports whether an access token for the Application Default Credentials can be read
+func checkGoogleCredentials(skip string) (doctorCheck, bool) {
+	check := doctorCheck{name: "Google Cloud credentials"}
+	if skip != "" {
+		check.status, check.detail = doctorSkipped, "skipped ("+skip+")"
+		return check, false
+	}
+	if _, err := vertex.AccessToken(); err != nil {
+		check.status, check.detail = doctorFail, err.Error()
+		check.fix = "Log in with: gcloud auth application-default login"
+		return check, false
+	}
+	check.detail = "access token available"
+	return check, true
+}
+
+func checkVertexModels(cfg *config.Config, skip string) doctorCheck {
+	check := doctorCheck{name: "models"}
+	if skip != "" {
+		check.status, check.detail = doctorSkipped, "skipped ("+skip+")"
+		return check
+	}
+
+	if err := vertex.ValidateModels(cfg.GCPProject, cfg.VertexRegion, cfg.Model, cfg.FastModel, cfg.HeavyModel); err != nil {
+		check.status, check.detail = doctorFail, err.Error()
+		check.fix = fmt.Sprintf("Enable the models in the Vertex AI Model Garden for project %s, or pick others with: clauderock manage config models", cfg.GCPProject)
+		return check
+	}
+	check.detail = fmt.Sprintf("all available in %s", cfg.VertexRegion)
+	return check
+}
+
+func checkClaudeBinary() doctorCheck {
+	check := doctorCheck{name: "Claude Code"}
+	path, version, err := launcher.ClaudeVersion()
+	if err != nil {
+		check.status, check.detail = doctorFail, err.Error()
+		if path == "" {
+			check.fix = "Install Claude Code: npm install -g @anthropic-ai/claude-code"
+		} else {
+			check.fix = fmt.Sprintf("Reinstall or repair Claude Code at %s", path)
+		}
+		return check
+	}
+	if unsupported := launcher.UnsupportedModelSettings(version); len(unsupported) > 0 {
+		check.status = doctorWarn
+		check.detail = fmt.Sprintf("%s at %s ignores the %s", version, path, strings.Join(unsupported, ", "))
+		check.fix = "Update Claude Code with: claude update"
+		return check
+	}
+	check.detail = fmt.Sprintf("%s at %s", version, path)
+	return check
+}
@@ -4,15 +4,23 @@ import (
 	"fmt"
 
 	"github.com/OlaHulleberg/clauderock/internal/aws"
+	"github.com/OlaHulleberg/clauderock/internal/config"
 	"github.com/OlaHulleberg/clauderock/internal/interactive"
 	"github.com/OlaHulleberg/clauderock/internal/profiles"
 	"github.com/spf13/cobra"
 )
 
+var (
+	configModelsProfile           string
+	configModelsAcceptRecommended bool
+	configModelsRefresh           bool
+)
+
 var configModelsCmd = &cobra.Command{
 	Use:   "models",
-	Short: "Interactively reconfigure models for the current profile",
-	Long: `Interactively reconfigure the main, fast, and heavy models for the current profile.
+	Short: "Interactively reconfigure models for a profile",
+	Long: `Interactively reconfigure the main, fast, and heavy models for the current profile
+(or any profile with --profile).
 
 This command allows you to update model selections without changing other configuration
 settings like AWS profile, region, API keys, or base URL. It works for both Bedrock
@@ -25,57 +33,89 @@ The command will:
   - Save the updated configuration
 
 Example usage:
-  clauderock manage config models`,
+  clauderock manage config models
+  clauderock manage config models --profile client-a
+  clauderock manage config models --refresh`,
 	RunE: runConfigModels,
 }
 
+func init() {
+	configModelsCmd.Flags().StringVar(&configModelsProfile, "profile", "", "Reconfigure models for a specific profile (defaults to current)")
+	configModelsCmd.Flags().BoolVar(&configModelsAcceptRecommended, "accept-recommended", false, "Use the recommended models without prompting")
+	configModelsCmd.Flags().BoolVar(&configModelsRefresh, "refresh", false, "Re-fetch the model list from Bedrock instead of using the cache")
+}
+
 func runConfigModels(cmd *cobra.Command, args []string) error {
+	if configModelsRefresh {
+		aws.IgnoreCachedModelLists()
+	}
+
 	// Create profile manager
 	mgr, err := profiles.NewManager()
 	if err != nil {
 		return fmt.Errorf("failed to create profile manager: %w", err)
 	}
 
-	// Load current profile configuration
-	cfg, err := mgr.GetCurrentConfig(Version)
-	if err != nil {
-		return fmt.Errorf("failed to load config: %w", err)
-	}
+	var cfg *config.Config
+	var profileName string
 
-	// Get current profile name
-	currentProfile, err := mgr.GetCurrent()
-	if err != nil {
-		return fmt.Errorf("failed to get current profile: %w", err)
+	if configModelsProfile != "" {
+		// Load the requested profile with its own credentials and region
+		cfg, err = mgr.Load(configModelsProfile)
+		if err != nil {
+			return fmt.Errorf("failed to load profile '%s': %w", configModelsProfile, err)
+		}
+		profileName = configModelsProfile
+	} else {
+		// Load current profile configuration
+		cfg, err = mgr.GetCurrentConfig(Version)
+		if err != nil {
+			return fmt.Errorf("failed to load config: %w", err)
+		}
+
+		// Get current profile name
+		profileName, err = mgr.GetCurrent()
+		if err != nil {
+			return fmt.Errorf("failed to get current profile: %w", err)
+		}
 	}
 
-	fmt.Printf("Configuring models for profile '%s' (type: %s)\n", currentProfile, cfg.ProfileType)
+	fmt.Printf("Configuring models for profile '%s' (type: %s)\n", profileName, cfg.ProfileType)
 
 	// Branch based on profile type
 	switch cfg.ProfileType {
 	case "bedrock":
-		if err := interactive.SelectBedrockModels(cfg); err != nil {
+		if err := interactive.SelectBedrockModels(cfg, configModelsAcceptRecommended); err != nil {
 			return err
 		}
 	case "api":
-		if err := interactive.SelectAPIModels(cfg); err != nil {
+		if err := interactive.SelectAPIModels(cfg, configModelsAcceptRecommended); err != nil {
+			return err
+		}
+	case "vertex":
+		if err := interactive.SelectVertexModels(cfg, configModelsAcceptRecommended); err != nil {
 			return err
 		}
 	default:
 		return fmt.Errorf("unsupported profile type: %s", cfg.ProfileType)
 	}
 
-	// Update version to current CLI version (but not for dev builds)
-	if Version != "dev" {
-		cfg.Version = Version
-	}
-
-	// Save updated configuration
-	if err := mgr.Save(currentProfile, cfg); err != nil {
+	// Save the selected models; the rest of the profile may have changed while the selection ran
+	_, err = mgr.Update(profileName, func(stored *config.Config) error {
+		stored.Model, stored.FastModel, stored.HeavyModel = cfg.Model, cfg.FastModel, cfg.HeavyModel
+		stored.DropStalePins()
+		// Update version to current CLI version (but not for dev builds)
+		if Version != "dev" {
+			stored.Version = Version
+		}
+		return nil
+	})
+	if err != nil {
 		return fmt.Errorf("failed to save config: %w", err)
 	}
 
 	// Display success message with updated models
-	fmt.Printf("\n✓ Configuration saved successfully to profile '%s'!\n", currentProfile)
+	fmt.Printf("\n✓ Configuration saved successfully to profile '%s'!\n", profileName)
 	fmt.Printf("\nUpdated models:\n")
 
 	// Display friendly model names for better readability
@@ -0,0 +1,80 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"runtime"
+	"runtime/debug"
+	"strings"
+	"time"
+)
+
+const issuesURL = "https://github.com/OlaHulleberg/clauderock/issues"
+
+// recoverPanic turns a panic into a crash log and a short message instead of a raw Go trace
+func recoverPanic() {
+	r := recover()
+	if r == nil {
+		return
+	}
+
+	stack := debug.Stack()
+	fmt.Fprintf(os.Stderr, "\nclauderock hit an unexpected error: %v\n", r)
+
+	path, err := writeCrashLog(r, stack)
+	if err != nil {
+		// Without a log file the trace is the only record, so print it after all
+		fmt.Fprintf(os.Stderr, "Failed to write crash log (%v); stack trace:\n%s\n", err, stack)
+	} else {
+		fmt.Fprintf(os.Stderr, "A crash report was saved to %s\n", path)
+	}
+	fmt.Fprintf(os.Stderr, "Please report this at %s and attach the crash report.\n", issuesURL)
+	os.Exit(2)
+}
+
+// writeCrashLog writes the panic value and stack trace to ~/.clauderock/logs
+func writeCrashLog(r interface{}, stack []byte) (string, error) {
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return "", fmt.Errorf("failed to get home directory: %w", err)
+	}
+
+	dir := filepath.Join(home, ".clauderock", "logs")
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return "", fmt.Errorf("failed to create log directory: %w", err)
+	}
+
+	now := time.Now()
+	path := filepath.Join(dir, fmt.Sprintf("crash-%s.log", now.Format("20060102-150405")))
+
+	var b strings.Builder
+	fmt.Fprintf(&b, "Time:    %s\n", now.Format(time.RFC3339))
+	fmt.Fprintf(&b, "Version: %s\n", Version)
+	fmt.Fprintf(&b, "OS:      %s/%s (%s)\n", runtime.GOOS, runtime.GOARCH, runtime.Version())
+	fmt.Fprintf(&b, "Command: clauderock %s\n", strings.Join(redactArgs(os.Args[1:]), " "))
+	fmt.Fprintf(&b, "Panic:   %v\n\n%s", r, stack)
+
+	if err := os.WriteFile(path, []byte(b.String()), 0600); err != nil {
+		return "", fmt.Errorf("failed to write crash log: %w", err)
+	}
+	return path, nil
+}
+
+// redactArgs hides secret flag values so crash reports are safe to share
+func redactArgs(args []string) []string {
+	const secretFlag = "--clauderock-api-key"
+
+	redacted := make([]string, len(args))
+	for i, arg := range args {
+		switch {
+		case strings.HasPrefix(arg, secretFlag+"="):
+			redacted[i] = secretFlag + "=<redacted>"
+		case i > 0 && args[i-1] == secretFlag:
+			redacted[i] = "<redacted>"
+		default:
+			redacted[i] = arg
+		}
+	}
+	return redacted
+}
@@ -0,0 +1,178 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/OlaHulleberg/clauderock/internal/currency"
+	"github.com/spf13/cobra"
+)
+
+// ratesFetchTimeout bounds an exchange rate download
+const ratesFetchTimeout = 15 * time.Second
+
+var currencyCmd = &cobra.Command{
+	Use:   "currency",
+	Short: "Show and set the currency costs are displayed in",
+	Long: `Show and set the currency costs are displayed in.
+
+Prices and budgets are kept in USD. Costs in stats, budget warnings, and
+session summaries are converted to the display currency using the European
+Central Bank's daily reference rates, cached in ~/.clauderock/exchange-rates.json
+for a day, or a rate you provide. Without a rate, costs stay in USD.
+
+Supported currencies: ` + strings.Join(currency.Supported, ", "),
+	RunE: runCurrencyShow,
+}
+
+var currencySetCmd = &cobra.Command{
+	Use:   "set <currency>",
+	Short: "Set the display currency",
+	Long: `Set the display currency, fetching exchange rates if none are cached.
+
+Examples:
+  clauderock manage currency set EUR
+  clauderock manage currency set USD`,
+	Args: cobra.ExactArgs(1),
+	RunE: runCurrencySet,
+}
+
+var currencyRateCmd = &cobra.Command{
+	Use:   "rate <currency> <units-per-usd|auto>",
+	Short: "Use your own exchange rate instead of the fetched one",
+	Long: `Use your own exchange rate, in units of the currency per USD, instead of the
+fetched one. 'auto' goes back to the fetched rate.
+
+Examples:
+  clauderock manage currency rate NOK 10.5
+  clauderock manage currency rate NOK auto`,
+	Args: cobra.ExactArgs(2),
+	RunE: runCurrencyRate,
+}
+
+var currencyRefreshCmd = &cobra.Command{
+	Use:   "refresh",
+	Short: "Fetch current exchange rates now",
+	Args:  cobra.NoArgs,
+	RunE:  runCurrencyRefresh,
+}
+
+func init() {
+	// Registered by manage.go
+	currencyCmd.AddCommand(currencySetCmd)
+	currencyCmd.AddCommand(currencyRateCmd)
+	currencyCmd.AddCommand(currencyRefreshCmd)
+}
+
+func runCurrencyShow(cmd *cobra.Command, args []string) error {
+	settings, err := currency.LoadSettings()
+	if err != nil {
+		return err
+	}
+	code := settings.Current()
+	fmt.Printf("Display currency: %s\n", code)
+	if code == currency.USD {
+		return nil
+	}
+
+	rate, userProvided, ok := currency.Rate(settings, code)
+	switch {
+	case !ok:
+		fmt.Println("Exchange rate: unknown, so costs are shown in USD")
+		fmt.Printf("               Fetch one with 'clauderock manage currency refresh' or set one with 'clauderock manage currency rate %s <rate>'\n", code)
+	case userProvided:
+		fmt.Printf("Exchange rate: 1 USD = %g %s (set by you)\n", rate, code)
+	default:
+		cache, err := currency.LoadRates()
+		if err != nil {
+			return err
+		}
+		fmt.Printf("Exchange rate: 1 USD = %.4f %s (%s reference rate of %s)\n", rate, code, strings.ToUpper(cache.Source), cache.Date)
+		if time.Since(cache.Updated) > currency.RatesTTL {
+			fmt.Println("               Rates are stale; refresh with: clauderock manage currency refresh")
+		}
+	}
+	return nil
+}
+
+func runCurrencySet(cmd *cobra.Command, args []string) error {
+	code, err := currency.Normalize(args[0])
+	if err != nil {
+		return err
+	}
+	settings, err := currency.LoadSettings()
+	if err != nil {
+		return err
+	}
+	settings.Currency = code
+	if code == currency.USD {
+		settings.Currency = ""
+	}
+	if err := currency.SaveSettings(settings); err != nil {
+		return err
+	}
+	fmt.Printf("✓ Costs will be shown in %s\n", code)
+
+	if _, _, ok := currency.Rate(settings, code); !ok {
+		ctx, cancel := context.WithTimeout(context.Background(), ratesFetchTimeout)
+		defer cancel()
+		if _, err := currency.RefreshRates(ctx); err != nil {
+			fmt.Printf("Warning: %v\n", err)
+			fmt.Printf("Costs stay in USD until rates can be fetched, or set one with: clauderock manage currency rate %s <rate>\n", code)
+		}
+	}
+	return nil
+}
+
+func runCurrencyRate(cmd *cobra.Command, args []string) error {
+	code, err := currency.Normalize(args[0])
+	if err != nil {
+		return err
+	}
+	if code == currency.USD {
+		return fmt.Errorf("USD is the base currency; its rate is always 1")
+	}
+	settings, err := currency.LoadSettings()
+	if err != nil {
+		return err
+	}
+
+	if strings.EqualFold(args[1], "auto") {
+		delete(settings.Rates, code)
+		if err := currency.SaveSettings(settings); err != nil {
+			return err
+		}
+		fmt.Printf("✓ %s uses the fetched exchange rate\n", code)
+		return nil
+	}
+
+	rate, err := strconv.ParseFloat(args[1], 64)
+	if err != nil || rate <= 0 {
+		return fmt.Errorf("invalid rate '%s': must be a positive number of %s per USD, or auto", args[1], code)
+	}
+	if settings.Rates == nil {
+		settings.Rates = make(map[string]float64)
+	}
+	settings.Rates[code] = rate
+	if err := currency.SaveSettings(settings); err != nil {
+		return err
+	}
+	fmt.Printf("✓ 1 USD = %g %s\n", rate, code)
+	return nil
+}
+
+func runCurrencyRefresh(cmd *cobra.Command, args []string) error {
+	fmt.Println("Fetching exchange rates...")
+	ctx, cancel := context.WithTimeout(context.Background(), ratesFetchTimeout)
+	defer cancel()
+
+	cache, err := currency.RefreshRates(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to refresh exchange rates: %w", err)
+	}
+	fmt.Printf("✓ Cached %s reference rates of %s\n", strings.ToUpper(cache.Source), cache.Date)
+	return nil
+}
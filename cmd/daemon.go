@@ -0,0 +1,73 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"os/signal"
+	"syscall"
+
+	"github.com/OlaHulleberg/clauderock/internal/daemon"
+	"github.com/OlaHulleberg/clauderock/internal/profiles"
+	"github.com/spf13/cobra"
+)
+
+var daemonSocket string
+
+var daemonCmd = &cobra.Command{
+	Use:   "daemon",
+	Short: "Serve a local socket API for prompts, status bars, and editors",
+	Long: `Run in the foreground and answer JSON-RPC 1.0 requests on a Unix socket
+(~/.clauderock/daemon.sock by default, readable only by you). Shell prompts,
+status bars, and editor plugins can query it cheaply instead of starting
+clauderock for every refresh.
+
+Methods (params is a one-element list holding the object shown):
+  Clauderock.ActiveProfile  {"dir": "/path"}   Profile a launch from dir would use
+  Clauderock.SwitchProfile  {"name": "work"}   Make a profile the active one
+  Clauderock.LiveStats      {"dir": "/path"}   Tokens, TPM/RPM and cost of the running
+                                               session (omit dir for the latest anywhere)
+
+The daemon ignores CLAUDEROCK_PROFILE from the shell it was started in, since
+that only describes that shell. Stop it with Ctrl+C or SIGTERM.
+
+Example:
+  clauderock daemon &
+  echo '{"method":"Clauderock.ActiveProfile","params":[{"dir":"'$PWD'"}],"id":1}' | \
+    socat - UNIX-CONNECT:$HOME/.clauderock/daemon.sock`,
+	Args: cobra.NoArgs,
+	RunE: runDaemon,
+}
+
+func init() {
+	daemonCmd.Flags().StringVar(&daemonSocket, "socket", "", "Socket path (default ~/.clauderock/daemon.sock)")
+	rootCmd.AddCommand(daemonCmd)
+}
+
+func runDaemon(cmd *cobra.Command, args []string) error {
+	path := daemonSocket
+	if path == "" {
+		var err error
+		if path, err = daemon.SocketPath(); err != nil {
+			return err
+		}
+	}
+
+	// Queries come from other shells, so this one's override must not leak into the answers
+	os.Unsetenv(profiles.CurrentProfileEnvVar)
+
+	mgr, err := profiles.NewManager()
+	if err != nil {
+		return fmt.Errorf("failed to create profile manager: %w", err)
+	}
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	listener, err := daemon.Listen(path)
+	if err != nil {
+		return err
+	}
+	fmt.Printf("Listening on %s\n", path)
+	return daemon.Serve(ctx, listener, daemon.NewService(mgr))
+}
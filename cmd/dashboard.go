@@ -0,0 +1,73 @@
+package cmd
+
+import (
+	"fmt"
+	"path/filepath"
+	"time"
+
+	"github.com/OlaHulleberg/clauderock/internal/interactive"
+	"github.com/OlaHulleberg/clauderock/internal/monitoring"
+	"github.com/spf13/cobra"
+)
+
+var (
+	dashboardDir      string
+	dashboardFile     string
+	dashboardRefresh  time.Duration
+	dashboardTPMLimit float64
+)
+
+var dashboardCmd = &cobra.Command{
+	Use:   "dashboard",
+	Short: "Show live metrics for the running Claude Code session",
+	Long: `Follow the session log of the running Claude Code session and show rolling
+TPM/RPM, cache hit rate, a running cost estimate, and throttle-risk warnings
+while the session is in progress.
+
+By default the most recently written session log across all projects is
+followed; use --dir to pick the session of one working directory.
+
+Examples:
+  clauderock manage dashboard
+  clauderock manage dashboard --dir .
+  clauderock manage dashboard --tpm-limit 200000`,
+	RunE: runDashboard,
+}
+
+func init() {
+	// Registered by manage.go
+
+	dashboardCmd.Flags().StringVar(&dashboardDir, "dir", "", "Working directory of the session to follow")
+	dashboardCmd.Flags().StringVar(&dashboardFile, "file", "", "Session JSONL file to follow")
+	dashboardCmd.Flags().DurationVar(&dashboardRefresh, "refresh", 2*time.Second, "How often to refresh")
+	dashboardCmd.Flags().Float64Var(&dashboardTPMLimit, "tpm-limit", 0, "Warn when rolling TPM reaches 80% of this quota")
+}
+
+func runDashboard(cmd *cobra.Command, args []string) error {
+	if dashboardRefresh <= 0 {
+		return fmt.Errorf("--refresh must be positive")
+	}
+
+	path := dashboardFile
+	if path == "" {
+		dir := dashboardDir
+		if dir != "" {
+			abs, err := filepath.Abs(dir)
+			if err != nil {
+				return fmt.Errorf("failed to resolve %s: %w", dir, err)
+			}
+			dir = abs
+		}
+
+		var err error
+		path, err = monitoring.FindActiveSessionJSONL(dir)
+		if err != nil {
+			return fmt.Errorf("failed to find an active session: %w", err)
+		}
+	}
+
+	return interactive.RunDashboard(monitoring.NewLiveSession(path), interactive.DashboardOptions{
+		Refresh:  dashboardRefresh,
+		TPMLimit: dashboardTPMLimit,
+	})
+}
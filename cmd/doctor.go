@@ -0,0 +1,130 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"text/tabwriter"
+
+	"github.com/OlaHulleberg/clauderock/internal/aws"
+	"github.com/OlaHulleberg/clauderock/internal/config"
+	"github.com/OlaHulleberg/clauderock/internal/profiles"
+	"github.com/spf13/cobra"
+)
+
+var (
+	doctorProfile    string
+	doctorPolicyOnly bool
+	doctorAccount    string
+)
+
+var doctorCmd = &cobra.Command{
+	Use:   "doctor",
+	Short: "Diagnose problems with the environment clauderock runs in",
+}
+
+var doctorIAMCmd = &cobra.Command{
+	Use:   "iam",
+	Short: "Check IAM permissions and print a least-privilege policy",
+	Long: `Check the IAM permissions a Bedrock profile needs and print a
+least-privilege IAM policy an administrator can attach.
+
+The checks use trial calls: sts:GetCallerIdentity, bedrock:ListInferenceProfiles,
+and bedrock:InvokeModel / bedrock:InvokeModelWithResponseStream for each of the
+profile's models. The invoke trials send a deliberately invalid request body, so
+no inference runs and nothing is billed.
+
+Examples:
+  clauderock manage doctor iam
+  clauderock manage doctor iam --profile work
+  clauderock manage doctor iam --policy-only --account 123456789012 > policy.json`,
+	RunE: runDoctorIAM,
+}
+
+func init() {
+	// Registered by manage.go
+
+	doctorIAMCmd.Flags().StringVar(&doctorProfile, "profile", "", "Profile to check (defaults to the active profile)")
+	doctorIAMCmd.Flags().BoolVar(&doctorPolicyOnly, "policy-only", false, "Only print the policy JSON, without calling AWS")
+	doctorIAMCmd.Flags().StringVar(&doctorAccount, "account", "", "AWS account ID to use in the policy (defaults to the caller's account)")
+	doctorCmd.AddCommand(doctorIAMCmd)
+}
+
+func runDoctorIAM(cmd *cobra.Command, args []string) error {
+	mgr, err := profiles.NewManager()
+	if err != nil {
+		return fmt.Errorf("failed to create profile manager: %w", err)
+	}
+
+	name := doctorProfile
+	if name == "" {
+		name, err = mgr.GetCurrent()
+		if err != nil {
+			return fmt.Errorf("failed to get current profile: %w", err)
+		}
+	}
+
+	cfg, err := mgr.Load(name)
+	if err != nil {
+		return fmt.Errorf("failed to load profile '%s': %w", name, err)
+	}
+	if cfg.ProfileType != "bedrock" {
+		return fmt.Errorf("profile '%s' is a %s profile; IAM checks only apply to bedrock profiles", name, cfg.ProfileType)
+	}
+
+	modelIDs := []string{cfg.Model, cfg.FastModel, cfg.HeavyModel}
+
+	if doctorPolicyOnly {
+		return printIAMPolicy(cfg, doctorAccount, modelIDs)
+	}
+
+	fmt.Printf("Checking IAM permissions for profile '%s' (%s, region %s)\n\n", name, cfg.CredentialsLabel(), cfg.Region)
+	report, err := aws.CheckIAM(cfg.AWSProfile(), cfg.Region, modelIDs...)
+	if err != nil {
+		return err
+	}
+
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	for _, check := range report.Checks {
+		status := "✓"
+		if !check.Allowed {
+			status = "✗"
+		}
+		fmt.Fprintf(w, "  %s %s\t%s\t%s\n", status, check.Action, check.Resource, check.Detail)
+	}
+	if err := w.Flush(); err != nil {
+		return err
+	}
+
+	account := doctorAccount
+	if account == "" {
+		account = report.AccountID
+	}
+	fmt.Println("\nLeast-privilege policy for this profile:")
+	if err := printIAMPolicy(cfg, account, modelIDs); err != nil {
+		return err
+	}
+
+	if missing := report.Missing(); len(missing) > 0 {
+		return fmt.Errorf("%d permission check(s) failed; attach the policy above to %s", len(missing), callerOrProfile(report, cfg))
+	}
+	fmt.Println("\nAll required permissions are in place.")
+	return nil
+}
+
+// printIAMPolicy prints the least-privilege policy for a profile's models
+func printIAMPolicy(cfg *config.Config, account string, modelIDs []string) error {
+	policy, err := aws.LeastPrivilegePolicy(cfg.Region, account, modelIDs...)
+	if err != nil {
+		return fmt.Errorf("failed to generate policy: %w", err)
+	}
+	fmt.Println(string(policy))
+	return nil
+}
+
+// callerOrProfile names the identity the policy should be attached to
+func callerOrProfile(report *aws.IAMReport, cfg *config.Config) string {
+	if report.CallerARN != "" {
+		return report.CallerARN
+	}
+	return "the identity behind " + cfg.CredentialsLabel()
+}
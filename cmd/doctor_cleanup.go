@@ -0,0 +1,83 @@
+package cmd
+
+import (
+	"fmt"
+
+	"github.com/OlaHulleberg/clauderock/internal/updater"
+	"github.com/spf13/cobra"
+)
+
+var doctorCleanupDryRun bool
+
+var doctorCleanupCmd = &cobra.Command{
+	Use:   "cleanup",
+	Short: "Remove temporary files clauderock left behind",
+	Long: `Find and remove temporary files clauderock left behind and report the space
+reclaimed:
+
+  - update downloads and extracted binaries (clauderock-archive-*,
+    clauderock-binary-*) in the system temp directory
+  - the binary an update by an earlier version replaced on Windows
+    (clauderock.exe.old); the backup 'manage update rollback' restores is kept
+  - unfinished profile writes (.*.tmp) in ~/.clauderock
+
+Only files older than an hour are touched, so an update or save still in
+progress is left alone. Every launch does the same cleanup quietly in the
+background.
+
+Examples:
+  clauderock manage doctor cleanup
+  clauderock manage doctor cleanup --dry-run`,
+	Args: cobra.NoArgs,
+	RunE: runDoctorCleanup,
+}
+
+func init() {
+	doctorCleanupCmd.Flags().BoolVar(&doctorCleanupDryRun, "dry-run", false, "List the files without removing them")
+	doctorCmd.AddCommand(doctorCleanupCmd)
+}
+
+func runDoctorCleanup(cmd *cobra.Command, args []string) error {
+	artifacts := updater.FindStaleArtifacts(updater.StaleAge)
+	if len(artifacts) == 0 {
+		fmt.Println(mutedStyle.Render("No leftover temporary files found."))
+		return nil
+	}
+
+	var total int64
+	for _, a := range artifacts {
+		total += a.Size
+		fmt.Printf("  %s %s\n", a.Path, mutedStyle.Render(fmt.Sprintf("(%s, %s)", formatBytes(a.Size), a.ModTime.Format("2006-01-02 15:04"))))
+	}
+	fmt.Println()
+
+	if doctorCleanupDryRun {
+		fmt.Printf("%d file(s), %s would be reclaimed\n", len(artifacts), formatBytes(total))
+		return nil
+	}
+
+	cmd.SilenceUsage = true
+	reclaimed, failed := updater.RemoveArtifacts(artifacts)
+	fmt.Printf("✓ Removed %d file(s), reclaimed %s\n", len(artifacts)-len(failed), formatBytes(reclaimed))
+	if len(failed) > 0 {
+		for _, a := range failed {
+			fmt.Printf("  Could not remove %s\n", a.Path)
+		}
+		return fmt.Errorf("%d file(s) could not be removed; a .old binary is removed once it no longer runs", len(failed))
+	}
+	return nil
+}
+
+// formatBytes renders a size in bytes with a binary unit
+func formatBytes(n int64) string {
+	const unit = 1024
+	if n < unit {
+		return fmt.Sprintf("%d B", n)
+	}
+	div, exp := int64(unit), 0
+	for m := n / unit; m >= unit; m /= unit {
+		div *= unit
+		exp++
+	}
+	return fmt.Sprintf("%.1f %ciB", float64(n)/float64(div), "KMGTPE"[exp])
+}
@@ -0,0 +1,76 @@
+package cmd
+
+import (
+	"fmt"
+
+	"github.com/OlaHulleberg/clauderock/internal/permissions"
+	"github.com/spf13/cobra"
+)
+
+var doctorPermissionsFix bool
+
+var doctorPermissionsCmd = &cobra.Command{
+	Use:   "permissions",
+	Short: "Check that profiles and keys are private to you",
+	Long: `Check that clauderock's profiles (~/.clauderock/profiles) and keyring
+(~/.clauderock/keyring) can only be read by you, and that clauderock isn't
+running as root.
+
+Profiles reference AWS profiles and keyring entries, and the keyring is
+encrypted with a passphrase derived from the machine and user name, so other
+users who can read it can decrypt the API keys in it. --fix makes files 0600
+and directories 0700. Files owned by another user, usually from running
+clauderock with sudo, need chown instead.
+
+Every launch runs the same check and warns when it finds a problem.
+
+Examples:
+  clauderock manage doctor permissions
+  clauderock manage doctor permissions --fix`,
+	Args: cobra.NoArgs,
+	RunE: runDoctorPermissions,
+}
+
+func init() {
+	doctorPermissionsCmd.Flags().BoolVar(&doctorPermissionsFix, "fix", false, "Make the files private to you")
+	doctorCmd.AddCommand(doctorPermissionsCmd)
+}
+
+func runDoctorPermissions(cmd *cobra.Command, args []string) error {
+	if permissions.Elevated() {
+		fmt.Println("⚠ Running with elevated privileges; run clauderock as your own user")
+		fmt.Println()
+	}
+
+	problems, err := permissions.Check()
+	if err != nil {
+		return err
+	}
+	if len(problems) == 0 {
+		fmt.Println("✓ Profiles and keyring are private to you")
+		return nil
+	}
+
+	// Problems are listed above the error, so usage would only bury them
+	cmd.SilenceUsage = true
+	unfixable := 0
+	for _, p := range problems {
+		fmt.Printf("  %s %s %s\n", p.Path, mutedStyle.Render(fmt.Sprintf("(%04o)", p.Mode)), p.Issue)
+		if !p.Fixable() {
+			unfixable++
+		}
+	}
+	fmt.Println()
+
+	if !doctorPermissionsFix {
+		return fmt.Errorf("%d problem(s) found; run with --fix to correct them", len(problems))
+	}
+	if err := permissions.Fix(problems); err != nil {
+		return err
+	}
+	fmt.Printf("✓ Made %d file(s) private\n", len(problems)-unfixable)
+	if unfixable > 0 {
+		return fmt.Errorf("%d file(s) belong to another user; change their owner with: sudo chown -R $USER ~/.clauderock", unfixable)
+	}
+	return nil
+}
@@ -0,0 +1,86 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/OlaHulleberg/clauderock/internal/iac"
+	"github.com/OlaHulleberg/clauderock/internal/profiles"
+	"github.com/spf13/cobra"
+)
+
+var (
+	exportIaCFormat       string
+	exportIaCProfile      string
+	exportIaCAccount      string
+	exportIaCLogGroup     string
+	exportIaCLogRetention int
+	exportIaCOutput       string
+)
+
+var exportIaCCmd = &cobra.Command{
+	Use:   "export-iac",
+	Short: "Export Terraform or CloudFormation for a profile's AWS requirements",
+	Long: `Export infrastructure-as-code snippets for what a Bedrock profile needs:
+a least-privilege IAM policy for its models, an optional CloudWatch log group,
+and the Bedrock model access it relies on (as comments, since model access
+cannot be provisioned as code).
+
+Examples:
+  clauderock manage export-iac --format terraform
+  clauderock manage export-iac --format cloudformation --profile work --account 123456789012
+  clauderock manage export-iac --format terraform --log-group /clauderock/work -o clauderock.tf`,
+	RunE: runExportIaC,
+}
+
+func init() {
+	// Registered by manage.go
+
+	exportIaCCmd.Flags().StringVar(&exportIaCFormat, "format", iac.FormatTerraform, "Output format: terraform or cloudformation")
+	exportIaCCmd.Flags().StringVar(&exportIaCProfile, "profile", "", "Profile to export (defaults to the active profile)")
+	exportIaCCmd.Flags().StringVar(&exportIaCAccount, "account", "", "AWS account ID for the policy (defaults to any account)")
+	exportIaCCmd.Flags().StringVar(&exportIaCLogGroup, "log-group", "", "Also create a CloudWatch log group with this name")
+	exportIaCCmd.Flags().IntVar(&exportIaCLogRetention, "log-retention", iac.DefaultLogRetentionDays, "Retention in days for --log-group")
+	exportIaCCmd.Flags().StringVarP(&exportIaCOutput, "output", "o", "", "Write to a file instead of stdout")
+}
+
+func runExportIaC(cmd *cobra.Command, args []string) error {
+	mgr, err := profiles.NewManager()
+	if err != nil {
+		return fmt.Errorf("failed to create profile manager: %w", err)
+	}
+
+	name := exportIaCProfile
+	if name == "" {
+		name, err = mgr.GetCurrent()
+		if err != nil {
+			return fmt.Errorf("failed to get current profile: %w", err)
+		}
+	}
+
+	cfg, err := mgr.Load(name)
+	if err != nil {
+		return fmt.Errorf("failed to load profile '%s': %w", name, err)
+	}
+
+	out, err := iac.Generate(exportIaCFormat, iac.Spec{
+		ProfileName:      name,
+		Config:           cfg,
+		AccountID:        exportIaCAccount,
+		LogGroup:         exportIaCLogGroup,
+		LogRetentionDays: exportIaCLogRetention,
+	})
+	if err != nil {
+		return err
+	}
+
+	if exportIaCOutput == "" {
+		fmt.Print(out)
+		return nil
+	}
+	if err := os.WriteFile(exportIaCOutput, []byte(out), 0644); err != nil {
+		return fmt.Errorf("failed to write %s: %w", exportIaCOutput, err)
+	}
+	fmt.Printf("Exported %s for profile '%s' to %s\n", exportIaCFormat, name, exportIaCOutput)
+	return nil
+}
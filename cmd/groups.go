@@ -0,0 +1,202 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"sort"
+	"text/tabwriter"
+	"time"
+
+	"github.com/OlaHulleberg/clauderock/internal/profiles"
+	"github.com/OlaHulleberg/clauderock/internal/usage"
+	"github.com/spf13/cobra"
+)
+
+var groupsCmd = &cobra.Command{
+	Use:   "groups",
+	Short: "Manage profile groups for spreading load across accounts",
+	Long: `Manage profile groups.
+
+A group lists profiles (usually one per Bedrock account). Launching with
+--clauderock-group picks the member with the most headroom: the fewest
+throttle events and the lowest peak TPM in the last hour, rotating between
+members that are equally idle.
+
+Examples:
+  clauderock manage groups create team acct-a acct-b acct-c
+  clauderock manage groups status team
+  clauderock --clauderock-group team`,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		mgr, err := profiles.NewManager()
+		if err != nil {
+			return fmt.Errorf("failed to create profile manager: %w", err)
+		}
+
+		names, err := mgr.ListGroups()
+		if err != nil {
+			return err
+		}
+		if len(names) == 0 {
+			fmt.Println("No groups found. Create one with: clauderock manage groups create <name> <profile>...")
+			return nil
+		}
+
+		fmt.Println("Available groups:")
+		for _, name := range names {
+			group, err := mgr.LoadGroup(name)
+			if err != nil {
+				fmt.Printf("    %s (unreadable: %v)\n", name, err)
+				continue
+			}
+			fmt.Printf("    %s: %v\n", name, group.Members)
+		}
+		return nil
+	},
+}
+
+var groupsCreateCmd = &cobra.Command{
+	Use:   "create <name> <profile>...",
+	Short: "Create or replace a profile group",
+	Args:  cobra.MinimumNArgs(2),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		mgr, err := profiles.NewManager()
+		if err != nil {
+			return fmt.Errorf("failed to create profile manager: %w", err)
+		}
+
+		group := &profiles.Group{Name: args[0], Members: args[1:]}
+		if err := mgr.SaveGroup(group); err != nil {
+			return err
+		}
+
+		fmt.Printf("Saved group '%s' with %d members\n", group.Name, len(group.Members))
+		return nil
+	},
+}
+
+var groupsDeleteCmd = &cobra.Command{
+	Use:   "delete <name>",
+	Short: "Delete a profile group (member profiles are kept)",
+	Args:  cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		mgr, err := profiles.NewManager()
+		if err != nil {
+			return fmt.Errorf("failed to create profile manager: %w", err)
+		}
+
+		if err := mgr.DeleteGroup(args[0]); err != nil {
+			return err
+		}
+
+		fmt.Printf("Deleted group '%s'\n", args[0])
+		return nil
+	},
+}
+
+var groupsStatusCmd = &cobra.Command{
+	Use:   "status <name>",
+	Short: "Show recent load per member and which one the next launch would use",
+	Args:  cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		mgr, err := profiles.NewManager()
+		if err != nil {
+			return fmt.Errorf("failed to create profile manager: %w", err)
+		}
+
+		group, err := mgr.LoadGroup(args[0])
+		if err != nil {
+			return err
+		}
+
+		candidates, err := rankGroupMembers(group)
+		if err != nil {
+			return err
+		}
+
+		w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+		fmt.Fprintln(w, "MEMBER\tSESSIONS (1h)\tTHROTTLES (1h)\tPEAK TPM (1h)\tLAST PICKED")
+		for i, c := range candidates {
+			name := c.Profile
+			if i == 0 {
+				name += " (next)"
+			}
+			lastPicked := "never"
+			if !c.LastPicked.IsZero() {
+				lastPicked = c.LastPicked.Format("Jan 02 15:04")
+			}
+			fmt.Fprintf(w, "%s\t%d\t%d\t%s\t%s\n", name, c.Sessions, c.ThrottleEvents, formatFloat(c.PeakTPM), lastPicked)
+		}
+		return w.Flush()
+	},
+}
+
+func init() {
+	// Registered by manage.go
+
+	groupsCmd.AddCommand(groupsCreateCmd)
+	groupsCmd.AddCommand(groupsDeleteCmd)
+	groupsCmd.AddCommand(groupsStatusCmd)
+}
+
+// groupCandidate is a group member with its recent load
+type groupCandidate struct {
+	usage.ProfileLoad
+	LastPicked time.Time
+}
+
+// rankGroupMembers orders members by headroom: fewest recent throttles, then lowest
+// recent peak TPM, then least recently picked so idle members take turns
+func rankGroupMembers(group *profiles.Group) ([]groupCandidate, error) {
+	tracker, err := usage.NewTracker()
+	if err != nil {
+		return nil, fmt.Errorf("failed to create tracker: %w", err)
+	}
+	defer tracker.Close()
+
+	since := time.Now().Add(-usage.HeadroomWindow)
+	candidates := make([]groupCandidate, 0, len(group.Members))
+	for _, member := range group.Members {
+		load, err := tracker.RecentLoad(member, since)
+		if err != nil {
+			return nil, err
+		}
+		candidates = append(candidates, groupCandidate{ProfileLoad: load, LastPicked: group.LastPicked[member]})
+	}
+
+	sort.SliceStable(candidates, func(i, j int) bool {
+		a, b := candidates[i], candidates[j]
+		if a.ThrottleEvents != b.ThrottleEvents {
+			return a.ThrottleEvents < b.ThrottleEvents
+		}
+		if a.PeakTPM != b.PeakTPM {
+			return a.PeakTPM < b.PeakTPM
+		}
+		return a.LastPicked.Before(b.LastPicked)
+	})
+	return candidates, nil
+}
+
+// pickGroupMember chooses the member profile with the most headroom and records the pick
+func pickGroupMember(mgr *profiles.Manager, groupName string) (string, error) {
+	group, err := mgr.LoadGroup(groupName)
+	if err != nil {
+		return "", err
+	}
+	if len(group.Members) == 0 {
+		return "", fmt.Errorf("group '%s' has no members", groupName)
+	}
+
+	candidates, err := rankGroupMembers(group)
+	if err != nil {
+		return "", err
+	}
+	picked := candidates[0]
+
+	if err := mgr.MarkPicked(group, picked.Profile); err != nil {
+		fmt.Printf("Warning: failed to record group rotation: %v\n", err)
+	}
+
+	fmt.Printf("Group '%s': using profile '%s' (%d throttles, peak %s TPM in the last hour)\n",
+		groupName, picked.Profile, picked.ThrottleEvents, formatFloat(picked.PeakTPM))
+	return picked.Profile, nil
+}
@@ -0,0 +1,124 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+
+	"github.com/OlaHulleberg/clauderock/internal/updater"
+	"github.com/spf13/cobra"
+)
+
+var (
+	installJSON    bool
+	installRelease string
+	installOutput  string
+	installScript  string
+)
+
+var installCmd = &cobra.Command{
+	Use:   "install",
+	Short: "Show how clauderock was installed and generate distribution files",
+	Long: `Show how clauderock was installed and how to keep it up to date.
+
+Binaries installed with Homebrew, Scoop, or go install are updated by their
+package manager; 'manage update' only replaces binaries from install.sh or a
+release archive, and points the others at their upgrade command.`,
+	Args: cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		info, err := updater.DetectInstall(Version)
+		if err != nil {
+			return err
+		}
+
+		if installJSON {
+			data, err := json.MarshalIndent(info, "", "  ")
+			if err != nil {
+				return fmt.Errorf("failed to marshal install info: %w", err)
+			}
+			fmt.Println(string(data))
+			return nil
+		}
+
+		fmt.Printf("Method:  %s\n", info.Method)
+		fmt.Printf("Binary:  %s\n", info.Path)
+		fmt.Printf("Version: %s\n", Version)
+		fmt.Printf("Update:  %s\n", info.UpdateCommand)
+		return nil
+	},
+}
+
+var installFormulaCmd = &cobra.Command{
+	Use:   "formula",
+	Short: "Generate the Homebrew formula for a release",
+	Long: `Generate the Homebrew formula for a release, using the archives and checksums
+the release publishes, so the tap installs the same binaries as 'manage update'.
+
+Examples:
+  clauderock manage install formula
+  clauderock manage install formula --release v0.9.0 --output Formula/clauderock.rb`,
+	Args: cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		rc, err := updater.FetchReleaseChecksums(installRelease)
+		if err != nil {
+			return err
+		}
+
+		formula := updater.BrewFormula(rc)
+		if installOutput == "" {
+			fmt.Print(formula)
+			return nil
+		}
+		if err := os.WriteFile(installOutput, []byte(formula), 0644); err != nil {
+			return fmt.Errorf("failed to write formula: %w", err)
+		}
+		fmt.Printf("✓ Wrote Homebrew formula for %s to %s\n", rc.Tag, installOutput)
+		return nil
+	},
+}
+
+var installPinScriptCmd = &cobra.Command{
+	Use:   "pin-script",
+	Short: "Pin install.sh to a release and its checksums",
+	Long: `Pin install.sh to a release: the script then installs exactly that release and
+refuses an archive whose SHA-256 doesn't match the release's checksums.txt.
+Only the pinned release block of the script is rewritten.
+
+Examples:
+  clauderock manage install pin-script
+  clauderock manage install pin-script --release v0.9.0 --file install.sh`,
+	Args: cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		script, err := os.ReadFile(installScript)
+		if err != nil {
+			return fmt.Errorf("failed to read install script: %w", err)
+		}
+
+		rc, err := updater.FetchReleaseChecksums(installRelease)
+		if err != nil {
+			return err
+		}
+
+		pinned, err := updater.PinInstallScript(script, rc)
+		if err != nil {
+			return err
+		}
+		if err := os.WriteFile(installScript, pinned, 0755); err != nil {
+			return fmt.Errorf("failed to write install script: %w", err)
+		}
+		fmt.Printf("✓ Pinned %s to %s (%d checksums)\n", installScript, rc.Tag, len(rc.Checksums))
+		return nil
+	},
+}
+
+func init() {
+	// Registered by manage.go
+	installCmd.AddCommand(installFormulaCmd)
+	installCmd.AddCommand(installPinScriptCmd)
+
+	installCmd.Flags().BoolVar(&installJSON, "json", false, "Output as JSON")
+	installFormulaCmd.Flags().StringVar(&installRelease, "release", "", "Release tag (defaults to the latest release)")
+	installFormulaCmd.Flags().StringVarP(&installOutput, "output", "o", "", "Write the formula to a file instead of stdout")
+	installPinScriptCmd.Flags().StringVar(&installRelease, "release", "", "Release tag (defaults to the latest release)")
+	installPinScriptCmd.Flags().StringVar(&installScript, "file", "install.sh", "Install script to update")
+}
@@ -0,0 +1,208 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/OlaHulleberg/clauderock/internal/currency"
+	"github.com/OlaHulleberg/clauderock/internal/profiles"
+	"github.com/OlaHulleberg/clauderock/internal/usage"
+	"github.com/spf13/cobra"
+)
+
+const (
+	integrateBlockStart = "# >>> clauderock prompt >>>"
+	integrateBlockEnd   = "# <<< clauderock prompt <<<"
+)
+
+var (
+	integrateShellName    string
+	integrateShellInstall bool
+)
+
+var integrateCmd = &cobra.Command{
+	Use:   "integrate",
+	Short: "Integrate clauderock with your shell and tools",
+}
+
+var integrateShellCmd = &cobra.Command{
+	Use:   "shell",
+	Short: "Generate or install a prompt segment showing the active profile and spend",
+	Long: `Generate a prompt segment that shows the active clauderock profile and the
+month-to-date estimated spend from the local usage database.
+
+Supported prompts: starship, zsh (including oh-my-zsh), bash. By default the
+snippet is printed; use --install to add it to your config file. Installing
+again replaces the previously installed snippet.
+
+Examples:
+  clauderock manage integrate shell --shell starship
+  clauderock manage integrate shell --shell zsh --install`,
+	RunE: runIntegrateShell,
+}
+
+var integratePromptCmd = &cobra.Command{
+	Use:   "prompt",
+	Short: "Print the prompt segment (used by the shell integration)",
+	Args:  cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		segment, err := promptSegment()
+		if err != nil {
+			// Never break the user's prompt; print nothing instead
+			return nil
+		}
+		fmt.Println(segment)
+		return nil
+	},
+}
+
+func init() {
+	// Registered by manage.go
+	integrateCmd.AddCommand(integrateShellCmd)
+	integrateCmd.AddCommand(integratePromptCmd)
+
+	integrateShellCmd.Flags().StringVar(&integrateShellName, "shell", "", "Prompt to integrate with: starship, zsh, bash (default: detected from $SHELL)")
+	integrateShellCmd.Flags().BoolVar(&integrateShellInstall, "install", false, "Install the snippet into the prompt's config file")
+}
+
+func runIntegrateShell(cmd *cobra.Command, args []string) error {
+	shell := integrateShellName
+	if shell == "" {
+		shell = filepath.Base(os.Getenv("SHELL"))
+	}
+
+	snippet, ok := promptSnippets[shell]
+	if !ok {
+		return fmt.Errorf("unsupported shell '%s' (must be one of: starship, zsh, bash)", shell)
+	}
+
+	if !integrateShellInstall {
+		fmt.Println(snippet)
+		return nil
+	}
+
+	path, err := promptConfigPath(shell)
+	if err != nil {
+		return err
+	}
+
+	if err := installSnippet(path, snippet); err != nil {
+		return err
+	}
+
+	fmt.Printf("✓ Installed clauderock prompt segment in %s\n", path)
+	fmt.Println("Open a new shell (or re-source the file) to see it.")
+	return nil
+}
+
+// promptSnippets holds the prompt integration for each supported shell, wrapped in markers
+var promptSnippets = map[string]string{
+	"starship": integrateBlockStart + `
+[custom.clauderock]
+command = "clauderock manage integrate prompt"
+when = "command -v clauderock"
+shell = ["sh"]
+format = "[$output]($style) "
+style = "bold purple"
+` + integrateBlockEnd,
+
+	"zsh": integrateBlockStart + `
+clauderock_prompt_info() { clauderock manage integrate prompt 2>/dev/null }
+setopt PROMPT_SUBST
+RPROMPT='$(clauderock_prompt_info)'"${RPROMPT:+ $RPROMPT}"
+` + integrateBlockEnd,
+
+	"bash": integrateBlockStart + `
+__clauderock_ps1() { clauderock manage integrate prompt 2>/dev/null; }
+PS1='$(__clauderock_ps1) '"$PS1"
+` + integrateBlockEnd,
+}
+
+// promptConfigPath returns the config file the snippet is installed into
+func promptConfigPath(shell string) (string, error) {
+	if shell == "starship" {
+		if path := os.Getenv("STARSHIP_CONFIG"); path != "" {
+			return path, nil
+		}
+	}
+
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return "", fmt.Errorf("failed to get home directory: %w", err)
+	}
+
+	switch shell {
+	case "starship":
+		return filepath.Join(home, ".config", "starship.toml"), nil
+	case "zsh":
+		return filepath.Join(home, ".zshrc"), nil
+	default:
+		return filepath.Join(home, ".bashrc"), nil
+	}
+}
+
+// installSnippet appends the snippet to path, replacing a previously installed one
+func installSnippet(path, snippet string) error {
+	data, err := os.ReadFile(path)
+	if err != nil && !os.IsNotExist(err) {
+		return fmt.Errorf("failed to read %s: %w", path, err)
+	}
+	content := string(data)
+
+	// Drop an existing block so reinstalling is idempotent
+	if start := strings.Index(content, integrateBlockStart); start != -1 {
+		if end := strings.Index(content[start:], integrateBlockEnd); end != -1 {
+			end += start + len(integrateBlockEnd)
+			content = content[:start] + strings.TrimPrefix(content[end:], "\n")
+		}
+	}
+
+	if content != "" && !strings.HasSuffix(content, "\n") {
+		content += "\n"
+	}
+	content += snippet + "\n"
+
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return fmt.Errorf("failed to create directory: %w", err)
+	}
+	if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+		return fmt.Errorf("failed to write %s: %w", path, err)
+	}
+	return nil
+}
+
+// promptSegment returns "<profile> $<month-to-date spend>" for the prompt
+func promptSegment() (string, error) {
+	mgr, err := profiles.NewManager()
+	if err != nil {
+		return "", err
+	}
+	current, err := mgr.GetCurrent()
+	if err != nil {
+		return "", err
+	}
+
+	db, err := usage.NewDatabase()
+	if err != nil {
+		return "", err
+	}
+	defer db.Close()
+
+	now := time.Now()
+	sessions, err := db.QuerySessions(usage.QueryFilter{
+		StartDate: time.Date(now.Year(), now.Month(), 1, 0, 0, 0, 0, now.Location()),
+	})
+	if err != nil {
+		return "", err
+	}
+
+	var spend float64
+	for _, s := range sessions {
+		spend += usage.SessionCost(s)
+	}
+
+	return fmt.Sprintf("☁ %s %s", current, currency.Format(spend)), nil
+}
@@ -0,0 +1,109 @@
+package cmd
+
+import (
+	"fmt"
+
+	"github.com/OlaHulleberg/clauderock/internal/policy"
+	"github.com/spf13/cobra"
+)
+
+// kioskAnnotation marks the commands that stay available in kiosk mode: launching and
+// read-only views. Everything else is disabled, so commands added later, including
+// those plugins provide, are blocked until they are reviewed and allowed here
+const kioskAnnotation = "clauderock-kiosk-allowed"
+
+// kioskError explains why a command is unavailable in kiosk mode
+func kioskError(what, source string) error {
+	return fmt.Errorf("%s is disabled in kiosk mode (enabled by %s); only launching with the provisioned profiles and read-only commands are allowed", what, source)
+}
+
+// checkKiosk refuses commands not marked with kioskAnnotation when kiosk mode is on
+func checkKiosk(cmd *cobra.Command, args []string) error {
+	if cmd.Annotations[kioskAnnotation] != "" || isCobraBuiltin(cmd) {
+		return nil
+	}
+
+	kiosk, source, err := policy.KioskMode()
+	if err != nil {
+		return err
+	}
+	if kiosk {
+		return kioskError(fmt.Sprintf("'%s'", cmd.CommandPath()), source)
+	}
+	return nil
+}
+
+// isCobraBuiltin reports whether cmd is cobra's help or shell completion, which only print
+func isCobraBuiltin(cmd *cobra.Command) bool {
+	for c := cmd; c != nil; c = c.Parent() {
+		switch c.Name() {
+		case "help", "completion", cobra.ShellCompRequestCmd, cobra.ShellCompNoDescRequestCmd:
+			return true
+		}
+	}
+	return false
+}
+
+func init() {
+	rootCmd.PersistentPreRunE = checkKiosk
+
+	allowed := []*cobra.Command{
+		// Launching, which checks kiosk mode itself for overrides and saving
+		rootCmd,
+		tmuxCmd,
+		daemonCmd,
+		profileSwitchCmd,
+
+		// Accessible mode, which some users need to use the machine at all
+		accessibilityCmd,
+		accessibilityStatusCmd,
+		accessibilityEnableCmd,
+		accessibilityDisableCmd,
+
+		// Read-only views and checks; doctor permissions --fix only narrows file modes
+		manageCmd,
+		adviseCmd,
+		apiCmd,
+		apiConformanceCmd,
+		configGetCmd,
+		configListCmd,
+		configDoctorCmd,
+		profilesCmd,
+		profileShowCmd,
+		currencyCmd,
+		dashboardCmd,
+		doctorCmd,
+		doctorIAMCmd,
+		doctorPermissionsCmd,
+		exportIaCCmd,
+		groupsCmd,
+		groupsStatusCmd,
+		installCmd,
+		integrateCmd,
+		integratePromptCmd,
+		metricsCmd,
+		metricsStatusCmd,
+		modelsCmd,
+		modelsListCmd,
+		modelsTestCmd,
+		modelsWatchCmd,
+		pluginsCmd,
+		policyCmd,
+		pricingCmd,
+		pricingListCmd,
+		statsCmd,
+		statsBrowseCmd,
+		statsCompareCmd,
+		statsCostCmd,
+		statsViewCmd,
+		telemetryCmd,
+		telemetryStatusCmd,
+		versionCmd,
+	}
+	for _, c := range allowed {
+		if c.Annotations == nil {
+			c.Annotations = make(map[string]string)
+		}
+		c.Annotations[kioskAnnotation] = "true"
+	}
+}
@@ -19,5 +19,20 @@ func init() {
 	manageCmd.AddCommand(modelsCmd)
 	manageCmd.AddCommand(statsCmd)
 	manageCmd.AddCommand(updateCmd)
+	manageCmd.AddCommand(installCmd)
 	manageCmd.AddCommand(versionCmd)
+	manageCmd.AddCommand(integrateCmd)
+	manageCmd.AddCommand(adviseCmd)
+	manageCmd.AddCommand(groupsCmd)
+	manageCmd.AddCommand(policyCmd)
+	manageCmd.AddCommand(telemetryCmd)
+	manageCmd.AddCommand(metricsCmd)
+	manageCmd.AddCommand(accessibilityCmd)
+	manageCmd.AddCommand(doctorCmd)
+	manageCmd.AddCommand(exportIaCCmd)
+	manageCmd.AddCommand(dashboardCmd)
+	manageCmd.AddCommand(pricingCmd)
+	manageCmd.AddCommand(currencyCmd)
+	manageCmd.AddCommand(apiCmd)
+	manageCmd.AddCommand(pluginsCmd)
 }
@@ -0,0 +1,179 @@
+package cmd
+
+import (
+	"fmt"
+	"net/url"
+	"path/filepath"
+	"sort"
+
+	"github.com/OlaHulleberg/clauderock/internal/usage"
+	"github.com/spf13/cobra"
+)
+
+var (
+	metricsOTLPEndpoint   string
+	metricsOTLPHeaders    map[string]string
+	metricsPrometheusFile string
+	metricsDeveloper      string
+)
+
+var metricsCmd = &cobra.Command{
+	Use:   "metrics",
+	Short: "Export usage metrics to OpenTelemetry or Prometheus (off by default)",
+	Long: `Export usage metrics to OpenTelemetry or Prometheus.
+
+Exporting is opt-in and off by default. When enabled, clauderock exports the
+cumulative sessions, requests, tokens, estimated cost, and active time of every
+tracked session after each session ends, labelled by developer, profile, model,
+and region, plus the tokens per minute of the most recent session. Platform
+teams can then aggregate usage across developers in Grafana.
+
+Targets:
+  --otlp-endpoint     Push to an OpenTelemetry collector over OTLP/HTTP (JSON)
+  --prometheus-file   Write a file for node_exporter's textfile collector
+
+Examples:
+  clauderock manage metrics enable --otlp-endpoint http://otel.internal:4318
+  clauderock manage metrics enable --prometheus-file /var/lib/node_exporter/textfile/clauderock.prom
+  clauderock manage metrics push`,
+}
+
+var metricsStatusCmd = &cobra.Command{
+	Use:   "status",
+	Short: "Show where usage metrics are exported",
+	RunE: func(cmd *cobra.Command, args []string) error {
+		settings, err := usage.LoadMetricsExportSettings()
+		if err != nil {
+			return err
+		}
+		if !settings.Enabled() {
+			fmt.Println("Metrics export: disabled")
+			return nil
+		}
+
+		fmt.Println("Metrics export: enabled")
+		fmt.Printf("Developer:       %s\n", settings.DeveloperName())
+		if settings.OTLPEndpoint != "" {
+			fmt.Printf("OTLP endpoint:   %s\n", settings.OTLPEndpoint)
+			names := make([]string, 0, len(settings.OTLPHeaders))
+			for name := range settings.OTLPHeaders {
+				names = append(names, name)
+			}
+			sort.Strings(names)
+			for _, name := range names {
+				// Header values are often credentials
+				fmt.Printf("  header %s: ****\n", name)
+			}
+		}
+		if settings.PrometheusFile != "" {
+			fmt.Printf("Prometheus file: %s\n", settings.PrometheusFile)
+		}
+		return nil
+	},
+}
+
+var metricsEnableCmd = &cobra.Command{
+	Use:   "enable",
+	Short: "Export usage metrics to an OTLP collector and/or a Prometheus textfile",
+	Args:  cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		settings, err := usage.LoadMetricsExportSettings()
+		if err != nil {
+			return err
+		}
+
+		flags := cmd.Flags()
+		if flags.Changed("otlp-endpoint") {
+			if metricsOTLPEndpoint != "" {
+				u, err := url.Parse(metricsOTLPEndpoint)
+				if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+					return fmt.Errorf("invalid OTLP endpoint %q: expected an http(s) URL such as http://localhost:4318", metricsOTLPEndpoint)
+				}
+			}
+			settings.OTLPEndpoint = metricsOTLPEndpoint
+		}
+		if flags.Changed("otlp-header") {
+			settings.OTLPHeaders = metricsOTLPHeaders
+		}
+		if flags.Changed("prometheus-file") {
+			settings.PrometheusFile = metricsPrometheusFile
+			if settings.PrometheusFile != "" {
+				abs, err := filepath.Abs(settings.PrometheusFile)
+				if err != nil {
+					return fmt.Errorf("invalid Prometheus file path: %w", err)
+				}
+				settings.PrometheusFile = abs
+			}
+		}
+		if flags.Changed("developer") {
+			settings.Developer = metricsDeveloper
+		}
+
+		if !settings.Enabled() {
+			return fmt.Errorf("no export target: set --otlp-endpoint and/or --prometheus-file")
+		}
+		if err := usage.SaveMetricsExportSettings(settings); err != nil {
+			return err
+		}
+		fmt.Println("Metrics export enabled. Metrics are exported after each session ends.")
+
+		// Export what's already tracked, so dashboards fill in straight away
+		return pushMetrics()
+	},
+}
+
+var metricsDisableCmd = &cobra.Command{
+	Use:   "disable",
+	Short: "Stop exporting usage metrics",
+	RunE: func(cmd *cobra.Command, args []string) error {
+		if err := usage.SaveMetricsExportSettings(usage.MetricsExportSettings{}); err != nil {
+			return err
+		}
+		fmt.Println("Metrics export disabled")
+		return nil
+	},
+}
+
+var metricsPushCmd = &cobra.Command{
+	Use:   "push",
+	Short: "Export usage metrics now",
+	RunE: func(cmd *cobra.Command, args []string) error {
+		settings, err := usage.LoadMetricsExportSettings()
+		if err != nil {
+			return err
+		}
+		if !settings.Enabled() {
+			return fmt.Errorf("metrics export is disabled; enable it with: clauderock manage metrics enable")
+		}
+		return pushMetrics()
+	},
+}
+
+func init() {
+	// Registered by manage.go
+
+	metricsEnableCmd.Flags().StringVar(&metricsOTLPEndpoint, "otlp-endpoint", "", "OTLP/HTTP collector URL, e.g. http://localhost:4318 (empty to stop pushing)")
+	metricsEnableCmd.Flags().StringToStringVar(&metricsOTLPHeaders, "otlp-header", nil, "Header sent to the collector, e.g. Authorization=\"Bearer ...\" (repeatable)")
+	metricsEnableCmd.Flags().StringVar(&metricsPrometheusFile, "prometheus-file", "", "Prometheus textfile to write, e.g. .../textfile/clauderock.prom (empty to stop writing)")
+	metricsEnableCmd.Flags().StringVar(&metricsDeveloper, "developer", "", "Developer label on exported metrics (default: your OS user name)")
+
+	metricsCmd.AddCommand(metricsStatusCmd)
+	metricsCmd.AddCommand(metricsEnableCmd)
+	metricsCmd.AddCommand(metricsDisableCmd)
+	metricsCmd.AddCommand(metricsPushCmd)
+}
+
+// pushMetrics exports the cumulative usage of all tracked sessions to the configured targets
+func pushMetrics() error {
+	tracker, err := usage.NewTracker()
+	if err != nil {
+		return fmt.Errorf("failed to open usage database: %w", err)
+	}
+	defer tracker.Close()
+
+	if err := tracker.ExportMetrics(); err != nil {
+		return err
+	}
+	fmt.Println("Usage metrics exported")
+	return nil
+}
@@ -1,13 +1,21 @@
 package cmd
 
 import (
+	"encoding/json"
 	"fmt"
+	"os"
 	"sort"
 	"strings"
+	"text/tabwriter"
+	"time"
 
+	"github.com/OlaHulleberg/clauderock/internal/api"
 	"github.com/OlaHulleberg/clauderock/internal/aws"
 	"github.com/OlaHulleberg/clauderock/internal/config"
+	"github.com/OlaHulleberg/clauderock/internal/keyring"
+	"github.com/OlaHulleberg/clauderock/internal/pricing"
 	"github.com/OlaHulleberg/clauderock/internal/profiles"
+	"github.com/OlaHulleberg/clauderock/internal/vertex"
 	"github.com/spf13/cobra"
 	"golang.org/x/text/cases"
 	"golang.org/x/text/language"
@@ -18,8 +26,26 @@ var (
 	crossRegionFilter  string
 	profileFilterModel string
 	regionFilter       string
+	modelsOutput       string
+	modelsJSON         bool
+	modelsRefresh      bool
+	modelsTestModel    string
+	modelsTestProfile  string
 )
 
+// modelListEntry is the structured representation of a model used by --output json
+type modelListEntry struct {
+	Name          string   `json:"name"`
+	Provider      string   `json:"provider"`
+	Model         string   `json:"model"`
+	ProfileID     string   `json:"profile-id"`
+	CrossRegions  []string `json:"cross-regions"`
+	ContextWindow int      `json:"context-window,omitempty"`
+	InputCost     float64  `json:"input-cost-per-mtok,omitempty"`
+	OutputCost    float64  `json:"output-cost-per-mtok,omitempty"`
+	Recommended   []string `json:"recommended,omitempty"`
+}
+
 var modelsCmd = &cobra.Command{
 	Use:   "models",
 	Short: "Manage and list available models",
@@ -32,28 +58,66 @@ var modelsListCmd = &cobra.Command{
 	Long: `List available models from AWS Bedrock.
 
 By default, uses settings from the current profile. You can override
-specific settings using flags.
+specific settings using flags. Listings are cached per region for a few
+hours; use --refresh to fetch them from Bedrock again.
 
 Examples:
   clauderock models list
   clauderock models list --provider anthropic
   clauderock models list --cross-region us
   clauderock models list --profile work-dev
-  clauderock models list --region us-west-2 --cross-region global`,
+  clauderock models list --region us-west-2 --cross-region global
+  clauderock models list --output wide
+  clauderock models list --json
+  clauderock models list --refresh`,
 	RunE: runModelsList,
 }
 
+var modelsTestCmd = &cobra.Command{
+	Use:   "test",
+	Short: "Send a minimal request to each configured model",
+	Long: `Send a minimal request (a few tokens) to each configured model slot and
+report latency and success.
+
+This verifies end-to-end access: credentials, IAM policy, model access grants,
+and (for API profiles) the gateway itself.
+
+Examples:
+  clauderock manage models test
+  clauderock manage models test --profile work-dev
+  clauderock manage models test --model global.anthropic.claude-haiku-4-5-20251001-v1:0`,
+	RunE: runModelsTest,
+}
+
 func init() {
 	// Registered by manage.go
 	modelsCmd.AddCommand(modelsListCmd)
+	modelsCmd.AddCommand(modelsTestCmd)
+
+	modelsTestCmd.Flags().StringVar(&modelsTestModel, "model", "", "Test only this model ID instead of the configured slots")
+	modelsTestCmd.Flags().StringVar(&modelsTestProfile, "profile", "", "Use settings from a specific profile")
 
 	modelsListCmd.Flags().StringVar(&providerFilter, "provider", "", "Filter by provider (e.g., anthropic, meta, amazon)")
 	modelsListCmd.Flags().StringVar(&crossRegionFilter, "cross-region", "", "Override cross-region setting (us, eu, global)")
 	modelsListCmd.Flags().StringVar(&profileFilterModel, "profile", "", "Use settings from a specific profile")
 	modelsListCmd.Flags().StringVar(&regionFilter, "region", "", "Override AWS region")
+	modelsListCmd.Flags().StringVarP(&modelsOutput, "output", "o", "table", "Output format (table, wide, json)")
+	modelsListCmd.Flags().BoolVar(&modelsJSON, "json", false, "Output as JSON (shorthand for --output json)")
+	modelsListCmd.Flags().BoolVar(&modelsRefresh, "refresh", false, "Re-fetch the model list from Bedrock instead of using the cache")
 }
 
 func runModelsList(cmd *cobra.Command, args []string) error {
+	output := modelsOutput
+	if modelsJSON {
+		output = "json"
+	}
+	if output != "table" && output != "wide" && output != "json" {
+		return fmt.Errorf("--output must be one of: table, wide, json")
+	}
+	if modelsRefresh {
+		aws.IgnoreCachedModelLists()
+	}
+
 	// Load profile or use flags
 	var awsProfile, region, crossRegion string
 
@@ -78,7 +142,7 @@ func runModelsList(cmd *cobra.Command, args []string) error {
 		}
 	}
 
-	awsProfile = cfg.Profile
+	awsProfile = cfg.AWSProfile()
 	region = cfg.Region
 	crossRegion = cfg.CrossRegion
 
@@ -90,14 +154,16 @@ func runModelsList(cmd *cobra.Command, args []string) error {
 		crossRegion = crossRegionFilter
 	}
 
-	// Show what we're querying
-	fmt.Printf("Fetching models from AWS Bedrock...\n")
-	fmt.Printf("  Region: %s\n", region)
-	fmt.Printf("  Cross-Region: %s\n", crossRegion)
-	if providerFilter != "" {
-		fmt.Printf("  Provider Filter: %s\n", providerFilter)
+	// Show what we're querying (kept out of structured output)
+	if output != "json" {
+		fmt.Printf("Fetching models from AWS Bedrock...\n")
+		fmt.Printf("  Region: %s\n", region)
+		fmt.Printf("  Cross-Region: %s\n", crossRegion)
+		if providerFilter != "" {
+			fmt.Printf("  Provider Filter: %s\n", providerFilter)
+		}
+		fmt.Println()
 	}
-	fmt.Println()
 
 	// Fetch models
 	models, err := aws.GetAvailableModelsDetailed(awsProfile, region, crossRegion)
@@ -116,6 +182,18 @@ func runModelsList(cmd *cobra.Command, args []string) error {
 		models = filtered
 	}
 
+	switch output {
+	case "json":
+		return displayModelsJSON(models)
+	case "wide":
+		if len(models) == 0 {
+			fmt.Println("No models found matching the criteria.")
+			return nil
+		}
+		displayModelsWide(models, region, crossRegion)
+		return nil
+	}
+
 	if len(models) == 0 {
 		fmt.Println("No models found matching the criteria.")
 		return nil
@@ -156,12 +234,85 @@ func displayModels(grouped map[string][]aws.ModelInfo, region, crossRegion strin
 		fmt.Printf("%s:\n", provider)
 		for _, m := range models {
 			indicator := getModelIndicator(m.Model)
-			fmt.Printf("  • %s%s\n", m.Name, indicator)
+			fmt.Printf("  • %s%s [%s]\n", m.Name, indicator, strings.Join(m.CrossRegions, ", "))
 		}
 		fmt.Println()
 	}
 
 	fmt.Printf("Found %d models across %d providers.\n", totalModels, len(providers))
+	fmt.Println("Brackets show the cross-region prefixes each model is available under.")
+}
+
+// buildModelListEntry enriches a model with catalog, pricing, and recommendation data
+func buildModelListEntry(m aws.ModelInfo) modelListEntry {
+	entry := modelListEntry{
+		Name:         m.Name,
+		Provider:     m.Provider,
+		Model:        m.Model,
+		ProfileID:    m.ProfileID,
+		CrossRegions: m.CrossRegions,
+	}
+	if window, ok := aws.ContextWindow(m.Name); ok {
+		entry.ContextWindow = window
+	}
+	if price, ok := pricing.GetModelPrice(m.Name); ok {
+		entry.InputCost = price.InputCost
+		entry.OutputCost = price.OutputCost
+	}
+	for _, slot := range []string{"main", "fast", "heavy"} {
+		if aws.IsRecommendedModel(m.Name, slot) {
+			entry.Recommended = append(entry.Recommended, slot)
+		}
+	}
+	return entry
+}
+
+func displayModelsJSON(models []aws.ModelInfo) error {
+	entries := make([]modelListEntry, 0, len(models))
+	for _, m := range models {
+		entries = append(entries, buildModelListEntry(m))
+	}
+
+	encoder := json.NewEncoder(os.Stdout)
+	encoder.SetIndent("", "  ")
+	return encoder.Encode(entries)
+}
+
+func displayModelsWide(models []aws.ModelInfo, region, crossRegion string) {
+	fmt.Printf("Available models in %s (%s cross-region):\n\n", region, crossRegion)
+
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, "PROFILE ID\tPROVIDER\tCONTEXT\tPRICE (IN/OUT per 1M)\tRECOMMENDED\tCROSS-REGIONS")
+	for _, m := range models {
+		entry := buildModelListEntry(m)
+
+		contextWindow := "-"
+		if entry.ContextWindow > 0 {
+			contextWindow = fmt.Sprintf("%dk", entry.ContextWindow/1000)
+		}
+
+		price := "-"
+		if entry.InputCost > 0 || entry.OutputCost > 0 {
+			price = fmt.Sprintf("$%.2f / $%.2f", entry.InputCost, entry.OutputCost)
+		}
+
+		recommended := "-"
+		if len(entry.Recommended) > 0 {
+			recommended = strings.Join(entry.Recommended, ",")
+		}
+
+		fmt.Fprintf(w, "%s\t%s\t%s\t%s\t%s\t%s\n",
+			entry.ProfileID,
+			entry.Provider,
+			contextWindow,
+			price,
+			recommended,
+			strings.Join(entry.CrossRegions, ","),
+		)
+	}
+	w.Flush()
+
+	fmt.Printf("\nFound %d models.\n", len(models))
 }
 
 func getModelIndicator(modelName string) string {
@@ -179,3 +330,98 @@ func getModelIndicator(modelName string) string {
 
 	return ""
 }
+
+// modelTestTarget is a single model to exercise in `models test`
+type modelTestTarget struct {
+	Slot    string
+	ModelID string
+}
+
+func runModelsTest(cmd *cobra.Command, args []string) error {
+	mgr, err := profiles.NewManager()
+	if err != nil {
+		return fmt.Errorf("failed to create profile manager: %w", err)
+	}
+
+	var cfg *config.Config
+	profileName := modelsTestProfile
+	if profileName != "" {
+		cfg, err = mgr.Load(profileName)
+		if err != nil {
+			return fmt.Errorf("failed to load profile '%s': %w", profileName, err)
+		}
+	} else {
+		cfg, err = mgr.GetCurrentConfig(Version)
+		if err != nil {
+			return fmt.Errorf("failed to load config: %w", err)
+		}
+		profileName, err = mgr.GetCurrent()
+		if err != nil {
+			return fmt.Errorf("failed to get current profile: %w", err)
+		}
+	}
+
+	var targets []modelTestTarget
+	if modelsTestModel != "" {
+		targets = []modelTestTarget{{Slot: "custom", ModelID: modelsTestModel}}
+	} else {
+		targets = []modelTestTarget{
+			{Slot: "main", ModelID: cfg.Model},
+			{Slot: "fast", ModelID: cfg.FastModel},
+			{Slot: "heavy", ModelID: cfg.HeavyModel},
+		}
+	}
+
+	// Build the per-request test function for this profile type
+	var testFn func(modelID string) (time.Duration, error)
+	switch cfg.ProfileType {
+	case "bedrock":
+		testFn = func(modelID string) (time.Duration, error) {
+			result, err := aws.TestModel(cfg.AWSProfile(), cfg.Region, modelID)
+			return result.Latency, err
+		}
+	case "api":
+		apiKey, err := keyring.Get(cfg.APIKeyID)
+		if err != nil {
+			return fmt.Errorf("failed to retrieve API key from keyring: %w", err)
+		}
+		testFn = func(modelID string) (time.Duration, error) {
+			result, err := api.TestModel(cfg.BaseURL, apiKey, modelID)
+			return result.Latency, err
+		}
+	case "vertex":
+		testFn = func(modelID string) (time.Duration, error) {
+			result, err := vertex.TestModel(cfg.GCPProject, cfg.VertexRegion, modelID)
+			return result.Latency, err
+		}
+	default:
+		return fmt.Errorf("unsupported profile type: %s", cfg.ProfileType)
+	}
+
+	fmt.Printf("Testing models for profile '%s' (type: %s)...\n\n", profileName, cfg.ProfileType)
+
+	failures := 0
+	for _, target := range targets {
+		if target.ModelID == "" {
+			fmt.Printf("  %-6s %s\n", target.Slot, "✗ not configured")
+			failures++
+			continue
+		}
+
+		latency, err := testFn(target.ModelID)
+		if err != nil {
+			fmt.Printf("  %-6s %s\n         ✗ %v\n", target.Slot, target.ModelID, err)
+			failures++
+			continue
+		}
+		fmt.Printf("  %-6s %s\n         ✓ %dms\n", target.Slot, target.ModelID, latency.Milliseconds())
+	}
+	fmt.Println()
+
+	if failures > 0 {
+		return fmt.Errorf("%d of %d model tests failed", failures, len(targets))
+	}
+
+	fmt.Printf("All %d model tests passed.\n", len(targets))
+	return nil
+}
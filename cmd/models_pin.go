@@ -0,0 +1,185 @@
+package cmd
+
+import (
+	"fmt"
+
+	"github.com/OlaHulleberg/clauderock/internal/aws"
+	"github.com/OlaHulleberg/clauderock/internal/config"
+	"github.com/OlaHulleberg/clauderock/internal/profiles"
+	"github.com/spf13/cobra"
+)
+
+var (
+	pinSlot    string
+	pinVersion string
+	pinProfile string
+)
+
+var modelsPinCmd = &cobra.Command{
+	Use:   "pin",
+	Short: "Pin a model slot to a specific dated snapshot",
+	Long: `Pin a model slot to a specific dated snapshot (Bedrock only).
+
+When several snapshots of a model exist (e.g., 20250514 and 20250929), clauderock
+normally resolves to the newest one. Pinning stores the chosen snapshot in the
+profile so later reconfiguration keeps using it.
+
+Examples:
+  clauderock manage models pin --slot main --version 20250929
+  clauderock manage models pin --slot heavy --version 20250514 --profile work-dev`,
+	RunE: runModelsPin,
+}
+
+var modelsUnpinCmd = &cobra.Command{
+	Use:   "unpin",
+	Short: "Remove a snapshot pin and resolve to the newest version",
+	Long: `Remove a snapshot pin from a model slot and re-resolve it to the newest snapshot.
+
+Examples:
+  clauderock manage models unpin --slot main`,
+	RunE: runModelsUnpin,
+}
+
+func init() {
+	// Registered by models.go
+	modelsCmd.AddCommand(modelsPinCmd)
+	modelsCmd.AddCommand(modelsUnpinCmd)
+
+	modelsPinCmd.Flags().StringVar(&pinSlot, "slot", "", "Model slot to pin (main, fast, heavy)")
+	modelsPinCmd.Flags().StringVar(&pinVersion, "version", "", "Dated snapshot to pin (e.g., 20250929)")
+	modelsPinCmd.Flags().StringVar(&pinProfile, "profile", "", "Profile to update (defaults to current)")
+
+	modelsUnpinCmd.Flags().StringVar(&pinSlot, "slot", "", "Model slot to unpin (main, fast, heavy)")
+	modelsUnpinCmd.Flags().StringVar(&pinProfile, "profile", "", "Profile to update (defaults to current)")
+}
+
+// loadTargetProfile loads the named profile (from a --profile flag), or the current profile
+func loadTargetProfile(mgr *profiles.Manager, name string) (string, *config.Config, error) {
+	if name != "" {
+		cfg, err := mgr.Load(name)
+		if err != nil {
+			return "", nil, fmt.Errorf("failed to load profile '%s': %w", name, err)
+		}
+		return name, cfg, nil
+	}
+
+	cfg, err := mgr.GetCurrentConfig(Version)
+	if err != nil {
+		return "", nil, fmt.Errorf("failed to load config: %w", err)
+	}
+	current, err := mgr.GetCurrent()
+	if err != nil {
+		return "", nil, fmt.Errorf("failed to get current profile: %w", err)
+	}
+	return current, cfg, nil
+}
+
+func runModelsPin(cmd *cobra.Command, args []string) error {
+	if pinSlot == "" || pinVersion == "" {
+		return fmt.Errorf("both --slot and --version are required")
+	}
+
+	mgr, err := profiles.NewManager()
+	if err != nil {
+		return fmt.Errorf("failed to create profile manager: %w", err)
+	}
+
+	profileName, cfg, err := loadTargetProfile(mgr, pinProfile)
+	if err != nil {
+		return err
+	}
+
+	if cfg.ProfileType != "bedrock" {
+		return fmt.Errorf("snapshot pinning is only supported for bedrock profiles")
+	}
+
+	current, err := cfg.ModelForSlot(pinSlot)
+	if err != nil {
+		return err
+	}
+	if current == "" {
+		return fmt.Errorf("no model configured for slot '%s', run: clauderock manage config models", pinSlot)
+	}
+
+	model := aws.ExtractFriendlyModelName(current)
+	fmt.Printf("Resolving %s snapshot %s...\n", model, pinVersion)
+	profileID, err := aws.ResolveModelVersion(cfg.AWSProfile(), cfg.Region, cfg.CrossRegion, model, pinVersion)
+	if err != nil {
+		return err
+	}
+
+	_, err = mgr.Update(profileName, func(cfg *config.Config) error {
+		if err := cfg.SetModelForSlot(pinSlot, profileID); err != nil {
+			return err
+		}
+		cfg.PinVersion(pinSlot, pinVersion)
+		return nil
+	})
+	if err != nil {
+		return fmt.Errorf("failed to save config: %w", err)
+	}
+
+	fmt.Printf("✓ Pinned %s model to %s (in profile '%s')\n", pinSlot, profileID, profileName)
+	return nil
+}
+
+func runModelsUnpin(cmd *cobra.Command, args []string) error {
+	if pinSlot == "" {
+		return fmt.Errorf("--slot is required")
+	}
+
+	mgr, err := profiles.NewManager()
+	if err != nil {
+		return fmt.Errorf("failed to create profile manager: %w", err)
+	}
+
+	profileName, cfg, err := loadTargetProfile(mgr, pinProfile)
+	if err != nil {
+		return err
+	}
+
+	current, err := cfg.ModelForSlot(pinSlot)
+	if err != nil {
+		return err
+	}
+
+	if cfg.PinnedVersion(pinSlot) == "" {
+		// A pin the model has moved past no longer applies; drop it so it isn't listed
+		if cfg.PinnedVersions[pinSlot] != "" {
+			_, err := mgr.Update(profileName, func(cfg *config.Config) error {
+				cfg.DropStalePins()
+				return nil
+			})
+			if err != nil {
+				return fmt.Errorf("failed to save config: %w", err)
+			}
+		}
+		fmt.Printf("Slot '%s' is not pinned in profile '%s'\n", pinSlot, profileName)
+		return nil
+	}
+
+	// Re-resolve to the newest snapshot
+	newest := ""
+	if cfg.ProfileType == "bedrock" && current != "" {
+		model := aws.ExtractFriendlyModelName(current)
+		newest, err = aws.ResolveModelToProfileID(cfg.AWSProfile(), cfg.Region, cfg.CrossRegion, model)
+		if err != nil {
+			return fmt.Errorf("failed to resolve newest snapshot: %w", err)
+		}
+		current = newest
+	}
+
+	_, err = mgr.Update(profileName, func(cfg *config.Config) error {
+		cfg.UnpinVersion(pinSlot)
+		if newest == "" {
+			return nil
+		}
+		return cfg.SetModelForSlot(pinSlot, newest)
+	})
+	if err != nil {
+		return fmt.Errorf("failed to save config: %w", err)
+	}
+
+	fmt.Printf("✓ Unpinned %s model, now using %s (in profile '%s')\n", pinSlot, current, profileName)
+	return nil
+}
@@ -0,0 +1,200 @@
+package cmd
+
+import (
+	"fmt"
+	"slices"
+
+	"github.com/OlaHulleberg/clauderock/internal/aws"
+	"github.com/OlaHulleberg/clauderock/internal/config"
+	"github.com/OlaHulleberg/clauderock/internal/interactive"
+	"github.com/OlaHulleberg/clauderock/internal/profiles"
+	"github.com/spf13/cobra"
+)
+
+var (
+	remapProfile string
+	remapAuto    bool
+)
+
+var modelsRemapCmd = &cobra.Command{
+	Use:   "remap",
+	Short: "Move model slots off retired models",
+	Long: `Check the profile's inference profile IDs against Bedrock and move each slot whose
+model was retired to the closest newer version of the same model family
+(e.g., claude-sonnet-4 to claude-sonnet-4-5). Pinned slots stay pinned to the new snapshot.
+
+clauderock runs the same check at launch using the cached model list, and again
+when model validation fails; pass --clauderock-auto-remap to apply it there without asking.
+
+Examples:
+  clauderock manage models remap
+  clauderock manage models remap --profile work-dev --auto-remap`,
+	Args: cobra.NoArgs,
+	RunE: runModelsRemap,
+}
+
+func init() {
+	// Registered by models.go
+	modelsCmd.AddCommand(modelsRemapCmd)
+
+	modelsRemapCmd.Flags().StringVar(&remapProfile, "profile", "", "Profile to update (defaults to current)")
+	modelsRemapCmd.Flags().BoolVar(&remapAuto, "auto-remap", false, "Apply the remaps without asking")
+}
+
+func runModelsRemap(cmd *cobra.Command, args []string) error {
+	mgr, err := profiles.NewManager()
+	if err != nil {
+		return fmt.Errorf("failed to create profile manager: %w", err)
+	}
+
+	profileName, cfg, err := loadTargetProfile(mgr, remapProfile)
+	if err != nil {
+		return err
+	}
+	if cfg.ProfileType != "bedrock" {
+		return fmt.Errorf("model remapping is only supported for bedrock profiles")
+	}
+
+	fmt.Println("Checking models against Bedrock...")
+	remaps, err := aws.FindRetiredModels(cfg, true)
+	if err != nil {
+		return fmt.Errorf("failed to check models: %w", err)
+	}
+	if len(remaps) == 0 {
+		fmt.Printf("✓ All models of profile '%s' are available\n", profileName)
+		return nil
+	}
+
+	if !remapAuto && !stdinIsTerminal() {
+		printRemaps(remaps)
+		return fmt.Errorf("pass --auto-remap to apply the remaps without a terminal")
+	}
+	return applyRemaps(mgr, profileName, cfg, remaps, remapAuto)
+}
+
+// remapRetiredModels offers to move the launching profile off retired models found in the
+// cached model list, so the launch doesn't fail validation. auto applies the remaps without asking
+func remapRetiredModels(mgr *profiles.Manager, cfg *config.Config, auto bool) {
+	if cfg.ProfileType != "bedrock" {
+		return
+	}
+
+	remaps, err := aws.FindRetiredModels(cfg, false)
+	if err != nil || len(remaps) == 0 {
+		return
+	}
+
+	profileName := clauderockProfileFlag
+	if profileName == "" {
+		if profileName, err = mgr.GetCurrent(); err != nil {
+			return
+		}
+	}
+
+	// Without a terminal to ask on, say what would change and let validation report the failure
+	if !auto && !stdinIsTerminal() {
+		fmt.Printf("Warning: profile '%s' uses retired models:\n", profileName)
+		printRemaps(remaps)
+		fmt.Println("Run with --clauderock-auto-remap to move them to newer versions.")
+		fmt.Println()
+		return
+	}
+
+	if err := applyRemaps(mgr, profileName, cfg, remaps, auto); err != nil {
+		fmt.Printf("Warning: %v\n", err)
+	}
+	fmt.Println()
+}
+
+// offerValidationRemap offers, after validation failed on retired models, to move the profile's
+// slots that still hold them to the closest newer versions, so the next launch succeeds
+// Slots set for this run by override flags aren't part of the profile and are left alone
+func offerValidationRemap(mgr *profiles.Manager, profileName string, remaps []aws.Remap, auto bool) {
+	cfg, err := mgr.Load(profileName)
+	if err != nil {
+		return
+	}
+	var stored []aws.Remap
+	for _, r := range remaps {
+		if id, _ := cfg.ModelForSlot(r.Slot); id == r.From {
+			stored = append(stored, r)
+		}
+	}
+
+	fmt.Println()
+	if len(stored) == 0 {
+		fmt.Println("Retired models given by override flags:")
+		printRemaps(remaps)
+		return
+	}
+	if !auto && !stdinIsTerminal() {
+		fmt.Printf("Profile '%s' uses retired models:\n", profileName)
+		printRemaps(stored)
+		fmt.Println("Run with --clauderock-auto-remap to move them to newer versions.")
+		return
+	}
+	if err := applyRemaps(mgr, profileName, cfg, stored, auto); err != nil {
+		fmt.Printf("Warning: %v\n", err)
+		return
+	}
+	fmt.Println("Run clauderock again to launch with the remapped models.")
+}
+
+// applyRemaps asks to apply the remaps (unless auto), then saves them to the profile
+func applyRemaps(mgr *profiles.Manager, profileName string, cfg *config.Config, remaps []aws.Remap, auto bool) error {
+	if !slices.ContainsFunc(remaps, func(r aws.Remap) bool { return r.To != "" }) {
+		printRemaps(remaps)
+		return fmt.Errorf("no newer versions of the retired models are available; pick models with: clauderock manage config models")
+	}
+
+	if !auto {
+		confirmed, err := interactive.Confirm(
+			"Retired models",
+			fmt.Sprintf("Move profile '%s' to the closest newer versions?", profileName),
+			remapDetails(remaps),
+		)
+		if err != nil {
+			return err
+		}
+		if !confirmed {
+			fmt.Println("Models not changed")
+			return nil
+		}
+	}
+
+	aws.ApplyRemaps(cfg, remaps)
+	_, err := mgr.Update(profileName, func(stored *config.Config) error {
+		aws.ApplyRemaps(stored, remaps)
+		return nil
+	})
+	if err != nil {
+		return fmt.Errorf("failed to save remapped models: %w", err)
+	}
+	for _, r := range remaps {
+		if r.To == "" {
+			fmt.Printf("✗ No newer version of %s for the %s model; pick one with: clauderock manage config models\n", r.From, r.Slot)
+			continue
+		}
+		fmt.Printf("✓ Remapped %s model: %s → %s\n", r.Slot, r.From, r.To)
+	}
+	return nil
+}
+
+// remapDetails describes each remap on one line
+func remapDetails(remaps []aws.Remap) []string {
+	details := make([]string, len(remaps))
+	for i, r := range remaps {
+		to := r.To
+		if to == "" {
+			to = "(no newer version)"
+		}
+		details[i] = fmt.Sprintf("%s: %s → %s", r.Slot, r.From, to)
+	}
+	return details
+}
+
+func printRemaps(remaps []aws.Remap) {
+	for _, line := range remapDetails(remaps) {
+		fmt.Printf("  %s\n", line)
+	}
+}
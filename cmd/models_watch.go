@@ -0,0 +1,129 @@
+package cmd
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/OlaHulleberg/clauderock/internal/aws"
+	"github.com/OlaHulleberg/clauderock/internal/config"
+	"github.com/OlaHulleberg/clauderock/internal/profiles"
+	"github.com/spf13/cobra"
+)
+
+var (
+	watchModel       string
+	watchProfile     string
+	watchRegion      string
+	watchCrossRegion string
+	watchInterval    time.Duration
+	watchMaxInterval time.Duration
+	watchTimeout     time.Duration
+)
+
+var modelsWatchCmd = &cobra.Command{
+	Use:   "watch",
+	Short: "Wait for a model to become available in your region",
+	Long: `Poll AWS Bedrock until a model or inference profile appears in the configured
+region, then notify (Bedrock only).
+
+Useful right after a new model launch, before it has rolled out everywhere.
+Polling starts at --interval and doubles after every miss, up to --max-interval.
+
+Examples:
+  clauderock manage models watch --model anthropic.claude-opus-4-5
+  clauderock manage models watch --model global.anthropic.claude-opus-4-5-20251101-v1:0
+  clauderock manage models watch --model anthropic.claude-opus-4-5 --region eu-west-1 --cross-region eu
+  clauderock manage models watch --model anthropic.claude-opus-4-5 --timeout 24h`,
+	RunE: runModelsWatch,
+}
+
+func init() {
+	// Registered by models.go
+	modelsCmd.AddCommand(modelsWatchCmd)
+
+	modelsWatchCmd.Flags().StringVar(&watchModel, "model", "", "Model (provider.model-name) or full profile ID to wait for")
+	modelsWatchCmd.Flags().StringVar(&watchProfile, "profile", "", "Use settings from a specific profile")
+	modelsWatchCmd.Flags().StringVar(&watchRegion, "region", "", "Override AWS region")
+	modelsWatchCmd.Flags().StringVar(&watchCrossRegion, "cross-region", "", "Override cross-region setting (us, eu, global)")
+	modelsWatchCmd.Flags().DurationVar(&watchInterval, "interval", time.Minute, "Initial time between checks")
+	modelsWatchCmd.Flags().DurationVar(&watchMaxInterval, "max-interval", 30*time.Minute, "Longest time between checks")
+	modelsWatchCmd.Flags().DurationVar(&watchTimeout, "timeout", 0, "Give up after this long (0 waits forever)")
+}
+
+func runModelsWatch(cmd *cobra.Command, args []string) error {
+	if watchModel == "" {
+		return fmt.Errorf("--model is required")
+	}
+	if watchInterval <= 0 {
+		return fmt.Errorf("--interval must be positive")
+	}
+	if watchMaxInterval < watchInterval {
+		watchMaxInterval = watchInterval
+	}
+
+	mgr, err := profiles.NewManager()
+	if err != nil {
+		return fmt.Errorf("failed to create profile manager: %w", err)
+	}
+
+	var cfg *config.Config
+	profileName := watchProfile
+	if profileName != "" {
+		cfg, err = mgr.Load(profileName)
+		if err != nil {
+			return fmt.Errorf("failed to load profile '%s': %w", profileName, err)
+		}
+	} else {
+		cfg, err = mgr.GetCurrentConfig(Version)
+		if err != nil {
+			return fmt.Errorf("failed to load config: %w", err)
+		}
+		profileName, err = mgr.GetCurrent()
+		if err != nil {
+			return fmt.Errorf("failed to get current profile: %w", err)
+		}
+	}
+	if cfg.ProfileType != "bedrock" {
+		return fmt.Errorf("profile '%s' is not a bedrock profile; models watch only works with AWS Bedrock", profileName)
+	}
+
+	region := cfg.Region
+	if watchRegion != "" {
+		region = watchRegion
+	}
+	crossRegion := cfg.CrossRegion
+	if watchCrossRegion != "" {
+		crossRegion = watchCrossRegion
+	}
+
+	fmt.Printf("Watching for %s in %s (%s cross-region)...\n", watchModel, region, crossRegion)
+
+	start := time.Now()
+	interval := watchInterval
+	for attempt := 1; ; attempt++ {
+		profileID, err := aws.FindModelProfile(cfg.AWSProfile(), region, crossRegion, watchModel)
+		now := time.Now()
+		switch {
+		case err != nil:
+			// Keep watching through transient failures, but surface them
+			fmt.Printf("  [%s] check %d failed: %v\n", now.Format("15:04:05"), attempt, err)
+		case profileID != "":
+			// The bell gets attention in a background terminal tab
+			fmt.Printf("\a✓ %s is available in %s as %s\n", watchModel, region, profileID)
+			fmt.Println("  Use it with: clauderock manage config models")
+			return nil
+		default:
+			fmt.Printf("  [%s] not available yet, checking again in %s\n", now.Format("15:04:05"), interval)
+		}
+
+		if watchTimeout > 0 && now.Add(interval).Sub(start) > watchTimeout {
+			return fmt.Errorf("%s did not become available in %s within %s", watchModel, region, watchTimeout)
+		}
+		time.Sleep(interval)
+
+		interval *= 2
+		if interval > watchMaxInterval {
+			interval = watchMaxInterval
+		}
+	}
+}
@@ -0,0 +1,128 @@
+package cmd
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/OlaHulleberg/clauderock/internal/plugins"
+	"github.com/spf13/cobra"
+)
+
+// pluginAnnotation marks commands provided by plugins, naming the plugin
+const pluginAnnotation = "clauderock-plugin"
+
+var pluginsCmd = &cobra.Command{
+	Use:   "plugins",
+	Short: "List installed plugins and what they add",
+	Long: `List the plugins installed in ~/.clauderock/plugins with the subcommands and
+launch hooks each one provides.
+
+A plugin is any executable in that directory (on Windows, any file with an
+extension listed in PATHEXT, such as .exe or .cmd). clauderock sends it one JSON
+request on stdin: {"protocol":1,"type":"describe"} at discovery, answered with
+a manifest on stdout:
+
+  {"name": "jira", "description": "Log session time to Jira",
+   "commands": [{"name": "jira-log", "short": "Log time manually"}],
+   "hooks": ["pre-launch", "post-launch"]}
+
+Commands appear as 'clauderock manage <name>' and receive
+{"type":"command","command":...,"args":[...]}, writing to the terminal directly.
+Hooks receive {"type":"hook","hook":...,"launch":{...}} describing the session,
+and may answer {"env":{...},"message":"..."}; env only applies to pre-launch,
+and variables that would override the profile are dropped, as inherited ones are.
+A failing hook is reported and never blocks a launch.
+
+Manifests are cached until a plugin's file changes.`,
+	Args: cobra.NoArgs,
+	RunE: runPlugins,
+}
+
+func runPlugins(cmd *cobra.Command, args []string) error {
+	dir, err := plugins.Dir()
+	if err != nil {
+		return err
+	}
+
+	found, problems := plugins.Discover()
+	if len(found) == 0 && len(problems) == 0 {
+		fmt.Printf("No plugins installed in %s\n", dir)
+		return nil
+	}
+
+	for _, p := range found {
+		fmt.Printf("%s (%s)\n", p.Name, p.Path)
+		if p.Description != "" {
+			fmt.Printf("  %s\n", p.Description)
+		}
+		for _, c := range p.Commands {
+			fmt.Printf("  command: clauderock manage %s", c.Name)
+			if reason := pluginCommandSkipped(p, c); reason != "" {
+				fmt.Printf(" (not registered: %s)", reason)
+			}
+			fmt.Println()
+		}
+		if len(p.Hooks) > 0 {
+			fmt.Printf("  hooks:   %s\n", strings.Join(p.Hooks, ", "))
+		}
+	}
+
+	if len(problems) > 0 {
+		fmt.Println()
+		for _, problem := range problems {
+			fmt.Printf("Warning: %v\n", problem)
+		}
+	}
+	return nil
+}
+
+// registerPluginCommands adds the subcommands plugins provide under manage
+// Built-in commands always win, as does the first plugin (by name) to claim a name
+func registerPluginCommands() {
+	found, _ := plugins.Discover()
+	for _, p := range found {
+		for _, c := range p.Commands {
+			if pluginCommandSkipped(p, c) != "" {
+				continue
+			}
+			manageCmd.AddCommand(pluginCommand(p, c))
+		}
+	}
+}
+
+// pluginCommandSkipped explains why a plugin command isn't registered, or returns ""
+func pluginCommandSkipped(p plugins.Plugin, c plugins.Command) string {
+	if c.Name == "" || strings.ContainsAny(c.Name, " \t") {
+		return "invalid name"
+	}
+	for _, existing := range manageCmd.Commands() {
+		if existing.Name() != c.Name && !existing.HasAlias(c.Name) {
+			continue
+		}
+		owner := existing.Annotations[pluginAnnotation]
+		switch {
+		case owner == "":
+			return "a built-in command has this name"
+		case owner != p.Name:
+			return fmt.Sprintf("plugin %s already provides it", owner)
+		}
+	}
+	return ""
+}
+
+func pluginCommand(p plugins.Plugin, c plugins.Command) *cobra.Command {
+	short := c.Short
+	if short == "" {
+		short = p.Description
+	}
+	return &cobra.Command{
+		Use:                c.Name,
+		Short:              fmt.Sprintf("%s (plugin %s)", short, p.Name),
+		DisableFlagParsing: true,
+		Annotations:        map[string]string{pluginAnnotation: p.Name},
+		RunE: func(cmd *cobra.Command, args []string) error {
+			cmd.SilenceUsage = true
+			return plugins.RunCommand(p, c.Name, args)
+		},
+	}
+}
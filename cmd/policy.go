@@ -0,0 +1,94 @@
+package cmd
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/OlaHulleberg/clauderock/internal/policy"
+	"github.com/OlaHulleberg/clauderock/internal/profiles"
+	"github.com/spf13/cobra"
+)
+
+var policyCmd = &cobra.Command{
+	Use:   "policy",
+	Short: "Show the organisation policy and check profiles against it",
+	Long: `Show the organisation policy and check every profile against it.
+
+The policy is read from /etc/clauderock/policy.json, or, when that doesn't
+exist, fetched from the HTTPS URL in /etc/clauderock/policy-url each time
+clauderock runs. CLAUDEROCK_POLICY can select another file in
+/etc/clauderock, but nothing outside that directory, so users can't replace
+or loosen the policy. A remote policy that can't be fetched blocks launching
+rather than falling back to a copy users could edit.
+
+The policy can restrict profile types, regions (the AWS region of Bedrock
+profiles and the Vertex AI region of Vertex profiles, so list both kinds,
+e.g. "eu-*" and "europe-*"), providers, and models; profiles that violate it
+cannot be saved or launched. Setting "kiosk": true
+(or CLAUDEROCK_KIOSK=1) leaves only launching with the provisioned profiles
+and read-only commands such as stats; every other command, plugins, and
+launch hooks are disabled. Example:
+
+  {
+    "allowed-profile-types": ["bedrock"],
+    "allowed-regions": ["eu-*"],
+    "allowed-providers": ["anthropic"],
+    "allowed-models": ["anthropic.claude-sonnet-*", "anthropic.claude-haiku-*"]
+  }`,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		p, err := policy.Load()
+		if err != nil {
+			return err
+		}
+		if p == nil {
+			fmt.Printf("No organisation policy installed (%s or %s)\n", policy.DefaultPath, policy.URLPath)
+			return nil
+		}
+
+		fmt.Printf("Policy: %s\n", p.Source)
+		printPolicyRule("Profile types", p.AllowedProfileTypes)
+		printPolicyRule("Regions", p.AllowedRegions)
+		printPolicyRule("Providers", p.AllowedProviders)
+		printPolicyRule("Models", p.AllowedModels)
+		if p.Kiosk {
+			fmt.Printf("  %-14s %s\n", "Kiosk mode:", "on")
+		}
+		fmt.Println()
+
+		mgr, err := profiles.NewManager()
+		if err != nil {
+			return fmt.Errorf("failed to create profile manager: %w", err)
+		}
+		names, err := mgr.List()
+		if err != nil {
+			return err
+		}
+
+		for _, name := range names {
+			cfg, err := mgr.Load(name)
+			if err != nil {
+				fmt.Printf("  ? %s: %v\n", name, err)
+				continue
+			}
+			violations := p.Check(cfg)
+			if len(violations) == 0 {
+				fmt.Printf("  ✓ %s\n", name)
+				continue
+			}
+			fmt.Printf("  ✗ %s\n", name)
+			for _, v := range violations {
+				fmt.Printf("      %s\n", v)
+			}
+		}
+		return nil
+	},
+}
+
+// printPolicyRule prints one allow-list of the policy
+func printPolicyRule(label string, allowed []string) {
+	value := "any"
+	if len(allowed) > 0 {
+		value = strings.Join(allowed, ", ")
+	}
+	fmt.Printf("  %-14s %s\n", label+":", value)
+}
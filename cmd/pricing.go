@@ -0,0 +1,221 @@
+package cmd
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	"github.com/OlaHulleberg/clauderock/internal/currency"
+	"github.com/OlaHulleberg/clauderock/internal/pricing"
+	"github.com/OlaHulleberg/clauderock/internal/profiles"
+	"github.com/spf13/cobra"
+)
+
+// pricingFetchTimeout bounds a price list download
+const pricingFetchTimeout = 30 * time.Second
+
+// defaultPricingRegion is used when the current profile isn't a Bedrock profile
+const defaultPricingRegion = "us-east-1"
+
+var (
+	pricingRegion   string
+	pricingProvider string
+	pricingJSON     bool
+)
+
+var pricingCmd = &cobra.Command{
+	Use:   "pricing",
+	Short: "Show and refresh the model prices used for cost estimates",
+	Long: `Show and refresh the model prices used for cost estimates.
+
+Prices come from the AWS Price List API and are cached per region in
+~/.clauderock/pricing-cache.json for a week. Sessions are priced for the region
+they ran in; regions that were never fetched use the first region's prices.
+Models the price list doesn't cover use clauderock's built-in prices.
+Stale prices are refreshed in the background while a session runs.`,
+	RunE: runPricingShow,
+}
+
+var pricingRefreshCmd = &cobra.Command{
+	Use:   "refresh",
+	Short: "Fetch current Bedrock prices now",
+	Long: `Fetch current Bedrock prices for a region from the AWS Price List API,
+replacing that region's cached prices.
+
+Examples:
+  clauderock manage pricing refresh
+  clauderock manage pricing refresh --region eu-west-1`,
+	RunE: runPricingRefresh,
+}
+
+var pricingListCmd = &cobra.Command{
+	Use:   "list",
+	Short: "List the prices cost estimates use, with where each comes from",
+	Long: `List the prices cost estimates use, with where each comes from: clauderock's
+built-in prices, or the AWS Price List for a region and when it was fetched.
+
+Examples:
+  clauderock manage pricing list
+  clauderock manage pricing list --provider anthropic --region eu-west-1
+  clauderock manage pricing list --json`,
+	Args: cobra.NoArgs,
+	RunE: runPricingList,
+}
+
+func init() {
+	// Registered by manage.go
+	pricingCmd.AddCommand(pricingRefreshCmd)
+	pricingCmd.AddCommand(pricingListCmd)
+
+	pricingCmd.Flags().StringVar(&pricingRegion, "region", "", "Show the prices used for sessions in a region")
+	pricingRefreshCmd.Flags().StringVar(&pricingRegion, "region", "", "Region to fetch prices for (defaults to the current profile's region)")
+	pricingListCmd.Flags().StringVar(&pricingRegion, "region", "", "List the prices used for sessions in a region")
+	pricingListCmd.Flags().StringVar(&pricingProvider, "provider", "", "Filter by provider (e.g., anthropic, meta, amazon)")
+	pricingListCmd.Flags().BoolVar(&pricingJSON, "json", false, "Output as JSON")
+}
+
+// currentPricingRegion returns the current Bedrock profile's region, or defaultPricingRegion
+func currentPricingRegion() string {
+	mgr, err := profiles.NewManager()
+	if err != nil {
+		return defaultPricingRegion
+	}
+	cfg, err := mgr.GetCurrentConfig(Version)
+	if err != nil || cfg.ProfileType != "bedrock" || cfg.Region == "" {
+		return defaultPricingRegion
+	}
+	return cfg.Region
+}
+
+// refreshStaleCaches refreshes cached prices and exchange rates past their TTL, prices for
+// the current profile's region and every region fetched before, under one deadline
+// Failures only mean the previous or built-in prices are used, and costs stay in USD
+// without a rate
+func refreshStaleCaches() {
+	ctx, cancel := context.WithTimeout(context.Background(), pricingFetchTimeout)
+	defer cancel()
+
+	regions := []string{currentPricingRegion()}
+	if cache, err := pricing.LoadCache(); err == nil && cache != nil {
+		for region := range cache.Regions {
+			if region != regions[0] {
+				regions = append(regions, region)
+			}
+		}
+	}
+	for _, region := range regions {
+		_ = pricing.RefreshIfStale(ctx, pricing.AWSPriceList{}, region)
+	}
+	_ = currency.RefreshRatesIfStale(ctx)
+}
+
+func runPricingShow(cmd *cobra.Command, args []string) error {
+	cache, err := pricing.LoadCache()
+	if err != nil {
+		return err
+	}
+	switch {
+	case cache == nil || cache.Updated.IsZero():
+		fmt.Println("Source: built-in prices (run 'clauderock manage pricing refresh' to fetch current prices)")
+	default:
+		fmt.Printf("Source: %s (%s), fetched %s\n", cache.Source, cache.Region, cache.Updated.Format("2006-01-02 15:04"))
+		if time.Since(cache.Updated) > pricing.CacheTTL {
+			fmt.Println("        Prices are stale; refresh with: clauderock manage pricing refresh")
+		}
+	}
+	if cache != nil && len(cache.Regions) > 0 {
+		regions := make([]string, 0, len(cache.Regions))
+		for region := range cache.Regions {
+			regions = append(regions, region)
+		}
+		sort.Strings(regions)
+		fmt.Printf("Regional prices: %s (show one with --region)\n", strings.Join(regions, ", "))
+	}
+	fmt.Println()
+
+	if pricingRegion != "" {
+		fmt.Printf("Prices for %s:\n", pricingRegion)
+	}
+	prices := pricing.PricesInRegion(pricingRegion)
+	keys := make([]string, 0, len(prices))
+	for key := range prices {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, "MODEL\tINPUT (per 1M)\tOUTPUT (per 1M)")
+	for _, key := range keys {
+		fmt.Fprintf(w, "%s\t$%.2f\t$%.2f\n", key, prices[key].InputCost, prices[key].OutputCost)
+	}
+	return w.Flush()
+}
+
+func runPricingRefresh(cmd *cobra.Command, args []string) error {
+	region := pricingRegion
+	if region == "" {
+		region = currentPricingRegion()
+	}
+
+	fmt.Printf("Fetching Bedrock prices for %s...\n", region)
+	ctx, cancel := context.WithTimeout(context.Background(), pricingFetchTimeout)
+	defer cancel()
+
+	cache, err := pricing.Refresh(ctx, pricing.AWSPriceList{}, region)
+	if err != nil {
+		return fmt.Errorf("failed to refresh pricing: %w", err)
+	}
+
+	fmt.Printf("✓ Cached prices for %d models in %s\n", len(cache.Regions[region].Prices), region)
+	return nil
+}
+
+func runPricingList(cmd *cobra.Command, args []string) error {
+	table, err := pricing.PriceTable(pricingRegion)
+	if err != nil {
+		return err
+	}
+	if pricingProvider != "" {
+		filtered := table[:0]
+		for _, entry := range table {
+			if strings.EqualFold(entry.Provider, pricingProvider) {
+				filtered = append(filtered, entry)
+			}
+		}
+		table = filtered
+	}
+
+	if pricingJSON {
+		encoder := json.NewEncoder(os.Stdout)
+		encoder.SetIndent("", "  ")
+		return encoder.Encode(table)
+	}
+
+	if len(table) == 0 {
+		fmt.Println("No prices found matching the criteria.")
+		return nil
+	}
+
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, "MODEL\tINPUT (per 1M)\tOUTPUT (per 1M)\tSOURCE")
+	for _, entry := range table {
+		source := entry.Source
+		if entry.Region != "" {
+			source = fmt.Sprintf("%s %s, fetched %s", entry.Source, entry.Region, entry.Fetched.Format("2006-01-02"))
+		}
+		fmt.Fprintf(w, "%s\t$%.2f\t$%.2f\t%s\n", entry.Key, entry.InputCost, entry.OutputCost, source)
+	}
+	if err := w.Flush(); err != nil {
+		return err
+	}
+
+	fmt.Printf("\nCache writes cost %.0f%% and cache reads %.0f%% of the input price.\n",
+		pricing.CacheWriteMultiplier*100, pricing.CacheReadMultiplier*100)
+	fmt.Println("Models not listed are priced like the newest listed model of their family, shown as approximate in stats.")
+	return nil
+}
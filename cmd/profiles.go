@@ -2,14 +2,19 @@ package cmd
 
 import (
 	"fmt"
+	"time"
 
+	"github.com/OlaHulleberg/clauderock/internal/currency"
 	"github.com/OlaHulleberg/clauderock/internal/profiles"
+	"github.com/OlaHulleberg/clauderock/internal/usage"
 	"github.com/spf13/cobra"
 )
 
 var profilesCmd = &cobra.Command{
 	Use:   "profiles",
 	Short: "List all available profiles",
+	Long: `List all available profiles, with each one's sessions and estimated cost
+this month from the usage database.`,
 	RunE: func(cmd *cobra.Command, args []string) error {
 		mgr, err := profiles.NewManager()
 		if err != nil {
@@ -31,19 +36,70 @@ var profilesCmd = &cobra.Command{
 			return nil
 		}
 
+		activeLabel := "(active)"
+		if mgr.IsCurrentFromEnv() {
+			activeLabel = fmt.Sprintf("(active via %s)", profiles.CurrentProfileEnvVar)
+		}
+
+		// Usage is a summary on the side; without a readable database the list is still shown
+		monthToDate := profileMonthToDate()
+
+		width := 0
+		for _, name := range profileList {
+			label := name
+			if name == current {
+				label += " " + activeLabel
+			}
+			width = max(width, len(label))
+		}
+
 		fmt.Println("Available profiles:")
+		var totalSessions int
+		var totalCost float64
 		for _, name := range profileList {
+			marker, label := " ", name
 			if name == current {
-				fmt.Printf("  * %s (active)\n", name)
-			} else {
-				fmt.Printf("    %s\n", name)
+				marker, label = "*", name+" "+activeLabel
+			}
+			if monthToDate == nil {
+				fmt.Printf("  %s %s\n", marker, label)
+				continue
 			}
+			spend := monthToDate[name]
+			totalSessions += spend.Sessions
+			totalCost += spend.Cost
+			fmt.Printf("  %s %-*s  %s\n", marker, width, label,
+				mutedStyle.Render(fmt.Sprintf("%3d sessions  %10s", spend.Sessions, currency.Format(spend.Cost))))
+		}
+		if monthToDate != nil {
+			fmt.Println()
+			fmt.Printf("  %s %s\n",
+				labelStyle.Render(fmt.Sprintf("This month: %d sessions,", totalSessions)),
+				costStyle.Render(currency.Format(totalCost)))
+			fmt.Println(mutedStyle.Render("  By day and model: clauderock manage stats cost"))
 		}
 
 		return nil
 	},
 }
 
+// profileMonthToDate returns each profile's sessions and estimated cost this month, or nil
+// when the usage database can't be read
+// Costs use the cached prices and exchange rates only, so listing profiles never waits on the network
+func profileMonthToDate() map[string]usage.GroupCost {
+	tracker, err := usage.NewTracker()
+	if err != nil {
+		return nil
+	}
+	defer tracker.Close()
+
+	monthToDate, err := tracker.MonthToDateByProfile(time.Now())
+	if err != nil {
+		return nil
+	}
+	return monthToDate
+}
+
 var profileSaveCmd = &cobra.Command{
 	Use:   "save",
 	Short: "Save current configuration as a named profile",
@@ -73,8 +129,8 @@ Example:
 			return fmt.Errorf("profile '%s' already exists, use 'config delete' first or choose a different name", profileName)
 		}
 
-		// Save as new profile
-		if err := mgr.Save(profileName, cfg); err != nil {
+		// Save as new profile; SaveAs checks the name again under the profile lock
+		if err := mgr.SaveAs(profileName, cfg); err != nil {
 			return fmt.Errorf("failed to save profile: %w", err)
 		}
 
@@ -173,24 +229,41 @@ var profileSwitchCmd = &cobra.Command{
 	Short: "Switch to a different profile",
 	Long: `Switch the active profile.
 
-Example:
-  clauderock config switch --name work-dev`,
+With --session, only the current shell is switched: the command prints an
+export of CLAUDEROCK_PROFILE to evaluate, leaving other terminals untouched.
+
+Examples:
+  clauderock config switch --name work-dev
+  eval "$(clauderock config switch --name work-dev --session)"`,
 	RunE: func(cmd *cobra.Command, args []string) error {
 		profileName, _ := cmd.Flags().GetString("name")
 		if profileName == "" {
 			return fmt.Errorf("profile name is required (use --name)")
 		}
+		session, _ := cmd.Flags().GetBool("session")
 
 		mgr, err := profiles.NewManager()
 		if err != nil {
 			return fmt.Errorf("failed to create profile manager: %w", err)
 		}
 
+		if session {
+			if !mgr.Exists(profileName) {
+				return fmt.Errorf("profile '%s' does not exist", profileName)
+			}
+			fmt.Printf("export %s=%s\n", profiles.CurrentProfileEnvVar, shellJoin([]string{profileName}))
+			return nil
+		}
+
 		if err := mgr.SetCurrent(profileName); err != nil {
 			return err
 		}
 
 		fmt.Printf("Switched to profile '%s'\n", profileName)
+		if mgr.IsCurrentFromEnv() {
+			fmt.Printf("Note: %s is set in this shell and takes precedence; unset it to use '%s' here\n",
+				profiles.CurrentProfileEnvVar, profileName)
+		}
 		return nil
 	},
 }
@@ -215,5 +288,6 @@ func init() {
 	configCmd.AddCommand(profileCopyCmd)
 
 	profileSwitchCmd.Flags().String("name", "", "Name of the profile to switch to")
+	profileSwitchCmd.Flags().Bool("session", false, "Print an export of CLAUDEROCK_PROFILE for this shell instead of switching globally")
 	configCmd.AddCommand(profileSwitchCmd)
 }
@@ -0,0 +1,302 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/OlaHulleberg/clauderock/internal/aws"
+	"github.com/OlaHulleberg/clauderock/internal/config"
+	"github.com/OlaHulleberg/clauderock/internal/keyring"
+	"github.com/OlaHulleberg/clauderock/internal/pricing"
+	"github.com/OlaHulleberg/clauderock/internal/profiles"
+	"github.com/OlaHulleberg/clauderock/internal/vertex"
+	"github.com/spf13/cobra"
+)
+
+var profileShowJSON bool
+
+// profileShowOutput is the resolved view of a profile used by --json
+type profileShowOutput struct {
+	Name             string                      `json:"name"`
+	Active           bool                        `json:"active"`
+	ProfileType      string                      `json:"profile-type"`
+	Version          string                      `json:"version,omitempty"`
+	AWSProfile       string                      `json:"aws-profile,omitempty"`
+	Credentials      string                      `json:"credentials-source,omitempty"`
+	Region           string                      `json:"region,omitempty"`
+	CrossRegion      string                      `json:"cross-region,omitempty"`
+	BaseURL          string                      `json:"base-url,omitempty"`
+	APIKey           string                      `json:"api-key,omitempty"` // Masked
+	GCPProject       string                      `json:"gcp-project,omitempty"`
+	VertexRegion     string                      `json:"vertex-region,omitempty"`
+	Models           map[string]profileShowModel `json:"models"`
+	Unverified       bool                        `json:"unverified,omitempty"` // Models came from the offline catalog
+	EnvPolicy        string                      `json:"env-policy"`
+	IntegrationMode  string                      `json:"integration-mode"`
+	AllowedDirs      []string                    `json:"allowed-dirs,omitempty"`
+	DirPolicy        string                      `json:"dir-policy,omitempty"`
+	Budget           *profileShowBudget          `json:"budget,omitempty"`
+	AutoUpgrade      string                      `json:"auto-upgrade"`
+	AutoUpgradeMode  string                      `json:"auto-upgrade-mode,omitempty"`
+	PricingTier      string                      `json:"pricing-tier"`
+	ProvisionedUSD   float64                     `json:"provisioned-hourly-usd,omitempty"`
+	ProvisionedSince string                      `json:"provisioned-since,omitempty"`
+	Encrypted        bool                        `json:"encrypted,omitempty"`
+	Valid            bool                        `json:"valid"`
+	ValidationError  string                      `json:"validation-error,omitempty"`
+}
+
+// profileShowBudget describes the profile's spending limits
+type profileShowBudget struct {
+	DailyUSD   float64 `json:"daily-usd,omitempty"`
+	MonthlyUSD float64 `json:"monthly-usd,omitempty"`
+	WarnAt     []int   `json:"warn-at"`
+	Policy     string  `json:"policy"`
+}
+
+// profileShowModel describes the model configured for one slot
+type profileShowModel struct {
+	Name          string `json:"name"`                     // Friendly name, e.g. anthropic.claude-sonnet-4-5
+	ID            string `json:"id"`                       // Configured (resolved) ID passed to Claude
+	PinnedVersion string `json:"pinned-version,omitempty"` // Dated snapshot pin (bedrock only)
+}
+
+var profileShowCmd = &cobra.Command{
+	Use:   "show [name]",
+	Short: "Show a profile's resolved configuration",
+	Long: `Show a profile's resolved configuration, including friendly model names,
+resolved model IDs, and validation status. Secrets are masked.
+
+Defaults to the active profile. Use --json for scripts and status bars.
+
+Example:
+  clauderock manage profiles show work --json`,
+	Args: cobra.MaximumNArgs(1),
+	RunE: runProfileShow,
+}
+
+func init() {
+	profileShowCmd.Flags().BoolVar(&profileShowJSON, "json", false, "Output as JSON")
+	profilesCmd.AddCommand(profileShowCmd)
+}
+
+func runProfileShow(cmd *cobra.Command, args []string) error {
+	mgr, err := profiles.NewManager()
+	if err != nil {
+		return fmt.Errorf("failed to create profile manager: %w", err)
+	}
+
+	current, err := mgr.GetCurrent()
+	if err != nil {
+		return fmt.Errorf("failed to get current profile: %w", err)
+	}
+
+	name := current
+	if len(args) == 1 {
+		name = args[0]
+	}
+
+	cfg, err := mgr.Load(name)
+	if err != nil {
+		return fmt.Errorf("failed to load profile '%s': %w", name, err)
+	}
+
+	out := buildProfileShowOutput(name, name == current, cfg)
+
+	if profileShowJSON {
+		encoder := json.NewEncoder(os.Stdout)
+		encoder.SetIndent("", "  ")
+		return encoder.Encode(out)
+	}
+
+	active := ""
+	if out.Active {
+		active = " (active)"
+	}
+	fmt.Printf("Profile: %s%s\n", out.Name, active)
+	fmt.Printf("  Type:         %s\n", out.ProfileType)
+	if out.ProfileType == "bedrock" {
+		if out.Credentials != "" {
+			fmt.Printf("  Credentials:  %s\n", out.Credentials)
+		} else {
+			fmt.Printf("  AWS Profile:  %s\n", out.AWSProfile)
+		}
+		fmt.Printf("  Region:       %s\n", out.Region)
+		fmt.Printf("  Cross Region: %s\n", out.CrossRegion)
+	} else if out.ProfileType == "vertex" {
+		fmt.Printf("  GCP Project:  %s\n", out.GCPProject)
+		fmt.Printf("  Region:       %s\n", out.VertexRegion)
+	} else {
+		fmt.Printf("  Base URL:     %s\n", out.BaseURL)
+		fmt.Printf("  API Key:      %s\n", out.APIKey)
+	}
+	for _, slot := range config.ModelSlots {
+		m := out.Models[slot]
+		line := m.Name
+		if m.ID != m.Name {
+			line = fmt.Sprintf("%s (%s)", m.Name, m.ID)
+		}
+		if m.PinnedVersion != "" {
+			line += " [pinned " + m.PinnedVersion + "]"
+		}
+		fmt.Printf("  %-13s %s\n", slot+" model:", line)
+	}
+	if out.Unverified {
+		fmt.Println("  Models:       unverified (picked offline, checked on the next launch)")
+	}
+	fmt.Printf("  Env Policy:   %s\n", out.EnvPolicy)
+	fmt.Printf("  Integration:  %s\n", out.IntegrationMode)
+	if len(out.AllowedDirs) > 0 {
+		fmt.Printf("  Allowed Dirs: %s (%s elsewhere)\n", strings.Join(out.AllowedDirs, ", "), out.DirPolicy)
+	}
+	if out.Budget != nil {
+		fmt.Printf("  Budget:       %s\n", budgetSummary(cfg))
+	}
+	if out.AutoUpgradeMode != "" {
+		fmt.Printf("  Auto-Upgrade: %s (%s)\n", out.AutoUpgrade, out.AutoUpgradeMode)
+	}
+	if out.PricingTier != config.PricingTierStandard {
+		fmt.Printf("  Pricing Tier: %s\n", pricingTierSummary(cfg))
+	}
+	if out.Encrypted {
+		fmt.Println("  Storage:      encrypted keyring")
+	}
+	if out.Valid {
+		fmt.Println("  Status:       ✓ valid")
+	} else {
+		fmt.Printf("  Status:       ✗ %s\n", out.ValidationError)
+	}
+	return nil
+}
+
+// buildProfileShowOutput resolves a profile's configuration for display
+func buildProfileShowOutput(name string, active bool, cfg *config.Config) profileShowOutput {
+	out := profileShowOutput{
+		Name:            name,
+		Active:          active,
+		ProfileType:     cfg.ProfileType,
+		Version:         cfg.Version,
+		AWSProfile:      cfg.AWSProfile(),
+		Credentials:     credentialsSourceOverride(cfg),
+		Region:          cfg.Region,
+		CrossRegion:     cfg.CrossRegion,
+		BaseURL:         cfg.BaseURL,
+		GCPProject:      cfg.GCPProject,
+		VertexRegion:    cfg.VertexRegion,
+		Models:          make(map[string]profileShowModel),
+		Unverified:      cfg.Unverified,
+		EnvPolicy:       cfg.EnvPolicy,
+		IntegrationMode: cfg.IntegrationMode,
+		AllowedDirs:     cfg.AllowedDirs,
+		Encrypted:       cfg.Encrypted,
+		Valid:           true,
+	}
+	if out.EnvPolicy == "" {
+		out.EnvPolicy = config.EnvPolicyInherit
+	}
+	if len(out.AllowedDirs) > 0 {
+		out.DirPolicy, _ = cfg.Get("dir-policy")
+	}
+	if out.IntegrationMode == "" {
+		out.IntegrationMode = config.IntegrationModeEnv
+	}
+	out.PricingTier, _ = cfg.Get("pricing-tier")
+	if out.PricingTier == config.PricingTierProvisioned {
+		out.ProvisionedUSD = cfg.ProvisionedHourlyUSD
+		out.ProvisionedSince = cfg.ProvisionedSince
+	}
+	out.AutoUpgrade, _ = cfg.Get("auto-upgrade")
+	if out.AutoUpgrade != config.AutoUpgradeOff {
+		out.AutoUpgradeMode, _ = cfg.Get("auto-upgrade-mode")
+	}
+	if cfg.BudgetDailyUSD > 0 || cfg.BudgetMonthlyUSD > 0 {
+		out.Budget = &profileShowBudget{
+			DailyUSD:   cfg.BudgetDailyUSD,
+			MonthlyUSD: cfg.BudgetMonthlyUSD,
+			WarnAt:     cfg.BudgetWarnAt,
+			Policy:     cfg.BudgetPolicy,
+		}
+		if len(out.Budget.WarnAt) == 0 {
+			out.Budget.WarnAt = config.DefaultBudgetWarnAt
+		}
+		if out.Budget.Policy == "" {
+			out.Budget.Policy = config.BudgetPolicyWarn
+		}
+	}
+
+	if cfg.ProfileType == "api" && cfg.APIKeyID != "" {
+		if apiKey, err := keyring.Get(cfg.APIKeyID); err == nil {
+			out.APIKey = maskSecret(apiKey)
+		} else {
+			out.APIKey = "<unavailable>"
+		}
+	}
+
+	for _, slot := range config.ModelSlots {
+		id, _ := cfg.ModelForSlot(slot)
+		name := aws.ExtractFriendlyModelName(id)
+		if cfg.ProfileType == "vertex" {
+			name = vertex.FriendlyName(id)
+		}
+		out.Models[slot] = profileShowModel{
+			Name:          name,
+			ID:            id,
+			PinnedVersion: cfg.PinnedVersion(slot),
+		}
+	}
+
+	if err := cfg.Validate(); err != nil {
+		out.Valid = false
+		out.ValidationError = err.Error()
+	}
+
+	return out
+}
+
+// budgetSummary describes a profile's budgets on one line
+// Example: "$10/day, $200/month (warn at 80%, warn over budget)"
+func budgetSummary(cfg *config.Config) string {
+	var limits []string
+	if cfg.BudgetDailyUSD > 0 {
+		limits = append(limits, fmt.Sprintf("$%g/day", cfg.BudgetDailyUSD))
+	}
+	if cfg.BudgetMonthlyUSD > 0 {
+		limits = append(limits, fmt.Sprintf("$%g/month", cfg.BudgetMonthlyUSD))
+	}
+	warnAt, _ := cfg.Get("budget-warn-at")
+	policy, _ := cfg.Get("budget-policy")
+	return fmt.Sprintf("%s (warn at %s%%, %s over budget)", strings.Join(limits, ", "), strings.ReplaceAll(warnAt, ",", "%, "), policy)
+}
+
+// pricingTierSummary describes how a profile's usage is priced
+func pricingTierSummary(cfg *config.Config) string {
+	tier, _ := cfg.Get("pricing-tier")
+	switch {
+	case tier == config.PricingTierProvisioned && cfg.ProvisionedHourlyUSD > 0 && cfg.ProvisionedSince != "":
+		return fmt.Sprintf("%s ($%g/hour commitment since %s)", tier, cfg.ProvisionedHourlyUSD, cfg.ProvisionedSince)
+	case tier == config.PricingTierProvisioned && cfg.ProvisionedHourlyUSD > 0:
+		return fmt.Sprintf("%s ($%g/hour commitment)", tier, cfg.ProvisionedHourlyUSD)
+	case tier == config.PricingTierBatch:
+		return fmt.Sprintf("%s (%.0f%% of on-demand prices)", tier, pricing.TierMultiplier(tier)*100)
+	}
+	return tier
+}
+
+// maskSecret keeps only enough of a secret to recognise it
+// Input: "sk-ant-api03-abcdef...wxyz" → "sk-a…wxyz"
+func maskSecret(secret string) string {
+	if len(secret) <= 8 {
+		return "****"
+	}
+	return secret[:4] + "…" + secret[len(secret)-4:]
+}
+
+// credentialsSourceOverride returns the credentials source when it isn't the default shared profile
+func credentialsSourceOverride(cfg *config.Config) string {
+	if cfg.UsesSharedProfile() {
+		return ""
+	}
+	return cfg.CredentialsSource
+}
@@ -0,0 +1,281 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/OlaHulleberg/clauderock/internal/interactive"
+	"github.com/OlaHulleberg/clauderock/internal/profiles"
+	"github.com/spf13/cobra"
+	"golang.org/x/term"
+)
+
+var (
+	profileExportAll          bool
+	profileExportOutput       string
+	profileExportIncludeKeys  bool
+	profileExportNoKeys       bool
+	profileTransferPassphrase string
+	profileImportOverwrite    bool
+	profileImportYes          bool
+)
+
+var profileExportCmd = &cobra.Command{
+	Use:   "export [name...]",
+	Short: "Export profiles to a file to share or move to another machine",
+	Long: `Export profiles to a portable file. Defaults to the active profile.
+
+API keys are only included if you say so, encrypted with a passphrase you
+choose; without them, 'import' asks for each API profile's key. Bedrock
+profiles contain no secrets, only the name of the AWS profile to use.
+
+For scripts, pass --include-keys or --no-keys, and name an environment
+variable holding the passphrase with --passphrase-env.
+
+Examples:
+  clauderock manage profiles export work
+  clauderock manage profiles export --all -o team-profiles.json --no-keys
+  CLAUDEROCK_PASSPHRASE=... clauderock manage profiles export api-dev --include-keys --passphrase-env CLAUDEROCK_PASSPHRASE`,
+	RunE: runProfileExport,
+}
+
+var profileImportCmd = &cobra.Command{
+	Use:   "import <file>",
+	Short: "Import profiles from an export file",
+	Long: `Import the profiles in a file written by 'manage profiles export'.
+
+Included API keys are decrypted with the export passphrase and stored in this
+machine's keyring; API profiles exported without a key ask for one. Existing
+profiles are only replaced with --overwrite.
+
+Settings that decide where requests, usage records, or environment variables
+go (base-url, team-sync, env-allowlist) are listed for you to confirm before
+anything is saved; --yes accepts them without asking.
+
+Examples:
+  clauderock manage profiles import team-profiles.json
+  clauderock manage profiles import work.json --overwrite`,
+	Args: cobra.ExactArgs(1),
+	RunE: runProfileImport,
+}
+
+func init() {
+	profileExportCmd.Flags().BoolVar(&profileExportAll, "all", false, "Export every profile")
+	profileExportCmd.Flags().StringVarP(&profileExportOutput, "output", "o", "", "File to write (default <profile>.clauderock.json, or clauderock-profiles.json with several; - for stdout)")
+	profileExportCmd.Flags().BoolVar(&profileExportIncludeKeys, "include-keys", false, "Include API keys, encrypted with a passphrase, without asking")
+	profileExportCmd.Flags().BoolVar(&profileExportNoKeys, "no-keys", false, "Leave API keys out without asking")
+	profileExportCmd.Flags().StringVar(&profileTransferPassphrase, "passphrase-env", "", "Environment variable holding the passphrase for API keys")
+	profileExportCmd.MarkFlagsMutuallyExclusive("include-keys", "no-keys")
+	profilesCmd.AddCommand(profileExportCmd)
+
+	profileImportCmd.Flags().BoolVar(&profileImportOverwrite, "overwrite", false, "Replace profiles that already exist")
+	profileImportCmd.Flags().BoolVar(&profileImportYes, "yes", false, "Accept base URLs, team sync targets, and environment allowlists without asking")
+	profileImportCmd.Flags().StringVar(&profileTransferPassphrase, "passphrase-env", "", "Environment variable holding the passphrase for API keys")
+	profilesCmd.AddCommand(profileImportCmd)
+}
+
+// stdinIsTerminal reports whether there's a terminal to prompt on
+func stdinIsTerminal() bool {
+	return term.IsTerminal(int(os.Stdin.Fd()))
+}
+
+// transferPassphrase reads the passphrase from --passphrase-env, or prompts for it
+// When confirm is set (exporting), the passphrase is asked for twice
+func transferPassphrase(confirm bool) (string, error) {
+	if profileTransferPassphrase != "" {
+		passphrase := os.Getenv(profileTransferPassphrase)
+		if passphrase == "" {
+			return "", fmt.Errorf("%s is not set", profileTransferPassphrase)
+		}
+		return passphrase, nil
+	}
+	if !stdinIsTerminal() {
+		return "", fmt.Errorf("a passphrase is needed for API keys; use --passphrase-env when not running in a terminal")
+	}
+
+	if !confirm {
+		return interactive.PromptPassword("Export passphrase:")
+	}
+	passphrase, err := interactive.PromptPassword(fmt.Sprintf("Passphrase to encrypt API keys with (at least %d characters):", profiles.MinPassphraseLength))
+	if err != nil {
+		return "", err
+	}
+	again, err := interactive.PromptPassword("Repeat the passphrase:")
+	if err != nil {
+		return "", err
+	}
+	if passphrase != again {
+		return "", fmt.Errorf("passphrases don't match")
+	}
+	return passphrase, nil
+}
+
+func runProfileExport(cmd *cobra.Command, args []string) error {
+	mgr, err := profiles.NewManager()
+	if err != nil {
+		return fmt.Errorf("failed to create profile manager: %w", err)
+	}
+
+	names := args
+	switch {
+	case profileExportAll && len(args) > 0:
+		return fmt.Errorf("use either --all or profile names, not both")
+	case profileExportAll:
+		if names, err = mgr.List(); err != nil {
+			return fmt.Errorf("failed to list profiles: %w", err)
+		}
+	case len(names) == 0:
+		current, err := mgr.GetCurrent()
+		if err != nil {
+			return fmt.Errorf("failed to get current profile: %w", err)
+		}
+		names = []string{current}
+	}
+
+	var apiProfiles []string
+	for _, name := range names {
+		cfg, err := mgr.Load(name)
+		if err != nil {
+			return fmt.Errorf("failed to load profile '%s': %w", name, err)
+		}
+		if cfg.ProfileType == "api" && cfg.APIKeyID != "" {
+			apiProfiles = append(apiProfiles, name)
+		}
+	}
+
+	includeKeys := profileExportIncludeKeys
+	if len(apiProfiles) > 0 && !profileExportIncludeKeys && !profileExportNoKeys {
+		if !stdinIsTerminal() {
+			return fmt.Errorf("profiles with API keys: %s; pass --include-keys or --no-keys", strings.Join(apiProfiles, ", "))
+		}
+		includeKeys, err = interactive.Confirm(
+			"Include API keys?",
+			"These profiles have API keys: "+strings.Join(apiProfiles, ", "),
+			[]string{
+				"Keys are encrypted with a passphrase you choose.",
+				"Anyone with the file and the passphrase can use them.",
+				"Without them, import asks for each key instead.",
+			})
+		if err != nil {
+			return err
+		}
+	}
+
+	var passphrase string
+	if includeKeys && len(apiProfiles) > 0 {
+		if passphrase, err = transferPassphrase(true); err != nil {
+			return err
+		}
+	}
+
+	file, err := mgr.Export(names, passphrase, Version)
+	if err != nil {
+		return err
+	}
+	data, err := json.MarshalIndent(file, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to encode export: %w", err)
+	}
+
+	output := profileExportOutput
+	if output == "" {
+		output = "clauderock-profiles.json"
+		if len(names) == 1 {
+			output = names[0] + ".clauderock.json"
+		}
+	}
+	if output == "-" {
+		fmt.Println(string(data))
+		return nil
+	}
+
+	// Encrypted keys are still secrets, so keep the file private
+	perm := os.FileMode(0644)
+	if file.NeedsPassphrase() {
+		perm = 0600
+	}
+	if err := os.WriteFile(output, append(data, '\n'), perm); err != nil {
+		return fmt.Errorf("failed to write export file: %w", err)
+	}
+	// WriteFile keeps the mode of a file it overwrites
+	if err := os.Chmod(output, perm); err != nil {
+		return fmt.Errorf("failed to set export file permissions: %w", err)
+	}
+
+	keys := "without API keys"
+	if file.NeedsPassphrase() {
+		keys = "with encrypted API keys"
+	} else if len(apiProfiles) == 0 {
+		keys = "no secrets included"
+	}
+	fmt.Printf("✓ Exported %s to %s (%s)\n", strings.Join(names, ", "), output, keys)
+	return nil
+}
+
+func runProfileImport(cmd *cobra.Command, args []string) error {
+	file, err := profiles.ReadExportFile(args[0])
+	if err != nil {
+		return err
+	}
+	if len(file.Profiles) == 0 {
+		return fmt.Errorf("%s contains no profiles", args[0])
+	}
+	if err := confirmImportedSettings(file); err != nil {
+		return err
+	}
+
+	mgr, err := profiles.NewManager()
+	if err != nil {
+		return fmt.Errorf("failed to create profile manager: %w", err)
+	}
+
+	opts := profiles.ImportOptions{Overwrite: profileImportOverwrite}
+	if file.NeedsPassphrase() {
+		if opts.Passphrase, err = transferPassphrase(false); err != nil {
+			return err
+		}
+	}
+	if stdinIsTerminal() {
+		opts.APIKey = func(profile string) (string, error) {
+			return interactive.PromptPassword(fmt.Sprintf("API key for profile '%s' (not included in the export):", profile))
+		}
+	}
+
+	imported, err := mgr.Import(file, opts)
+	for _, name := range imported {
+		fmt.Printf("✓ Imported profile '%s'\n", name)
+	}
+	if err != nil {
+		return err
+	}
+
+	fmt.Printf("\nSwitch with: clauderock config switch --name %s\n", shellArg(imported[0]))
+	return nil
+}
+
+// confirmImportedSettings shows the settings of an export that route requests, usage records,
+// or environment variables elsewhere and asks before importing them, unless --yes is given
+func confirmImportedSettings(file *profiles.ExportFile) error {
+	settings := file.SensitiveSettings()
+	if len(settings) == 0 || profileImportYes {
+		return nil
+	}
+	if !stdinIsTerminal() {
+		return fmt.Errorf("the export sets where requests or usage records go:\n  %s\nReview them and pass --yes to import", strings.Join(settings, "\n  "))
+	}
+
+	confirmed, err := interactive.Confirm(
+		"Review imported settings",
+		"These settings decide where requests, usage records, and environment variables go. Only import them if you trust whoever made the file.",
+		settings,
+	)
+	if err != nil {
+		return fmt.Errorf("confirmation failed: %w", err)
+	}
+	if !confirmed {
+		return fmt.Errorf("import cancelled")
+	}
+	return nil
+}
@@ -1,22 +1,32 @@
 package cmd
 
 import (
+	"errors"
 	"fmt"
 	"os"
+	"slices"
 	"strings"
+	"time"
 
+	"github.com/OlaHulleberg/clauderock/internal/accessibility"
 	"github.com/OlaHulleberg/clauderock/internal/aws"
 	"github.com/OlaHulleberg/clauderock/internal/config"
+	"github.com/OlaHulleberg/clauderock/internal/currency"
+	"github.com/OlaHulleberg/clauderock/internal/i18n"
 	"github.com/OlaHulleberg/clauderock/internal/interactive"
 	"github.com/OlaHulleberg/clauderock/internal/keyring"
 	"github.com/OlaHulleberg/clauderock/internal/launcher"
+	"github.com/OlaHulleberg/clauderock/internal/permissions"
+	"github.com/OlaHulleberg/clauderock/internal/policy"
 	"github.com/OlaHulleberg/clauderock/internal/profiles"
 	"github.com/OlaHulleberg/clauderock/internal/updater"
+	"github.com/OlaHulleberg/clauderock/internal/usage"
 	"github.com/spf13/cobra"
 )
 
 var (
 	clauderockProfileFlag             string
+	clauderockGroupFlag               string
 	clauderockProfileTypeFlag         string
 	clauderockModelFlag               string
 	clauderockFastModelFlag           string
@@ -27,9 +37,18 @@ var (
 	clauderockBaseURLFlag             string
 	clauderockAPIKeyFlag              string
 	clauderockDisableAuthSuppressFlag bool
+	clauderockKeepEnvFlag             bool
+	clauderockProfileStartupFlag      bool
+	clauderockAutoRemapFlag           bool
+	clauderockSaveAsFlag              string
+	clauderockSessionNameFlag         string
+	clauderockTagFlags                []string
 	Version                           = "dev"
 )
 
+// processStart approximates when clauderock started, for --clauderock-profile-startup
+var processStart = time.Now()
+
 var rootCmd = &cobra.Command{
 	Use:   "clauderock",
 	Short: "Launch Claude Code with AWS Bedrock configuration",
@@ -38,14 +57,21 @@ var rootCmd = &cobra.Command{
 }
 
 func Execute() {
-	if err := rootCmd.Execute(); err != nil {
+	defer recoverPanic()
+	accessibility.Apply()
+	registerPluginCommands()
+
+	cmd, err := rootCmd.ExecuteC()
+	recordTelemetry(cmd, err)
+	if err != nil {
 		os.Exit(1)
 	}
 }
 
 func init() {
 	rootCmd.Flags().StringVar(&clauderockProfileFlag, "clauderock-profile", "", "Use a specific clauderock profile for this run")
-	rootCmd.Flags().StringVar(&clauderockProfileTypeFlag, "clauderock-profile-type", "", "Override profile type for this run (bedrock or api)")
+	rootCmd.Flags().StringVar(&clauderockGroupFlag, "clauderock-group", "", "Use the member of a profile group with the most headroom for this run")
+	rootCmd.Flags().StringVar(&clauderockProfileTypeFlag, "clauderock-profile-type", "", "Override profile type for this run (bedrock, api or vertex)")
 	rootCmd.Flags().StringVar(&clauderockModelFlag, "clauderock-model", "", "Override main model for this run")
 	rootCmd.Flags().StringVar(&clauderockFastModelFlag, "clauderock-fast-model", "", "Override fast model for this run")
 	rootCmd.Flags().StringVar(&clauderockHeavyModelFlag, "clauderock-heavy-model", "", "Override heavy model for this run")
@@ -54,7 +80,13 @@ func init() {
 	rootCmd.Flags().StringVar(&clauderockCrossRegionFlag, "clauderock-cross-region", "", "Override cross-region setting for this run (bedrock only)")
 	rootCmd.Flags().StringVar(&clauderockBaseURLFlag, "clauderock-base-url", "", "Override base URL for this run (api only)")
 	rootCmd.Flags().StringVar(&clauderockAPIKeyFlag, "clauderock-api-key", "", "Override API key for this run (api only, ephemeral)")
+	rootCmd.Flags().StringVar(&clauderockSaveAsFlag, "clauderock-save-as", "", "Validate the effective configuration, including overrides, and save it as a new profile before launching")
+	rootCmd.Flags().StringVar(&clauderockSessionNameFlag, "clauderock-session-name", "", "Name this session in usage stats (e.g., fix-auth-bug); you're offered to add a note when it ends")
+	rootCmd.Flags().StringArrayVar(&clauderockTagFlags, "clauderock-tag", nil, "Tag this session in usage stats as key=value (e.g., team=payments); repeatable")
 	rootCmd.Flags().BoolVar(&clauderockDisableAuthSuppressFlag, "clauderock-disable-auth-suppress", false, "Disable automatic credential suppression during startup")
+	rootCmd.Flags().BoolVar(&clauderockKeepEnvFlag, "clauderock-keep-env", false, "Keep conflicting inherited environment variables (only warn about them)")
+	rootCmd.Flags().BoolVar(&clauderockProfileStartupFlag, "clauderock-profile-startup", false, "Report how long each step before Claude Code starts takes")
+	rootCmd.Flags().BoolVar(&clauderockAutoRemapFlag, "clauderock-auto-remap", false, "Move retired models to their closest newer versions without asking")
 
 	// Allow unknown flags to pass through to Claude CLI
 	rootCmd.FParseErrWhitelist.UnknownFlags = true
@@ -65,13 +97,53 @@ func runRoot(cmd *cobra.Command, args []string) error {
 	// This includes all non-clauderock flags and positional arguments
 	passthroughArgs := collectPassthroughArgs()
 
+	sessionTags, err := usage.FormatTags(clauderockTagFlags)
+	if err != nil {
+		return err
+	}
+
+	var startup *launcher.StartupProfile
+	if clauderockProfileStartupFlag {
+		startup = launcher.NewStartupProfile(processStart)
+		startup.Track("start clauderock", processStart)
+	}
+	since := time.Now()
+
 	// Check for updates in background
 	go updater.CheckForUpdates(Version)
 
+	// Remove temporary files earlier updates and profile saves left behind
+	go updater.CleanupStaleArtifacts()
+
+	warnUnsafePermissions()
+
 	// Load configuration from profile
 	profileMgr, err := profiles.NewManager()
 	if err != nil {
-		return fmt.Errorf("failed to create profile manager: %w", err)
+		return i18n.Wrap(err, "failed to create profile manager")
+	}
+
+	// A group resolves to one of its member profiles
+	if clauderockGroupFlag != "" {
+		if clauderockProfileFlag != "" {
+			return fmt.Errorf("--clauderock-group and --clauderock-profile cannot be used together")
+		}
+		clauderockProfileFlag, err = pickGroupMember(profileMgr, clauderockGroupFlag)
+		if err != nil {
+			return fmt.Errorf("failed to pick profile from group '%s': %w", clauderockGroupFlag, err)
+		}
+	}
+
+	// A .clauderock file in the working directory or a parent binds it to a profile
+	if clauderockProfileFlag == "" {
+		binding, err := profileMgr.ProjectProfile(".")
+		if err != nil {
+			return err
+		}
+		if binding != nil {
+			fmt.Printf("Using profile '%s' (bound by %s)\n", binding.Profile, binding.Path)
+			clauderockProfileFlag = binding.Profile
+		}
 	}
 
 	var cfg *config.Config
@@ -85,13 +157,23 @@ func runRoot(cmd *cobra.Command, args []string) error {
 		// Load current profile
 		cfg, err = profileMgr.GetCurrentConfig(Version)
 		if err != nil {
-			return fmt.Errorf("failed to load config: %w", err)
+			return i18n.Wrap(err, "failed to load config")
 		}
 	}
 
+	startup.Track("load profile", since)
+
+	kiosk, kioskSource, err := policy.KioskMode()
+	if err != nil {
+		return err
+	}
+
 	// If config is incomplete, launch interactive configurator
 	if cfg.IsIncomplete() {
-		fmt.Println("Configuration incomplete. Starting interactive setup...")
+		if kiosk {
+			return fmt.Errorf("profile is not fully provisioned and setup is disabled in kiosk mode (enabled by %s); ask your administrator", kioskSource)
+		}
+		fmt.Println(i18n.T("Configuration incomplete. Starting interactive setup..."))
 		if err := interactive.RunInteractiveConfig(Version, profileMgr); err != nil {
 			return fmt.Errorf("configuration setup failed: %w", err)
 		}
@@ -102,13 +184,23 @@ func runRoot(cmd *cobra.Command, args []string) error {
 		}
 	}
 
+	// Credentials from the environment must not fall back to an inherited AWS profile
+	if cfg.ProfileType == "bedrock" && cfg.CredentialsSource == config.CredentialsSourceEnvironment {
+		aws.UseEnvironmentCredentials()
+	}
+
+	if !kiosk {
+		applyAutoUpgrade(profileMgr, cfg)
+		remapRetiredModels(profileMgr, cfg, clauderockAutoRemapFlag)
+	}
+
 	// Apply overrides from flags
 	hasOverrides := false
 
 	// Profile type override
 	if clauderockProfileTypeFlag != "" {
-		if clauderockProfileTypeFlag != "bedrock" && clauderockProfileTypeFlag != "api" {
-			return fmt.Errorf("--clauderock-profile-type must be either 'bedrock' or 'api'")
+		if clauderockProfileTypeFlag != "bedrock" && clauderockProfileTypeFlag != "api" && clauderockProfileTypeFlag != "vertex" {
+			return fmt.Errorf("--clauderock-profile-type must be one of: bedrock, api, vertex")
 		}
 		cfg.ProfileType = clauderockProfileTypeFlag
 		hasOverrides = true
@@ -120,6 +212,7 @@ func runRoot(cmd *cobra.Command, args []string) error {
 			return fmt.Errorf("--clauderock-aws-profile can only be used with bedrock profile type")
 		}
 		cfg.Profile = clauderockAWSProfileFlag
+		cfg.CredentialsSource = config.CredentialsSourceProfile
 		hasOverrides = true
 	}
 	if clauderockRegionFlag != "" {
@@ -162,33 +255,47 @@ func runRoot(cmd *cobra.Command, args []string) error {
 		hasOverrides = true
 	}
 
-	// Model overrides (works for both profile types)
+	// Model overrides (works for all profile types)
 	if clauderockModelFlag != "" {
-		// For bedrock, validate it's a full profile ID
-		if cfg.ProfileType == "bedrock" && !aws.IsFullProfileID(clauderockModelFlag) {
-			return fmt.Errorf("--clauderock-model must be a full profile ID for bedrock (e.g., 'global.anthropic.claude-sonnet-4-5-20250929-v1:0')\nRun 'clauderock manage models list' to see available models")
+		model, err := resolveModelOverride(cfg, "clauderock-model", "main", clauderockModelFlag, "global.anthropic.claude-sonnet-4-5-20250929-v1:0", !kiosk)
+		if err != nil {
+			return err
 		}
-		cfg.Model = clauderockModelFlag
+		cfg.Model = model
 		hasOverrides = true
 	}
 	if clauderockFastModelFlag != "" {
-		if cfg.ProfileType == "bedrock" && !aws.IsFullProfileID(clauderockFastModelFlag) {
-			return fmt.Errorf("--clauderock-fast-model must be a full profile ID for bedrock (e.g., 'global.anthropic.claude-haiku-4-5-20250929-v1:0')\nRun 'clauderock manage models list' to see available models")
+		model, err := resolveModelOverride(cfg, "clauderock-fast-model", "fast", clauderockFastModelFlag, "global.anthropic.claude-haiku-4-5-20251001-v1:0", !kiosk)
+		if err != nil {
+			return err
 		}
-		cfg.FastModel = clauderockFastModelFlag
+		cfg.FastModel = model
 		hasOverrides = true
 	}
 	if clauderockHeavyModelFlag != "" {
-		if cfg.ProfileType == "bedrock" && !aws.IsFullProfileID(clauderockHeavyModelFlag) {
-			return fmt.Errorf("--clauderock-heavy-model must be a full profile ID for bedrock (e.g., 'global.anthropic.claude-opus-4-1-20250514-v1:0')\nRun 'clauderock manage models list' to see available models")
+		model, err := resolveModelOverride(cfg, "clauderock-heavy-model", "heavy", clauderockHeavyModelFlag, "global.anthropic.claude-opus-4-1-20250805-v1:0", !kiosk)
+		if err != nil {
+			return err
 		}
-		cfg.HeavyModel = clauderockHeavyModelFlag
+		cfg.HeavyModel = model
 		hasOverrides = true
 	}
 
+	if hasOverrides && kiosk {
+		return kioskError("overriding profile settings", kioskSource)
+	}
+	if clauderockSaveAsFlag != "" && kiosk {
+		return kioskError("saving profiles", kioskSource)
+	}
+
 	// Validate configuration
 	if err := cfg.Validate(); err != nil {
-		return fmt.Errorf("invalid configuration: %w", err)
+		return i18n.Wrap(err, "invalid configuration")
+	}
+
+	// Organisation policy applies to the effective configuration, including overrides
+	if err := policy.Enforce(cfg); err != nil {
+		return err
 	}
 
 	// Show overrides if any
@@ -221,9 +328,19 @@ func runRoot(cmd *cobra.Command, args []string) error {
 		if clauderockHeavyModelFlag != "" {
 			fmt.Printf("  Heavy Model: %s\n", cfg.HeavyModel)
 		}
+		if clauderockSaveAsFlag == "" && !stdinIsTerminal() {
+			fmt.Println("Keep them as a profile by adding: --clauderock-save-as <name>")
+		}
 		fmt.Println()
 	}
 
+	// Persist the effective configuration before launching, so it survives the session
+	if clauderockSaveAsFlag != "" {
+		if err := saveConfigAs(profileMgr, clauderockSaveAsFlag, cfg); err != nil {
+			return err
+		}
+	}
+
 	// Use stored inference profile IDs directly (no AWS query needed!)
 	mainModelID := cfg.Model
 	fastModelID := cfg.FastModel
@@ -245,8 +362,298 @@ func runRoot(cmd *cobra.Command, args []string) error {
 		}
 	}
 
+	since = time.Now()
+	if err := checkBudgets(cfg, currentProfile); err != nil {
+		return err
+	}
+	startup.Track("check budgets", since)
+
+	since = time.Now()
+	if err := ensureSSOLogin(cfg); err != nil {
+		return err
+	}
+	startup.Track("check AWS SSO session", since)
+
+	since = time.Now()
+	announceNewModels(cfg)
+	startup.Track("check for new models", since)
+
+	// Prices and exchange rates past their TTL are refreshed while Claude Code runs, so stats
+	// and cost views never wait on the network; a missed refresh leaves the cached ones in use
+	go refreshStaleCaches()
+
 	// Launch Claude Code with passthrough args
-	return launcher.Launch(cfg, mainModelID, fastModelID, heavyModelID, currentProfile, clauderockDisableAuthSuppressFlag, passthroughArgs)
+	err = launcher.Launch(cfg, mainModelID, fastModelID, heavyModelID, currentProfile, clauderockSessionNameFlag, sessionTags, clauderockDisableAuthSuppressFlag, clauderockKeepEnvFlag, startup, passthroughArgs)
+
+	// Validation found retired models the cached list didn't know about yet
+	var retired *aws.RetiredModelsError
+	if errors.As(err, &retired) && !kiosk {
+		offerValidationRemap(profileMgr, currentProfile, retired.Remaps, clauderockAutoRemapFlag)
+	}
+
+	// A run with overrides that worked is worth keeping
+	if err == nil && hasOverrides && clauderockSaveAsFlag == "" && !kiosk && stdinIsTerminal() {
+		offerSaveConfig(profileMgr, cfg)
+	}
+	return err
+}
+
+// warnUnsafePermissions warns when clauderock runs as root, or other users can read its
+// profiles or keyring; neither stops the launch
+func warnUnsafePermissions() {
+	if permissions.Elevated() {
+		fmt.Println("Warning: clauderock is running with elevated privileges. Files it writes to ~/.clauderock may")
+		fmt.Println("         become unreadable to your own user; run it as yourself instead.")
+	}
+	problems, err := permissions.Check()
+	if err != nil || len(problems) == 0 {
+		return
+	}
+	fmt.Printf("Warning: %d clauderock file(s) holding profiles or keys are accessible by other users or owned by another user\n", len(problems))
+	fmt.Println("         Run 'clauderock manage doctor permissions --fix' to correct them")
+}
+
+// checkBudgets compares the profile's tracked spend with its budgets, warning at the
+// configured thresholds and refusing to launch over budget when budget-policy is refuse
+func checkBudgets(cfg *config.Config, profileName string) error {
+	if cfg.BudgetDailyUSD <= 0 && cfg.BudgetMonthlyUSD <= 0 {
+		return nil
+	}
+
+	tracker, err := usage.NewTracker()
+	if err != nil {
+		return budgetUnavailable(cfg, profileName, err)
+	}
+	defer tracker.Close()
+
+	statuses, err := tracker.CheckBudgets(profileName, cfg.BudgetDailyUSD, cfg.BudgetMonthlyUSD, time.Now())
+	if err != nil {
+		return budgetUnavailable(cfg, profileName, err)
+	}
+
+	thresholds := cfg.BudgetWarnAt
+	if len(thresholds) == 0 {
+		thresholds = config.DefaultBudgetWarnAt
+	}
+
+	warned := false
+	for _, b := range statuses {
+		spent := fmt.Sprintf("%s of its %s %s budget", currency.Format(b.Spent), currency.Format(b.Limit), b.Period)
+		if b.Exceeded() {
+			if cfg.BudgetPolicy == config.BudgetPolicyRefuse {
+				return fmt.Errorf("profile '%s' has used %s\nRaise it with: clauderock manage config set budget-%s-usd <amount>", profileName, spent, b.Period)
+			}
+			fmt.Printf("Warning: profile '%s' is over budget: it has used %s\n", profileName, spent)
+			warned = true
+			continue
+		}
+		for _, threshold := range thresholds {
+			if b.Percent() >= float64(threshold) {
+				fmt.Printf("Warning: profile '%s' has used %s (%.0f%%)\n", profileName, spent, b.Percent())
+				warned = true
+				break
+			}
+		}
+	}
+	if warned {
+		fmt.Println()
+	}
+	return nil
+}
+
+// budgetUnavailable handles a budget that can't be checked because the usage database
+// can't be read. Spending is then untracked, so a refuse policy stops the launch rather
+// than letting it through unlimited, and a warn policy says so plainly
+func budgetUnavailable(cfg *config.Config, profileName string, err error) error {
+	if cfg.BudgetPolicy == config.BudgetPolicyRefuse {
+		return fmt.Errorf("can't check the budgets of profile '%s' because the usage database can't be read: %w\nFix the database, or launch anyway with: clauderock manage config set budget-policy warn", profileName, err)
+	}
+	fmt.Fprintf(os.Stderr, "WARNING: the budgets of profile '%s' are NOT being enforced: the usage database can't be read: %v\n", profileName, err)
+	fmt.Fprintln(os.Stderr, "         Spending is not limited until this is fixed; set budget-policy to refuse to stop launches instead")
+	fmt.Fprintln(os.Stderr)
+	return nil
+}
+
+// modelUpgrade is a newer model found for a slot by applyAutoUpgrade
+type modelUpgrade struct {
+	slot, from, to string
+}
+
+// applyAutoUpgrade moves the profile's model slots to newer models from the cached model list
+// when auto-upgrade is on, asking first unless auto-upgrade-mode is silent. Pinned slots are kept
+func applyAutoUpgrade(mgr *profiles.Manager, cfg *config.Config) {
+	if cfg.ProfileType != "bedrock" || cfg.AutoUpgrade == "" || cfg.AutoUpgrade == config.AutoUpgradeOff {
+		return
+	}
+
+	profileIDs, err := aws.CachedProfileIDs(cfg.Region, cfg.CrossRegion)
+	if err != nil || len(profileIDs) == 0 {
+		return
+	}
+
+	var upgrades []modelUpgrade
+	for _, slot := range config.ModelSlots {
+		if cfg.PinnedVersion(slot) != "" {
+			continue
+		}
+		current, _ := cfg.ModelForSlot(slot)
+		newer := aws.FindUpgrade(current, cfg.CrossRegion, profileIDs, cfg.AutoUpgrade == config.AutoUpgradeMajor)
+		if newer != "" && !slices.Contains(cfg.DeclinedUpgrades, newer) {
+			upgrades = append(upgrades, modelUpgrade{slot: slot, from: current, to: newer})
+		}
+	}
+	if len(upgrades) == 0 {
+		return
+	}
+
+	profileName := clauderockProfileFlag
+	if profileName == "" {
+		if profileName, err = mgr.GetCurrent(); err != nil {
+			return
+		}
+	}
+
+	if cfg.AutoUpgradeMode != config.AutoUpgradeModeSilent {
+		// Without a terminal to ask on, keep the current models until the next interactive launch
+		if !stdinIsTerminal() {
+			return
+		}
+
+		details := make([]string, len(upgrades))
+		for i, u := range upgrades {
+			details[i] = fmt.Sprintf("%s: %s → %s", u.slot, u.from, u.to)
+		}
+		confirmed, err := interactive.Confirm(
+			"Newer models available",
+			fmt.Sprintf("Upgrade profile '%s' to them? Declined upgrades aren't offered again.", profileName),
+			details,
+		)
+		if err != nil {
+			return
+		}
+		if !confirmed {
+			_, err := mgr.Update(profileName, func(stored *config.Config) error {
+				for _, u := range upgrades {
+					stored.DeclinedUpgrades = append(stored.DeclinedUpgrades, u.to)
+				}
+				return nil
+			})
+			if err != nil {
+				fmt.Printf("Warning: failed to save profile: %v\n", err)
+			}
+			return
+		}
+	}
+
+	_, err = mgr.Update(profileName, func(stored *config.Config) error {
+		for _, u := range upgrades {
+			stored.SetModelForSlot(u.slot, u.to)
+		}
+		return nil
+	})
+	if err != nil {
+		fmt.Printf("Warning: failed to save upgraded models: %v\n\n", err)
+		return
+	}
+	for _, u := range upgrades {
+		cfg.SetModelForSlot(u.slot, u.to)
+	}
+	for _, u := range upgrades {
+		fmt.Printf("✓ Upgraded %s model: %s → %s\n", u.slot, u.from, u.to)
+	}
+	fmt.Println()
+}
+
+// resolveModelOverride returns the model a --clauderock-*-model flag selects for a slot
+// Bedrock needs full profile IDs; a friendly name (e.g., anthropic.claude-sonnet-4-5) is resolved
+// to the newest matching inference profile, using the cached model list, once the user confirms it
+func resolveModelOverride(cfg *config.Config, flag, slot, model, example string, canPrompt bool) (string, error) {
+	if cfg.ProfileType != "bedrock" || aws.IsFullProfileID(model) {
+		return model, nil
+	}
+
+	usage := fmt.Errorf("--%s must be a full profile ID for bedrock (e.g., '%s')\nRun 'clauderock manage models list' to see available models", flag, example)
+	if !canPrompt || !stdinIsTerminal() {
+		return "", usage
+	}
+
+	profileID, err := aws.ResolveModelToProfileID(cfg.AWSProfile(), cfg.Region, cfg.CrossRegion, model)
+	if err != nil {
+		return "", fmt.Errorf("failed to resolve --%s %s: %w", flag, model, err)
+	}
+
+	confirmed, err := interactive.Confirm(
+		"Resolve model override",
+		fmt.Sprintf("Use %s as the %s model for this run?", profileID, slot),
+		[]string{
+			fmt.Sprintf("--%s %s is a friendly name; Bedrock needs the inference profile ID", flag, model),
+			"Newest " + cfg.CrossRegion + " profile in " + cfg.Region,
+			"Pass the full ID to skip this question",
+		},
+	)
+	if err != nil {
+		return "", err
+	}
+	if !confirmed {
+		return "", usage
+	}
+	return profileID, nil
+}
+
+// ensureSSOLogin offers to run 'aws sso login' when a Bedrock profile's SSO session has expired,
+// so the launch doesn't fail on credentials. A check that can't complete is left to launch validation
+func ensureSSOLogin(cfg *config.Config) error {
+	if cfg.ProfileType != "bedrock" {
+		return nil
+	}
+	status, err := aws.CheckSSOSession(cfg.AWSProfile())
+	if err != nil || status == nil || !status.Expired {
+		return nil
+	}
+
+	loginCmd := fmt.Sprintf("aws sso login --profile %s", status.LoginProfile)
+	if !stdinIsTerminal() {
+		fmt.Printf("Warning: the AWS SSO session for profile '%s' has expired; log in with: %s\n\n", cfg.Profile, loginCmd)
+		return nil
+	}
+
+	detail := "No SSO login was found for it."
+	if !status.ExpiresAt.IsZero() {
+		detail = fmt.Sprintf("It expired %s.", status.ExpiresAt.Local().Format("Jan 02 15:04"))
+	}
+	confirmed, err := interactive.Confirm(
+		"AWS SSO session expired",
+		fmt.Sprintf("Log in to AWS profile '%s' now?", status.LoginProfile),
+		[]string{detail, "Runs: " + loginCmd},
+	)
+	if err != nil {
+		return err
+	}
+	if !confirmed {
+		fmt.Printf("Launching anyway; Bedrock requests will fail until you run: %s\n\n", loginCmd)
+		return nil
+	}
+
+	if err := aws.SSOLogin(status.LoginProfile); err != nil {
+		return err
+	}
+	fmt.Println()
+	return nil
+}
+
+// announceNewModels prints models an earlier refresh found that weren't there before,
+// then refreshes the cached model list in the background for the next launch
+func announceNewModels(cfg *config.Config) {
+	if cfg.ProfileType != "bedrock" || aws.ModelNewsSuppressed() {
+		return
+	}
+
+	if models, err := aws.PendingNewModels(cfg.Region, cfg.CrossRegion); err == nil && len(models) > 0 {
+		fmt.Printf("New models available: %s (hide with %s=1)\n\n", strings.Join(models, ", "), aws.NoModelNewsEnvVar)
+	}
+
+	// Silently fail - a missed refresh only delays the news
+	go aws.RefreshModelCache(cfg.AWSProfile(), cfg.Region, cfg.CrossRegion)
 }
 
 // collectPassthroughArgs separates clauderock flags from Claude CLI args
@@ -257,21 +664,28 @@ func collectPassthroughArgs() []string {
 
 	var passthroughArgs []string
 	clauderockFlags := map[string]bool{
-		"--clauderock-profile":       true,
-		"--clauderock-profile-type":  true,
-		"--clauderock-model":         true,
-		"--clauderock-fast-model":    true,
-		"--clauderock-heavy-model":   true,
-		"--clauderock-aws-profile":   true,
-		"--clauderock-region":        true,
-		"--clauderock-cross-region":  true,
-		"--clauderock-base-url":      true,
-		"--clauderock-api-key":       true,
+		"--clauderock-profile":      true,
+		"--clauderock-group":        true,
+		"--clauderock-profile-type": true,
+		"--clauderock-model":        true,
+		"--clauderock-fast-model":   true,
+		"--clauderock-heavy-model":  true,
+		"--clauderock-aws-profile":  true,
+		"--clauderock-region":       true,
+		"--clauderock-cross-region": true,
+		"--clauderock-base-url":     true,
+		"--clauderock-api-key":      true,
+		"--clauderock-save-as":      true,
+		"--clauderock-session-name": true,
+		"--clauderock-tag":          true,
 	}
 
 	// Boolean flags (no value, don't skip next arg)
 	clauderockBoolFlags := map[string]bool{
 		"--clauderock-disable-auth-suppress": true,
+		"--clauderock-keep-env":              true,
+		"--clauderock-profile-startup":       true,
+		"--clauderock-auto-remap":            true,
 	}
 
 	skip := false
@@ -0,0 +1,95 @@
+package cmd
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/OlaHulleberg/clauderock/internal/config"
+	"github.com/OlaHulleberg/clauderock/internal/interactive"
+	"github.com/OlaHulleberg/clauderock/internal/launcher"
+	"github.com/OlaHulleberg/clauderock/internal/profiles"
+)
+
+// saveConfigAs saves the effective configuration for --clauderock-save-as once its models
+// have been validated, asking first when there is a terminal
+// Declining still launches; a configuration that fails validation is neither saved nor launched
+func saveConfigAs(mgr *profiles.Manager, name string, cfg *config.Config) error {
+	if mgr.Exists(name) {
+		return fmt.Errorf("failed to save profile '%s': profile '%s' already exists", name, name)
+	}
+
+	fmt.Println("Checking the models before saving...")
+	if err := launcher.ValidateModels(cfg, cfg.Model, cfg.FastModel, cfg.HeavyModel); err != nil {
+		return fmt.Errorf("not saving profile '%s': invalid model configuration: %w", name, err)
+	}
+
+	if stdinIsTerminal() {
+		confirmed, err := interactive.Confirm(
+			"Save profile",
+			fmt.Sprintf("Save this configuration as profile '%s'?", name),
+			configSummary(cfg),
+		)
+		if err != nil {
+			return err
+		}
+		if !confirmed {
+			fmt.Println("Profile not saved")
+			fmt.Println()
+			return nil
+		}
+	}
+
+	if err := mgr.SaveAs(name, cfg); err != nil {
+		return fmt.Errorf("failed to save profile '%s': %w", name, err)
+	}
+	fmt.Printf("✓ Saved this configuration as profile '%s' (switch to it with: clauderock manage config switch --name %s)\n\n", name, shellArg(name))
+	return nil
+}
+
+// offerSaveConfig offers, after a session run with overrides, to keep the configuration as a
+// new profile; it's only offered once the models are known to work, and errors are only reported
+func offerSaveConfig(mgr *profiles.Manager, cfg *config.Config) {
+	if err := launcher.ValidateModels(cfg, cfg.Model, cfg.FastModel, cfg.HeavyModel); err != nil {
+		return
+	}
+
+	confirmed, err := interactive.Confirm(
+		"Save profile",
+		"Keep this run's configuration, overrides included, as a new profile?",
+		configSummary(cfg),
+	)
+	if err != nil || !confirmed {
+		return
+	}
+
+	for {
+		name, err := interactive.PromptTextInput("Profile name", "us-test", "")
+		if err != nil {
+			return
+		}
+		name = strings.TrimSpace(name)
+		if err := mgr.SaveAs(name, cfg); err != nil {
+			fmt.Printf("✗ %v\n", err)
+			continue
+		}
+		fmt.Printf("✓ Saved as profile '%s' (switch to it with: clauderock manage config switch --name %s)\n", name, shellArg(name))
+		return
+	}
+}
+
+// configSummary lists the settings a saved profile would launch with
+func configSummary(cfg *config.Config) []string {
+	summary := []string{"Type: " + cfg.ProfileType}
+	switch cfg.ProfileType {
+	case "bedrock":
+		summary = append(summary, "Region: "+cfg.Region, "Cross Region: "+cfg.CrossRegion)
+		if profile := cfg.AWSProfile(); profile != "" {
+			summary = append(summary, "AWS Profile: "+profile)
+		}
+	case "vertex":
+		summary = append(summary, "Project: "+cfg.GCPProject, "Region: "+cfg.VertexRegion)
+	default:
+		summary = append(summary, "Base URL: "+cfg.BaseURL)
+	}
+	return append(summary, "Model: "+cfg.Model, "Fast Model: "+cfg.FastModel, "Heavy Model: "+cfg.HeavyModel)
+}
@@ -8,12 +8,15 @@ import (
 	"strings"
 	"time"
 
+	"github.com/OlaHulleberg/clauderock/internal/accessibility"
+	"github.com/OlaHulleberg/clauderock/internal/chart"
+	"github.com/OlaHulleberg/clauderock/internal/currency"
+	"github.com/OlaHulleberg/clauderock/internal/i18n"
 	"github.com/OlaHulleberg/clauderock/internal/pricing"
+	"github.com/OlaHulleberg/clauderock/internal/profiles"
 	"github.com/OlaHulleberg/clauderock/internal/usage"
 	"github.com/charmbracelet/lipgloss"
 	"github.com/spf13/cobra"
-	"golang.org/x/text/language"
-	"golang.org/x/text/message"
 )
 
 var (
@@ -24,37 +27,55 @@ var (
 	statsMonth    string
 	statsToday    bool
 	statsWeek     bool
+	statsAll      bool
 	statsDetailed bool
 	statsExport   string
+	statsSigma    float64
+	statsName     string
+	statsRepo     string
+	statsTag      string
+	statsGroupBy  string
+	statsTopBy    string
+	statsWatch    time.Duration
 )
 
 // Styles for stats output
 var (
-	headerStyle     = lipgloss.NewStyle().Bold(true).Foreground(lipgloss.Color("12"))
-	sectionStyle    = lipgloss.NewStyle().Bold(true).Foreground(lipgloss.Color("10"))
-	labelStyle      = lipgloss.NewStyle().Foreground(lipgloss.Color("8"))
-	valueStyle      = lipgloss.NewStyle().Bold(true).Foreground(lipgloss.Color("15"))
-	highlightStyle  = lipgloss.NewStyle().Bold(true).Foreground(lipgloss.Color("10"))
-	costStyle       = lipgloss.NewStyle().Bold(true).Foreground(lipgloss.Color("11"))
-	mutedStyle      = lipgloss.NewStyle().Foreground(lipgloss.Color("8"))
-	boxStyle        = lipgloss.NewStyle().Border(lipgloss.RoundedBorder()).BorderForeground(lipgloss.Color("8")).Padding(0, 1)
-	separatorStyle  = lipgloss.NewStyle().Foreground(lipgloss.Color("8"))
+	headerStyle    = lipgloss.NewStyle().Bold(true).Foreground(lipgloss.Color("12"))
+	sectionStyle   = lipgloss.NewStyle().Bold(true).Foreground(lipgloss.Color("10"))
+	labelStyle     = lipgloss.NewStyle().Foreground(lipgloss.Color("8"))
+	valueStyle     = lipgloss.NewStyle().Bold(true).Foreground(lipgloss.Color("15"))
+	highlightStyle = lipgloss.NewStyle().Bold(true).Foreground(lipgloss.Color("10"))
+	costStyle      = lipgloss.NewStyle().Bold(true).Foreground(lipgloss.Color("11"))
+	mutedStyle     = lipgloss.NewStyle().Foreground(lipgloss.Color("8"))
+	boxStyle       = lipgloss.NewStyle().Border(lipgloss.RoundedBorder()).BorderForeground(lipgloss.Color("8")).Padding(0, 1)
+	separatorStyle = lipgloss.NewStyle().Foreground(lipgloss.Color("8"))
 )
 
-// formatNumber formats an integer with thousand separators
+// formatNumber formats an integer with the locale's thousand separators
 func formatNumber(n int64) string {
-	p := message.NewPrinter(language.English)
-	return p.Sprintf("%d", n)
+	return i18n.Printer().Sprintf("%d", n)
 }
 
-// formatFloat formats a float with thousand separators
+// formatFloat formats a float with the locale's thousand separators
 func formatFloat(f float64) string {
-	p := message.NewPrinter(language.English)
-	return p.Sprintf("%.0f", f)
+	return i18n.Printer().Sprintf("%.0f", f)
+}
+
+// sectionHeading renders a section title, without the bullet symbol in accessible mode
+func sectionHeading(title string) string {
+	if accessibility.Enabled() {
+		return sectionStyle.Render(title + ":")
+	}
+	return sectionStyle.Render("▸ " + title)
 }
 
 // progressBar creates a visual progress bar for percentages
+// In accessible mode it is omitted; the percentage next to it carries the same information
 func progressBar(percentage float64, width int) string {
+	if accessibility.Enabled() {
+		return ""
+	}
 	filled := int(percentage / 100.0 * float64(width))
 	if filled > width {
 		filled = width
@@ -73,7 +94,14 @@ var statsCmd = &cobra.Command{
 	Long: `View usage statistics and estimated costs.
 
 Shows session counts, token usage, TPM/RPM metrics, and cost estimates
-based on actual token usage from tracked sessions.
+based on actual token usage from tracked sessions. Sessions whose tokens or
+cost are far above their profile's baseline are listed under Anomalies.
+
+Coding time only counts active time: gaps of more than 5 minutes between
+requests are treated as idle. Wall-clock time includes them.
+
+The default time range, hidden sections, and number of top sessions are set
+with 'clauderock manage stats view'; --all shows all time regardless.
 
 Examples:
   clauderock stats
@@ -82,7 +110,13 @@ Examples:
   clauderock stats --since 2025-10-01
   clauderock stats --month 2025-10
   clauderock stats --today
-  clauderock stats --export report.csv`,
+  clauderock stats --all
+  clauderock stats --week --top-by cost
+  clauderock stats --name fix-auth
+  clauderock stats --repo acme/api --tag team=payments
+  clauderock stats --month 2025-10 --group-by branch
+  clauderock stats --export report.csv
+  clauderock stats --month 2025-10 --export usage.png`,
 	RunE: runStats,
 }
 
@@ -91,46 +125,163 @@ func init() {
 
 	statsCmd.Flags().StringVar(&statsProfile, "profile", "", "Filter by profile name")
 	statsCmd.Flags().StringVar(&statsModel, "model", "", "Filter by model")
+	statsCmd.Flags().StringVar(&statsName, "name", "", "Filter by session name (matches names containing it)")
+	statsCmd.Flags().StringVar(&statsRepo, "repo", "", "Filter by git repository (e.g., acme/api)")
+	statsCmd.Flags().StringVar(&statsTag, "tag", "", "Filter by session tag: key=value, or a key for any value")
 	statsCmd.Flags().StringVar(&statsSince, "since", "", "Filter sessions since date (YYYY-MM-DD)")
 	statsCmd.Flags().StringVar(&statsUntil, "until", "", "Filter sessions until date (YYYY-MM-DD)")
 	statsCmd.Flags().StringVar(&statsMonth, "month", "", "Filter by month (YYYY-MM)")
 	statsCmd.Flags().BoolVar(&statsToday, "today", false, "Show today's stats only")
 	statsCmd.Flags().BoolVar(&statsWeek, "week", false, "Show this week's stats")
+	statsCmd.Flags().BoolVar(&statsAll, "all", false, "Show all time, ignoring the default range from 'stats view'")
+	statsCmd.Flags().StringVar(&statsTopBy, "top-by", usage.TopByTPM, "Rank top sessions by tpm, tokens, cost, or duration (active time)")
+	statsCmd.Flags().StringVar(&statsGroupBy, "group-by", "", "Sum sessions per git repository or branch instead (repo, branch)")
 	statsCmd.Flags().BoolVar(&statsDetailed, "detailed", false, "Show detailed output")
-	statsCmd.Flags().StringVar(&statsExport, "export", "", "Export to CSV file")
+	statsCmd.Flags().StringVar(&statsExport, "export", "", "Export to a CSV file, or to a PNG/SVG chart of tokens per day and cost per model")
+	statsCmd.Flags().Float64Var(&statsSigma, "anomaly-sigma", usage.DefaultAnomalySigma, "Flag sessions this many standard deviations above their profile's baseline")
+	// The tmux stats pane redraws through this instead of rerunning the command in a loop
+	statsCmd.Flags().DurationVar(&statsWatch, "watch", 0, "Redraw whenever a session is recorded, checking at this interval")
+	statsCmd.Flags().MarkHidden("watch")
 }
 
 func runStats(cmd *cobra.Command, args []string) error {
+	if statsWatch > 0 {
+		return watchStats(cmd, args)
+	}
+	return showStats(cmd, args)
+}
+
+// watchStats redraws the stats when another process records a session, and when the
+// day changes so relative ranges such as --today move on. Checking only reads SQLite's
+// change counter, so an idle pane doesn't rerun the stats queries
+func watchStats(cmd *cobra.Command, args []string) error {
+	db, err := usage.NewDatabase()
+	if err != nil {
+		return err
+	}
+	defer db.Close()
+
+	changes, err := db.WatchChanges()
+	if err != nil {
+		return err
+	}
+	defer changes.Close()
+
+	var shownDay string
+	for {
+		changed, err := changes.Changed()
+		if err != nil {
+			return err
+		}
+		if day := time.Now().Format("2006-01-02"); changed || day != shownDay {
+			shownDay = day
+			fmt.Print("\033[H\033[2J")
+			if err := showStats(cmd, args); err != nil {
+				// Keep watching; the next recorded session may well succeed
+				fmt.Printf("Error: %v\n", err)
+			}
+		}
+		time.Sleep(statsWatch)
+	}
+}
+
+// showStats prints the stats once
+func showStats(cmd *cobra.Command, args []string) error {
 	tracker, err := usage.NewTracker()
 	if err != nil {
 		return fmt.Errorf("failed to create tracker: %w", err)
 	}
 	defer tracker.Close()
 
-	// Build filter
+	filter, err := statsFilter()
+	if err != nil {
+		return err
+	}
+	if err := usage.CheckTopBy(statsTopBy); err != nil {
+		return err
+	}
+	if err := usage.CheckAnomalySigma(statsSigma); err != nil {
+		return err
+	}
+
+	view, err := usage.LoadViewSettings()
+	if err != nil {
+		return err
+	}
+	if !statsAll && !statsDateFlagsSet() {
+		if err := applyViewRange(&filter, view.Range); err != nil {
+			return err
+		}
+	}
+
+	// Get session stats (new detailed view)
+	sessionStats, err := tracker.GetSessionStats(filter, statsTopBy, view.TopSessionCount())
+	if err != nil {
+		return fmt.Errorf("failed to get session stats: %w", err)
+	}
+
+	// Export to CSV, or to a chart image for .png and .svg files
+	if statsExport != "" {
+		var err error
+		if chart.IsChartPath(statsExport) {
+			err = exportSessionsToChart(filter, statsExport)
+		} else {
+			err = exportSessionsToCSV(tracker, filter, statsExport)
+		}
+		if err != nil {
+			return fmt.Errorf("failed to export: %w", err)
+		}
+		fmt.Printf("Exported to %s\n", statsExport)
+		return nil
+	}
+
+	if statsGroupBy != "" {
+		groups, err := tracker.GroupSessions(filter, statsGroupBy)
+		if err != nil {
+			return err
+		}
+		displaySessionGroups(groups, statsGroupBy)
+		return nil
+	}
+
+	// Display session stats
+	displaySessionStats(sessionStats, filter, view, statsTopBy)
+
+	if view.Shows("anomalies") {
+		anomalies, err := tracker.FindAnomalies(filter, statsSigma)
+		if err != nil {
+			return fmt.Errorf("failed to detect anomalies: %w", err)
+		}
+		displayAnomalies(anomalies)
+	}
+
+	return nil
+}
+
+// statsFilter builds the session filter from the stats filter flags
+func statsFilter() (usage.QueryFilter, error) {
 	filter := usage.QueryFilter{
 		ProfileName: statsProfile,
 		Model:       statsModel,
+		SessionName: statsName,
+		Repo:        statsRepo,
+		Tag:         statsTag,
+	}
+	if statsTag != "" {
+		if err := usage.CheckTagFilter(statsTag); err != nil {
+			return filter, err
+		}
 	}
 
 	// Parse date filters
 	if statsToday {
-		now := time.Now()
-		filter.StartDate = time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, now.Location())
-		filter.EndDate = time.Date(now.Year(), now.Month(), now.Day(), 23, 59, 59, 0, now.Location())
+		filter.StartDate, filter.EndDate = todayRange(time.Now())
 	} else if statsWeek {
-		now := time.Now()
-		weekday := int(now.Weekday())
-		if weekday == 0 {
-			weekday = 7 // Sunday
-		}
-		startOfWeek := now.AddDate(0, 0, -(weekday - 1))
-		filter.StartDate = time.Date(startOfWeek.Year(), startOfWeek.Month(), startOfWeek.Day(), 0, 0, 0, 0, startOfWeek.Location())
-		filter.EndDate = now
+		filter.StartDate, filter.EndDate = weekRange(time.Now())
 	} else if statsMonth != "" {
 		monthDate, err := time.Parse("2006-01", statsMonth)
 		if err != nil {
-			return fmt.Errorf("invalid month format, use YYYY-MM: %w", err)
+			return filter, fmt.Errorf("invalid month format, use YYYY-MM: %w", err)
 		}
 		filter.StartDate = time.Date(monthDate.Year(), monthDate.Month(), 1, 0, 0, 0, 0, monthDate.Location())
 		filter.EndDate = filter.StartDate.AddDate(0, 1, 0).Add(-time.Second)
@@ -138,186 +289,440 @@ func runStats(cmd *cobra.Command, args []string) error {
 		if statsSince != "" {
 			sinceDate, err := time.Parse("2006-01-02", statsSince)
 			if err != nil {
-				return fmt.Errorf("invalid since date format, use YYYY-MM-DD: %w", err)
+				return filter, fmt.Errorf("invalid since date format, use YYYY-MM-DD: %w", err)
 			}
 			filter.StartDate = sinceDate
 		}
 		if statsUntil != "" {
 			untilDate, err := time.Parse("2006-01-02", statsUntil)
 			if err != nil {
-				return fmt.Errorf("invalid until date format, use YYYY-MM-DD: %w", err)
+				return filter, fmt.Errorf("invalid until date format, use YYYY-MM-DD: %w", err)
 			}
 			filter.EndDate = untilDate
 		}
 	}
+	return filter, nil
+}
 
-	// Get session stats (new detailed view)
-	sessionStats, err := tracker.GetSessionStats(filter)
-	if err != nil {
-		return fmt.Errorf("failed to get session stats: %w", err)
-	}
-
-	// Export to CSV if requested
-	if statsExport != "" {
-		if err := exportSessionsToCSV(tracker, filter, statsExport); err != nil {
-			return fmt.Errorf("failed to export: %w", err)
-		}
-		fmt.Printf("Exported to %s\n", statsExport)
-		return nil
-	}
-
-	// Display session stats
-	displaySessionStats(sessionStats, filter)
-
-	return nil
+// statsDateFlagsSet reports whether any flag picks the time range, overriding the default one
+func statsDateFlagsSet() bool {
+	return statsToday || statsWeek || statsMonth != "" || statsSince != "" || statsUntil != ""
 }
 
-func displaySessionStats(stats *usage.SessionStats, filter usage.QueryFilter) {
+// displaySessionStats shows the overview, leaving out the sections the view hides
+func displaySessionStats(stats *usage.SessionStats, filter usage.QueryFilter, view usage.ViewSettings, topBy string) {
 	// Determine time period for header
-	timePeriod := "All Time"
+	timePeriod := i18n.T("All Time")
 	if !filter.StartDate.IsZero() || !filter.EndDate.IsZero() {
 		if !filter.StartDate.IsZero() && !filter.EndDate.IsZero() {
-			timePeriod = fmt.Sprintf("%s to %s",
+			timePeriod = i18n.T("%s to %s",
 				filter.StartDate.Format("2006-01-02"),
 				filter.EndDate.Format("2006-01-02"))
 		} else if !filter.StartDate.IsZero() {
-			timePeriod = fmt.Sprintf("Since %s", filter.StartDate.Format("2006-01-02"))
+			timePeriod = i18n.T("Since %s", filter.StartDate.Format("2006-01-02"))
 		} else {
-			timePeriod = fmt.Sprintf("Until %s", filter.EndDate.Format("2006-01-02"))
+			timePeriod = i18n.T("Until %s", filter.EndDate.Format("2006-01-02"))
 		}
 	}
 
 	// Header
-	fmt.Println(headerStyle.Render("📊 Session Statistics") + " " + mutedStyle.Render("("+timePeriod+")"))
+	fmt.Println(headerStyle.Render(accessibility.PlainText("📊 "+i18n.T("Session Statistics"))) + " " + mutedStyle.Render("("+timePeriod+")"))
 	fmt.Println()
 
 	if stats.TotalSessions == 0 {
-		fmt.Println(mutedStyle.Render("No sessions found matching the criteria."))
+		fmt.Println(mutedStyle.Render(i18n.T("No sessions found matching the criteria.")))
 		return
 	}
 
-	// Overall session metrics
+	if view.Shows("overview") {
+		displayOverview(stats)
+	}
+	if view.Shows("tokens") {
+		displayTokenUsage(stats)
+	}
+	if view.Shows("tpm") {
+		displayTPM(stats)
+	}
+	if view.Shows("rpm") {
+		displayRPM(stats)
+	}
+	if view.Shows("cache") {
+		displayCacheEfficiency(stats)
+	}
+	if view.Shows("heavy") {
+		displayHeavyUsage(stats)
+	}
+	if view.Shows("latency") {
+		displayLatency(stats.Latency)
+	}
+
+	// Display by profile
+	if view.Shows("profiles") && len(stats.ProfileBreakdown) > 0 && filter.ProfileName == "" {
+		fmt.Println(sectionHeading(i18n.T("By Profile")))
+		fmt.Println()
+		displayBreakdown(stats.ProfileBreakdown, stats.TotalSessions)
+		fmt.Println()
+	}
+
+	// Display by model
+	if view.Shows("models") && len(stats.ModelBreakdown) > 0 {
+		fmt.Println(sectionHeading(i18n.T("By Model")))
+		fmt.Println()
+		displayBreakdown(stats.ModelBreakdown, stats.TotalSessions)
+		fmt.Println()
+	}
+
+	if view.Shows("top-sessions") {
+		displayTopSessions(stats, topBy)
+	}
+	if view.Shows("hourly") {
+		displayHourlyRequests(stats)
+	}
+	if view.Shows("costs") {
+		displayEstimatedCosts(stats, filter)
+	}
+}
+
+// displayOverview shows the session count and coding time in a box
+// Coding time is active time; wall-clock time also counts idle gaps between requests
+func displayOverview(stats *usage.SessionStats) {
 	overallContent := fmt.Sprintf(
-		"%s %s\n%s %.2f hours\n%s %.1f minutes",
-		labelStyle.Render("Total Sessions:"),
+		"%s %s\n%s %s\n%s %s\n%s %s",
+		labelStyle.Render(i18n.T("Total Sessions:")),
 		valueStyle.Render(formatNumber(int64(stats.TotalSessions))),
-		labelStyle.Render("Total Coding Time:"),
-		stats.TotalDurationHours,
-		labelStyle.Render("Average Session:"),
-		stats.AvgSessionMinutes,
+		labelStyle.Render(i18n.T("Total Coding Time:")),
+		i18n.T("%.2f hours", stats.TotalActiveHours),
+		labelStyle.Render(i18n.T("Wall-Clock Time:")),
+		i18n.T("%.2f hours", stats.TotalDurationHours),
+		labelStyle.Render(i18n.T("Average Session:")),
+		i18n.T("%.1f minutes", stats.AvgActiveMinutes),
 	)
-	fmt.Println(boxStyle.Render(overallContent))
+	if accessibility.Enabled() {
+		fmt.Println(overallContent)
+	} else {
+		fmt.Println(boxStyle.Render(overallContent))
+	}
 	fmt.Println()
+}
 
-	// Token metrics
-	fmt.Println(sectionStyle.Render("▸ Token Usage"))
+// displayTokenUsage shows the request and token totals
+func displayTokenUsage(stats *usage.SessionStats) {
+	fmt.Println(sectionHeading(i18n.T("Token Usage")))
 	fmt.Println()
-	fmt.Printf("  %s %s\n", labelStyle.Render("Total Requests:"), valueStyle.Render(formatNumber(stats.TotalRequests)))
-	fmt.Printf("  %s %s\n", labelStyle.Render("Input Tokens:"), valueStyle.Render(formatNumber(stats.TotalInputTokens)))
-	fmt.Printf("  %s %s\n", labelStyle.Render("Output Tokens:"), valueStyle.Render(formatNumber(stats.TotalOutputTokens)))
-	fmt.Printf("  %s %s\n", labelStyle.Render("Total Tokens:"), highlightStyle.Render(formatNumber(stats.TotalInputTokens+stats.TotalOutputTokens)))
+	fmt.Printf("  %s %s\n", labelStyle.Render(i18n.T("Total Requests:")), valueStyle.Render(formatNumber(stats.TotalRequests)))
+	fmt.Printf("  %s %s\n", labelStyle.Render(i18n.T("Input Tokens:")), valueStyle.Render(formatNumber(stats.TotalInputTokens)))
+	fmt.Printf("  %s %s\n", labelStyle.Render(i18n.T("Output Tokens:")), valueStyle.Render(formatNumber(stats.TotalOutputTokens)))
+	fmt.Printf("  %s %s\n", labelStyle.Render(i18n.T("Total Tokens:")), highlightStyle.Render(formatNumber(stats.TotalInputTokens+stats.TotalOutputTokens)))
 	fmt.Println()
+}
 
-	// TPM/RPM metrics
-	fmt.Println(sectionStyle.Render("▸ Tokens Per Minute (TPM)"))
+// displayTPM shows tokens per minute
+func displayTPM(stats *usage.SessionStats) {
+	fmt.Println(sectionHeading(i18n.T("Tokens Per Minute (TPM)")))
 	fmt.Println()
-	fmt.Printf("  %s %s\n", labelStyle.Render("Average:"), valueStyle.Render(formatFloat(stats.AvgTPM)+" TPM"))
-	fmt.Printf("  %s %s\n", labelStyle.Render("Peak:"), highlightStyle.Render(formatFloat(stats.PeakTPM)+" TPM"))
+	fmt.Printf("  %s %s\n", labelStyle.Render(i18n.T("Average:")), valueStyle.Render(formatFloat(stats.AvgTPM)+" TPM"))
+	fmt.Printf("  %s %s\n", labelStyle.Render(i18n.T("Peak:")), highlightStyle.Render(formatFloat(stats.PeakTPM)+" TPM"))
 	fmt.Printf("  %s %s\n", labelStyle.Render("P95:"), valueStyle.Render(formatFloat(stats.P95TPM)+" TPM"))
 	fmt.Println()
+}
 
-	fmt.Println(sectionStyle.Render("▸ Requests Per Minute (RPM)"))
+// displayRPM shows requests per minute
+func displayRPM(stats *usage.SessionStats) {
+	fmt.Println(sectionHeading(i18n.T("Requests Per Minute (RPM)")))
 	fmt.Println()
-	fmt.Printf("  %s %s\n", labelStyle.Render("Average:"), valueStyle.Render(fmt.Sprintf("%.1f RPM", stats.AvgRPM)))
-	fmt.Printf("  %s %s\n", labelStyle.Render("Peak:"), highlightStyle.Render(fmt.Sprintf("%.1f RPM", stats.PeakRPM)))
+	fmt.Printf("  %s %s\n", labelStyle.Render(i18n.T("Average:")), valueStyle.Render(fmt.Sprintf("%.1f RPM", stats.AvgRPM)))
+	fmt.Printf("  %s %s\n", labelStyle.Render(i18n.T("Peak:")), highlightStyle.Render(fmt.Sprintf("%.1f RPM", stats.PeakRPM)))
 	fmt.Printf("  %s %s\n", labelStyle.Render("P95:"), valueStyle.Render(fmt.Sprintf("%.1f RPM", stats.P95RPM)))
 	fmt.Println()
+}
 
-	// Cache efficiency
-	fmt.Println(sectionStyle.Render("▸ Cache Efficiency"))
+// displayCacheEfficiency shows the average prompt cache hit rate, muted when low
+func displayCacheEfficiency(stats *usage.SessionStats) {
+	fmt.Println(sectionHeading(i18n.T("Cache Efficiency")))
 	fmt.Println()
 	cacheColor := highlightStyle
+	cacheRate := fmt.Sprintf("%.1f%%", stats.AvgCacheHitRate)
 	if stats.AvgCacheHitRate < 30 {
 		cacheColor = mutedStyle
+		if accessibility.Enabled() {
+			cacheRate += " " + i18n.T("(low)")
+		}
 	}
-	fmt.Printf("  %s %s\n", labelStyle.Render("Average Hit Rate:"), cacheColor.Render(fmt.Sprintf("%.1f%%", stats.AvgCacheHitRate)))
+	fmt.Printf("  %s %s\n", labelStyle.Render(i18n.T("Average Hit Rate:")), cacheColor.Render(cacheRate))
 	fmt.Println()
+}
 
-	// Display by profile
-	if len(stats.ProfileBreakdown) > 0 && filter.ProfileName == "" {
-		fmt.Println(sectionStyle.Render("▸ By Profile"))
-		fmt.Println()
-		displayBreakdown(stats.ProfileBreakdown, stats.TotalSessions)
-		fmt.Println()
-	}
+// topSessionTitles head the top sessions for each ranking
+var topSessionTitles = map[string]string{
+	usage.TopByTPM:      "Top Sessions by TPM",
+	usage.TopByTokens:   "Top Sessions by Tokens",
+	usage.TopByCost:     "Top Sessions by Cost",
+	usage.TopByDuration: "Top Sessions by Active Time",
+}
 
-	// Display by model
-	if len(stats.ModelBreakdown) > 0 {
-		fmt.Println(sectionStyle.Render("▸ By Model"))
-		fmt.Println()
-		displayBreakdown(stats.ModelBreakdown, stats.TotalSessions)
-		fmt.Println()
+// displayTopSessions lists the sessions ranked highest by topBy, highlighting the ranked value
+func displayTopSessions(stats *usage.SessionStats, topBy string) {
+	if len(stats.TopSessions) == 0 {
+		return
 	}
 
-	// Display top sessions
-	if len(stats.TopSessions) > 0 {
-		fmt.Println(sectionStyle.Render("▸ Top Sessions by Activity"))
-		fmt.Println()
-		for i, session := range stats.TopSessions {
-			fmt.Printf("  %s %s - %s avg TPM, %s min %s\n",
-				mutedStyle.Render(fmt.Sprintf("%d.", i+1)),
-				valueStyle.Render(session.StartTime.Format("Jan 02 15:04")),
-				highlightStyle.Render(formatFloat(session.AvgTPM)),
-				valueStyle.Render(fmt.Sprintf("%d", session.DurationSeconds/60)),
-				mutedStyle.Render("("+session.Model+")"))
+	fmt.Println(sectionHeading(i18n.T(topSessionTitles[topBy])))
+	fmt.Println()
+	for i, session := range stats.TopSessions {
+		metrics := map[string]string{
+			usage.TopByTPM:      formatFloat(session.AvgTPM) + " avg TPM",
+			usage.TopByTokens:   formatNumber(session.TotalInputTokens+session.TotalOutputTokens) + " tokens",
+			usage.TopByCost:     currency.Format(usage.SessionCost(session)),
+			usage.TopByDuration: fmt.Sprintf("%d min", usage.ActiveSeconds(session)/60),
 		}
-		fmt.Println()
+		parts := make([]string, 0, len(usage.TopByOptions))
+		for _, by := range usage.TopByOptions {
+			if by == topBy {
+				parts = append(parts, highlightStyle.Render(metrics[by]))
+			} else {
+				parts = append(parts, metrics[by])
+			}
+		}
+		fmt.Printf("  %s %s - %s %s\n",
+			mutedStyle.Render(fmt.Sprintf("%d.", i+1)),
+			valueStyle.Render(session.StartTime.Format("Jan 02 15:04")),
+			strings.Join(parts, ", "),
+			mutedStyle.Render("("+session.Model+")"))
 	}
+	fmt.Println()
+}
 
-	// Display estimated costs
-	fmt.Println(sectionStyle.Render("▸ Estimated Costs"))
-	fmt.Println(mutedStyle.Render("  Based on actual token usage"))
+// displayEstimatedCosts shows estimated costs, by the model that served each request
+func displayEstimatedCosts(stats *usage.SessionStats, filter usage.QueryFilter) {
+	fmt.Println(sectionHeading(i18n.T("Estimated Costs")))
+	fmt.Println(mutedStyle.Render("  " + i18n.T("Based on actual token usage")))
 	fmt.Println()
 
 	totalCost := 0.0
-	for model, count := range stats.ModelBreakdown {
-		// Calculate average tokens per session for this model
-		var modelInputTokens, modelOutputTokens int64
-		var modelSessions int
-
-		db, err := usage.NewDatabase()
-		if err == nil {
-			defer db.Close()
-			modelFilter := filter
-			modelFilter.Model = model
-			sessions, err := db.QuerySessions(modelFilter)
-			if err == nil {
-				for _, s := range sessions {
-					modelInputTokens += s.TotalInputTokens
-					modelOutputTokens += s.TotalOutputTokens
-					modelSessions++
-				}
+	approximate := false
+	for _, m := range stats.ModelCosts {
+		totalCost += m.Cost()
+		note := ""
+		if _, ok := pricing.GetModelPrice(m.Model); !ok {
+			if estimate, ok := pricing.EstimateModelPrice(m.Model); ok {
+				note = " " + i18n.T("approximate, priced as %s", estimate.Model)
+				approximate = true
+			} else {
+				note = " " + i18n.T("price unknown, not included")
 			}
 		}
+		fmt.Printf("  %s %s %s\n",
+			labelStyle.Render(m.Model+":"),
+			costStyle.Render(currency.Format(m.Cost())),
+			mutedStyle.Render(i18n.T("(%s requests, %s cache)", formatNumber(m.Requests), currency.Format(m.CacheCost))+note))
+	}
 
-		if modelSessions > 0 {
-			cost := pricing.CalculateCost(model, modelInputTokens, modelOutputTokens)
-			totalCost += cost
-			fmt.Printf("  %s %s %s\n",
-				labelStyle.Render(model+":"),
-				costStyle.Render(fmt.Sprintf("$%.2f", cost)),
-				mutedStyle.Render(fmt.Sprintf("(%d sessions)", count)))
+	totalCost += displayProvisioned(stats, filter)
+
+	if totalCost > 0 {
+		total := costStyle.Render(currency.Format(totalCost))
+		if approximate {
+			total = costStyle.Render("≈ "+currency.Format(totalCost)) + " " + mutedStyle.Render(i18n.T("(includes approximate prices)"))
 		}
+		fmt.Println()
+		fmt.Printf("  %s %s\n", labelStyle.Render(i18n.T("Total Estimated Cost:")), total)
+		fmt.Println(mutedStyle.Render("  " + i18n.T("By day, profile, and model: clauderock manage stats cost")))
 	}
+}
 
-	if totalCost > 0 {
+// displayProvisioned lists the Provisioned Throughput commitments of profiles on that pricing tier,
+// whose tokens are priced at zero above, and returns their cost over the stats period
+func displayProvisioned(stats *usage.SessionStats, filter usage.QueryFilter) float64 {
+	if len(stats.ProvisionedProfiles) == 0 {
+		return 0
+	}
+	mgr, err := profiles.NewManager()
+	if err != nil {
+		return 0
+	}
+
+	periodStart := filter.StartDate
+	if periodStart.IsZero() {
+		periodStart = stats.FirstStart
+	}
+	end := time.Now()
+	if !filter.EndDate.IsZero() && filter.EndDate.Before(end) {
+		end = filter.EndDate
+	}
+
+	var total float64
+	for _, name := range stats.ProvisionedProfiles {
+		label := labelStyle.Render(i18n.T("Provisioned Throughput (%s):", name))
+		cfg, err := mgr.Load(name)
+		if err != nil || cfg.ProvisionedHourlyUSD <= 0 {
+			fmt.Printf("  %s %s\n", label, mutedStyle.Render(i18n.T("covered by commitment, cost unknown")+" (provisioned-hourly-usd)"))
+			continue
+		}
+
+		// Hours before the commitment started weren't paid for
+		start := periodStart
+		if since := cfg.ProvisionedStart(); since.After(start) {
+			start = since
+		}
+		hours := end.Sub(start).Hours()
+		if hours <= 0 {
+			fmt.Printf("  %s %s\n", label, mutedStyle.Render(i18n.T("commitment starts %s, after this period", cfg.ProvisionedSince)))
+			continue
+		}
+		commitment := cfg.ProvisionedHourlyUSD * hours
+		total += commitment
+		fmt.Printf("  %s %s %s\n", label,
+			costStyle.Render(currency.Format(commitment)),
+			mutedStyle.Render(i18n.T("(%.0f hours at %s/hour)", hours, currency.Format(cfg.ProvisionedHourlyUSD))))
+	}
+	return total
+}
+
+// displayHourlyRequests shows when requests are made, from sessions with recorded API calls
+func displayHourlyRequests(stats *usage.SessionStats) {
+	if stats.RecordedCalls == 0 {
+		return
+	}
+
+	busiest := 0
+	for _, n := range stats.HourlyRequests {
+		if n > busiest {
+			busiest = n
+		}
+	}
+
+	fmt.Println(sectionHeading(i18n.T("Requests by Hour")))
+	fmt.Println()
+	for hour, n := range stats.HourlyRequests {
+		if n == 0 {
+			continue
+		}
+		bar := progressBar(float64(n)/float64(busiest)*100, 20)
+		if bar != "" {
+			bar += " "
+		}
+		fmt.Printf("  %s %s%s\n",
+			labelStyle.Render(fmt.Sprintf("%02d:00", hour)),
+			bar,
+			valueStyle.Render(formatNumber(int64(n))))
+	}
+	fmt.Println()
+}
+
+func displayHeavyUsage(stats *usage.SessionStats) {
+	if stats.HeavyRequests == 0 {
+		return
+	}
+
+	fmt.Println(sectionHeading(i18n.T("Heavy Model Usage")))
+	fmt.Println()
+	requestShare := float64(stats.HeavyRequests) / float64(stats.TotalRequests) * 100
+	fmt.Printf("  %s %s %s\n",
+		labelStyle.Render(i18n.T("Heavy Requests:")),
+		valueStyle.Render(formatNumber(stats.HeavyRequests)),
+		mutedStyle.Render(i18n.T("(%.1f%% of %s)", requestShare, formatNumber(stats.TotalRequests))))
+	fmt.Printf("  %s %s %s\n",
+		labelStyle.Render(i18n.T("Share of Cost:")),
+		highlightStyle.Render(fmt.Sprintf("%.1f%%", stats.HeavyCostShare())),
+		mutedStyle.Render(fmt.Sprintf("(%s / %s)", currency.Format(stats.HeavyCost), currency.Format(stats.TotalCost))))
+	fmt.Println()
+
+	for i, session := range stats.HeavySessions {
+		fmt.Printf("  %s %s - %s %s\n",
+			mutedStyle.Render(fmt.Sprintf("%d.", i+1)),
+			valueStyle.Render(session.StartTime.Format("Jan 02 15:04")),
+			i18n.T("%d heavy requests, %.1f%% of its cost", session.HeavyRequests, usage.HeavyCostShare(session)),
+			mutedStyle.Render("("+sessionLabel(session)+")"))
+	}
+	if len(stats.HeavySessions) > 0 {
 		fmt.Println()
-		fmt.Printf("  %s %s\n",
-			labelStyle.Render("Total Estimated Cost:"),
-			costStyle.Render(fmt.Sprintf("$%.2f", totalCost)))
 	}
 }
 
+// displayLatency shows first-token latency per model and region, to compare regions
+func displayLatency(latency []usage.LatencyStats) {
+	if len(latency) == 0 {
+		return
+	}
+
+	fmt.Println(sectionHeading(i18n.T("First-Token Latency")))
+	fmt.Println()
+	for _, l := range latency {
+		region := l.Region
+		if region == "" {
+			region = "API"
+		}
+		fmt.Printf("  %s %s %s\n",
+			labelStyle.Render(fmt.Sprintf("%s (%s):", l.Model, region)),
+			valueStyle.Render(i18n.T("avg %s ms, P95 %s ms", formatFloat(l.AvgMs), formatFloat(l.P95Ms))),
+			mutedStyle.Render(i18n.T("(%d calls)", l.Samples)))
+	}
+	fmt.Println()
+}
+
+// displayAnomalies lists sessions whose tokens or cost spiked above their profile's baseline
+func displayAnomalies(anomalies []usage.Anomaly) {
+	if len(anomalies) == 0 {
+		return
+	}
+
+	fmt.Println()
+	fmt.Println(sectionHeading(i18n.T("Anomalies")))
+	fmt.Println(mutedStyle.Render("  " + i18n.T("Sessions far above their profile's baseline (runaway loops, huge pastes)")))
+	fmt.Println()
+	for _, a := range anomalies {
+		value := formatFloat(a.Value) + " tokens"
+		typical := formatFloat(a.Mean)
+		if a.Metric == "cost" {
+			value = currency.Format(a.Value)
+			typical = currency.Format(a.Mean)
+		}
+		fmt.Printf("  %s %s %s %s\n",
+			valueStyle.Render(a.Session.StartTime.Format("Jan 02 15:04")),
+			costStyle.Render(value),
+			labelStyle.Render(i18n.T("vs typical %s, %.1fσ", typical, a.Sigma)),
+			mutedStyle.Render("("+sessionLabel(a.Session)+")"))
+	}
+}
+
+// displaySessionGroups lists the sessions summed per repository or branch, most expensive first
+func displaySessionGroups(groups []usage.SessionGroup, by string) {
+	title := i18n.T("Sessions by Repository")
+	if by == usage.GroupByBranch {
+		title = i18n.T("Sessions by Branch")
+	}
+	fmt.Println(headerStyle.Render(accessibility.PlainText("📊 " + title)))
+	fmt.Println()
+
+	if len(groups) == 0 {
+		fmt.Println(mutedStyle.Render(i18n.T("No sessions found matching the criteria.")))
+		return
+	}
+
+	width := 0
+	for _, g := range groups {
+		width = max(width, len(g.Name))
+	}
+	for _, g := range groups {
+		fmt.Printf("  %s %s %s\n",
+			labelStyle.Render(fmt.Sprintf("%-*s", width, g.Name)),
+			costStyle.Render(fmt.Sprintf("%10s", currency.Format(g.Cost))),
+			mutedStyle.Render(i18n.T("(%d sessions, %.1f hours, %s requests, %s tokens)",
+				g.Sessions, float64(g.ActiveSeconds)/3600, formatNumber(int64(g.Requests)), formatNumber(g.Tokens))))
+		if by == usage.GroupByRepo && g.RepoURL != "" {
+			fmt.Printf("  %s %s\n", strings.Repeat(" ", width), mutedStyle.Render(g.RepoURL))
+		}
+	}
+}
+
+// sessionLabel identifies a session in lists by its profile, and its name when it has one
+func sessionLabel(s usage.Session) string {
+	if s.Name == "" {
+		return s.ProfileName
+	}
+	return s.ProfileName + ": " + s.Name
+}
+
 func displayBreakdown(breakdown map[string]int, total int) {
 	// Sort by count descending
 	type kv struct {
@@ -335,28 +740,27 @@ func displayBreakdown(breakdown map[string]int, total int) {
 	for _, item := range sorted {
 		percentage := float64(item.Value) / float64(total) * 100
 		bar := progressBar(percentage, 20)
-		fmt.Printf("  %s %s %s %s\n",
+		if bar != "" {
+			bar += " "
+		}
+		fmt.Printf("  %s %s%s %s\n",
 			valueStyle.Render(item.Key+":"),
 			bar,
 			highlightStyle.Render(fmt.Sprintf("%.1f%%", percentage)),
-			mutedStyle.Render(fmt.Sprintf("(%d sessions)", item.Value)))
+			mutedStyle.Render(i18n.T("(%d sessions)", item.Value)))
 	}
 }
 
 func exportSessionsToCSV(tracker *usage.Tracker, filter usage.QueryFilter, filename string) error {
-	// Get raw sessions
-	db, err := usage.NewDatabase()
-	if err != nil {
-		return err
-	}
-	defer db.Close()
-
-	sessions, err := db.QuerySessions(filter)
+	sessions, err := tracker.Sessions(filter)
 	if err != nil {
 		return err
 	}
+	return writeSessionsCSV(sessions, filename)
+}
 
-	// Create CSV file
+// writeSessionsCSV writes one row per session to filename
+func writeSessionsCSV(sessions []usage.Session, filename string) error {
 	file, err := os.Create(filename)
 	if err != nil {
 		return err
@@ -370,6 +774,7 @@ func exportSessionsToCSV(tracker *usage.Tracker, filter usage.QueryFilter, filen
 	header := []string{
 		"Start Time",
 		"Duration (min)",
+		"Active (min)",
 		"Profile Name",
 		"Model",
 		"Requests",
@@ -383,6 +788,14 @@ func exportSessionsToCSV(tracker *usage.Tracker, filter usage.QueryFilter, filen
 		"P95 RPM",
 		"Cache Hit Rate %",
 		"Estimated Cost",
+		"Heavy Requests",
+		"Heavy Cost Share %",
+		"Session Name",
+		"Note",
+		"Repo",
+		"Branch",
+		"Repo URL",
+		"Tags",
 	}
 	if err := writer.Write(header); err != nil {
 		return err
@@ -390,10 +803,11 @@ func exportSessionsToCSV(tracker *usage.Tracker, filter usage.QueryFilter, filen
 
 	// Write data
 	for _, session := range sessions {
-		cost := pricing.CalculateCost(session.Model, session.TotalInputTokens, session.TotalOutputTokens)
+		cost := usage.SessionCost(session)
 		row := []string{
 			session.StartTime.Format("2006-01-02 15:04:05"),
 			fmt.Sprintf("%d", session.DurationSeconds/60),
+			fmt.Sprintf("%d", usage.ActiveSeconds(session)/60),
 			session.ProfileName,
 			session.Model,
 			fmt.Sprintf("%d", session.TotalRequests),
@@ -407,6 +821,14 @@ func exportSessionsToCSV(tracker *usage.Tracker, filter usage.QueryFilter, filen
 			fmt.Sprintf("%.1f", session.P95RPM),
 			fmt.Sprintf("%.1f", session.CacheHitRate),
 			fmt.Sprintf("%.2f", cost),
+			fmt.Sprintf("%d", session.HeavyRequests),
+			fmt.Sprintf("%.1f", usage.HeavyCostShare(session)),
+			session.Name,
+			session.Note,
+			session.Repo,
+			session.Branch,
+			session.RepoURL,
+			session.Tags,
 		}
 		if err := writer.Write(row); err != nil {
 			return err
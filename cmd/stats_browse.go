@@ -0,0 +1,134 @@
+package cmd
+
+import (
+	"encoding/csv"
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/OlaHulleberg/clauderock/internal/interactive"
+	"github.com/OlaHulleberg/clauderock/internal/usage"
+	"github.com/spf13/cobra"
+)
+
+var statsBrowseCmd = &cobra.Command{
+	Use:   "browse",
+	Short: "Browse tracked sessions in an interactive table",
+	Long: `Browse tracked sessions in an interactive table.
+
+Sort by date, cost, tokens, requests, or active time, filter by profile,
+model, or date, and open a session to see the model, tokens, latency, and
+estimated cost of each of its requests.
+
+Keys:
+  enter   Show the session's requests (esc goes back)
+  /       Filter, e.g. "profile:work model:sonnet date:2025-10 name:auth repo:api"
+  s / r   Change the sort column / reverse the order
+  e       Export the sessions shown, or the open session's requests, to CSV
+          in the current directory
+
+The same flags as 'stats' limit which sessions are loaded.
+
+Examples:
+  clauderock manage stats browse
+  clauderock manage stats browse --month 2025-10
+  clauderock manage stats browse --profile work`,
+	Args: cobra.NoArgs,
+	RunE: runStatsBrowse,
+}
+
+func init() {
+	statsCmd.AddCommand(statsBrowseCmd)
+
+	statsBrowseCmd.Flags().StringVar(&statsProfile, "profile", "", "Filter by profile name")
+	statsBrowseCmd.Flags().StringVar(&statsModel, "model", "", "Filter by model")
+	statsBrowseCmd.Flags().StringVar(&statsName, "name", "", "Filter by session name (matches names containing it)")
+	statsBrowseCmd.Flags().StringVar(&statsRepo, "repo", "", "Filter by git repository (e.g., acme/api)")
+	statsBrowseCmd.Flags().StringVar(&statsTag, "tag", "", "Filter by session tag: key=value, or a key for any value")
+	statsBrowseCmd.Flags().StringVar(&statsSince, "since", "", "Filter sessions since date (YYYY-MM-DD)")
+	statsBrowseCmd.Flags().StringVar(&statsUntil, "until", "", "Filter sessions until date (YYYY-MM-DD)")
+	statsBrowseCmd.Flags().StringVar(&statsMonth, "month", "", "Filter by month (YYYY-MM)")
+	statsBrowseCmd.Flags().BoolVar(&statsToday, "today", false, "Show today's sessions only")
+	statsBrowseCmd.Flags().BoolVar(&statsWeek, "week", false, "Show this week's sessions")
+}
+
+func runStatsBrowse(cmd *cobra.Command, args []string) error {
+	filter, err := statsFilter()
+	if err != nil {
+		return err
+	}
+
+	db, err := usage.NewDatabase()
+	if err != nil {
+		return fmt.Errorf("failed to open usage database: %w", err)
+	}
+	defer db.Close()
+
+	sessions, err := db.QuerySessions(filter)
+	if err != nil {
+		return err
+	}
+	calls, err := db.QueryAPICalls(filter)
+	if err != nil {
+		return err
+	}
+
+	return interactive.RunStatsBrowser(sessions, calls, interactive.StatsBrowserOptions{
+		ExportSessions: func(sessions []usage.Session) (string, error) {
+			path := browseExportPath("sessions")
+			return path, writeSessionsCSV(sessions, path)
+		},
+		ExportCalls: func(session usage.Session, calls []usage.APICall) (string, error) {
+			path := browseExportPath("session-" + session.StartTime.Local().Format("20060102-1504"))
+			return path, writeCallsCSV(session, calls, path)
+		},
+	})
+}
+
+// browseExportPath names an export file in the current directory, unique to the second
+func browseExportPath(what string) string {
+	return fmt.Sprintf("clauderock-%s-%s.csv", what, time.Now().Format("20060102-150405"))
+}
+
+// writeCallsCSV writes one row per API call of a session to filename
+func writeCallsCSV(session usage.Session, calls []usage.APICall, filename string) error {
+	file, err := os.Create(filename)
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+
+	writer := csv.NewWriter(file)
+	header := []string{
+		"Time",
+		"Model",
+		"Input Tokens",
+		"Output Tokens",
+		"Cache Read Tokens",
+		"Cache Creation Tokens",
+		"Latency (ms)",
+		"Estimated Cost",
+	}
+	if err := writer.Write(header); err != nil {
+		return err
+	}
+
+	for _, c := range calls {
+		row := []string{
+			c.Timestamp.Local().Format("2006-01-02 15:04:05"),
+			c.Model,
+			fmt.Sprintf("%d", c.InputTokens),
+			fmt.Sprintf("%d", c.OutputTokens),
+			fmt.Sprintf("%d", c.CacheReadTokens),
+			fmt.Sprintf("%d", c.CacheCreationTokens),
+			fmt.Sprintf("%d", c.LatencyMs),
+			fmt.Sprintf("%.4f", usage.CallCost(session, c)),
+		}
+		if err := writer.Write(row); err != nil {
+			return err
+		}
+	}
+
+	writer.Flush()
+	return writer.Error()
+}
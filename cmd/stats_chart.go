@@ -0,0 +1,99 @@
+package cmd
+
+import (
+	"os"
+	"sort"
+	"time"
+
+	"github.com/OlaHulleberg/clauderock/internal/chart"
+	"github.com/OlaHulleberg/clauderock/internal/currency"
+	"github.com/OlaHulleberg/clauderock/internal/usage"
+)
+
+// exportSessionsToChart renders tokens per day and cost per model to a PNG or SVG file
+func exportSessionsToChart(filter usage.QueryFilter, filename string) error {
+	db, err := usage.NewDatabase()
+	if err != nil {
+		return err
+	}
+	defer db.Close()
+
+	sessions, err := db.QuerySessions(filter)
+	if err != nil {
+		return err
+	}
+
+	charts := []chart.BarChart{
+		{
+			Title:  "Tokens per day",
+			Format: func(v float64) string { return formatNumber(int64(v)) },
+			Bars:   tokensPerDay(sessions),
+		},
+		{
+			Title:  "Estimated cost per model",
+			Format: currency.Format,
+			Bars:   costPerModel(sessions),
+		},
+	}
+
+	file, err := os.Create(filename)
+	if err != nil {
+		return err
+	}
+
+	if err := chart.Render(file, filename, charts); err != nil {
+		file.Close()
+		return err
+	}
+	// Close flushes the chart to disk, so a failure here means the file is incomplete
+	return file.Close()
+}
+
+// tokensPerDay totals input and output tokens per calendar day, including days without sessions
+func tokensPerDay(sessions []usage.Session) []chart.Bar {
+	if len(sessions) == 0 {
+		return nil
+	}
+
+	totals := make(map[string]int64)
+	first, last := sessions[0].StartTime, sessions[0].StartTime
+	for _, s := range sessions {
+		totals[s.StartTime.Format("2006-01-02")] += s.TotalInputTokens + s.TotalOutputTokens
+		if s.StartTime.Before(first) {
+			first = s.StartTime
+		}
+		if s.StartTime.After(last) {
+			last = s.StartTime
+		}
+	}
+
+	midnight := func(t time.Time) time.Time {
+		return time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, t.Location())
+	}
+
+	var bars []chart.Bar
+	for day, end := midnight(first), midnight(last); !day.After(end); day = day.AddDate(0, 0, 1) {
+		bars = append(bars, chart.Bar{Label: day.Format("01-02"), Value: float64(totals[day.Format("2006-01-02")])})
+	}
+	return bars
+}
+
+// costPerModel totals estimated cost per model, most expensive first
+func costPerModel(sessions []usage.Session) []chart.Bar {
+	totals := make(map[string]float64)
+	for _, s := range sessions {
+		totals[usage.PriceKey(s.Model)] += usage.SessionCost(s)
+	}
+
+	bars := make([]chart.Bar, 0, len(totals))
+	for model, cost := range totals {
+		bars = append(bars, chart.Bar{Label: model, Value: cost})
+	}
+	sort.Slice(bars, func(i, j int) bool {
+		if bars[i].Value != bars[j].Value {
+			return bars[i].Value > bars[j].Value
+		}
+		return bars[i].Label < bars[j].Label
+	})
+	return bars
+}
@@ -0,0 +1,142 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	"github.com/OlaHulleberg/clauderock/internal/currency"
+	"github.com/OlaHulleberg/clauderock/internal/usage"
+	"github.com/spf13/cobra"
+)
+
+var (
+	comparePeriod  string
+	compareVs      string
+	compareProfile string
+)
+
+var statsCompareCmd = &cobra.Command{
+	Use:   "compare",
+	Short: "Compare usage between two time periods",
+	Long: `Compare sessions, tokens, cost, cache hit rate, and model mix between two
+time periods, with the change from --vs to --period.
+
+Periods are a month (YYYY-MM), a day (YYYY-MM-DD), or a range (YYYY-MM-DD..YYYY-MM-DD).
+
+Examples:
+  clauderock manage stats compare --period 2025-10 --vs 2025-09
+  clauderock manage stats compare --period 2025-10-13..2025-10-19 --vs 2025-10-06..2025-10-12 --profile work`,
+	RunE: runStatsCompare,
+}
+
+func init() {
+	statsCmd.AddCommand(statsCompareCmd)
+
+	statsCompareCmd.Flags().StringVar(&comparePeriod, "period", "", "Period to report on (YYYY-MM, YYYY-MM-DD, or a range)")
+	statsCompareCmd.Flags().StringVar(&compareVs, "vs", "", "Baseline period to compare against")
+	statsCompareCmd.Flags().StringVar(&compareProfile, "profile", "", "Filter by profile name")
+	statsCompareCmd.MarkFlagRequired("period")
+	statsCompareCmd.MarkFlagRequired("vs")
+}
+
+// parsePeriod turns a month, day, or day range into an inclusive time range
+func parsePeriod(period string) (start, end time.Time, err error) {
+	if from, to, ok := strings.Cut(period, ".."); ok {
+		start, err = time.ParseInLocation("2006-01-02", from, time.Local)
+		if err != nil {
+			return start, end, fmt.Errorf("invalid range start '%s', use YYYY-MM-DD", from)
+		}
+		last, err := time.ParseInLocation("2006-01-02", to, time.Local)
+		if err != nil {
+			return start, end, fmt.Errorf("invalid range end '%s', use YYYY-MM-DD", to)
+		}
+		if last.Before(start) {
+			return start, end, fmt.Errorf("range %s ends before it starts", period)
+		}
+		return start, last.AddDate(0, 0, 1).Add(-time.Second), nil
+	}
+
+	if month, err := time.ParseInLocation("2006-01", period, time.Local); err == nil {
+		return month, month.AddDate(0, 1, 0).Add(-time.Second), nil
+	}
+	if day, err := time.ParseInLocation("2006-01-02", period, time.Local); err == nil {
+		return day, day.AddDate(0, 0, 1).Add(-time.Second), nil
+	}
+	return start, end, fmt.Errorf("invalid period '%s', use YYYY-MM, YYYY-MM-DD, or YYYY-MM-DD..YYYY-MM-DD", period)
+}
+
+func runStatsCompare(cmd *cobra.Command, args []string) error {
+	tracker, err := usage.NewTracker()
+	if err != nil {
+		return fmt.Errorf("failed to create tracker: %w", err)
+	}
+	defer tracker.Close()
+
+	summarize := func(period string) (*usage.PeriodSummary, error) {
+		start, end, err := parsePeriod(period)
+		if err != nil {
+			return nil, err
+		}
+		return tracker.SummarizePeriod(usage.QueryFilter{ProfileName: compareProfile, StartDate: start, EndDate: end})
+	}
+
+	before, err := summarize(compareVs)
+	if err != nil {
+		return err
+	}
+	after, err := summarize(comparePeriod)
+	if err != nil {
+		return err
+	}
+
+	fmt.Println(headerStyle.Render(fmt.Sprintf("Usage %s vs %s", comparePeriod, compareVs)))
+	fmt.Println()
+
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	fmt.Fprintf(w, "METRIC\t%s\t%s\tCHANGE\n", compareVs, comparePeriod)
+	fmt.Fprintf(w, "Sessions\t%s\t%s\t%s\n",
+		formatNumber(int64(before.Sessions)), formatNumber(int64(after.Sessions)),
+		formatChange(float64(before.Sessions), float64(after.Sessions)))
+	fmt.Fprintf(w, "Input tokens\t%s\t%s\t%s\n",
+		formatNumber(before.InputTokens), formatNumber(after.InputTokens),
+		formatChange(float64(before.InputTokens), float64(after.InputTokens)))
+	fmt.Fprintf(w, "Output tokens\t%s\t%s\t%s\n",
+		formatNumber(before.OutputTokens), formatNumber(after.OutputTokens),
+		formatChange(float64(before.OutputTokens), float64(after.OutputTokens)))
+	fmt.Fprintf(w, "Total tokens\t%s\t%s\t%s\n",
+		formatNumber(before.Tokens()), formatNumber(after.Tokens()),
+		formatChange(float64(before.Tokens()), float64(after.Tokens())))
+	fmt.Fprintf(w, "Estimated cost\t%s\t%s\t%s\n", currency.Format(before.Cost), currency.Format(after.Cost), formatChange(before.Cost, after.Cost))
+	fmt.Fprintf(w, "Cache hit rate\t%.1f%%\t%.1f%%\t%s\n", before.CacheHitRate, after.CacheHitRate,
+		formatPointChange(before.CacheHitRate, after.CacheHitRate))
+
+	if models := usage.MixModels(before, after); len(models) > 0 {
+		fmt.Fprintf(w, "\t\t\t\n")
+		fmt.Fprintf(w, "MODEL MIX (share of tokens)\t\t\t\n")
+		for _, model := range models {
+			fmt.Fprintf(w, "  %s\t%.1f%%\t%.1f%%\t%s\n", model, before.ModelMix[model], after.ModelMix[model],
+				formatPointChange(before.ModelMix[model], after.ModelMix[model]))
+		}
+	}
+	return w.Flush()
+}
+
+// formatChange formats the relative change between two values
+func formatChange(before, after float64) string {
+	change, ok := usage.PercentChange(before, after)
+	if !ok {
+		if after == 0 {
+			return "-"
+		}
+		return "new"
+	}
+	return fmt.Sprintf("%+.1f%%", change)
+}
+
+// formatPointChange formats the difference between two percentages in percentage points
+func formatPointChange(before, after float64) string {
+	return fmt.Sprintf("%+.1f pts", after-before)
+}
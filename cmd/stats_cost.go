@@ -0,0 +1,150 @@
+package cmd
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/OlaHulleberg/clauderock/internal/accessibility"
+	"github.com/OlaHulleberg/clauderock/internal/currency"
+	"github.com/OlaHulleberg/clauderock/internal/usage"
+	"github.com/spf13/cobra"
+)
+
+var (
+	costDays    int
+	costWeekly  bool
+	costProfile string
+	costModel   string
+	costRepo    string
+	costTag     string
+)
+
+var statsCostCmd = &cobra.Command{
+	Use:   "cost",
+	Short: "Report estimated spend by day, profile, model, and project",
+	Long: `Report the estimated spend of tracked sessions by day (or week), by profile, by
+model, and by project (the git repository a session ran in), with trend
+sparklines, plus this month's spend so far and where it is heading at the
+current pace.
+
+Costs are estimated per session from its token counts, prompt cache reads and
+writes included, the same way budgets are checked.
+
+Examples:
+  clauderock manage stats cost
+  clauderock manage stats cost --days 7 --profile work
+  clauderock manage stats cost --weekly --days 90
+  clauderock manage stats cost --tag team=payments`,
+	Args: cobra.NoArgs,
+	RunE: runStatsCost,
+}
+
+func init() {
+	statsCmd.AddCommand(statsCostCmd)
+
+	statsCostCmd.Flags().IntVar(&costDays, "days", 30, "Number of days to report on, ending today")
+	statsCostCmd.Flags().BoolVar(&costWeekly, "weekly", false, "Break spend down by week (starting Monday) instead of by day")
+	statsCostCmd.Flags().StringVar(&costProfile, "profile", "", "Filter by profile name")
+	statsCostCmd.Flags().StringVar(&costModel, "model", "", "Filter by model")
+	statsCostCmd.Flags().StringVar(&costRepo, "repo", "", "Filter by git repository (e.g., acme/api)")
+	statsCostCmd.Flags().StringVar(&costTag, "tag", "", "Filter by session tag: key=value, or a key for any value")
+}
+
+func runStatsCost(cmd *cobra.Command, args []string) error {
+	tracker, err := usage.NewTracker()
+	if err != nil {
+		return fmt.Errorf("failed to create tracker: %w", err)
+	}
+	defer tracker.Close()
+
+	if costTag != "" {
+		if err := usage.CheckTagFilter(costTag); err != nil {
+			return err
+		}
+	}
+	filter := usage.QueryFilter{ProfileName: costProfile, Model: costModel, Repo: costRepo, Tag: costTag}
+	report, err := tracker.CostReport(filter, costDays, costWeekly, time.Now())
+	if err != nil {
+		return err
+	}
+
+	fmt.Println(headerStyle.Render(accessibility.PlainText("💰 Cost Report")) + " " +
+		mutedStyle.Render(fmt.Sprintf("(%s to %s)", report.Start.Format("2006-01-02"), report.End.Format("2006-01-02"))))
+	fmt.Println()
+
+	fmt.Println(sectionHeading("Totals"))
+	fmt.Printf("  %s %s\n", labelStyle.Render("This period:    "), costStyle.Render(currency.Format(report.Total)))
+	fmt.Printf("  %s %s\n", labelStyle.Render("Month to date:  "), costStyle.Render(currency.Format(report.MonthToDate)))
+	fmt.Printf("  %s %s %s\n", labelStyle.Render("Projected month:"), costStyle.Render(currency.Format(report.ProjectedMonth)),
+		mutedStyle.Render("(at this month's pace so far)"))
+	fmt.Println()
+
+	if report.Total == 0 {
+		fmt.Println(mutedStyle.Render("No tracked spend in this period."))
+		return nil
+	}
+
+	heading, dateFormat := "By Day", "Mon 2006-01-02"
+	if report.Weekly {
+		heading, dateFormat = "By Week", "Week of 2006-01-02"
+	}
+	fmt.Println(sectionHeading(heading))
+	peak := 0.0
+	for _, b := range report.Buckets {
+		peak = max(peak, b.Cost)
+	}
+	for _, b := range report.Buckets {
+		fmt.Printf("  %s %s %s\n", labelStyle.Render(b.Start.Format(dateFormat)), progressBar(b.Cost/peak*100, 20), costStyle.Render(currency.Format(b.Cost)))
+	}
+	fmt.Println()
+
+	displayGroupCosts("By Profile", report.Profiles, report.Total)
+	displayGroupCosts("By Model", report.Models, report.Total)
+	if len(report.Projects) > 0 {
+		displayGroupCosts("By Project", report.Projects, report.Total)
+	}
+	return nil
+}
+
+// displayGroupCosts lists the spend of each profile, model, or project with its trend over the report
+func displayGroupCosts(heading string, groups []usage.GroupCost, total float64) {
+	fmt.Println(sectionHeading(heading))
+	width := 0
+	for _, g := range groups {
+		width = max(width, len(g.Name))
+	}
+	for _, g := range groups {
+		fmt.Printf("  %s %s %s %s\n",
+			labelStyle.Render(fmt.Sprintf("%-*s", width, g.Name)),
+			sparkline(g.Trend),
+			costStyle.Render(fmt.Sprintf("%10s", currency.Format(g.Cost))),
+			mutedStyle.Render(fmt.Sprintf("(%.1f%%)", g.Cost/total*100)))
+	}
+	fmt.Println()
+}
+
+// sparklineLevels are the block characters of a sparkline, lowest first
+var sparklineLevels = []rune("▁▂▃▄▅▆▇█")
+
+// sparkline draws values as a row of block characters scaled to the largest value
+// In accessible mode it is omitted, like progress bars; the totals next to it remain
+func sparkline(values []float64) string {
+	if accessibility.Enabled() {
+		return ""
+	}
+	peak := 0.0
+	for _, v := range values {
+		peak = max(peak, v)
+	}
+
+	var b strings.Builder
+	for _, v := range values {
+		level := 0
+		if peak > 0 {
+			level = int(v / peak * float64(len(sparklineLevels)-1))
+		}
+		b.WriteRune(sparklineLevels[level])
+	}
+	return mutedStyle.Render(b.String())
+}
@@ -0,0 +1,49 @@
+package cmd
+
+import (
+	"fmt"
+
+	"github.com/OlaHulleberg/clauderock/internal/usage"
+	"github.com/spf13/cobra"
+)
+
+var statsRepairCmd = &cobra.Command{
+	Use:   "repair",
+	Short: "Record sessions that clauderock was closed before tracking",
+	Long: `Record sessions whose clauderock was killed while Claude Code ran, for example
+because the terminal window was closed.
+
+Every launch journals its session in ~/.clauderock/sessions before starting
+Claude Code. A session that was never tracked is recorded from its Claude Code
+log, ending at the last time clauderock was seen running, with exit code -1.
+The next launch does this automatically; repair does it without launching.
+
+Examples:
+  clauderock manage stats repair`,
+	Args: cobra.NoArgs,
+	RunE: runStatsRepair,
+}
+
+func init() {
+	statsCmd.AddCommand(statsRepairCmd)
+}
+
+func runStatsRepair(cmd *cobra.Command, args []string) error {
+	tracker, err := usage.NewTracker()
+	if err != nil {
+		return fmt.Errorf("failed to open usage database: %w", err)
+	}
+	defer tracker.Close()
+
+	recovered, err := tracker.RecoverSessions()
+	if recovered > 0 {
+		fmt.Printf("✓ Recorded %d interrupted session(s)\n", recovered)
+	}
+	if err != nil {
+		return err
+	}
+	if recovered == 0 {
+		fmt.Println(mutedStyle.Render("No interrupted sessions to record."))
+	}
+	return nil
+}
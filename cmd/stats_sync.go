@@ -0,0 +1,99 @@
+package cmd
+
+import (
+	"fmt"
+
+	"github.com/OlaHulleberg/clauderock/internal/profiles"
+	"github.com/OlaHulleberg/clauderock/internal/usage"
+	"github.com/spf13/cobra"
+)
+
+var (
+	statsSyncProfile string
+	statsSyncStatus  bool
+)
+
+var statsSyncCmd = &cobra.Command{
+	Use:   "sync",
+	Short: "Upload anonymized session records to the profile's team-sync target",
+	Long: `Upload the sessions recorded since the last sync to the shared S3 bucket or
+DynamoDB table set with 'manage config set team-sync'.
+
+Sessions are synced automatically as each one ends; this retries sessions a
+failed sync left behind, or uploads history recorded before team-sync was set.
+Records carry an anonymous per-installation developer ID, the model, tokens,
+durations, and estimated cost, but never the working directory, session name,
+note, or profile name.
+
+Examples:
+  clauderock manage stats sync
+  clauderock manage stats sync --profile work
+  clauderock manage stats sync --status`,
+	Args: cobra.NoArgs,
+	RunE: runStatsSync,
+}
+
+func init() {
+	statsSyncCmd.Flags().StringVar(&statsSyncProfile, "profile", "", "Profile to sync (defaults to the active profile)")
+	statsSyncCmd.Flags().BoolVar(&statsSyncStatus, "status", false, "Show the target and pending sessions without uploading")
+	statsCmd.AddCommand(statsSyncCmd)
+}
+
+func runStatsSync(cmd *cobra.Command, args []string) error {
+	mgr, err := profiles.NewManager()
+	if err != nil {
+		return fmt.Errorf("failed to create profile manager: %w", err)
+	}
+
+	name := statsSyncProfile
+	if name == "" {
+		name, err = mgr.GetCurrent()
+		if err != nil {
+			return fmt.Errorf("failed to get current profile: %w", err)
+		}
+	}
+
+	cfg, err := mgr.Load(name)
+	if err != nil {
+		return fmt.Errorf("failed to load profile '%s': %w", name, err)
+	}
+	if cfg.TeamSync == "" {
+		return fmt.Errorf("profile '%s' has no team-sync target; set one with 'clauderock manage config set team-sync s3://bucket/prefix'", name)
+	}
+	if _, _, err := cfg.TeamSyncTarget(); err != nil {
+		return err
+	}
+
+	tracker, err := usage.NewTracker()
+	if err != nil {
+		return fmt.Errorf("failed to open usage database: %w", err)
+	}
+	defer tracker.Close()
+
+	if statsSyncStatus {
+		developerID, err := usage.TeamDeveloperID()
+		if err != nil {
+			return err
+		}
+		pending, err := tracker.PendingTeamSync(name, cfg)
+		if err != nil {
+			return err
+		}
+		fmt.Printf("%s %s\n", labelStyle.Render("Target:      "), valueStyle.Render(cfg.TeamSync))
+		fmt.Printf("%s %s\n", labelStyle.Render("Developer ID:"), valueStyle.Render(developerID))
+		fmt.Printf("%s %s\n", labelStyle.Render("Pending:     "), valueStyle.Render(fmt.Sprintf("%d session(s)", pending)))
+		return nil
+	}
+
+	uploaded, err := tracker.SyncTeamUsage(name, cfg)
+	if uploaded > 0 {
+		fmt.Printf("✓ Uploaded %d session(s) to %s\n", uploaded, cfg.TeamSync)
+	}
+	if err != nil {
+		return err
+	}
+	if uploaded == 0 {
+		fmt.Println(mutedStyle.Render("No new sessions to sync."))
+	}
+	return nil
+}
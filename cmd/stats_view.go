@@ -0,0 +1,227 @@
+package cmd
+
+import (
+	"fmt"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/OlaHulleberg/clauderock/internal/usage"
+	"github.com/spf13/cobra"
+)
+
+var statsViewCmd = &cobra.Command{
+	Use:   "view",
+	Short: "Show and set what 'manage stats' shows by default",
+	Long: `Show and set what 'manage stats' shows without flags: its time range, the
+sections left out, and how many top sessions it lists. Date flags such as
+--today or --month, and --all, override the default range for one run.
+
+Settings are saved in ~/.clauderock/stats-view.json.
+
+Sections: ` + strings.Join(usage.Sections, ", ") + `
+
+Examples:
+  clauderock manage stats view
+  clauderock manage stats view range 30d
+  clauderock manage stats view hide tpm rpm latency
+  clauderock manage stats view show tpm
+  clauderock manage stats view top 10
+  clauderock manage stats view reset`,
+	Args: cobra.NoArgs,
+	RunE: runStatsViewShow,
+}
+
+var statsViewRangeCmd = &cobra.Command{
+	Use:   "range <all|today|week|month|Nd>",
+	Short: "Set the default time range",
+	Long: `Set the default time range: all time, today, this week, this month, or the
+last number of days (e.g., 30d).`,
+	Args: cobra.ExactArgs(1),
+	RunE: runStatsViewRange,
+}
+
+var statsViewHideCmd = &cobra.Command{
+	Use:   "hide <section>...",
+	Short: "Leave sections out of the stats overview",
+	Args:  cobra.MinimumNArgs(1),
+	RunE:  runStatsViewHide,
+}
+
+var statsViewShowCmd = &cobra.Command{
+	Use:   "show <section>...",
+	Short: "Bring hidden sections back",
+	Args:  cobra.MinimumNArgs(1),
+	RunE:  runStatsViewShowSections,
+}
+
+var statsViewTopCmd = &cobra.Command{
+	Use:   "top <count>",
+	Short: "Set how many top sessions are listed",
+	Args:  cobra.ExactArgs(1),
+	RunE:  runStatsViewTop,
+}
+
+var statsViewResetCmd = &cobra.Command{
+	Use:   "reset",
+	Short: "Go back to all time, every section, and five top sessions",
+	Args:  cobra.NoArgs,
+	RunE:  runStatsViewReset,
+}
+
+func init() {
+	statsViewCmd.AddCommand(statsViewRangeCmd)
+	statsViewCmd.AddCommand(statsViewHideCmd)
+	statsViewCmd.AddCommand(statsViewShowCmd)
+	statsViewCmd.AddCommand(statsViewTopCmd)
+	statsViewCmd.AddCommand(statsViewResetCmd)
+	statsCmd.AddCommand(statsViewCmd)
+}
+
+// todayRange covers the whole of now's day
+func todayRange(now time.Time) (time.Time, time.Time) {
+	start := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, now.Location())
+	return start, time.Date(now.Year(), now.Month(), now.Day(), 23, 59, 59, 0, now.Location())
+}
+
+// weekRange covers now's week so far, starting Monday
+func weekRange(now time.Time) (time.Time, time.Time) {
+	weekday := int(now.Weekday())
+	if weekday == 0 {
+		weekday = 7 // Sunday
+	}
+	startOfWeek := now.AddDate(0, 0, -(weekday - 1))
+	return time.Date(startOfWeek.Year(), startOfWeek.Month(), startOfWeek.Day(), 0, 0, 0, 0, startOfWeek.Location()), now
+}
+
+// applyViewRange sets the filter's dates from a default time range
+func applyViewRange(filter *usage.QueryFilter, r string) error {
+	r, days, err := usage.ParseRange(r)
+	if err != nil {
+		return err
+	}
+	now := time.Now()
+	switch {
+	case r == usage.RangeToday:
+		filter.StartDate, filter.EndDate = todayRange(now)
+	case r == usage.RangeWeek:
+		filter.StartDate, filter.EndDate = weekRange(now)
+	case r == usage.RangeMonth:
+		filter.StartDate = time.Date(now.Year(), now.Month(), 1, 0, 0, 0, 0, now.Location())
+		filter.EndDate = now
+	case days > 0:
+		start := now.AddDate(0, 0, -(days - 1))
+		filter.StartDate = time.Date(start.Year(), start.Month(), start.Day(), 0, 0, 0, 0, start.Location())
+		filter.EndDate = now
+	}
+	return nil
+}
+
+func runStatsViewShow(cmd *cobra.Command, args []string) error {
+	view, err := usage.LoadViewSettings()
+	if err != nil {
+		return err
+	}
+	r, _, err := usage.ParseRange(view.Range)
+	if err != nil {
+		return err
+	}
+	hidden := "none"
+	if len(view.Hidden) > 0 {
+		hidden = strings.Join(view.Hidden, ", ")
+	}
+
+	fmt.Printf("%s %s\n", labelStyle.Render("Range:       "), valueStyle.Render(r))
+	fmt.Printf("%s %s\n", labelStyle.Render("Hidden:      "), valueStyle.Render(hidden))
+	fmt.Printf("%s %s\n", labelStyle.Render("Top sessions:"), valueStyle.Render(strconv.Itoa(view.TopSessionCount())))
+	return nil
+}
+
+func runStatsViewRange(cmd *cobra.Command, args []string) error {
+	r, _, err := usage.ParseRange(args[0])
+	if err != nil {
+		return err
+	}
+	view, err := usage.LoadViewSettings()
+	if err != nil {
+		return err
+	}
+	view.Range = r
+	if r == usage.RangeAll {
+		view.Range = ""
+	}
+	if err := usage.SaveViewSettings(view); err != nil {
+		return err
+	}
+	fmt.Printf("✓ Stats default to: %s\n", r)
+	return nil
+}
+
+func runStatsViewHide(cmd *cobra.Command, args []string) error {
+	view, err := usage.LoadViewSettings()
+	if err != nil {
+		return err
+	}
+	for _, section := range args {
+		if err := usage.CheckSection(section); err != nil {
+			return err
+		}
+		if !slices.Contains(view.Hidden, section) {
+			view.Hidden = append(view.Hidden, section)
+		}
+	}
+	if err := usage.SaveViewSettings(view); err != nil {
+		return err
+	}
+	fmt.Printf("✓ Hidden: %s\n", strings.Join(view.Hidden, ", "))
+	return nil
+}
+
+func runStatsViewShowSections(cmd *cobra.Command, args []string) error {
+	view, err := usage.LoadViewSettings()
+	if err != nil {
+		return err
+	}
+	for _, section := range args {
+		if err := usage.CheckSection(section); err != nil {
+			return err
+		}
+	}
+	view.Hidden = slices.DeleteFunc(view.Hidden, func(section string) bool {
+		return slices.Contains(args, section)
+	})
+	if err := usage.SaveViewSettings(view); err != nil {
+		return err
+	}
+	fmt.Printf("✓ Showing: %s\n", strings.Join(args, ", "))
+	return nil
+}
+
+func runStatsViewTop(cmd *cobra.Command, args []string) error {
+	count, err := strconv.Atoi(args[0])
+	if err != nil || count < 1 || count > usage.MaxTopSessions {
+		return fmt.Errorf("invalid count '%s': must be between 1 and %d", args[0], usage.MaxTopSessions)
+	}
+	view, err := usage.LoadViewSettings()
+	if err != nil {
+		return err
+	}
+	view.TopSessions = count
+	if count == usage.DefaultTopSessions {
+		view.TopSessions = 0
+	}
+	if err := usage.SaveViewSettings(view); err != nil {
+		return err
+	}
+	fmt.Printf("✓ Listing %d top sessions\n", count)
+	return nil
+}
+
+func runStatsViewReset(cmd *cobra.Command, args []string) error {
+	if err := usage.SaveViewSettings(usage.ViewSettings{}); err != nil {
+		return err
+	}
+	fmt.Println("✓ Stats show all time, every section, and five top sessions")
+	return nil
+}
@@ -0,0 +1,134 @@
+package cmd
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"os"
+	"runtime"
+
+	"github.com/OlaHulleberg/clauderock/internal/policy"
+	"github.com/OlaHulleberg/clauderock/internal/telemetry"
+	"github.com/spf13/cobra"
+)
+
+var telemetryCmd = &cobra.Command{
+	Use:   "telemetry",
+	Short: "Manage anonymous usage telemetry (off by default)",
+	Long: `Manage anonymous usage telemetry.
+
+Telemetry is strictly opt-in and off by default. When enabled, clauderock
+records the version, OS, architecture, command name, and a coarse error class
+(never arguments, paths, profile names, or error messages). Events are
+aggregated locally and sent in batches. DO_NOT_TRACK=1 always disables it.`,
+}
+
+var telemetryStatusCmd = &cobra.Command{
+	Use:   "status",
+	Short: "Show whether telemetry is enabled and what is buffered",
+	RunE: func(cmd *cobra.Command, args []string) error {
+		settings, err := telemetry.LoadSettings()
+		if err != nil {
+			return err
+		}
+
+		state := "disabled"
+		if settings.Enabled {
+			state = "enabled"
+		}
+		if telemetry.DoNotTrack() {
+			state += " (overridden by DO_NOT_TRACK)"
+		}
+		fmt.Printf("Telemetry: %s\n", state)
+
+		endpoint := telemetry.ResolvedEndpoint()
+		if endpoint == "" {
+			endpoint = "none configured (events stay on this machine)"
+		}
+		fmt.Printf("Endpoint:  %s\n", endpoint)
+
+		buffer, err := telemetry.LoadBuffer()
+		if err != nil {
+			return err
+		}
+		fmt.Printf("Buffered:  %d events\n", buffer.Total())
+		for _, e := range buffer.Events {
+			fmt.Printf("  %3d × %s [%s] %s/%s %s\n", e.Count, e.Command, e.ErrorClass, e.OS, e.Arch, e.Version)
+		}
+		return nil
+	},
+}
+
+var telemetryEnableCmd = &cobra.Command{
+	Use:   "enable",
+	Short: "Opt in to anonymous usage telemetry",
+	RunE: func(cmd *cobra.Command, args []string) error {
+		if err := telemetry.SetEnabled(true); err != nil {
+			return err
+		}
+		fmt.Println("Telemetry enabled. Thank you! Disable any time with: clauderock manage telemetry disable")
+		return nil
+	},
+}
+
+var telemetryDisableCmd = &cobra.Command{
+	Use:   "disable",
+	Short: "Opt out of telemetry and delete buffered events",
+	RunE: func(cmd *cobra.Command, args []string) error {
+		if err := telemetry.SetEnabled(false); err != nil {
+			return err
+		}
+		fmt.Println("Telemetry disabled and buffered events deleted")
+		return nil
+	},
+}
+
+func init() {
+	// Registered by manage.go
+
+	telemetryCmd.AddCommand(telemetryStatusCmd)
+	telemetryCmd.AddCommand(telemetryEnableCmd)
+	telemetryCmd.AddCommand(telemetryDisableCmd)
+}
+
+// recordTelemetry buffers an anonymous event for the command that ran, if telemetry is enabled
+// The prompt segment runs on every shell prompt and is left out to keep it fast
+func recordTelemetry(cmd *cobra.Command, err error) {
+	if cmd == nil || cmd == telemetryDisableCmd || cmd == integratePromptCmd {
+		return
+	}
+	// Plugin command names are the user's own, so they're reported generically
+	command := cmd.CommandPath()
+	if cmd.Annotations[pluginAnnotation] != "" {
+		command = "clauderock manage <plugin>"
+	}
+	telemetry.Record(telemetry.Event{
+		Version:    Version,
+		OS:         runtime.GOOS,
+		Arch:       runtime.GOARCH,
+		Command:    command,
+		ErrorClass: errorClass(err),
+	})
+}
+
+// errorClass reduces an error to a coarse category that carries no user data
+func errorClass(err error) string {
+	var netErr net.Error
+	switch {
+	case err == nil:
+		return "none"
+	case errors.Is(err, policy.ErrViolation):
+		return "policy"
+	case errors.Is(err, context.DeadlineExceeded):
+		return "timeout"
+	case errors.As(err, &netErr):
+		return "network"
+	case errors.Is(err, os.ErrPermission):
+		return "permission"
+	case errors.Is(err, os.ErrNotExist):
+		return "not-found"
+	default:
+		return "other"
+	}
+}
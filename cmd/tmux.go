@@ -0,0 +1,162 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"os/exec"
+	"strings"
+	"time"
+
+	"github.com/OlaHulleberg/clauderock/internal/profiles"
+	"github.com/spf13/cobra"
+)
+
+const tmuxSessionName = "clauderock"
+
+var (
+	tmuxProfile string
+	tmuxRefresh int
+	tmuxNoStats bool
+)
+
+var tmuxCmd = &cobra.Command{
+	Use:   "tmux [-- claude args...]",
+	Short: "Open Claude in a tmux window with a stats pane",
+	Long: `Open a tmux window running Claude via clauderock, with a side pane showing
+today's usage stats for the profile, redrawn when a session is recorded.
+
+The window is named after the profile and tagged with the tmux window option
+@clauderock-profile. Inside tmux, the window is added to the current session;
+otherwise a "clauderock" session is created (or reused) and attached.
+
+Examples:
+  clauderock tmux
+  clauderock tmux --profile work -- --continue`,
+	RunE: runTmux,
+}
+
+func init() {
+	rootCmd.AddCommand(tmuxCmd)
+
+	tmuxCmd.Flags().StringVar(&tmuxProfile, "profile", "", "Use a specific clauderock profile (default: current)")
+	tmuxCmd.Flags().IntVar(&tmuxRefresh, "refresh", 30, "How often the stats pane checks for newly recorded sessions, in seconds")
+	tmuxCmd.Flags().BoolVar(&tmuxNoStats, "no-stats", false, "Don't open the stats pane")
+}
+
+func runTmux(cmd *cobra.Command, args []string) error {
+	tmuxPath, err := exec.LookPath("tmux")
+	if err != nil {
+		return fmt.Errorf("tmux not found in PATH: %w", err)
+	}
+
+	if tmuxRefresh < 1 {
+		return fmt.Errorf("--refresh must be at least 1 second")
+	}
+
+	profileName := tmuxProfile
+	mgr, err := profiles.NewManager()
+	if err != nil {
+		return fmt.Errorf("failed to create profile manager: %w", err)
+	}
+	if profileName == "" {
+		profileName, err = mgr.GetCurrent()
+		if err != nil {
+			return fmt.Errorf("failed to get current profile: %w", err)
+		}
+	} else if _, err := mgr.Load(profileName); err != nil {
+		return fmt.Errorf("failed to load profile '%s': %w", profileName, err)
+	}
+
+	self, err := os.Executable()
+	if err != nil {
+		return fmt.Errorf("failed to locate clauderock executable: %w", err)
+	}
+
+	cwd, err := os.Getwd()
+	if err != nil {
+		return fmt.Errorf("failed to get working directory: %w", err)
+	}
+
+	// Command lines run by tmux through the shell
+	claudeLine := shellJoin(append([]string{self, "--clauderock-profile", profileName}, args...))
+	statsLine := shellJoin([]string{self, "manage", "stats", "--profile", profileName, "--today",
+		"--watch", (time.Duration(tmuxRefresh) * time.Second).String()})
+	windowName := "claude:" + profileName
+
+	// Create the Claude window in the current session, an existing clauderock session, or a new one
+	insideTmux := os.Getenv("TMUX") != ""
+	attach := false
+	var windowID string
+	switch {
+	case insideTmux:
+		windowID, err = tmuxOutput(tmuxPath, "new-window", "-P", "-F", "#{window_id}", "-c", cwd, "-n", windowName, claudeLine)
+	case exec.Command(tmuxPath, "has-session", "-t", tmuxSessionName).Run() == nil:
+		attach = true
+		windowID, err = tmuxOutput(tmuxPath, "new-window", "-P", "-F", "#{window_id}", "-t", tmuxSessionName+":", "-c", cwd, "-n", windowName, claudeLine)
+	default:
+		attach = true
+		windowID, err = tmuxOutput(tmuxPath, "new-session", "-d", "-P", "-F", "#{window_id}", "-s", tmuxSessionName, "-c", cwd, "-n", windowName, claudeLine)
+	}
+	if err != nil {
+		return fmt.Errorf("failed to open tmux window: %w", err)
+	}
+
+	// Tag the window so scripts and status lines can find clauderock windows by profile
+	if _, err := tmuxOutput(tmuxPath, "set-option", "-w", "-t", windowID, "@clauderock-profile", profileName); err != nil {
+		return fmt.Errorf("failed to tag tmux window: %w", err)
+	}
+
+	if !tmuxNoStats {
+		// -d keeps focus on the Claude pane
+		if _, err := tmuxOutput(tmuxPath, "split-window", "-d", "-h", "-l", "35%", "-t", windowID, "-c", cwd, statsLine); err != nil {
+			return fmt.Errorf("failed to open stats pane: %w", err)
+		}
+	}
+
+	if !attach {
+		return nil
+	}
+
+	attachCmd := exec.Command(tmuxPath, "attach-session", "-t", windowID)
+	attachCmd.Stdin = os.Stdin
+	attachCmd.Stdout = os.Stdout
+	attachCmd.Stderr = os.Stderr
+	return attachCmd.Run()
+}
+
+// tmuxOutput runs a tmux command and returns its trimmed stdout
+func tmuxOutput(tmuxPath string, args ...string) (string, error) {
+	out, err := exec.Command(tmuxPath, args...).Output()
+	if err != nil {
+		if exitErr, ok := err.(*exec.ExitError); ok && len(exitErr.Stderr) > 0 {
+			return "", fmt.Errorf("%s", strings.TrimSpace(string(exitErr.Stderr)))
+		}
+		return "", err
+	}
+	return strings.TrimSpace(string(out)), nil
+}
+
+// shellJoin quotes each argument for POSIX shells and joins them with spaces
+func shellJoin(args []string) string {
+	quoted := make([]string, len(args))
+	for i, arg := range args {
+		quoted[i] = "'" + strings.ReplaceAll(arg, "'", `'\''`) + "'"
+	}
+	return strings.Join(quoted, " ")
+}
+
+// shellArg quotes an argument of a command shown for copying, such as a profile name with
+// spaces in it, and leaves plain ones readable
+func shellArg(arg string) string {
+	plain := arg != ""
+	for _, r := range arg {
+		if !(r >= 'a' && r <= 'z' || r >= 'A' && r <= 'Z' || r >= '0' && r <= '9' || strings.ContainsRune("-_.+@%=:,/", r)) {
+			plain = false
+			break
+		}
+	}
+	if plain {
+		return arg
+	}
+	return shellJoin([]string{arg})
+}
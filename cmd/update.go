@@ -1,16 +1,112 @@
 package cmd
 
 import (
+	"fmt"
+
 	"github.com/OlaHulleberg/clauderock/internal/updater"
 	"github.com/spf13/cobra"
 )
 
+var updateVersion string
+
 var updateCmd = &cobra.Command{
 	Use:   "update",
 	Short: "Check for updates and install if available",
+	Long: `Check for updates and install if available.
+
+--version installs a specific release instead, including an older one. While a
+version is pinned, update installs that version and no upgrade notices are
+shown at launch. The binary an update replaces is kept, so rollback can
+restore it.
+
+Examples:
+  clauderock manage update
+  clauderock manage update --version v1.2.3
+  clauderock manage update rollback
+  clauderock manage update pin v1.2.3
+  clauderock manage update unpin`,
+	Args: cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		version := ""
+		if updateVersion != "" {
+			var err error
+			if version, err = updater.NormalizeVersion(updateVersion); err != nil {
+				return err
+			}
+		}
+		return updater.Update(Version, version)
+	},
+}
+
+var updateRollbackCmd = &cobra.Command{
+	Use:   "rollback",
+	Short: "Restore the version the last update replaced",
+	Long: `Restore the binary the last update replaced. The binary it replaces becomes
+the backup in turn, so running rollback again returns to where you were.`,
+	Args: cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		restored, err := updater.Rollback(Version)
+		if err != nil {
+			return err
+		}
+		if restored == "" {
+			restored = "the previous version"
+		}
+		fmt.Printf("✓ Rolled back to %s (from %s)\n", restored, Version)
+		if settings, err := updater.LoadSettings(); err == nil && settings.PinnedVersion != "" {
+			fmt.Printf("Version %s is pinned; 'clauderock manage update' installs it again\n", settings.PinnedVersion)
+		}
+		return nil
+	},
+}
+
+var updatePinCmd = &cobra.Command{
+	Use:   "pin [version]",
+	Short: "Stay on a version and stop upgrade notices",
+	Long: `Pin clauderock to a release, the running version unless one is given.
+'manage update' then installs that version rather than the latest, and no
+upgrade notices are shown at launch. 'manage update --version' still installs
+any release.`,
+	Args: cobra.MaximumNArgs(1),
 	RunE: func(cmd *cobra.Command, args []string) error {
-		return updater.Update(Version)
+		version := Version
+		if len(args) == 1 {
+			var err error
+			if version, err = updater.NormalizeVersion(args[0]); err != nil {
+				return err
+			}
+		} else if Version == "dev" {
+			return fmt.Errorf("cannot pin a development build; give a release version")
+		}
+
+		if err := updater.Pin(version, Version); err != nil {
+			return err
+		}
+		fmt.Printf("✓ Pinned to %s\n", version)
+		if version != Version {
+			fmt.Println("Install it with: clauderock manage update")
+		}
+		return nil
 	},
 }
 
-// Registered by manage.go
+var updateUnpinCmd = &cobra.Command{
+	Use:   "unpin",
+	Short: "Go back to updating to the latest version",
+	Args:  cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		if err := updater.Unpin(); err != nil {
+			return err
+		}
+		fmt.Println("✓ Unpinned; 'clauderock manage update' installs the latest version")
+		return nil
+	},
+}
+
+func init() {
+	// Registered by manage.go
+	updateCmd.Flags().StringVar(&updateVersion, "version", "", "Install this release (e.g., v1.2.3) instead of the latest")
+	updateCmd.AddCommand(updateRollbackCmd)
+	updateCmd.AddCommand(updatePinCmd)
+	updateCmd.AddCommand(updateUnpinCmd)
+}
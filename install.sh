@@ -8,6 +8,13 @@ REPO="OlaHulleberg/clauderock"
 INSTALL_DIR="/usr/local/bin"
 FALLBACK_DIR="$HOME/.local/bin"
 
+# With a pinned version the script installs exactly that release and checks its archive against
+# these checksums; without one it installs the latest release, checked against its checksums.txt
+# --- pinned release (managed by 'clauderock manage install pin-script') ---
+PINNED_VERSION=""
+PINNED_CHECKSUMS=""
+# --- end pinned release ---
+
 # Colors for output
 RED='\033[0;31m'
 GREEN='\033[0;32m'
@@ -52,15 +59,32 @@ fi
 
 ARCHIVE_NAME="clauderock_${OS}_${ARCH}.${ARCHIVE_EXT}"
 
-# Fetch latest release info
-echo "Fetching latest release..."
-RELEASE_URL="https://api.github.com/repos/$REPO/releases/latest"
-DOWNLOAD_URL=$(curl -s "$RELEASE_URL" | grep "browser_download_url.*$ARCHIVE_NAME" | cut -d '"' -f 4)
+# Find the release archive
+if [ -n "$PINNED_VERSION" ]; then
+    echo "Installing pinned release $PINNED_VERSION..."
+    DOWNLOAD_URL="https://github.com/$REPO/releases/download/$PINNED_VERSION/$ARCHIVE_NAME"
+    EXPECTED_SHA256=$(echo "$PINNED_CHECKSUMS" | awk -v name="$ARCHIVE_NAME" '$2 == name { print $1 }')
+    if [ -z "$EXPECTED_SHA256" ]; then
+        echo -e "${RED}Error: Release $PINNED_VERSION has no binary for $OS/$ARCH${NC}"
+        exit 1
+    fi
+else
+    echo "Fetching latest release..."
+    RELEASE_URL="https://api.github.com/repos/$REPO/releases/latest"
+    RELEASE_JSON=$(curl -s "$RELEASE_URL")
+    DOWNLOAD_URL=$(echo "$RELEASE_JSON" | grep "browser_download_url.*/$ARCHIVE_NAME\"" | cut -d '"' -f 4)
+    CHECKSUMS_URL=$(echo "$RELEASE_JSON" | grep "browser_download_url.*/checksums.txt\"" | cut -d '"' -f 4)
+
+    if [ -z "$DOWNLOAD_URL" ]; then
+        echo -e "${RED}Error: Could not find release for $OS/$ARCH${NC}"
+        echo "Please check https://github.com/$REPO/releases for available downloads"
+        exit 1
+    fi
 
-if [ -z "$DOWNLOAD_URL" ]; then
-    echo -e "${RED}Error: Could not find release for $OS/$ARCH${NC}"
-    echo "Please check https://github.com/$REPO/releases for available downloads"
-    exit 1
+    EXPECTED_SHA256=""
+    if [ -n "$CHECKSUMS_URL" ]; then
+        EXPECTED_SHA256=$(curl -fsSL "$CHECKSUMS_URL" | awk -v name="$ARCHIVE_NAME" '$2 == name { print $1 }')
+    fi
 fi
 
 # Create temp directory
@@ -75,6 +99,24 @@ if ! curl -fsSL "$DOWNLOAD_URL" -o "$ARCHIVE_NAME"; then
     exit 1
 fi
 
+# Verify the archive
+if [ -n "$EXPECTED_SHA256" ]; then
+    if command -v sha256sum >/dev/null 2>&1; then
+        ACTUAL_SHA256=$(sha256sum "$ARCHIVE_NAME" | awk '{ print $1 }')
+    else
+        ACTUAL_SHA256=$(shasum -a 256 "$ARCHIVE_NAME" | awk '{ print $1 }')
+    fi
+    if [ "$ACTUAL_SHA256" != "$EXPECTED_SHA256" ]; then
+        echo -e "${RED}Error: Checksum mismatch for $ARCHIVE_NAME${NC}"
+        echo "  expected: $EXPECTED_SHA256"
+        echo "  actual:   $ACTUAL_SHA256"
+        exit 1
+    fi
+    echo "Checksum verified"
+else
+    echo -e "${YELLOW}Warning: No checksum published for $ARCHIVE_NAME, skipping verification${NC}"
+fi
+
 # Extract binary
 echo "Extracting..."
 if [ "$ARCHIVE_EXT" = "zip" ]; then
@@ -0,0 +1,123 @@
+package accessibility
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"unicode"
+
+	"github.com/charmbracelet/lipgloss"
+	"github.com/muesli/termenv"
+)
+
+// EnvVar forces accessible mode on (1, true, yes) or off (0, false, no), overriding the saved setting
+const EnvVar = "CLAUDEROCK_ACCESSIBLE"
+
+// Settings holds the saved accessibility preference
+type Settings struct {
+	Enabled bool `json:"enabled"`
+}
+
+var (
+	once    sync.Once
+	enabled bool
+)
+
+func settingsPath() (string, error) {
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return "", fmt.Errorf("failed to get home directory: %w", err)
+	}
+	return filepath.Join(home, ".clauderock", "accessibility.json"), nil
+}
+
+// LoadSettings returns the saved preference; accessible mode is off until enabled
+func LoadSettings() (Settings, error) {
+	path, err := settingsPath()
+	if err != nil {
+		return Settings{}, err
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return Settings{}, nil
+		}
+		return Settings{}, fmt.Errorf("failed to read accessibility settings: %w", err)
+	}
+
+	var s Settings
+	if err := json.Unmarshal(data, &s); err != nil {
+		return Settings{}, fmt.Errorf("failed to parse accessibility settings: %w", err)
+	}
+	return s, nil
+}
+
+// SetEnabled saves the accessibility preference
+func SetEnabled(on bool) error {
+	path, err := settingsPath()
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return fmt.Errorf("failed to create directory: %w", err)
+	}
+	data, err := json.MarshalIndent(Settings{Enabled: on}, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to marshal accessibility settings: %w", err)
+	}
+	if err := os.WriteFile(path, data, 0644); err != nil {
+		return fmt.Errorf("failed to write accessibility settings: %w", err)
+	}
+	return nil
+}
+
+// EnvOverride reports whether CLAUDEROCK_ACCESSIBLE decides the mode, and which way
+func EnvOverride() (on bool, set bool) {
+	switch strings.ToLower(strings.TrimSpace(os.Getenv(EnvVar))) {
+	case "1", "true", "yes":
+		return true, true
+	case "0", "false", "no":
+		return false, true
+	}
+	return false, false
+}
+
+// Enabled reports whether output should avoid box-drawing, emoji, colour-only
+// signals, and full-screen prompts
+func Enabled() bool {
+	once.Do(func() {
+		if on, set := EnvOverride(); set {
+			enabled = on
+			return
+		}
+		// An unreadable settings file falls back to the default rendering
+		settings, _ := LoadSettings()
+		enabled = settings.Enabled
+	})
+	return enabled
+}
+
+// Apply disables colour output when accessible mode is on
+func Apply() {
+	if Enabled() {
+		lipgloss.SetColorProfile(termenv.Ascii)
+	}
+}
+
+// PlainText strips emoji and box-drawing symbols from s when accessible mode is on
+func PlainText(s string) string {
+	if !Enabled() {
+		return s
+	}
+	stripped := strings.Map(func(r rune) rune {
+		if unicode.Is(unicode.So, r) || r == '\uFE0F' {
+			return -1
+		}
+		return r
+	}, s)
+	return strings.Join(strings.Fields(stripped), " ")
+}
@@ -1,10 +1,13 @@
 package api
 
 import (
+	"bytes"
 	"encoding/json"
 	"fmt"
 	"io"
+	"net"
 	"net/http"
+	"net/url"
 	"strings"
 	"time"
 )
@@ -19,6 +22,16 @@ func (e *HTTPError) Error() string {
 	return fmt.Sprintf("API returned status %d: %s", e.StatusCode, e.Body)
 }
 
+// TestResult reports the outcome of a successful test request
+type TestResult struct {
+	Latency      time.Duration
+	InputTokens  int64
+	OutputTokens int64
+}
+
+// messagesTimeout bounds a /v1/messages request
+const messagesTimeout = 60 * time.Second
+
 // ModelInfo represents a model from the API
 type ModelInfo struct {
 	ID          string   `json:"id"`
@@ -27,6 +40,61 @@ type ModelInfo struct {
 	Recommended []string `json:"recommended,omitempty"`
 }
 
+// ParseBaseURL validates a user-supplied gateway base URL and returns its normalized form
+// Adds https:// when no scheme is given, and strips trailing slashes and a trailing /v1
+// since API paths (e.g. /v1/messages) are appended to the base URL
+func ParseBaseURL(raw string) (string, error) {
+	raw = strings.TrimSpace(raw)
+	if raw == "" {
+		return "", fmt.Errorf("base URL cannot be empty")
+	}
+	if strings.ContainsAny(raw, " \t") {
+		return "", fmt.Errorf("base URL cannot contain spaces")
+	}
+
+	if !strings.Contains(raw, "://") {
+		raw = "https://" + raw
+	}
+
+	u, err := url.Parse(raw)
+	if err != nil {
+		return "", fmt.Errorf("invalid base URL: %w", err)
+	}
+	if u.Scheme != "http" && u.Scheme != "https" {
+		return "", fmt.Errorf("base URL must use http:// or https://, got %s://", u.Scheme)
+	}
+	if u.Hostname() == "" {
+		return "", fmt.Errorf("base URL must include a host")
+	}
+	if u.RawQuery != "" || u.Fragment != "" {
+		return "", fmt.Errorf("base URL cannot contain a query string or fragment")
+	}
+
+	path := strings.TrimRight(u.Path, "/")
+	path = strings.TrimSuffix(path, "/v1")
+	u.Path = strings.TrimRight(path, "/")
+	u.RawPath = ""
+
+	return u.String(), nil
+}
+
+// IsInsecureRemoteURL reports whether baseURL uses plain http:// for a host other than localhost
+func IsInsecureRemoteURL(baseURL string) bool {
+	u, err := url.Parse(baseURL)
+	if err != nil || u.Scheme != "http" {
+		return false
+	}
+
+	host := u.Hostname()
+	if host == "localhost" || strings.HasSuffix(host, ".localhost") {
+		return false
+	}
+	if ip := net.ParseIP(host); ip != nil && ip.IsLoopback() {
+		return false
+	}
+	return true
+}
+
 // ModelsResponse represents the response from /v1/models endpoint
 type ModelsResponse struct {
 	Data []ModelInfo `json:"data"`
@@ -125,6 +193,69 @@ func ValidateModels(baseURL, apiKey string, modelIDs ...string) error {
 	return nil
 }
 
+// TestModel sends a minimal /v1/messages request to verify the model is reachable
+// Returns the round-trip latency and token usage of the request
+func TestModel(baseURL, apiKey, modelID string) (TestResult, error) {
+	resp, latency, err := postMessages(baseURL, apiKey, map[string]interface{}{
+		"model":      modelID,
+		"max_tokens": 5,
+		"messages": []map[string]string{
+			{"role": "user", "content": "Reply with OK."},
+		},
+	})
+	result := TestResult{Latency: latency}
+	if err != nil {
+		return result, err
+	}
+	defer resp.Body.Close()
+
+	// Token usage is optional; gateways that omit it simply report zero
+	var messageResp struct {
+		Usage struct {
+			InputTokens  int64 `json:"input_tokens"`
+			OutputTokens int64 `json:"output_tokens"`
+		} `json:"usage"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&messageResp); err == nil {
+		result.InputTokens = messageResp.Usage.InputTokens
+		result.OutputTokens = messageResp.Usage.OutputTokens
+	}
+
+	return result, nil
+}
+
+// postMessages sends a /v1/messages request with both auth styles, so Anthropic-compatible
+// and OpenRouter-style gateways accept it
+func postMessages(baseURL, apiKey string, payload map[string]interface{}) (*http.Response, time.Duration, error) {
+	body, err := json.Marshal(payload)
+	if err != nil {
+		return nil, 0, fmt.Errorf("failed to build request: %w", err)
+	}
+
+	req, err := http.NewRequest("POST", NormalizeBaseURL(baseURL)+"/v1/messages", bytes.NewReader(body))
+	if err != nil {
+		return nil, 0, fmt.Errorf("failed to create request: %w", err)
+	}
+	req.Header.Set("Authorization", "Bearer "+apiKey)
+	req.Header.Set("x-api-key", apiKey)
+	req.Header.Set("anthropic-version", "2023-06-01")
+	req.Header.Set("Content-Type", "application/json")
+
+	client := &http.Client{Timeout: messagesTimeout}
+	start := time.Now()
+	resp, err := client.Do(req)
+	latency := time.Since(start)
+	if err != nil {
+		return nil, latency, fmt.Errorf("request failed: %w", err)
+	}
+	if resp.StatusCode != http.StatusOK {
+		defer resp.Body.Close()
+		data, _ := io.ReadAll(resp.Body)
+		return nil, latency, &HTTPError{StatusCode: resp.StatusCode, Body: string(data)}
+	}
+	return resp, latency, nil
+}
+
 // IsRecommendedModel returns true if the model is recommended for the given context
 // Checks the model's Recommended field for matching context
 func IsRecommendedModel(model ModelInfo, context string) bool {
@@ -0,0 +1,210 @@
+package api
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+)
+
+// ConformanceCheck is the outcome of one scripted request against a gateway
+type ConformanceCheck struct {
+	Name     string
+	Feature  string // Claude Code feature that depends on it
+	Required bool   // Claude Code can't work without it; optional checks only degrade features
+	Passed   bool
+	Detail   string
+	Latency  time.Duration
+}
+
+// RunConformance sends the requests Claude Code relies on to a gateway: a models list,
+// a small message, a streamed message, and a forced tool call, all with tiny token limits
+func RunConformance(baseURL, apiKey, modelID string) []ConformanceCheck {
+	return []ConformanceCheck{
+		checkModelsList(baseURL, apiKey, modelID),
+		checkMessage(baseURL, apiKey, modelID),
+		checkStreaming(baseURL, apiKey, modelID),
+		checkToolCall(baseURL, apiKey, modelID),
+	}
+}
+
+func checkModelsList(baseURL, apiKey, modelID string) ConformanceCheck {
+	check := ConformanceCheck{Name: "Models list", Feature: "model selection and launch validation"}
+
+	start := time.Now()
+	models, err := FetchAvailableModels(baseURL, apiKey)
+	check.Latency = time.Since(start)
+	if err != nil {
+		if httpErr, ok := err.(*HTTPError); ok && httpErr.StatusCode == http.StatusNotFound {
+			check.Detail = "/v1/models not found; models must be entered by ID and launch validation is skipped"
+		} else {
+			check.Detail = err.Error()
+		}
+		return check
+	}
+
+	check.Passed = true
+	check.Detail = fmt.Sprintf("%d models", len(models))
+	for _, m := range models {
+		if m.ID == modelID {
+			return check
+		}
+	}
+	check.Detail += fmt.Sprintf(", but %s isn't among them", modelID)
+	return check
+}
+
+func checkMessage(baseURL, apiKey, modelID string) ConformanceCheck {
+	check := ConformanceCheck{Name: "Message", Feature: "every request", Required: true}
+
+	resp, latency, err := postMessages(baseURL, apiKey, map[string]interface{}{
+		"model":      modelID,
+		"max_tokens": 5,
+		"messages": []map[string]string{
+			{"role": "user", "content": "Reply with OK."},
+		},
+	})
+	check.Latency = latency
+	if err != nil {
+		check.Detail = err.Error()
+		return check
+	}
+	defer resp.Body.Close()
+
+	var message struct {
+		Type    string `json:"type"`
+		Content []struct {
+			Type string `json:"type"`
+		} `json:"content"`
+		Usage *struct {
+			InputTokens  int64 `json:"input_tokens"`
+			OutputTokens int64 `json:"output_tokens"`
+		} `json:"usage"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&message); err != nil {
+		check.Detail = fmt.Sprintf("response isn't JSON: %v", err)
+		return check
+	}
+	if message.Type != "message" || len(message.Content) == 0 {
+		check.Detail = "response isn't an Anthropic Messages API message"
+		return check
+	}
+
+	check.Passed = true
+	if message.Usage == nil {
+		check.Detail = "no token usage in the response; usage stats and costs will be empty"
+	} else {
+		check.Detail = fmt.Sprintf("%d input, %d output tokens", message.Usage.InputTokens, message.Usage.OutputTokens)
+	}
+	return check
+}
+
+func checkStreaming(baseURL, apiKey, modelID string) ConformanceCheck {
+	check := ConformanceCheck{Name: "Streaming", Feature: "live responses in the terminal", Required: true}
+
+	resp, latency, err := postMessages(baseURL, apiKey, map[string]interface{}{
+		"model":      modelID,
+		"max_tokens": 5,
+		"stream":     true,
+		"messages": []map[string]string{
+			{"role": "user", "content": "Reply with OK."},
+		},
+	})
+	check.Latency = latency
+	if err != nil {
+		check.Detail = err.Error()
+		return check
+	}
+	defer resp.Body.Close()
+
+	if !strings.HasPrefix(resp.Header.Get("Content-Type"), "text/event-stream") {
+		check.Detail = fmt.Sprintf("expected text/event-stream, got %q; the gateway may be buffering or ignoring stream: true", resp.Header.Get("Content-Type"))
+		return check
+	}
+
+	// Server-sent events carry their type in each data payload
+	seen := make(map[string]bool)
+	scanner := bufio.NewScanner(resp.Body)
+	scanner.Buffer(make([]byte, 64*1024), 1024*1024)
+	for scanner.Scan() {
+		data, ok := strings.CutPrefix(scanner.Text(), "data:")
+		if !ok {
+			continue
+		}
+		var event struct {
+			Type string `json:"type"`
+		}
+		if json.Unmarshal([]byte(strings.TrimSpace(data)), &event) == nil && event.Type != "" {
+			seen[event.Type] = true
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		check.Detail = fmt.Sprintf("stream interrupted: %v", err)
+		return check
+	}
+
+	var missing []string
+	for _, event := range []string{"message_start", "content_block_delta", "message_stop"} {
+		if !seen[event] {
+			missing = append(missing, event)
+		}
+	}
+	if len(missing) > 0 {
+		check.Detail = "stream is missing " + strings.Join(missing, ", ") + " events"
+		return check
+	}
+
+	check.Passed = true
+	check.Detail = fmt.Sprintf("%d event types", len(seen))
+	return check
+}
+
+func checkToolCall(baseURL, apiKey, modelID string) ConformanceCheck {
+	check := ConformanceCheck{Name: "Tool call", Feature: "editing files and running commands", Required: true}
+
+	resp, latency, err := postMessages(baseURL, apiKey, map[string]interface{}{
+		"model":      modelID,
+		"max_tokens": 100,
+		"tools": []map[string]interface{}{{
+			"name":        "get_time",
+			"description": "Returns the current time",
+			"input_schema": map[string]interface{}{
+				"type":       "object",
+				"properties": map[string]interface{}{},
+			},
+		}},
+		"tool_choice": map[string]string{"type": "tool", "name": "get_time"},
+		"messages": []map[string]string{
+			{"role": "user", "content": "What time is it?"},
+		},
+	})
+	check.Latency = latency
+	if err != nil {
+		check.Detail = err.Error()
+		return check
+	}
+	defer resp.Body.Close()
+
+	var message struct {
+		StopReason string `json:"stop_reason"`
+		Content    []struct {
+			Type string `json:"type"`
+			Name string `json:"name"`
+		} `json:"content"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&message); err != nil {
+		check.Detail = fmt.Sprintf("response isn't JSON: %v", err)
+		return check
+	}
+	for _, block := range message.Content {
+		if block.Type == "tool_use" && block.Name == "get_time" {
+			check.Passed = true
+			check.Detail = "stop reason " + message.StopReason
+			return check
+		}
+	}
+	check.Detail = fmt.Sprintf("no tool_use block in the response (stop reason %q); the gateway may drop tools", message.StopReason)
+	return check
+}
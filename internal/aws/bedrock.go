@@ -3,58 +3,89 @@ package aws
 import (
 	"context"
 	"fmt"
+	"os"
 	"sort"
 	"strings"
+	"sync/atomic"
 
 	"github.com/OlaHulleberg/clauderock/internal/config"
 	"github.com/aws/aws-sdk-go-v2/aws"
 	awsconfig "github.com/aws/aws-sdk-go-v2/config"
+	"github.com/aws/aws-sdk-go-v2/credentials"
 	"github.com/aws/aws-sdk-go-v2/service/bedrock"
 	"github.com/aws/aws-sdk-go-v2/service/bedrock/types"
 )
 
-// ModelInfo contains detailed model information
-type ModelInfo struct {
-	Name     string // e.g., "anthropic.claude-sonnet-4-5"
-	Provider string // e.g., "anthropic"
-	Model    string // e.g., "claude-sonnet-4-5"
+// loadAWSConfig loads the SDK config for a shared AWS profile, or from the SDK's
+// default credential chain (environment, web identity, container and instance roles)
+// when awsProfile is empty, or from the environment alone after UseEnvironmentCredentials
+func loadAWSConfig(ctx context.Context, awsProfile string, optFns ...func(*awsconfig.LoadOptions) error) (aws.Config, error) {
+	if awsProfile != "" {
+		optFns = append([]func(*awsconfig.LoadOptions) error{awsconfig.WithSharedConfigProfile(awsProfile)}, optFns...)
+	} else if environmentOnly.Load() {
+		optFns = append(environmentCredentials(), optFns...)
+	}
+	return awsconfig.LoadDefaultConfig(ctx, optFns...)
 }
 
-// FindInferenceProfiles finds the main and fast model inference profile IDs
-func FindInferenceProfiles(cfg *config.Config) (string, string, error) {
-	ctx := context.Background()
+// environmentOnly makes calls without a shared profile use only the credentials in the environment
+var environmentOnly atomic.Bool
+
+// UseEnvironmentCredentials makes clauderock's AWS calls without a shared profile use only the
+// credentials in the environment, for profiles with credentials-source environment: ~/.aws files
+// are ignored and an inherited AWS_PROFILE or AWS_DEFAULT_PROFILE is dropped, which the SDK's
+// default chain would otherwise load instead
+func UseEnvironmentCredentials() {
+	_ = os.Unsetenv("AWS_PROFILE")
+	_ = os.Unsetenv("AWS_DEFAULT_PROFILE")
+	environmentOnly.Store(true)
+}
 
-	// Load AWS config with specified profile and region
-	awsCfg, err := awsconfig.LoadDefaultConfig(ctx,
-		awsconfig.WithSharedConfigProfile(cfg.Profile),
-		awsconfig.WithRegion(cfg.Region),
-	)
-	if err != nil {
-		return "", "", fmt.Errorf("failed to load AWS config: %w", err)
+// environmentCredentials are the load options of UseEnvironmentCredentials; access keys are used
+// as given, while a web identity token or container credentials are still resolved by the SDK
+func environmentCredentials() []func(*awsconfig.LoadOptions) error {
+	opts := []func(*awsconfig.LoadOptions) error{
+		awsconfig.WithSharedConfigFiles([]string{}),
+		awsconfig.WithSharedCredentialsFiles([]string{}),
 	}
+	id, secret := os.Getenv("AWS_ACCESS_KEY_ID"), os.Getenv("AWS_SECRET_ACCESS_KEY")
+	if id != "" && secret != "" {
+		provider := credentials.NewStaticCredentialsProvider(id, secret, os.Getenv("AWS_SESSION_TOKEN"))
+		opts = append(opts, awsconfig.WithCredentialsProvider(provider))
+	}
+	return opts
+}
 
-	// Create Bedrock client
-	client := bedrock.NewFromConfig(awsCfg)
+// ModelInfo contains detailed model information
+type ModelInfo struct {
+	Name         string   // e.g., "anthropic.claude-sonnet-4-5"
+	Provider     string   // e.g., "anthropic"
+	Model        string   // e.g., "claude-sonnet-4-5"
+	ProfileID    string   // e.g., "global.anthropic.claude-sonnet-4-5-20250929-v1:0"
+	CrossRegions []string // Cross-region prefixes offering this model, e.g., ["us", "eu", "global"]
+}
 
-	// List cross-region inference profiles (SYSTEM_DEFINED type only)
-	result, err := client.ListInferenceProfiles(ctx, &bedrock.ListInferenceProfilesInput{
-		TypeEquals: types.InferenceProfileTypeSystemDefined,
-	})
+// crossRegionPrefixes lists the supported cross-region prefixes in display order
+var crossRegionPrefixes = []string{"us", "eu", "global"}
+
+// FindInferenceProfiles finds the main and fast model inference profile IDs
+func FindInferenceProfiles(cfg *config.Config) (string, string, error) {
+	profiles, err := listSystemInferenceProfiles(cfg.AWSProfile(), cfg.Region)
 	if err != nil {
-		return "", "", fmt.Errorf("failed to list inference profiles: %w", err)
+		return "", "", err
 	}
 
 	// Find matching profiles
-	mainModelID, err := findMatchingProfile(result.InferenceProfileSummaries, cfg.CrossRegion, cfg.Model)
+	mainModelID, err := findMatchingProfile(profiles, cfg.CrossRegion, cfg.Model)
 	if err != nil {
 		return "", "", fmt.Errorf("main model: %w\nAvailable profiles:\n%s",
-			err, formatAvailableProfiles(result.InferenceProfileSummaries))
+			err, formatAvailableProfiles(profiles))
 	}
 
-	fastModelID, err := findMatchingProfile(result.InferenceProfileSummaries, cfg.CrossRegion, cfg.FastModel)
+	fastModelID, err := findMatchingProfile(profiles, cfg.CrossRegion, cfg.FastModel)
 	if err != nil {
 		return "", "", fmt.Errorf("fast model: %w\nAvailable profiles:\n%s",
-			err, formatAvailableProfiles(result.InferenceProfileSummaries))
+			err, formatAvailableProfiles(profiles))
 	}
 
 	return mainModelID, fastModelID, nil
@@ -65,8 +96,13 @@ func findMatchingProfile(profiles []types.InferenceProfileSummary, crossRegion,
 	// Example input: "anthropic.claude-sonnet-4-5"
 	// Expected profile format: {cross-region}.{provider}.{model-name}-{version}
 	// Example: global.anthropic.claude-sonnet-4-5-20250929-v1:0
+	candidates := matchingProfileIDs(profiles, crossRegion, model)
+	if len(candidates) > 0 {
+		// Newest snapshot first, so the choice doesn't depend on API ordering
+		return candidates[0], nil
+	}
 
-	// Build prefix from cross-region and model (which includes provider)
+	// Fall back to plain prefix matching for partial model names
 	prefix := fmt.Sprintf("%s.%s", crossRegion, model)
 
 	for _, profile := range profiles {
@@ -81,6 +117,40 @@ func findMatchingProfile(profiles []types.InferenceProfileSummary, crossRegion,
 	return "", fmt.Errorf("could not find inference profile for model '%s' with cross-region '%s'", model, crossRegion)
 }
 
+// matchingProfileIDs returns every profile ID for an exact model under a cross-region
+// Results are ordered newest snapshot first (by date, then by version suffix)
+func matchingProfileIDs(profiles []types.InferenceProfileSummary, crossRegion, model string) []string {
+	var candidates []string
+	for _, profile := range profiles {
+		if profile.InferenceProfileId == nil {
+			continue
+		}
+		profileID := aws.ToString(profile.InferenceProfileId)
+		provider, modelName, ok := parseProfileID(profileID, crossRegion)
+		if ok && fmt.Sprintf("%s.%s", provider, modelName) == model {
+			candidates = append(candidates, profileID)
+		}
+	}
+
+	sort.SliceStable(candidates, func(i, j int) bool {
+		dateI := ExtractVersionDate(candidates[i])
+		dateJ := ExtractVersionDate(candidates[j])
+		if dateI != dateJ {
+			return dateI > dateJ
+		}
+		return candidates[i] > candidates[j]
+	})
+
+	return candidates
+}
+
+// ExtractVersionDate extracts the dated snapshot from a profile ID
+// Input: "global.anthropic.claude-sonnet-4-5-20250929-v1:0"
+// Output: "20250929" (empty if the profile ID has no dated snapshot)
+func ExtractVersionDate(profileID string) string {
+	return config.SnapshotDate(profileID)
+}
+
 func formatAvailableProfiles(profiles []types.InferenceProfileSummary) string {
 	var builder strings.Builder
 	for _, profile := range profiles {
@@ -204,48 +274,119 @@ func ResolveModelToProfileID(awsProfile, region, crossRegion, model string) (str
 		return model, nil
 	}
 
-	ctx := context.Background()
+	profiles, err := listSystemInferenceProfiles(awsProfile, region)
+	if err != nil {
+		return "", err
+	}
 
-	// Load AWS config
-	awsCfg, err := awsconfig.LoadDefaultConfig(ctx,
-		awsconfig.WithSharedConfigProfile(awsProfile),
-		awsconfig.WithRegion(region),
-	)
+	// Find matching profile
+	profileID, err := findMatchingProfile(profiles, crossRegion, model)
 	if err != nil {
-		return "", fmt.Errorf("failed to load AWS config: %w", err)
+		return "", fmt.Errorf("%w\nAvailable profiles:\n%s",
+			err, formatAvailableProfiles(profiles))
 	}
 
-	// Create Bedrock client
-	client := bedrock.NewFromConfig(awsCfg)
+	return profileID, nil
+}
 
-	// List cross-region inference profiles
-	result, err := client.ListInferenceProfiles(ctx, &bedrock.ListInferenceProfilesInput{
-		TypeEquals: types.InferenceProfileTypeSystemDefined,
-	})
+// ListModelVersions returns all profile IDs available for a friendly model name, newest first
+func ListModelVersions(awsProfile, region, crossRegion, model string) ([]string, error) {
+	profiles, err := listSystemInferenceProfiles(awsProfile, region)
 	if err != nil {
-		return "", fmt.Errorf("failed to list inference profiles: %w", err)
+		return nil, err
 	}
 
-	// Find matching profile
-	profileID, err := findMatchingProfile(result.InferenceProfileSummaries, crossRegion, model)
+	versions := matchingProfileIDs(profiles, crossRegion, model)
+	if len(versions) == 0 {
+		return nil, fmt.Errorf("could not find inference profile for model '%s' with cross-region '%s'", model, crossRegion)
+	}
+	return versions, nil
+}
+
+// ResolveModelVersion resolves a friendly model name to the profile ID of a specific dated snapshot
+// An empty version behaves like ResolveModelToProfileID (newest snapshot)
+func ResolveModelVersion(awsProfile, region, crossRegion, model, version string) (string, error) {
+	if version == "" {
+		return ResolveModelToProfileID(awsProfile, region, crossRegion, model)
+	}
+
+	versions, err := ListModelVersions(awsProfile, region, crossRegion, model)
 	if err != nil {
-		return "", fmt.Errorf("%w\nAvailable profiles:\n%s",
-			err, formatAvailableProfiles(result.InferenceProfileSummaries))
+		return "", err
 	}
 
-	return profileID, nil
+	var available []string
+	for _, profileID := range versions {
+		if ExtractVersionDate(profileID) == version {
+			return profileID, nil
+		}
+		available = append(available, "  - "+profileID)
+	}
+
+	return "", fmt.Errorf("version '%s' not found for model '%s'\nAvailable versions:\n%s",
+		version, model, strings.Join(available, "\n"))
 }
 
-// GetAvailableModels fetches available models from Bedrock for a given profile, region, and cross-region
-// Returns a deduplicated list of model names in format "provider.model-name" (e.g., "anthropic.claude-sonnet-4-5", "meta.llama3-70b")
-func GetAvailableModels(profile, region, crossRegion string) ([]string, error) {
+// ResolveSlotModel resolves a friendly model name for a config slot, honouring any pinned snapshot
+// If the pinned snapshot isn't available for the model, the pin is dropped and unpinned is true
+func ResolveSlotModel(cfg *config.Config, slot, model string) (profileID string, unpinned bool, err error) {
+	if IsFullProfileID(model) {
+		return model, false, nil
+	}
+
+	if version := cfg.PinnedVersion(slot); version != "" {
+		profileID, err := ResolveModelVersion(cfg.AWSProfile(), cfg.Region, cfg.CrossRegion, model, version)
+		if err == nil {
+			return profileID, false, nil
+		}
+		cfg.UnpinVersion(slot)
+		unpinned = true
+	}
+
+	profileID, err = ResolveModelToProfileID(cfg.AWSProfile(), cfg.Region, cfg.CrossRegion, model)
+	return profileID, unpinned, err
+}
+
+// FindModelProfile looks up a model in a region without failing when it's missing
+// model may be a friendly name ("anthropic.claude-opus-4-5") or a full profile ID
+// Returns the newest matching profile ID, or "" when the model isn't available yet
+// Always asks Bedrock, since callers poll for models to appear
+func FindModelProfile(awsProfile, region, crossRegion, model string) (string, error) {
+	profiles, err := fetchSystemInferenceProfiles(awsProfile, region)
+	if err != nil {
+		return "", err
+	}
+
+	if IsFullProfileID(model) {
+		for _, profile := range profiles {
+			if aws.ToString(profile.InferenceProfileId) == model {
+				return model, nil
+			}
+		}
+		return "", nil
+	}
+
+	if candidates := matchingProfileIDs(profiles, crossRegion, model); len(candidates) > 0 {
+		return candidates[0], nil
+	}
+	return "", nil
+}
+
+// listSystemInferenceProfiles lists the SYSTEM_DEFINED inference profiles visible to a profile/region,
+// reusing their cached listing while it's fresh
+func listSystemInferenceProfiles(awsProfile, region string) ([]types.InferenceProfileSummary, error) {
+	if profiles, ok := cachedInferenceProfiles(awsProfile, region); ok {
+		return profiles, nil
+	}
+	return fetchSystemInferenceProfiles(awsProfile, region)
+}
+
+// fetchSystemInferenceProfiles lists the SYSTEM_DEFINED inference profiles from Bedrock and caches them
+func fetchSystemInferenceProfiles(awsProfile, region string) ([]types.InferenceProfileSummary, error) {
 	ctx := context.Background()
 
-	// Load AWS config with specified profile and region
-	awsCfg, err := awsconfig.LoadDefaultConfig(ctx,
-		awsconfig.WithSharedConfigProfile(profile),
-		awsconfig.WithRegion(region),
-	)
+	// Load AWS config
+	awsCfg, err := loadAWSConfig(ctx, awsProfile, awsconfig.WithRegion(region))
 	if err != nil {
 		return nil, fmt.Errorf("failed to load AWS config: %w", err)
 	}
@@ -253,7 +394,7 @@ func GetAvailableModels(profile, region, crossRegion string) ([]string, error) {
 	// Create Bedrock client
 	client := bedrock.NewFromConfig(awsCfg)
 
-	// List cross-region inference profiles (SYSTEM_DEFINED type only)
+	// List cross-region inference profiles
 	result, err := client.ListInferenceProfiles(ctx, &bedrock.ListInferenceProfilesInput{
 		TypeEquals: types.InferenceProfileTypeSystemDefined,
 	})
@@ -261,10 +402,32 @@ func GetAvailableModels(profile, region, crossRegion string) ([]string, error) {
 		return nil, fmt.Errorf("failed to list inference profiles: %w", err)
 	}
 
+	saveInferenceProfiles(awsProfile, region, result.InferenceProfileSummaries)
+	return result.InferenceProfileSummaries, nil
+}
+
+// GetAvailableModels fetches available models from Bedrock for a given profile, region, and cross-region
+// Returns a deduplicated list of model names in format "provider.model-name" (e.g., "anthropic.claude-sonnet-4-5", "meta.llama3-70b")
+func GetAvailableModels(profile, region, crossRegion string) ([]string, error) {
+	profiles, err := listSystemInferenceProfiles(profile, region)
+	if err != nil {
+		return nil, err
+	}
+
+	models := modelsForCrossRegion(profiles, crossRegion)
+	if len(models) == 0 {
+		return nil, fmt.Errorf("no models found for cross-region '%s'", crossRegion)
+	}
+
+	return models, nil
+}
+
+// modelsForCrossRegion returns the sorted, deduplicated friendly model names offered under a cross-region
+func modelsForCrossRegion(profiles []types.InferenceProfileSummary, crossRegion string) []string {
 	// Extract unique model names for the specified cross-region
 	modelMap := make(map[string]bool)
 
-	for _, profile := range result.InferenceProfileSummaries {
+	for _, profile := range profiles {
 		if profile.InferenceProfileId != nil {
 			profileID := aws.ToString(profile.InferenceProfileId)
 
@@ -301,11 +464,7 @@ func GetAvailableModels(profile, region, crossRegion string) ([]string, error) {
 		return modelI < modelJ
 	})
 
-	if len(models) == 0 {
-		return nil, fmt.Errorf("no models found for cross-region '%s'", crossRegion)
-	}
-
-	return models, nil
+	return models
 }
 
 // IsRecommendedModel returns true if the model is recommended for the given context
@@ -360,75 +519,70 @@ func SortModelsWithRecommendation(models []string, context string) []string {
 }
 
 // ValidateProfileIDs validates that the given profile IDs exist in AWS Bedrock
+// The cached listing for the profile and region is checked first; Bedrock is only asked when it's
+// stale or lacks an ID
 func ValidateProfileIDs(awsProfile, region string, profileIDs ...string) error {
-	ctx := context.Background()
-
-	// Load AWS config
-	awsCfg, err := awsconfig.LoadDefaultConfig(ctx,
-		awsconfig.WithSharedConfigProfile(awsProfile),
-		awsconfig.WithRegion(region),
-	)
-	if err != nil {
-		return fmt.Errorf("failed to load AWS config: %w", err)
+	if profiles, ok := cachedInferenceProfiles(awsProfile, region); ok && missingProfileID(profiles, profileIDs) == "" {
+		return nil
 	}
 
-	// Create Bedrock client
-	client := bedrock.NewFromConfig(awsCfg)
-
-	// List all inference profiles
-	result, err := client.ListInferenceProfiles(ctx, &bedrock.ListInferenceProfilesInput{
-		TypeEquals: types.InferenceProfileTypeSystemDefined,
-	})
+	profiles, err := fetchSystemInferenceProfiles(awsProfile, region)
 	if err != nil {
-		return fmt.Errorf("failed to list inference profiles: %w", err)
+		return err
 	}
+	if missing := missingProfileID(profiles, profileIDs); missing != "" {
+		return fmt.Errorf("profile ID '%s' does not exist in AWS Bedrock\nRun 'clauderock manage models list' to see available models", missing)
+	}
+	return nil
+}
 
-	// Build a set of valid profile IDs
-	validProfiles := make(map[string]bool)
-	for _, profile := range result.InferenceProfileSummaries {
+// missingProfileID returns the first of profileIDs not in profiles, or ""
+func missingProfileID(profiles []types.InferenceProfileSummary, profileIDs []string) string {
+	valid := make(map[string]bool)
+	for _, profile := range profiles {
 		if profile.InferenceProfileId != nil {
-			validProfiles[aws.ToString(profile.InferenceProfileId)] = true
+			valid[aws.ToString(profile.InferenceProfileId)] = true
 		}
 	}
-
-	// Validate each requested profile ID
 	for _, profileID := range profileIDs {
-		if !validProfiles[profileID] {
-			return fmt.Errorf("profile ID '%s' does not exist in AWS Bedrock\nRun 'clauderock manage models list' to see available models", profileID)
+		if !valid[profileID] {
+			return profileID
 		}
 	}
-
-	return nil
+	return ""
 }
 
 // GetAvailableModelsDetailed fetches available models from Bedrock with detailed information
 func GetAvailableModelsDetailed(profile, region, crossRegion string) ([]ModelInfo, error) {
-	ctx := context.Background()
-
-	// Load AWS config with specified profile and region
-	awsCfg, err := awsconfig.LoadDefaultConfig(ctx,
-		awsconfig.WithSharedConfigProfile(profile),
-		awsconfig.WithRegion(region),
-	)
+	profiles, err := listSystemInferenceProfiles(profile, region)
 	if err != nil {
-		return nil, fmt.Errorf("failed to load AWS config: %w", err)
+		return nil, err
 	}
 
-	// Create Bedrock client
-	client := bedrock.NewFromConfig(awsCfg)
-
-	// List cross-region inference profiles (SYSTEM_DEFINED type only)
-	result, err := client.ListInferenceProfiles(ctx, &bedrock.ListInferenceProfilesInput{
-		TypeEquals: types.InferenceProfileTypeSystemDefined,
-	})
-	if err != nil {
-		return nil, fmt.Errorf("failed to list inference profiles: %w", err)
+	// Record every cross-region prefix each model is offered under
+	availability := make(map[string]map[string]bool)
+	for _, profile := range profiles {
+		if profile.InferenceProfileId == nil {
+			continue
+		}
+		profileID := aws.ToString(profile.InferenceProfileId)
+		for _, prefix := range crossRegionPrefixes {
+			provider, modelName, ok := parseProfileID(profileID, prefix)
+			if !ok {
+				continue
+			}
+			fullModelName := fmt.Sprintf("%s.%s", provider, modelName)
+			if availability[fullModelName] == nil {
+				availability[fullModelName] = make(map[string]bool)
+			}
+			availability[fullModelName][prefix] = true
+		}
 	}
 
 	// Extract unique model names for the specified cross-region
 	modelMap := make(map[string]ModelInfo)
 
-	for _, profile := range result.InferenceProfileSummaries {
+	for _, profile := range profiles {
 		if profile.InferenceProfileId != nil {
 			profileID := aws.ToString(profile.InferenceProfileId)
 
@@ -436,10 +590,16 @@ func GetAvailableModelsDetailed(profile, region, crossRegion string) ([]ModelInf
 			provider, modelName, ok := parseProfileID(profileID, crossRegion)
 			if ok {
 				fullModelName := fmt.Sprintf("%s.%s", provider, modelName)
+				// Keep the first matching profile ID, same as findMatchingProfile
+				if _, exists := modelMap[fullModelName]; exists {
+					continue
+				}
 				modelMap[fullModelName] = ModelInfo{
-					Name:     fullModelName,
-					Provider: provider,
-					Model:    modelName,
+					Name:         fullModelName,
+					Provider:     provider,
+					Model:        modelName,
+					ProfileID:    profileID,
+					CrossRegions: sortedCrossRegions(availability[fullModelName]),
 				}
 			}
 		}
@@ -462,3 +622,14 @@ func GetAvailableModelsDetailed(profile, region, crossRegion string) ([]ModelInf
 
 	return models, nil
 }
+
+// sortedCrossRegions returns the cross-region prefixes present in the set, in display order
+func sortedCrossRegions(set map[string]bool) []string {
+	var regions []string
+	for _, prefix := range crossRegionPrefixes {
+		if set[prefix] {
+			regions = append(regions, prefix)
+		}
+	}
+	return regions
+}
@@ -0,0 +1,43 @@
+package aws
+
+import "strings"
+
+// contextWindows maps friendly model name prefixes to their context window in tokens.
+// Longer prefixes are checked first so specific variants win over families.
+var contextWindows = map[string]int{
+	"anthropic.claude-opus-4":     200000,
+	"anthropic.claude-sonnet-4":   200000,
+	"anthropic.claude-haiku-4":    200000,
+	"anthropic.claude-3-7-sonnet": 200000,
+	"anthropic.claude-3-5-sonnet": 200000,
+	"anthropic.claude-3-5-haiku":  200000,
+	"anthropic.claude-3-haiku":    200000,
+	"anthropic.claude-3-opus":     200000,
+	"meta.llama3-1":               128000,
+	"meta.llama3-2":               128000,
+	"meta.llama3-3":               128000,
+	"meta.llama4":                 128000,
+	"amazon.nova-premier":         1000000,
+	"amazon.nova-pro":             300000,
+	"amazon.nova-lite":            300000,
+	"amazon.nova-micro":           128000,
+	"mistral.pixtral-large":       128000,
+	"deepseek.r1":                 128000,
+	"writer.palmyra-x4":           128000,
+	"writer.palmyra-x5":           1000000,
+}
+
+// ContextWindow returns the known context window (in tokens) for a friendly model name
+// Returns false if the model is not in the built-in catalog
+func ContextWindow(model string) (int, bool) {
+	bestPrefix := ""
+	for prefix := range contextWindows {
+		if strings.HasPrefix(model, prefix) && len(prefix) > len(bestPrefix) {
+			bestPrefix = prefix
+		}
+	}
+	if bestPrefix == "" {
+		return 0, false
+	}
+	return contextWindows[bestPrefix], true
+}
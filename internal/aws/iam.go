@@ -0,0 +1,242 @@
+package aws
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	awsconfig "github.com/aws/aws-sdk-go-v2/config"
+	"github.com/aws/aws-sdk-go-v2/service/bedrock"
+	"github.com/aws/aws-sdk-go-v2/service/bedrock/types"
+	"github.com/aws/aws-sdk-go-v2/service/bedrockruntime"
+	"github.com/aws/aws-sdk-go-v2/service/sts"
+	"github.com/aws/smithy-go"
+)
+
+// PermissionCheck is the outcome of trying one IAM action
+type PermissionCheck struct {
+	Action   string
+	Resource string // Model profile ID for invoke actions, empty otherwise
+	Allowed  bool
+	Detail   string // Caller ARN on success, or why the check failed
+}
+
+// IAMReport collects the permission checks for one AWS profile and region
+type IAMReport struct {
+	CallerARN string
+	AccountID string
+	Checks    []PermissionCheck
+}
+
+// Missing returns the checks that were denied or could not be completed
+func (r *IAMReport) Missing() []PermissionCheck {
+	var missing []PermissionCheck
+	for _, c := range r.Checks {
+		if !c.Allowed {
+			missing = append(missing, c)
+		}
+	}
+	return missing
+}
+
+// invalidInvokeBody is rejected by the model's input schema, which Bedrock only checks
+// after authorization and after resolving the model; a ValidationException naming the
+// missing body fields therefore proves the action is allowed without running (or paying
+// for) any inference
+var invalidInvokeBody = []byte("{}")
+
+// bodySchemaMarkers appear in the ValidationException the model's input schema raises for
+// invalidInvokeBody. Other ValidationExceptions, such as an invalid model identifier or a
+// model that needs an inference profile, are raised before the model is reached and say
+// nothing about the permission
+var bodySchemaMarkers = []string{"required key", "extraneous key", "anthropic_version", "max_tokens"}
+
+// CheckIAM tries each action clauderock needs with trial calls:
+// sts:GetCallerIdentity, bedrock:ListInferenceProfiles, and bedrock:InvokeModel plus
+// bedrock:InvokeModelWithResponseStream for every profile ID
+func CheckIAM(awsProfile, region string, profileIDs ...string) (*IAMReport, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), testTimeout)
+	defer cancel()
+
+	// Load AWS config
+	awsCfg, err := loadAWSConfig(ctx, awsProfile, awsconfig.WithRegion(region))
+	if err != nil {
+		return nil, fmt.Errorf("failed to load AWS config: %w", err)
+	}
+
+	report := &IAMReport{}
+
+	identity, err := sts.NewFromConfig(awsCfg).GetCallerIdentity(ctx, &sts.GetCallerIdentityInput{})
+	if err != nil {
+		// Without working credentials every other check would fail for the same reason
+		report.Checks = append(report.Checks, PermissionCheck{Action: "sts:GetCallerIdentity", Detail: describeAWSError(err)})
+		return report, nil
+	}
+	report.CallerARN = aws.ToString(identity.Arn)
+	report.AccountID = aws.ToString(identity.Account)
+	report.Checks = append(report.Checks, PermissionCheck{Action: "sts:GetCallerIdentity", Allowed: true, Detail: report.CallerARN})
+
+	_, err = bedrock.NewFromConfig(awsCfg).ListInferenceProfiles(ctx, &bedrock.ListInferenceProfilesInput{
+		TypeEquals: types.InferenceProfileTypeSystemDefined,
+		MaxResults: aws.Int32(1),
+	})
+	report.Checks = append(report.Checks, permissionCheck("bedrock:ListInferenceProfiles", "", err, false))
+
+	runtime := bedrockruntime.NewFromConfig(awsCfg, func(o *bedrockruntime.Options) {
+		o.RetryMaxAttempts = 1
+	})
+	for _, profileID := range uniqueNonEmpty(profileIDs) {
+		_, err := runtime.InvokeModel(ctx, &bedrockruntime.InvokeModelInput{
+			ModelId:     aws.String(profileID),
+			ContentType: aws.String("application/json"),
+			Body:        invalidInvokeBody,
+		})
+		report.Checks = append(report.Checks, permissionCheck("bedrock:InvokeModel", profileID, err, true))
+
+		_, err = runtime.InvokeModelWithResponseStream(ctx, &bedrockruntime.InvokeModelWithResponseStreamInput{
+			ModelId:     aws.String(profileID),
+			ContentType: aws.String("application/json"),
+			Body:        invalidInvokeBody,
+		})
+		report.Checks = append(report.Checks, permissionCheck("bedrock:InvokeModelWithResponseStream", profileID, err, true))
+	}
+
+	return report, nil
+}
+
+// CallerIdentity returns the ARN of the identity an AWS profile's credentials resolve to
+func CallerIdentity(awsProfile, region string) (string, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), testTimeout)
+	defer cancel()
+
+	awsCfg, err := loadAWSConfig(ctx, awsProfile, awsconfig.WithRegion(region))
+	if err != nil {
+		return "", fmt.Errorf("failed to load AWS config: %w", err)
+	}
+
+	identity, err := sts.NewFromConfig(awsCfg).GetCallerIdentity(ctx, &sts.GetCallerIdentityInput{})
+	if err != nil {
+		return "", errors.New(describeAWSError(err))
+	}
+	return aws.ToString(identity.Arn), nil
+}
+
+// permissionCheck interprets the error of a trial call
+// For invoke trials, a validation error from the model's input schema means the request
+// got past authorization
+func permissionCheck(action, resource string, err error, validationMeansAllowed bool) PermissionCheck {
+	check := PermissionCheck{Action: action, Resource: resource}
+	if err == nil {
+		check.Allowed = true
+		return check
+	}
+
+	var apiErr smithy.APIError
+	if validationMeansAllowed && errors.As(err, &apiErr) && apiErr.ErrorCode() == "ValidationException" && reachedModel(apiErr.ErrorMessage()) {
+		check.Allowed = true
+		return check
+	}
+
+	check.Detail = describeAWSError(err)
+	return check
+}
+
+// reachedModel reports whether a ValidationException message comes from the model
+// rejecting the request body rather than from Bedrock rejecting the request itself
+func reachedModel(message string) bool {
+	message = strings.ToLower(message)
+	for _, marker := range bodySchemaMarkers {
+		if strings.Contains(message, marker) {
+			return true
+		}
+	}
+	return false
+}
+
+// describeAWSError shortens an AWS error to its code and message
+func describeAWSError(err error) string {
+	var apiErr smithy.APIError
+	if errors.As(err, &apiErr) {
+		return fmt.Sprintf("%s: %s", apiErr.ErrorCode(), apiErr.ErrorMessage())
+	}
+	return err.Error()
+}
+
+func uniqueNonEmpty(values []string) []string {
+	seen := make(map[string]bool)
+	var unique []string
+	for _, v := range values {
+		if v != "" && !seen[v] {
+			seen[v] = true
+			unique = append(unique, v)
+		}
+	}
+	return unique
+}
+
+// policyDocument is an IAM policy in the shape AWS expects
+type policyDocument struct {
+	Version   string            `json:"Version"`
+	Statement []policyStatement `json:"Statement"`
+}
+
+type policyStatement struct {
+	Sid      string   `json:"Sid"`
+	Effect   string   `json:"Effect"`
+	Action   []string `json:"Action"`
+	Resource []string `json:"Resource"`
+}
+
+// LeastPrivilegePolicy returns an IAM policy granting only what clauderock needs for the
+// given inference profile IDs. accountID may be empty, in which case any account matches.
+// Cross-region profiles route to other regions, so the underlying foundation models are
+// allowed in every region; global profiles also need the region-less model ARN.
+// sts:GetCallerIdentity needs no permission and is therefore not listed
+func LeastPrivilegePolicy(region, accountID string, profileIDs ...string) ([]byte, error) {
+	if accountID == "" {
+		accountID = "*"
+	}
+
+	var resources []string
+	for _, profileID := range uniqueNonEmpty(profileIDs) {
+		if !IsFullProfileID(profileID) {
+			// A plain model ID is invoked directly in the profile's region
+			resources = append(resources, fmt.Sprintf("arn:aws:bedrock:%s::foundation-model/%s", region, profileID))
+			continue
+		}
+
+		prefix, modelID, _ := strings.Cut(profileID, ".")
+		resources = append(resources,
+			fmt.Sprintf("arn:aws:bedrock:%s:%s:inference-profile/%s", region, accountID, profileID),
+			fmt.Sprintf("arn:aws:bedrock:*::foundation-model/%s", modelID),
+		)
+		if prefix == "global" {
+			resources = append(resources, fmt.Sprintf("arn:aws:bedrock:::foundation-model/%s", modelID))
+		}
+	}
+
+	doc := policyDocument{
+		Version: "2012-10-17",
+		Statement: []policyStatement{
+			{
+				Sid:      "ClauderockListInferenceProfiles",
+				Effect:   "Allow",
+				Action:   []string{"bedrock:ListInferenceProfiles"},
+				Resource: []string{"*"},
+			},
+		},
+	}
+	if len(resources) > 0 {
+		doc.Statement = append(doc.Statement, policyStatement{
+			Sid:      "ClauderockInvokeModels",
+			Effect:   "Allow",
+			Action:   []string{"bedrock:InvokeModel", "bedrock:InvokeModelWithResponseStream"},
+			Resource: resources,
+		})
+	}
+
+	return json.MarshalIndent(doc, "", "  ")
+}
@@ -0,0 +1,178 @@
+package aws
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/OlaHulleberg/clauderock/internal/fileutil"
+	"github.com/aws/aws-sdk-go-v2/aws"
+)
+
+// NoModelNewsEnvVar suppresses the new-model announcement on launch when set to 1, true or yes
+const NoModelNewsEnvVar = "CLAUDEROCK_NO_MODEL_NEWS"
+
+// modelCacheTTL is how long a cached model list is used before it's refreshed
+const modelCacheTTL = 24 * time.Hour
+
+// modelCacheEntry is the cached model list for one region and cross-region
+type modelCacheEntry struct {
+	Updated    time.Time `json:"updated"`
+	Models     []string  `json:"models"`
+	New        []string  `json:"new,omitempty"`         // Models added by a refresh and not announced yet
+	ProfileIDs []string  `json:"profile-ids,omitempty"` // Every inference profile under the cross-region
+}
+
+// modelCache maps "region/cross-region" to its cached model list
+type modelCache map[string]*modelCacheEntry
+
+func modelCachePath() (string, error) {
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return "", fmt.Errorf("failed to get home directory: %w", err)
+	}
+	return filepath.Join(home, ".clauderock", "model-cache.json"), nil
+}
+
+func modelCacheKey(region, crossRegion string) string {
+	return region + "/" + crossRegion
+}
+
+func loadModelCache() (modelCache, error) {
+	path, err := modelCachePath()
+	if err != nil {
+		return nil, err
+	}
+	cache := modelCache{}
+	if _, err := fileutil.LoadJSON(path, &cache); err != nil {
+		return nil, err
+	}
+	return cache, nil
+}
+
+// updateModelCache runs change on the current cache and saves it, holding the cache's lock
+// so concurrent refreshes and announcements don't overwrite each other. A corrupt cache is
+// rebuilt from scratch
+func updateModelCache(change func(cache modelCache) error) error {
+	path, err := modelCachePath()
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return err
+	}
+	unlock, err := fileutil.Lock(path+".lock", "the model cache")
+	if err != nil {
+		return err
+	}
+	defer unlock()
+
+	cache, err := loadModelCache()
+	if err != nil {
+		cache = modelCache{}
+	}
+	if err := change(cache); err != nil {
+		return err
+	}
+	return fileutil.SaveJSON(path, cache, 0644)
+}
+
+// RefreshModelCache re-lists the models for a region when the cached list is stale, and
+// remembers any Anthropic models that weren't there last time for PendingNewModels
+// The first refresh for a region only seeds the cache, so nothing is announced
+func RefreshModelCache(awsProfile, region, crossRegion string) error {
+	key := modelCacheKey(region, crossRegion)
+	if cache, err := loadModelCache(); err == nil {
+		if entry := cache[key]; entry != nil && time.Since(entry.Updated) < modelCacheTTL {
+			return nil
+		}
+	}
+
+	// The lock is only taken once the list is fetched, so a slow AWS call doesn't hold up
+	// other processes; the fetched list is merged into whatever the cache holds by then
+	profiles, err := fetchSystemInferenceProfiles(awsProfile, region)
+	if err != nil {
+		return err
+	}
+	models := modelsForCrossRegion(profiles, crossRegion)
+
+	return updateModelCache(func(cache modelCache) error {
+		entry := cache[key]
+		if entry == nil {
+			entry = &modelCacheEntry{}
+			cache[key] = entry
+		} else {
+			known := make(map[string]bool, len(entry.Models)+len(entry.New))
+			for _, model := range entry.Models {
+				known[model] = true
+			}
+			for _, model := range entry.New {
+				known[model] = true
+			}
+			for _, model := range models {
+				if !known[model] && strings.HasPrefix(model, "anthropic.") {
+					entry.New = append(entry.New, model)
+				}
+			}
+			sort.Strings(entry.New)
+		}
+		entry.Updated = time.Now()
+		entry.Models = models
+		entry.ProfileIDs = nil
+		for _, profile := range profiles {
+			if id := aws.ToString(profile.InferenceProfileId); strings.HasPrefix(id, crossRegion+".") {
+				entry.ProfileIDs = append(entry.ProfileIDs, id)
+			}
+		}
+		return nil
+	})
+}
+
+// PendingNewModels returns the models found by earlier refreshes that haven't been announced,
+// and clears them so each model is announced once
+func PendingNewModels(region, crossRegion string) ([]string, error) {
+	cache, err := loadModelCache()
+	if err != nil {
+		return nil, err
+	}
+	if entry := cache[modelCacheKey(region, crossRegion)]; entry == nil || len(entry.New) == 0 {
+		return nil, nil
+	}
+
+	var models []string
+	err = updateModelCache(func(cache modelCache) error {
+		if entry := cache[modelCacheKey(region, crossRegion)]; entry != nil {
+			models, entry.New = entry.New, nil
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return models, nil
+}
+
+// CachedProfileIDs returns the inference profile IDs from the last model list refresh
+// for a region and cross-region, or nil when it hasn't been refreshed yet
+func CachedProfileIDs(region, crossRegion string) ([]string, error) {
+	cache, err := loadModelCache()
+	if err != nil {
+		return nil, err
+	}
+	if entry := cache[modelCacheKey(region, crossRegion)]; entry != nil {
+		return entry.ProfileIDs, nil
+	}
+	return nil, nil
+}
+
+// ModelNewsSuppressed reports whether NoModelNewsEnvVar turns the announcement off
+func ModelNewsSuppressed() bool {
+	switch strings.ToLower(strings.TrimSpace(os.Getenv(NoModelNewsEnvVar))) {
+	case "1", "true", "yes":
+		return true
+	}
+	return false
+}
@@ -0,0 +1,61 @@
+package aws
+
+import (
+	_ "embed"
+	"encoding/json"
+	"fmt"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/bedrock/types"
+)
+
+// offlineCatalogJSON lists known Anthropic inference profile IDs; refresh it each release
+// from `aws bedrock list-inference-profiles --type-equals SYSTEM_DEFINED`
+//
+//go:embed offline_catalog.json
+var offlineCatalogJSON []byte
+
+// offlineCatalog is the parsed form of offline_catalog.json
+type offlineCatalog struct {
+	Updated  string   `json:"updated"`
+	Profiles []string `json:"profiles"`
+}
+
+// loadOfflineCatalog parses the embedded catalog; a parse failure is a programming error
+func loadOfflineCatalog() offlineCatalog {
+	var catalog offlineCatalog
+	if err := json.Unmarshal(offlineCatalogJSON, &catalog); err != nil {
+		panic(fmt.Sprintf("aws: invalid embedded offline catalog: %v", err))
+	}
+	return catalog
+}
+
+// offlineProfiles returns the embedded catalog in the shape ListInferenceProfiles returns
+func offlineProfiles() []types.InferenceProfileSummary {
+	catalog := loadOfflineCatalog()
+	profiles := make([]types.InferenceProfileSummary, len(catalog.Profiles))
+	for i, id := range catalog.Profiles {
+		profiles[i] = types.InferenceProfileSummary{InferenceProfileId: aws.String(id)}
+	}
+	return profiles
+}
+
+// OfflineCatalogDate returns when the embedded catalog was last updated (YYYY-MM-DD)
+func OfflineCatalogDate() string {
+	return loadOfflineCatalog().Updated
+}
+
+// OfflineModels returns the friendly model names the embedded catalog knows for a cross-region
+// Used when ListInferenceProfiles fails (no network, missing IAM permissions); the
+// models may not actually be enabled for the account
+func OfflineModels(crossRegion string) []string {
+	return modelsForCrossRegion(offlineProfiles(), crossRegion)
+}
+
+// ResolveOfflineModel resolves a friendly model name to the newest profile ID in the embedded catalog
+func ResolveOfflineModel(crossRegion, model string) (string, error) {
+	if IsFullProfileID(model) {
+		return model, nil
+	}
+	return findMatchingProfile(offlineProfiles(), crossRegion, model)
+}
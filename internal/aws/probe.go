@@ -0,0 +1,62 @@
+package aws
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	awsconfig "github.com/aws/aws-sdk-go-v2/config"
+	"github.com/aws/aws-sdk-go-v2/service/bedrock"
+	"github.com/aws/aws-sdk-go-v2/service/bedrock/types"
+	"github.com/aws/smithy-go"
+)
+
+const probeTimeout = 10 * time.Second
+
+// ProbeRegion checks whether Bedrock's ListInferenceProfiles is usable for an AWS profile in a region
+// Returns available=false with a nil error when the region definitely cannot be used
+// (no Bedrock endpoint, region not enabled, or access denied), and a non-nil error when
+// the outcome is unknown (e.g., credentials could not be loaded)
+func ProbeRegion(awsProfile, region string) (available bool, err error) {
+	ctx, cancel := context.WithTimeout(context.Background(), probeTimeout)
+	defer cancel()
+
+	// Load AWS config
+	awsCfg, err := loadAWSConfig(ctx, awsProfile, awsconfig.WithRegion(region))
+	if err != nil {
+		return false, fmt.Errorf("failed to load AWS config: %w", err)
+	}
+
+	// Create Bedrock client without retries so unusable regions fail fast
+	client := bedrock.NewFromConfig(awsCfg, func(o *bedrock.Options) {
+		o.RetryMaxAttempts = 1
+	})
+
+	_, err = client.ListInferenceProfiles(ctx, &bedrock.ListInferenceProfilesInput{
+		TypeEquals: types.InferenceProfileTypeSystemDefined,
+		MaxResults: aws.Int32(1),
+	})
+	if err == nil {
+		return true, nil
+	}
+
+	// No Bedrock endpoint in this region
+	var dnsErr *net.DNSError
+	if errors.As(err, &dnsErr) {
+		return false, nil
+	}
+
+	// Region not enabled for the account, or blocked by IAM/SCP policy
+	var apiErr smithy.APIError
+	if errors.As(err, &apiErr) {
+		switch apiErr.ErrorCode() {
+		case "AccessDeniedException", "UnrecognizedClientException", "InvalidSignatureException":
+			return false, nil
+		}
+	}
+
+	return false, err
+}
@@ -0,0 +1,121 @@
+package aws
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/bedrock/types"
+)
+
+// profileCacheTTL is how long the inference profiles listed for a region are reused
+// by the config wizard and model lookups before Bedrock is asked again
+const profileCacheTTL = 6 * time.Hour
+
+// profileCacheEntry is the cached inference profile listing for one AWS profile and region
+type profileCacheEntry struct {
+	Updated    time.Time `json:"updated"`
+	ProfileIDs []string  `json:"profile-ids"`
+}
+
+var (
+	profileCacheMu        sync.Mutex
+	profileCacheNotBefore time.Time
+)
+
+// IgnoreCachedModelLists makes model lookups fetch from Bedrock instead of using listings
+// cached before now; listings fetched afterwards are reused as usual
+func IgnoreCachedModelLists() {
+	profileCacheMu.Lock()
+	profileCacheNotBefore = time.Now()
+	profileCacheMu.Unlock()
+}
+
+// profileCachePath returns where the listing for an AWS profile and region is cached
+// Listings differ between accounts, so each set of credentials gets its own file
+func profileCachePath(awsProfile, region string) (string, error) {
+	if region == "" || strings.ContainsAny(region, `/\.`) {
+		return "", fmt.Errorf("invalid region '%s'", region)
+	}
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return "", fmt.Errorf("failed to get home directory: %w", err)
+	}
+	return filepath.Join(home, ".clauderock", "cache", "models-"+region+"-"+credentialScope(awsProfile)+".json"), nil
+}
+
+// credentialScope identifies the credentials a listing was made with, without putting
+// profile names or key IDs in file names: the shared profile, or for credentials the SDK
+// finds on its own, the variables that select them
+func credentialScope(awsProfile string) string {
+	identity := "profile:" + awsProfile
+	if awsProfile == "" {
+		identity = strings.Join([]string{
+			"env",
+			os.Getenv("AWS_PROFILE"),
+			os.Getenv("AWS_ACCESS_KEY_ID"),
+			os.Getenv("AWS_ROLE_ARN"),
+		}, ":")
+	}
+	sum := sha256.Sum256([]byte(identity))
+	return hex.EncodeToString(sum[:6])
+}
+
+// cachedInferenceProfiles returns the cached listing for an AWS profile and region while it's fresh
+func cachedInferenceProfiles(awsProfile, region string) ([]types.InferenceProfileSummary, bool) {
+	path, err := profileCachePath(awsProfile, region)
+	if err != nil {
+		return nil, false
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, false
+	}
+	var entry profileCacheEntry
+	if err := json.Unmarshal(data, &entry); err != nil || len(entry.ProfileIDs) == 0 {
+		return nil, false
+	}
+
+	profileCacheMu.Lock()
+	notBefore := profileCacheNotBefore
+	profileCacheMu.Unlock()
+	if time.Since(entry.Updated) > profileCacheTTL || entry.Updated.Before(notBefore) {
+		return nil, false
+	}
+
+	profiles := make([]types.InferenceProfileSummary, len(entry.ProfileIDs))
+	for i, id := range entry.ProfileIDs {
+		profiles[i] = types.InferenceProfileSummary{InferenceProfileId: aws.String(id)}
+	}
+	return profiles, true
+}
+
+// saveInferenceProfiles caches the listing for an AWS profile and region; the cache only saves
+// time, so errors are ignored
+func saveInferenceProfiles(awsProfile, region string, profiles []types.InferenceProfileSummary) {
+	path, err := profileCachePath(awsProfile, region)
+	if err != nil {
+		return
+	}
+	entry := profileCacheEntry{Updated: time.Now()}
+	for _, profile := range profiles {
+		if profile.InferenceProfileId != nil {
+			entry.ProfileIDs = append(entry.ProfileIDs, aws.ToString(profile.InferenceProfileId))
+		}
+	}
+	data, err := json.MarshalIndent(entry, "", "  ")
+	if err != nil {
+		return
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return
+	}
+	_ = os.WriteFile(path, data, 0644)
+}
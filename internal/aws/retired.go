@@ -0,0 +1,151 @@
+package aws
+
+import (
+	"strings"
+
+	"github.com/OlaHulleberg/clauderock/internal/config"
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/bedrock/types"
+)
+
+// Remap moves a model slot off an inference profile that no longer exists
+// To is "" when the region has no newer version of the model to move to
+type Remap struct {
+	Slot string
+	From string
+	To   string
+}
+
+// RetiredModelsError is a failed model validation explained by retired models, carrying the
+// remaps that would fix it
+type RetiredModelsError struct {
+	Remaps []Remap
+	Err    error
+}
+
+func (e *RetiredModelsError) Error() string {
+	return e.Err.Error()
+}
+
+func (e *RetiredModelsError) Unwrap() error {
+	return e.Err
+}
+
+// ValidateModels checks the profile's model slots exist in its region, like ValidateProfileIDs
+// When they don't because models were retired, the error is a *RetiredModelsError
+func ValidateModels(cfg *config.Config, profileIDs ...string) error {
+	err := ValidateProfileIDs(cfg.AWSProfile(), cfg.Region, profileIDs...)
+	if err == nil {
+		return nil
+	}
+	if remaps, findErr := FindRetiredModels(cfg, true); findErr == nil && len(remaps) > 0 {
+		return &RetiredModelsError{Remaps: remaps, Err: err}
+	}
+	return err
+}
+
+// FindRetiredModels returns a remap for each model slot of a Bedrock profile whose inference profile
+// ID is gone from the region, e.g. because the model was retired
+// Without fetch, only a fresh cached model list is consulted, so the check costs nothing at launch;
+// IDs missing from the cache are always confirmed with Bedrock before they count as retired
+func FindRetiredModels(cfg *config.Config, fetch bool) ([]Remap, error) {
+	slotIDs := make(map[string]string)
+	var ids []string
+	for _, slot := range config.ModelSlots {
+		id, _ := cfg.ModelForSlot(slot)
+		if IsFullProfileID(id) {
+			slotIDs[slot] = id
+			ids = append(ids, id)
+		}
+	}
+	if len(ids) == 0 {
+		return nil, nil
+	}
+
+	profiles, cached := cachedInferenceProfiles(cfg.AWSProfile(), cfg.Region)
+	if cached && missingProfileID(profiles, ids) == "" {
+		return nil, nil
+	}
+	if !cached && !fetch {
+		return nil, nil
+	}
+	profiles, err := fetchSystemInferenceProfiles(cfg.AWSProfile(), cfg.Region)
+	if err != nil {
+		return nil, err
+	}
+
+	available := make(map[string]bool)
+	for _, profile := range profiles {
+		available[aws.ToString(profile.InferenceProfileId)] = true
+	}
+	var remaps []Remap
+	for _, slot := range config.ModelSlots {
+		id, ok := slotIDs[slot]
+		if !ok || available[id] {
+			continue
+		}
+		remaps = append(remaps, Remap{Slot: slot, From: id, To: findReplacement(id, profiles)})
+	}
+	return remaps, nil
+}
+
+// findReplacement returns the closest newer profile ID for a retired one: the next snapshot of the
+// same model, or else the newest snapshot of the next version of its family
+// (e.g., claude-sonnet-4 to claude-sonnet-4-5, not straight to the newest Sonnet)
+func findReplacement(retired string, profiles []types.InferenceProfileSummary) string {
+	model := ExtractFriendlyModelName(retired)
+	crossRegion, _, _ := strings.Cut(retired, ".")
+
+	// Candidates are ordered newest first, so the closest newer snapshot is the last newer one
+	candidates := matchingProfileIDs(profiles, crossRegion, model)
+	for i := len(candidates) - 1; i >= 0; i-- {
+		if newerSnapshot(candidates[i], retired) {
+			return candidates[i]
+		}
+	}
+
+	family, version, ok := ModelFamilyVersion(model)
+	if !ok {
+		return ""
+	}
+	provider, _, _ := parseModelName(model)
+
+	closest := ""
+	var closestVersion []int
+	for _, candidate := range modelsForCrossRegion(profiles, crossRegion) {
+		candidateProvider, _, _ := parseModelName(candidate)
+		candidateFamily, candidateVersion, ok := ModelFamilyVersion(candidate)
+		if !ok || candidateProvider != provider || candidateFamily != family || CompareModelVersions(candidateVersion, version) <= 0 {
+			continue
+		}
+		if closest == "" || CompareModelVersions(candidateVersion, closestVersion) < 0 {
+			closest, closestVersion = candidate, candidateVersion
+		}
+	}
+	if closest == "" {
+		return ""
+	}
+	if ids := matchingProfileIDs(profiles, crossRegion, closest); len(ids) > 0 {
+		return ids[0]
+	}
+	return ""
+}
+
+// ApplyRemaps moves the slots to their replacements, keeping pinned slots pinned to the new snapshot
+// Remaps without a replacement are skipped
+func ApplyRemaps(cfg *config.Config, remaps []Remap) {
+	for _, r := range remaps {
+		if r.To == "" {
+			continue
+		}
+		pinned := cfg.PinnedVersion(r.Slot) != ""
+		cfg.SetModelForSlot(r.Slot, r.To)
+		if pinned {
+			if version := ExtractVersionDate(r.To); version != "" {
+				cfg.PinVersion(r.Slot, version)
+			} else {
+				cfg.UnpinVersion(r.Slot)
+			}
+		}
+	}
+}
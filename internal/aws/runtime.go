@@ -0,0 +1,69 @@
+package aws
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	awsconfig "github.com/aws/aws-sdk-go-v2/config"
+	"github.com/aws/aws-sdk-go-v2/service/bedrockruntime"
+	runtimetypes "github.com/aws/aws-sdk-go-v2/service/bedrockruntime/types"
+)
+
+const (
+	testPrompt    = "Reply with OK."
+	testMaxTokens = 5
+	testTimeout   = 60 * time.Second
+)
+
+// TestResult reports the outcome of a successful test request
+type TestResult struct {
+	Latency      time.Duration
+	InputTokens  int64
+	OutputTokens int64
+}
+
+// TestModel sends a minimal Converse request to verify end-to-end access to a model
+// This exercises credentials, IAM policy, and model access grants in one call
+// Returns the round-trip latency and token usage of the request
+func TestModel(awsProfile, region, profileID string) (TestResult, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), testTimeout)
+	defer cancel()
+
+	// Load AWS config
+	awsCfg, err := loadAWSConfig(ctx, awsProfile, awsconfig.WithRegion(region))
+	if err != nil {
+		return TestResult{}, fmt.Errorf("failed to load AWS config: %w", err)
+	}
+
+	// Create Bedrock runtime client
+	client := bedrockruntime.NewFromConfig(awsCfg)
+
+	start := time.Now()
+	output, err := client.Converse(ctx, &bedrockruntime.ConverseInput{
+		ModelId: aws.String(profileID),
+		Messages: []runtimetypes.Message{
+			{
+				Role: runtimetypes.ConversationRoleUser,
+				Content: []runtimetypes.ContentBlock{
+					&runtimetypes.ContentBlockMemberText{Value: testPrompt},
+				},
+			},
+		},
+		InferenceConfig: &runtimetypes.InferenceConfiguration{
+			MaxTokens: aws.Int32(testMaxTokens),
+		},
+	})
+	result := TestResult{Latency: time.Since(start)}
+	if err != nil {
+		return result, fmt.Errorf("converse request failed: %w", err)
+	}
+
+	if output.Usage != nil {
+		result.InputTokens = int64(aws.ToInt32(output.Usage.InputTokens))
+		result.OutputTokens = int64(aws.ToInt32(output.Usage.OutputTokens))
+	}
+
+	return result, nil
+}
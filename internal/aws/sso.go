@@ -0,0 +1,172 @@
+package aws
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"time"
+
+	awsconfig "github.com/aws/aws-sdk-go-v2/config"
+	"github.com/aws/aws-sdk-go-v2/credentials/ssocreds"
+	"github.com/aws/smithy-go"
+)
+
+// ssoCheckTimeout bounds the credential refresh tried when the cached SSO token has expired
+const ssoCheckTimeout = 10 * time.Second
+
+// SSOStatus describes the SSO login an AWS profile's credentials come from
+type SSOStatus struct {
+	LoginProfile string    // Profile to pass to 'aws sso login'; a source_profile for chained roles
+	ExpiresAt    time.Time // Zero when there's no cached token
+	Expired      bool
+}
+
+// ssoCachedToken is the subset of an ~/.aws/sso/cache token file used here
+type ssoCachedToken struct {
+	AccessToken  string `json:"accessToken"`
+	ExpiresAt    string `json:"expiresAt"`
+	RefreshToken string `json:"refreshToken"`
+}
+
+// expiry parses the token's expiry; older AWS CLI versions wrote "UTC" instead of "Z"
+func (t ssoCachedToken) expiry() (time.Time, bool) {
+	for _, layout := range []string{time.RFC3339, "2006-01-02T15:04:05UTC"} {
+		if expires, err := time.Parse(layout, t.ExpiresAt); err == nil {
+			return expires, true
+		}
+	}
+	return time.Time{}, false
+}
+
+// CheckSSOSession checks whether the SSO login behind an AWS profile is still valid
+// Returns nil when the profile's credentials don't come from SSO, including when awsProfile is
+// empty and credentials come from the environment or the default chain
+// The cached token is read from disk; AWS is only called when an expired token might be refreshable
+func CheckSSOSession(awsProfile string) (*SSOStatus, error) {
+	if awsProfile == "" {
+		return nil, nil
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), ssoCheckTimeout)
+	defer cancel()
+
+	cacheKey, loginProfile, err := ssoSource(ctx, awsProfile)
+	if err != nil || cacheKey == "" {
+		return nil, err
+	}
+
+	status := &SSOStatus{LoginProfile: loginProfile, Expired: true}
+
+	path, err := ssocreds.StandardCachedTokenFilepath(cacheKey)
+	if err != nil {
+		return nil, err
+	}
+	data, err := os.ReadFile(path)
+	if os.IsNotExist(err) {
+		return status, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read SSO token cache: %w", err)
+	}
+	var token ssoCachedToken
+	if err := json.Unmarshal(data, &token); err != nil || token.AccessToken == "" {
+		return status, nil
+	}
+
+	expires, ok := token.expiry()
+	if !ok {
+		return status, nil
+	}
+	status.ExpiresAt = expires
+	if time.Now().Before(expires) {
+		status.Expired = false
+		return status, nil
+	}
+	if token.RefreshToken == "" {
+		return status, nil
+	}
+
+	// sso-session logins carry a refresh token the SDK uses to renew the expired one
+	awsCfg, err := loadAWSConfig(ctx, awsProfile)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load AWS config: %w", err)
+	}
+	if _, err := awsCfg.Credentials.Retrieve(ctx); err != nil {
+		if isSSOTokenError(err) {
+			return status, nil
+		}
+		return nil, fmt.Errorf("failed to refresh SSO credentials: %w", err)
+	}
+	status.Expired = false
+	return status, nil
+}
+
+// SSOLoginProfile returns the profile to pass to 'aws sso login' for an AWS profile, or ""
+// when the profile's credentials don't come from SSO. Only ~/.aws/config is read
+func SSOLoginProfile(awsProfile string) (string, error) {
+	if awsProfile == "" {
+		return "", nil
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), ssoCheckTimeout)
+	defer cancel()
+
+	_, loginProfile, err := ssoSource(ctx, awsProfile)
+	return loginProfile, err
+}
+
+// ssoSource finds the profile with sso_* settings an AWS profile's credentials come from,
+// returning the key its token is cached under and the profile's name; both are empty when
+// the credentials don't come from SSO
+func ssoSource(ctx context.Context, awsProfile string) (cacheKey, loginProfile string, err error) {
+	shared, err := awsconfig.LoadSharedConfigProfile(ctx, awsProfile)
+	if err != nil {
+		return "", "", fmt.Errorf("failed to load AWS profile '%s': %w", awsProfile, err)
+	}
+
+	// Assumed roles get their credentials from the end of the source_profile chain
+	for c := &shared; c != nil; c = c.Source {
+		cacheKey = c.SSOSessionName
+		if cacheKey == "" {
+			cacheKey = c.SSOStartURL
+		}
+		if cacheKey != "" {
+			return cacheKey, c.Profile, nil
+		}
+	}
+	return "", "", nil
+}
+
+// isSSOTokenError reports whether an error means the SSO login has to be repeated
+func isSSOTokenError(err error) bool {
+	var tokenErr *ssocreds.InvalidTokenError
+	if errors.As(err, &tokenErr) {
+		return true
+	}
+	var apiErr smithy.APIError
+	if errors.As(err, &apiErr) {
+		switch apiErr.ErrorCode() {
+		case "InvalidGrantException", "ExpiredTokenException", "UnauthorizedException":
+			return true
+		}
+	}
+	return false
+}
+
+// SSOLogin runs 'aws sso login' for a profile, letting it open the browser and print to the terminal
+func SSOLogin(awsProfile string) error {
+	awsPath, err := exec.LookPath("aws")
+	if err != nil {
+		return fmt.Errorf("AWS CLI not found in PATH; install it or log in elsewhere with: aws sso login --profile %s", awsProfile)
+	}
+
+	cmd := exec.Command(awsPath, "sso", "login", "--profile", awsProfile)
+	cmd.Stdin = os.Stdin
+	cmd.Stdout = os.Stdout
+	cmd.Stderr = os.Stderr
+	if err := cmd.Run(); err != nil {
+		return fmt.Errorf("aws sso login failed: %w", err)
+	}
+	return nil
+}
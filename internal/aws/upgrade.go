@@ -0,0 +1,118 @@
+package aws
+
+import (
+	"strconv"
+	"strings"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/bedrock/types"
+)
+
+// modelFamilies are the model families upgrades may move between versions of, and that
+// unlisted models are priced by
+var modelFamilies = []string{"opus", "sonnet", "haiku"}
+
+// FindUpgrade returns a newer profile ID for current among profileIDs, or "" when it's the newest
+// Without major, only newer snapshots of the same model count; with major, newer versions
+// of the same family do too (e.g., claude-sonnet-4-5 to claude-sonnet-4-6)
+func FindUpgrade(current, crossRegion string, profileIDs []string, major bool) string {
+	if !IsFullProfileID(current) || len(profileIDs) == 0 {
+		return ""
+	}
+
+	profiles := make([]types.InferenceProfileSummary, len(profileIDs))
+	for i, id := range profileIDs {
+		profiles[i] = types.InferenceProfileSummary{InferenceProfileId: aws.String(id)}
+	}
+
+	model := ExtractFriendlyModelName(current)
+	if major {
+		model = newestInFamily(model, modelsForCrossRegion(profiles, crossRegion))
+	}
+
+	candidates := matchingProfileIDs(profiles, crossRegion, model)
+	if len(candidates) == 0 || candidates[0] == current {
+		return ""
+	}
+	if model == ExtractFriendlyModelName(current) && !newerSnapshot(candidates[0], current) {
+		return ""
+	}
+	return candidates[0]
+}
+
+// newerSnapshot reports whether profile ID a is a newer snapshot than b, ordered like matchingProfileIDs
+func newerSnapshot(a, b string) bool {
+	dateA, dateB := ExtractVersionDate(a), ExtractVersionDate(b)
+	if dateA != dateB {
+		return dateA > dateB
+	}
+	return a > b
+}
+
+// newestInFamily returns the newest version of model's family among models, or model itself
+func newestInFamily(model string, models []string) string {
+	family, version, ok := ModelFamilyVersion(model)
+	if !ok {
+		return model
+	}
+	provider, _, _ := parseModelName(model)
+
+	newest := model
+	for _, candidate := range models {
+		candidateProvider, _, _ := parseModelName(candidate)
+		candidateFamily, candidateVersion, ok := ModelFamilyVersion(candidate)
+		if !ok || candidateProvider != provider || candidateFamily != family {
+			continue
+		}
+		if CompareModelVersions(candidateVersion, version) > 0 {
+			newest, version = candidate, candidateVersion
+		}
+	}
+	return newest
+}
+
+// ModelFamilyVersion splits a friendly model name into its family and version numbers; a
+// dated snapshot suffix is ignored
+// Input: "anthropic.claude-sonnet-4-5" or "anthropic.claude-3-5-sonnet"
+// Output: "sonnet", [4 5] or "sonnet", [3 5]
+func ModelFamilyVersion(model string) (family string, version []int, ok bool) {
+	_, name, ok := parseModelName(model)
+	if !ok {
+		return "", nil, false
+	}
+
+	for _, part := range strings.Split(name, "-") {
+		for _, f := range modelFamilies {
+			if part == f {
+				family = f
+			}
+		}
+		// Version parts are short numbers; longer ones are snapshot dates
+		if n, err := strconv.Atoi(part); err == nil && len(part) <= 2 {
+			version = append(version, n)
+		}
+	}
+	if family == "" || len(version) == 0 {
+		return "", nil, false
+	}
+	return family, version, true
+}
+
+// CompareModelVersions compares version numbers part by part; a longer version wins a tie (4.1 > 4)
+func CompareModelVersions(a, b []int) int {
+	for i := 0; i < len(a) && i < len(b); i++ {
+		if a[i] != b[i] {
+			if a[i] > b[i] {
+				return 1
+			}
+			return -1
+		}
+	}
+	switch {
+	case len(a) > len(b):
+		return 1
+	case len(a) < len(b):
+		return -1
+	}
+	return 0
+}
@@ -1,45 +1,169 @@
 package awsutil
 
 import (
+	"errors"
 	"fmt"
 	"os"
 	"path/filepath"
+	"sort"
 	"strings"
 
 	"gopkg.in/ini.v1"
 )
 
+// ErrNoProfiles is returned by GetProfiles when no usable AWS profile exists
+var ErrNoProfiles = errors.New("no AWS profiles found")
+
+// configFilePath returns the path to the shared AWS config file, honoring AWS_CONFIG_FILE
+func configFilePath() (string, error) {
+	return sharedFilePath("AWS_CONFIG_FILE", "config")
+}
+
+// credentialsFilePath returns the path to the shared AWS credentials file, honoring AWS_SHARED_CREDENTIALS_FILE
+func credentialsFilePath() (string, error) {
+	return sharedFilePath("AWS_SHARED_CREDENTIALS_FILE", "credentials")
+}
+
+// sharedFilePath resolves a shared AWS file from its env override or ~/.aws/<name>
+func sharedFilePath(envVar, name string) (string, error) {
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return "", fmt.Errorf("failed to get user home directory: %w", err)
+	}
+
+	if override := os.Getenv(envVar); override != "" {
+		// Match the AWS CLI's tilde expansion for env overrides
+		if override == "~" {
+			return home, nil
+		}
+		if strings.HasPrefix(override, "~/") {
+			return filepath.Join(home, override[2:]), nil
+		}
+		return override, nil
+	}
+
+	return filepath.Join(home, ".aws", name), nil
+}
+
+// loadSharedFile parses a shared AWS file leniently: malformed lines are skipped
+// and AWS-style nested values (e.g. "s3 =\n  max_concurrent_requests = 10") are accepted
+func loadSharedFile(path string) (*ini.File, error) {
+	return ini.LoadSources(ini.LoadOptions{
+		Loose:                   true,
+		SkipUnrecognizableLines: true,
+		AllowNestedValues:       true,
+	}, path)
+}
+
+// ProfileInfo describes an AWS profile with display hints parsed from ~/.aws/config
+type ProfileInfo struct {
+	Name        string
+	Region      string // Configured default region
+	SSOStartURL string // From sso_start_url or the referenced sso-session
+	AccountID   string // From sso_account_id or role_arn
+	RoleName    string // From sso_role_name or role_arn
+
+	SourceProfile     string   // Profile whose credentials are used to assume role_arn
+	CredentialProcess string   // External command that supplies credentials
+	CredentialChain   []string // Resolved source_profile chain, e.g. ["dev", "root"]
+}
+
+// Summary returns a short description of the profile's metadata for selectors
+// Example: "us-east-1 · sso: my-org.awsapps.com · account 123456789012"
+func (p ProfileInfo) Summary() string {
+	var parts []string
+	if p.Region != "" {
+		parts = append(parts, p.Region)
+	}
+	if p.SSOStartURL != "" {
+		host := strings.TrimPrefix(strings.TrimPrefix(p.SSOStartURL, "https://"), "http://")
+		host = strings.TrimSuffix(strings.TrimSuffix(host, "/"), "/start")
+		parts = append(parts, "sso: "+host)
+	}
+	if p.AccountID != "" {
+		account := "account " + p.AccountID
+		if p.RoleName != "" {
+			account += " (" + p.RoleName + ")"
+		}
+		parts = append(parts, account)
+	}
+	if len(p.CredentialChain) > 0 {
+		parts = append(parts, "via "+strings.Join(p.CredentialChain, " → "))
+	}
+	if p.CredentialProcess != "" {
+		parts = append(parts, "process: "+credentialProcessName(p.CredentialProcess))
+	}
+	return strings.Join(parts, " · ")
+}
+
 // GetProfiles returns a list of available AWS profiles from ~/.aws/config and ~/.aws/credentials
+// Returns an error wrapping ErrNoProfiles when none are configured
 func GetProfiles() ([]string, error) {
-	home, err := os.UserHomeDir()
+	details, err := GetProfileDetails()
 	if err != nil {
-		return nil, fmt.Errorf("failed to get user home directory: %w", err)
+		return nil, err
+	}
+
+	profiles := make([]string, len(details))
+	for i, p := range details {
+		profiles[i] = p.Name
 	}
+	return profiles, nil
+}
 
-	profileMap := make(map[string]bool)
+// GetProfileDetails returns all AWS profiles with region, SSO, and account hints, sorted by name
+// Returns an error wrapping ErrNoProfiles when none are configured
+func GetProfileDetails() ([]ProfileInfo, error) {
+	configPath, err := configFilePath()
+	if err != nil {
+		return nil, err
+	}
+	credentialsPath, err := credentialsFilePath()
+	if err != nil {
+		return nil, err
+	}
 
-	// Parse credentials file
-	credentialsPath := filepath.Join(home, ".aws", "credentials")
-	if _, err := os.Stat(credentialsPath); err == nil {
-		cfg, err := ini.Load(credentialsPath)
-		if err != nil {
-			return nil, fmt.Errorf("failed to parse credentials file: %w", err)
+	profileMap := make(map[string]*ProfileInfo)
+	getProfile := func(name string) *ProfileInfo {
+		if p, ok := profileMap[name]; ok {
+			return p
 		}
+		p := &ProfileInfo{Name: name}
+		profileMap[name] = p
+		return p
+	}
 
+	// A file that still fails to parse is skipped rather than failing the wizard;
+	// the error is only surfaced if no profiles could be found at all
+	var parseErrs []error
+
+	// Parse credentials file (a missing file loads as empty)
+	if cfg, err := loadSharedFile(credentialsPath); err != nil {
+		parseErrs = append(parseErrs, fmt.Errorf("failed to parse credentials file %s: %w", credentialsPath, err))
+	} else {
 		for _, section := range cfg.Sections() {
 			name := section.Name()
-			if name != "DEFAULT" && name != "" {
-				profileMap[name] = true
+			if name == "DEFAULT" || name == "" {
+				continue
+			}
+			p := getProfile(name)
+			if process := section.Key("credential_process").String(); process != "" {
+				p.CredentialProcess = process
 			}
 		}
 	}
 
-	// Parse config file
-	configPath := filepath.Join(home, ".aws", "config")
-	if _, err := os.Stat(configPath); err == nil {
-		cfg, err := ini.Load(configPath)
-		if err != nil {
-			return nil, fmt.Errorf("failed to parse config file: %w", err)
+	// Parse config file (a missing file loads as empty)
+	if cfg, err := loadSharedFile(configPath); err != nil {
+		parseErrs = append(parseErrs, fmt.Errorf("failed to parse config file %s: %w", configPath, err))
+	} else {
+		// Collect sso-session start URLs so profiles referencing them can show it
+		ssoSessions := make(map[string]string)
+		for _, section := range cfg.Sections() {
+			if strings.HasPrefix(section.Name(), "sso-session ") {
+				sessionName := strings.TrimPrefix(section.Name(), "sso-session ")
+				ssoSessions[sessionName] = section.Key("sso_start_url").String()
+			}
 		}
 
 		for _, section := range cfg.Sections() {
@@ -48,32 +172,102 @@ func GetProfiles() ([]string, error) {
 			if name == "DEFAULT" || name == "" || strings.HasPrefix(name, "sso-session ") {
 				continue
 			}
-			// Only process sections that start with "profile "
+
+			// Profiles are "profile <name>", except the bare "default" section
+			var profileName string
 			if strings.HasPrefix(name, "profile ") {
-				profileName := strings.TrimPrefix(name, "profile ")
-				profileMap[profileName] = true
+				profileName = strings.TrimPrefix(name, "profile ")
+			} else if name == "default" {
+				profileName = name
+			} else {
+				continue
+			}
+
+			p := getProfile(profileName)
+			p.Region = section.Key("region").String()
+			p.SSOStartURL = section.Key("sso_start_url").String()
+			if p.SSOStartURL == "" {
+				p.SSOStartURL = ssoSessions[section.Key("sso_session").String()]
+			}
+			p.AccountID = section.Key("sso_account_id").String()
+			p.RoleName = section.Key("sso_role_name").String()
+
+			// Assumed-role profiles: arn:aws:iam::<account>:role/<name>
+			if roleARN := section.Key("role_arn").String(); roleARN != "" && p.AccountID == "" {
+				p.AccountID, p.RoleName = parseRoleARN(roleARN)
+			}
+			p.SourceProfile = section.Key("source_profile").String()
+			if process := section.Key("credential_process").String(); process != "" {
+				p.CredentialProcess = process
 			}
 		}
 	}
 
+	for _, p := range profileMap {
+		p.CredentialChain = resolveSourceChain(p.Name, profileMap)
+	}
+
 	// If no profiles found, check if files exist
 	if len(profileMap) == 0 {
 		if _, err := os.Stat(credentialsPath); os.IsNotExist(err) {
 			if _, err := os.Stat(configPath); os.IsNotExist(err) {
-				return nil, fmt.Errorf("no AWS config files found. Please run 'aws configure' to set up your AWS credentials")
+				return nil, fmt.Errorf("%w (no AWS config files found). Please run 'aws configure' to set up your AWS credentials", ErrNoProfiles)
 			}
 		}
+		if len(parseErrs) > 0 {
+			return nil, fmt.Errorf("%w: %w", ErrNoProfiles, errors.Join(parseErrs...))
+		}
+		return nil, fmt.Errorf("%w. Please run 'aws configure' to set up your AWS credentials", ErrNoProfiles)
+	}
+
+	// Convert map to sorted slice
+	profiles := make([]ProfileInfo, 0, len(profileMap))
+	for _, p := range profileMap {
+		profiles = append(profiles, *p)
 	}
+	sort.Slice(profiles, func(i, j int) bool {
+		return profiles[i].Name < profiles[j].Name
+	})
+
+	return profiles, nil
+}
 
-	// Convert map to slice
-	profiles := make([]string, 0, len(profileMap))
-	for profile := range profileMap {
-		profiles = append(profiles, profile)
+// parseRoleARN extracts the account ID and role name from an IAM role ARN
+// Input: "arn:aws:iam::123456789012:role/Admin" → "123456789012", "Admin"
+func parseRoleARN(arn string) (accountID, roleName string) {
+	parts := strings.SplitN(arn, ":", 6)
+	if len(parts) != 6 {
+		return "", ""
 	}
+	return parts[4], strings.TrimPrefix(parts[5], "role/")
+}
+
+// resolveSourceChain follows source_profile references starting at name
+// Returns the chain of source profiles (excluding name itself), stopping at cycles or unknown profiles
+func resolveSourceChain(name string, profiles map[string]*ProfileInfo) []string {
+	var chain []string
+	visited := map[string]bool{name: true}
 
-	if len(profiles) == 0 {
-		return nil, fmt.Errorf("no AWS profiles found. Please run 'aws configure' to set up your AWS credentials")
+	current := profiles[name]
+	for current != nil && current.SourceProfile != "" {
+		next := current.SourceProfile
+		if visited[next] {
+			chain = append(chain, next+" (cycle)")
+			break
+		}
+		visited[next] = true
+		chain = append(chain, next)
+		current = profiles[next]
 	}
+	return chain
+}
 
-	return profiles, nil
+// credentialProcessName returns the executable name of a credential_process command for display
+// Input: "/usr/local/bin/aws-vault exec dev --json" → "aws-vault"
+func credentialProcessName(process string) string {
+	fields := strings.Fields(process)
+	if len(fields) == 0 {
+		return ""
+	}
+	return filepath.Base(strings.Trim(fields[0], `"'`))
 }
@@ -1,60 +1,125 @@
 package awsutil
 
+import (
+	"fmt"
+	"sort"
+
+	"github.com/aws/aws-sdk-go-v2/service/bedrock"
+)
+
 // Region represents an AWS region with its identifier and description
 type Region struct {
-	ID   string
-	Name string
+	ID      string
+	Name    string
+	Bedrock bool // Bedrock has a regional endpoint per the SDK's endpoints metadata
 }
 
+// curatedRegions ranks the most commonly used regions first for better UX
+// Regions missing here are still discovered from the SDK endpoints metadata
+var curatedRegions = []Region{
+	// Most common regions first
+	{ID: "us-east-1", Name: "US East (N. Virginia)"},
+	{ID: "us-west-2", Name: "US West (Oregon)"},
+	{ID: "eu-west-1", Name: "Europe (Ireland)"},
+	{ID: "eu-central-1", Name: "Europe (Frankfurt)"},
+	{ID: "eu-north-1", Name: "Europe (Stockholm)"},
+	{ID: "ap-southeast-1", Name: "Asia Pacific (Singapore)"},
+	{ID: "ap-northeast-1", Name: "Asia Pacific (Tokyo)"},
+
+	// US regions
+	{ID: "us-east-2", Name: "US East (Ohio)"},
+	{ID: "us-west-1", Name: "US West (N. California)"},
+
+	// Europe regions
+	{ID: "eu-west-2", Name: "Europe (London)"},
+	{ID: "eu-west-3", Name: "Europe (Paris)"},
+	{ID: "eu-south-1", Name: "Europe (Milan)"},
+	{ID: "eu-south-2", Name: "Europe (Spain)"},
+	{ID: "eu-central-2", Name: "Europe (Zurich)"},
+
+	// Asia Pacific regions
+	{ID: "ap-south-1", Name: "Asia Pacific (Mumbai)"},
+	{ID: "ap-south-2", Name: "Asia Pacific (Hyderabad)"},
+	{ID: "ap-northeast-2", Name: "Asia Pacific (Seoul)"},
+	{ID: "ap-northeast-3", Name: "Asia Pacific (Osaka)"},
+	{ID: "ap-southeast-2", Name: "Asia Pacific (Sydney)"},
+	{ID: "ap-southeast-3", Name: "Asia Pacific (Jakarta)"},
+	{ID: "ap-southeast-4", Name: "Asia Pacific (Melbourne)"},
+	{ID: "ap-east-1", Name: "Asia Pacific (Hong Kong)"},
+
+	// Canada
+	{ID: "ca-central-1", Name: "Canada (Central)"},
+	{ID: "ca-west-1", Name: "Canada (Calgary)"},
+
+	// South America
+	{ID: "sa-east-1", Name: "South America (São Paulo)"},
+
+	// Middle East
+	{ID: "me-south-1", Name: "Middle East (Bahrain)"},
+	{ID: "me-central-1", Name: "Middle East (UAE)"},
+
+	// Africa
+	{ID: "af-south-1", Name: "Africa (Cape Town)"},
+
+	// Israel
+	{ID: "il-central-1", Name: "Israel (Tel Aviv)"},
+}
+
+// Candidate components for region IDs probed against the endpoints metadata
+var (
+	regionGeographies = []string{"us", "eu", "ap", "ca", "sa", "me", "af", "il", "mx"}
+	regionDirections  = []string{"east", "west", "north", "south", "central", "northeast", "northwest", "southeast", "southwest"}
+)
+
+const maxRegionNumber = 9
+
 // GetRegions returns a list of all AWS regions
-// Most commonly used regions are listed first for better UX
+// Curated regions come first in ranking order, followed by any additional
+// Bedrock regions found in the SDK endpoints metadata
 func GetRegions() []Region {
-	return []Region{
-		// Most common regions first
-		{ID: "us-east-1", Name: "US East (N. Virginia)"},
-		{ID: "us-west-2", Name: "US West (Oregon)"},
-		{ID: "eu-west-1", Name: "Europe (Ireland)"},
-		{ID: "eu-central-1", Name: "Europe (Frankfurt)"},
-		{ID: "eu-north-1", Name: "Europe (Stockholm)"},
-		{ID: "ap-southeast-1", Name: "Asia Pacific (Singapore)"},
-		{ID: "ap-northeast-1", Name: "Asia Pacific (Tokyo)"},
-
-		// US regions
-		{ID: "us-east-2", Name: "US East (Ohio)"},
-		{ID: "us-west-1", Name: "US West (N. California)"},
-
-		// Europe regions
-		{ID: "eu-west-2", Name: "Europe (London)"},
-		{ID: "eu-west-3", Name: "Europe (Paris)"},
-		{ID: "eu-south-1", Name: "Europe (Milan)"},
-		{ID: "eu-south-2", Name: "Europe (Spain)"},
-		{ID: "eu-central-2", Name: "Europe (Zurich)"},
-
-		// Asia Pacific regions
-		{ID: "ap-south-1", Name: "Asia Pacific (Mumbai)"},
-		{ID: "ap-south-2", Name: "Asia Pacific (Hyderabad)"},
-		{ID: "ap-northeast-2", Name: "Asia Pacific (Seoul)"},
-		{ID: "ap-northeast-3", Name: "Asia Pacific (Osaka)"},
-		{ID: "ap-southeast-2", Name: "Asia Pacific (Sydney)"},
-		{ID: "ap-southeast-3", Name: "Asia Pacific (Jakarta)"},
-		{ID: "ap-southeast-4", Name: "Asia Pacific (Melbourne)"},
-		{ID: "ap-east-1", Name: "Asia Pacific (Hong Kong)"},
-
-		// Canada
-		{ID: "ca-central-1", Name: "Canada (Central)"},
-		{ID: "ca-west-1", Name: "Canada (Calgary)"},
-
-		// South America
-		{ID: "sa-east-1", Name: "South America (São Paulo)"},
-
-		// Middle East
-		{ID: "me-south-1", Name: "Middle East (Bahrain)"},
-		{ID: "me-central-1", Name: "Middle East (UAE)"},
-
-		// Africa
-		{ID: "af-south-1", Name: "Africa (Cape Town)"},
-
-		// Israel
-		{ID: "il-central-1", Name: "Israel (Tel Aviv)"},
+	bedrockRegions := bedrockEndpointRegions()
+
+	regions := make([]Region, 0, len(curatedRegions)+len(bedrockRegions))
+	seen := make(map[string]bool)
+	for _, r := range curatedRegions {
+		r.Bedrock = bedrockRegions[r.ID]
+		regions = append(regions, r)
+		seen[r.ID] = true
+	}
+
+	var discovered []string
+	for id := range bedrockRegions {
+		if !seen[id] {
+			discovered = append(discovered, id)
+		}
+	}
+	sort.Strings(discovered)
+
+	for _, id := range discovered {
+		regions = append(regions, Region{ID: id, Name: id, Bedrock: true})
 	}
+
+	return regions
+}
+
+// bedrockEndpointRegions returns the regions the SDK's Bedrock endpoints metadata lists
+// The metadata keys Bedrock endpoints as "bedrock-<region>" with a credential scope of
+// <region>; unknown keys fall back to a generic endpoint signed for the key itself
+func bedrockEndpointRegions() map[string]bool {
+	resolver := bedrock.NewDefaultEndpointResolver()
+	regions := make(map[string]bool)
+
+	for _, geo := range regionGeographies {
+		for _, dir := range regionDirections {
+			for n := 1; n <= maxRegionNumber; n++ {
+				id := fmt.Sprintf("%s-%s-%d", geo, dir, n)
+				endpoint, err := resolver.ResolveEndpoint("bedrock-"+id, bedrock.EndpointResolverOptions{})
+				if err == nil && endpoint.SigningRegion == id {
+					regions[id] = true
+				}
+			}
+		}
+	}
+
+	return regions
 }
@@ -0,0 +1,106 @@
+package awsutil
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"gopkg.in/ini.v1"
+)
+
+// SSOProfile describes a profile that authenticates through AWS IAM Identity Center (SSO)
+type SSOProfile struct {
+	Name      string
+	StartURL  string
+	SSORegion string
+	AccountID string
+	RoleName  string
+	Region    string
+}
+
+// WriteSSOProfile adds an SSO profile stanza to ~/.aws/config
+func WriteSSOProfile(p SSOProfile) error {
+	configPath, err := configFilePath()
+	if err != nil {
+		return err
+	}
+
+	return updateINIFile(configPath, 0644, func(cfg *ini.File) error {
+		section, err := cfg.NewSection("profile " + p.Name)
+		if err != nil {
+			return err
+		}
+		section.Key("sso_start_url").SetValue(p.StartURL)
+		section.Key("sso_region").SetValue(p.SSORegion)
+		section.Key("sso_account_id").SetValue(p.AccountID)
+		section.Key("sso_role_name").SetValue(p.RoleName)
+		section.Key("region").SetValue(p.Region)
+		return nil
+	})
+}
+
+// WriteAccessKeyProfile stores static access keys in ~/.aws/credentials and the region in ~/.aws/config
+func WriteAccessKeyProfile(name, accessKeyID, secretAccessKey, region string) error {
+	credentialsPath, err := credentialsFilePath()
+	if err != nil {
+		return err
+	}
+
+	// Credentials file holds secrets, keep it private
+	if err := updateINIFile(credentialsPath, 0600, func(cfg *ini.File) error {
+		section, err := cfg.NewSection(name)
+		if err != nil {
+			return err
+		}
+		section.Key("aws_access_key_id").SetValue(accessKeyID)
+		section.Key("aws_secret_access_key").SetValue(secretAccessKey)
+		return nil
+	}); err != nil {
+		return err
+	}
+
+	configPath, err := configFilePath()
+	if err != nil {
+		return err
+	}
+
+	return updateINIFile(configPath, 0644, func(cfg *ini.File) error {
+		section, err := cfg.NewSection("profile " + name)
+		if err != nil {
+			return err
+		}
+		section.Key("region").SetValue(region)
+		return nil
+	})
+}
+
+// updateINIFile loads an ini file (or starts empty if missing), applies fn, and writes it back
+func updateINIFile(path string, perm os.FileMode, fn func(cfg *ini.File) error) error {
+	cfg := ini.Empty()
+	if _, err := os.Stat(path); err == nil {
+		cfg, err = ini.Load(path)
+		if err != nil {
+			return fmt.Errorf("failed to parse %s: %w", path, err)
+		}
+	}
+
+	if err := fn(cfg); err != nil {
+		return fmt.Errorf("failed to update %s: %w", path, err)
+	}
+
+	if err := os.MkdirAll(filepath.Dir(path), 0700); err != nil {
+		return fmt.Errorf("failed to create directory: %w", err)
+	}
+
+	file, err := os.OpenFile(path, os.O_WRONLY|os.O_CREATE|os.O_TRUNC, perm)
+	if err != nil {
+		return fmt.Errorf("failed to open %s: %w", path, err)
+	}
+	defer file.Close()
+
+	if _, err := cfg.WriteTo(file); err != nil {
+		return fmt.Errorf("failed to write %s: %w", path, err)
+	}
+
+	return nil
+}
@@ -0,0 +1,150 @@
+// Package chart renders simple bar charts to SVG or PNG without external tools
+package chart
+
+import (
+	"fmt"
+	"image/color"
+	"io"
+	"math"
+	"path/filepath"
+	"strings"
+)
+
+// Layout constants, in pixels
+const (
+	width        = 800
+	chartHeight  = 320
+	marginLeft   = 80
+	marginRight  = 20
+	marginTop    = 40
+	marginBottom = 50
+	labelWidth   = 7 // Width of one character in the PNG font; SVG text is sized to match
+)
+
+var (
+	backgroundColor = color.RGBA{0xff, 0xff, 0xff, 0xff}
+	barColor        = color.RGBA{0x3b, 0x82, 0xf6, 0xff}
+	axisColor       = color.RGBA{0x9c, 0xa3, 0xaf, 0xff}
+	textColor       = color.RGBA{0x1f, 0x29, 0x37, 0xff}
+)
+
+// Bar is one labelled value
+type Bar struct {
+	Label string
+	Value float64
+}
+
+// BarChart is a titled series of bars
+type BarChart struct {
+	Title  string
+	Format func(float64) string // Formats axis and bar values; defaults to %.0f
+	Bars   []Bar
+}
+
+// anchor aligns text horizontally around its x coordinate
+type anchor int
+
+const (
+	anchorStart anchor = iota
+	anchorMiddle
+	anchorEnd
+)
+
+// canvas is implemented by the SVG and PNG backends
+type canvas interface {
+	rect(x, y, w, h int, c color.RGBA)
+	line(x1, y1, x2, y2 int, c color.RGBA)
+	text(x, y int, s string, a anchor, c color.RGBA)
+}
+
+// Render writes the charts stacked vertically, as PNG or SVG depending on the file extension
+func Render(w io.Writer, path string, charts []BarChart) error {
+	height := chartHeight * len(charts)
+	switch strings.ToLower(filepath.Ext(path)) {
+	case ".svg":
+		c := newSVGCanvas(width, height)
+		drawCharts(c, charts)
+		return c.writeTo(w)
+	case ".png":
+		c := newPNGCanvas(width, height)
+		drawCharts(c, charts)
+		return c.writeTo(w)
+	default:
+		return fmt.Errorf("unsupported chart format '%s' (use .png or .svg)", filepath.Ext(path))
+	}
+}
+
+// IsChartPath reports whether path has an extension Render supports
+func IsChartPath(path string) bool {
+	ext := strings.ToLower(filepath.Ext(path))
+	return ext == ".png" || ext == ".svg"
+}
+
+func drawCharts(c canvas, charts []BarChart) {
+	c.rect(0, 0, width, chartHeight*len(charts), backgroundColor)
+	for i, chart := range charts {
+		drawBarChart(c, chart, i*chartHeight)
+	}
+}
+
+func drawBarChart(c canvas, chart BarChart, top int) {
+	format := chart.Format
+	if format == nil {
+		format = func(v float64) string { return fmt.Sprintf("%.0f", v) }
+	}
+
+	c.text(width/2, top+marginTop/2+5, chart.Title, anchorMiddle, textColor)
+
+	plotLeft, plotRight := marginLeft, width-marginRight
+	plotTop, plotBottom := top+marginTop, top+chartHeight-marginBottom
+	plotHeight := plotBottom - plotTop
+
+	// Axes
+	c.line(plotLeft, plotTop, plotLeft, plotBottom, axisColor)
+	c.line(plotLeft, plotBottom, plotRight, plotBottom, axisColor)
+
+	if len(chart.Bars) == 0 {
+		c.text((plotLeft+plotRight)/2, (plotTop+plotBottom)/2, "No data", anchorMiddle, axisColor)
+		return
+	}
+
+	maxValue := 0.0
+	for _, b := range chart.Bars {
+		maxValue = math.Max(maxValue, b.Value)
+	}
+	if maxValue == 0 {
+		maxValue = 1
+	}
+
+	// Y axis: zero, half, and maximum
+	for _, fraction := range []float64{0, 0.5, 1} {
+		y := plotBottom - int(fraction*float64(plotHeight))
+		c.line(plotLeft-4, y, plotLeft, y, axisColor)
+		c.text(plotLeft-8, y+4, format(fraction*maxValue), anchorEnd, textColor)
+	}
+
+	slot := float64(plotRight-plotLeft) / float64(len(chart.Bars))
+	barWidth := int(math.Max(1, slot*0.7))
+
+	// Show only as many x labels as fit without overlapping
+	longest := 1
+	for _, b := range chart.Bars {
+		longest = max(longest, len(b.Label))
+	}
+	labelEvery := int(math.Ceil(float64(longest*labelWidth+8) / slot))
+	labelEvery = max(labelEvery, 1)
+
+	showValues := slot >= 40
+	for i, b := range chart.Bars {
+		center := plotLeft + int(slot*(float64(i)+0.5))
+		h := int(b.Value / maxValue * float64(plotHeight))
+		c.rect(center-barWidth/2, plotBottom-h, barWidth, h, barColor)
+
+		if showValues {
+			c.text(center, plotBottom-h-4, format(b.Value), anchorMiddle, textColor)
+		}
+		if i%labelEvery == 0 {
+			c.text(center, plotBottom+16, b.Label, anchorMiddle, textColor)
+		}
+	}
+}
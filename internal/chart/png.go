@@ -0,0 +1,58 @@
+package chart
+
+import (
+	"image"
+	"image/color"
+	"image/draw"
+	"image/png"
+	"io"
+
+	"golang.org/x/image/font"
+	"golang.org/x/image/font/basicfont"
+	"golang.org/x/image/math/fixed"
+)
+
+// pngCanvas draws directly onto an RGBA image using the built-in bitmap font
+type pngCanvas struct {
+	img *image.RGBA
+}
+
+func newPNGCanvas(width, height int) *pngCanvas {
+	return &pngCanvas{img: image.NewRGBA(image.Rect(0, 0, width, height))}
+}
+
+func (p *pngCanvas) rect(x, y, w, h int, c color.RGBA) {
+	draw.Draw(p.img, image.Rect(x, y, x+w, y+h), image.NewUniform(c), image.Point{}, draw.Src)
+}
+
+// line only needs to handle the horizontal and vertical lines charts use
+func (p *pngCanvas) line(x1, y1, x2, y2 int, c color.RGBA) {
+	if x1 > x2 {
+		x1, x2 = x2, x1
+	}
+	if y1 > y2 {
+		y1, y2 = y2, y1
+	}
+	p.rect(x1, y1, x2-x1+1, y2-y1+1, c)
+}
+
+func (p *pngCanvas) text(x, y int, s string, a anchor, c color.RGBA) {
+	d := &font.Drawer{
+		Dst:  p.img,
+		Src:  image.NewUniform(c),
+		Face: basicfont.Face7x13,
+	}
+	w := d.MeasureString(s).Round()
+	switch a {
+	case anchorMiddle:
+		x -= w / 2
+	case anchorEnd:
+		x -= w
+	}
+	d.Dot = fixed.P(x, y)
+	d.DrawString(s)
+}
+
+func (p *pngCanvas) writeTo(w io.Writer) error {
+	return png.Encode(w, p.img)
+}
@@ -0,0 +1,44 @@
+package chart
+
+import (
+	"fmt"
+	"image/color"
+	"io"
+	"strings"
+)
+
+// svgEscaper escapes the characters that are special in SVG text
+var svgEscaper = strings.NewReplacer("&", "&amp;", "<", "&lt;", ">", "&gt;", "\"", "&quot;")
+
+// svgCanvas collects SVG elements in drawing order
+type svgCanvas struct {
+	width, height int
+	b             strings.Builder
+}
+
+func newSVGCanvas(width, height int) *svgCanvas {
+	return &svgCanvas{width: width, height: height}
+}
+
+func hexColor(c color.RGBA) string {
+	return fmt.Sprintf("#%02x%02x%02x", c.R, c.G, c.B)
+}
+
+func (s *svgCanvas) rect(x, y, w, h int, c color.RGBA) {
+	fmt.Fprintf(&s.b, "  <rect x=\"%d\" y=\"%d\" width=\"%d\" height=\"%d\" fill=\"%s\"/>\n", x, y, w, h, hexColor(c))
+}
+
+func (s *svgCanvas) line(x1, y1, x2, y2 int, c color.RGBA) {
+	fmt.Fprintf(&s.b, "  <line x1=\"%d\" y1=\"%d\" x2=\"%d\" y2=\"%d\" stroke=\"%s\"/>\n", x1, y1, x2, y2, hexColor(c))
+}
+
+func (s *svgCanvas) text(x, y int, text string, a anchor, c color.RGBA) {
+	anchors := map[anchor]string{anchorStart: "start", anchorMiddle: "middle", anchorEnd: "end"}
+	fmt.Fprintf(&s.b, "  <text x=\"%d\" y=\"%d\" text-anchor=\"%s\" fill=\"%s\">%s</text>\n", x, y, anchors[a], hexColor(c), svgEscaper.Replace(text))
+}
+
+func (s *svgCanvas) writeTo(w io.Writer) error {
+	_, err := fmt.Fprintf(w, "<svg xmlns=\"http://www.w3.org/2000/svg\" width=\"%d\" height=\"%d\" viewBox=\"0 0 %d %d\" font-family=\"monospace\" font-size=\"12\">\n%s</svg>\n",
+		s.width, s.height, s.width, s.height, s.b.String())
+	return err
+}
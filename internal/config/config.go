@@ -3,31 +3,166 @@ package config
 import (
 	"encoding/json"
 	"fmt"
+	"math"
 	"os"
 	"path/filepath"
+	"regexp"
+	"sort"
 	"strconv"
 	"strings"
+	"time"
 )
 
 type Config struct {
 	Version     string `json:"version"`      // CLI version that last modified this config (e.g., "v0.6.1")
-	ProfileType string `json:"profile-type"` // "bedrock" or "api"
+	ProfileType string `json:"profile-type"` // "bedrock", "api" or "vertex"
 
 	// Bedrock-specific fields (only used when ProfileType == "bedrock")
-	Profile     string `json:"profile,omitempty"`
-	Region      string `json:"region,omitempty"`
-	CrossRegion string `json:"cross-region,omitempty"`
+	Profile           string `json:"profile,omitempty"`
+	CredentialsSource string `json:"credentials-source,omitempty"` // "profile" (default), "environment" or "default-chain"
+	Region            string `json:"region,omitempty"`
+	CrossRegion       string `json:"cross-region,omitempty"`
 
 	// API-specific fields (only used when ProfileType == "api")
 	BaseURL  string `json:"base-url,omitempty"`
 	APIKeyID string `json:"api-key-id,omitempty"` // Reference to encrypted keyring entry
 
-	// Model fields (used by both types)
+	// Vertex AI-specific fields (only used when ProfileType == "vertex")
+	GCPProject   string `json:"gcp-project,omitempty"`
+	VertexRegion string `json:"vertex-region,omitempty"` // e.g., "us-east5" or "global"
+
+	// Model fields (used by all types)
 	Model      string `json:"model"`
 	FastModel  string `json:"fast-model"`
 	HeavyModel string `json:"heavy-model"`
+
+	// PinnedVersions maps a model slot (main, fast, heavy) to a dated snapshot (e.g., "20250929")
+	// When set, model resolution uses that snapshot instead of the newest one (bedrock only)
+	PinnedVersions map[string]string `json:"pinned-versions,omitempty"`
+
+	// Unverified is set when the models were picked from the built-in offline catalog because
+	// Bedrock could not be queried; it is cleared after the first successful validation at launch
+	Unverified bool `json:"unverified,omitempty"`
+
+	// Environment passed to Claude: "inherit" (default) passes the full shell environment,
+	// "minimal" passes only a built-in allowlist plus EnvAllowlist
+	EnvPolicy    string   `json:"env-policy,omitempty"`
+	EnvAllowlist []string `json:"env-allowlist,omitempty"`
+
+	// How the model/provider configuration reaches Claude: "env" (default) injects it into the
+	// process environment, "settings" writes it to the project's .claude/settings.local.json
+	IntegrationMode string `json:"integration-mode,omitempty"`
+
+	// Directories (and their subdirectories) this profile may be launched from; empty allows any.
+	// Keeps e.g. a client's AWS account from being billed for work in another client's repos.
+	// DirPolicy decides what happens elsewhere: "refuse" (default) or "warn"
+	AllowedDirs []string `json:"allowed-dirs,omitempty"`
+	DirPolicy   string   `json:"dir-policy,omitempty"`
+
+	// Spending limits in USD, checked against the usage database before each launch; 0 disables.
+	// A warning is printed when spend crosses one of BudgetWarnAt (percent of the budget, default 80);
+	// BudgetPolicy decides what happens once a budget is used up: "warn" (default) or "refuse"
+	BudgetDailyUSD   float64 `json:"budget-daily-usd,omitempty"`
+	BudgetMonthlyUSD float64 `json:"budget-monthly-usd,omitempty"`
+	BudgetWarnAt     []int   `json:"budget-warn-at,omitempty"`
+	BudgetPolicy     string  `json:"budget-policy,omitempty"`
+
+	// Moves model slots to newer models found by the background model list refresh (bedrock only):
+	// "off" (default), "minor" (newer snapshots of the same model) or "major" (also newer versions
+	// of the same family). AutoUpgradeMode decides how: "prompt" (default) asks at launch, "silent" doesn't
+	AutoUpgrade     string `json:"auto-upgrade,omitempty"`
+	AutoUpgradeMode string `json:"auto-upgrade-mode,omitempty"`
+
+	// DeclinedUpgrades lists profile IDs the user said no to at an upgrade prompt, so they aren't offered again
+	DeclinedUpgrades []string `json:"declined-upgrades,omitempty"`
+
+	// How this profile's usage is billed, for cost estimates: "standard" (default) on-demand prices,
+	// "batch" at the batch inference discount, or "provisioned" where a Provisioned Throughput
+	// commitment covers the tokens and costs ProvisionedHourlyUSD per hour instead
+	PricingTier          string  `json:"pricing-tier,omitempty"`
+	ProvisionedHourlyUSD float64 `json:"provisioned-hourly-usd,omitempty"`
+	// Date the Provisioned Throughput commitment started (YYYY-MM-DD); no hours are counted before it
+	ProvisionedSince string `json:"provisioned-since,omitempty"`
+
+	// Encrypted keeps the whole profile in the encrypted keyring, leaving only a reference to it
+	// in profiles/<name>.json, for organisations that treat base URLs or AWS account names as sensitive
+	Encrypted bool `json:"encrypted,omitempty"`
+
+	// TeamSync uploads anonymized session records after each session to a shared S3 bucket or
+	// DynamoDB table, so team-wide usage can be aggregated: s3://bucket/prefix or dynamodb://table
+	TeamSync string `json:"team-sync,omitempty"`
+
+	// Claude Code tuning passed through as environment variables; empty or 0 leaves Claude Code's default.
+	// FastModelRegion sends fast model requests to another AWS region (bedrock only), MaxOutputTokens
+	// caps each response, and MaxThinkingTokens sets the extended thinking budget
+	FastModelRegion   string `json:"fast-model-region,omitempty"`
+	MaxOutputTokens   int    `json:"max-output-tokens,omitempty"`
+	MaxThinkingTokens int    `json:"max-thinking-tokens,omitempty"`
 }
 
+// MinThinkingTokens is the smallest extended thinking budget Claude accepts
+const MinThinkingTokens = 1024
+
+// awsRegionPattern matches AWS region names such as us-east-1 or us-gov-west-1
+var awsRegionPattern = regexp.MustCompile(`^[a-z]{2}(-[a-z]+)+-\d+$`)
+
+// ModelSlots lists the model slots in display order
+var ModelSlots = []string{"main", "fast", "heavy"}
+
+// Where Bedrock profiles get AWS credentials
+const (
+	CredentialsSourceProfile      = "profile"       // Shared AWS profile named by Profile
+	CredentialsSourceEnvironment  = "environment"   // AWS_ACCESS_KEY_ID etc. or a web identity token
+	CredentialsSourceDefaultChain = "default-chain" // The SDK's default chain, e.g. IRSA or instance roles
+)
+
+// Environment policies for the launched Claude process
+const (
+	EnvPolicyInherit = "inherit"
+	EnvPolicyMinimal = "minimal"
+)
+
+// Integration modes for delivering configuration to Claude
+const (
+	IntegrationModeEnv      = "env"
+	IntegrationModeSettings = "settings"
+)
+
+// Policies for launching outside AllowedDirs
+const (
+	DirPolicyRefuse = "refuse"
+	DirPolicyWarn   = "warn"
+)
+
+// Policies for launching with an exhausted budget
+const (
+	BudgetPolicyWarn   = "warn"
+	BudgetPolicyRefuse = "refuse"
+)
+
+// Automatic model upgrade policies
+const (
+	AutoUpgradeOff   = "off"
+	AutoUpgradeMinor = "minor"
+	AutoUpgradeMajor = "major"
+)
+
+// How automatic model upgrades are applied
+const (
+	AutoUpgradeModePrompt = "prompt"
+	AutoUpgradeModeSilent = "silent"
+)
+
+// Pricing tiers for cost estimates
+const (
+	PricingTierStandard    = "standard"
+	PricingTierBatch       = "batch"
+	PricingTierProvisioned = "provisioned"
+)
+
+// DefaultBudgetWarnAt is the warning threshold used when BudgetWarnAt is empty, in percent
+var DefaultBudgetWarnAt = []int{80}
+
 var validCrossRegions = map[string]bool{
 	"us":     true,
 	"eu":     true,
@@ -263,17 +398,56 @@ func (c *Config) Save() error {
 	return os.WriteFile(path, data, 0644)
 }
 
+// UsesSharedProfile reports whether AWS credentials come from the shared profile named by Profile
+func (c *Config) UsesSharedProfile() bool {
+	return c.CredentialsSource == "" || c.CredentialsSource == CredentialsSourceProfile
+}
+
+// AWSProfile returns the shared AWS profile to load credentials from, or "" when
+// credentials come from the environment or the SDK's default chain
+func (c *Config) AWSProfile() string {
+	if !c.UsesSharedProfile() {
+		return ""
+	}
+	return c.Profile
+}
+
+// CredentialsLabel describes where AWS credentials come from, e.g. "AWS profile 'dev'"
+func (c *Config) CredentialsLabel() string {
+	switch c.CredentialsSource {
+	case CredentialsSourceEnvironment:
+		return "AWS credentials from the environment"
+	case CredentialsSourceDefaultChain:
+		return "the AWS default credential chain"
+	default:
+		return fmt.Sprintf("AWS profile '%s'", c.Profile)
+	}
+}
+
+func validProfileType(profileType string) bool {
+	return profileType == "bedrock" || profileType == "api" || profileType == "vertex"
+}
+
+func validCredentialsSource(source string) bool {
+	return source == "" || source == CredentialsSourceProfile ||
+		source == CredentialsSourceEnvironment || source == CredentialsSourceDefaultChain
+}
+
 // IsIncomplete checks if config is missing required fields
 func (c *Config) IsIncomplete() bool {
 	// Check profile type specific fields
 	if c.ProfileType == "bedrock" {
-		if c.Profile == "" || c.Region == "" || c.CrossRegion == "" {
+		if (c.UsesSharedProfile() && c.Profile == "") || c.Region == "" || c.CrossRegion == "" {
 			return true
 		}
 	} else if c.ProfileType == "api" {
 		if c.BaseURL == "" || c.APIKeyID == "" {
 			return true
 		}
+	} else if c.ProfileType == "vertex" {
+		if c.GCPProject == "" || c.VertexRegion == "" {
+			return true
+		}
 	}
 
 	// Check model fields
@@ -286,14 +460,17 @@ func (c *Config) IsIncomplete() bool {
 
 func (c *Config) Validate() error {
 	// Validate ProfileType
-	if c.ProfileType != "bedrock" && c.ProfileType != "api" {
-		return fmt.Errorf("profile-type must be either 'bedrock' or 'api'")
+	if !validProfileType(c.ProfileType) {
+		return fmt.Errorf("profile-type must be one of: bedrock, api, vertex")
 	}
 
 	// Validate based on profile type
 	if c.ProfileType == "bedrock" {
-		if c.Profile == "" {
-			return fmt.Errorf("profile is required for bedrock profile type")
+		if !validCredentialsSource(c.CredentialsSource) {
+			return fmt.Errorf("credentials-source must be one of: profile, environment, default-chain")
+		}
+		if c.UsesSharedProfile() && c.Profile == "" {
+			return fmt.Errorf("profile is required for bedrock profile type (or set credentials-source to environment or default-chain)")
 		}
 		if c.Region == "" {
 			return fmt.Errorf("region is required for bedrock profile type")
@@ -311,9 +488,16 @@ func (c *Config) Validate() error {
 		if c.APIKeyID == "" {
 			return fmt.Errorf("api-key-id is required for api profile type")
 		}
+	} else if c.ProfileType == "vertex" {
+		if c.GCPProject == "" {
+			return fmt.Errorf("gcp-project is required for vertex profile type")
+		}
+		if c.VertexRegion == "" {
+			return fmt.Errorf("vertex-region is required for vertex profile type")
+		}
 	}
 
-	// Models are required for both profile types
+	// Models are required for all profile types
 	if c.Model == "" {
 		return fmt.Errorf("model is required")
 	}
@@ -324,18 +508,88 @@ func (c *Config) Validate() error {
 		return fmt.Errorf("heavy-model is required")
 	}
 
+	if c.EnvPolicy != "" && c.EnvPolicy != EnvPolicyInherit && c.EnvPolicy != EnvPolicyMinimal {
+		return fmt.Errorf("env-policy must be either 'inherit' or 'minimal'")
+	}
+
+	if c.IntegrationMode != "" && c.IntegrationMode != IntegrationModeEnv && c.IntegrationMode != IntegrationModeSettings {
+		return fmt.Errorf("integration-mode must be either 'env' or 'settings'")
+	}
+
+	if c.DirPolicy != "" && c.DirPolicy != DirPolicyRefuse && c.DirPolicy != DirPolicyWarn {
+		return fmt.Errorf("dir-policy must be either 'refuse' or 'warn'")
+	}
+
+	if c.BudgetDailyUSD < 0 || c.BudgetMonthlyUSD < 0 {
+		return fmt.Errorf("budgets cannot be negative")
+	}
+
+	if c.BudgetPolicy != "" && c.BudgetPolicy != BudgetPolicyWarn && c.BudgetPolicy != BudgetPolicyRefuse {
+		return fmt.Errorf("budget-policy must be either 'warn' or 'refuse'")
+	}
+
+	if c.AutoUpgrade != "" && c.AutoUpgrade != AutoUpgradeOff && c.AutoUpgrade != AutoUpgradeMinor && c.AutoUpgrade != AutoUpgradeMajor {
+		return fmt.Errorf("auto-upgrade must be one of: off, minor, major")
+	}
+
+	if c.AutoUpgradeMode != "" && c.AutoUpgradeMode != AutoUpgradeModePrompt && c.AutoUpgradeMode != AutoUpgradeModeSilent {
+		return fmt.Errorf("auto-upgrade-mode must be either 'prompt' or 'silent'")
+	}
+
+	if c.PricingTier != "" && c.PricingTier != PricingTierStandard && c.PricingTier != PricingTierBatch && c.PricingTier != PricingTierProvisioned {
+		return fmt.Errorf("pricing-tier must be one of: standard, batch, provisioned")
+	}
+
+	if c.ProvisionedHourlyUSD < 0 {
+		return fmt.Errorf("provisioned-hourly-usd cannot be negative")
+	}
+
+	if c.ProvisionedSince != "" {
+		if _, err := parseDate(c.ProvisionedSince); err != nil {
+			return fmt.Errorf("provisioned-since: %w", err)
+		}
+	}
+
+	if c.TeamSync != "" {
+		if _, err := ParseTeamSyncTarget(c.TeamSync); err != nil {
+			return err
+		}
+	}
+
+	if c.FastModelRegion != "" {
+		if c.ProfileType != "bedrock" {
+			return fmt.Errorf("fast-model-region only applies to bedrock profiles")
+		}
+		if !awsRegionPattern.MatchString(c.FastModelRegion) {
+			return fmt.Errorf("invalid fast-model-region: %s (expected an AWS region such as us-west-2)", c.FastModelRegion)
+		}
+	}
+
+	if c.MaxOutputTokens < 0 {
+		return fmt.Errorf("max-output-tokens cannot be negative")
+	}
+
+	if c.MaxThinkingTokens != 0 && c.MaxThinkingTokens < MinThinkingTokens {
+		return fmt.Errorf("max-thinking-tokens must be at least %d (or 0 for Claude Code's default)", MinThinkingTokens)
+	}
+
 	return nil
 }
 
 func (c *Config) Set(key, value string) error {
 	switch key {
 	case "profile-type":
-		if value != "bedrock" && value != "api" {
-			return fmt.Errorf("profile-type must be either 'bedrock' or 'api'")
+		if !validProfileType(value) {
+			return fmt.Errorf("profile-type must be one of: bedrock, api, vertex")
 		}
 		c.ProfileType = value
 	case "profile":
 		c.Profile = value
+	case "credentials-source":
+		if !validCredentialsSource(value) {
+			return fmt.Errorf("invalid credentials-source: %s (must be one of: profile, environment, default-chain)", value)
+		}
+		c.CredentialsSource = value
 	case "region":
 		c.Region = value
 	case "cross-region":
@@ -343,16 +597,140 @@ func (c *Config) Set(key, value string) error {
 			return fmt.Errorf("invalid cross-region: %s (must be one of: us, eu, global)", value)
 		}
 		c.CrossRegion = value
+	case "gcp-project":
+		c.GCPProject = value
+	case "vertex-region":
+		c.VertexRegion = value
 	case "base-url":
 		c.BaseURL = value
 	case "api-key-id":
 		c.APIKeyID = value
-	case "model":
-		c.Model = value
-	case "fast-model":
-		c.FastModel = value
-	case "heavy-model":
-		c.HeavyModel = value
+	case "model", "fast-model", "heavy-model":
+		slot, _ := SlotForKey(key)
+		return c.SetModelForSlot(slot, value)
+	case "env-policy":
+		if value != EnvPolicyInherit && value != EnvPolicyMinimal {
+			return fmt.Errorf("env-policy must be either 'inherit' or 'minimal'")
+		}
+		c.EnvPolicy = value
+	case "env-allowlist":
+		c.EnvAllowlist = nil
+		for _, name := range strings.Split(value, ",") {
+			if name = strings.TrimSpace(name); name != "" {
+				c.EnvAllowlist = append(c.EnvAllowlist, name)
+			}
+		}
+	case "integration-mode":
+		if value != IntegrationModeEnv && value != IntegrationModeSettings {
+			return fmt.Errorf("integration-mode must be either 'env' or 'settings'")
+		}
+		c.IntegrationMode = value
+	case "allowed-dirs":
+		c.AllowedDirs = nil
+		for _, dir := range strings.Split(value, ",") {
+			if dir = strings.TrimSpace(dir); dir == "" {
+				continue
+			}
+			abs, err := absDir(dir)
+			if err != nil {
+				return err
+			}
+			c.AllowedDirs = append(c.AllowedDirs, abs)
+		}
+	case "dir-policy":
+		if value != DirPolicyRefuse && value != DirPolicyWarn {
+			return fmt.Errorf("dir-policy must be either 'refuse' or 'warn'")
+		}
+		c.DirPolicy = value
+	case "budget-daily-usd", "budget-monthly-usd":
+		amount, err := parseUSD(value)
+		if err != nil {
+			return fmt.Errorf("%s: %w", key, err)
+		}
+		if key == "budget-daily-usd" {
+			c.BudgetDailyUSD = amount
+		} else {
+			c.BudgetMonthlyUSD = amount
+		}
+	case "budget-warn-at":
+		c.BudgetWarnAt = nil
+		for _, part := range strings.Split(value, ",") {
+			if part = strings.TrimSuffix(strings.TrimSpace(part), "%"); part == "" {
+				continue
+			}
+			percent, err := strconv.Atoi(part)
+			if err != nil || percent <= 0 || percent > 100 {
+				return fmt.Errorf("budget-warn-at must be comma-separated percentages between 1 and 100")
+			}
+			c.BudgetWarnAt = append(c.BudgetWarnAt, percent)
+		}
+		sort.Ints(c.BudgetWarnAt)
+	case "budget-policy":
+		if value != BudgetPolicyWarn && value != BudgetPolicyRefuse {
+			return fmt.Errorf("budget-policy must be either 'warn' or 'refuse'")
+		}
+		c.BudgetPolicy = value
+	case "auto-upgrade":
+		if value != AutoUpgradeOff && value != AutoUpgradeMinor && value != AutoUpgradeMajor {
+			return fmt.Errorf("auto-upgrade must be one of: off, minor, major")
+		}
+		c.AutoUpgrade = value
+	case "auto-upgrade-mode":
+		if value != AutoUpgradeModePrompt && value != AutoUpgradeModeSilent {
+			return fmt.Errorf("auto-upgrade-mode must be either 'prompt' or 'silent'")
+		}
+		c.AutoUpgradeMode = value
+	case "pricing-tier":
+		if value != PricingTierStandard && value != PricingTierBatch && value != PricingTierProvisioned {
+			return fmt.Errorf("pricing-tier must be one of: standard, batch, provisioned")
+		}
+		c.PricingTier = value
+	case "provisioned-hourly-usd":
+		amount, err := parseUSD(value)
+		if err != nil {
+			return fmt.Errorf("%s: %w", key, err)
+		}
+		c.ProvisionedHourlyUSD = amount
+	case "provisioned-since":
+		if value != "" {
+			if _, err := parseDate(value); err != nil {
+				return fmt.Errorf("%s: %w", key, err)
+			}
+		}
+		c.ProvisionedSince = value
+	case "encrypted":
+		encrypted, err := strconv.ParseBool(value)
+		if err != nil {
+			return fmt.Errorf("encrypted must be either 'true' or 'false'")
+		}
+		c.Encrypted = encrypted
+	case "team-sync":
+		if value == "" || value == "off" {
+			c.TeamSync = ""
+			break
+		}
+		if _, err := ParseTeamSyncTarget(value); err != nil {
+			return err
+		}
+		c.TeamSync = value
+	case "fast-model-region":
+		if value != "" && !awsRegionPattern.MatchString(value) {
+			return fmt.Errorf("invalid fast-model-region: %s (expected an AWS region such as us-west-2)", value)
+		}
+		c.FastModelRegion = value
+	case "max-output-tokens", "max-thinking-tokens":
+		tokens, err := strconv.Atoi(value)
+		if err != nil || tokens < 0 {
+			return fmt.Errorf("%s must be a whole number of tokens (0 for Claude Code's default)", key)
+		}
+		if key == "max-output-tokens" {
+			c.MaxOutputTokens = tokens
+		} else {
+			if tokens != 0 && tokens < MinThinkingTokens {
+				return fmt.Errorf("max-thinking-tokens must be at least %d (or 0 for Claude Code's default)", MinThinkingTokens)
+			}
+			c.MaxThinkingTokens = tokens
+		}
 	default:
 		return fmt.Errorf("unknown config key: %s", key)
 	}
@@ -365,10 +743,19 @@ func (c *Config) Get(key string) (string, error) {
 		return c.ProfileType, nil
 	case "profile":
 		return c.Profile, nil
+	case "credentials-source":
+		if c.CredentialsSource == "" {
+			return CredentialsSourceProfile, nil
+		}
+		return c.CredentialsSource, nil
 	case "region":
 		return c.Region, nil
 	case "cross-region":
 		return c.CrossRegion, nil
+	case "gcp-project":
+		return c.GCPProject, nil
+	case "vertex-region":
+		return c.VertexRegion, nil
 	case "base-url":
 		return c.BaseURL, nil
 	case "api-key-id":
@@ -379,7 +766,252 @@ func (c *Config) Get(key string) (string, error) {
 		return c.FastModel, nil
 	case "heavy-model":
 		return c.HeavyModel, nil
+	case "env-policy":
+		if c.EnvPolicy == "" {
+			return EnvPolicyInherit, nil
+		}
+		return c.EnvPolicy, nil
+	case "env-allowlist":
+		return strings.Join(c.EnvAllowlist, ","), nil
+	case "integration-mode":
+		if c.IntegrationMode == "" {
+			return IntegrationModeEnv, nil
+		}
+		return c.IntegrationMode, nil
+	case "allowed-dirs":
+		return strings.Join(c.AllowedDirs, ","), nil
+	case "dir-policy":
+		if c.DirPolicy == "" {
+			return DirPolicyRefuse, nil
+		}
+		return c.DirPolicy, nil
+	case "budget-daily-usd":
+		return formatUSD(c.BudgetDailyUSD), nil
+	case "budget-monthly-usd":
+		return formatUSD(c.BudgetMonthlyUSD), nil
+	case "budget-warn-at":
+		thresholds := c.BudgetWarnAt
+		if len(thresholds) == 0 {
+			thresholds = DefaultBudgetWarnAt
+		}
+		parts := make([]string, len(thresholds))
+		for i, t := range thresholds {
+			parts[i] = strconv.Itoa(t)
+		}
+		return strings.Join(parts, ","), nil
+	case "budget-policy":
+		if c.BudgetPolicy == "" {
+			return BudgetPolicyWarn, nil
+		}
+		return c.BudgetPolicy, nil
+	case "auto-upgrade":
+		if c.AutoUpgrade == "" {
+			return AutoUpgradeOff, nil
+		}
+		return c.AutoUpgrade, nil
+	case "auto-upgrade-mode":
+		if c.AutoUpgradeMode == "" {
+			return AutoUpgradeModePrompt, nil
+		}
+		return c.AutoUpgradeMode, nil
+	case "pricing-tier":
+		if c.PricingTier == "" {
+			return PricingTierStandard, nil
+		}
+		return c.PricingTier, nil
+	case "provisioned-hourly-usd":
+		return formatUSD(c.ProvisionedHourlyUSD), nil
+	case "provisioned-since":
+		return c.ProvisionedSince, nil
+	case "encrypted":
+		return strconv.FormatBool(c.Encrypted), nil
+	case "team-sync":
+		return c.TeamSync, nil
+	case "fast-model-region":
+		return c.FastModelRegion, nil
+	case "max-output-tokens":
+		return strconv.Itoa(c.MaxOutputTokens), nil
+	case "max-thinking-tokens":
+		return strconv.Itoa(c.MaxThinkingTokens), nil
 	default:
 		return "", fmt.Errorf("unknown config key: %s", key)
 	}
 }
+
+// ModelForSlot returns the model configured for a slot (main, fast, heavy)
+func (c *Config) ModelForSlot(slot string) (string, error) {
+	switch slot {
+	case "main":
+		return c.Model, nil
+	case "fast":
+		return c.FastModel, nil
+	case "heavy":
+		return c.HeavyModel, nil
+	default:
+		return "", fmt.Errorf("unknown model slot: %s (must be one of: main, fast, heavy)", slot)
+	}
+}
+
+// SetModelForSlot sets the model for a slot (main, fast, heavy)
+// A pin the new model no longer matches is removed, so it can't go stale
+func (c *Config) SetModelForSlot(slot, model string) error {
+	switch slot {
+	case "main":
+		c.Model = model
+	case "fast":
+		c.FastModel = model
+	case "heavy":
+		c.HeavyModel = model
+	default:
+		return fmt.Errorf("unknown model slot: %s (must be one of: main, fast, heavy)", slot)
+	}
+	if c.PinnedVersions[slot] != "" && c.PinnedVersion(slot) == "" {
+		c.UnpinVersion(slot)
+	}
+	return nil
+}
+
+// PinVersion pins a model slot to a dated snapshot
+func (c *Config) PinVersion(slot, version string) {
+	if c.PinnedVersions == nil {
+		c.PinnedVersions = make(map[string]string)
+	}
+	c.PinnedVersions[slot] = version
+}
+
+// PinnedVersion returns the snapshot a slot is pinned to, or "" when it isn't pinned
+// A pin only holds while the slot's model is that snapshot, or a name without a snapshot
+// the pin resolves; a pin the slot's model has moved past, to another snapshot, is ignored
+func (c *Config) PinnedVersion(slot string) string {
+	version := c.PinnedVersions[slot]
+	if version == "" {
+		return ""
+	}
+	model, err := c.ModelForSlot(slot)
+	if err != nil {
+		return ""
+	}
+	if current := SnapshotDate(model); current != "" && current != version {
+		return ""
+	}
+	return version
+}
+
+// DropStalePins removes the pins PinnedVersion ignores and returns their slots
+func (c *Config) DropStalePins() []string {
+	var dropped []string
+	for _, slot := range ModelSlots {
+		if c.PinnedVersions[slot] != "" && c.PinnedVersion(slot) == "" {
+			c.UnpinVersion(slot)
+			dropped = append(dropped, slot)
+		}
+	}
+	return dropped
+}
+
+// SnapshotDate returns the dated snapshot in a model ID, such as "20250929" in
+// "global.anthropic.claude-sonnet-4-5-20250929-v1:0", or "" when it has none
+func SnapshotDate(model string) string {
+	for _, part := range strings.Split(model, "-") {
+		if len(part) == 8 && strings.Trim(part, "0123456789") == "" {
+			return part
+		}
+	}
+	return ""
+}
+
+// UnpinVersion removes a snapshot pin from a model slot
+func (c *Config) UnpinVersion(slot string) {
+	delete(c.PinnedVersions, slot)
+	if len(c.PinnedVersions) == 0 {
+		c.PinnedVersions = nil
+	}
+}
+
+// SlotForKey maps a model config key (model, fast-model, heavy-model) to its slot name
+func SlotForKey(key string) (string, bool) {
+	switch key {
+	case "model":
+		return "main", true
+	case "fast-model":
+		return "fast", true
+	case "heavy-model":
+		return "heavy", true
+	default:
+		return "", false
+	}
+}
+
+// parseUSD parses a budget amount such as "50", "12.50" or "$50"; 0 disables the budget
+func parseUSD(value string) (float64, error) {
+	amount, err := strconv.ParseFloat(strings.TrimPrefix(strings.TrimSpace(value), "$"), 64)
+	if err != nil || amount < 0 || math.IsNaN(amount) || math.IsInf(amount, 0) {
+		return 0, fmt.Errorf("invalid amount '%s' (use a number of US dollars, 0 to disable)", value)
+	}
+	return amount, nil
+}
+
+// formatUSD formats a budget amount for display
+func formatUSD(amount float64) string {
+	return strconv.FormatFloat(amount, 'f', -1, 64)
+}
+
+// parseDate parses a calendar date such as "2025-10-01" as local midnight
+func parseDate(value string) (time.Time, error) {
+	date, err := time.ParseInLocation("2006-01-02", value, time.Local)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("invalid date '%s' (use YYYY-MM-DD)", value)
+	}
+	return date, nil
+}
+
+// ProvisionedStart returns when the Provisioned Throughput commitment started, or the zero
+// time when provisioned-since isn't set
+func (c *Config) ProvisionedStart() time.Time {
+	if c.ProvisionedSince == "" {
+		return time.Time{}
+	}
+	start, _ := parseDate(c.ProvisionedSince)
+	return start
+}
+
+// absDir expands a leading ~ and makes dir absolute
+func absDir(dir string) (string, error) {
+	if dir == "~" || strings.HasPrefix(dir, "~/") {
+		home, err := os.UserHomeDir()
+		if err != nil {
+			return "", fmt.Errorf("failed to get home directory: %w", err)
+		}
+		dir = filepath.Join(home, strings.TrimPrefix(dir, "~"))
+	}
+	abs, err := filepath.Abs(dir)
+	if err != nil {
+		return "", fmt.Errorf("invalid directory %s: %w", dir, err)
+	}
+	return abs, nil
+}
+
+// DirAllowed reports whether the profile may be launched from dir
+// Symlinks are resolved on both sides so a linked checkout matches its real path
+func (c *Config) DirAllowed(dir string) bool {
+	if len(c.AllowedDirs) == 0 {
+		return true
+	}
+
+	dir = resolveDir(dir)
+	for _, allowed := range c.AllowedDirs {
+		rel, err := filepath.Rel(resolveDir(allowed), dir)
+		if err == nil && rel != ".." && !strings.HasPrefix(rel, ".."+string(filepath.Separator)) {
+			return true
+		}
+	}
+	return false
+}
+
+// resolveDir cleans dir and resolves symlinks when it exists
+func resolveDir(dir string) string {
+	if resolved, err := filepath.EvalSymlinks(dir); err == nil {
+		return resolved
+	}
+	return filepath.Clean(dir)
+}
@@ -0,0 +1,232 @@
+package config
+
+import (
+	"bytes"
+	_ "embed"
+	"encoding/json"
+	"fmt"
+	"math"
+	"regexp"
+	"sort"
+	"strings"
+)
+
+// ProfileSchema is the JSON Schema (draft 2020-12) profiles are validated against
+//
+//go:embed profile.schema.json
+var ProfileSchema []byte
+
+// schema is the subset of JSON Schema profile.schema.json uses
+type schema struct {
+	Type                 string             `json:"type"`
+	Enum                 []any              `json:"enum"`
+	Const                any                `json:"const"`
+	Properties           map[string]*schema `json:"properties"`
+	AdditionalProperties *bool              `json:"additionalProperties"`
+	Required             []string           `json:"required"`
+	Items                *schema            `json:"items"`
+	MinLength            *int               `json:"minLength"`
+	Pattern              string             `json:"pattern"`
+	Minimum              *float64           `json:"minimum"`
+	Maximum              *float64           `json:"maximum"`
+	AllOf                []*schema          `json:"allOf"`
+	If                   *schema            `json:"if"`
+	Then                 *schema            `json:"then"`
+}
+
+// SchemaError is one way a profile breaks the schema
+type SchemaError struct {
+	Path    string // JSON pointer to the offending value, e.g. "/budget-warn-at/0"; "" for the whole profile
+	Message string
+}
+
+func (e SchemaError) Error() string {
+	if e.Path == "" {
+		return e.Message
+	}
+	return e.Path + ": " + e.Message
+}
+
+// ValidateSchema checks a profile's raw JSON against ProfileSchema
+// Returns every violation found, ordered by path; none means the profile is valid
+func ValidateSchema(data []byte) ([]SchemaError, error) {
+	var root schema
+	if err := json.Unmarshal(ProfileSchema, &root); err != nil {
+		return nil, fmt.Errorf("invalid built-in profile schema: %w", err)
+	}
+
+	// Numbers are kept as json.Number so integers can be told apart
+	dec := json.NewDecoder(bytes.NewReader(data))
+	dec.UseNumber()
+	var value any
+	if err := dec.Decode(&value); err != nil {
+		return []SchemaError{{Message: fmt.Sprintf("not valid JSON: %v", err)}}, nil
+	}
+
+	var errs []SchemaError
+	root.validate(value, "", &errs)
+	sort.SliceStable(errs, func(i, j int) bool { return errs[i].Path < errs[j].Path })
+	return errs, nil
+}
+
+func (s *schema) validate(value any, path string, errs *[]SchemaError) {
+	fail := func(format string, args ...any) {
+		*errs = append(*errs, SchemaError{Path: path, Message: fmt.Sprintf(format, args...)})
+	}
+
+	if s.Type != "" && !hasType(value, s.Type) {
+		fail("must be %s %s, not %s", article(s.Type), s.Type, typeName(value))
+		return
+	}
+	if s.Const != nil && !sameValue(value, s.Const) {
+		fail("must be %s", formatValue(s.Const))
+	}
+	if len(s.Enum) > 0 && !inEnum(value, s.Enum) {
+		options := make([]string, len(s.Enum))
+		for i, option := range s.Enum {
+			options[i] = formatValue(option)
+		}
+		fail("must be one of %s, not %s", strings.Join(options, ", "), formatValue(value))
+	}
+
+	switch v := value.(type) {
+	case string:
+		if s.MinLength != nil && len([]rune(v)) < *s.MinLength {
+			fail("must not be empty")
+		}
+		if s.Pattern != "" {
+			if re, err := regexp.Compile(s.Pattern); err == nil && !re.MatchString(v) {
+				fail("%q does not match %s", v, s.Pattern)
+			}
+		}
+	case json.Number:
+		n, _ := v.Float64()
+		if s.Minimum != nil && n < *s.Minimum {
+			fail("must be at least %s", formatNumber(*s.Minimum))
+		}
+		if s.Maximum != nil && n > *s.Maximum {
+			fail("must be at most %s", formatNumber(*s.Maximum))
+		}
+	case []any:
+		if s.Items != nil {
+			for i, item := range v {
+				s.Items.validate(item, fmt.Sprintf("%s/%d", path, i), errs)
+			}
+		}
+	case map[string]any:
+		for _, name := range s.Required {
+			if _, ok := v[name]; !ok {
+				fail("missing required key %q", name)
+			}
+		}
+		keys := make([]string, 0, len(v))
+		for key := range v {
+			keys = append(keys, key)
+		}
+		sort.Strings(keys)
+		for _, key := range keys {
+			child := path + "/" + key
+			if prop, ok := s.Properties[key]; ok {
+				prop.validate(v[key], child, errs)
+			} else if s.AdditionalProperties != nil && !*s.AdditionalProperties {
+				*errs = append(*errs, SchemaError{Path: child, Message: "unknown key" + suggestKey(key, s.Properties)})
+			}
+		}
+	}
+
+	for _, sub := range s.AllOf {
+		sub.validate(value, path, errs)
+	}
+	if s.If != nil && s.Then != nil {
+		var ifErrs []SchemaError
+		s.If.validate(value, path, &ifErrs)
+		if len(ifErrs) == 0 {
+			s.Then.validate(value, path, errs)
+		}
+	}
+}
+
+func hasType(value any, kind string) bool {
+	switch kind {
+	case "integer":
+		n, ok := value.(json.Number)
+		if !ok {
+			return false
+		}
+		f, err := n.Float64()
+		return err == nil && f == math.Trunc(f)
+	default:
+		return typeName(value) == kind || (kind == "number" && typeName(value) == "integer")
+	}
+}
+
+func typeName(value any) string {
+	switch v := value.(type) {
+	case nil:
+		return "null"
+	case bool:
+		return "boolean"
+	case string:
+		return "string"
+	case json.Number:
+		if f, err := v.Float64(); err == nil && f == math.Trunc(f) {
+			return "integer"
+		}
+		return "number"
+	case []any:
+		return "array"
+	case map[string]any:
+		return "object"
+	default:
+		return fmt.Sprintf("%T", value)
+	}
+}
+
+func article(kind string) string {
+	if strings.ContainsRune("aeiou", rune(kind[0])) {
+		return "an"
+	}
+	return "a"
+}
+
+// sameValue compares a decoded profile value with a schema value, which was decoded without UseNumber
+func sameValue(value, want any) bool {
+	if n, ok := value.(json.Number); ok {
+		f, err := n.Float64()
+		w, isNumber := want.(float64)
+		return err == nil && isNumber && f == w
+	}
+	return value == want
+}
+
+func inEnum(value any, options []any) bool {
+	for _, option := range options {
+		if sameValue(value, option) {
+			return true
+		}
+	}
+	return false
+}
+
+func formatValue(value any) string {
+	data, err := json.Marshal(value)
+	if err != nil {
+		return fmt.Sprint(value)
+	}
+	return string(data)
+}
+
+func formatNumber(n float64) string {
+	return fmt.Sprintf("%g", n)
+}
+
+// suggestKey points at the known key an unknown one was probably meant to be
+func suggestKey(key string, known map[string]*schema) string {
+	normalized := strings.NewReplacer("_", "-", " ", "-").Replace(strings.ToLower(key))
+	for name := range known {
+		if name == normalized {
+			return fmt.Sprintf(" (did you mean %q?)", name)
+		}
+	}
+	return ""
+}
@@ -0,0 +1,62 @@
+package config
+
+import (
+	"fmt"
+	"net/url"
+	"strings"
+)
+
+// Kinds of team sync targets
+const (
+	TeamSyncS3       = "s3"
+	TeamSyncDynamoDB = "dynamodb"
+)
+
+// TeamSyncTarget is where a profile's anonymized session records are uploaded for team-wide reports
+type TeamSyncTarget struct {
+	Kind   string // TeamSyncS3 or TeamSyncDynamoDB
+	Name   string // Bucket or table name
+	Prefix string // S3 key prefix, without surrounding slashes
+	Region string // From ?region=, else the profile's region
+}
+
+// ParseTeamSyncTarget parses a team-sync value: s3://bucket/prefix or dynamodb://table,
+// either optionally followed by ?region=<region>
+func ParseTeamSyncTarget(value string) (TeamSyncTarget, error) {
+	u, err := url.Parse(value)
+	if err != nil || u.Host == "" {
+		return TeamSyncTarget{}, fmt.Errorf("team-sync must be s3://bucket/prefix or dynamodb://table")
+	}
+
+	target := TeamSyncTarget{Kind: u.Scheme, Name: u.Host, Region: u.Query().Get("region")}
+	switch u.Scheme {
+	case TeamSyncS3:
+		target.Prefix = strings.Trim(u.Path, "/")
+	case TeamSyncDynamoDB:
+		if strings.Trim(u.Path, "/") != "" {
+			return TeamSyncTarget{}, fmt.Errorf("team-sync: a DynamoDB target is just the table name (dynamodb://table)")
+		}
+	default:
+		return TeamSyncTarget{}, fmt.Errorf("team-sync must be s3://bucket/prefix or dynamodb://table")
+	}
+	return target, nil
+}
+
+// TeamSyncTarget returns where the profile's usage is synced, and false when team sync is off
+// A target without a region uses the profile's own region
+func (c *Config) TeamSyncTarget() (TeamSyncTarget, bool, error) {
+	if c.TeamSync == "" {
+		return TeamSyncTarget{}, false, nil
+	}
+	target, err := ParseTeamSyncTarget(c.TeamSync)
+	if err != nil {
+		return TeamSyncTarget{}, false, err
+	}
+	if target.Region == "" {
+		target.Region = c.Region
+	}
+	if target.Region == "" {
+		return TeamSyncTarget{}, false, fmt.Errorf("team-sync needs a region for %s profiles: append ?region=<region>", c.ProfileType)
+	}
+	return target, true, nil
+}
@@ -0,0 +1,190 @@
+package currency
+
+import (
+	"encoding/json"
+	"fmt"
+	"math"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+)
+
+// USD is the currency prices and budgets are kept in
+const USD = "USD"
+
+// Supported are the currencies costs can be displayed in
+var Supported = []string{USD, "EUR", "GBP", "NOK", "JPY"}
+
+// formats renders an amount in each supported currency; %s is the number
+var formats = map[string]string{
+	USD:   "$%s",
+	"EUR": "€%s",
+	"GBP": "£%s",
+	"NOK": "%s kr",
+	"JPY": "¥%s",
+}
+
+// minorDigits is the number of decimals each currency is normally shown with
+var minorDigits = map[string]int{
+	"JPY": 0,
+}
+
+// Settings holds the saved display currency
+type Settings struct {
+	Currency string             `json:"currency"`        // Empty means USD
+	Rates    map[string]float64 `json:"rates,omitempty"` // User-provided units per USD, used instead of fetched rates
+}
+
+// Normalize upper-cases a currency code and checks it's supported
+func Normalize(code string) (string, error) {
+	code = strings.ToUpper(strings.TrimSpace(code))
+	for _, c := range Supported {
+		if c == code {
+			return code, nil
+		}
+	}
+	return "", fmt.Errorf("unsupported currency '%s' (supported: %s)", code, strings.Join(Supported, ", "))
+}
+
+func settingsPath() (string, error) {
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return "", fmt.Errorf("failed to get home directory: %w", err)
+	}
+	return filepath.Join(home, ".clauderock", "currency.json"), nil
+}
+
+// LoadSettings returns the saved settings; costs are shown in USD until a currency is set
+func LoadSettings() (Settings, error) {
+	path, err := settingsPath()
+	if err != nil {
+		return Settings{}, err
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return Settings{}, nil
+		}
+		return Settings{}, fmt.Errorf("failed to read currency settings: %w", err)
+	}
+
+	var s Settings
+	if err := json.Unmarshal(data, &s); err != nil {
+		return Settings{}, fmt.Errorf("failed to parse currency settings: %w", err)
+	}
+	return s, nil
+}
+
+// SaveSettings saves the currency settings
+func SaveSettings(s Settings) error {
+	path, err := settingsPath()
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return fmt.Errorf("failed to create directory: %w", err)
+	}
+	data, err := json.MarshalIndent(s, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to marshal currency settings: %w", err)
+	}
+	if err := os.WriteFile(path, data, 0644); err != nil {
+		return fmt.Errorf("failed to write currency settings: %w", err)
+	}
+
+	resetActive()
+	return nil
+}
+
+// Current returns the display currency code
+func (s Settings) Current() string {
+	if s.Currency == "" {
+		return USD
+	}
+	return s.Currency
+}
+
+// Rate returns the units of code per USD, preferring a user-provided rate over the cached one
+func Rate(s Settings, code string) (rate float64, userProvided bool, ok bool) {
+	if code == USD {
+		return 1, false, true
+	}
+	if rate, ok := s.Rates[code]; ok && rate > 0 {
+		return rate, true, true
+	}
+	// An unreadable rate cache is treated like a missing one
+	if cache, err := LoadRates(); err == nil && cache != nil {
+		if rate, ok := cache.Rates[code]; ok && rate > 0 {
+			return rate, false, true
+		}
+	}
+	return 0, false, false
+}
+
+var (
+	activeMu   sync.Mutex
+	activeCode string
+	activeRate float64
+)
+
+// active returns the currency costs are displayed in and its rate per USD
+// Without a known rate, costs stay in USD rather than being shown wrong
+func active() (string, float64) {
+	activeMu.Lock()
+	defer activeMu.Unlock()
+
+	if activeCode == "" {
+		activeCode, activeRate = USD, 1
+		if settings, err := LoadSettings(); err == nil {
+			code := settings.Current()
+			if rate, _, ok := Rate(settings, code); ok {
+				activeCode, activeRate = code, rate
+			}
+		}
+	}
+	return activeCode, activeRate
+}
+
+func resetActive() {
+	activeMu.Lock()
+	activeCode = ""
+	activeMu.Unlock()
+}
+
+// Code returns the currency costs are displayed in
+func Code() string {
+	code, _ := active()
+	return code
+}
+
+// Convert converts a USD amount to the display currency
+func Convert(usd float64) float64 {
+	_, rate := active()
+	return usd * rate
+}
+
+// Format converts a USD amount and renders it in the display currency, e.g. "€12.30"
+func Format(usd float64) string {
+	return FormatPrecise(usd, 2)
+}
+
+// FormatPrecise is Format with decimals digits for currencies shown with cents;
+// currencies without minor units get correspondingly fewer
+func FormatPrecise(usd float64, decimals int) string {
+	code, rate := active()
+	return formatIn(code, usd*rate, decimals)
+}
+
+func formatIn(code string, amount float64, decimals int) string {
+	if minor, ok := minorDigits[code]; ok {
+		decimals = max(decimals-(2-minor), 0)
+	}
+	number := fmt.Sprintf("%.*f", decimals, math.Abs(amount))
+	sign := ""
+	if amount < 0 && strings.Trim(number, "0.") != "" {
+		sign = "-"
+	}
+	return sign + fmt.Sprintf(formats[code], number)
+}
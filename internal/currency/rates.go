@@ -0,0 +1,152 @@
+package currency
+
+import (
+	"context"
+	"encoding/xml"
+	"fmt"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strconv"
+	"time"
+
+	"github.com/OlaHulleberg/clauderock/internal/fileutil"
+)
+
+// RatesTTL is how long fetched exchange rates are used before they're refreshed
+const RatesTTL = 24 * time.Hour
+
+// ecbRatesURL is the European Central Bank's daily reference rates; it's public and needs no key
+const ecbRatesURL = "https://www.ecb.europa.eu/stats/eurofxref/eurofxref-daily.xml"
+
+// RateCache is the exchange rates saved on disk by RefreshRates
+type RateCache struct {
+	Updated time.Time          `json:"updated"` // When rates were last fetched successfully
+	Checked time.Time          `json:"checked"` // When a fetch was last attempted
+	Date    string             `json:"date"`    // Reference date the source published the rates for
+	Source  string             `json:"source"`
+	Rates   map[string]float64 `json:"rates"` // Units per USD
+}
+
+func ratesPath() (string, error) {
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return "", fmt.Errorf("failed to get home directory: %w", err)
+	}
+	return filepath.Join(home, ".clauderock", "exchange-rates.json"), nil
+}
+
+// LoadRates reads the saved exchange rates; it returns nil when rates were never fetched
+func LoadRates() (*RateCache, error) {
+	path, err := ratesPath()
+	if err != nil {
+		return nil, err
+	}
+	var cache RateCache
+	found, err := fileutil.LoadJSON(path, &cache)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read exchange rates: %w", err)
+	}
+	if !found {
+		return nil, nil
+	}
+	return &cache, nil
+}
+
+// ecbEnvelope is the subset of the ECB reference rates document used here
+type ecbEnvelope struct {
+	Cube struct {
+		Cube struct {
+			Time  string `xml:"time,attr"`
+			Rates []struct {
+				Currency string `xml:"currency,attr"`
+				Rate     string `xml:"rate,attr"`
+			} `xml:"Cube"`
+		} `xml:"Cube"`
+	} `xml:"Cube"`
+}
+
+// fetchECBRates fetches the ECB reference rates and converts them from per EUR to per USD
+func fetchECBRates(ctx context.Context) (date string, rates map[string]float64, err error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, ecbRatesURL, nil)
+	if err != nil {
+		return "", nil, err
+	}
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return "", nil, fmt.Errorf("failed to fetch exchange rates: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return "", nil, fmt.Errorf("exchange rate source returned status %d", resp.StatusCode)
+	}
+
+	var doc ecbEnvelope
+	if err := xml.NewDecoder(resp.Body).Decode(&doc); err != nil {
+		return "", nil, fmt.Errorf("failed to parse exchange rates: %w", err)
+	}
+
+	perEUR := map[string]float64{"EUR": 1}
+	for _, r := range doc.Cube.Cube.Rates {
+		if rate, err := strconv.ParseFloat(r.Rate, 64); err == nil && rate > 0 {
+			perEUR[r.Currency] = rate
+		}
+	}
+	usd, ok := perEUR[USD]
+	if !ok {
+		return "", nil, fmt.Errorf("exchange rates are missing USD")
+	}
+
+	rates = make(map[string]float64)
+	for _, code := range Supported {
+		if rate, ok := perEUR[code]; ok {
+			rates[code] = rate / usd
+		}
+	}
+	return doc.Cube.Cube.Time, rates, nil
+}
+
+// RefreshRates fetches current exchange rates and saves them
+func RefreshRates(ctx context.Context) (*RateCache, error) {
+	path, err := ratesPath()
+	if err != nil {
+		return nil, err
+	}
+	cache, err := fileutil.RefreshCache(path, func(cache *RateCache) error {
+		cache.Checked = time.Now()
+		date, rates, err := fetchECBRates(ctx)
+		if err != nil {
+			return err
+		}
+		cache.Updated = cache.Checked
+		cache.Date = date
+		cache.Source = "ecb"
+		cache.Rates = rates
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	resetActive()
+	return cache, nil
+}
+
+// RefreshRatesIfStale refreshes the exchange rates when costs are shown in a currency
+// without a user-provided rate and the cached rates are older than RatesTTL
+func RefreshRatesIfStale(ctx context.Context) error {
+	settings, err := LoadSettings()
+	if err != nil {
+		return err
+	}
+	code := settings.Current()
+	if _, userProvided := settings.Rates[code]; code == USD || userProvided {
+		return nil
+	}
+	if cache, err := LoadRates(); err == nil && cache != nil && time.Since(cache.Checked) < RatesTTL {
+		return nil
+	}
+	_, err = RefreshRates(ctx)
+	return err
+}
@@ -0,0 +1,282 @@
+// Package daemon serves a local JSON-RPC API over a Unix socket, so shell prompts, status
+// bars and editor plugins can ask for the active profile or live session stats without
+// starting clauderock for every query
+package daemon
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"net/rpc"
+	"net/rpc/jsonrpc"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+
+	"github.com/OlaHulleberg/clauderock/internal/monitoring"
+	"github.com/OlaHulleberg/clauderock/internal/profiles"
+	"github.com/OlaHulleberg/clauderock/internal/usage"
+)
+
+// ServiceName prefixes every method, e.g. "Clauderock.ActiveProfile"
+const ServiceName = "Clauderock"
+
+// liveSessionIdle is how long a followed session log is kept after its last query
+const liveSessionIdle = 10 * time.Minute
+
+// Bounds of the wait before accepting again after Accept fails
+const (
+	acceptRetryMin = 5 * time.Millisecond
+	acceptRetryMax = time.Second
+)
+
+// SocketPath returns the default socket location
+func SocketPath() (string, error) {
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return "", fmt.Errorf("failed to get home directory: %w", err)
+	}
+	return filepath.Join(home, ".clauderock", "daemon.sock"), nil
+}
+
+// ProfileArgs asks which profile a launch from Dir would use
+type ProfileArgs struct {
+	Dir string `json:"dir,omitempty"` // Empty ignores .clauderock bindings
+}
+
+// SwitchArgs names the profile to make active
+type SwitchArgs struct {
+	Name string `json:"name"`
+}
+
+// ProfileReply describes a profile
+type ProfileReply struct {
+	Name        string `json:"name"`
+	Source      string `json:"source"` // "binding" or "current"
+	BindingPath string `json:"binding-path,omitempty"`
+	ProfileType string `json:"profile-type"`
+	Region      string `json:"region,omitempty"`
+	Model       string `json:"model"`
+	FastModel   string `json:"fast-model"`
+	HeavyModel  string `json:"heavy-model"`
+}
+
+// StatsArgs picks the session to report on
+type StatsArgs struct {
+	Dir string `json:"dir,omitempty"` // Empty picks the most recently active session anywhere
+}
+
+// StatsReply is a snapshot of a live session
+type StatsReply struct {
+	Path                string    `json:"path"`
+	Requests            int       `json:"requests"`
+	InputTokens         int64     `json:"input-tokens"`
+	OutputTokens        int64     `json:"output-tokens"`
+	CacheReadTokens     int64     `json:"cache-read-tokens"`
+	CacheCreationTokens int64     `json:"cache-creation-tokens"`
+	CacheHitRate        float64   `json:"cache-hit-rate"`
+	RollingTPM          float64   `json:"rolling-tpm"`
+	RollingRPM          float64   `json:"rolling-rpm"`
+	ThrottleEvents      int       `json:"throttle-events"`
+	LastCall            time.Time `json:"last-call,omitzero"`
+	CostUSD             float64   `json:"cost-usd"`
+	CostApproximate     bool      `json:"cost-approximate,omitempty"`
+}
+
+// Service implements the RPC methods
+type Service struct {
+	mgr *profiles.Manager
+
+	mu   sync.Mutex
+	live map[string]*followedSession
+}
+
+// followedSession keeps a session log's parse state between queries, so each query only reads new lines
+type followedSession struct {
+	session  *monitoring.LiveSession
+	lastUsed time.Time
+}
+
+// NewService creates the RPC service
+func NewService(mgr *profiles.Manager) *Service {
+	return &Service{mgr: mgr, live: make(map[string]*followedSession)}
+}
+
+// ActiveProfile reports the profile a launch from args.Dir would use
+func (s *Service) ActiveProfile(args ProfileArgs, reply *ProfileReply) error {
+	var binding *profiles.ProjectBinding
+	if args.Dir != "" {
+		var err error
+		if binding, err = s.mgr.ProjectProfile(args.Dir); err != nil {
+			return err
+		}
+	}
+
+	name := ""
+	if binding != nil {
+		name = binding.Profile
+		reply.Source = "binding"
+		reply.BindingPath = binding.Path
+	} else {
+		current, err := s.mgr.GetCurrent()
+		if err != nil {
+			return err
+		}
+		name = current
+		reply.Source = "current"
+	}
+	return s.describe(name, reply)
+}
+
+// SwitchProfile makes a profile the active one, like 'manage config switch'
+func (s *Service) SwitchProfile(args SwitchArgs, reply *ProfileReply) error {
+	if err := s.mgr.SetCurrent(args.Name); err != nil {
+		return err
+	}
+	reply.Source = "current"
+	return s.describe(args.Name, reply)
+}
+
+func (s *Service) describe(name string, reply *ProfileReply) error {
+	cfg, err := s.mgr.Load(name)
+	if err != nil {
+		return fmt.Errorf("failed to load profile '%s': %w", name, err)
+	}
+	reply.Name = name
+	reply.ProfileType = cfg.ProfileType
+	switch cfg.ProfileType {
+	case "bedrock":
+		reply.Region = cfg.Region
+	case "vertex":
+		reply.Region = cfg.VertexRegion
+	}
+	reply.Model = cfg.Model
+	reply.FastModel = cfg.FastModel
+	reply.HeavyModel = cfg.HeavyModel
+	return nil
+}
+
+// LiveStats reports the running session in args.Dir, or the most recently active one
+func (s *Service) LiveStats(args StatsArgs, reply *StatsReply) error {
+	path, err := monitoring.FindActiveSessionJSONL(args.Dir)
+	if err != nil {
+		return err
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	now := time.Now()
+	for p, followed := range s.live {
+		if now.Sub(followed.lastUsed) > liveSessionIdle {
+			delete(s.live, p)
+		}
+	}
+	followed, ok := s.live[path]
+	if !ok {
+		followed = &followedSession{session: monitoring.NewLiveSession(path)}
+		s.live[path] = followed
+	}
+	followed.lastUsed = now
+	if err := followed.session.Poll(); err != nil {
+		return err
+	}
+
+	m := followed.session.Metrics(now)
+	cost, approximate := usage.LiveCost(m)
+	*reply = StatsReply{
+		Path:                path,
+		Requests:            m.Requests,
+		InputTokens:         m.InputTokens,
+		OutputTokens:        m.OutputTokens,
+		CacheReadTokens:     m.CacheReadTokens,
+		CacheCreationTokens: m.CacheCreationTokens,
+		CacheHitRate:        m.CacheHitRate,
+		RollingTPM:          m.RollingTPM,
+		RollingRPM:          m.RollingRPM,
+		ThrottleEvents:      m.ThrottleEvents,
+		LastCall:            m.LastCall,
+		CostUSD:             cost,
+		CostApproximate:     approximate,
+	}
+	return nil
+}
+
+// Listen opens the socket at path, replacing one left by a daemon that didn't shut down cleanly
+func Listen(path string) (net.Listener, error) {
+	if err := clearStaleSocket(path); err != nil {
+		return nil, err
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0700); err != nil {
+		return nil, fmt.Errorf("failed to create socket directory: %w", err)
+	}
+
+	listener, err := listenPrivate(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to listen on %s: %w", path, err)
+	}
+	// Only the user may talk to the daemon; it can switch their profile. The socket is
+	// created private where the platform allows, and this covers the rest
+	if err := os.Chmod(path, 0600); err != nil {
+		listener.Close()
+		return nil, fmt.Errorf("failed to restrict socket permissions: %w", err)
+	}
+	return listener, nil
+}
+
+// Serve answers requests on listener until ctx is cancelled, then closes it
+func Serve(ctx context.Context, listener net.Listener, service *Service) error {
+	defer listener.Close()
+
+	server := rpc.NewServer()
+	if err := server.RegisterName(ServiceName, service); err != nil {
+		return fmt.Errorf("failed to register service: %w", err)
+	}
+
+	go func() {
+		<-ctx.Done()
+		listener.Close()
+	}()
+
+	// Accept failures such as running out of file descriptors usually persist for a while,
+	// so wait before retrying, longer each time, rather than spinning
+	var delay time.Duration
+	for {
+		conn, err := listener.Accept()
+		if err != nil {
+			if ctx.Err() != nil {
+				return nil
+			}
+			if errors.Is(err, net.ErrClosed) {
+				return err
+			}
+			delay = min(max(2*delay, acceptRetryMin), acceptRetryMax)
+			select {
+			case <-ctx.Done():
+				return nil
+			case <-time.After(delay):
+			}
+			continue
+		}
+		delay = 0
+		go server.ServeCodec(jsonrpc.NewServerCodec(conn))
+	}
+}
+
+// clearStaleSocket removes a socket left by a daemon that didn't shut down cleanly,
+// and refuses to start next to one that is still running
+func clearStaleSocket(path string) error {
+	if _, err := os.Stat(path); os.IsNotExist(err) {
+		return nil
+	}
+	if conn, err := net.DialTimeout("unix", path, time.Second); err == nil {
+		conn.Close()
+		return fmt.Errorf("a daemon is already listening on %s", path)
+	}
+	if err := os.Remove(path); err != nil {
+		return fmt.Errorf("failed to remove stale socket %s: %w", path, err)
+	}
+	return nil
+}
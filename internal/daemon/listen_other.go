@@ -0,0 +1,10 @@
+//go:build !(darwin || dragonfly || freebsd || linux || netbsd || openbsd)
+
+package daemon
+
+import "net"
+
+// listenPrivate creates the socket; without a umask, Listen restricts it right after
+func listenPrivate(path string) (net.Listener, error) {
+	return net.Listen("unix", path)
+}
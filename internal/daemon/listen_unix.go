@@ -0,0 +1,16 @@
+//go:build darwin || dragonfly || freebsd || linux || netbsd || openbsd
+
+package daemon
+
+import (
+	"net"
+	"syscall"
+)
+
+// listenPrivate creates the socket with no group or other permissions from the start,
+// so there is no window in which another user could connect before it is restricted
+func listenPrivate(path string) (net.Listener, error) {
+	old := syscall.Umask(0o077)
+	defer syscall.Umask(old)
+	return net.Listen("unix", path)
+}
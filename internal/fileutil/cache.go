@@ -0,0 +1,53 @@
+package fileutil
+
+import (
+	"encoding/json"
+	"errors"
+	"io/fs"
+	"os"
+	"path/filepath"
+)
+
+// LoadJSON decodes the JSON file at path into v; found is false when the file doesn't exist
+func LoadJSON(path string, v any) (found bool, err error) {
+	data, err := os.ReadFile(path)
+	if errors.Is(err, fs.ErrNotExist) {
+		return false, nil
+	}
+	if err != nil {
+		return false, err
+	}
+	return true, json.Unmarshal(data, v)
+}
+
+// SaveJSON writes v to path as indented JSON, atomically, creating its directory if needed
+func SaveJSON(path string, v any, perm os.FileMode) error {
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return err
+	}
+	data, err := json.MarshalIndent(v, "", "  ")
+	if err != nil {
+		return err
+	}
+	return WriteFileAtomic(path, data, perm)
+}
+
+// RefreshCache updates a cache of downloaded data: it loads the cache at path, starting empty
+// when it is missing or unreadable, lets fetch download into it, and saves it. fetch records
+// the attempt in the cache even when it fails, and the cache is saved either way, so a
+// download that keeps failing is retried once its cache goes stale rather than on every run
+func RefreshCache[T any](path string, fetch func(cache *T) error) (*T, error) {
+	cache := new(T)
+	if _, err := LoadJSON(path, cache); err != nil {
+		cache = new(T)
+	}
+
+	fetchErr := fetch(cache)
+	if err := SaveJSON(path, cache, 0644); err != nil {
+		return nil, err
+	}
+	if fetchErr != nil {
+		return nil, fetchErr
+	}
+	return cache, nil
+}
@@ -0,0 +1,50 @@
+package fileutil
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"time"
+)
+
+const (
+	// lockTimeout bounds how long a change waits for another clauderock process to finish its own
+	lockTimeout = 10 * time.Second
+	// lockPollInterval is how often a waiting change retries the lock
+	lockPollInterval = 25 * time.Millisecond
+)
+
+// errLocked is returned by tryLockFile when another process holds the lock
+var errLocked = errors.New("locked by another process")
+
+// Lock takes an advisory lock on the file at path, creating it, to serialize a
+// read-modify-write across clauderock processes; what names the locked data in errors
+// The lock is not reentrant. Readers don't need it when writers replace files atomically
+func Lock(path, what string) (unlock func(), err error) {
+	f, err := os.OpenFile(path, os.O_CREATE|os.O_RDWR, 0644)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open the lock for %s: %w", what, err)
+	}
+
+	deadline := time.Now().Add(lockTimeout)
+	for {
+		err := tryLockFile(f)
+		if err == nil {
+			break
+		}
+		if !errors.Is(err, errLocked) {
+			f.Close()
+			return nil, fmt.Errorf("failed to lock %s: %w", what, err)
+		}
+		if time.Now().After(deadline) {
+			f.Close()
+			return nil, fmt.Errorf("timed out waiting for another clauderock process to finish changing %s (lock: %s)", what, path)
+		}
+		time.Sleep(lockPollInterval)
+	}
+
+	return func() {
+		unlockFile(f)
+		f.Close()
+	}, nil
+}
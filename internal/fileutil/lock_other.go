@@ -0,0 +1,12 @@
+//go:build !(darwin || dragonfly || freebsd || linux || netbsd || openbsd || windows)
+
+package fileutil
+
+import "os"
+
+// tryLockFile is a no-op where clauderock has no file locking; writes are still atomic
+func tryLockFile(f *os.File) error {
+	return nil
+}
+
+func unlockFile(f *os.File) {}
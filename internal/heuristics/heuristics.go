@@ -0,0 +1,117 @@
+package heuristics
+
+import (
+	"fmt"
+	"sync"
+)
+
+// Model families that suggestions refer to; they match any model ID containing "claude-<family>"
+const (
+	FamilyOpus   = "opus"
+	FamilySonnet = "sonnet"
+	FamilyHaiku  = "haiku"
+)
+
+// Suggestion recommends a model family for one slot (main, fast or heavy)
+type Suggestion struct {
+	Slot      string
+	Family    string
+	Rationale string
+	Source    string // Name of the heuristic that made the suggestion
+}
+
+// Suggestions holds at most one suggestion per slot
+type Suggestions map[string]Suggestion
+
+// Heuristic turns a project profile into model suggestions
+// Register additional heuristics to extend the built-in rules
+type Heuristic interface {
+	Name() string
+	Suggest(p ProjectProfile) []Suggestion
+}
+
+var (
+	registryMu sync.Mutex
+	registry   = []Heuristic{
+		largeRepoHeuristic{},
+		smallRepoHeuristic{},
+	}
+)
+
+// Register adds a heuristic; heuristics registered earlier win when they suggest the same slot
+func Register(h Heuristic) {
+	registryMu.Lock()
+	defer registryMu.Unlock()
+	registry = append(registry, h)
+}
+
+// Suggest runs every registered heuristic and keeps the first suggestion for each slot
+func Suggest(p ProjectProfile) Suggestions {
+	registryMu.Lock()
+	heuristics := append([]Heuristic(nil), registry...)
+	registryMu.Unlock()
+
+	suggestions := make(Suggestions)
+	for _, h := range heuristics {
+		for _, s := range h.Suggest(p) {
+			if _, exists := suggestions[s.Slot]; exists {
+				continue
+			}
+			s.Source = h.Name()
+			suggestions[s.Slot] = s
+		}
+	}
+	return suggestions
+}
+
+// largeRepoFiles is the file count above which a repository counts as large
+const largeRepoFiles = 5000
+
+// smallRepoFiles is the file count below which a repository counts as small
+const smallRepoFiles = 200
+
+// largeRepoHeuristic suggests a heavier main model for large repositories and monorepos,
+// where changes span packages and more context has to be reasoned about at once
+type largeRepoHeuristic struct{}
+
+func (largeRepoHeuristic) Name() string { return "large-repo" }
+
+func (largeRepoHeuristic) Suggest(p ProjectProfile) []Suggestion {
+	switch {
+	case p.Monorepo() && p.FileCount >= largeRepoFiles:
+		return []Suggestion{{
+			Slot:      "main",
+			Family:    FamilyOpus,
+			Rationale: fmt.Sprintf("large monorepo (%d files); cross-package changes benefit from deeper reasoning", p.FileCount),
+		}}
+	case p.Monorepo():
+		return []Suggestion{{
+			Slot:      "main",
+			Family:    FamilySonnet,
+			Rationale: "monorepo; a balanced main model keeps multi-package sessions affordable",
+		}}
+	case p.FileCount >= largeRepoFiles:
+		return []Suggestion{{
+			Slot:      "main",
+			Family:    FamilyOpus,
+			Rationale: fmt.Sprintf("large codebase (%d files); deeper reasoning pays off", p.FileCount),
+		}}
+	}
+	return nil
+}
+
+// smallRepoHeuristic suggests lighter models for small projects where the heavy tier rarely pays off
+type smallRepoHeuristic struct{}
+
+func (smallRepoHeuristic) Name() string { return "small-repo" }
+
+func (smallRepoHeuristic) Suggest(p ProjectProfile) []Suggestion {
+	if p.Monorepo() || p.FileCount >= smallRepoFiles {
+		return nil
+	}
+	return []Suggestion{{
+		Slot:      "heavy",
+		Family:    FamilySonnet,
+		Rationale: fmt.Sprintf("small project (%d files); the heaviest tier rarely pays off", p.FileCount),
+	}}
+}
@@ -0,0 +1,219 @@
+package heuristics
+
+import (
+	"bytes"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+)
+
+// maxInspectedFiles caps how many files are walked so huge checkouts stay fast
+const maxInspectedFiles = 50000
+
+// ProjectProfile describes the shape of a working repository
+type ProjectProfile struct {
+	Dir             string
+	Languages       []string // Ordered by number of files, most common first
+	FileCount       int
+	SizeBytes       int64
+	Truncated       bool     // The walk stopped at maxInspectedFiles
+	MonorepoMarkers []string // Workspace files or repeated manifests that indicate a monorepo
+}
+
+// Monorepo reports whether any monorepo markers were found
+func (p ProjectProfile) Monorepo() bool {
+	return len(p.MonorepoMarkers) > 0
+}
+
+// Summary returns a one-line description of the project
+func (p ProjectProfile) Summary() string {
+	var parts []string
+	if len(p.Languages) > 0 {
+		langs := p.Languages
+		if len(langs) > 3 {
+			langs = langs[:3]
+		}
+		parts = append(parts, strings.Join(langs, ", "))
+	}
+
+	files := fmt.Sprintf("%d files", p.FileCount)
+	if p.Truncated {
+		files = fmt.Sprintf("%d+ files", p.FileCount)
+	}
+	parts = append(parts, files)
+
+	if p.Monorepo() {
+		parts = append(parts, fmt.Sprintf("monorepo (%s)", strings.Join(p.MonorepoMarkers, ", ")))
+	}
+	return strings.Join(parts, " · ")
+}
+
+// skippedDirs are dependency, build and VCS directories that say nothing about the project itself
+var skippedDirs = map[string]bool{
+	".git":         true,
+	".hg":          true,
+	".svn":         true,
+	"node_modules": true,
+	"vendor":       true,
+	"dist":         true,
+	"build":        true,
+	"target":       true,
+	".venv":        true,
+	"venv":         true,
+	"__pycache__":  true,
+	".next":        true,
+	".terraform":   true,
+}
+
+// languageByExt maps source file extensions to language names
+var languageByExt = map[string]string{
+	".go":    "Go",
+	".rs":    "Rust",
+	".py":    "Python",
+	".ts":    "TypeScript",
+	".tsx":   "TypeScript",
+	".js":    "JavaScript",
+	".jsx":   "JavaScript",
+	".mjs":   "JavaScript",
+	".java":  "Java",
+	".kt":    "Kotlin",
+	".scala": "Scala",
+	".rb":    "Ruby",
+	".php":   "PHP",
+	".cs":    "C#",
+	".c":     "C",
+	".h":     "C",
+	".cc":    "C++",
+	".cpp":   "C++",
+	".hpp":   "C++",
+	".swift": "Swift",
+	".m":     "Objective-C",
+	".ex":    "Elixir",
+	".exs":   "Elixir",
+	".hs":    "Haskell",
+	".lua":   "Lua",
+	".sh":    "Shell",
+	".tf":    "Terraform",
+	".sql":   "SQL",
+	".dart":  "Dart",
+}
+
+// workspaceMarkers are root files that declare a multi-package workspace
+var workspaceMarkers = []string{
+	"go.work",
+	"pnpm-workspace.yaml",
+	"lerna.json",
+	"nx.json",
+	"turbo.json",
+	"rush.json",
+	"WORKSPACE",
+	"WORKSPACE.bazel",
+	"MODULE.bazel",
+}
+
+// manifestFiles are per-package manifests; several of them also indicate a monorepo
+var manifestFiles = map[string]bool{
+	"go.mod":         true,
+	"package.json":   true,
+	"Cargo.toml":     true,
+	"pyproject.toml": true,
+	"pom.xml":        true,
+	"build.gradle":   true,
+}
+
+// minRepeatedManifests is how many package manifests make a repository count as a monorepo
+const minRepeatedManifests = 4
+
+// Inspect walks dir and describes the project it contains
+func Inspect(dir string) (ProjectProfile, error) {
+	absDir, err := filepath.Abs(dir)
+	if err != nil {
+		return ProjectProfile{}, fmt.Errorf("failed to resolve project directory: %w", err)
+	}
+
+	profile := ProjectProfile{Dir: absDir}
+	langCounts := make(map[string]int)
+	manifests := 0
+
+	err = filepath.WalkDir(absDir, func(path string, d fs.DirEntry, walkErr error) error {
+		if walkErr != nil {
+			// Unreadable subdirectories are skipped rather than failing the inspection
+			if d != nil && d.IsDir() && path != absDir {
+				return fs.SkipDir
+			}
+			return walkErr
+		}
+
+		if d.IsDir() {
+			if path != absDir && skippedDirs[d.Name()] {
+				return fs.SkipDir
+			}
+			return nil
+		}
+
+		if profile.FileCount >= maxInspectedFiles {
+			profile.Truncated = true
+			return fs.SkipAll
+		}
+		profile.FileCount++
+
+		if info, err := d.Info(); err == nil {
+			profile.SizeBytes += info.Size()
+		}
+		if lang, ok := languageByExt[strings.ToLower(filepath.Ext(d.Name()))]; ok {
+			langCounts[lang]++
+		}
+		if manifestFiles[d.Name()] {
+			manifests++
+		}
+		return nil
+	})
+	if err != nil {
+		return ProjectProfile{}, fmt.Errorf("failed to inspect %s: %w", absDir, err)
+	}
+
+	profile.Languages = rankLanguages(langCounts)
+	profile.MonorepoMarkers = findMonorepoMarkers(absDir, manifests)
+	return profile, nil
+}
+
+// rankLanguages orders languages by file count, breaking ties by name
+func rankLanguages(counts map[string]int) []string {
+	langs := make([]string, 0, len(counts))
+	for lang := range counts {
+		langs = append(langs, lang)
+	}
+	sort.Slice(langs, func(i, j int) bool {
+		if counts[langs[i]] != counts[langs[j]] {
+			return counts[langs[i]] > counts[langs[j]]
+		}
+		return langs[i] < langs[j]
+	})
+	return langs
+}
+
+// findMonorepoMarkers looks for workspace declarations in the project root
+func findMonorepoMarkers(dir string, manifests int) []string {
+	var markers []string
+	for _, name := range workspaceMarkers {
+		if _, err := os.Stat(filepath.Join(dir, name)); err == nil {
+			markers = append(markers, name)
+		}
+	}
+
+	// Workspaces declared inside the root manifest
+	if data, err := os.ReadFile(filepath.Join(dir, "package.json")); err == nil && bytes.Contains(data, []byte(`"workspaces"`)) {
+		markers = append(markers, "package.json workspaces")
+	}
+	if data, err := os.ReadFile(filepath.Join(dir, "Cargo.toml")); err == nil && bytes.Contains(data, []byte("[workspace]")) {
+		markers = append(markers, "Cargo workspace")
+	}
+
+	if manifests >= minRepeatedManifests {
+		markers = append(markers, fmt.Sprintf("%d package manifests", manifests))
+	}
+	return markers
+}
@@ -0,0 +1,93 @@
+package i18n
+
+// Norwegian Bokmål translations
+func init() {
+	set(norwegianBokmal, map[string]string{
+		// Wizard
+		"Select Profile Type":                               "Velg profiltype",
+		"Choose authentication method...":                   "Velg autentiseringsmetode...",
+		"Select AWS Profile":                                "Velg AWS-profil",
+		"Type to filter profiles...":                        "Skriv for å filtrere profiler...",
+		"Filter AWS Regions":                                "Filtrer AWS-regioner",
+		"Type to filter regions...":                         "Skriv for å filtrere regioner...",
+		"Select Cross Region":                               "Velg kryssregion",
+		"Select Vertex AI Region":                           "Velg Vertex AI-region",
+		"Type to filter...":                                 "Skriv for å filtrere...",
+		"Select Main Model":                                 "Velg hovedmodell",
+		"Select Fast Model":                                 "Velg rask modell",
+		"Select Heavy Model":                                "Velg tung modell",
+		"Type to filter models...":                          "Skriv for å filtrere modeller...",
+		"Fetching available models...":                      "Henter tilgjengelige modeller...",
+		"Resolving model profile IDs...":                    "Slår opp modellprofil-ID-er...",
+		"Configuration:":                                    "Konfigurasjon:",
+		"Configuration saved successfully to profile '%s'!": "Konfigurasjonen ble lagret i profilen '%s'!",
+		"Progress saved. Run the setup again to resume where you left off.": "Fremdriften er lagret. Kjør oppsettet igjen for å fortsette der du slapp.",
+		"Enter your API key": "Skriv inn API-nøkkelen",
+		"It will be stored in clauderock's keyring file, readable only by you": "Den lagres i nøkkelringfilen til clauderock, som bare du kan lese",
+
+		// Stats
+		"Session Statistics": "Øktstatistikk",
+		"All Time":           "Hele perioden",
+		"%s to %s":           "%s til %s",
+		"Since %s":           "Siden %s",
+		"Until %s":           "Frem til %s",
+		"No sessions found matching the criteria.": "Fant ingen økter som samsvarer med kriteriene.",
+		"Total Sessions:":                          "Totalt antall økter:",
+		"Total Coding Time:":                       "Total kodetid:",
+		"Wall-Clock Time:":                         "Klokketid:",
+		"%.2f hours":                               "%.2f timer",
+		"Average Session:":                         "Gjennomsnittlig økt:",
+		"%.1f minutes":                             "%.1f minutter",
+		"Token Usage":                              "Tokenbruk",
+		"Total Requests:":                          "Totalt antall forespørsler:",
+		"Input Tokens:":                            "Inndata-tokens:",
+		"Output Tokens:":                           "Utdata-tokens:",
+		"Total Tokens:":                            "Totalt antall tokens:",
+		"Tokens Per Minute (TPM)":                  "Tokens per minutt (TPM)",
+		"Requests Per Minute (RPM)":                "Forespørsler per minutt (RPM)",
+		"Average:":                                 "Gjennomsnitt:",
+		"Peak:":                                    "Topp:",
+		"Cache Efficiency":                         "Cache-effektivitet",
+		"Average Hit Rate:":                        "Gjennomsnittlig treffrate:",
+		"(low)":                                    "(lav)",
+		"Heavy Model Usage":                        "Bruk av tung modell",
+		"Heavy Requests:":                          "Forespørsler til tung modell:",
+		"(%.1f%% of %s)":                           "(%.1f%% av %s)",
+		"Share of Cost:":                           "Andel av kostnad:",
+		"%d heavy requests, %.1f%% of its cost":    "%d forespørsler til tung modell, %.1f%% av kostnaden",
+		"First-Token Latency":                      "Tid til første token",
+		"avg %s ms, P95 %s ms":                     "snitt %s ms, P95 %s ms",
+		"(%d calls)":                               "(%d kall)",
+		"By Profile":                               "Per profil",
+		"By Model":                                 "Per modell",
+		"Top Sessions by TPM":                      "Økter med høyest TPM",
+		"Top Sessions by Tokens":                   "Økter med flest tokens",
+		"Top Sessions by Cost":                     "Dyreste økter",
+		"Top Sessions by Active Time":              "Økter med lengst aktiv tid",
+		"Estimated Costs":                          "Estimerte kostnader",
+		"Based on actual token usage":              "Basert på faktisk tokenbruk",
+		"(%s requests, %s cache)":                  "(%s forespørsler, %s hurtigbuffer)",
+		"Requests by Hour":                         "Forespørsler per time",
+		"approximate, priced as %s":                "omtrentlig, priset som %s",
+		"price unknown, not included":              "ukjent pris, ikke inkludert",
+		"(includes approximate prices)":            "(inkluderer omtrentlige priser)",
+		"Provisioned Throughput (%s):":             "Provisioned Throughput (%s):",
+		"covered by commitment, cost unknown":      "dekket av forpliktelsen, ukjent kostnad",
+		"(%.0f hours at %s/hour)":                  "(%.0f timer à %s/time)",
+		"commitment starts %s, after this period":  "forpliktelsen starter %s, etter denne perioden",
+		"Total Estimated Cost:":                    "Total estimert kostnad:",
+		"(%d sessions)":                            "(%d økter)",
+		"Anomalies":                                "Avvik",
+		"Sessions far above their profile's baseline (runaway loops, huge pastes)": "Økter langt over profilens normalnivå (løkker som har løpt løpsk, store innlimeringer)",
+		"vs typical %s, %.1fσ":                              "mot typisk %s, %.1fσ",
+		"Sessions by Repository":                            "Økter per kodelager",
+		"Sessions by Branch":                                "Økter per gren",
+		"(%d sessions, %.1f hours, %s requests, %s tokens)": "(%d økter, %.1f timer, %s forespørsler, %s tokens)",
+
+		// Launch
+		"Configuration incomplete. Starting interactive setup...": "Konfigurasjonen er ufullstendig. Starter interaktivt oppsett...",
+		"failed to create profile manager":                        "kunne ikke opprette profilbehandler",
+		"failed to load config":                                   "kunne ikke laste konfigurasjonen",
+		"invalid configuration":                                   "ugyldig konfigurasjon",
+	})
+}
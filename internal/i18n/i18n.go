@@ -0,0 +1,92 @@
+package i18n
+
+import (
+	"fmt"
+	"os"
+	"strings"
+	"sync"
+
+	"golang.org/x/text/language"
+	"golang.org/x/text/message"
+	"golang.org/x/text/message/catalog"
+)
+
+// LangEnvVar overrides the detected locale (e.g., CLAUDEROCK_LANG=nb)
+const LangEnvVar = "CLAUDEROCK_LANG"
+
+// norwegianBokmal has no predefined tag in x/text/language
+var norwegianBokmal = language.MustParse("nb")
+
+// supported lists the languages with a catalog; English strings double as message keys
+var supported = []language.Tag{
+	language.English,
+	norwegianBokmal,
+}
+
+var (
+	once    sync.Once
+	printer *message.Printer
+	tag     language.Tag
+)
+
+// builder holds every translation; catalog files register theirs in init
+var builder = catalog.NewBuilder(catalog.Fallback(language.English))
+
+// set registers translations for one language
+func set(lang language.Tag, messages map[string]string) {
+	for key, msg := range messages {
+		if err := builder.SetString(lang, key, msg); err != nil {
+			panic(fmt.Sprintf("i18n: invalid message %q: %v", key, err))
+		}
+	}
+}
+
+func initPrinter() {
+	once.Do(func() {
+		matcher := language.NewMatcher(supported)
+		// Use the supported tag itself so numbers are formatted to match the message language
+		_, index, _ := matcher.Match(language.Make(detectLocale()))
+		tag = supported[index]
+		printer = message.NewPrinter(tag, message.Catalog(builder))
+	})
+}
+
+// detectLocale returns the user's locale from CLAUDEROCK_LANG or the POSIX locale variables
+func detectLocale() string {
+	for _, env := range []string{LangEnvVar, "LC_ALL", "LC_MESSAGES", "LANG"} {
+		value := strings.TrimSpace(os.Getenv(env))
+		if value == "" {
+			continue
+		}
+		// "nb_NO.UTF-8@euro" → "nb-NO"
+		value, _, _ = strings.Cut(value, ".")
+		value, _, _ = strings.Cut(value, "@")
+		if value == "C" || value == "POSIX" {
+			return "en"
+		}
+		return strings.ReplaceAll(value, "_", "-")
+	}
+	return "en"
+}
+
+// Language returns the language output is rendered in
+func Language() language.Tag {
+	initPrinter()
+	return tag
+}
+
+// Printer returns the printer for the detected language, also used for locale-aware numbers
+func Printer() *message.Printer {
+	initPrinter()
+	return printer
+}
+
+// T translates an English message and formats it with args
+func T(key string, args ...interface{}) string {
+	return Printer().Sprintf(key, args...)
+}
+
+// Wrap annotates err with a translated message, keeping it available to errors.Is/As
+func Wrap(err error, key string, args ...interface{}) error {
+	return fmt.Errorf("%s: %w", T(key, args...), err)
+}
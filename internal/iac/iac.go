@@ -0,0 +1,189 @@
+package iac
+
+import (
+	"encoding/json"
+	"fmt"
+	"regexp"
+	"strings"
+
+	"github.com/OlaHulleberg/clauderock/internal/aws"
+	"github.com/OlaHulleberg/clauderock/internal/config"
+	"gopkg.in/yaml.v3"
+)
+
+// Supported output formats
+const (
+	FormatTerraform      = "terraform"
+	FormatCloudFormation = "cloudformation"
+)
+
+// DefaultLogRetentionDays is the retention of the optional CloudWatch log group
+const DefaultLogRetentionDays = 30
+
+// Spec describes what to provision for one bedrock profile
+type Spec struct {
+	ProfileName      string
+	Config           *config.Config
+	AccountID        string // Empty matches any account in the policy
+	LogGroup         string // Empty omits the CloudWatch log group
+	LogRetentionDays int
+}
+
+// invalidNameChars are the characters IAM does not allow in policy names
+var invalidNameChars = regexp.MustCompile(`[^A-Za-z0-9+=,.@_-]`)
+
+// policyName returns the IAM policy name for the profile
+func (s Spec) policyName() string {
+	return "clauderock-" + invalidNameChars.ReplaceAllString(s.ProfileName, "-")
+}
+
+func (s Spec) modelIDs() []string {
+	return []string{s.Config.Model, s.Config.FastModel, s.Config.HeavyModel}
+}
+
+// Generate renders the snippet for the profile in the given format
+func Generate(format string, s Spec) (string, error) {
+	if s.Config.ProfileType != "bedrock" {
+		return "", fmt.Errorf("profile '%s' is a %s profile; infrastructure export only applies to bedrock profiles", s.ProfileName, s.Config.ProfileType)
+	}
+	if s.LogRetentionDays == 0 {
+		s.LogRetentionDays = DefaultLogRetentionDays
+	}
+
+	policy, err := aws.LeastPrivilegePolicy(s.Config.Region, s.AccountID, s.modelIDs()...)
+	if err != nil {
+		return "", fmt.Errorf("failed to generate policy: %w", err)
+	}
+
+	switch format {
+	case FormatTerraform:
+		return terraform(s, policy), nil
+	case FormatCloudFormation, "cfn":
+		return cloudFormation(s, policy)
+	default:
+		return "", fmt.Errorf("unknown format '%s' (use %s or %s)", format, FormatTerraform, FormatCloudFormation)
+	}
+}
+
+// modelAccessNotes explains the model access grants the profile relies on, which
+// cannot be provisioned as code and have to be requested in the Bedrock console
+func modelAccessNotes(s Spec) []string {
+	notes := []string{
+		fmt.Sprintf("Model access for clauderock profile %q (region %s).", s.ProfileName, s.Config.Region),
+		"Bedrock model access is granted per account in the console (Bedrock > Model access)",
+		"and cannot be provisioned here. Enable these models before first use:",
+	}
+	seen := make(map[string]bool)
+	for _, id := range s.modelIDs() {
+		if id == "" {
+			continue
+		}
+		model := id
+		if aws.IsFullProfileID(id) {
+			_, model, _ = strings.Cut(id, ".")
+		}
+		if seen[model] {
+			continue
+		}
+		seen[model] = true
+		notes = append(notes, "  - "+model)
+	}
+	return notes
+}
+
+func terraform(s Spec, policy []byte) string {
+	var b strings.Builder
+
+	fmt.Fprintf(&b, "# Generated by clauderock for profile %q\n#\n", s.ProfileName)
+	for _, line := range modelAccessNotes(s) {
+		b.WriteString("# " + line + "\n")
+	}
+	b.WriteString("\n")
+
+	fmt.Fprintf(&b, "resource \"aws_iam_policy\" \"clauderock\" {\n")
+	fmt.Fprintf(&b, "  name        = %q\n", s.policyName())
+	fmt.Fprintf(&b, "  description = %q\n", "Least-privilege Bedrock access for clauderock profile "+s.ProfileName)
+	b.WriteString("  policy      = <<-EOT\n")
+	for _, line := range strings.Split(string(policy), "\n") {
+		b.WriteString("    " + line + "\n")
+	}
+	b.WriteString("  EOT\n}\n")
+
+	if s.LogGroup != "" {
+		b.WriteString("\n")
+		b.WriteString("resource \"aws_cloudwatch_log_group\" \"clauderock\" {\n")
+		fmt.Fprintf(&b, "  name              = %q\n", s.LogGroup)
+		fmt.Fprintf(&b, "  retention_in_days = %d\n", s.LogRetentionDays)
+		b.WriteString("}\n")
+	}
+
+	b.WriteString("\noutput \"clauderock_policy_arn\" {\n  value = aws_iam_policy.clauderock.arn\n}\n")
+	return b.String()
+}
+
+// cloudFormation templates are built as data so quoting is always valid YAML
+type cfnTemplate struct {
+	AWSTemplateFormatVersion string                 `yaml:"AWSTemplateFormatVersion"`
+	Description              string                 `yaml:"Description"`
+	Resources                map[string]cfnResource `yaml:"Resources"`
+	Outputs                  map[string]cfnOutput   `yaml:"Outputs"`
+}
+
+type cfnResource struct {
+	Type       string                 `yaml:"Type"`
+	Properties map[string]interface{} `yaml:"Properties"`
+}
+
+type cfnOutput struct {
+	Value map[string]string `yaml:"Value"`
+}
+
+func cloudFormation(s Spec, policy []byte) (string, error) {
+	var document map[string]interface{}
+	if err := json.Unmarshal(policy, &document); err != nil {
+		return "", fmt.Errorf("failed to parse generated policy: %w", err)
+	}
+
+	tmpl := cfnTemplate{
+		AWSTemplateFormatVersion: "2010-09-09",
+		Description:              "Bedrock access for clauderock profile " + s.ProfileName,
+		Resources: map[string]cfnResource{
+			"ClauderockPolicy": {
+				Type: "AWS::IAM::ManagedPolicy",
+				Properties: map[string]interface{}{
+					"ManagedPolicyName": s.policyName(),
+					"Description":       "Least-privilege Bedrock access for clauderock profile " + s.ProfileName,
+					"PolicyDocument":    document,
+				},
+			},
+		},
+		Outputs: map[string]cfnOutput{
+			"ClauderockPolicyArn": {Value: map[string]string{"Ref": "ClauderockPolicy"}},
+		},
+	}
+	if s.LogGroup != "" {
+		tmpl.Resources["ClauderockLogGroup"] = cfnResource{
+			Type: "AWS::Logs::LogGroup",
+			Properties: map[string]interface{}{
+				"LogGroupName":    s.LogGroup,
+				"RetentionInDays": s.LogRetentionDays,
+			},
+		}
+	}
+
+	var b strings.Builder
+	fmt.Fprintf(&b, "# Generated by clauderock for profile %q\n#\n", s.ProfileName)
+	for _, line := range modelAccessNotes(s) {
+		b.WriteString("# " + line + "\n")
+	}
+
+	encoder := yaml.NewEncoder(&b)
+	encoder.SetIndent(2)
+	if err := encoder.Encode(tmpl); err != nil {
+		return "", fmt.Errorf("failed to render template: %w", err)
+	}
+	if err := encoder.Close(); err != nil {
+		return "", fmt.Errorf("failed to render template: %w", err)
+	}
+	return b.String(), nil
+}
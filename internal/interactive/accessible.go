@@ -0,0 +1,181 @@
+package interactive
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"os"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/OlaHulleberg/clauderock/internal/accessibility"
+)
+
+// stdinReader is shared by the plain prompts so buffered input is not lost between them
+var stdinReader = bufio.NewReader(os.Stdin)
+
+// readLine reads one trimmed line, returning io.EOF when input is closed
+func readLine() (string, error) {
+	line, err := stdinReader.ReadString('\n')
+	if err != nil && (err != io.EOF || line == "") {
+		return "", err
+	}
+	return strings.TrimSpace(line), nil
+}
+
+// selectSequential is the accessible selector: a numbered list answered on one line,
+// with no cursor movement, redraws, or colour-only states for screen readers to miss
+func selectSequential(title string, options []SelectOption, currentValue string, probe OptionProbe) (string, error) {
+	if probe != nil {
+		fmt.Println("Checking availability, please wait...")
+		runProbes(options, probe)
+	}
+
+	current := ""
+	for _, opt := range options {
+		if !opt.IsHeader && !opt.Disabled && opt.ID == currentValue {
+			current = opt.ID
+		}
+	}
+
+	filter := ""
+	for {
+		shown := filterOptions(options, filter)
+		var choices []SelectOption
+
+		fmt.Println()
+		fmt.Println(accessibility.PlainText(title))
+		if filter != "" {
+			fmt.Printf("Options matching %q:\n", filter)
+		}
+		for _, opt := range shown {
+			if opt.IsHeader {
+				if opt.Display != "" {
+					fmt.Printf("Section: %s\n", accessibility.PlainText(opt.Display))
+				}
+				continue
+			}
+			choices = append(choices, opt)
+			line := fmt.Sprintf("  %d. %s", len(choices), accessibility.PlainText(opt.Display))
+			if opt.Recommended {
+				line += " (recommended)"
+			}
+			if opt.ID == current {
+				line += " (current)"
+			}
+			if opt.Disabled {
+				line += " (unavailable)"
+			}
+			fmt.Println(line)
+		}
+		if len(choices) == 0 {
+			if filter == "" {
+				return "", fmt.Errorf("no options available")
+			}
+			fmt.Printf("No options match %q.\n", filter)
+			filter = ""
+			continue
+		}
+
+		prompt := "Enter a number, or text to filter the list"
+		if filter != "" {
+			prompt += ", or press Enter to show all options"
+		} else if current != "" {
+			prompt += fmt.Sprintf(", or press Enter to keep %s", current)
+		}
+		fmt.Printf("%s. Type q to cancel.\nChoice: ", prompt)
+
+		input, err := readLine()
+		if err != nil {
+			return "", fmt.Errorf("selection cancelled")
+		}
+
+		switch {
+		case input == "":
+			if filter == "" && current != "" {
+				return current, nil
+			}
+			filter = ""
+		case strings.EqualFold(input, "q"):
+			return "", fmt.Errorf("selection cancelled")
+		default:
+			if n, err := strconv.Atoi(input); err == nil {
+				if n < 1 || n > len(choices) {
+					fmt.Printf("Please enter a number between 1 and %d.\n", len(choices))
+					continue
+				}
+				if choices[n-1].Disabled {
+					fmt.Printf("Option %d is unavailable, choose another.\n", n)
+					continue
+				}
+				return choices[n-1].ID, nil
+			}
+			// An exact ID selects directly; anything else narrows the list
+			for _, opt := range choices {
+				if strings.EqualFold(opt.ID, input) && !opt.Disabled {
+					return opt.ID, nil
+				}
+			}
+			filter = input
+		}
+	}
+}
+
+// runProbes finishes every probe before the list is read out, so the
+// options do not change while a screen reader is announcing them
+func runProbes(options []SelectOption, probe OptionProbe) {
+	var wg sync.WaitGroup
+	for i := range options {
+		if options[i].IsHeader {
+			continue
+		}
+		wg.Add(1)
+		go func(opt *SelectOption) {
+			defer wg.Done()
+			note, usable := probe(opt.ID)
+			if note != "" {
+				opt.Display += " " + note
+			}
+			opt.Disabled = !usable
+		}(&options[i])
+	}
+	wg.Wait()
+}
+
+// confirmPlain is the accessible confirmation prompt
+func confirmPlain(title, message string, details []string) (bool, error) {
+	fmt.Printf("Warning: %s\n", accessibility.PlainText(title))
+	fmt.Println(message)
+	for _, detail := range details {
+		fmt.Println(accessibility.PlainText(detail))
+	}
+
+	for {
+		fmt.Print("Are you sure you want to continue? Type yes to confirm or no to cancel: ")
+		input, err := readLine()
+		if err != nil {
+			return false, nil
+		}
+		switch strings.ToLower(input) {
+		case "yes":
+			return true, nil
+		case "no", "n":
+			return false, nil
+		}
+	}
+}
+
+// promptPlain is the accessible text input
+func promptPlain(title, example string) (string, error) {
+	fmt.Println(accessibility.PlainText(title))
+	if example != "" {
+		fmt.Printf("For example: %s\n", example)
+	}
+	fmt.Print("Value: ")
+	input, err := readLine()
+	if err != nil {
+		return "", fmt.Errorf("input cancelled")
+	}
+	return input, nil
+}
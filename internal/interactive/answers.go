@@ -0,0 +1,126 @@
+package interactive
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+
+	"github.com/OlaHulleberg/clauderock/internal/api"
+	"github.com/OlaHulleberg/clauderock/internal/config"
+	"github.com/OlaHulleberg/clauderock/internal/vertex"
+	"gopkg.in/yaml.v3"
+)
+
+// WizardAnswers holds declarative answers for the configuration wizard
+// Any answer left empty is prompted for interactively
+type WizardAnswers struct {
+	ProfileType       string `yaml:"profile-type"`       // "bedrock", "api", or "vertex"
+	CredentialsSource string `yaml:"credentials-source"` // profile, environment, or default-chain (bedrock)
+	Profile           string `yaml:"profile"`            // AWS profile (bedrock)
+	Region            string `yaml:"region"`             // AWS region (bedrock)
+	CrossRegion       string `yaml:"cross-region"`       // us, eu, or global (bedrock)
+	BaseURL           string `yaml:"base-url"`           // API gateway base URL (api)
+	APIKeyEnv         string `yaml:"api-key-env"`        // Environment variable holding the API key (api)
+	GCPProject        string `yaml:"gcp-project"`        // Google Cloud project ID (vertex)
+	VertexRegion      string `yaml:"vertex-region"`      // Vertex AI region, e.g. us-east5 or global (vertex)
+	Model             string `yaml:"model"`
+	FastModel         string `yaml:"fast-model"`
+	HeavyModel        string `yaml:"heavy-model"`
+	SmokeTest         *bool  `yaml:"smoke-test"` // Run the post-setup test request without asking
+}
+
+// LoadAnswers reads and validates a YAML answers file
+// API keys are never read from the file itself; use api-key-env to name an environment variable
+func LoadAnswers(path string) (*WizardAnswers, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read answers file: %w", err)
+	}
+
+	var answers WizardAnswers
+	decoder := yaml.NewDecoder(bytes.NewReader(data))
+	decoder.KnownFields(true)
+	if err := decoder.Decode(&answers); err != nil && !errors.Is(err, io.EOF) {
+		return nil, fmt.Errorf("failed to parse answers file: %w", err)
+	}
+
+	if err := answers.validate(); err != nil {
+		return nil, fmt.Errorf("invalid answers file: %w", err)
+	}
+
+	return &answers, nil
+}
+
+// validate checks answers that can be verified without prompting
+func (a *WizardAnswers) validate() error {
+	switch a.ProfileType {
+	case "", "bedrock", "api", "vertex":
+	default:
+		return fmt.Errorf("profile-type must be 'bedrock', 'api', or 'vertex', got '%s'", a.ProfileType)
+	}
+
+	switch a.CrossRegion {
+	case "", "us", "eu", "global":
+	default:
+		return fmt.Errorf("cross-region must be 'us', 'eu', or 'global', got '%s'", a.CrossRegion)
+	}
+
+	switch a.CredentialsSource {
+	case "", config.CredentialsSourceProfile:
+	case config.CredentialsSourceEnvironment, config.CredentialsSourceDefaultChain:
+		if a.Profile != "" {
+			return fmt.Errorf("profile only applies to credentials-source 'profile'")
+		}
+	default:
+		return fmt.Errorf("credentials-source must be 'profile', 'environment', or 'default-chain', got '%s'", a.CredentialsSource)
+	}
+
+	if a.BaseURL != "" {
+		if _, err := api.ParseBaseURL(a.BaseURL); err != nil {
+			return err
+		}
+	}
+
+	if a.GCPProject != "" && !vertex.ValidProject(a.GCPProject) {
+		return fmt.Errorf("gcp-project '%s' is not a valid Google Cloud project ID", a.GCPProject)
+	}
+	if a.VertexRegion != "" && !vertex.ValidRegion(a.VertexRegion) {
+		return fmt.Errorf("vertex-region '%s' is not a valid Vertex AI region (e.g. us-east5 or global)", a.VertexRegion)
+	}
+
+	bedrockSet := a.CredentialsSource != "" || a.Profile != "" || a.Region != "" || a.CrossRegion != ""
+	apiSet := a.BaseURL != "" || a.APIKeyEnv != ""
+	vertexSet := a.GCPProject != "" || a.VertexRegion != ""
+	if a.ProfileType != "api" && a.ProfileType != "" && apiSet {
+		return fmt.Errorf("base-url and api-key-env only apply to profile-type 'api'")
+	}
+	if a.ProfileType != "bedrock" && a.ProfileType != "" && bedrockSet {
+		return fmt.Errorf("credentials-source, profile, region, and cross-region only apply to profile-type 'bedrock'")
+	}
+	if a.ProfileType != "vertex" && a.ProfileType != "" && vertexSet {
+		return fmt.Errorf("gcp-project and vertex-region only apply to profile-type 'vertex'")
+	}
+
+	return nil
+}
+
+// state converts the answers into wizard state so answered steps are skipped
+func (a *WizardAnswers) state(targetProfile string) *wizardState {
+	return &wizardState{
+		TargetProfile:     targetProfile,
+		ProfileType:       a.ProfileType,
+		CredentialsSource: a.CredentialsSource,
+		AWSProfile:        a.Profile,
+		Region:            a.Region,
+		CrossRegion:       a.CrossRegion,
+		BaseURL:           a.BaseURL,
+		GCPProject:        a.GCPProject,
+		VertexRegion:      a.VertexRegion,
+		Model:             a.Model,
+		FastModel:         a.FastModel,
+		HeavyModel:        a.HeavyModel,
+		source:            "from answers file",
+	}
+}
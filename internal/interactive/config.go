@@ -1,6 +1,7 @@
 package interactive
 
 import (
+	"errors"
 	"fmt"
 	"os"
 	"strings"
@@ -9,6 +10,8 @@ import (
 	"github.com/OlaHulleberg/clauderock/internal/aws"
 	"github.com/OlaHulleberg/clauderock/internal/awsutil"
 	"github.com/OlaHulleberg/clauderock/internal/config"
+	"github.com/OlaHulleberg/clauderock/internal/heuristics"
+	"github.com/OlaHulleberg/clauderock/internal/i18n"
 	"github.com/OlaHulleberg/clauderock/internal/keyring"
 	"golang.org/x/text/cases"
 	"golang.org/x/text/language"
@@ -41,7 +44,8 @@ func formatModelDisplay(model string, showProvider bool) string {
 }
 
 // buildModelOptions creates SelectOptions with headers for recommended and provider sections
-func buildModelOptions(models []string, context string) []SelectOption {
+// Project hints, when present, replace the default recommendation for their slot
+func buildModelOptions(models []string, context string, hints heuristics.Suggestions) []SelectOption {
 	var options []SelectOption
 
 	// Add "Recommended" section
@@ -53,15 +57,25 @@ func buildModelOptions(models []string, context string) []SelectOption {
 		}
 	}
 
+	header := recommendedSectionHeader
+	isKnownGood := func(m string) bool {
+		return aws.IsRecommendedModel(m, "main") || aws.IsRecommendedModel(m, "fast") || aws.IsRecommendedModel(m, "heavy")
+	}
+	if suggested, hint, ok := suggestedModel(models, context, hints, isKnownGood); ok {
+		recommendedModel = suggested
+		header = recommendedHeaderWithRationale(hint)
+	}
+
 	if recommendedModel != "" {
 		options = append(options, SelectOption{
 			ID:       "",
-			Display:  recommendedSectionHeader,
+			Display:  header,
 			IsHeader: true,
 		})
 		options = append(options, SelectOption{
-			ID:      recommendedModel,
-			Display: formatModelDisplay(recommendedModel, true), // Show provider for recommended
+			ID:          recommendedModel,
+			Display:     formatModelDisplay(recommendedModel, true), // Show provider for recommended
+			Recommended: true,
 		})
 		options = append(options, SelectOption{
 			ID:       "",
@@ -113,8 +127,22 @@ func buildModelOptions(models []string, context string) []SelectOption {
 	return options
 }
 
+// WizardOptions controls optional behaviour of the interactive configuration wizard
+type WizardOptions struct {
+	AcceptRecommended bool           // Skip the model screens and use the recommended models
+	Answers           *WizardAnswers // Pre-supplied answers; only missing ones are prompted for
+	InspectProject    bool           // Inspect the working directory and tailor the recommended models to it
+
+	hints heuristics.Suggestions
+}
+
 // RunInteractiveConfig runs an interactive configuration wizard
 func RunInteractiveConfig(currentVersion string, mgr interface{}) error {
+	return RunInteractiveConfigWithOptions(currentVersion, mgr, WizardOptions{})
+}
+
+// RunInteractiveConfigWithOptions runs the interactive configuration wizard with the given options
+func RunInteractiveConfigWithOptions(currentVersion string, mgr interface{}, opts WizardOptions) error {
 	// Type assert the manager (we'll accept any interface to avoid circular dependencies)
 	type ConfigManager interface {
 		GetCurrentConfig(version string) (*config.Config, error)
@@ -138,149 +166,234 @@ func RunInteractiveConfig(currentVersion string, mgr interface{}) error {
 		return fmt.Errorf("failed to get current profile: %w", err)
 	}
 
-	// Step 0: Profile Type Selection
-	profileTypeOptions := []SelectOption{
-		{ID: "bedrock", Display: "AWS Bedrock (Cross-region inference)"},
-		{ID: "api", Display: "API Key (Direct API access)"},
+	// Start from the answers file, or offer to continue an unfinished setup for this profile
+	var state *wizardState
+	if opts.Answers != nil {
+		state = opts.Answers.state(currentProfile)
+	} else {
+		state, err = offerResume(currentProfile)
+		if err != nil {
+			return err
+		}
 	}
 
-	selectedProfileType, err := InteractiveSelect(
-		"Select Profile Type",
-		"Choose authentication method...",
-		profileTypeOptions,
-		cfg.ProfileType,
-	)
-	if err != nil {
-		return fmt.Errorf("profile type selection failed: %w", err)
+	if opts.InspectProject {
+		opts.hints = inspectProjectHints()
+	}
+
+	if err := runWizard(cfg, manager, currentProfile, currentVersion, opts, state); err != nil {
+		// Keep the answers collected so far so the next run can pick up from here
+		if opts.Answers == nil && state.hasAnswers() {
+			if saveErr := saveWizardState(state); saveErr == nil {
+				fmt.Println("\n" + i18n.T("Progress saved. Run the setup again to resume where you left off."))
+			}
+		}
+		return err
 	}
 
-	cfg.ProfileType = selectedProfileType
+	clearWizardState()
+	return nil
+}
+
+// runWizard selects the profile type and runs the matching configuration flow
+func runWizard(cfg *config.Config, manager interface {
+	Save(name string, cfg *config.Config) error
+}, currentProfile, currentVersion string, opts WizardOptions, state *wizardState) error {
+	// Step 0: Profile Type Selection
+	if !state.answered("Profile Type", state.ProfileType) {
+		profileTypeOptions := []SelectOption{
+			{ID: "bedrock", Display: "AWS Bedrock (Cross-region inference)"},
+			{ID: "api", Display: "API Key (Direct API access)"},
+			{ID: "vertex", Display: "Google Vertex AI (Model Garden)"},
+		}
+
+		selectedProfileType, err := InteractiveSelect(
+			i18n.T("Select Profile Type"),
+			i18n.T("Choose authentication method..."),
+			profileTypeOptions,
+			cfg.ProfileType,
+		)
+		if err != nil {
+			return fmt.Errorf("profile type selection failed: %w", err)
+		}
+		state.ProfileType = selectedProfileType
+	}
+
+	cfg.ProfileType = state.ProfileType
 
 	// Branch based on profile type
-	if selectedProfileType == "bedrock" {
-		return runBedrockConfig(cfg, manager, currentProfile, currentVersion)
-	} else if selectedProfileType == "api" {
-		return runAPIConfig(cfg, manager, currentProfile, currentVersion)
+	if state.ProfileType == "bedrock" {
+		return runBedrockConfig(cfg, manager, currentProfile, currentVersion, opts, state)
+	} else if state.ProfileType == "api" {
+		return runAPIConfig(cfg, manager, currentProfile, currentVersion, opts, state)
+	} else if state.ProfileType == "vertex" {
+		return runVertexConfig(cfg, manager, currentProfile, currentVersion, opts, state)
 	}
 
-	return fmt.Errorf("unsupported profile type: %s", selectedProfileType)
+	return fmt.Errorf("unsupported profile type: %s", state.ProfileType)
 }
 
 // runBedrockConfig handles the Bedrock configuration flow
 func runBedrockConfig(cfg *config.Config, manager interface {
 	Save(name string, cfg *config.Config) error
-}, currentProfile, currentVersion string) error {
+}, currentProfile, currentVersion string, opts WizardOptions, state *wizardState) error {
 	// Variables to hold user selections
 	var (
 		selectedProfile     string
 		selectedRegion      string
 		selectedCrossRegion string
-		selectedModel       string
-		selectedFastModel   string
 	)
 
 	// Initialize with current values
 	selectedProfile = cfg.Profile
 	selectedRegion = cfg.Region
 	selectedCrossRegion = cfg.CrossRegion
-	selectedModel = cfg.Model
-	selectedFastModel = cfg.FastModel
 
-	// Step 1: Profile selection
-	profiles, err := awsutil.GetProfiles()
-	if err != nil {
-		return fmt.Errorf("failed to get AWS profiles: %w", err)
+	if state.CredentialsSource != "" {
+		cfg.CredentialsSource = state.CredentialsSource
 	}
 
-	profileOptions := make([]SelectOption, len(profiles))
-	for i, p := range profiles {
-		profileOptions[i] = SelectOption{ID: p, Display: p}
-	}
+	// Step 1: Profile selection, unless credentials come from the environment or the default chain
+	if !cfg.UsesSharedProfile() {
+		selectedProfile = ""
+		fmt.Printf("AWS credentials: %s (credentials-source %s)\n", cfg.CredentialsLabel(), cfg.CredentialsSource)
+	} else if state.answered("AWS Profile", state.AWSProfile) {
+		selectedProfile = state.AWSProfile
+	} else {
+		profiles, err := awsutil.GetProfileDetails()
+		if errors.Is(err, awsutil.ErrNoProfiles) {
+			// First-time AWS user: offer to create a profile inline
+			created, createErr := CreateAWSProfile()
+			if createErr != nil {
+				return fmt.Errorf("failed to get AWS profiles: %w", createErr)
+			}
+			profiles, err = awsutil.GetProfileDetails()
+			if err != nil {
+				return fmt.Errorf("failed to get AWS profiles: %w", err)
+			}
+			selectedProfile = created
+		} else if err != nil {
+			return fmt.Errorf("failed to get AWS profiles: %w", err)
+		}
 
-	selectedProfile, err = InteractiveSelect(
-		"Select AWS Profile",
-		"Type to filter profiles...",
-		profileOptions,
-		selectedProfile,
-	)
-	if err != nil {
-		return fmt.Errorf("profile selection failed: %w", err)
+		profileOptions := make([]SelectOption, len(profiles))
+		for i, p := range profiles {
+			display := p.Name
+			if summary := p.Summary(); summary != "" {
+				display = fmt.Sprintf("%s  (%s)", p.Name, summary)
+			}
+			profileOptions[i] = SelectOption{ID: p.Name, Display: display}
+		}
+
+		selectedProfile, err = InteractiveSelect(
+			i18n.T("Select AWS Profile"),
+			i18n.T("Type to filter profiles..."),
+			profileOptions,
+			selectedProfile,
+		)
+		if err != nil {
+			return fmt.Errorf("profile selection failed: %w", err)
+		}
+		state.AWSProfile = selectedProfile
 	}
 
 	// Step 2: Region selection
-	selectedRegion, err = SelectRegionWithSearch(selectedRegion)
-	if err != nil {
-		return fmt.Errorf("region selection failed: %w", err)
+	if state.answered("Region", state.Region) {
+		selectedRegion = state.Region
+	} else {
+		var err error
+		selectedRegion, err = SelectRegionWithSearch(selectedProfile, selectedRegion)
+		if err != nil {
+			return fmt.Errorf("region selection failed: %w", err)
+		}
+		state.Region = selectedRegion
 	}
 
 	// Step 3: Cross-region selection
-	crossRegionOptions := []SelectOption{
-		{ID: "global", Display: "Global"},
-		{ID: "us", Display: "US"},
-		{ID: "eu", Display: "EU"},
-	}
-
-	selectedCrossRegion, err = InteractiveSelect(
-		"Select Cross Region",
-		"Type to filter...",
-		crossRegionOptions,
-		selectedCrossRegion,
-	)
-	if err != nil {
-		return fmt.Errorf("cross-region selection failed: %w", err)
-	}
-
-	// Step 4: Fetch available models
-	fmt.Println("\nFetching available models...")
-	models, err := aws.GetAvailableModels(selectedProfile, selectedRegion, selectedCrossRegion)
-	if err != nil {
-		return fmt.Errorf("failed to fetch models: %w", err)
-	}
-
-	if len(models) == 0 {
-		return fmt.Errorf("no models available for the selected configuration")
-	}
-
-	// Step 5: Main model selection
-	// Build model options with headers for main context
-	mainModelOptions := buildModelOptions(models, "main")
+	if state.answered("Cross Region", state.CrossRegion) {
+		selectedCrossRegion = state.CrossRegion
+	} else {
+		crossRegionOptions := []SelectOption{
+			{ID: "global", Display: "Global"},
+			{ID: "us", Display: "US"},
+			{ID: "eu", Display: "EU"},
+		}
 
-	selectedModel, err = InteractiveSelect(
-		"Select Main Model",
-		"Type to filter models...",
-		mainModelOptions,
-		selectedModel,
-	)
-	if err != nil {
-		return fmt.Errorf("main model selection failed: %w", err)
+		var err error
+		selectedCrossRegion, err = InteractiveSelect(
+			i18n.T("Select Cross Region"),
+			i18n.T("Type to filter..."),
+			crossRegionOptions,
+			selectedCrossRegion,
+		)
+		if err != nil {
+			return fmt.Errorf("cross-region selection failed: %w", err)
+		}
+		state.CrossRegion = selectedCrossRegion
 	}
 
-	// Step 6: Fast model selection
-	// Build model options with headers for fast context
-	fastModelOptions := buildModelOptions(models, "fast")
+	selectedModel, selectedFastModel, selectedHeavyModel := state.Model, state.FastModel, state.HeavyModel
+	offline := false
+	if selectedModel == "" || selectedFastModel == "" || selectedHeavyModel == "" {
+		// Step 4: Fetch available models
+		fmt.Println("\n" + i18n.T("Fetching available models..."))
+		var models []string
+		var err error
+		models, offline, err = fetchBedrockModels(selectedProfile, selectedRegion, selectedCrossRegion)
+		if err != nil {
+			return err
+		}
 
-	selectedFastModel, err = InteractiveSelect(
-		"Select Fast Model",
-		"Type to filter models...",
-		fastModelOptions,
-		selectedFastModel,
-	)
-	if err != nil {
-		return fmt.Errorf("fast model selection failed: %w", err)
-	}
+		// Step 5: Main model selection
+		if !state.answered("Main Model", selectedModel) {
+			// Build model options with headers for main context
+			mainModelOptions := markUnverified(buildModelOptions(models, "main", opts.hints), offline)
+
+			selectedModel, err = selectModel(
+				i18n.T("Select Main Model"),
+				mainModelOptions,
+				cfg.Model,
+				opts.AcceptRecommended,
+			)
+			if err != nil {
+				return fmt.Errorf("main model selection failed: %w", err)
+			}
+			state.Model = selectedModel
+		}
 
-	// Step 7: Heavy model selection
-	// Build model options with headers for heavy context
-	heavyModelOptions := buildModelOptions(models, "heavy")
+		// Step 6: Fast model selection
+		if !state.answered("Fast Model", selectedFastModel) {
+			// Build model options with headers for fast context
+			fastModelOptions := markUnverified(buildModelOptions(models, "fast", opts.hints), offline)
+
+			selectedFastModel, err = selectModel(
+				i18n.T("Select Fast Model"),
+				fastModelOptions,
+				cfg.FastModel,
+				opts.AcceptRecommended,
+			)
+			if err != nil {
+				return fmt.Errorf("fast model selection failed: %w", err)
+			}
+			state.FastModel = selectedFastModel
+		}
 
-	selectedHeavyModel, err := InteractiveSelect(
-		"Select Heavy Model",
-		"Type to filter models...",
-		heavyModelOptions,
-		"",
-	)
-	if err != nil {
-		return fmt.Errorf("heavy model selection failed: %w", err)
+		// Step 7: Heavy model selection
+		if !state.answered("Heavy Model", selectedHeavyModel) {
+			// Build model options with headers for heavy context
+			heavyModelOptions := markUnverified(buildModelOptions(models, "heavy", opts.hints), offline)
+
+			selectedHeavyModel, err = selectModel(
+				i18n.T("Select Heavy Model"),
+				heavyModelOptions,
+				"",
+				opts.AcceptRecommended,
+			)
+			if err != nil {
+				return fmt.Errorf("heavy model selection failed: %w", err)
+			}
+			state.HeavyModel = selectedHeavyModel
+		}
 	}
 
 	// Update configuration with selections
@@ -288,25 +401,30 @@ func runBedrockConfig(cfg *config.Config, manager interface {
 	cfg.Region = selectedRegion
 	cfg.CrossRegion = selectedCrossRegion
 
-	// Resolve friendly model names to full profile IDs
-	fmt.Println("\nResolving model profile IDs...")
-	mainModelID, err := aws.ResolveModelToProfileID(selectedProfile, selectedRegion, selectedCrossRegion, selectedModel)
+	// Resolve friendly model names to full profile IDs (honouring pinned snapshots)
+	fmt.Println("\n" + i18n.T("Resolving model profile IDs..."))
+	resolve := resolveSlotModel
+	if offline {
+		resolve = resolveOfflineSlotModel
+	}
+	mainModelID, err := resolve(cfg, "main", selectedModel)
 	if err != nil {
 		return fmt.Errorf("failed to resolve main model: %w", err)
 	}
 	cfg.Model = mainModelID
 
-	fastModelID, err := aws.ResolveModelToProfileID(selectedProfile, selectedRegion, selectedCrossRegion, selectedFastModel)
+	fastModelID, err := resolve(cfg, "fast", selectedFastModel)
 	if err != nil {
 		return fmt.Errorf("failed to resolve fast model: %w", err)
 	}
 	cfg.FastModel = fastModelID
 
-	heavyModelID, err := aws.ResolveModelToProfileID(selectedProfile, selectedRegion, selectedCrossRegion, selectedHeavyModel)
+	heavyModelID, err := resolve(cfg, "heavy", selectedHeavyModel)
 	if err != nil {
 		return fmt.Errorf("failed to resolve heavy model: %w", err)
 	}
 	cfg.HeavyModel = heavyModelID
+	cfg.Unverified = offline
 
 	// Validate configuration
 	if err := cfg.Validate(); err != nil {
@@ -323,46 +441,67 @@ func runBedrockConfig(cfg *config.Config, manager interface {
 		return fmt.Errorf("failed to save config: %w", err)
 	}
 
-	fmt.Printf("\n✓ Configuration saved successfully to profile '%s'!\n", currentProfile)
-	fmt.Printf("\nConfiguration:\n")
-	fmt.Printf("  Profile:      %s\n", cfg.Profile)
+	fmt.Printf("\n✓ %s\n", i18n.T("Configuration saved successfully to profile '%s'!", currentProfile))
+	fmt.Printf("\n%s\n", i18n.T("Configuration:"))
+	if cfg.UsesSharedProfile() {
+		fmt.Printf("  Profile:      %s\n", cfg.Profile)
+	} else {
+		fmt.Printf("  Credentials:  %s\n", cfg.CredentialsSource)
+	}
 	fmt.Printf("  Region:       %s\n", cfg.Region)
 	fmt.Printf("  Cross Region: %s\n", cfg.CrossRegion)
 	fmt.Printf("  Model:        %s\n", cfg.Model)
 	fmt.Printf("  Fast Model:   %s\n", cfg.FastModel)
 	fmt.Printf("  Heavy Model:  %s\n", cfg.HeavyModel)
+	fmt.Println()
+
+	// Verify the setup end-to-end before the first real session
+	offerSmokeTest(cfg, "", opts)
 
 	return nil
 }
 
+// releaseAPIKey deletes a key stored for a save that failed, through the manager when it can
+// check under its lock that no profile refers to the key
+func releaseAPIKey(manager any, keyID string) {
+	if releaser, ok := manager.(interface{ ReleaseAPIKey(id string) error }); ok {
+		releaser.ReleaseAPIKey(keyID)
+		return
+	}
+	keyring.Delete(keyID)
+}
+
 // runAPIConfig handles the API key configuration flow
 func runAPIConfig(cfg *config.Config, manager interface {
 	Save(name string, cfg *config.Config) error
-}, currentProfile, currentVersion string) error {
+}, currentProfile, currentVersion string, opts WizardOptions, state *wizardState) error {
 	// Step 1: Base URL Input
-	fmt.Println("\nEnter the base URL for your API gateway:")
-	fmt.Println("Examples: api.example.com, https://api.example.com, http://localhost:8080")
-	fmt.Print("> ")
-
-	var baseURL string
-	if _, err := fmt.Scanln(&baseURL); err != nil {
-		return fmt.Errorf("failed to read base URL: %w", err)
-	}
-
-	if baseURL == "" {
-		return fmt.Errorf("base URL cannot be empty")
+	if !state.answered("Base URL", state.BaseURL) {
+		baseURL, err := promptBaseURL()
+		if err != nil {
+			return err
+		}
+		state.BaseURL = baseURL
 	}
 
 	// Normalize the base URL
+	baseURL, err := api.ParseBaseURL(state.BaseURL)
+	if err != nil {
+		return err
+	}
+	warnInsecureBaseURL(baseURL)
 	cfg.BaseURL = baseURL
 
 	// Step 2: API Key Input
-	fmt.Println("\nEnter your API key:")
-	fmt.Println("(This will be stored securely in your system keychain)")
-
-	// Check environment variable first
-	apiKey := os.Getenv("ANTHROPIC_API_KEY")
-	if apiKey != "" {
+	// Use the answers file's key variable, otherwise check ANTHROPIC_API_KEY
+	var apiKey string
+	if opts.Answers != nil && opts.Answers.APIKeyEnv != "" {
+		apiKey = os.Getenv(opts.Answers.APIKeyEnv)
+		if apiKey == "" {
+			return fmt.Errorf("environment variable %s from answers file is not set", opts.Answers.APIKeyEnv)
+		}
+		fmt.Printf("Using API key from %s (from answers file)\n", opts.Answers.APIKeyEnv)
+	} else if apiKey = os.Getenv("ANTHROPIC_API_KEY"); apiKey != "" {
 		fmt.Println("\nFound ANTHROPIC_API_KEY in environment.")
 		useEnvKey, err := Confirm(
 			"API Key Detected",
@@ -380,107 +519,45 @@ func runAPIConfig(cfg *config.Config, manager interface {
 
 	// Prompt for API key if not using environment variable
 	if apiKey == "" {
-		fmt.Print("> ")
-		if _, err := fmt.Scanln(&apiKey); err != nil {
-			return fmt.Errorf("failed to read API key: %w", err)
-		}
-
-		if apiKey == "" {
-			return fmt.Errorf("API key cannot be empty")
-		}
-	}
-
-	// Step 3: Fetch available models
-	fmt.Println("\nFetching available models from API...")
-	models, err := api.FetchAvailableModels(cfg.BaseURL, apiKey)
-
-	var selectedModel, selectedFastModel, selectedHeavyModel string
-
-	// Fall back to manual input if API call fails
-	if err != nil || len(models) == 0 {
-		fmt.Println("Using manual input mode")
-		fmt.Println()
-
-		// Main model input
-		selectedModel, err = PromptTextInput(
-			"Enter Main Model ID",
-			"",
-			"claude-sonnet-4-5",
-		)
-		if err != nil {
-			return fmt.Errorf("main model input failed: %w", err)
-		}
-		if selectedModel == "" {
-			return fmt.Errorf("main model ID cannot be empty")
-		}
-
-		// Fast model input
-		selectedFastModel, err = PromptTextInput(
-			"Enter Fast Model ID",
-			"",
-			"claude-haiku-4-5",
-		)
-		if err != nil {
-			return fmt.Errorf("fast model input failed: %w", err)
-		}
-		if selectedFastModel == "" {
-			return fmt.Errorf("fast model ID cannot be empty")
-		}
-
-		// Heavy model input
-		selectedHeavyModel, err = PromptTextInput(
-			"Enter Heavy Model ID",
-			"",
-			"claude-opus-4",
+		key, err := PromptSecret(
+			i18n.T("Enter your API key"),
+			i18n.T("It will be stored in clauderock's keyring file, readable only by you"),
 		)
 		if err != nil {
-			return fmt.Errorf("heavy model input failed: %w", err)
-		}
-		if selectedHeavyModel == "" {
-			return fmt.Errorf("heavy model ID cannot be empty")
+			return fmt.Errorf("failed to read API key: %w", err)
 		}
-	} else {
-		// Extract model IDs for selection
-		modelIDs := make([]string, len(models))
-		for i, m := range models {
-			modelIDs[i] = m.ID
-		}
-
-		// Step 4: Main model selection
-		mainModelOptions := buildAPIModelOptions(models, "main")
-		selectedModel, err = InteractiveSelect(
-			"Select Main Model",
-			"Type to filter models...",
-			mainModelOptions,
-			"",
-		)
-		if err != nil {
-			return fmt.Errorf("main model selection failed: %w", err)
+		if key == "" {
+			return fmt.Errorf("API key cannot be empty")
 		}
-
-		// Step 5: Fast model selection
-		fastModelOptions := buildAPIModelOptions(models, "fast")
-		selectedFastModel, err = InteractiveSelect(
-			"Select Fast Model",
-			"Type to filter models...",
-			fastModelOptions,
-			"",
-		)
-		if err != nil {
-			return fmt.Errorf("fast model selection failed: %w", err)
+		apiKey = key
+	}
+
+	// Each model answered up front is kept; only the missing ones are asked for
+	selectedModel, selectedFastModel, selectedHeavyModel := state.Model, state.FastModel, state.HeavyModel
+	mainAnswered := state.answered("Main Model", selectedModel)
+	fastAnswered := state.answered("Fast Model", selectedFastModel)
+	heavyAnswered := state.answered("Heavy Model", selectedHeavyModel)
+	if !mainAnswered || !fastAnswered || !heavyAnswered {
+		// Step 3: Fetch available models
+		fmt.Println("\nFetching available models from API...")
+		models, err := api.FetchAvailableModels(cfg.BaseURL, apiKey)
+
+		// Fall back to manual input if API call fails
+		if err != nil || len(models) == 0 {
+			fmt.Println("Using manual input mode")
+			fmt.Println()
+
+			err = promptModelIDs(&selectedModel, &selectedFastModel, &selectedHeavyModel,
+				"claude-sonnet-4-5", "claude-haiku-4-5", "claude-opus-4")
+		} else {
+			// Steps 4-6: Main, fast, and heavy model selection
+			err = selectMissingModels(models, opts.hints, opts.AcceptRecommended, nil,
+				&selectedModel, &selectedFastModel, &selectedHeavyModel)
 		}
-
-		// Step 6: Heavy model selection
-		heavyModelOptions := buildAPIModelOptions(models, "heavy")
-		selectedHeavyModel, err = InteractiveSelect(
-			"Select Heavy Model",
-			"Type to filter models...",
-			heavyModelOptions,
-			"",
-		)
 		if err != nil {
-			return fmt.Errorf("heavy model selection failed: %w", err)
+			return err
 		}
+		state.Model, state.FastModel, state.HeavyModel = selectedModel, selectedFastModel, selectedHeavyModel
 	}
 
 	// Generate keyring ID and store API key
@@ -501,13 +578,15 @@ func runAPIConfig(cfg *config.Config, manager interface {
 
 	// Clear Bedrock-specific fields
 	cfg.Profile = ""
+	cfg.CredentialsSource = ""
 	cfg.Region = ""
 	cfg.CrossRegion = ""
+	cfg.PinnedVersions = nil
 
 	// Validate configuration
 	if err := cfg.Validate(); err != nil {
 		// Clean up keyring entry if validation fails
-		keyring.Delete(keyID)
+		releaseAPIKey(manager, keyID)
 		return fmt.Errorf("invalid configuration: %w", err)
 	}
 
@@ -518,24 +597,29 @@ func runAPIConfig(cfg *config.Config, manager interface {
 
 	// Save configuration to current profile
 	if err := manager.Save(currentProfile, cfg); err != nil {
-		// Clean up keyring entry if save fails
-		keyring.Delete(keyID)
+		// Clean up keyring entry if save fails, unless the profile was written after all
+		releaseAPIKey(manager, keyID)
 		return fmt.Errorf("failed to save config: %w", err)
 	}
 
-	fmt.Printf("\n✓ Configuration saved successfully to profile '%s'!\n", currentProfile)
-	fmt.Printf("\nConfiguration:\n")
+	fmt.Printf("\n✓ %s\n", i18n.T("Configuration saved successfully to profile '%s'!", currentProfile))
+	fmt.Printf("\n%s\n", i18n.T("Configuration:"))
 	fmt.Printf("  Profile Type: %s\n", cfg.ProfileType)
 	fmt.Printf("  Base URL:     %s\n", cfg.BaseURL)
 	fmt.Printf("  Model:        %s\n", cfg.Model)
 	fmt.Printf("  Fast Model:   %s\n", cfg.FastModel)
 	fmt.Printf("  Heavy Model:  %s\n", cfg.HeavyModel)
+	fmt.Println()
+
+	// Verify the setup end-to-end before the first real session
+	offerSmokeTest(cfg, apiKey, opts)
 
 	return nil
 }
 
 // buildAPIModelOptions creates SelectOptions for API models
-func buildAPIModelOptions(models []api.ModelInfo, context string) []SelectOption {
+// Project hints, when present, replace the default recommendation for their slot
+func buildAPIModelOptions(models []api.ModelInfo, context string, hints heuristics.Suggestions) []SelectOption {
 	var options []SelectOption
 
 	// Add "Recommended" section
@@ -547,15 +631,39 @@ func buildAPIModelOptions(models []api.ModelInfo, context string) []SelectOption
 		}
 	}
 
+	header := recommendedSectionHeader
+	ids := make([]string, len(models))
+	for i, m := range models {
+		ids[i] = m.ID
+	}
+	isKnownGood := func(id string) bool {
+		for _, m := range models {
+			if m.ID == id {
+				return len(m.Recommended) > 0
+			}
+		}
+		return false
+	}
+	if suggested, hint, ok := suggestedModel(ids, context, hints, isKnownGood); ok {
+		for i := range models {
+			if models[i].ID == suggested {
+				recommendedModel = &models[i]
+				break
+			}
+		}
+		header = recommendedHeaderWithRationale(hint)
+	}
+
 	if recommendedModel != nil {
 		options = append(options, SelectOption{
 			ID:       "",
-			Display:  recommendedSectionHeader,
+			Display:  header,
 			IsHeader: true,
 		})
 		options = append(options, SelectOption{
-			ID:      recommendedModel.ID,
-			Display: fmt.Sprintf("  ⭐ %s", recommendedModel.Name),
+			ID:          recommendedModel.ID,
+			Display:     fmt.Sprintf("  ⭐ %s", recommendedModel.Name),
+			Recommended: true,
 		})
 		options = append(options, SelectOption{
 			ID:       "",
@@ -584,3 +692,148 @@ func buildAPIModelOptions(models []api.ModelInfo, context string) []SelectOption
 
 	return options
 }
+
+// suggestedModel picks the model matching the project hint for a slot
+// Among models of the suggested family, known-good ones win, then the highest version
+func suggestedModel(models []string, slot string, hints heuristics.Suggestions, isKnownGood func(string) bool) (string, heuristics.Suggestion, bool) {
+	hint, ok := hints[slot]
+	if !ok {
+		return "", heuristics.Suggestion{}, false
+	}
+
+	family := "claude-" + hint.Family
+	var best string
+	for _, m := range models {
+		if !strings.Contains(strings.ToLower(m), family) {
+			continue
+		}
+		switch {
+		case best == "":
+			best = m
+		case isKnownGood(m) != isKnownGood(best):
+			if isKnownGood(m) {
+				best = m
+			}
+		case m > best:
+			best = m
+		}
+	}
+	if best == "" {
+		return "", heuristics.Suggestion{}, false
+	}
+	return best, hint, true
+}
+
+// recommendedHeaderWithRationale labels the recommended section with why the project hint applies
+func recommendedHeaderWithRationale(hint heuristics.Suggestion) string {
+	return fmt.Sprintf("%s (for this project: %s)", recommendedSectionHeader, hint.Rationale)
+}
+
+// inspectProjectHints inspects the working directory and prints the resulting model hints
+func inspectProjectHints() heuristics.Suggestions {
+	dir, err := os.Getwd()
+	if err != nil {
+		fmt.Printf("Warning: could not inspect project: %v\n", err)
+		return nil
+	}
+
+	fmt.Println("\nInspecting project...")
+	project, err := heuristics.Inspect(dir)
+	if err != nil {
+		fmt.Printf("Warning: could not inspect project: %v\n", err)
+		return nil
+	}
+	fmt.Printf("  %s\n", project.Summary())
+
+	hints := heuristics.Suggest(project)
+	if len(hints) == 0 {
+		fmt.Println("  No project-specific model suggestions; using the defaults")
+		return nil
+	}
+	for _, slot := range []string{"main", "fast", "heavy"} {
+		if hint, ok := hints[slot]; ok {
+			fmt.Printf("  %s model: %s — %s\n", slot, hint.Family, hint.Rationale)
+		}
+	}
+	return hints
+}
+
+// promptModelIDs asks for the model IDs that aren't set yet when they can't be listed
+func promptModelIDs(mainModel, fastModel, heavyModel *string, mainExample, fastExample, heavyExample string) error {
+	for _, p := range []struct {
+		slot, title, example string
+		value                *string
+	}{
+		{"main", "Enter Main Model ID", mainExample, mainModel},
+		{"fast", "Enter Fast Model ID", fastExample, fastModel},
+		{"heavy", "Enter Heavy Model ID", heavyExample, heavyModel},
+	} {
+		if *p.value != "" {
+			continue
+		}
+		value, err := PromptTextInput(p.title, "", p.example)
+		if err != nil {
+			return fmt.Errorf("%s model input failed: %w", p.slot, err)
+		}
+		if value == "" {
+			return fmt.Errorf("%s model ID cannot be empty", p.slot)
+		}
+		*p.value = value
+	}
+	return nil
+}
+
+// selectMissingModels picks each model that isn't set yet from a listing, preselecting
+// current's model for the slot when current is set
+func selectMissingModels(models []api.ModelInfo, hints heuristics.Suggestions, acceptRecommended bool, current *config.Config, mainModel, fastModel, heavyModel *string) error {
+	var currentMain, currentFast, currentHeavy string
+	if current != nil {
+		currentMain, currentFast, currentHeavy = current.Model, current.FastModel, current.HeavyModel
+	}
+	for _, slot := range []struct {
+		name, title, current string
+		value                *string
+	}{
+		{"main", i18n.T("Select Main Model"), currentMain, mainModel},
+		{"fast", i18n.T("Select Fast Model"), currentFast, fastModel},
+		{"heavy", i18n.T("Select Heavy Model"), currentHeavy, heavyModel},
+	} {
+		if *slot.value != "" {
+			continue
+		}
+		value, err := selectModel(slot.title, buildAPIModelOptions(models, slot.name, hints), slot.current, acceptRecommended)
+		if err != nil {
+			return fmt.Errorf("%s model selection failed: %w", slot.name, err)
+		}
+		*slot.value = value
+	}
+	return nil
+}
+
+// promptBaseURL asks for the API gateway base URL until a valid one is entered
+func promptBaseURL() (string, error) {
+	for {
+		input, err := PromptTextInput(
+			"Enter the base URL for your API gateway",
+			"https://api.example.com",
+			"api.example.com, https://api.example.com, http://localhost:8080",
+		)
+		if err != nil {
+			return "", fmt.Errorf("failed to read base URL: %w", err)
+		}
+
+		baseURL, err := api.ParseBaseURL(input)
+		if err != nil {
+			fmt.Printf("✗ %v\n", err)
+			continue
+		}
+		return baseURL, nil
+	}
+}
+
+// warnInsecureBaseURL warns when the API key would be sent unencrypted to a remote host
+func warnInsecureBaseURL(baseURL string) {
+	if api.IsInsecureRemoteURL(baseURL) {
+		fmt.Printf("⚠ Warning: %s uses http:// for a non-local host; your API key will be sent unencrypted\n", baseURL)
+	}
+}
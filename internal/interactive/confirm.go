@@ -3,6 +3,7 @@ package interactive
 import (
 	"strings"
 
+	"github.com/OlaHulleberg/clauderock/internal/accessibility"
 	"github.com/charmbracelet/bubbles/textinput"
 	tea "github.com/charmbracelet/bubbletea"
 	"github.com/charmbracelet/lipgloss"
@@ -27,6 +28,10 @@ type confirmModel struct {
 
 // Confirm shows a confirmation dialog that requires typing "yes" to confirm
 func Confirm(title string, message string, details []string) (bool, error) {
+	if accessibility.Enabled() {
+		return confirmPlain(title, message, details)
+	}
+
 	ti := textinput.New()
 	ti.Placeholder = "yes/no"
 	ti.Focus()
@@ -0,0 +1,145 @@
+package interactive
+
+import (
+	"fmt"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/OlaHulleberg/clauderock/internal/accessibility"
+	"github.com/OlaHulleberg/clauderock/internal/currency"
+	"github.com/OlaHulleberg/clauderock/internal/monitoring"
+	"github.com/OlaHulleberg/clauderock/internal/usage"
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// throttleRiskFraction is the share of the TPM limit above which throttling is likely
+const throttleRiskFraction = 0.8
+
+// DashboardOptions configures the live session dashboard
+type DashboardOptions struct {
+	Refresh  time.Duration
+	TPMLimit float64 // Warn when rolling TPM approaches this; 0 disables the check
+}
+
+type dashboardTickMsg time.Time
+
+// dashboardModel is the Bubbletea model for the live session dashboard
+type dashboardModel struct {
+	session *monitoring.LiveSession
+	opts    DashboardOptions
+	metrics monitoring.LiveMetrics
+	now     time.Time
+	err     error
+}
+
+// RunDashboard shows live metrics for a session until the user quits
+func RunDashboard(session *monitoring.LiveSession, opts DashboardOptions) error {
+	if accessibility.Enabled() {
+		return runDashboardPlain(session, opts)
+	}
+
+	m := dashboardModel{session: session, opts: opts}
+	m.refresh(time.Now())
+
+	_, err := tea.NewProgram(m, tea.WithAltScreen()).Run()
+	return err
+}
+
+func (m *dashboardModel) refresh(now time.Time) {
+	m.now = now
+	m.err = m.session.Poll()
+	m.metrics = m.session.Metrics(now)
+}
+
+func (m dashboardModel) tick() tea.Cmd {
+	return tea.Tick(m.opts.Refresh, func(t time.Time) tea.Msg { return dashboardTickMsg(t) })
+}
+
+func (m dashboardModel) Init() tea.Cmd {
+	return m.tick()
+}
+
+func (m dashboardModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	switch msg := msg.(type) {
+	case tea.KeyMsg:
+		switch msg.String() {
+		case "q", "esc", "ctrl+c":
+			return m, tea.Quit
+		}
+	case dashboardTickMsg:
+		m.refresh(time.Time(msg))
+		return m, m.tick()
+	}
+	return m, nil
+}
+
+func (m dashboardModel) View() string {
+	var b strings.Builder
+
+	b.WriteString(titleStyle.Render("Live Session") + " " + helpStyle.Render(filepath.Base(m.session.Path)) + "\n\n")
+
+	row := func(label, value string) {
+		b.WriteString(fmt.Sprintf("  %-18s %s\n", label, selectedStyle.Render(value)))
+	}
+	row("Requests:", fmt.Sprintf("%d", m.metrics.Requests))
+	row("Tokens:", fmt.Sprintf("%d in / %d out", m.metrics.InputTokens, m.metrics.OutputTokens))
+	row("Rolling TPM (1m):", fmt.Sprintf("%.0f", m.metrics.RollingTPM))
+	row("Rolling RPM (1m):", fmt.Sprintf("%.0f", m.metrics.RollingRPM))
+	row("Cache hit rate:", fmt.Sprintf("%.1f%%", m.metrics.CacheHitRate))
+	row("Estimated cost:", formatLiveCost(m.metrics))
+	if !m.metrics.LastCall.IsZero() {
+		row("Last request:", fmt.Sprintf("%s ago", m.now.Sub(m.metrics.LastCall).Round(time.Second)))
+	}
+
+	if warnings := throttleWarnings(m.metrics, m.opts.TPMLimit); len(warnings) > 0 {
+		b.WriteString("\n")
+		for _, w := range warnings {
+			b.WriteString("  " + warningStyle.Render("⚠ "+w) + "\n")
+		}
+	}
+	if m.err != nil {
+		b.WriteString("\n  " + warningStyle.Render(m.err.Error()) + "\n")
+	}
+
+	b.WriteString("\n" + helpStyle.Render("q: quit"))
+	return b.String()
+}
+
+// runDashboardPlain prints a summary line on every refresh instead of redrawing the screen
+func runDashboardPlain(session *monitoring.LiveSession, opts DashboardOptions) error {
+	fmt.Printf("Following %s (Ctrl+C to stop)\n", session.Path)
+	for {
+		if err := session.Poll(); err != nil {
+			return err
+		}
+		m := session.Metrics(time.Now())
+		fmt.Printf("Requests %d, TPM %.0f, RPM %.0f, cache hit rate %.1f%%, estimated cost %s\n",
+			m.Requests, m.RollingTPM, m.RollingRPM, m.CacheHitRate, formatLiveCost(m))
+		for _, w := range throttleWarnings(m, opts.TPMLimit) {
+			fmt.Println("Warning: " + w)
+		}
+		time.Sleep(opts.Refresh)
+	}
+}
+
+// formatLiveCost renders usage.LiveCost, marking approximate estimates
+func formatLiveCost(m monitoring.LiveMetrics) string {
+	cost, approximate := usage.LiveCost(m)
+	if approximate {
+		return fmt.Sprintf("≈ %s (approximate)", currency.FormatPrecise(cost, 4))
+	}
+	return currency.FormatPrecise(cost, 4)
+}
+
+// throttleWarnings explains why the session is at risk of being throttled
+func throttleWarnings(m monitoring.LiveMetrics, tpmLimit float64) []string {
+	var warnings []string
+	if m.RecentThrottles > 0 {
+		warnings = append(warnings, fmt.Sprintf("%d throttled request(s) in the last 5 minutes", m.RecentThrottles))
+	}
+	if tpmLimit > 0 && m.RollingTPM >= tpmLimit*throttleRiskFraction {
+		warnings = append(warnings, fmt.Sprintf("rolling TPM is at %.0f%% of the %.0f TPM limit", m.RollingTPM/tpmLimit*100, tpmLimit))
+	}
+	return warnings
+}
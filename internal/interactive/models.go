@@ -6,21 +6,19 @@ import (
 	"github.com/OlaHulleberg/clauderock/internal/api"
 	"github.com/OlaHulleberg/clauderock/internal/aws"
 	"github.com/OlaHulleberg/clauderock/internal/config"
+	"github.com/OlaHulleberg/clauderock/internal/i18n"
 	"github.com/OlaHulleberg/clauderock/internal/keyring"
 )
 
 // SelectBedrockModels interactively selects models for a Bedrock profile
 // Updates cfg.Model, cfg.FastModel, and cfg.HeavyModel with full profile IDs
-func SelectBedrockModels(cfg *config.Config) error {
+// When acceptRecommended is set, recommended models are chosen without prompting
+func SelectBedrockModels(cfg *config.Config, acceptRecommended bool) error {
 	// Fetch available models using current AWS configuration
-	fmt.Println("\nFetching available models...")
-	models, err := aws.GetAvailableModels(cfg.Profile, cfg.Region, cfg.CrossRegion)
+	fmt.Println("\n" + i18n.T("Fetching available models..."))
+	models, offline, err := fetchBedrockModels(cfg.AWSProfile(), cfg.Region, cfg.CrossRegion)
 	if err != nil {
-		return fmt.Errorf("failed to fetch models: %w", err)
-	}
-
-	if len(models) == 0 {
-		return fmt.Errorf("no models available for the selected configuration")
+		return err
 	}
 
 	// Extract current friendly names for defaults
@@ -29,54 +27,58 @@ func SelectBedrockModels(cfg *config.Config) error {
 	currentHeavy := aws.ExtractFriendlyModelName(cfg.HeavyModel)
 
 	// Main model selection
-	mainModelOptions := buildModelOptions(models, "main")
-	selectedMain, err := InteractiveSelect(
-		"Select Main Model",
-		"Type to filter models...",
+	mainModelOptions := markUnverified(buildModelOptions(models, "main", nil), offline)
+	selectedMain, err := selectModel(
+		i18n.T("Select Main Model"),
 		mainModelOptions,
 		currentMain,
+		acceptRecommended,
 	)
 	if err != nil {
 		return fmt.Errorf("main model selection failed: %w", err)
 	}
 
 	// Fast model selection
-	fastModelOptions := buildModelOptions(models, "fast")
-	selectedFast, err := InteractiveSelect(
-		"Select Fast Model",
-		"Type to filter models...",
+	fastModelOptions := markUnverified(buildModelOptions(models, "fast", nil), offline)
+	selectedFast, err := selectModel(
+		i18n.T("Select Fast Model"),
 		fastModelOptions,
 		currentFast,
+		acceptRecommended,
 	)
 	if err != nil {
 		return fmt.Errorf("fast model selection failed: %w", err)
 	}
 
 	// Heavy model selection
-	heavyModelOptions := buildModelOptions(models, "heavy")
-	selectedHeavy, err := InteractiveSelect(
-		"Select Heavy Model",
-		"Type to filter models...",
+	heavyModelOptions := markUnverified(buildModelOptions(models, "heavy", nil), offline)
+	selectedHeavy, err := selectModel(
+		i18n.T("Select Heavy Model"),
 		heavyModelOptions,
 		currentHeavy,
+		acceptRecommended,
 	)
 	if err != nil {
 		return fmt.Errorf("heavy model selection failed: %w", err)
 	}
 
-	// Resolve friendly model names to full profile IDs
-	fmt.Println("\nResolving model profile IDs...")
-	mainModelID, err := aws.ResolveModelToProfileID(cfg.Profile, cfg.Region, cfg.CrossRegion, selectedMain)
+	// Resolve friendly model names to full profile IDs (honouring pinned snapshots)
+	fmt.Println("\n" + i18n.T("Resolving model profile IDs..."))
+	resolve := resolveSlotModel
+	if offline {
+		resolve = resolveOfflineSlotModel
+	}
+	mainModelID, err := resolve(cfg, "main", selectedMain)
 	if err != nil {
 		return fmt.Errorf("failed to resolve main model: %w", err)
 	}
 
-	fastModelID, err := aws.ResolveModelToProfileID(cfg.Profile, cfg.Region, cfg.CrossRegion, selectedFast)
+	fastModelID, err := resolve(cfg, "fast", selectedFast)
 	if err != nil {
 		return fmt.Errorf("failed to resolve fast model: %w", err)
 	}
 
-	heavyModelID, err := aws.ResolveModelToProfileID(cfg.Profile, cfg.Region, cfg.CrossRegion, selectedHeavy)
+	heavyModelID, err := resolve(cfg, "heavy", selectedHeavy)
 	if err != nil {
 		return fmt.Errorf("failed to resolve heavy model: %w", err)
 	}
@@ -85,13 +87,62 @@ func SelectBedrockModels(cfg *config.Config) error {
 	cfg.Model = mainModelID
 	cfg.FastModel = fastModelID
 	cfg.HeavyModel = heavyModelID
+	cfg.Unverified = offline
 
 	return nil
 }
 
+// fetchBedrockModels lists the models Bedrock offers, falling back to the built-in catalog
+// when Bedrock cannot be queried (no network, missing IAM permissions); offline reports the fallback
+func fetchBedrockModels(awsProfile, region, crossRegion string) (models []string, offline bool, err error) {
+	models, err = aws.GetAvailableModels(awsProfile, region, crossRegion)
+	if err == nil && len(models) > 0 {
+		return models, false, nil
+	}
+
+	models = aws.OfflineModels(crossRegion)
+	if len(models) == 0 {
+		return nil, false, fmt.Errorf("failed to fetch models: %w", err)
+	}
+
+	fmt.Printf("Warning: could not list models from Bedrock: %v\n", err)
+	fmt.Printf("Offering the built-in model catalog (updated %s) instead.\n", aws.OfflineCatalogDate())
+	fmt.Println("These models are unverified until the first successful launch.")
+	return models, true, nil
+}
+
+// markUnverified labels every selectable option as unverified when offline is set
+func markUnverified(options []SelectOption, offline bool) []SelectOption {
+	if !offline {
+		return options
+	}
+	for i := range options {
+		if !options[i].IsHeader {
+			options[i].Display += " (unverified)"
+		}
+	}
+	return options
+}
+
+// resolveOfflineSlotModel resolves a model to the newest snapshot in the built-in catalog
+// Pinned snapshots cannot be looked up offline and are not applied
+func resolveOfflineSlotModel(cfg *config.Config, slot, model string) (string, error) {
+	return aws.ResolveOfflineModel(cfg.CrossRegion, model)
+}
+
+// resolveSlotModel resolves a model for a slot and reports when a pinned snapshot had to be dropped
+func resolveSlotModel(cfg *config.Config, slot, model string) (string, error) {
+	profileID, unpinned, err := aws.ResolveSlotModel(cfg, slot, model)
+	if unpinned {
+		fmt.Printf("Note: pinned snapshot not available for %s, %s slot unpinned\n", model, slot)
+	}
+	return profileID, err
+}
+
 // SelectAPIModels interactively selects models for an API profile
 // Updates cfg.Model, cfg.FastModel, and cfg.HeavyModel with model IDs
-func SelectAPIModels(cfg *config.Config) error {
+// When acceptRecommended is set, recommended models are chosen without prompting
+func SelectAPIModels(cfg *config.Config, acceptRecommended bool) error {
 	// Retrieve API key from keyring
 	apiKey, err := keyring.Get(cfg.APIKeyID)
 	if err != nil {
@@ -108,36 +159,36 @@ func SelectAPIModels(cfg *config.Config) error {
 	}
 
 	// Main model selection
-	mainModelOptions := buildAPIModelOptions(models, "main")
-	selectedMain, err := InteractiveSelect(
-		"Select Main Model",
-		"Type to filter models...",
+	mainModelOptions := buildAPIModelOptions(models, "main", nil)
+	selectedMain, err := selectModel(
+		i18n.T("Select Main Model"),
 		mainModelOptions,
 		cfg.Model,
+		acceptRecommended,
 	)
 	if err != nil {
 		return fmt.Errorf("main model selection failed: %w", err)
 	}
 
 	// Fast model selection
-	fastModelOptions := buildAPIModelOptions(models, "fast")
-	selectedFast, err := InteractiveSelect(
-		"Select Fast Model",
-		"Type to filter models...",
+	fastModelOptions := buildAPIModelOptions(models, "fast", nil)
+	selectedFast, err := selectModel(
+		i18n.T("Select Fast Model"),
 		fastModelOptions,
 		cfg.FastModel,
+		acceptRecommended,
 	)
 	if err != nil {
 		return fmt.Errorf("fast model selection failed: %w", err)
 	}
 
 	// Heavy model selection
-	heavyModelOptions := buildAPIModelOptions(models, "heavy")
-	selectedHeavy, err := InteractiveSelect(
-		"Select Heavy Model",
-		"Type to filter models...",
+	heavyModelOptions := buildAPIModelOptions(models, "heavy", nil)
+	selectedHeavy, err := selectModel(
+		i18n.T("Select Heavy Model"),
 		heavyModelOptions,
 		cfg.HeavyModel,
+		acceptRecommended,
 	)
 	if err != nil {
 		return fmt.Errorf("heavy model selection failed: %w", err)
@@ -157,43 +208,9 @@ func SelectAPIModelsManually(cfg *config.Config) error {
 	fmt.Println("\nUsing manual input mode")
 	fmt.Println()
 
-	// Main model input
-	mainModel, err := PromptTextInput(
-		"Enter Main Model ID",
-		"",
-		"claude-sonnet-4-5",
-	)
-	if err != nil {
-		return fmt.Errorf("main model input failed: %w", err)
-	}
-	if mainModel == "" {
-		return fmt.Errorf("main model ID cannot be empty")
-	}
-
-	// Fast model input
-	fastModel, err := PromptTextInput(
-		"Enter Fast Model ID",
-		"",
-		"claude-haiku-4-5",
-	)
-	if err != nil {
-		return fmt.Errorf("fast model input failed: %w", err)
-	}
-	if fastModel == "" {
-		return fmt.Errorf("fast model ID cannot be empty")
-	}
-
-	// Heavy model input
-	heavyModel, err := PromptTextInput(
-		"Enter Heavy Model ID",
-		"",
-		"claude-opus-4",
-	)
-	if err != nil {
-		return fmt.Errorf("heavy model input failed: %w", err)
-	}
-	if heavyModel == "" {
-		return fmt.Errorf("heavy model ID cannot be empty")
+	var mainModel, fastModel, heavyModel string
+	if err := promptModelIDs(&mainModel, &fastModel, &heavyModel, "claude-sonnet-4-5", "claude-haiku-4-5", "claude-opus-4"); err != nil {
+		return err
 	}
 
 	// Update config with entered model IDs
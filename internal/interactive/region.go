@@ -3,26 +3,54 @@ package interactive
 import (
 	"fmt"
 
+	"github.com/OlaHulleberg/clauderock/internal/aws"
 	"github.com/OlaHulleberg/clauderock/internal/awsutil"
+	"github.com/OlaHulleberg/clauderock/internal/i18n"
 )
 
 // SelectRegionWithSearch provides an interactive region selector with real-time filtering
-func SelectRegionWithSearch(currentRegion string) (string, error) {
+// Each region is probed in the background for Bedrock access with the given AWS profile,
+// and regions where it is unusable are greyed out
+func SelectRegionWithSearch(awsProfile, currentRegion string) (string, error) {
 	allRegions := awsutil.GetRegions()
 
 	// Convert regions to SelectOptions
 	options := make([]SelectOption, len(allRegions))
 	for i, r := range allRegions {
+		display := fmt.Sprintf("%s - %s", r.ID, r.Name)
+		if r.ID == r.Name {
+			display = r.ID
+		}
+		if r.Bedrock {
+			display += " [Bedrock]"
+		}
 		options[i] = SelectOption{
 			ID:      r.ID,
-			Display: fmt.Sprintf("%s - %s", r.ID, r.Name),
+			Display: display,
 		}
 	}
 
-	return InteractiveSelect(
-		"Filter AWS Regions",
-		"Type to filter regions...",
+	return InteractiveSelectWithProbe(
+		i18n.T("Filter AWS Regions"),
+		i18n.T("Type to filter regions..."),
 		options,
 		currentRegion,
+		func(region string) (string, bool) {
+			return probeBedrockRegion(awsProfile, region)
+		},
 	)
 }
+
+// probeBedrockRegion annotates a region with whether Bedrock can be used there
+// Regions are only disabled when the probe is conclusive, so credential issues don't block selection
+func probeBedrockRegion(awsProfile, region string) (string, bool) {
+	available, err := aws.ProbeRegion(awsProfile, region)
+	switch {
+	case err != nil:
+		return "(not checked)", true
+	case available:
+		return "✓", true
+	default:
+		return "(Bedrock unavailable)", false
+	}
+}
@@ -0,0 +1,156 @@
+package interactive
+
+import (
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/OlaHulleberg/clauderock/internal/accessibility"
+	"github.com/charmbracelet/bubbles/textinput"
+	tea "github.com/charmbracelet/bubbletea"
+	"golang.org/x/term"
+)
+
+// secretInputModel is the Bubbletea model for masked input such as API keys and passphrases
+type secretInputModel struct {
+	title     string
+	hint      string
+	textInput textinput.Model
+	value     string
+	empty     bool
+	quitting  bool
+	cancelled bool
+}
+
+// PromptSecret reads a secret without echoing it: typed and pasted characters show as dots,
+// with a count so a paste can be seen to have landed. Line breaks a paste carries are dropped
+// and surrounding whitespace is trimmed, while spaces inside the value are kept
+func PromptSecret(title, hint string) (string, error) {
+	if accessibility.Enabled() {
+		return promptSecretPlain(title, hint)
+	}
+
+	ti := textinput.New()
+	ti.EchoMode = textinput.EchoPassword
+	ti.EchoCharacter = '•'
+	ti.Focus()
+	ti.Width = 60
+
+	finalModel, err := tea.NewProgram(secretInputModel{title: title, hint: hint, textInput: ti}).Run()
+	if err != nil {
+		return "", err
+	}
+
+	result := finalModel.(secretInputModel)
+	if result.cancelled {
+		return "", fmt.Errorf("input cancelled")
+	}
+
+	return result.value, nil
+}
+
+// promptSecretPlain is the accessible secret input: a plain line, read without echo when
+// stdin is a terminal so the secret isn't read aloud or left on screen
+func promptSecretPlain(title, hint string) (string, error) {
+	fmt.Println(accessibility.PlainText(title))
+	if hint != "" {
+		fmt.Println(accessibility.PlainText(hint))
+	}
+	fmt.Print("Value (not shown): ")
+
+	fd := int(os.Stdin.Fd())
+	if !term.IsTerminal(fd) {
+		input, err := readLine()
+		if err != nil {
+			return "", fmt.Errorf("input cancelled")
+		}
+		return input, nil
+	}
+
+	input, err := term.ReadPassword(fd)
+	fmt.Println()
+	if err != nil {
+		return "", fmt.Errorf("input cancelled")
+	}
+	return cleanSecret(string(input)), nil
+}
+
+// cleanSecret drops line breaks and tabs a paste can bring along and trims the ends
+func cleanSecret(s string) string {
+	s = strings.NewReplacer("\r", "", "\n", "", "\t", "").Replace(s)
+	return strings.TrimSpace(s)
+}
+
+// Init initializes the model
+func (m secretInputModel) Init() tea.Cmd {
+	return textinput.Blink
+}
+
+// Update handles key presses and pastes and updates the model
+func (m secretInputModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	var cmd tea.Cmd
+
+	switch msg := msg.(type) {
+	case tea.KeyMsg:
+		switch msg.Type {
+		case tea.KeyEsc, tea.KeyCtrlC:
+			m.quitting = true
+			m.cancelled = true
+			return m, tea.Quit
+
+		case tea.KeyEnter:
+			value := cleanSecret(m.textInput.Value())
+			if value == "" {
+				m.empty = true
+				return m, nil
+			}
+			m.value = value
+			m.quitting = true
+			return m, tea.Quit
+		}
+
+		// A bracketed paste arrives as one message; the input would turn its line breaks into spaces
+		if msg.Paste {
+			msg.Runes = []rune(strings.NewReplacer("\r", "", "\n", "", "\t", "").Replace(string(msg.Runes)))
+		}
+		m.empty = false
+		m.textInput, cmd = m.textInput.Update(msg)
+		return m, cmd
+	}
+
+	m.textInput, cmd = m.textInput.Update(msg)
+	return m, cmd
+}
+
+// View renders the UI
+func (m secretInputModel) View() string {
+	if m.quitting {
+		return ""
+	}
+
+	var b strings.Builder
+
+	b.WriteString(titleStyle.Render(m.title))
+	b.WriteString("\n")
+	if m.hint != "" {
+		b.WriteString(helpStyle.Render(m.hint))
+		b.WriteString("\n")
+	}
+
+	b.WriteString(m.textInput.View())
+	b.WriteString("\n")
+
+	switch n := len([]rune(m.textInput.Value())); {
+	case m.empty:
+		b.WriteString(warningStyle.Render("A value is required"))
+	case n == 1:
+		b.WriteString(countStyle.Render("1 character"))
+	case n > 1:
+		b.WriteString(countStyle.Render(fmt.Sprintf("%d characters", n)))
+	}
+	b.WriteString("\n\n")
+
+	b.WriteString(helpStyle.Render("Enter: confirm • Ctrl+V: paste • Esc: cancel"))
+
+	return b.String()
+}
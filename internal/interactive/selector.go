@@ -4,6 +4,8 @@ import (
 	"fmt"
 	"strings"
 
+	"github.com/OlaHulleberg/clauderock/internal/accessibility"
+	"github.com/OlaHulleberg/clauderock/internal/i18n"
 	"github.com/charmbracelet/bubbles/textinput"
 	tea "github.com/charmbracelet/bubbletea"
 	"github.com/charmbracelet/lipgloss"
@@ -24,13 +26,27 @@ var (
 	headerStyle   = lipgloss.NewStyle().Bold(true).Foreground(lipgloss.Color("14")).Underline(true)
 	helpStyle     = lipgloss.NewStyle().Foreground(lipgloss.Color("8"))
 	countStyle    = lipgloss.NewStyle().Foreground(lipgloss.Color("8")).Italic(true)
+	disabledStyle = lipgloss.NewStyle().Foreground(lipgloss.Color("8")).Strikethrough(true)
 )
 
 // SelectOption represents an option in the selector
 type SelectOption struct {
-	ID       string // The value to return when selected
-	Display  string // The text to display
-	IsHeader bool   // If true, this is a non-selectable header
+	ID          string // The value to return when selected
+	Display     string // The text to display
+	IsHeader    bool   // If true, this is a non-selectable header
+	Recommended bool   // If true, Tab selects this option directly
+	Disabled    bool   // If true, the option is shown greyed out and cannot be selected
+}
+
+// OptionProbe checks an option in the background
+// Returns a note to append to the option's display and whether it remains selectable
+type OptionProbe func(id string) (note string, usable bool)
+
+// probeResultMsg delivers the outcome of an OptionProbe to the selector
+type probeResultMsg struct {
+	id     string
+	note   string
+	usable bool
 }
 
 // selectorModel is the Bubbletea model for real-time selection
@@ -46,10 +62,25 @@ type selectorModel struct {
 	height      int
 	quitting    bool
 	cancelled   bool
+	probe       OptionProbe
+	pending     int // Probes still running
 }
 
 // InteractiveSelect provides a reusable interactive selector with real-time filtering
 func InteractiveSelect(title, placeholder string, options []SelectOption, currentValue string) (string, error) {
+	return InteractiveSelectWithProbe(title, placeholder, options, currentValue, nil)
+}
+
+// InteractiveSelectWithProbe is InteractiveSelect with every option checked asynchronously by probe
+// Options are annotated as results arrive; unusable ones are greyed out and cannot be selected
+func InteractiveSelectWithProbe(title, placeholder string, options []SelectOption, currentValue string, probe OptionProbe) (string, error) {
+	// Probe results mutate options, so work on a copy
+	options = append([]SelectOption(nil), options...)
+
+	if accessibility.Enabled() {
+		return selectSequential(title, options, currentValue, probe)
+	}
+
 	// Initialize text input
 	ti := textinput.New()
 	ti.Placeholder = placeholder
@@ -75,6 +106,14 @@ func InteractiveSelect(title, placeholder string, options []SelectOption, curren
 		cursor:      cursor,
 		width:       defaultSelectorWidth,
 		height:      defaultSelectorHeight,
+		probe:       probe,
+	}
+	if probe != nil {
+		for _, opt := range options {
+			if !opt.IsHeader {
+				m.pending++
+			}
+		}
 	}
 
 	// Ensure cursor starts on a non-header item
@@ -119,7 +158,38 @@ func (m *selectorModel) moveCursorToNearestSelectableOption() {
 
 // Init initializes the model
 func (m selectorModel) Init() tea.Cmd {
-	return textinput.Blink
+	if m.probe == nil {
+		return textinput.Blink
+	}
+
+	cmds := []tea.Cmd{textinput.Blink}
+	for _, opt := range m.options {
+		if opt.IsHeader {
+			continue
+		}
+		id := opt.ID
+		cmds = append(cmds, func() tea.Msg {
+			note, usable := m.probe(id)
+			return probeResultMsg{id: id, note: note, usable: usable}
+		})
+	}
+	return tea.Batch(cmds...)
+}
+
+// applyProbeResult annotates the probed option and refreshes the filtered view
+func (m *selectorModel) applyProbeResult(msg probeResultMsg) {
+	m.pending--
+	for i := range m.options {
+		if m.options[i].IsHeader || m.options[i].ID != msg.id {
+			continue
+		}
+		if msg.note != "" {
+			m.options[i].Display += " " + msg.note
+		}
+		m.options[i].Disabled = !msg.usable
+	}
+	m.filtered = filterOptions(m.options, m.textInput.Value())
+	m.moveCursorToNearestSelectableOption()
 }
 
 // Update handles key presses and updates the model
@@ -131,6 +201,10 @@ func (m selectorModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		m.width = msg.Width
 		m.height = msg.Height
 
+	case probeResultMsg:
+		m.applyProbeResult(msg)
+		return m, nil
+
 	case tea.KeyMsg:
 		switch msg.Type {
 		case tea.KeyEsc, tea.KeyCtrlC:
@@ -139,12 +213,21 @@ func (m selectorModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 			return m, tea.Quit
 
 		case tea.KeyEnter:
-			if len(m.filtered) > 0 && !m.filtered[m.cursor].IsHeader {
+			if len(m.filtered) > 0 && !m.filtered[m.cursor].IsHeader && !m.filtered[m.cursor].Disabled {
 				m.selected = m.filtered[m.cursor].ID
 				m.quitting = true
 				return m, tea.Quit
 			}
 
+		case tea.KeyTab:
+			// Accept the recommended option regardless of the current filter
+			if recommended, ok := findRecommendedOption(m.options); ok {
+				m.selected = recommended.ID
+				m.quitting = true
+				return m, tea.Quit
+			}
+			return m, nil
+
 		case tea.KeyUp:
 			if m.cursor > 0 {
 				m.cursor--
@@ -197,6 +280,9 @@ func (m selectorModel) View() string {
 
 	// Show filtered results count
 	b.WriteString(countStyle.Render(fmt.Sprintf("Showing %d of %d options", len(m.filtered), len(m.options))))
+	if m.pending > 0 {
+		b.WriteString(countStyle.Render(fmt.Sprintf(" • checking availability (%d remaining)", m.pending)))
+	}
 	b.WriteString("\n\n")
 
 	// Render filtered list
@@ -219,6 +305,12 @@ func (m selectorModel) View() string {
 		if option.IsHeader {
 			// Render headers with special style
 			b.WriteString(headerStyle.Render(option.Display))
+		} else if option.Disabled {
+			prefix := "  "
+			if i == m.cursor {
+				prefix = "> "
+			}
+			b.WriteString(prefix + disabledStyle.Render(option.Display))
 		} else if i == m.cursor {
 			b.WriteString(selectedStyle.Render("> " + option.Display))
 		} else {
@@ -229,12 +321,41 @@ func (m selectorModel) View() string {
 
 	// Help text
 	b.WriteString("\n")
-	b.WriteString(helpStyle.Render("↑/↓: navigate • Enter: select • Esc: cancel"))
+	if _, ok := findRecommendedOption(m.options); ok {
+		b.WriteString(helpStyle.Render("↑/↓: navigate • Enter: select • Tab: accept recommended • Esc: cancel"))
+	} else {
+		b.WriteString(helpStyle.Render("↑/↓: navigate • Enter: select • Esc: cancel"))
+	}
 
 	return b.String()
 }
 
+// findRecommendedOption returns the first selectable option marked as recommended
+func findRecommendedOption(options []SelectOption) (SelectOption, bool) {
+	for _, opt := range options {
+		if opt.Recommended && !opt.IsHeader {
+			return opt, true
+		}
+	}
+	return SelectOption{}, false
+}
+
+// selectModel shows the model selector, or returns the recommended option directly
+// when acceptRecommended is set and the options include one
+func selectModel(title string, options []SelectOption, currentValue string, acceptRecommended bool) (string, error) {
+	if acceptRecommended {
+		if recommended, ok := findRecommendedOption(options); ok {
+			fmt.Printf("%s: %s (recommended)\n", title, recommended.ID)
+			return recommended.ID, nil
+		}
+	}
+
+	return InteractiveSelect(title, i18n.T("Type to filter models..."), options, currentValue)
+}
+
 // filterOptions filters options based on search term
+// Every section (including RECOMMENDED) is searched, each matching option appears
+// exactly once under its first section header, and sections are separated by spacers
 func filterOptions(options []SelectOption, searchTerm string) []SelectOption {
 	if searchTerm == "" {
 		return options
@@ -243,29 +364,23 @@ func filterOptions(options []SelectOption, searchTerm string) []SelectOption {
 	searchLower := strings.ToLower(searchTerm)
 	var filtered []SelectOption
 	var currentHeader *SelectOption
-	inRecommendedSection := false
+	seen := make(map[string]bool)
 
 	for _, option := range options {
 		if option.IsHeader {
-			// Track if we're entering the RECOMMENDED section
-			if option.Display == recommendedSectionHeader {
-				inRecommendedSection = true
-				currentHeader = nil
+			// Blank headers are spacers; they are re-inserted between matched sections below
+			if option.Display == "" {
 				continue
 			}
 
-			// Exit RECOMMENDED section when we hit another header
-			if inRecommendedSection {
-				inRecommendedSection = false
-			}
-
 			// Keep track of current header
-			currentHeader = &option
+			header := option
+			currentHeader = &header
 			continue
 		}
 
-		// Skip items in RECOMMENDED section during search
-		if inRecommendedSection {
+		// Options listed in several sections (e.g., recommended) only show up once
+		if seen[option.ID] {
 			continue
 		}
 
@@ -274,10 +389,14 @@ func filterOptions(options []SelectOption, searchTerm string) []SelectOption {
 			strings.Contains(strings.ToLower(option.Display), searchLower) {
 			// Add the header before the first match in this section
 			if currentHeader != nil {
+				if len(filtered) > 0 {
+					filtered = append(filtered, SelectOption{IsHeader: true})
+				}
 				filtered = append(filtered, *currentHeader)
 				currentHeader = nil // Only add header once per section
 			}
 			filtered = append(filtered, option)
+			seen[option.ID] = true
 		}
 	}
 
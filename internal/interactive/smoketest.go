@@ -0,0 +1,121 @@
+package interactive
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/OlaHulleberg/clauderock/internal/api"
+	"github.com/OlaHulleberg/clauderock/internal/aws"
+	"github.com/OlaHulleberg/clauderock/internal/config"
+	"github.com/OlaHulleberg/clauderock/internal/currency"
+	"github.com/OlaHulleberg/clauderock/internal/pricing"
+	"github.com/OlaHulleberg/clauderock/internal/vertex"
+)
+
+// smokeTestResult is the profile-type independent outcome of one test request
+type smokeTestResult struct {
+	latency      time.Duration
+	inputTokens  int64
+	outputTokens int64
+}
+
+// offerSmokeTest asks whether to send a tiny test request to each configured model
+// and reports latency, token usage, and estimated cost. apiKey is only used for API profiles.
+// Failures are reported but don't fail the wizard, since the configuration is already saved.
+func offerSmokeTest(cfg *config.Config, apiKey string, opts WizardOptions) {
+	run := false
+	if opts.Answers != nil && opts.Answers.SmokeTest != nil {
+		run = *opts.Answers.SmokeTest
+	} else {
+		confirmed, err := Confirm(
+			"Run a test request now?",
+			"Send a tiny generation request to each configured model to verify credentials and model access end-to-end",
+			[]string{"Uses a handful of tokens per model (well under $0.01 in total)"},
+		)
+		if err != nil {
+			return
+		}
+		run = confirmed
+	}
+	if !run {
+		return
+	}
+
+	var testFn func(modelID string) (smokeTestResult, error)
+	switch cfg.ProfileType {
+	case "bedrock":
+		testFn = func(modelID string) (smokeTestResult, error) {
+			result, err := aws.TestModel(cfg.AWSProfile(), cfg.Region, modelID)
+			return smokeTestResult{result.Latency, result.InputTokens, result.OutputTokens}, err
+		}
+	case "api":
+		testFn = func(modelID string) (smokeTestResult, error) {
+			result, err := api.TestModel(cfg.BaseURL, apiKey, modelID)
+			return smokeTestResult{result.Latency, result.InputTokens, result.OutputTokens}, err
+		}
+	case "vertex":
+		testFn = func(modelID string) (smokeTestResult, error) {
+			result, err := vertex.TestModel(cfg.GCPProject, cfg.VertexRegion, modelID)
+			return smokeTestResult{result.Latency, result.InputTokens, result.OutputTokens}, err
+		}
+	default:
+		return
+	}
+
+	fmt.Println("\nRunning test requests...")
+
+	// Only Bedrock prices vary by region
+	priceRegion := ""
+	if cfg.ProfileType == "bedrock" {
+		priceRegion = cfg.Region
+	}
+
+	var totalCost float64
+	failures := 0
+	for _, slot := range config.ModelSlots {
+		modelID, _ := cfg.ModelForSlot(slot)
+
+		result, err := testFn(modelID)
+		if err != nil {
+			fmt.Printf("  %-6s %s\n         ✗ %v\n", slot, modelID, err)
+			failures++
+			continue
+		}
+
+		priceKey := smokeTestPriceKey(cfg.ProfileType, modelID)
+		cost := pricing.CalculateCostInRegion(priceKey, priceRegion, result.inputTokens, result.outputTokens)
+		totalCost += cost
+
+		costDisplay := "cost unknown"
+		if _, ok := pricing.GetModelPriceInRegion(priceKey, priceRegion); ok {
+			costDisplay = currency.FormatPrecise(cost, 6)
+		} else if pricing.IsEstimated(priceKey) {
+			costDisplay = fmt.Sprintf("≈ %s (approximate)", currency.FormatPrecise(cost, 6))
+		}
+		fmt.Printf("  %-6s %s\n         ✓ %dms • %d in / %d out tokens • %s\n",
+			slot, modelID, result.latency.Milliseconds(), result.inputTokens, result.outputTokens, costDisplay)
+	}
+
+	fmt.Println()
+	if failures > 0 {
+		fmt.Printf("⚠ %d of %d test requests failed. Check model access and credentials, then run 'clauderock manage models test'.\n",
+			failures, len(config.ModelSlots))
+		return
+	}
+
+	fmt.Printf("✓ All test requests succeeded (total cost: %s)\n", currency.FormatPrecise(totalCost, 6))
+}
+
+// smokeTestPriceKey maps a configured model ID to its pricing table key
+// Bedrock profile IDs use their friendly name; bare API and Vertex AI model IDs are assumed to be Anthropic models
+func smokeTestPriceKey(profileType, modelID string) string {
+	if profileType == "bedrock" {
+		return aws.ExtractFriendlyModelName(modelID)
+	}
+	if !strings.Contains(modelID, ".") {
+		modelID, _, _ = strings.Cut(modelID, "@")
+		return "anthropic." + modelID
+	}
+	return modelID
+}
@@ -0,0 +1,166 @@
+package interactive
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/OlaHulleberg/clauderock/internal/fileutil"
+)
+
+const (
+	wizardStateFile = "wizard-state.json"
+	wizardStateTTL  = 24 * time.Hour
+)
+
+// wizardState holds answers collected by a wizard run that did not finish
+// Secrets (API keys) are never persisted; they are prompted for again on resume
+type wizardState struct {
+	TargetProfile     string    `json:"target-profile"` // clauderock profile being configured
+	ProfileType       string    `json:"profile-type,omitempty"`
+	CredentialsSource string    `json:"credentials-source,omitempty"`
+	AWSProfile        string    `json:"aws-profile,omitempty"`
+	Region            string    `json:"region,omitempty"`
+	CrossRegion       string    `json:"cross-region,omitempty"`
+	BaseURL           string    `json:"base-url,omitempty"`
+	GCPProject        string    `json:"gcp-project,omitempty"`
+	VertexRegion      string    `json:"vertex-region,omitempty"`
+	Model             string    `json:"model,omitempty"`
+	FastModel         string    `json:"fast-model,omitempty"`
+	HeavyModel        string    `json:"heavy-model,omitempty"`
+	SavedAt           time.Time `json:"saved-at"`
+
+	source string // How pre-filled answers were obtained, shown when a step is skipped
+}
+
+// hasAnswers reports whether any answer beyond the target profile was collected
+func (s *wizardState) hasAnswers() bool {
+	return s.ProfileType != "" || s.CredentialsSource != "" || s.AWSProfile != "" || s.Region != "" || s.CrossRegion != "" ||
+		s.BaseURL != "" || s.GCPProject != "" || s.VertexRegion != "" || s.Model != "" || s.FastModel != "" || s.HeavyModel != ""
+}
+
+// wizardStatePath returns the path of the file holding unfinished wizard answers
+// It lives in ~/.clauderock rather than the shared temp directory, where other users could
+// read it or plant a symlink in its place
+func wizardStatePath() (string, error) {
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return "", fmt.Errorf("failed to get home directory: %w", err)
+	}
+	return filepath.Join(home, ".clauderock", wizardStateFile), nil
+}
+
+// loadWizardState returns the unfinished wizard state for targetProfile, or nil if there is none
+// Stale state and state for other profiles are ignored
+func loadWizardState(targetProfile string) *wizardState {
+	path, err := wizardStatePath()
+	if err != nil {
+		return nil
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil
+	}
+
+	var state wizardState
+	if err := json.Unmarshal(data, &state); err != nil {
+		return nil
+	}
+
+	if state.TargetProfile != targetProfile || time.Since(state.SavedAt) > wizardStateTTL || !state.hasAnswers() {
+		return nil
+	}
+
+	return &state
+}
+
+// saveWizardState persists the answers collected so far, readable only by the user
+func saveWizardState(state *wizardState) error {
+	state.SavedAt = time.Now()
+
+	data, err := json.MarshalIndent(state, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to marshal wizard state: %w", err)
+	}
+
+	path, err := wizardStatePath()
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return fmt.Errorf("failed to create directory: %w", err)
+	}
+	if err := fileutil.WriteFileAtomic(path, data, 0600); err != nil {
+		return fmt.Errorf("failed to write wizard state: %w", err)
+	}
+
+	return nil
+}
+
+// clearWizardState removes any persisted wizard state
+func clearWizardState() {
+	if path, err := wizardStatePath(); err == nil {
+		os.Remove(path)
+	}
+}
+
+// offerResume asks whether to continue an unfinished setup for targetProfile
+// Returns the state to continue with (empty when starting fresh)
+func offerResume(targetProfile string) (*wizardState, error) {
+	fresh := &wizardState{TargetProfile: targetProfile}
+
+	previous := loadWizardState(targetProfile)
+	if previous == nil {
+		return fresh, nil
+	}
+
+	var details []string
+	for _, answer := range []struct{ label, value string }{
+		{"Profile Type", previous.ProfileType},
+		{"AWS Profile", previous.AWSProfile},
+		{"Region", previous.Region},
+		{"Cross Region", previous.CrossRegion},
+		{"Base URL", previous.BaseURL},
+		{"GCP Project", previous.GCPProject},
+		{"Vertex Region", previous.VertexRegion},
+		{"Model", previous.Model},
+		{"Fast Model", previous.FastModel},
+		{"Heavy Model", previous.HeavyModel},
+	} {
+		if answer.value != "" {
+			details = append(details, fmt.Sprintf("%s: %s", answer.label, answer.value))
+		}
+	}
+
+	resume, err := Confirm(
+		"Resume Previous Setup?",
+		fmt.Sprintf("A setup for profile '%s' did not finish (%s). Resume where you left off?",
+			targetProfile, previous.SavedAt.Format("2006-01-02 15:04")),
+		details,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("confirmation failed: %w", err)
+	}
+
+	if !resume {
+		clearWizardState()
+		return fresh, nil
+	}
+
+	return previous, nil
+}
+
+// answered prints a pre-filled answer and reports whether the step can be skipped
+func (s *wizardState) answered(label, value string) bool {
+	if value == "" {
+		return false
+	}
+	source := s.source
+	if source == "" {
+		source = "resumed"
+	}
+	fmt.Printf("%s: %s (%s)\n", label, value, source)
+	return true
+}
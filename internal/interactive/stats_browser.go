@@ -0,0 +1,416 @@
+package interactive
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/OlaHulleberg/clauderock/internal/accessibility"
+	"github.com/OlaHulleberg/clauderock/internal/currency"
+	"github.com/OlaHulleberg/clauderock/internal/usage"
+	"github.com/charmbracelet/bubbles/table"
+	"github.com/charmbracelet/bubbles/textinput"
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// browserChromeLines is the screen height taken by the title, filter line, and help around the table
+const browserChromeLines = 8
+
+// StatsBrowserOptions configures the stats browser
+type StatsBrowserOptions struct {
+	// ExportSessions writes the sessions shown to a file and returns its path
+	ExportSessions func(sessions []usage.Session) (string, error)
+	// ExportCalls writes one session's requests to a file and returns its path
+	ExportCalls func(session usage.Session, calls []usage.APICall) (string, error)
+}
+
+// sessionSort is a column the session table can be sorted by
+type sessionSort struct {
+	name string
+	less func(a, b usage.Session) bool
+}
+
+var sessionSorts = []sessionSort{
+	{"date", func(a, b usage.Session) bool { return a.StartTime.Before(b.StartTime) }},
+	{"cost", func(a, b usage.Session) bool { return usage.SessionCost(a) < usage.SessionCost(b) }},
+	{"tokens", func(a, b usage.Session) bool {
+		return a.TotalInputTokens+a.TotalOutputTokens < b.TotalInputTokens+b.TotalOutputTokens
+	}},
+	{"requests", func(a, b usage.Session) bool { return a.TotalRequests < b.TotalRequests }},
+	{"duration", func(a, b usage.Session) bool { return usage.ActiveSeconds(a) < usage.ActiveSeconds(b) }},
+}
+
+// statsBrowserModel is the Bubbletea model for browsing tracked sessions
+type statsBrowserModel struct {
+	opts     StatsBrowserOptions
+	sessions []usage.Session
+	calls    map[int64][]usage.APICall
+
+	shown      []usage.Session // Sessions matching the filter, in sort order
+	sortBy     int             // Index into sessionSorts
+	descending bool
+	filter     textinput.Model
+	filtering  bool
+
+	table  table.Model
+	detail *usage.Session // Session being drilled into, nil on the session list
+	status string
+}
+
+// RunStatsBrowser shows sessions in an interactive table until the user quits
+// calls are the recorded API calls of those sessions, for the per-request breakdown
+func RunStatsBrowser(sessions []usage.Session, calls []usage.APICall, opts StatsBrowserOptions) error {
+	if accessibility.Enabled() {
+		printSessionsPlain(sessions)
+		return nil
+	}
+
+	byID := make(map[int64][]usage.APICall)
+	for _, c := range calls {
+		byID[c.SessionID] = append(byID[c.SessionID], c)
+	}
+
+	ti := textinput.New()
+	ti.Placeholder = "profile:work model:sonnet date:2025-10 name:auth"
+	ti.Prompt = "/ "
+	ti.CharLimit = defaultInputCharLimit
+	ti.Width = defaultInputWidth
+
+	m := statsBrowserModel{
+		opts:       opts,
+		sessions:   sessions,
+		calls:      byID,
+		descending: true,
+		filter:     ti,
+		table:      table.New(table.WithFocused(true), table.WithHeight(defaultSelectorHeight)),
+	}
+	m.showSessions()
+
+	_, err := tea.NewProgram(m, tea.WithAltScreen()).Run()
+	return err
+}
+
+func (m statsBrowserModel) Init() tea.Cmd {
+	return nil
+}
+
+func (m statsBrowserModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	switch msg := msg.(type) {
+	case tea.WindowSizeMsg:
+		m.table.SetHeight(max(msg.Height-browserChromeLines, 3))
+		return m, nil
+
+	case tea.KeyMsg:
+		if m.filtering {
+			return m.updateFilter(msg)
+		}
+		m.status = ""
+
+		switch msg.String() {
+		case "ctrl+c", "q":
+			return m, tea.Quit
+		case "esc":
+			if m.detail == nil {
+				return m, tea.Quit
+			}
+			// Back on the list, with the session just viewed selected
+			id := m.detail.ID
+			m.detail = nil
+			m.showSessions()
+			for i, s := range m.shown {
+				if s.ID == id {
+					m.table.SetCursor(i)
+				}
+			}
+			return m, nil
+		case "enter":
+			if m.detail == nil && len(m.shown) > 0 {
+				selected := m.shown[m.table.Cursor()]
+				m.detail = &selected
+				m.showCalls()
+			}
+			return m, nil
+		case "e":
+			m.export()
+			return m, nil
+		}
+
+		if m.detail == nil {
+			switch msg.String() {
+			case "/":
+				m.filtering = true
+				return m, m.filter.Focus()
+			case "s":
+				m.sortBy = (m.sortBy + 1) % len(sessionSorts)
+				m.showSessions()
+				return m, nil
+			case "r":
+				m.descending = !m.descending
+				m.showSessions()
+				return m, nil
+			}
+		}
+	}
+
+	var cmd tea.Cmd
+	m.table, cmd = m.table.Update(msg)
+	return m, cmd
+}
+
+// updateFilter edits the filter, re-filtering the sessions as it changes
+func (m statsBrowserModel) updateFilter(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	switch msg.Type {
+	case tea.KeyCtrlC:
+		return m, tea.Quit
+	case tea.KeyEsc:
+		m.filter.SetValue("")
+		fallthrough
+	case tea.KeyEnter:
+		m.filtering = false
+		m.filter.Blur()
+		m.showSessions()
+		return m, nil
+	}
+
+	var cmd tea.Cmd
+	m.filter, cmd = m.filter.Update(msg)
+	m.showSessions()
+	return m, cmd
+}
+
+// showSessions fills the table with the sessions matching the filter, in sort order
+func (m *statsBrowserModel) showSessions() {
+	terms := strings.Fields(strings.ToLower(m.filter.Value()))
+	m.shown = nil
+	for _, s := range m.sessions {
+		if sessionMatches(s, terms) {
+			m.shown = append(m.shown, s)
+		}
+	}
+
+	less := sessionSorts[m.sortBy].less
+	sort.SliceStable(m.shown, func(i, j int) bool {
+		if m.descending {
+			return less(m.shown[j], m.shown[i])
+		}
+		return less(m.shown[i], m.shown[j])
+	})
+
+	rows := make([]table.Row, len(m.shown))
+	for i, s := range m.shown {
+		rows[i] = table.Row{
+			s.StartTime.Local().Format("2006-01-02 15:04"),
+			s.ProfileName,
+			s.Name,
+			usage.PriceKey(s.Model),
+			formatMinutes(usage.ActiveSeconds(s)),
+			fmt.Sprintf("%d", s.TotalRequests),
+			fmt.Sprintf("%d", s.TotalInputTokens+s.TotalOutputTokens),
+			currency.Format(usage.SessionCost(s)),
+		}
+	}
+
+	// Columns before rows, so the table never renders rows wider than its columns
+	m.table.SetRows(nil)
+	m.table.SetColumns([]table.Column{
+		{Title: "Started", Width: 16},
+		{Title: "Profile", Width: 14},
+		{Title: "Name", Width: 18},
+		{Title: "Model", Width: 30},
+		{Title: "Active", Width: 8},
+		{Title: "Requests", Width: 8},
+		{Title: "Tokens", Width: 12},
+		{Title: "Cost", Width: 10},
+	})
+	m.table.SetRows(rows)
+	m.table.SetCursor(0)
+}
+
+// showCalls fills the table with the per-request breakdown of the session being drilled into
+func (m *statsBrowserModel) showCalls() {
+	calls := m.calls[m.detail.ID]
+	rows := make([]table.Row, len(calls))
+	for i, c := range calls {
+		latency := "-"
+		if c.LatencyMs > 0 {
+			latency = fmt.Sprintf("%d ms", c.LatencyMs)
+		}
+		rows[i] = table.Row{
+			c.Timestamp.Local().Format("15:04:05"),
+			usage.PriceKey(c.Model),
+			fmt.Sprintf("%d", c.InputTokens),
+			fmt.Sprintf("%d", c.OutputTokens),
+			fmt.Sprintf("%d", c.CacheReadTokens),
+			fmt.Sprintf("%d", c.CacheCreationTokens),
+			latency,
+			currency.FormatPrecise(usage.CallCost(*m.detail, c), 4),
+		}
+	}
+
+	m.table.SetRows(nil)
+	m.table.SetColumns([]table.Column{
+		{Title: "Time", Width: 8},
+		{Title: "Model", Width: 30},
+		{Title: "Input", Width: 9},
+		{Title: "Output", Width: 9},
+		{Title: "Cache rd", Width: 9},
+		{Title: "Cache wr", Width: 9},
+		{Title: "Latency", Width: 9},
+		{Title: "Cost", Width: 10},
+	})
+	m.table.SetRows(rows)
+	m.table.SetCursor(0)
+}
+
+// export writes the sessions shown, or the session being drilled into, using the configured exporters
+func (m *statsBrowserModel) export() {
+	var path string
+	var err error
+	switch {
+	case m.detail != nil && m.opts.ExportCalls != nil:
+		path, err = m.opts.ExportCalls(*m.detail, m.calls[m.detail.ID])
+	case m.detail == nil && m.opts.ExportSessions != nil:
+		path, err = m.opts.ExportSessions(m.shown)
+	default:
+		return
+	}
+	if err != nil {
+		m.status = warningStyle.Render("Export failed: " + err.Error())
+		return
+	}
+	m.status = selectedStyle.Render("Exported to " + path)
+}
+
+func (m statsBrowserModel) View() string {
+	var b strings.Builder
+
+	if m.detail == nil {
+		order := "descending"
+		if !m.descending {
+			order = "ascending"
+		}
+		b.WriteString(titleStyle.Render("Sessions") + " " +
+			countStyle.Render(fmt.Sprintf("%d of %d, by %s %s", len(m.shown), len(m.sessions), sessionSorts[m.sortBy].name, order)) + "\n")
+		if m.filtering || m.filter.Value() != "" {
+			b.WriteString(m.filter.View())
+		}
+		b.WriteString("\n\n")
+	} else {
+		s := m.detail
+		b.WriteString(titleStyle.Render("Session "+s.StartTime.Local().Format("2006-01-02 15:04")) + " " +
+			countStyle.Render(fmt.Sprintf("%s, %s", s.ProfileName, usage.PriceKey(s.Model))) + "\n")
+		if s.Name != "" {
+			b.WriteString(helpStyle.Render("Name: "+s.Name) + "\n")
+		}
+		if s.Note != "" {
+			b.WriteString(helpStyle.Render("Note: "+s.Note) + "\n")
+		}
+		b.WriteString(helpStyle.Render(fmt.Sprintf("%s active, %d requests, %.1f%% cache hit rate, exit code %d, %s",
+			formatMinutes(usage.ActiveSeconds(*s)), s.TotalRequests, s.CacheHitRate, s.ExitCode, s.WorkingDirectory)) + "\n\n")
+	}
+
+	if len(m.table.Rows()) == 0 {
+		if m.detail != nil {
+			b.WriteString(helpStyle.Render("No per-request data; this session was tracked before requests were recorded") + "\n")
+		} else {
+			b.WriteString(helpStyle.Render("No sessions match") + "\n")
+		}
+	} else {
+		b.WriteString(m.table.View() + "\n")
+	}
+
+	b.WriteString("\n")
+	if m.status != "" {
+		b.WriteString(m.status + "\n")
+	}
+	switch {
+	case m.filtering:
+		b.WriteString(helpStyle.Render("enter: apply • esc: clear • filter by profile:, model:, date:, name:, repo:, tag: or any text"))
+	case m.detail != nil:
+		b.WriteString(helpStyle.Render("↑/↓: move • e: export requests • esc: back • q: quit"))
+	default:
+		b.WriteString(helpStyle.Render("↑/↓: move • enter: requests • /: filter • s: sort • r: reverse • e: export • q: quit"))
+	}
+	return b.String()
+}
+
+// sessionMatches reports whether a session matches every filter term
+// "profile:", "model:", "date:", "name:", "repo:" and "tag:" terms match one field; other terms
+// match any of them, or the session's note
+func sessionMatches(s usage.Session, terms []string) bool {
+	profile := strings.ToLower(s.ProfileName)
+	model := strings.ToLower(usage.PriceKey(s.Model) + " " + s.Model)
+	date := s.StartTime.Local().Format("2006-01-02 15:04")
+	name := strings.ToLower(s.Name)
+	note := strings.ToLower(s.Note)
+	repo := strings.ToLower(s.Repo)
+	tags := strings.ToLower(s.Tags)
+
+	for _, term := range terms {
+		field, value, ok := strings.Cut(term, ":")
+		switch {
+		case ok && field == "profile":
+			if !strings.Contains(profile, value) {
+				return false
+			}
+		case ok && field == "model":
+			if !strings.Contains(model, value) {
+				return false
+			}
+		case ok && field == "date":
+			if !strings.HasPrefix(date, value) {
+				return false
+			}
+		case ok && field == "name":
+			if !strings.Contains(name, value) {
+				return false
+			}
+		case ok && field == "repo":
+			if !strings.Contains(repo, value) {
+				return false
+			}
+		case ok && field == "tag":
+			if !strings.Contains(tags, value) {
+				return false
+			}
+		default:
+			if !strings.Contains(profile, term) && !strings.Contains(model, term) && !strings.HasPrefix(date, term) &&
+				!strings.Contains(name, term) && !strings.Contains(note, term) && !strings.Contains(repo, term) &&
+				!strings.Contains(tags, term) {
+				return false
+			}
+		}
+	}
+	return true
+}
+
+// formatMinutes renders seconds as whole minutes, or hours and minutes
+func formatMinutes(seconds int) string {
+	d := (time.Duration(seconds) * time.Second).Round(time.Minute)
+	if d < time.Hour {
+		return fmt.Sprintf("%dm", int(d.Minutes()))
+	}
+	return fmt.Sprintf("%dh%02dm", int(d.Hours()), int(d.Minutes())%60)
+}
+
+// printSessionsPlain lists sessions one per line, newest first, for accessible mode
+func printSessionsPlain(sessions []usage.Session) {
+	if len(sessions) == 0 {
+		fmt.Println("No sessions match")
+		return
+	}
+	for _, s := range sessions {
+		name := ""
+		if s.Name != "" {
+			name = fmt.Sprintf(", named %s", s.Name)
+		}
+		fmt.Printf("%s, profile %s%s, model %s, %s active, %d requests, %d tokens, estimated cost %s\n",
+			s.StartTime.Local().Format("2006-01-02 15:04"), s.ProfileName, name, usage.PriceKey(s.Model),
+			formatMinutes(usage.ActiveSeconds(s)), s.TotalRequests, s.TotalInputTokens+s.TotalOutputTokens,
+			currency.Format(usage.SessionCost(s)))
+		if s.Note != "" {
+			fmt.Printf("  Note: %s\n", s.Note)
+		}
+	}
+}
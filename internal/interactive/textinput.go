@@ -4,6 +4,7 @@ import (
 	"fmt"
 	"strings"
 
+	"github.com/OlaHulleberg/clauderock/internal/accessibility"
 	"github.com/charmbracelet/bubbles/textinput"
 	tea "github.com/charmbracelet/bubbletea"
 )
@@ -20,6 +21,10 @@ type textInputModel struct {
 
 // PromptTextInput provides a reusable interactive text input with example text
 func PromptTextInput(title, placeholder, example string) (string, error) {
+	if accessibility.Enabled() {
+		return promptPlain(title, example)
+	}
+
 	// Initialize text input
 	ti := textinput.New()
 	ti.Placeholder = placeholder
@@ -47,6 +52,11 @@ func PromptTextInput(title, placeholder, example string) (string, error) {
 	return result.value, nil
 }
 
+// PromptPassword is PromptTextInput with the typed characters masked
+func PromptPassword(title string) (string, error) {
+	return PromptSecret(title, "")
+}
+
 // Init initializes the model
 func (m textInputModel) Init() tea.Cmd {
 	return textinput.Blink
@@ -0,0 +1,180 @@
+package interactive
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/OlaHulleberg/clauderock/internal/api"
+	"github.com/OlaHulleberg/clauderock/internal/config"
+	"github.com/OlaHulleberg/clauderock/internal/heuristics"
+	"github.com/OlaHulleberg/clauderock/internal/i18n"
+	"github.com/OlaHulleberg/clauderock/internal/vertex"
+)
+
+// vertexRegions lists the Vertex AI locations serving Claude models
+var vertexRegions = []SelectOption{
+	{ID: "global", Display: "global  (routes to any region with capacity)"},
+	{ID: "us-east5", Display: "us-east5  (Columbus)"},
+	{ID: "europe-west1", Display: "europe-west1  (Belgium)"},
+	{ID: "asia-east1", Display: "asia-east1  (Taiwan)"},
+	{ID: "asia-southeast1", Display: "asia-southeast1  (Singapore)"},
+}
+
+// vertexSlotContexts maps a model slot to the recommendation context buildAPIModelOptions looks for
+var vertexSlotContexts = map[string]string{"main": "code", "fast": "code-fast", "heavy": "code-heavy"}
+
+// runVertexConfig handles the Vertex AI configuration flow
+func runVertexConfig(cfg *config.Config, manager interface {
+	Save(name string, cfg *config.Config) error
+}, currentProfile, currentVersion string, opts WizardOptions, state *wizardState) error {
+	// Step 1: Project
+	if !state.answered("GCP Project", state.GCPProject) {
+		project, err := promptGCPProject(cfg.GCPProject)
+		if err != nil {
+			return err
+		}
+		state.GCPProject = project
+	}
+
+	// Step 2: Region
+	if !state.answered("Vertex Region", state.VertexRegion) {
+		current := cfg.VertexRegion
+		if current == "" {
+			current = os.Getenv("CLOUD_ML_REGION")
+		}
+		region, err := InteractiveSelect(
+			i18n.T("Select Vertex AI Region"),
+			i18n.T("Type to filter regions..."),
+			vertexRegions,
+			current,
+		)
+		if err != nil {
+			return fmt.Errorf("region selection failed: %w", err)
+		}
+		state.VertexRegion = region
+	}
+	cfg.GCPProject = state.GCPProject
+	cfg.VertexRegion = state.VertexRegion
+
+	// Step 3: Models
+	// Each model answered up front is kept; only the missing ones are asked for
+	selectedModel, selectedFastModel, selectedHeavyModel := state.Model, state.FastModel, state.HeavyModel
+	mainAnswered := state.answered("Main Model", selectedModel)
+	fastAnswered := state.answered("Fast Model", selectedFastModel)
+	heavyAnswered := state.answered("Heavy Model", selectedHeavyModel)
+	if !mainAnswered || !fastAnswered || !heavyAnswered {
+		if err := selectVertexModels(cfg, opts.hints, opts.AcceptRecommended, &selectedModel, &selectedFastModel, &selectedHeavyModel); err != nil {
+			return err
+		}
+		state.Model, state.FastModel, state.HeavyModel = selectedModel, selectedFastModel, selectedHeavyModel
+	}
+
+	cfg.Model = selectedModel
+	cfg.FastModel = selectedFastModel
+	cfg.HeavyModel = selectedHeavyModel
+
+	// Clear the other profile types' fields
+	cfg.Profile = ""
+	cfg.CredentialsSource = ""
+	cfg.Region = ""
+	cfg.CrossRegion = ""
+	cfg.PinnedVersions = nil
+	cfg.BaseURL = ""
+	cfg.APIKeyID = ""
+
+	// Validate configuration
+	if err := cfg.Validate(); err != nil {
+		return fmt.Errorf("invalid configuration: %w", err)
+	}
+
+	// Update version to current CLI version (but not for dev builds)
+	if currentVersion != "dev" {
+		cfg.Version = currentVersion
+	}
+
+	if err := manager.Save(currentProfile, cfg); err != nil {
+		return fmt.Errorf("failed to save config: %w", err)
+	}
+
+	fmt.Printf("\n✓ %s\n", i18n.T("Configuration saved successfully to profile '%s'!", currentProfile))
+	fmt.Printf("\n%s\n", i18n.T("Configuration:"))
+	fmt.Printf("  Profile Type:  %s\n", cfg.ProfileType)
+	fmt.Printf("  GCP Project:   %s\n", cfg.GCPProject)
+	fmt.Printf("  Vertex Region: %s\n", cfg.VertexRegion)
+	fmt.Printf("  Model:         %s\n", cfg.Model)
+	fmt.Printf("  Fast Model:    %s\n", cfg.FastModel)
+	fmt.Printf("  Heavy Model:   %s\n", cfg.HeavyModel)
+	fmt.Println()
+
+	// Verify the setup end-to-end before the first real session
+	offerSmokeTest(cfg, "", opts)
+
+	return nil
+}
+
+// SelectVertexModels picks the three models of a Vertex AI profile from the Model Garden,
+// falling back to manual input when it can't be listed
+func SelectVertexModels(cfg *config.Config, acceptRecommended bool) error {
+	var mainModel, fastModel, heavyModel string
+	if err := selectVertexModels(cfg, nil, acceptRecommended, &mainModel, &fastModel, &heavyModel); err != nil {
+		return err
+	}
+	cfg.Model, cfg.FastModel, cfg.HeavyModel = mainModel, fastModel, heavyModel
+	return nil
+}
+
+// selectVertexModels picks the models that aren't set yet, preselecting cfg's current ones
+func selectVertexModels(cfg *config.Config, hints heuristics.Suggestions, acceptRecommended bool, mainModel, fastModel, heavyModel *string) error {
+	fmt.Println("\nFetching available models from the Vertex AI Model Garden...")
+	models, err := vertex.FetchAvailableModels(cfg.GCPProject, cfg.VertexRegion)
+	if err != nil {
+		fmt.Printf("Could not list models: %v\n", err)
+		fmt.Println("Using manual input mode")
+		fmt.Println()
+		return promptModelIDs(mainModel, fastModel, heavyModel,
+			"claude-sonnet-4-5@20250929", "claude-haiku-4-5@20251001", "claude-opus-4-1@20250805")
+	}
+
+	return selectMissingModels(vertexModelInfos(models), hints, acceptRecommended, cfg, mainModel, fastModel, heavyModel)
+}
+
+// promptGCPProject asks for the Google Cloud project, suggesting the current or environment one
+func promptGCPProject(current string) (string, error) {
+	if current == "" {
+		current = os.Getenv("ANTHROPIC_VERTEX_PROJECT_ID")
+	}
+	if current == "" {
+		current = os.Getenv("GOOGLE_CLOUD_PROJECT")
+	}
+
+	for {
+		example := "my-project-123456"
+		if current != "" {
+			example = current + " (press Enter to keep)"
+		}
+		input, err := PromptTextInput("Enter your Google Cloud project ID", "my-project-123456", example)
+		if err != nil {
+			return "", fmt.Errorf("failed to read project ID: %w", err)
+		}
+		if input == "" {
+			input = current
+		}
+		if !vertex.ValidProject(input) {
+			fmt.Printf("✗ '%s' is not a valid Google Cloud project ID\n", input)
+			continue
+		}
+		return input, nil
+	}
+}
+
+// vertexModelInfos adapts Model Garden models to the API model list the selector is built from
+func vertexModelInfos(models []vertex.ModelInfo) []api.ModelInfo {
+	infos := make([]api.ModelInfo, len(models))
+	for i, m := range models {
+		infos[i] = api.ModelInfo{ID: m.ID, Name: m.Name}
+		if m.Recommended != "" {
+			infos[i].Recommended = []string{vertexSlotContexts[m.Recommended]}
+		}
+	}
+	return infos
+}
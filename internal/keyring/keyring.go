@@ -75,6 +75,20 @@ func Delete(id string) error {
 	return nil
 }
 
+// Check confirms the keyring can be opened and listed, returning how many keys it holds
+func Check() (int, error) {
+	ring, err := openKeyring()
+	if err != nil {
+		return 0, fmt.Errorf("failed to open keyring: %w", err)
+	}
+
+	keys, err := ring.Keys()
+	if err != nil {
+		return 0, fmt.Errorf("failed to list keyring: %w", err)
+	}
+	return len(keys), nil
+}
+
 // openKeyring opens the file-based keyring with machine-specific encryption
 func openKeyring() (keyring.Keyring, error) {
 	home, err := os.UserHomeDir()
@@ -0,0 +1,159 @@
+package launcher
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/OlaHulleberg/clauderock/internal/config"
+)
+
+// claudeVersionTimeout bounds 'claude --version'
+const claudeVersionTimeout = 5 * time.Second
+
+// envSupport is the first Claude Code version that reads a variable clauderock sets
+type envSupport struct {
+	Name       string
+	MinVersion string
+	Setting    string // Profile setting the variable carries
+}
+
+// claudeEnvSupport lists the variables older Claude Code versions silently ignore,
+// falling back to their default models
+// The versions are the releases whose entries in Claude Code's changelog introduce each
+// variable (https://github.com/anthropics/claude-code/blob/main/CHANGELOG.md); add a row
+// here, with its release, when clauderock starts setting another model variable
+var claudeEnvSupport = []envSupport{
+	// 1.0.88 introduced the sonnet and opus alias variables together
+	{Name: "ANTHROPIC_DEFAULT_SONNET_MODEL", MinVersion: "1.0.88", Setting: "main model"},
+	{Name: "ANTHROPIC_DEFAULT_OPUS_MODEL", MinVersion: "1.0.88", Setting: "heavy model"},
+	// 2.0.17 added the haiku alias variable, replacing ANTHROPIC_SMALL_FAST_MODEL
+	{Name: "ANTHROPIC_DEFAULT_HAIKU_MODEL", MinVersion: "2.0.17", Setting: "fast model"},
+}
+
+// claudeVersionCache remembers the version of a claude binary, so launches don't run it every time
+type claudeVersionCache struct {
+	Path    string    `json:"path"`
+	ModTime time.Time `json:"mod-time"`
+	Size    int64     `json:"size"`
+	Version string    `json:"version"`
+}
+
+var versionPattern = regexp.MustCompile(`\d+\.\d+\.\d+`)
+
+func claudeVersionCachePath() (string, error) {
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(home, ".clauderock", "claude-version.json"), nil
+}
+
+// claudeVersion returns the version of the claude binary at path, from the cache while the binary is unchanged
+func claudeVersion(path string) (string, error) {
+	info, err := os.Stat(path)
+	if err != nil {
+		return "", err
+	}
+
+	cachePath, err := claudeVersionCachePath()
+	if err != nil {
+		return "", err
+	}
+	if data, err := os.ReadFile(cachePath); err == nil {
+		var cached claudeVersionCache
+		if json.Unmarshal(data, &cached) == nil && cached.Path == path &&
+			cached.ModTime.Equal(info.ModTime()) && cached.Size == info.Size() {
+			return cached.Version, nil
+		}
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), claudeVersionTimeout)
+	defer cancel()
+	out, err := exec.CommandContext(ctx, path, "--version").Output()
+	if err != nil {
+		return "", fmt.Errorf("failed to run claude --version: %w", err)
+	}
+	version := versionPattern.FindString(string(out))
+	if version == "" {
+		return "", fmt.Errorf("unrecognised claude --version output: %s", strings.TrimSpace(string(out)))
+	}
+
+	// The cache only saves time, so failing to write it is ignored
+	cached := claudeVersionCache{Path: path, ModTime: info.ModTime(), Size: info.Size(), Version: version}
+	if data, err := json.MarshalIndent(cached, "", "  "); err == nil {
+		if os.MkdirAll(filepath.Dir(cachePath), 0755) == nil {
+			_ = os.WriteFile(cachePath, data, 0644)
+		}
+	}
+	return version, nil
+}
+
+// ClaudeVersion finds the claude binary on PATH and returns its path and version
+func ClaudeVersion() (path, version string, err error) {
+	path, err = exec.LookPath("claude")
+	if err != nil {
+		return "", "", fmt.Errorf("claude not found in PATH: %w", err)
+	}
+	version, err = claudeVersion(path)
+	return path, version, err
+}
+
+// UnsupportedModelSettings describes the model settings Claude Code version ignores, e.g.
+// "fast model (ANTHROPIC_DEFAULT_HAIKU_MODEL needs 2.0.17)"
+func UnsupportedModelSettings(version string) []string {
+	var names []string
+	for _, s := range claudeEnvSupport {
+		names = append(names, s.Name+"=")
+	}
+	var settings []string
+	for _, s := range unsupportedEnv(version, names) {
+		settings = append(settings, fmt.Sprintf("%s (%s needs %s)", s.Setting, s.Name, s.MinVersion))
+	}
+	return settings
+}
+
+// unsupportedEnv returns the variables in vars that Claude Code version is too old to read
+func unsupportedEnv(version string, vars []string) []envSupport {
+	set := make(map[string]bool)
+	for _, kv := range vars {
+		if name, _, ok := strings.Cut(kv, "="); ok {
+			set[name] = true
+		}
+	}
+
+	var unsupported []envSupport
+	for _, s := range claudeEnvSupport {
+		if set[s.Name] && config.CompareVersions(version, s.MinVersion) < 0 {
+			unsupported = append(unsupported, s)
+		}
+	}
+	return unsupported
+}
+
+// warnUnsupportedEnv warns when the installed Claude Code ignores variables the profile relies on,
+// which would otherwise send requests to its default models without any sign
+// A version that can't be detected skips the check
+func warnUnsupportedEnv(claudePath string, vars []string) {
+	version, err := claudeVersion(claudePath)
+	if err != nil {
+		return
+	}
+	unsupported := unsupportedEnv(version, vars)
+	if len(unsupported) == 0 {
+		return
+	}
+
+	for _, s := range unsupported {
+		fmt.Printf("Warning: Claude Code %s ignores %s (needs %s or newer); the %s setting won't be used and Claude picks its default model\n",
+			version, s.Name, s.MinVersion, s.Setting)
+	}
+	fmt.Println("Update Claude Code with: claude update")
+	fmt.Println()
+}
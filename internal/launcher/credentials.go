@@ -0,0 +1,53 @@
+package launcher
+
+import (
+	"fmt"
+
+	"github.com/OlaHulleberg/clauderock/internal/config"
+)
+
+// awsCredentialEnvVars carry AWS credentials for profiles that don't load a shared AWS profile
+// Entries ending in "*" match by prefix
+var awsCredentialEnvVars = []string{
+	"AWS_ACCESS_KEY_ID", "AWS_SECRET_ACCESS_KEY", "AWS_SESSION_TOKEN",
+	"AWS_WEB_IDENTITY_TOKEN_FILE", "AWS_ROLE_ARN", "AWS_ROLE_SESSION_NAME",
+	"AWS_CONTAINER_CREDENTIALS_*", "AWS_CONTAINER_AUTHORIZATION_TOKEN*", "AWS_EC2_METADATA_*",
+}
+
+// credentialEnvAllowlist returns the variables the "minimal" env policy must keep so Claude Code
+// can find the profile's AWS credentials
+func credentialEnvAllowlist(cfg *config.Config) []string {
+	if cfg.ProfileType != "bedrock" || cfg.UsesSharedProfile() {
+		return nil
+	}
+	if cfg.CredentialsSource == config.CredentialsSourceDefaultChain {
+		// The default chain still honours a profile picked by the environment
+		return append(append([]string{}, awsCredentialEnvVars...), "AWS_PROFILE")
+	}
+	return awsCredentialEnvVars
+}
+
+// CheckEnvironmentCredentials makes sure env carries AWS credentials for a profile with
+// credentials-source environment, so the launch doesn't fail on the first request
+func CheckEnvironmentCredentials(env []string) error {
+	switch {
+	case envValue(env, "AWS_ACCESS_KEY_ID") != "" && envValue(env, "AWS_SECRET_ACCESS_KEY") != "":
+	case envValue(env, "AWS_WEB_IDENTITY_TOKEN_FILE") != "" && envValue(env, "AWS_ROLE_ARN") != "":
+	case envValue(env, "AWS_CONTAINER_CREDENTIALS_FULL_URI") != "" || envValue(env, "AWS_CONTAINER_CREDENTIALS_RELATIVE_URI") != "":
+	default:
+		return fmt.Errorf("credentials-source is 'environment' but no AWS credentials are set: %s", CredentialsFix(&config.Config{CredentialsSource: config.CredentialsSourceEnvironment}))
+	}
+	return nil
+}
+
+// CredentialsFix suggests how to provide the AWS credentials a Bedrock profile expects
+func CredentialsFix(cfg *config.Config) string {
+	switch cfg.CredentialsSource {
+	case config.CredentialsSourceEnvironment:
+		return "export AWS_ACCESS_KEY_ID and AWS_SECRET_ACCESS_KEY (or AWS_WEB_IDENTITY_TOKEN_FILE and AWS_ROLE_ARN), or use the SDK's default chain with: clauderock manage config set credentials-source default-chain"
+	case config.CredentialsSourceDefaultChain:
+		return "make credentials available to the AWS SDK (environment variables, a web identity token such as IRSA, or a container or instance role)"
+	default:
+		return fmt.Sprintf("check that AWS profile '%s' exists in ~/.aws/config (aws configure sso), or change it with: clauderock manage config set profile <name>", cfg.Profile)
+	}
+}
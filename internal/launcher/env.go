@@ -0,0 +1,352 @@
+package launcher
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/OlaHulleberg/clauderock/internal/api"
+	"github.com/OlaHulleberg/clauderock/internal/aws"
+	"github.com/OlaHulleberg/clauderock/internal/config"
+	"github.com/OlaHulleberg/clauderock/internal/keyring"
+	"github.com/OlaHulleberg/clauderock/internal/profiles"
+	"github.com/OlaHulleberg/clauderock/internal/vertex"
+)
+
+// envConflict describes an inherited environment variable that silently
+// overrides part of a clauderock profile's configuration
+type envConflict struct {
+	Name         string
+	ProfileTypes []string // Profile types affected; empty means all
+	Reason       string
+	Scrub        bool // Remove by default; otherwise only warn since it may be intentional
+	SharedOnly   bool // Only a conflict when credentials come from a shared AWS profile
+	EnvOnly      bool // Only a conflict when credentials come from the environment
+}
+
+// bedrockInAPIProfile is the conflict for a Bedrock or AWS variable left in an API
+// profile's environment, which the profile never uses
+func bedrockInAPIProfile(name string) envConflict {
+	return envConflict{Name: name, ProfileTypes: []string{"api"}, Reason: "belongs to Bedrock, not this API profile", Scrub: true}
+}
+
+// conflictingEnvVars lists variables commonly left over from a previous eval/export
+var conflictingEnvVars = []envConflict{
+	{Name: "CLAUDE_CODE_USE_VERTEX", ProfileTypes: []string{"bedrock", "api"}, Reason: "switches Claude Code to Vertex AI", Scrub: true},
+	{Name: "CLAUDE_CODE_USE_BEDROCK", ProfileTypes: []string{"api", "vertex"}, Reason: "switches Claude Code to Bedrock", Scrub: true},
+	{Name: "ANTHROPIC_BASE_URL", ProfileTypes: []string{"bedrock"}, Reason: "redirects requests away from Bedrock", Scrub: true},
+	{Name: "ANTHROPIC_BASE_URL", ProfileTypes: []string{"vertex"}, Reason: "redirects requests away from Vertex AI", Scrub: true},
+	{Name: "ANTHROPIC_API_KEY", ProfileTypes: []string{"bedrock"}, Reason: "takes precedence over Bedrock credentials", Scrub: true},
+	{Name: "ANTHROPIC_API_KEY", ProfileTypes: []string{"vertex"}, Reason: "takes precedence over Vertex AI credentials", Scrub: true},
+	{Name: "ANTHROPIC_AUTH_TOKEN", Reason: "overrides the configured credentials", Scrub: true},
+	{Name: "ANTHROPIC_MODEL", Reason: "overrides the configured main model", Scrub: true},
+	{Name: "ANTHROPIC_SMALL_FAST_MODEL", Reason: "overrides the configured fast model", Scrub: true},
+	{Name: "ANTHROPIC_BEDROCK_BASE_URL", ProfileTypes: []string{"bedrock"}, Reason: "overrides the Bedrock endpoint"},
+	{Name: "CLAUDE_CODE_SKIP_BEDROCK_AUTH", ProfileTypes: []string{"bedrock"}, Reason: "skips AWS authentication"},
+	{Name: "AWS_ACCESS_KEY_ID", ProfileTypes: []string{"bedrock"}, Reason: "takes precedence over the configured AWS profile", SharedOnly: true},
+	{Name: "AWS_SESSION_TOKEN", ProfileTypes: []string{"bedrock"}, Reason: "takes precedence over the configured AWS profile", SharedOnly: true},
+	{Name: "AWS_PROFILE", ProfileTypes: []string{"bedrock"}, Reason: "loads a shared AWS profile instead of the credentials in the environment", Scrub: true, EnvOnly: true},
+	{Name: "AWS_DEFAULT_PROFILE", ProfileTypes: []string{"bedrock"}, Reason: "loads a shared AWS profile instead of the credentials in the environment", Scrub: true, EnvOnly: true},
+	{Name: "ANTHROPIC_VERTEX_BASE_URL", ProfileTypes: []string{"vertex"}, Reason: "overrides the Vertex AI endpoint"},
+	{Name: "CLAUDE_CODE_SKIP_VERTEX_AUTH", ProfileTypes: []string{"vertex"}, Reason: "skips Google Cloud authentication"},
+
+	// Bedrock/AWS variables have no business in an API profile's session
+	bedrockInAPIProfile("AWS_BEARER_TOKEN_BEDROCK"),
+	bedrockInAPIProfile("ANTHROPIC_BEDROCK_BASE_URL"),
+	bedrockInAPIProfile("CLAUDE_CODE_SKIP_BEDROCK_AUTH"),
+	bedrockInAPIProfile("AWS_PROFILE"),
+	bedrockInAPIProfile("AWS_REGION"),
+	bedrockInAPIProfile("AWS_DEFAULT_REGION"),
+	bedrockInAPIProfile("AWS_ACCESS_KEY_ID"),
+	bedrockInAPIProfile("AWS_SECRET_ACCESS_KEY"),
+	bedrockInAPIProfile("AWS_SESSION_TOKEN"),
+}
+
+// backendEnvVars lists variables that only make sense for one profile type
+// A child environment carrying the other type's variables may reach the wrong backend
+var backendEnvVars = map[string][]string{
+	"bedrock": {"CLAUDE_CODE_USE_BEDROCK", "AWS_BEARER_TOKEN_BEDROCK", "ANTHROPIC_BEDROCK_BASE_URL", "CLAUDE_CODE_SKIP_BEDROCK_AUTH"},
+	"api":     {"ANTHROPIC_API_KEY", "ANTHROPIC_AUTH_TOKEN", "ANTHROPIC_BASE_URL"},
+	"vertex":  {"CLAUDE_CODE_USE_VERTEX", "ANTHROPIC_VERTEX_BASE_URL", "CLAUDE_CODE_SKIP_VERTEX_AUTH"},
+}
+
+// checkBackendIsolation returns the variables in the final child environment that belong
+// to the other profile type (only possible when --clauderock-keep-env kept them)
+func checkBackendIsolation(env []string, profileType string) []string {
+	foreign := make(map[string]bool)
+	for t, names := range backendEnvVars {
+		if t == profileType {
+			continue
+		}
+		for _, name := range names {
+			foreign[name] = true
+		}
+	}
+
+	var found []string
+	for _, kv := range env {
+		if name, value, ok := strings.Cut(kv, "="); ok && value != "" && foreign[name] {
+			found = append(found, name)
+		}
+	}
+	return found
+}
+
+// appliesTo reports whether the conflict affects a profile
+func (c envConflict) appliesTo(cfg *config.Config) bool {
+	if c.SharedOnly && !cfg.UsesSharedProfile() {
+		return false
+	}
+	if c.EnvOnly && cfg.CredentialsSource != config.CredentialsSourceEnvironment {
+		return false
+	}
+	if len(c.ProfileTypes) == 0 {
+		return true
+	}
+	for _, t := range c.ProfileTypes {
+		if t == cfg.ProfileType {
+			return true
+		}
+	}
+	return false
+}
+
+// findEnvConflicts returns the conflicting variables set (non-empty) in env for a profile
+func findEnvConflicts(env []string, cfg *config.Config) []envConflict {
+	set := make(map[string]bool)
+	for _, kv := range env {
+		if name, value, ok := strings.Cut(kv, "="); ok && value != "" {
+			set[name] = true
+		}
+	}
+
+	var conflicts []envConflict
+	for _, c := range conflictingEnvVars {
+		if set[c.Name] && c.appliesTo(cfg) {
+			conflicts = append(conflicts, c)
+		}
+	}
+	return conflicts
+}
+
+// sanitizeInheritedEnv warns about inherited variables that conflict with the profile
+// and removes the ones that would silently override it, unless keepEnv is set
+func sanitizeInheritedEnv(env []string, cfg *config.Config, keepEnv bool) []string {
+	filtered, conflicts := scrubInheritedEnv(env, cfg, keepEnv)
+	for _, c := range conflicts {
+		if c.Scrub && !keepEnv {
+			fmt.Printf("Warning: ignoring %s from your environment (%s)\n", c.Name, c.Reason)
+		} else {
+			fmt.Printf("Warning: %s is set in your environment and %s\n", c.Name, c.Reason)
+		}
+	}
+	return filtered
+}
+
+// sanitizePluginEnv drops the variables pre-launch hooks asked for that would override the
+// profile, as inherited ones are; --clauderock-keep-env only keeps the user's own environment
+func sanitizePluginEnv(env []string, cfg *config.Config) []string {
+	filtered, conflicts := scrubInheritedEnv(env, cfg, false)
+	for _, c := range conflicts {
+		if c.Scrub {
+			fmt.Printf("Warning: ignoring %s set by a plugin (%s)\n", c.Name, c.Reason)
+		} else {
+			fmt.Printf("Warning: %s is set by a plugin and %s\n", c.Name, c.Reason)
+		}
+	}
+	return filtered
+}
+
+// scrubInheritedEnv removes inherited variables that would silently override the profile,
+// unless keepEnv is set, and returns every conflict found
+func scrubInheritedEnv(env []string, cfg *config.Config, keepEnv bool) ([]string, []envConflict) {
+	conflicts := findEnvConflicts(env, cfg)
+	if len(conflicts) == 0 {
+		return env, nil
+	}
+
+	scrub := make(map[string]bool)
+	for _, c := range conflicts {
+		if c.Scrub && !keepEnv {
+			scrub[c.Name] = true
+		}
+	}
+	if len(scrub) == 0 {
+		return env, conflicts
+	}
+
+	filtered := make([]string, 0, len(env))
+	for _, kv := range env {
+		name, _, _ := strings.Cut(kv, "=")
+		if !scrub[name] {
+			filtered = append(filtered, kv)
+		}
+	}
+	return filtered, conflicts
+}
+
+// ProfileEnv returns the variables that point Claude Code at a profile's provider and models
+// For API profiles this reads the API key from the keyring
+func ProfileEnv(cfg *config.Config, mainModelID, fastModelID, heavyModelID string) ([]string, error) {
+	models := []string{
+		fmt.Sprintf("ANTHROPIC_DEFAULT_SONNET_MODEL=%s", mainModelID),
+		fmt.Sprintf("ANTHROPIC_DEFAULT_HAIKU_MODEL=%s", fastModelID),
+		fmt.Sprintf("ANTHROPIC_DEFAULT_OPUS_MODEL=%s", heavyModelID),
+	}
+	models = append(models, tuningEnv(cfg)...)
+
+	switch cfg.ProfileType {
+	case "bedrock":
+		env := append([]string{"CLAUDE_CODE_USE_BEDROCK=1"}, models...)
+		// Without a shared profile Claude Code finds credentials the way the AWS SDK does
+		if awsProfile := cfg.AWSProfile(); awsProfile != "" {
+			env = append(env, fmt.Sprintf("AWS_PROFILE=%s", awsProfile))
+		}
+		return append(env, fmt.Sprintf("AWS_REGION=%s", cfg.Region)), nil
+	case "api":
+		apiKey, err := keyring.Get(cfg.APIKeyID)
+		if err != nil {
+			return nil, fmt.Errorf("failed to retrieve API key from keyring: %w", err)
+		}
+		env := []string{
+			fmt.Sprintf("ANTHROPIC_API_KEY=%s", apiKey),
+			fmt.Sprintf("ANTHROPIC_BASE_URL=%s", api.NormalizeBaseURL(cfg.BaseURL)),
+		}
+		return append(env, models...), nil
+	case "vertex":
+		env := append([]string{"CLAUDE_CODE_USE_VERTEX=1"}, models...)
+		return append(env,
+			fmt.Sprintf("ANTHROPIC_VERTEX_PROJECT_ID=%s", cfg.GCPProject),
+			fmt.Sprintf("CLOUD_ML_REGION=%s", cfg.VertexRegion),
+		), nil
+	default:
+		return nil, fmt.Errorf("unsupported profile type: %s", cfg.ProfileType)
+	}
+}
+
+// fastModelRegion returns the region fast model requests are sent to instead of the
+// profile's region, or "" when they go to the same one
+func fastModelRegion(cfg *config.Config) string {
+	if cfg.ProfileType != "bedrock" {
+		return ""
+	}
+	return cfg.FastModelRegion
+}
+
+// tuningEnv returns the Claude Code tuning variables the profile sets
+func tuningEnv(cfg *config.Config) []string {
+	var env []string
+	if region := fastModelRegion(cfg); region != "" {
+		env = append(env, fmt.Sprintf("ANTHROPIC_SMALL_FAST_MODEL_AWS_REGION=%s", region))
+	}
+	if cfg.MaxOutputTokens > 0 {
+		env = append(env, fmt.Sprintf("CLAUDE_CODE_MAX_OUTPUT_TOKENS=%d", cfg.MaxOutputTokens))
+	}
+	if cfg.MaxThinkingTokens > 0 {
+		env = append(env, fmt.Sprintf("MAX_THINKING_TOKENS=%d", cfg.MaxThinkingTokens))
+	}
+	return env
+}
+
+// SessionEnv returns the variables telling hooks, status lines, and MCP servers inside the
+// session which clauderock profile and models are active
+// CLAUDEROCK_PROFILE also makes clauderock commands run from the session use the same profile
+func SessionEnv(cfg *config.Config, profileName, sessionID, mainModelID, fastModelID, heavyModelID string) []string {
+	return []string{
+		fmt.Sprintf("%s=%s", profiles.CurrentProfileEnvVar, profileName),
+		fmt.Sprintf("CLAUDEROCK_PROFILE_TYPE=%s", cfg.ProfileType),
+		fmt.Sprintf("CLAUDEROCK_SESSION_ID=%s", sessionID),
+		fmt.Sprintf("CLAUDEROCK_MODEL=%s", friendlyModelName(cfg, mainModelID)),
+		fmt.Sprintf("CLAUDEROCK_FAST_MODEL=%s", friendlyModelName(cfg, fastModelID)),
+		fmt.Sprintf("CLAUDEROCK_HEAVY_MODEL=%s", friendlyModelName(cfg, heavyModelID)),
+	}
+}
+
+// friendlyModelName returns the display name of a model ID for the profile's provider
+func friendlyModelName(cfg *config.Config, modelID string) string {
+	switch cfg.ProfileType {
+	case "vertex":
+		return vertex.FriendlyName(modelID)
+	case "api":
+		return api.ExtractFriendlyName(modelID)
+	default:
+		return aws.ExtractFriendlyModelName(modelID)
+	}
+}
+
+// LaunchEnv returns the complete environment clauderock gives Claude Code for a profile:
+// inherited filtered by the profile's env-policy, without conflicting variables, plus ProfileEnv
+// It prints nothing and ignores integration-mode, always delivering the profile in the environment
+func LaunchEnv(cfg *config.Config, inherited []string, mainModelID, fastModelID, heavyModelID string) ([]string, error) {
+	if cfg.EnvPolicy == config.EnvPolicyMinimal {
+		inherited = filterEnvAllowlist(inherited, append(credentialEnvAllowlist(cfg), cfg.EnvAllowlist...))
+	}
+	env, _ := scrubInheritedEnv(inherited, cfg, false)
+
+	profileEnv, err := ProfileEnv(cfg, mainModelID, fastModelID, heavyModelID)
+	if err != nil {
+		return nil, err
+	}
+	return append(append([]string{}, env...), profileEnv...), nil
+}
+
+// envValue returns the value of name in a KEY=VALUE list, or ""
+func envValue(env []string, name string) string {
+	for _, kv := range env {
+		if key, value, ok := strings.Cut(kv, "="); ok && key == name {
+			return value
+		}
+	}
+	return ""
+}
+
+// minimalEnvAllowlist is passed through under the "minimal" env policy
+// Entries ending in "*" match by prefix
+var minimalEnvAllowlist = []string{
+	// Shell and terminal basics
+	"PATH", "HOME", "USER", "LOGNAME", "SHELL", "TERM", "COLORTERM", "TERM_PROGRAM",
+	"LANG", "LC_*", "TZ", "TMPDIR", "TMP", "TEMP",
+	"XDG_CONFIG_HOME", "XDG_CACHE_HOME", "XDG_DATA_HOME", "XDG_RUNTIME_DIR",
+
+	// Windows essentials
+	"SYSTEMROOT", "WINDIR", "COMSPEC", "PATHEXT", "USERPROFILE", "APPDATA", "LOCALAPPDATA",
+	"HOMEDRIVE", "HOMEPATH",
+
+	// Networking and TLS
+	"HTTP_PROXY", "HTTPS_PROXY", "NO_PROXY", "http_proxy", "https_proxy", "no_proxy",
+	"SSL_CERT_FILE", "SSL_CERT_DIR", "NODE_EXTRA_CA_CERTS",
+
+	// Claude and AWS file locations (credentials come from the configured profile, or
+	// from credentialEnvAllowlist when the profile doesn't use one)
+	"CLAUDE_CONFIG_DIR", "AWS_CONFIG_FILE", "AWS_SHARED_CREDENTIALS_FILE",
+
+	// Google Cloud Application Default Credentials, used by Vertex AI profiles
+	"GOOGLE_APPLICATION_CREDENTIALS", "CLOUDSDK_CONFIG",
+}
+
+// filterEnvAllowlist keeps only variables matching the minimal allowlist or the extra entries
+func filterEnvAllowlist(env []string, extra []string) []string {
+	patterns := append(append([]string{}, minimalEnvAllowlist...), extra...)
+
+	filtered := make([]string, 0, len(patterns))
+	for _, kv := range env {
+		name, _, _ := strings.Cut(kv, "=")
+		if matchesEnvPattern(name, patterns) {
+			filtered = append(filtered, kv)
+		}
+	}
+	return filtered
+}
+
+// matchesEnvPattern reports whether name matches an exact entry or a "PREFIX*" entry
+func matchesEnvPattern(name string, patterns []string) bool {
+	for _, p := range patterns {
+		if prefix, ok := strings.CutSuffix(p, "*"); ok {
+			if strings.HasPrefix(name, prefix) {
+				return true
+			}
+		} else if name == p {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,212 @@
+package launcher
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/OlaHulleberg/clauderock/internal/api"
+	"github.com/OlaHulleberg/clauderock/internal/aws"
+	"github.com/OlaHulleberg/clauderock/internal/config"
+	"github.com/aws/smithy-go"
+)
+
+// Kinds of preflight validation failures
+const (
+	failureModelMissing = "model-missing"
+	failureAuthExpired  = "auth-expired"
+	failureAuthDenied   = "auth-denied"
+	failureAWSConfig    = "aws-config"
+	failureOther        = "other"
+)
+
+// failureRepeatThreshold is how many failures in a row of the same kind trigger a suggested fix
+const failureRepeatThreshold = 2
+
+// maxFailureLog caps the number of entries kept in the failure log
+const maxFailureLog = 50
+
+// validationFailure is one entry of the failure log
+type validationFailure struct {
+	Time    time.Time `json:"time"`
+	Profile string    `json:"profile"`
+	Kind    string    `json:"kind"`
+	Message string    `json:"message"`
+}
+
+func failureLogPath() (string, error) {
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(home, ".clauderock", "validation-failures.json"), nil
+}
+
+func loadFailureLog() ([]validationFailure, error) {
+	path, err := failureLogPath()
+	if err != nil {
+		return nil, err
+	}
+	data, err := os.ReadFile(path)
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	var failures []validationFailure
+	if err := json.Unmarshal(data, &failures); err != nil {
+		return nil, err
+	}
+	return failures, nil
+}
+
+func saveFailureLog(failures []validationFailure) error {
+	path, err := failureLogPath()
+	if err != nil {
+		return err
+	}
+	if len(failures) > maxFailureLog {
+		failures = failures[len(failures)-maxFailureLog:]
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return err
+	}
+	data, err := json.MarshalIndent(failures, "", "  ")
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(path, data, 0644)
+}
+
+// classifyFailure sorts a validation error into a kind with a known fix
+func classifyFailure(err error) string {
+	var httpErr *api.HTTPError
+	if errors.As(err, &httpErr) && (httpErr.StatusCode == http.StatusUnauthorized || httpErr.StatusCode == http.StatusForbidden) {
+		return failureAuthDenied
+	}
+
+	var apiErr smithy.APIError
+	if errors.As(err, &apiErr) {
+		switch apiErr.ErrorCode() {
+		case "ExpiredTokenException", "ExpiredToken":
+			return failureAuthExpired
+		case "AccessDeniedException", "UnrecognizedClientException", "InvalidSignatureException":
+			return failureAuthDenied
+		}
+	}
+
+	msg := strings.ToLower(err.Error())
+	switch {
+	case strings.Contains(msg, "does not exist") || strings.Contains(msg, "models not available"):
+		return failureModelMissing
+	case strings.Contains(msg, "expired") || strings.Contains(msg, "refresh cached credentials") ||
+		strings.Contains(msg, "sso session"):
+		return failureAuthExpired
+	case strings.Contains(msg, "failed to load aws config") || strings.Contains(msg, "shared config profile"):
+		return failureAWSConfig
+	default:
+		return failureOther
+	}
+}
+
+// failureFix suggests how to fix a kind of validation failure for a profile
+func failureFix(kind string, cfg *config.Config) string {
+	bedrock := cfg.ProfileType == "bedrock"
+	vertex := cfg.ProfileType == "vertex"
+	switch kind {
+	case failureModelMissing:
+		if bedrock {
+			return "move retired models to newer versions with: clauderock manage models remap"
+		}
+		return "pick available models with: clauderock manage config models"
+	case failureAuthExpired:
+		if bedrock {
+			// Only SSO profiles are refreshed by logging in; keys and roles need the credentials fixed
+			if loginProfile, err := aws.SSOLoginProfile(cfg.AWSProfile()); err == nil && loginProfile != "" {
+				return fmt.Sprintf("refresh your AWS session with: aws sso login --profile %s", loginProfile)
+			}
+			return "refresh your AWS credentials: " + CredentialsFix(cfg)
+		}
+		if vertex {
+			return "refresh your Google Cloud credentials with: gcloud auth application-default login"
+		}
+		return "update the API key with: clauderock manage config"
+	case failureAuthDenied:
+		if bedrock {
+			return "check the IAM permissions with: clauderock manage doctor iam"
+		}
+		if vertex {
+			return fmt.Sprintf("check that the Vertex AI API is enabled in project '%s' and your account has the Vertex AI User role", cfg.GCPProject)
+		}
+		return "update the API key with: clauderock manage config"
+	case failureAWSConfig:
+		return CredentialsFix(cfg)
+	default:
+		return ""
+	}
+}
+
+// recordValidationFailure logs a failed preflight validation and, when the same kind of
+// failure keeps repeating for the profile, returns a hint with the likely fix
+func recordValidationFailure(cfg *config.Config, profileName string, validationErr error) string {
+	failures, err := loadFailureLog()
+	if err != nil {
+		// A corrupt log only costs us the history
+		failures = nil
+	}
+
+	kind := classifyFailure(validationErr)
+	failures = append(failures, validationFailure{
+		Time:    time.Now(),
+		Profile: profileName,
+		Kind:    kind,
+		Message: validationErr.Error(),
+	})
+	_ = saveFailureLog(failures)
+
+	// Count the failures of this kind in a row for the profile, newest first
+	repeats := 0
+	var since time.Time
+	for i := len(failures) - 1; i >= 0; i-- {
+		f := failures[i]
+		if f.Profile != profileName {
+			continue
+		}
+		if f.Kind != kind {
+			break
+		}
+		repeats++
+		since = f.Time
+	}
+
+	fix := failureFix(kind, cfg)
+	if repeats < failureRepeatThreshold || fix == "" {
+		return ""
+	}
+	return fmt.Sprintf("This has failed %d times in a row for profile '%s' since %s; %s",
+		repeats, profileName, since.Format("Jan 02 15:04"), fix)
+}
+
+// clearValidationFailures forgets a profile's failures after a successful validation
+// Claude is already running, so errors are ignored
+func clearValidationFailures(profileName string) {
+	failures, err := loadFailureLog()
+	if err != nil || len(failures) == 0 {
+		return
+	}
+	kept := failures[:0]
+	for _, f := range failures {
+		if f.Profile != profileName {
+			kept = append(kept, f)
+		}
+	}
+	if len(kept) != len(failures) {
+		_ = saveFailureLog(kept)
+	}
+}
@@ -4,79 +4,198 @@ import (
 	"fmt"
 	"os"
 	"os/exec"
+	"os/signal"
 	"path/filepath"
+	"strings"
 	"time"
 
 	"github.com/OlaHulleberg/clauderock/internal/api"
 	"github.com/OlaHulleberg/clauderock/internal/aws"
 	"github.com/OlaHulleberg/clauderock/internal/config"
-	"github.com/OlaHulleberg/clauderock/internal/keyring"
+	"github.com/OlaHulleberg/clauderock/internal/currency"
+	"github.com/OlaHulleberg/clauderock/internal/interactive"
+	"github.com/OlaHulleberg/clauderock/internal/monitoring"
+	"github.com/OlaHulleberg/clauderock/internal/plugins"
+	"github.com/OlaHulleberg/clauderock/internal/profiles"
 	"github.com/OlaHulleberg/clauderock/internal/usage"
+	"github.com/OlaHulleberg/clauderock/internal/vertex"
+	"golang.org/x/term"
 )
 
 // Launch executes Claude Code with the proper environment variables (Bedrock or API)
-func Launch(cfg *config.Config, mainModelID, fastModelID, heavyModelID string, profileName string, disableAuthSuppress bool, args []string) error {
+// startup, when not nil, records how long each step before Claude Code starts takes and is reported just before it does
+// sessionName labels the session in usage stats; named sessions are offered a note when they end
+// tags are recorded with the session as given by usage.FormatTags, for filtering stats by them
+func Launch(cfg *config.Config, mainModelID, fastModelID, heavyModelID string, profileName, sessionName, tags string, disableAuthSuppress, keepEnv bool, startup *StartupProfile, args []string) error {
 	// Get current working directory for session tracking
 	cwd, err := os.Getwd()
 	if err != nil {
 		cwd = ""
 	}
 
+	if err := checkLaunchDir(cfg, profileName, cwd); err != nil {
+		return err
+	}
+	if cfg.ProfileType == "bedrock" && cfg.CredentialsSource == config.CredentialsSourceEnvironment {
+		if err := CheckEnvironmentCredentials(os.Environ()); err != nil {
+			return err
+		}
+	}
+
+	// Reading the keyring and validating the models are the slowest steps and nothing else
+	// depends on them, so they run while the rest of the launch is prepared
+	// API validation needs the key, so it follows the keyring read
+	validationDone := make(chan error, 1)
+	switch cfg.ProfileType {
+	case "bedrock":
+		go func() {
+			since := time.Now()
+			err := aws.ValidateModels(cfg, mainModelID, fastModelID, heavyModelID)
+			startup.Track("validate models", since)
+			validationDone <- err
+		}()
+	case "vertex":
+		go func() {
+			since := time.Now()
+			err := vertex.ValidateModels(cfg.GCPProject, cfg.VertexRegion, mainModelID, fastModelID, heavyModelID)
+			startup.Track("validate models", since)
+			validationDone <- err
+		}()
+	}
+	var profileEnv []string
+	profileEnvDone := make(chan error, 1)
+	go func() {
+		since := time.Now()
+		env, err := ProfileEnv(cfg, mainModelID, fastModelID, heavyModelID)
+		startup.Track("profile environment", since)
+		profileEnv = env
+		profileEnvDone <- err
+		if err != nil || cfg.ProfileType != "api" {
+			return
+		}
+
+		since = time.Now()
+		err = api.ValidateModels(cfg.BaseURL, envValue(env, "ANTHROPIC_API_KEY"), mainModelID, fastModelID, heavyModelID)
+		startup.Track("validate models", since)
+		validationDone <- err
+	}()
+
+	// Record sessions left unrecorded by clauderock runs that were killed
+	since := time.Now()
+	recoverInterruptedSessions()
+	startup.Track("recover interrupted sessions", since)
+
+	// Vertex AI profiles keep their region separately from the AWS one
+	region := cfg.Region
+	if cfg.ProfileType == "vertex" {
+		region = cfg.VertexRegion
+	}
+
 	// Track session start
+	git := detectGit(cwd)
 	sessionStart := time.Now()
+	session := usage.SessionInfo{
+		StartTime:           sessionStart,
+		ProfileName:         profileName,
+		WorkingDirectory:    cwd,
+		AWSProfile:          cfg.AWSProfile(),
+		Region:              region,
+		CrossRegion:         cfg.CrossRegion,
+		PricingTier:         cfg.PricingTier,
+		Name:                sessionName,
+		Repo:                git.Repo,
+		RepoURL:             git.RemoteURL,
+		Branch:              git.Branch,
+		Tags:                tags,
+		Model:               cfg.Model,
+		ModelProfileID:      mainModelID,
+		FastModel:           cfg.FastModel,
+		FastModelProfileID:  fastModelID,
+		FastModelRegion:     fastModelRegion(cfg),
+		HeavyModel:          cfg.HeavyModel,
+		HeavyModelProfileID: heavyModelID,
+	}
 
 	// Find claude binary
+	since = time.Now()
 	claudePath, err := exec.LookPath("claude")
 	if err != nil {
 		return fmt.Errorf("claude binary not found in PATH: %w", err)
 	}
+	startup.Track("find claude", since)
 
 	// Prepare environment variables based on profile type
-	env := os.Environ()
+	// The "minimal" policy only passes an explicit allowlist instead of all shell secrets
+	inherited := os.Environ()
+	if cfg.EnvPolicy == config.EnvPolicyMinimal {
+		inherited = filterEnvAllowlist(inherited, append(credentialEnvAllowlist(cfg), cfg.EnvAllowlist...))
+	}
 
-	// Setup validation channel
-	validationDone := make(chan error, 1)
+	// Leftover exports (e.g., from a previous eval) would silently override the profile
+	env := sanitizeInheritedEnv(inherited, cfg, keepEnv)
+
+	// Plugins may add variables for the session; the profile's own variables still win
+	hookInfo := plugins.LaunchInfo{
+		Profile:          profileName,
+		ProfileType:      cfg.ProfileType,
+		WorkingDirectory: cwd,
+		Region:           region,
+		Model:            mainModelID,
+		FastModel:        fastModelID,
+		HeavyModel:       heavyModelID,
+		Start:            sessionStart,
+	}
+	since = time.Now()
+	env = append(env, sanitizePluginEnv(plugins.RunHook(plugins.HookPreLaunch, hookInfo), cfg)...)
+	startup.Track("pre-launch plugins", since)
 
-	if cfg.ProfileType == "bedrock" {
-		// Bedrock mode: Use AWS credentials
-		env = append(env,
-			"CLAUDE_CODE_USE_BEDROCK=1",
-			fmt.Sprintf("ANTHROPIC_DEFAULT_SONNET_MODEL=%s", mainModelID),
-			fmt.Sprintf("ANTHROPIC_DEFAULT_HAIKU_MODEL=%s", fastModelID),
-			fmt.Sprintf("ANTHROPIC_DEFAULT_OPUS_MODEL=%s", heavyModelID),
-			fmt.Sprintf("AWS_PROFILE=%s", cfg.Profile),
-			fmt.Sprintf("AWS_REGION=%s", cfg.Region),
-		)
-
-		// Validate model profile IDs in background
-		go func() {
-			validationDone <- aws.ValidateProfileIDs(cfg.Profile, cfg.Region, mainModelID, fastModelID, heavyModelID)
-		}()
+	// Variables carrying the profile's provider and model configuration
+	if err := <-profileEnvDone; err != nil {
+		return err
+	}
+
+	since = time.Now()
+	warnUnsupportedEnv(claudePath, profileEnv)
+	startup.Track("check claude version", since)
 
-	} else if cfg.ProfileType == "api" {
-		// API mode: Retrieve API key from encrypted keyring
-		apiKey, err := keyring.Get(cfg.APIKeyID)
+	// Deliver the configuration via the project's settings file or the process environment
+	restoreSettings := func() error { return nil }
+	if cfg.IntegrationMode == config.IntegrationModeSettings {
+		if cwd == "" {
+			return fmt.Errorf("integration-mode settings requires a working directory")
+		}
+		settingsEnv, secrets := splitSecretEnv(profileEnv)
+		restoreSettings, err = writeProjectSettingsEnv(cwd, settingsEnv, secrets)
 		if err != nil {
-			return fmt.Errorf("failed to retrieve API key from keyring: %w", err)
+			return fmt.Errorf("failed to write project settings: %w", err)
 		}
-
-		// Normalize base URL
-		normalizedURL := api.NormalizeBaseURL(cfg.BaseURL)
-
-		env = append(env,
-			fmt.Sprintf("ANTHROPIC_API_KEY=%s", apiKey),
-			fmt.Sprintf("ANTHROPIC_BASE_URL=%s", normalizedURL),
-			fmt.Sprintf("ANTHROPIC_DEFAULT_SONNET_MODEL=%s", mainModelID),
-			fmt.Sprintf("ANTHROPIC_DEFAULT_HAIKU_MODEL=%s", fastModelID),
-			fmt.Sprintf("ANTHROPIC_DEFAULT_OPUS_MODEL=%s", heavyModelID),
-		)
-
-		// Validate models via API in background
+		defer cleanupProjectSettings(restoreSettings)
+		env = append(env, secrets...)
+
+		// Ctrl+C is handled by Claude itself; keep clauderock alive so the settings file is restored
+		// Catching the signal rather than ignoring it leaves Claude's own handling alone, since an
+		// ignored signal would stay ignored in the child
+		interrupts := make(chan os.Signal, 1)
+		signal.Notify(interrupts, os.Interrupt)
 		go func() {
-			validationDone <- api.ValidateModels(cfg.BaseURL, apiKey, mainModelID, fastModelID, heavyModelID)
+			for range interrupts {
+			}
+		}()
+		defer func() {
+			signal.Stop(interrupts)
+			close(interrupts)
 		}()
 	} else {
-		return fmt.Errorf("unsupported profile type: %s", cfg.ProfileType)
+		env = append(env, profileEnv...)
+	}
+
+	// Identify the session to whatever runs inside it; set per process, even in settings mode
+	env = append(env, SessionEnv(cfg, profileName, monitoring.SessionID(sessionStart), mainModelID, fastModelID, heavyModelID)...)
+
+	// Last line of defence: the child must not see both providers' configuration
+	if foreign := checkBackendIsolation(env, cfg.ProfileType); len(foreign) > 0 {
+		fmt.Printf("Warning: %s profile launched with %s set; Claude may use the wrong backend\n\n",
+			cfg.ProfileType, strings.Join(foreign, ", "))
 	}
 
 	// Execute claude with passthrough args
@@ -98,6 +217,17 @@ func Launch(cfg *config.Config, mainModelID, fastModelID, heavyModelID string, p
 		}
 	}
 
+	// Journal the session first, so it's still recorded if clauderock is killed while Claude runs
+	since = time.Now()
+	watcher, err := monitoring.WatchSession(sessionStart, session)
+	if err != nil {
+		fmt.Printf("Warning: failed to journal session: %v\n", err)
+	}
+	defer watcher.Finish()
+	startup.Track("journal session", since)
+
+	startup.Report(os.Stderr)
+
 	// Start Claude Code (non-blocking)
 	if err := cmd.Start(); err != nil {
 		// Restore credentials before returning error if they were disabled
@@ -108,6 +238,9 @@ func Launch(cfg *config.Config, mainModelID, fastModelID, heavyModelID string, p
 		}
 		return fmt.Errorf("failed to start claude: %w", err)
 	}
+	if err := watcher.SetChild(cmd.Process.Pid); err != nil {
+		fmt.Printf("Warning: failed to journal Claude's process: %v\n", err)
+	}
 
 	// Wait 1000ms for Claude Code to initialize, then restore credentials if they were disabled
 	if credentialsDisabled {
@@ -131,9 +264,18 @@ func Launch(cfg *config.Config, mainModelID, fastModelID, heavyModelID string, p
 			cmd.Process.Kill()
 			// Wait for process to be killed
 			<-cmdDone
+			if hint := recordValidationFailure(cfg, profileName, validationErr); hint != "" {
+				return fmt.Errorf("invalid model configuration: %w\n\n%s", validationErr, hint)
+			}
 			return fmt.Errorf("invalid model configuration: %w", validationErr)
 		}
-		// Validation succeeded - wait for Claude Code to complete normally
+		clearValidationFailures(profileName)
+		// Validation succeeded - models picked from the offline catalog are now known to work
+		if cfg.Unverified {
+			markVerified(profileName, mainModelID, fastModelID, heavyModelID)
+		}
+
+		// Wait for Claude Code to complete normally
 		cmdErr := <-cmdDone
 		exitCode := 0
 		if cmdErr != nil {
@@ -146,9 +288,12 @@ func Launch(cfg *config.Config, mainModelID, fastModelID, heavyModelID string, p
 
 		// Track session end and return
 		sessionEnd := time.Now()
-		trackSession(cfg, mainModelID, fastModelID, heavyModelID, profileName, cwd, sessionStart, sessionEnd, exitCode)
+		trackSession(cfg, session, watcher, sessionEnd, exitCode)
+		runPostLaunchHook(hookInfo, sessionEnd, exitCode)
 
 		if exitCode != 0 {
+			// os.Exit skips deferred cleanup
+			cleanupProjectSettings(restoreSettings)
 			os.Exit(exitCode)
 		}
 		return nil
@@ -166,15 +311,41 @@ func Launch(cfg *config.Config, mainModelID, fastModelID, heavyModelID string, p
 
 		// Track session end and return
 		sessionEnd := time.Now()
-		trackSession(cfg, mainModelID, fastModelID, heavyModelID, profileName, cwd, sessionStart, sessionEnd, exitCode)
+		trackSession(cfg, session, watcher, sessionEnd, exitCode)
+		runPostLaunchHook(hookInfo, sessionEnd, exitCode)
 
 		if exitCode != 0 {
+			// os.Exit skips deferred cleanup
+			cleanupProjectSettings(restoreSettings)
 			os.Exit(exitCode)
 		}
 		return nil
 	}
 }
 
+// ValidateModels checks the models exist for the profile, the same checks Launch runs while
+// Claude Code starts, for callers that need the answer first
+func ValidateModels(cfg *config.Config, mainModelID, fastModelID, heavyModelID string) error {
+	switch cfg.ProfileType {
+	case "bedrock":
+		return aws.ValidateModels(cfg, mainModelID, fastModelID, heavyModelID)
+	case "vertex":
+		return vertex.ValidateModels(cfg.GCPProject, cfg.VertexRegion, mainModelID, fastModelID, heavyModelID)
+	}
+	env, err := ProfileEnv(cfg, mainModelID, fastModelID, heavyModelID)
+	if err != nil {
+		return err
+	}
+	return api.ValidateModels(cfg.BaseURL, envValue(env, "ANTHROPIC_API_KEY"), mainModelID, fastModelID, heavyModelID)
+}
+
+// cleanupProjectSettings restores the project settings file, warning on failure
+func cleanupProjectSettings(restore func() error) {
+	if err := restore(); err != nil {
+		fmt.Printf("Warning: failed to restore .claude/settings.local.json: %v\n", err)
+	}
+}
+
 // getCredentialsPath returns the path to the credentials file
 func getCredentialsPath() (string, error) {
 	homeDir, err := os.UserHomeDir()
@@ -219,30 +390,124 @@ func restoreCredentials() error {
 	return os.Rename(disabledPath, credPath)
 }
 
-func trackSession(cfg *config.Config, mainModelID, fastModelID, heavyModelID, profileName, cwd string, sessionStart, sessionEnd time.Time, exitCode int) {
+// trackSession records a finished session, then drops its journal entry
+// If recording fails the entry is kept, so the next invocation can try again
+func trackSession(cfg *config.Config, session usage.SessionInfo, watcher *monitoring.SessionWatcher, sessionEnd time.Time, exitCode int) {
+	session.EndTime = sessionEnd
+	session.ExitCode = exitCode
+
 	// Track usage after Claude Code exits
 	tracker, err := usage.NewTracker()
-	if err == nil {
-		// Track session with timing information
-		trackErr := tracker.TrackSession(usage.SessionInfo{
-			StartTime:           sessionStart,
-			EndTime:             sessionEnd,
-			ProfileName:         profileName,
-			WorkingDirectory:    cwd,
-			AWSProfile:          cfg.Profile,
-			Region:              cfg.Region,
-			CrossRegion:         cfg.CrossRegion,
-			Model:               cfg.Model,
-			ModelProfileID:      mainModelID,
-			FastModel:           cfg.FastModel,
-			FastModelProfileID:  fastModelID,
-			HeavyModel:          cfg.HeavyModel,
-			HeavyModelProfileID: heavyModelID,
-			ExitCode:            exitCode,
-		})
-		tracker.Close()
-		if trackErr != nil {
-			fmt.Printf("Warning: failed to track session: %v\n", trackErr)
+	if err != nil {
+		watcher.Detach()
+		return
+	}
+	defer tracker.Close()
+
+	id, anomalies, err := tracker.TrackSession(session)
+	if err != nil {
+		watcher.Detach()
+		fmt.Printf("Warning: failed to track session: %v\n", err)
+		return
+	}
+	watcher.Finish()
+	printAnomalies(anomalies)
+
+	// The session is recorded before asking for a note, so an unanswered prompt loses nothing
+	if session.Name != "" {
+		if note := promptSessionNote(session.Name); note != "" {
+			if err := tracker.SetSessionNote(id, note); err != nil {
+				fmt.Printf("Warning: %v\n", err)
+			}
+		}
+	}
+
+	// Syncing is best-effort; unsynced sessions go up with the next one
+	if _, err := tracker.SyncTeamUsage(session.ProfileName, cfg); err != nil {
+		fmt.Printf("Warning: failed to sync usage to %s: %v\n", cfg.TeamSync, err)
+	}
+}
+
+// promptSessionNote asks for a note on how a named session went, when there is a terminal to ask on
+// Skipping or cancelling the prompt leaves the session without a note
+func promptSessionNote(name string) string {
+	if !term.IsTerminal(int(os.Stdin.Fd())) {
+		return ""
+	}
+	note, err := interactive.PromptTextInput(
+		fmt.Sprintf("Add a note to session '%s' (enter to skip)", name),
+		"what got done, what's left",
+		"",
+	)
+	if err != nil {
+		return ""
+	}
+	return note
+}
+
+// recoverInterruptedSessions records journaled sessions whose clauderock was killed before tracking them
+func recoverInterruptedSessions() {
+	tracker, err := usage.NewTracker()
+	if err != nil {
+		return
+	}
+	defer tracker.Close()
+
+	recovered, err := tracker.RecoverSessions()
+	if err != nil {
+		fmt.Printf("Warning: failed to recover interrupted sessions: %v\n", err)
+	}
+	if recovered > 0 {
+		fmt.Printf("Recorded %d session(s) from clauderock runs that were closed before Claude exited\n\n", recovered)
+	}
+}
+
+// runPostLaunchHook tells plugins how the session ended
+func runPostLaunchHook(info plugins.LaunchInfo, end time.Time, exitCode int) {
+	info.End = end
+	info.ExitCode = &exitCode
+	plugins.RunHook(plugins.HookPostLaunch, info)
+}
+
+// printAnomalies warns about a session whose usage is far above the profile's baseline,
+// which usually means a runaway agent loop or an accidental huge paste
+func printAnomalies(anomalies []usage.Anomaly) {
+	if len(anomalies) == 0 {
+		return
+	}
+
+	fmt.Println("\n⚠ Unusual usage in this session:")
+	for _, a := range anomalies {
+		if a.Metric == "cost" {
+			fmt.Printf("  cost %s vs typical %s (%.1fσ above this profile's baseline)\n", currency.Format(a.Value), currency.Format(a.Mean), a.Sigma)
+		} else {
+			fmt.Printf("  %.0f tokens vs typical %.0f (%.1fσ above this profile's baseline)\n", a.Value, a.Mean, a.Sigma)
 		}
 	}
+	fmt.Println("  Check for runaway agent loops or accidentally pasted large context.")
+}
+
+// markVerified records that a profile's models passed validation
+// Claude is already running, so failures are silent; the next launch simply tries again
+func markVerified(profileName string, modelIDs ...string) {
+	mgr, err := profiles.NewManager()
+	if err != nil {
+		return
+	}
+	_ = mgr.MarkVerified(profileName, modelIDs...)
+}
+
+// checkLaunchDir enforces the profile's directory allowlist
+func checkLaunchDir(cfg *config.Config, profileName, cwd string) error {
+	if len(cfg.AllowedDirs) == 0 || (cwd != "" && cfg.DirAllowed(cwd)) {
+		return nil
+	}
+
+	message := fmt.Sprintf("profile '%s' is restricted to %s, but the current directory is %s",
+		profileName, strings.Join(cfg.AllowedDirs, ", "), cwd)
+	if cfg.DirPolicy == config.DirPolicyWarn {
+		fmt.Printf("Warning: %s\n\n", message)
+		return nil
+	}
+	return fmt.Errorf("%s\nSwitch profile with --clauderock-profile, or change the allowlist with: clauderock manage config set allowed-dirs <dir>,...", message)
 }
@@ -0,0 +1,163 @@
+package launcher
+
+import (
+	"bufio"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// gitInfo is the git checkout a session was launched in, for attributing sessions to projects
+type gitInfo struct {
+	Repo      string // "owner/name" from the origin remote, otherwise the checkout's directory name
+	RemoteURL string // Origin remote without any credentials in it; empty without one
+	Branch    string // Checked-out branch; empty when HEAD is detached
+}
+
+// detectGit describes the git checkout dir is in, or returns a zero gitInfo outside one
+// Worktrees are attributed to the repository they belong to, with their own branch
+// The files are read directly, so it works without git installed and costs no process start
+func detectGit(dir string) gitInfo {
+	if dir == "" {
+		return gitInfo{}
+	}
+	gitDir, commonDir, ok := findGitDir(dir)
+	if !ok {
+		return gitInfo{}
+	}
+
+	info := gitInfo{RemoteURL: stripCredentials(originURL(commonDir)), Branch: headBranch(gitDir)}
+	info.Repo = repoNameFromURL(info.RemoteURL)
+	if info.Repo == "" {
+		// A bare repository's git dir is the repository; otherwise it's the .git inside the checkout
+		if filepath.Base(commonDir) == ".git" {
+			info.Repo = filepath.Base(filepath.Dir(commonDir))
+		} else {
+			info.Repo = strings.TrimSuffix(filepath.Base(commonDir), ".git")
+		}
+	}
+	return info
+}
+
+// findGitDir walks up from dir to the nearest .git and returns the checkout's own git directory
+// and the repository's shared one, which differ for worktrees
+func findGitDir(dir string) (gitDir, commonDir string, ok bool) {
+	for {
+		path := filepath.Join(dir, ".git")
+		info, err := os.Stat(path)
+		if err == nil {
+			if info.IsDir() {
+				return path, path, true
+			}
+			return worktreeGitDirs(path)
+		}
+		parent := filepath.Dir(dir)
+		if parent == dir {
+			return "", "", false
+		}
+		dir = parent
+	}
+}
+
+// worktreeGitDirs resolves a worktree's .git file ("gitdir: <path>") to its git dir and the
+// main repository's
+func worktreeGitDirs(gitFile string) (gitDir, commonDir string, ok bool) {
+	data, err := os.ReadFile(gitFile)
+	if err != nil {
+		return "", "", false
+	}
+	gitDir, ok = strings.CutPrefix(strings.TrimSpace(string(data)), "gitdir:")
+	if !ok {
+		return "", "", false
+	}
+	gitDir = resolvePath(filepath.Dir(gitFile), strings.TrimSpace(gitDir))
+
+	// Linked worktrees point at .git/worktrees/<name>, whose commondir leads back to .git
+	if data, err := os.ReadFile(filepath.Join(gitDir, "commondir")); err == nil {
+		return gitDir, resolvePath(gitDir, strings.TrimSpace(string(data))), true
+	}
+	return gitDir, gitDir, true
+}
+
+// headBranch returns the branch a git dir's HEAD points at, or "" when it is detached
+func headBranch(gitDir string) string {
+	data, err := os.ReadFile(filepath.Join(gitDir, "HEAD"))
+	if err != nil {
+		return ""
+	}
+	ref, ok := strings.CutPrefix(strings.TrimSpace(string(data)), "ref:")
+	if !ok {
+		return ""
+	}
+	return strings.TrimPrefix(strings.TrimSpace(ref), "refs/heads/")
+}
+
+// resolvePath makes a path from a git file absolute, relative to the directory holding the file
+func resolvePath(base, path string) string {
+	if filepath.IsAbs(path) {
+		return filepath.Clean(path)
+	}
+	return filepath.Join(base, path)
+}
+
+// originURL returns the URL of the origin remote from a git dir's config, or "" without one
+func originURL(gitDir string) string {
+	f, err := os.Open(filepath.Join(gitDir, "config"))
+	if err != nil {
+		return ""
+	}
+	defer f.Close()
+
+	inOrigin := false
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if strings.HasPrefix(line, "[") {
+			inOrigin = line == `[remote "origin"]`
+			continue
+		}
+		if !inOrigin {
+			continue
+		}
+		if key, value, ok := strings.Cut(line, "="); ok && strings.TrimSpace(key) == "url" {
+			return strings.TrimSpace(value)
+		}
+	}
+	return ""
+}
+
+// stripCredentials removes a user and password or token from a remote URL, so they aren't stored
+// with sessions; scp-like remotes (git@host:owner/name) carry no credentials
+func stripCredentials(url string) string {
+	scheme, rest, ok := strings.Cut(url, "://")
+	if !ok {
+		return url
+	}
+	host, path, _ := strings.Cut(rest, "/")
+	if i := strings.LastIndex(host, "@"); i >= 0 {
+		host = host[i+1:]
+	}
+	if path == "" {
+		return scheme + "://" + host
+	}
+	return scheme + "://" + host + "/" + path
+}
+
+// repoNameFromURL returns "owner/name" from a remote URL in any of git's forms:
+// https://host/owner/name.git, git@host:owner/name.git, ssh://git@host/owner/name
+func repoNameFromURL(url string) string {
+	if url == "" {
+		return ""
+	}
+	path := url
+	if _, rest, ok := strings.Cut(url, "://"); ok {
+		_, path, _ = strings.Cut(rest, "/")
+	} else if _, rest, ok := strings.Cut(url, ":"); ok {
+		path = rest
+	}
+	parts := strings.Split(strings.Trim(strings.TrimSuffix(strings.TrimRight(path, "/"), ".git"), "/"), "/")
+	if len(parts) >= 2 {
+		return parts[len(parts)-2] + "/" + parts[len(parts)-1]
+	}
+	return parts[len(parts)-1]
+}
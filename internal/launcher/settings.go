@@ -0,0 +1,137 @@
+package launcher
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+)
+
+// secretEnv are the variables never written to settings.local.json: they reach Claude through
+// its process environment only, so a clauderock killed before restoring the file leaves no
+// credentials behind in the project
+var secretEnv = map[string]bool{
+	"ANTHROPIC_API_KEY":        true,
+	"ANTHROPIC_AUTH_TOKEN":     true,
+	"AWS_BEARER_TOKEN_BEDROCK": true,
+}
+
+// splitSecretEnv separates the variables in secretEnv from the rest
+func splitSecretEnv(vars []string) (plain, secrets []string) {
+	for _, kv := range vars {
+		name, _, _ := strings.Cut(kv, "=")
+		if secretEnv[name] {
+			secrets = append(secrets, kv)
+		} else {
+			plain = append(plain, kv)
+		}
+	}
+	return plain, secrets
+}
+
+// writeProjectSettingsEnv merges env vars (KEY=VALUE) into the env block of
+// <dir>/.claude/settings.local.json so wrappers and IDE integrations that bypass
+// clauderock's process environment still pick them up
+// Secrets are passed in the process environment instead: any the file sets are taken out
+// for the session, since they would override it, and copies of them that an earlier
+// clauderock was killed before removing are dropped for good
+// Returns a restore function that puts the file back as it was; it is safe to call more than once
+func writeProjectSettingsEnv(dir string, vars, secrets []string) (func() error, error) {
+	claudeDir := filepath.Join(dir, ".claude")
+	settingsPath := filepath.Join(claudeDir, "settings.local.json")
+
+	// Remember the original state for restoration
+	original, readErr := os.ReadFile(settingsPath)
+	existed := readErr == nil
+	if readErr != nil && !os.IsNotExist(readErr) {
+		return nil, fmt.Errorf("failed to read %s: %w", settingsPath, readErr)
+	}
+	_, statErr := os.Stat(claudeDir)
+	createdDir := os.IsNotExist(statErr)
+
+	settings := make(map[string]interface{})
+	if existed && len(strings.TrimSpace(string(original))) > 0 {
+		if err := json.Unmarshal(original, &settings); err != nil {
+			return nil, fmt.Errorf("failed to parse %s: %w", settingsPath, err)
+		}
+	}
+
+	env, _ := settings["env"].(map[string]interface{})
+	if env == nil {
+		env = make(map[string]interface{})
+	}
+
+	stale := false
+	for _, kv := range secrets {
+		name, value, _ := strings.Cut(kv, "=")
+		if env[name] == value {
+			stale = true
+		}
+		delete(env, name)
+	}
+	if stale {
+		if original, readErr = withoutStaleSecrets(original, secrets); readErr != nil {
+			return nil, fmt.Errorf("failed to remove credentials left in %s: %w", settingsPath, readErr)
+		}
+	}
+
+	for _, kv := range vars {
+		if name, value, ok := strings.Cut(kv, "="); ok {
+			env[name] = value
+		}
+	}
+	settings["env"] = env
+
+	data, err := json.MarshalIndent(settings, "", "  ")
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal settings: %w", err)
+	}
+
+	if err := os.MkdirAll(claudeDir, 0755); err != nil {
+		return nil, fmt.Errorf("failed to create %s: %w", claudeDir, err)
+	}
+	if err := os.WriteFile(settingsPath, data, 0600); err != nil {
+		return nil, fmt.Errorf("failed to write %s: %w", settingsPath, err)
+	}
+
+	var once sync.Once
+	var restoreErr error
+	restore := func() error {
+		once.Do(func() {
+			if existed {
+				restoreErr = os.WriteFile(settingsPath, original, 0600)
+				return
+			}
+			if err := os.Remove(settingsPath); err != nil && !os.IsNotExist(err) {
+				restoreErr = err
+				return
+			}
+			if createdDir {
+				// Only removes the directory if nothing else was written to it meanwhile
+				os.Remove(claudeDir)
+			}
+		})
+		return restoreErr
+	}
+
+	return restore, nil
+}
+
+// withoutStaleSecrets returns the settings file content without the secrets it holds with
+// exactly the values clauderock would set, which only an interrupted earlier launch puts there
+func withoutStaleSecrets(original []byte, secrets []string) ([]byte, error) {
+	var settings map[string]interface{}
+	if err := json.Unmarshal(original, &settings); err != nil {
+		return nil, err
+	}
+	env, _ := settings["env"].(map[string]interface{})
+	for _, kv := range secrets {
+		name, value, _ := strings.Cut(kv, "=")
+		if env[name] == value {
+			delete(env, name)
+		}
+	}
+	return json.MarshalIndent(settings, "", "  ")
+}
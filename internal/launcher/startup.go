@@ -0,0 +1,68 @@
+package launcher
+
+import (
+	"fmt"
+	"io"
+	"sort"
+	"sync"
+	"time"
+)
+
+// startupTarget is the overhead clauderock aims to add before Claude Code starts
+const startupTarget = 300 * time.Millisecond
+
+// StartupProfile records how long the steps before Claude Code starts take, for --clauderock-profile-startup
+// Steps may run concurrently; a nil profile records nothing
+type StartupProfile struct {
+	start time.Time
+
+	mu    sync.Mutex
+	steps []startupStep
+}
+
+type startupStep struct {
+	name  string
+	start time.Time
+	took  time.Duration
+}
+
+// NewStartupProfile starts a profile at start, normally when clauderock began running
+func NewStartupProfile(start time.Time) *StartupProfile {
+	return &StartupProfile{start: start}
+}
+
+// Track records a step that began at since and has just finished
+func (p *StartupProfile) Track(name string, since time.Time) {
+	if p == nil {
+		return
+	}
+	p.mu.Lock()
+	p.steps = append(p.steps, startupStep{name: name, start: since, took: time.Since(since)})
+	p.mu.Unlock()
+}
+
+// Report prints the recorded steps in the order they began, with the overhead so far
+func (p *StartupProfile) Report(w io.Writer) {
+	if p == nil {
+		return
+	}
+	total := time.Since(p.start)
+
+	p.mu.Lock()
+	steps := append([]startupStep{}, p.steps...)
+	p.mu.Unlock()
+	sort.SliceStable(steps, func(i, j int) bool { return steps[i].start.Before(steps[j].start) })
+
+	fmt.Fprintf(w, "Startup profile (%s before Claude Code starts, target %s):\n", formatStartupDuration(total), formatStartupDuration(startupTarget))
+	for _, s := range steps {
+		fmt.Fprintf(w, "  %8s  %8s  %s\n", "+"+formatStartupDuration(s.start.Sub(p.start)), formatStartupDuration(s.took), s.name)
+	}
+	if total > startupTarget {
+		fmt.Fprintf(w, "  over target by %s\n", formatStartupDuration(total-startupTarget))
+	}
+	fmt.Fprintln(w)
+}
+
+func formatStartupDuration(d time.Duration) string {
+	return fmt.Sprintf("%.1fms", float64(d.Microseconds())/1000)
+}
@@ -106,7 +106,7 @@ func (m *Manager) migrateToV040(profileName string, cfg *config.Config, saver Pr
 
 	// Resolve models to full profile IDs (skip empty ones)
 	if cfg.Model != "" && !modelIsFullID {
-		fullID, err := aws.ResolveModelToProfileID(cfg.Profile, cfg.Region, cfg.CrossRegion, cfg.Model)
+		fullID, err := aws.ResolveModelToProfileID(cfg.AWSProfile(), cfg.Region, cfg.CrossRegion, cfg.Model)
 		if err != nil {
 			return fmt.Errorf("failed to resolve main model: %w", err)
 		}
@@ -114,7 +114,7 @@ func (m *Manager) migrateToV040(profileName string, cfg *config.Config, saver Pr
 	}
 
 	if cfg.FastModel != "" && !fastModelIsFullID {
-		fullID, err := aws.ResolveModelToProfileID(cfg.Profile, cfg.Region, cfg.CrossRegion, cfg.FastModel)
+		fullID, err := aws.ResolveModelToProfileID(cfg.AWSProfile(), cfg.Region, cfg.CrossRegion, cfg.FastModel)
 		if err != nil {
 			return fmt.Errorf("failed to resolve fast model: %w", err)
 		}
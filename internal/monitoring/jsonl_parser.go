@@ -2,22 +2,34 @@ package monitoring
 
 import (
 	"bufio"
+	"bytes"
 	"encoding/json"
 	"fmt"
+	"io"
 	"os"
 	"path/filepath"
+	"regexp"
 	"sort"
 	"strings"
 	"time"
 )
 
+// IdleThreshold is the gap between API calls above which the time in between counts as idle
+const IdleThreshold = 5 * time.Minute
+
 // ClaudeMessage represents a message from Claude Code's JSONL file
 type ClaudeMessage struct {
-	Timestamp string `json:"timestamp"`
-	Type      string `json:"type"`
-	SessionID string `json:"sessionId"`
-	Message   struct {
-		Model string `json:"model"`
+	Timestamp         string `json:"timestamp"`
+	Type              string `json:"type"`
+	SessionID         string `json:"sessionId"`
+	IsAPIErrorMessage bool   `json:"isApiErrorMessage"`
+	TTFTMs            int64  `json:"ttftMs"` // Time to first token, when Claude Code records it
+	Message           struct {
+		Model   string `json:"model"`
+		Content []struct {
+			Type string `json:"type"`
+			Text string `json:"text"`
+		} `json:"content"`
 		Usage struct {
 			InputTokens              int64 `json:"input_tokens"`
 			OutputTokens             int64 `json:"output_tokens"`
@@ -35,6 +47,7 @@ type APICall struct {
 	OutputTokens        int64
 	CacheReadTokens     int64
 	CacheCreationTokens int64
+	Latency             time.Duration // Time to first token; 0 when it could not be derived
 }
 
 // SessionMetrics contains aggregated metrics for a session
@@ -52,22 +65,20 @@ type SessionMetrics struct {
 	PeakRPM             float64
 	P95RPM              float64
 	CacheHitRate        float64
-	APICalls            []APICall
+	ThrottleEvents      int           // API errors caused by rate limiting or throttling
+	ActiveDuration      time.Duration // Time between API calls, leaving out idle gaps
+	AvgLatencyMs        float64       // First-token latency over the calls where it is known
+	P95LatencyMs        float64
+	LatencySamples      int
 }
 
 // FindSessionJSONL finds the JSONL file for a session based on working directory and start time
 func FindSessionJSONL(workingDir string, sessionStart time.Time) (string, error) {
-	home, err := os.UserHomeDir()
+	projectDir, err := projectDirFor(workingDir)
 	if err != nil {
-		return "", fmt.Errorf("failed to get home directory: %w", err)
+		return "", err
 	}
 
-	// Encode working directory to Claude Code's format
-	// Replace "/" with "-" (keep the leading dash - it represents root "/")
-	encodedDir := strings.ReplaceAll(workingDir, "/", "-")
-
-	projectDir := filepath.Join(home, ".claude", "projects", encodedDir)
-
 	// Check if directory exists
 	if _, err := os.Stat(projectDir); os.IsNotExist(err) {
 		return "", fmt.Errorf("project directory not found: %s", projectDir)
@@ -142,188 +153,396 @@ func FindSessionJSONL(workingDir string, sessionStart time.Time) (string, error)
 	return filesWithTime[0].path, nil
 }
 
+// projectsDir returns the directory Claude Code keeps its per-project session logs in
+func projectsDir() (string, error) {
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return "", fmt.Errorf("failed to get home directory: %w", err)
+	}
+	return filepath.Join(home, ".claude", "projects"), nil
+}
+
+// projectDirFor returns the session log directory of a working directory
+func projectDirFor(workingDir string) (string, error) {
+	dir, err := projectsDir()
+	if err != nil {
+		return "", err
+	}
+
+	// Encode working directory to Claude Code's format
+	// Replace "/" with "-" (keep the leading dash - it represents root "/")
+	encodedDir := strings.ReplaceAll(workingDir, "/", "-")
+
+	return filepath.Join(dir, encodedDir), nil
+}
+
 // ParseSessionJSONL parses a JSONL file and extracts session metrics
 func ParseSessionJSONL(jsonlPath string) (*SessionMetrics, error) {
+	return StreamSessionJSONL(jsonlPath, nil)
+}
+
+// StreamSessionJSONL parses a JSONL file like ParseSessionJSONL, passing each API call to
+// onCall as it is read when onCall is set. The file is streamed a line at a time and the
+// calls aren't kept, so memory use doesn't grow with the length of the session
+func StreamSessionJSONL(jsonlPath string, onCall func(APICall)) (*SessionMetrics, error) {
 	file, err := os.Open(jsonlPath)
 	if err != nil {
 		return nil, fmt.Errorf("failed to open JSONL file: %w", err)
 	}
 	defer file.Close()
 
-	metrics := &SessionMetrics{
-		APICalls: []APICall{},
-	}
+	metrics := &SessionMetrics{}
 
 	// Extract session UUID from filename
 	base := filepath.Base(jsonlPath)
 	metrics.SessionUUID = strings.TrimSuffix(base, ".jsonl")
 
-	scanner := bufio.NewScanner(file)
-	for scanner.Scan() {
-		var msg ClaudeMessage
-		if err := json.Unmarshal(scanner.Bytes(), &msg); err != nil {
-			// Skip malformed lines
-			continue
+	var parser lineParser
+	var totals sessionAggregator
+	_, err = readLines(file, true, func(line []byte) {
+		apiCall, isCall, throttled := parser.parse(line)
+		if throttled {
+			metrics.ThrottleEvents++
 		}
-
-		// Only process assistant messages (these have usage data)
-		if msg.Type != "assistant" {
-			continue
+		if isCall {
+			totals.add(apiCall)
+			if onCall != nil {
+				onCall(apiCall)
+			}
 		}
+	})
+	if err != nil {
+		return nil, fmt.Errorf("error reading JSONL file: %w", err)
+	}
 
-		// Parse timestamp
-		timestamp, err := time.Parse(time.RFC3339, msg.Timestamp)
-		if err != nil {
+	// Calculate aggregated metrics
+	totals.finish(metrics)
+
+	return metrics, nil
+}
+
+// maxLineBytes bounds the size of a JSONL line that is parsed; longer lines are skipped
+// so one enormous tool result can't exhaust memory or fail the whole session
+const maxLineBytes = 16 << 20
+
+// readLines calls fn for every complete line read from r, reusing one buffer, and returns
+// the number of bytes consumed by those lines. An unterminated last line is only passed
+// (and consumed) when final is set; otherwise it may still be being written
+func readLines(r io.Reader, final bool, fn func(line []byte)) (int64, error) {
+	reader := bufio.NewReaderSize(r, 64*1024)
+	var consumed int64
+	var long []byte // A line longer than the reader's buffer, assembled from its fragments
+	size := 0       // Bytes of the current line read so far
+	for {
+		fragment, err := reader.ReadSlice('\n')
+		size += len(fragment)
+		if err == bufio.ErrBufferFull {
+			if size <= maxLineBytes {
+				long = append(long, fragment...)
+			} else {
+				long = nil
+			}
 			continue
 		}
+		if err != nil && err != io.EOF {
+			return consumed, err
+		}
+		if err == io.EOF && (!final || size == 0) {
+			return consumed, nil
+		}
 
-		// Extract API call data
-		apiCall := APICall{
-			Timestamp:           timestamp,
-			Model:               msg.Message.Model,
-			InputTokens:         msg.Message.Usage.InputTokens,
-			OutputTokens:        msg.Message.Usage.OutputTokens,
-			CacheReadTokens:     msg.Message.Usage.CacheReadInputTokens,
-			CacheCreationTokens: msg.Message.Usage.CacheCreationInputTokens,
+		line := fragment
+		if len(long) > 0 {
+			long = append(long, fragment...)
+			line = long
 		}
+		if size <= maxLineBytes {
+			fn(bytes.TrimRight(line, "\r\n"))
+		}
+		consumed += int64(size)
 
-		metrics.APICalls = append(metrics.APICalls, apiCall)
+		size = 0
+		long = long[:0]
+		if cap(long) > 1<<20 {
+			long = nil // Don't hold on to a huge line's buffer
+		}
+		if err == io.EOF {
+			return consumed, nil
+		}
 	}
+}
 
-	if err := scanner.Err(); err != nil {
-		return nil, fmt.Errorf("error reading JSONL file: %w", err)
-	}
+// lineParser turns JSONL lines into API calls, carrying state between lines
+type lineParser struct {
+	// Times of the user messages (prompts and tool results) not yet answered, by line UUID,
+	// used to derive latency when the JSONL has no ttftMs field. A response's first line
+	// names the message it answers as its parent, so it is timed from that request even
+	// when other lines, such as a subagent's, were written in between
+	pendingRequests map[string]time.Time
+	// Time of the last user message, for logs written before lines had UUIDs
+	pendingRequest time.Time
+}
 
-	if len(metrics.APICalls) == 0 {
-		return metrics, nil // Empty session, no API calls
-	}
+// maxPendingRequests bounds the unanswered user messages remembered; once that many pile
+// up, they are forgotten
+const maxPendingRequests = 256
+
+// recordHeader is decoded from every user or assistant line before anything else,
+// so user lines (often large tool results) never have their content decoded
+type recordHeader struct {
+	Timestamp         string `json:"timestamp"`
+	Type              string `json:"type"`
+	UUID              string `json:"uuid"`
+	ParentUUID        string `json:"parentUuid"`
+	IsMeta            bool   `json:"isMeta"`
+	IsAPIErrorMessage bool   `json:"isApiErrorMessage"`
+}
 
-	// Calculate aggregated metrics
-	calculateMetrics(metrics)
+// requestTime returns when the request a response line answers was sent, and forgets it so
+// the response's later lines aren't timed again
+func (p *lineParser) requestTime(header recordHeader) (time.Time, bool) {
+	if header.ParentUUID == "" {
+		sent := p.pendingRequest
+		p.pendingRequest = time.Time{}
+		return sent, !sent.IsZero()
+	}
+	sent, ok := p.pendingRequests[header.ParentUUID]
+	if ok {
+		// Only the answered request is forgotten: a subagent's response can arrive while
+		// the main conversation's request is still waiting for its own
+		delete(p.pendingRequests, header.ParentUUID)
+	}
+	return sent, ok
+}
 
-	return metrics, nil
+// assistantRecord is the part of an assistant line an API call is built from; its
+// content is only decoded for API errors, to check them for throttling
+type assistantRecord struct {
+	TTFTMs  int64 `json:"ttftMs"`
+	Message struct {
+		Model string `json:"model"`
+		Usage struct {
+			InputTokens              int64 `json:"input_tokens"`
+			OutputTokens             int64 `json:"output_tokens"`
+			CacheReadInputTokens     int64 `json:"cache_read_input_tokens"`
+			CacheCreationInputTokens int64 `json:"cache_creation_input_tokens"`
+		} `json:"usage"`
+	} `json:"message"`
 }
 
-func calculateMetrics(metrics *SessionMetrics) {
-	if len(metrics.APICalls) == 0 {
-		return
+var (
+	userMarker      = []byte(`"user"`)
+	assistantMarker = []byte(`"assistant"`)
+)
+
+// parse extracts the API call recorded on a line, and reports whether the line
+// is an API error caused by throttling
+func (p *lineParser) parse(line []byte) (apiCall APICall, isCall bool, throttled bool) {
+	// Summaries, snapshots and other records never name either role, so skip them unparsed
+	if !bytes.Contains(line, assistantMarker) && !bytes.Contains(line, userMarker) {
+		return apiCall, false, false
 	}
 
-	// Calculate totals
-	metrics.TotalRequests = len(metrics.APICalls)
-	for _, call := range metrics.APICalls {
-		metrics.TotalInputTokens += call.InputTokens
-		metrics.TotalOutputTokens += call.OutputTokens
-		metrics.CacheReadTokens += call.CacheReadTokens
-		metrics.CacheCreationTokens += call.CacheCreationTokens
+	var header recordHeader
+	if err := json.Unmarshal(line, &header); err != nil {
+		// Skip malformed lines
+		return apiCall, false, false
 	}
 
-	// Calculate session duration from first to last API call
-	firstCall := metrics.APICalls[0].Timestamp
-	lastCall := metrics.APICalls[len(metrics.APICalls)-1].Timestamp
-	duration := lastCall.Sub(firstCall)
-	durationMinutes := duration.Minutes()
+	if header.Type == "user" {
+		// Meta lines, such as command output, are written without sending a request
+		if timestamp, err := time.Parse(time.RFC3339, header.Timestamp); err == nil && !header.IsMeta {
+			if header.UUID == "" {
+				p.pendingRequest = timestamp
+			} else {
+				if p.pendingRequests == nil || len(p.pendingRequests) >= maxPendingRequests {
+					p.pendingRequests = make(map[string]time.Time)
+				}
+				p.pendingRequests[header.UUID] = timestamp
+			}
+		}
+		return apiCall, false, false
+	}
 
-	// Handle very short sessions
-	if durationMinutes < 0.01 {
-		durationMinutes = 0.01
+	// Only process assistant messages (these have usage data)
+	if header.Type != "assistant" {
+		return apiCall, false, false
 	}
 
-	// Calculate TPM using AWS Bedrock's formula: Input + Output + CacheCreation
-	// (CacheRead tokens are NOT counted - they save you tokens!)
-	totalTokens := metrics.TotalInputTokens + metrics.TotalOutputTokens + metrics.CacheCreationTokens
+	if header.IsAPIErrorMessage {
+		var msg ClaudeMessage
+		throttled = json.Unmarshal(line, &msg) == nil && isThrottleError(msg)
+	}
 
-	// Calculate average TPM and RPM
-	metrics.AvgTPM = float64(totalTokens) / durationMinutes
-	metrics.AvgRPM = float64(metrics.TotalRequests) / durationMinutes
+	// Parse timestamp
+	timestamp, err := time.Parse(time.RFC3339, header.Timestamp)
+	if err != nil {
+		return apiCall, false, throttled
+	}
 
-	// Calculate peak and P95 TPM/RPM
-	metrics.PeakTPM, metrics.P95TPM = calculatePeakAndP95Tokens(metrics.APICalls)
-	metrics.PeakRPM, metrics.P95RPM = calculatePeakAndP95Requests(metrics.APICalls)
+	var record assistantRecord
+	if err := json.Unmarshal(line, &record); err != nil {
+		return apiCall, false, throttled
+	}
 
-	// Calculate cache hit rate
-	totalInputTokensIncludingCache := metrics.TotalInputTokens + metrics.CacheReadTokens
-	if totalInputTokensIncludingCache > 0 {
-		metrics.CacheHitRate = float64(metrics.CacheReadTokens) / float64(totalInputTokensIncludingCache) * 100.0
+	// Extract API call data
+	apiCall = APICall{
+		Timestamp:           timestamp,
+		Model:               record.Message.Model,
+		InputTokens:         record.Message.Usage.InputTokens,
+		OutputTokens:        record.Message.Usage.OutputTokens,
+		CacheReadTokens:     record.Message.Usage.CacheReadInputTokens,
+		CacheCreationTokens: record.Message.Usage.CacheCreationInputTokens,
 	}
+
+	// A response is split over several assistant lines; only the first one, whose parent
+	// is the request, carries its latency
+	sent, answered := p.requestTime(header)
+	if record.TTFTMs > 0 {
+		apiCall.Latency = time.Duration(record.TTFTMs) * time.Millisecond
+	} else if answered && !header.IsAPIErrorMessage && timestamp.After(sent) {
+		apiCall.Latency = timestamp.Sub(sent)
+	}
+
+	return apiCall, true, throttled
 }
 
-// calculatePeakAndP95Tokens calculates peak and P95 TPM using 1-minute rolling windows
-func calculatePeakAndP95Tokens(calls []APICall) (float64, float64) {
-	if len(calls) == 0 {
-		return 0, 0
+// throttleErrorCodes are the error codes of throttled requests: those of Bedrock's API errors
+// and the Anthropic API's error type. Claude Code logs API errors as text, so these are looked
+// for in it rather than matched as errors
+var throttleErrorCodes = []string{"ThrottlingException", "TooManyRequestsException", "rate_limit_error"}
+
+// apiErrorStatus matches the HTTP status Claude Code starts an API error with,
+// e.g. "API Error: 429 {...}" or "API Error (429 Too many requests...)"
+var apiErrorStatus = regexp.MustCompile(`^API Error:?\s*\(?(\d{3})\b`)
+
+// isThrottleError reports whether an API error message was caused by rate limiting: its status
+// is 429 or it carries a throttling error code. Free text such as a quoted prompt isn't matched
+func isThrottleError(msg ClaudeMessage) bool {
+	for _, c := range msg.Message.Content {
+		if m := apiErrorStatus.FindStringSubmatch(c.Text); m != nil && m[1] == "429" {
+			return true
+		}
+		for _, code := range throttleErrorCodes {
+			if strings.Contains(c.Text, code) {
+				return true
+			}
+		}
 	}
+	return false
+}
 
-	// Group API calls into 1-minute buckets
-	buckets := make(map[int64]int64)
-	for _, call := range calls {
-		bucket := call.Timestamp.Unix() / 60 // 1-minute buckets
-		// AWS formula: Input + Output + CacheCreation (CacheRead tokens don't count)
-		tokens := call.InputTokens + call.OutputTokens + call.CacheCreationTokens
-		buckets[bucket] += tokens
+// sessionAggregator accumulates session metrics one API call at a time, keeping only
+// per-minute buckets and latencies rather than the calls themselves
+type sessionAggregator struct {
+	requests            int
+	inputTokens         int64
+	outputTokens        int64
+	cacheReadTokens     int64
+	cacheCreationTokens int64
+	first, last         time.Time
+	active              time.Duration
+	tokenBuckets        map[int64]int64 // Minute -> TPM tokens
+	requestBuckets      map[int64]int   // Minute -> requests
+	latenciesMs         []float64
+}
+
+// add counts one API call; calls are expected in the order they were made
+func (a *sessionAggregator) add(call APICall) {
+	if a.requests == 0 {
+		a.first = call.Timestamp
+		a.tokenBuckets = make(map[int64]int64)
+		a.requestBuckets = make(map[int64]int)
+	} else if gap := call.Timestamp.Sub(a.last); gap > 0 && gap <= IdleThreshold {
+		// Gaps longer than IdleThreshold count as idle
+		a.active += gap
 	}
+	a.last = call.Timestamp
+
+	a.requests++
+	a.inputTokens += call.InputTokens
+	a.outputTokens += call.OutputTokens
+	a.cacheReadTokens += call.CacheReadTokens
+	a.cacheCreationTokens += call.CacheCreationTokens
+
+	// 1-minute buckets, using AWS Bedrock's TPM formula: Input + Output + CacheCreation
+	// (CacheRead tokens are NOT counted - they save you tokens!)
+	bucket := call.Timestamp.Unix() / 60
+	a.tokenBuckets[bucket] += call.InputTokens + call.OutputTokens + call.CacheCreationTokens
+	a.requestBuckets[bucket]++
 
-	// Extract bucket values
-	var values []float64
-	for _, tokens := range buckets {
-		values = append(values, float64(tokens))
+	if call.Latency > 0 {
+		a.latenciesMs = append(a.latenciesMs, float64(call.Latency.Milliseconds()))
 	}
+}
 
-	if len(values) == 0 {
-		return 0, 0
+// finish fills in the aggregated metrics
+func (a *sessionAggregator) finish(metrics *SessionMetrics) {
+	if a.requests == 0 {
+		return // Empty session, no API calls
 	}
 
-	// Sort for P95 calculation
-	sort.Float64s(values)
+	// Calculate totals
+	metrics.TotalRequests = a.requests
+	metrics.TotalInputTokens = a.inputTokens
+	metrics.TotalOutputTokens = a.outputTokens
+	metrics.CacheReadTokens = a.cacheReadTokens
+	metrics.CacheCreationTokens = a.cacheCreationTokens
+	metrics.ActiveDuration = a.active
 
-	// Peak is the max value
-	peak := values[len(values)-1]
+	// Calculate session duration from first to last API call
+	durationMinutes := a.last.Sub(a.first).Minutes()
 
-	// P95 is the 95th percentile
-	p95Index := int(float64(len(values)) * 0.95)
-	if p95Index >= len(values) {
-		p95Index = len(values) - 1
+	// Handle very short sessions
+	if durationMinutes < 0.01 {
+		durationMinutes = 0.01
 	}
-	p95 := values[p95Index]
 
-	return peak, p95
-}
+	// Calculate average TPM and RPM
+	totalTokens := a.inputTokens + a.outputTokens + a.cacheCreationTokens
+	metrics.AvgTPM = float64(totalTokens) / durationMinutes
+	metrics.AvgRPM = float64(a.requests) / durationMinutes
 
-// calculatePeakAndP95Requests calculates peak and P95 RPM using 1-minute rolling windows
-func calculatePeakAndP95Requests(calls []APICall) (float64, float64) {
-	if len(calls) == 0 {
-		return 0, 0
+	// Calculate peak and P95 TPM/RPM
+	tokens := make([]float64, 0, len(a.tokenBuckets))
+	for _, t := range a.tokenBuckets {
+		tokens = append(tokens, float64(t))
 	}
-
-	// Group API calls into 1-minute buckets
-	buckets := make(map[int64]int)
-	for _, call := range calls {
-		bucket := call.Timestamp.Unix() / 60 // 1-minute buckets
-		buckets[bucket]++
+	requests := make([]float64, 0, len(a.requestBuckets))
+	for _, r := range a.requestBuckets {
+		requests = append(requests, float64(r))
+	}
+	metrics.PeakTPM, metrics.P95TPM = PeakAndP95(tokens)
+	metrics.PeakRPM, metrics.P95RPM = PeakAndP95(requests)
+
+	// First-token latency over the calls where it is known
+	if len(a.latenciesMs) > 0 {
+		var sum float64
+		for _, v := range a.latenciesMs {
+			sum += v
+		}
+		metrics.AvgLatencyMs = sum / float64(len(a.latenciesMs))
+		_, metrics.P95LatencyMs = PeakAndP95(a.latenciesMs)
+		metrics.LatencySamples = len(a.latenciesMs)
 	}
 
-	// Extract bucket values
-	var values []float64
-	for _, count := range buckets {
-		values = append(values, float64(count))
+	// Calculate cache hit rate
+	totalInputTokensIncludingCache := a.inputTokens + a.cacheReadTokens
+	if totalInputTokensIncludingCache > 0 {
+		metrics.CacheHitRate = float64(a.cacheReadTokens) / float64(totalInputTokensIncludingCache) * 100.0
 	}
+}
 
+// PeakAndP95 returns the largest value and the 95th percentile, sorting values in place
+func PeakAndP95(values []float64) (float64, float64) {
 	if len(values) == 0 {
 		return 0, 0
 	}
-
-	// Sort for P95 calculation
 	sort.Float64s(values)
 
-	// Peak is the max value
-	peak := values[len(values)-1]
-
-	// P95 is the 95th percentile
 	p95Index := int(float64(len(values)) * 0.95)
 	if p95Index >= len(values) {
 		p95Index = len(values) - 1
 	}
-	p95 := values[p95Index]
-
-	return peak, p95
+	return values[len(values)-1], values[p95Index]
 }
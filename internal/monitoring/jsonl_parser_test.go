@@ -0,0 +1,196 @@
+package monitoring
+
+import (
+	"bufio"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+// writeTranscript writes a session log of calls prompt and response pairs, each prompt
+// carrying a tool result of resultBytes, like a long agentic session
+func writeTranscript(tb testing.TB, calls, resultBytes int) string {
+	tb.Helper()
+
+	path := filepath.Join(tb.TempDir(), "session.jsonl")
+	file, err := os.Create(path)
+	if err != nil {
+		tb.Fatal(err)
+	}
+	defer file.Close()
+
+	w := bufio.NewWriter(file)
+	result := strings.Repeat("x", resultBytes)
+	start := time.Date(2025, 1, 1, 9, 0, 0, 0, time.UTC)
+	for i := 0; i < calls; i++ {
+		at := start.Add(time.Duration(i) * 10 * time.Second)
+		fmt.Fprintf(w, `{"type":"user","timestamp":%q,"message":{"role":"user","content":[{"type":"tool_result","content":%q}]}}`+"\n",
+			at.Format(time.RFC3339), result)
+		fmt.Fprintf(w, `{"type":"assistant","timestamp":%q,"message":{"model":"claude-sonnet-4-5","content":[{"type":"text","text":"done"}],"usage":{"input_tokens":1200,"output_tokens":350,"cache_read_input_tokens":40000,"cache_creation_input_tokens":800}}}`+"\n",
+			at.Add(2*time.Second).Format(time.RFC3339))
+		if i%50 == 0 {
+			fmt.Fprintln(w, `{"type":"summary","summary":"checkpoint"}`)
+		}
+	}
+	if err := w.Flush(); err != nil {
+		tb.Fatal(err)
+	}
+	return path
+}
+
+func BenchmarkParseSessionJSONL(b *testing.B) {
+	path := writeTranscript(b, 20000, 4096)
+	info, err := os.Stat(path)
+	if err != nil {
+		b.Fatal(err)
+	}
+
+	b.SetBytes(info.Size())
+	b.ReportAllocs()
+	for b.Loop() {
+		metrics, err := ParseSessionJSONL(path)
+		if err != nil {
+			b.Fatal(err)
+		}
+		if metrics.TotalRequests != 20000 {
+			b.Fatalf("TotalRequests = %d, want 20000", metrics.TotalRequests)
+		}
+	}
+}
+
+// Line builders for hand-written transcripts; at is seconds after 09:00
+var transcriptStart = time.Date(2025, 1, 1, 9, 0, 0, 0, time.UTC)
+
+func stamp(at int) string {
+	return transcriptStart.Add(time.Duration(at) * time.Second).Format(time.RFC3339)
+}
+
+func userLine(uuid string, at int) string {
+	return fmt.Sprintf(`{"type":"user","uuid":%q,"timestamp":%q,"message":{"role":"user","content":"hi"}}`, uuid, stamp(at))
+}
+
+func assistantLine(uuid, parent string, at int, input, output, cacheRead, cacheCreation int64) string {
+	return fmt.Sprintf(`{"type":"assistant","uuid":%q,"parentUuid":%q,"timestamp":%q,"message":{"model":"claude-sonnet-4-5","content":[{"type":"text","text":"ok"}],"usage":{"input_tokens":%d,"output_tokens":%d,"cache_read_input_tokens":%d,"cache_creation_input_tokens":%d}}}`,
+		uuid, parent, stamp(at), input, output, cacheRead, cacheCreation)
+}
+
+func apiErrorLine(at int, text string) string {
+	return fmt.Sprintf(`{"type":"assistant","isApiErrorMessage":true,"timestamp":%q,"message":{"model":"<synthetic>","content":[{"type":"text","text":%q}],"usage":{}}}`,
+		stamp(at), text)
+}
+
+func TestParseSessionJSONL(t *testing.T) {
+	tests := []struct {
+		name  string
+		lines []string
+		want  SessionMetrics // Only the fields below are compared
+	}{
+		{
+			name: "token totals",
+			lines: []string{
+				userLine("u1", 0),
+				assistantLine("a1", "u1", 1, 100, 20, 1000, 50),
+				userLine("u2", 30),
+				assistantLine("a2", "u2", 31, 200, 40, 3000, 0),
+			},
+			want: SessionMetrics{TotalRequests: 2, TotalInputTokens: 300, TotalOutputTokens: 60,
+				CacheReadTokens: 4000, CacheCreationTokens: 50, ActiveDuration: 30 * time.Second,
+				AvgLatencyMs: 1000, LatencySamples: 2},
+		},
+		{
+			name: "non-assistant and malformed lines are skipped",
+			lines: []string{
+				`{"type":"summary","summary":"assistant checkpoint"}`,
+				`not json at all`,
+				`{"type":"assistant","timestamp":`,
+				`{"type":"system","content":"the assistant said"}`,
+				`{"type":"assistant","timestamp":"yesterday","message":{"usage":{"input_tokens":999}}}`,
+				userLine("u1", 0),
+				assistantLine("a1", "", 60, 10, 5, 0, 0),
+			},
+			want: SessionMetrics{TotalRequests: 1, TotalInputTokens: 10, TotalOutputTokens: 5},
+		},
+		{
+			name: "throttle counting",
+			lines: []string{
+				apiErrorLine(0, `API Error: 429 {"type":"error","error":{"type":"rate_limit_error"}}`),
+				apiErrorLine(10, `API Error (429 Too many requests, please wait before trying again.)`),
+				apiErrorLine(20, `API Error: 400 ThrottlingException: Too many tokens, please wait`),
+				apiErrorLine(30, `API Error: 500 {"type":"error","error":{"type":"api_error"}}`),
+				// Ordinary replies mentioning rate limits aren't errors
+				assistantLine("a1", "", 40, 10, 5, 0, 0),
+				strings.Replace(assistantLine("a2", "", 50, 10, 5, 0, 0), `"text":"ok"`, `"text":"API Error: 429 is a rate_limit_error"`, 1),
+			},
+			want: SessionMetrics{TotalRequests: 6, TotalInputTokens: 20, TotalOutputTokens: 10,
+				ThrottleEvents: 3, ActiveDuration: 50 * time.Second},
+		},
+		{
+			name: "gaps above the idle threshold are idle",
+			lines: []string{
+				assistantLine("a1", "", 0, 1, 1, 0, 0),
+				assistantLine("a2", "", 120, 1, 1, 0, 0),
+				assistantLine("a3", "", 120+int(IdleThreshold/time.Second)+1, 1, 1, 0, 0),
+				assistantLine("a4", "", 120+int(IdleThreshold/time.Second)+61, 1, 1, 0, 0),
+			},
+			want: SessionMetrics{TotalRequests: 4, TotalInputTokens: 4, TotalOutputTokens: 4,
+				ActiveDuration: 3 * time.Minute},
+		},
+		{
+			name: "latency",
+			lines: []string{
+				// Paired through parentUuid, even when a subagent's request is answered in between
+				userLine("u1", 0),
+				userLine("sub1", 1),
+				assistantLine("sub-a1", "sub1", 2, 1, 1, 0, 0),
+				assistantLine("a1", "u1", 4, 1, 1, 0, 0),
+				// Later lines of the same response aren't timed again
+				assistantLine("a1b", "a1", 9, 1, 1, 0, 0),
+				// A recorded time to first token wins over the timestamps
+				userLine("u2", 20),
+				strings.Replace(assistantLine("a2", "u2", 30, 1, 1, 0, 0), `{"type":"assistant",`, `{"type":"assistant","ttftMs":1500,`, 1),
+				// Lines written before UUIDs existed pair with the last user line
+				fmt.Sprintf(`{"type":"user","timestamp":%q,"message":{"role":"user","content":"hi"}}`, stamp(40)),
+				assistantLine("", "", 43, 1, 1, 0, 0),
+			},
+			want: SessionMetrics{TotalRequests: 5, TotalInputTokens: 5, TotalOutputTokens: 5,
+				ActiveDuration: 41 * time.Second, AvgLatencyMs: 2375, LatencySamples: 4},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			path := filepath.Join(t.TempDir(), "session.jsonl")
+			if err := os.WriteFile(path, []byte(strings.Join(tt.lines, "\n")+"\n"), 0644); err != nil {
+				t.Fatal(err)
+			}
+
+			got, err := ParseSessionJSONL(path)
+			if err != nil {
+				t.Fatalf("ParseSessionJSONL() error = %v", err)
+			}
+
+			checks := []struct {
+				field     string
+				got, want any
+			}{
+				{"TotalRequests", got.TotalRequests, tt.want.TotalRequests},
+				{"TotalInputTokens", got.TotalInputTokens, tt.want.TotalInputTokens},
+				{"TotalOutputTokens", got.TotalOutputTokens, tt.want.TotalOutputTokens},
+				{"CacheReadTokens", got.CacheReadTokens, tt.want.CacheReadTokens},
+				{"CacheCreationTokens", got.CacheCreationTokens, tt.want.CacheCreationTokens},
+				{"ThrottleEvents", got.ThrottleEvents, tt.want.ThrottleEvents},
+				{"ActiveDuration", got.ActiveDuration, tt.want.ActiveDuration},
+				{"AvgLatencyMs", got.AvgLatencyMs, tt.want.AvgLatencyMs},
+				{"LatencySamples", got.LatencySamples, tt.want.LatencySamples},
+			}
+			for _, c := range checks {
+				if c.got != c.want {
+					t.Errorf("%s = %v, want %v", c.field, c.got, c.want)
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,218 @@
+package monitoring
+
+import (
+	"fmt"
+	"io"
+	"maps"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"time"
+)
+
+// LiveWindow is the rolling window live TPM and RPM are measured over
+const LiveWindow = time.Minute
+
+// recentThrottleWindow is how far back throttle events count as recent
+const recentThrottleWindow = 5 * time.Minute
+
+// LiveSession follows a session JSONL file while Claude Code is still writing to it
+// Only running totals and the calls of the last LiveWindow are kept, so following a
+// long session doesn't grow with it
+type LiveSession struct {
+	Path            string
+	offset          int64 // End of the last complete line read
+	parser          lineParser
+	totals          ModelTokens
+	byModel         map[string]ModelTokens
+	lastCall        time.Time
+	recent          []APICall // Calls within LiveWindow of the latest one
+	throttles       int
+	recentThrottles []time.Time
+}
+
+// ModelTokens is the usage of the API calls that went to one model, or to all of them
+type ModelTokens struct {
+	Requests            int
+	InputTokens         int64
+	OutputTokens        int64
+	CacheReadTokens     int64
+	CacheCreationTokens int64
+}
+
+func (t *ModelTokens) add(call APICall) {
+	t.Requests++
+	t.InputTokens += call.InputTokens
+	t.OutputTokens += call.OutputTokens
+	t.CacheReadTokens += call.CacheReadTokens
+	t.CacheCreationTokens += call.CacheCreationTokens
+}
+
+// LiveMetrics is a snapshot of a live session
+type LiveMetrics struct {
+	Requests            int
+	InputTokens         int64
+	OutputTokens        int64
+	CacheReadTokens     int64
+	CacheCreationTokens int64
+	CacheHitRate        float64
+	RollingTPM          float64 // Over LiveWindow, using the same formula as AvgTPM
+	RollingRPM          float64
+	ThrottleEvents      int
+	RecentThrottles     int // Throttle events within the last 5 minutes
+	LastCall            time.Time
+	ByModel             map[string]ModelTokens // Model ID as logged -> its usage
+}
+
+// FindActiveSessionJSONL returns the most recently written session JSONL, in the
+// project of workingDir or, when workingDir is empty, across all projects
+func FindActiveSessionJSONL(workingDir string) (string, error) {
+	pattern := "*/*.jsonl"
+	dir, err := projectsDir()
+	if err != nil {
+		return "", err
+	}
+	if workingDir != "" {
+		if dir, err = projectDirFor(workingDir); err != nil {
+			return "", err
+		}
+		pattern = "*.jsonl"
+	}
+
+	files, err := filepath.Glob(filepath.Join(dir, pattern))
+	if err != nil {
+		return "", fmt.Errorf("failed to glob JSONL files: %w", err)
+	}
+
+	var newest string
+	var newestTime time.Time
+	for _, file := range files {
+		// Skip agent files, like FindSessionJSONL
+		if strings.HasPrefix(filepath.Base(file), "agent-") {
+			continue
+		}
+		info, err := os.Stat(file)
+		if err != nil {
+			continue
+		}
+		if newest == "" || info.ModTime().After(newestTime) {
+			newest, newestTime = file, info.ModTime()
+		}
+	}
+
+	if newest == "" {
+		return "", fmt.Errorf("no session JSONL files found in %s", dir)
+	}
+	return newest, nil
+}
+
+// NewLiveSession starts following the JSONL file at path from its beginning
+func NewLiveSession(path string) *LiveSession {
+	return &LiveSession{Path: path}
+}
+
+// Poll reads the lines written since the last poll
+func (l *LiveSession) Poll() error {
+	file, err := os.Open(l.Path)
+	if err != nil {
+		return fmt.Errorf("failed to open JSONL file: %w", err)
+	}
+	defer file.Close()
+
+	info, err := file.Stat()
+	if err != nil {
+		return fmt.Errorf("failed to stat JSONL file: %w", err)
+	}
+	if info.Size() < l.offset {
+		// The file was truncated or replaced; start over
+		*l = LiveSession{Path: l.Path}
+	}
+
+	if _, err := file.Seek(l.offset, io.SeekStart); err != nil {
+		return fmt.Errorf("failed to seek JSONL file: %w", err)
+	}
+
+	// An incomplete last line is left for the next poll, once the rest is written
+	now := time.Now()
+	read, err := readLines(file, false, func(line []byte) {
+		apiCall, isCall, throttled := l.parser.parse(line)
+		if throttled {
+			at := apiCall.Timestamp
+			if at.IsZero() {
+				at = now
+			}
+			l.throttles++
+			l.recentThrottles = append(l.recentThrottles, at)
+		}
+		if isCall {
+			l.addCall(apiCall)
+		}
+	})
+	l.offset += read
+	if err != nil {
+		return fmt.Errorf("failed to read JSONL file: %w", err)
+	}
+
+	l.recentThrottles = slices.DeleteFunc(l.recentThrottles, func(at time.Time) bool {
+		return now.Sub(at) > recentThrottleWindow
+	})
+	return nil
+}
+
+// addCall counts a call and drops the ones that fell out of the rolling window
+func (l *LiveSession) addCall(call APICall) {
+	l.totals.add(call)
+	if l.byModel == nil {
+		l.byModel = make(map[string]ModelTokens)
+	}
+	model := l.byModel[call.Model]
+	model.add(call)
+	l.byModel[call.Model] = model
+
+	if call.Timestamp.After(l.lastCall) {
+		l.lastCall = call.Timestamp
+	}
+	windowStart := l.lastCall.Add(-LiveWindow)
+	l.recent = slices.DeleteFunc(append(l.recent, call), func(c APICall) bool {
+		return !c.Timestamp.After(windowStart)
+	})
+}
+
+// Metrics summarizes the session as of now
+func (l *LiveSession) Metrics(now time.Time) LiveMetrics {
+	m := LiveMetrics{
+		Requests:            l.totals.Requests,
+		InputTokens:         l.totals.InputTokens,
+		OutputTokens:        l.totals.OutputTokens,
+		CacheReadTokens:     l.totals.CacheReadTokens,
+		CacheCreationTokens: l.totals.CacheCreationTokens,
+		ThrottleEvents:      l.throttles,
+		LastCall:            l.lastCall,
+		ByModel:             maps.Clone(l.byModel),
+	}
+
+	windowStart := now.Add(-LiveWindow)
+	var windowTokens int64
+	var windowRequests int
+	for _, call := range l.recent {
+		if call.Timestamp.After(windowStart) {
+			// AWS formula: Input + Output + CacheCreation (CacheRead tokens don't count)
+			windowTokens += call.InputTokens + call.OutputTokens + call.CacheCreationTokens
+			windowRequests++
+		}
+	}
+	m.RollingTPM = float64(windowTokens) / LiveWindow.Minutes()
+	m.RollingRPM = float64(windowRequests) / LiveWindow.Minutes()
+
+	if total := m.InputTokens + m.CacheReadTokens; total > 0 {
+		m.CacheHitRate = float64(m.CacheReadTokens) / float64(total) * 100.0
+	}
+
+	for _, at := range l.recentThrottles {
+		if now.Sub(at) <= recentThrottleWindow {
+			m.RecentThrottles++
+		}
+	}
+	return m
+}
@@ -0,0 +1,277 @@
+package monitoring
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/OlaHulleberg/clauderock/internal/fileutil"
+)
+
+const (
+	// heartbeatInterval is how often a running session's journal entry is touched
+	heartbeatInterval = time.Minute
+	// staleAfter is how long an entry goes without a heartbeat before its session counts as
+	// interrupted even though a process with its ID exists (the ID may have been reused)
+	staleAfter = 10 * heartbeatInterval
+	// childMaxAge bounds how long a session whose clauderock is gone is waited on because
+	// its Claude Code process is still running, in case that process ID has been reused
+	childMaxAge = 7 * 24 * time.Hour
+	// claimedSuffix marks an entry being finalized; the claiming process's ID follows it,
+	// so a claim left by an invocation that died while recovering can be taken back
+	claimedSuffix = ".claimed"
+)
+
+// JournalEntry records a session that has started but hasn't been tracked yet
+// Entries left behind by a killed clauderock are finalized by the next invocation
+type JournalEntry struct {
+	ID       string          `json:"id"`
+	PID      int             `json:"pid"`
+	ChildPID int             `json:"child-pid,omitempty"` // Claude Code's process, once started
+	Start    time.Time       `json:"start"`
+	Session  json.RawMessage `json:"session"` // The caller's session details, stored as given
+	LastSeen time.Time       `json:"-"`       // Last heartbeat, from the file's modification time
+}
+
+// SessionWatcher keeps a running session's journal entry alive
+type SessionWatcher struct {
+	path  string
+	entry JournalEntry
+	stop  chan struct{}
+	once  sync.Once
+}
+
+func journalDir() (string, error) {
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return "", fmt.Errorf("failed to get home directory: %w", err)
+	}
+	return filepath.Join(home, ".clauderock", "sessions"), nil
+}
+
+// SessionID identifies the session this clauderock process starts at start; it names the journal entry
+func SessionID(start time.Time) string {
+	return fmt.Sprintf("%d-%d", start.UnixNano(), os.Getpid())
+}
+
+// WatchSession journals a starting session straight away, so it can be recorded even if
+// clauderock is killed (e.g., the terminal is closed), and touches the entry until the
+// watcher is finished or detached
+func WatchSession(start time.Time, session any) (*SessionWatcher, error) {
+	dir, err := journalDir()
+	if err != nil {
+		return nil, err
+	}
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return nil, fmt.Errorf("failed to create session journal: %w", err)
+	}
+
+	details, err := json.Marshal(session)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal session: %w", err)
+	}
+	entry := JournalEntry{
+		ID:      SessionID(start),
+		PID:     os.Getpid(),
+		Start:   start,
+		Session: details,
+	}
+	data, err := json.MarshalIndent(entry, "", "  ")
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal journal entry: %w", err)
+	}
+
+	path := filepath.Join(dir, entry.ID+".json")
+	if err := os.WriteFile(path, data, 0644); err != nil {
+		return nil, fmt.Errorf("failed to write session journal: %w", err)
+	}
+
+	w := &SessionWatcher{path: path, entry: entry, stop: make(chan struct{})}
+	go w.heartbeat()
+	return w, nil
+}
+
+// SetChild records the Claude Code process the session runs, so the session isn't
+// recovered while Claude is still running even if clauderock itself has been killed
+func (w *SessionWatcher) SetChild(pid int) error {
+	if w == nil {
+		return nil
+	}
+	w.entry.ChildPID = pid
+	data, err := json.MarshalIndent(w.entry, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to marshal journal entry: %w", err)
+	}
+	if err := fileutil.WriteFileAtomic(w.path, data, 0644); err != nil {
+		return fmt.Errorf("failed to write session journal: %w", err)
+	}
+	return nil
+}
+
+func (w *SessionWatcher) heartbeat() {
+	ticker := time.NewTicker(heartbeatInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-w.stop:
+			return
+		case now := <-ticker.C:
+			_ = os.Chtimes(w.path, now, now)
+		}
+	}
+}
+
+// Finish stops the heartbeat and removes the entry once the session has been tracked
+// or deliberately not tracked; calls after the first do nothing
+func (w *SessionWatcher) Finish() {
+	if w == nil {
+		return
+	}
+	w.once.Do(func() {
+		close(w.stop)
+		_ = os.Remove(w.path)
+	})
+}
+
+// Detach stops the heartbeat but leaves the entry for the next invocation to finalize,
+// for when tracking the session failed
+func (w *SessionWatcher) Detach() {
+	if w == nil {
+		return
+	}
+	w.once.Do(func() {
+		close(w.stop)
+		// Backdate the heartbeat so the entry isn't mistaken for a running session
+		stale := time.Now().Add(-staleAfter)
+		_ = os.Chtimes(w.path, stale, stale)
+	})
+}
+
+// running reports whether the entry's session may still be going: its clauderock is alive
+// and heartbeating, or clauderock is gone but the Claude Code it started is still running
+func (e JournalEntry) running() bool {
+	if time.Since(e.LastSeen) < staleAfter && processAlive(e.PID) {
+		return true
+	}
+	return e.ChildPID > 0 && time.Since(e.Start) < childMaxAge && processAlive(e.ChildPID)
+}
+
+// InterruptedSessions returns the journaled sessions that are no longer running, including
+// those whose claim was abandoned by an invocation that died while recovering them
+func InterruptedSessions() ([]JournalEntry, error) {
+	dir, err := journalDir()
+	if err != nil {
+		return nil, err
+	}
+	files, err := os.ReadDir(dir)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to read session journal: %w", err)
+	}
+
+	var entries []JournalEntry
+	for _, file := range files {
+		if file.IsDir() {
+			continue
+		}
+		path := filepath.Join(dir, file.Name())
+		if name, ok := abandonedClaim(file.Name()); ok {
+			// Renaming keeps the modification time, so the last heartbeat survives
+			if os.Rename(path, filepath.Join(dir, name)) != nil {
+				continue
+			}
+			path = filepath.Join(dir, name)
+		} else if !strings.HasSuffix(file.Name(), ".json") {
+			continue
+		}
+		info, err := os.Stat(path)
+		if err != nil {
+			continue
+		}
+		data, err := os.ReadFile(path)
+		if err != nil {
+			continue
+		}
+
+		var entry JournalEntry
+		if err := json.Unmarshal(data, &entry); err != nil || entry.ID == "" {
+			// Nothing can be recovered from a corrupt entry
+			_ = os.Remove(path)
+			continue
+		}
+		entry.LastSeen = info.ModTime()
+
+		if entry.running() {
+			continue
+		}
+		entries = append(entries, entry)
+	}
+	return entries, nil
+}
+
+// ClaimJournalEntry takes an interrupted entry for finalizing, so two invocations
+// recovering at the same time don't both record it
+// Returns false when another invocation got there first
+func ClaimJournalEntry(id string) (bool, error) {
+	dir, err := journalDir()
+	if err != nil {
+		return false, err
+	}
+	path := filepath.Join(dir, id+".json")
+	if err := os.Rename(path, claimedPath(path)); err != nil {
+		if os.IsNotExist(err) {
+			return false, nil
+		}
+		return false, fmt.Errorf("failed to claim session journal entry: %w", err)
+	}
+	return true, nil
+}
+
+// ReleaseJournalEntry ends a claim: a recorded entry is removed, any other goes back
+// to the journal to be tried again
+func ReleaseJournalEntry(id string, recorded bool) error {
+	dir, err := journalDir()
+	if err != nil {
+		return err
+	}
+	path := filepath.Join(dir, id+".json")
+	if recorded {
+		err = os.Remove(claimedPath(path))
+	} else {
+		err = os.Rename(claimedPath(path), path)
+	}
+	if err != nil && !os.IsNotExist(err) {
+		return fmt.Errorf("failed to release session journal entry: %w", err)
+	}
+	return nil
+}
+
+// claimedPath is the name an entry at path has while this process has it claimed
+func claimedPath(path string) string {
+	return fmt.Sprintf("%s%s.%d", path, claimedSuffix, os.Getpid())
+}
+
+// abandonedClaim reports whether name is a claimed entry whose claiming process has exited,
+// returning the entry's unclaimed name
+func abandonedClaim(name string) (string, bool) {
+	// Claims from before the claiming process was recorded can't be told apart, so they're all taken back
+	if strings.HasSuffix(name, ".json"+claimedSuffix) {
+		return strings.TrimSuffix(name, claimedSuffix), true
+	}
+	i := strings.LastIndex(name, claimedSuffix+".")
+	if i < 0 || !strings.HasSuffix(name[:i], ".json") {
+		return "", false
+	}
+	pid, err := strconv.Atoi(name[i+len(claimedSuffix)+1:])
+	if err != nil || processAlive(pid) {
+		return "", false
+	}
+	return name[:i], true
+}
@@ -0,0 +1,103 @@
+package permissions
+
+import (
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+)
+
+// Modes clauderock's sensitive files and directories should have: private to the user
+const (
+	PrivateFileMode os.FileMode = 0600
+	PrivateDirMode  os.FileMode = 0700
+)
+
+// Problem is a sensitive file or directory that other users can read, or that belongs to another user
+type Problem struct {
+	Path  string
+	Mode  os.FileMode
+	Want  os.FileMode // Mode Fix sets; zero when changing the mode wouldn't help
+	Issue string
+}
+
+// Fixable reports whether Fix can correct the problem; files of another user need chown
+func (p Problem) Fixable() bool {
+	return p.Want != 0
+}
+
+// sensitivePaths returns the directories whose contents must stay private: profiles reference
+// AWS profiles and keyring entries, and the keyring's passphrase is derived from the machine and
+// user, so a copy readable by others is as good as plaintext
+func sensitivePaths() ([]string, error) {
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return nil, fmt.Errorf("failed to get home directory: %w", err)
+	}
+	base := filepath.Join(home, ".clauderock")
+	return []string{filepath.Join(base, "profiles"), filepath.Join(base, "keyring")}, nil
+}
+
+// Check returns the permission problems of the profiles and keyring directories and their files
+// Missing files are not a problem; on Windows, where access is governed by ACLs rather than mode
+// bits, only ownership is unchecked too, so Check finds nothing there
+func Check() ([]Problem, error) {
+	if !modesApply {
+		return nil, nil
+	}
+	dirs, err := sensitivePaths()
+	if err != nil {
+		return nil, err
+	}
+
+	var problems []Problem
+	for _, dir := range dirs {
+		info, err := os.Stat(dir)
+		if err != nil {
+			continue
+		}
+		problems = append(problems, checkPath(dir, info)...)
+
+		entries, err := os.ReadDir(dir)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read %s: %w", dir, err)
+		}
+		for _, e := range entries {
+			info, err := e.Info()
+			if err != nil || !info.Mode().IsRegular() {
+				continue
+			}
+			problems = append(problems, checkPath(filepath.Join(dir, e.Name()), info)...)
+		}
+	}
+	return problems, nil
+}
+
+// checkPath reports a path owned by another user, or readable or writable by group or others
+func checkPath(path string, info fs.FileInfo) []Problem {
+	mode := info.Mode().Perm()
+	if uid, ok := ownerUID(info); ok && uid != os.Getuid() {
+		return []Problem{{Path: path, Mode: mode, Issue: fmt.Sprintf("owned by another user (uid %d), possibly from running clauderock with sudo", uid)}}
+	}
+	if mode&0077 == 0 {
+		return nil
+	}
+	want := PrivateFileMode
+	if info.IsDir() {
+		want = PrivateDirMode
+	}
+	return []Problem{{Path: path, Mode: mode, Want: want, Issue: "accessible by other users"}}
+}
+
+// Fix sets the private mode on every fixable problem, returning the first failure
+func Fix(problems []Problem) error {
+	for _, p := range problems {
+		if !p.Fixable() {
+			continue
+		}
+		if err := os.Chmod(p.Path, p.Want); err != nil {
+			return fmt.Errorf("failed to change mode of %s: %w", p.Path, err)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,17 @@
+//go:build !(darwin || dragonfly || freebsd || linux || netbsd || openbsd || windows)
+
+package permissions
+
+import "io/fs"
+
+// modesApply is whether mode bits control who can read files; unchecked on other systems
+const modesApply = false
+
+// Elevated is unknown on other systems
+func Elevated() bool {
+	return false
+}
+
+func ownerUID(info fs.FileInfo) (int, bool) {
+	return 0, false
+}
@@ -0,0 +1,26 @@
+//go:build darwin || dragonfly || freebsd || linux || netbsd || openbsd
+
+package permissions
+
+import (
+	"io/fs"
+	"os"
+	"syscall"
+)
+
+// modesApply is whether mode bits control who can read files
+const modesApply = true
+
+// Elevated reports whether clauderock runs as root
+func Elevated() bool {
+	return os.Geteuid() == 0
+}
+
+// ownerUID returns the user ID owning a file
+func ownerUID(info fs.FileInfo) (int, bool) {
+	stat, ok := info.Sys().(*syscall.Stat_t)
+	if !ok {
+		return 0, false
+	}
+	return int(stat.Uid), true
+}
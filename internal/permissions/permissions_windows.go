@@ -0,0 +1,22 @@
+//go:build windows
+
+package permissions
+
+import (
+	"io/fs"
+
+	"golang.org/x/sys/windows"
+)
+
+// modesApply is whether mode bits control who can read files; Windows uses ACLs
+const modesApply = false
+
+// Elevated reports whether clauderock runs as an elevated administrator
+func Elevated() bool {
+	return windows.GetCurrentProcessToken().IsElevated()
+}
+
+// ownerUID is unknown on Windows, where files are owned by SIDs
+func ownerUID(info fs.FileInfo) (int, bool) {
+	return 0, false
+}
@@ -0,0 +1,73 @@
+package plugins
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"time"
+)
+
+// manifestCacheEntry remembers a plugin's manifest, so startup doesn't run every plugin each time
+// A plugin that failed to describe itself is remembered too, so a broken one doesn't slow every run
+type manifestCacheEntry struct {
+	ModTime  time.Time `json:"mod-time"`
+	Size     int64     `json:"size"`
+	Manifest Manifest  `json:"manifest"`
+	Error    string    `json:"error,omitempty"`
+}
+
+// manifestCache maps plugin paths to their cached manifests
+type manifestCache map[string]manifestCacheEntry
+
+func manifestCachePath() (string, error) {
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(home, ".clauderock", "cache", "plugins.json"), nil
+}
+
+func loadManifestCache() manifestCache {
+	cache := make(manifestCache)
+	path, err := manifestCachePath()
+	if err != nil {
+		return cache
+	}
+	if data, err := os.ReadFile(path); err == nil {
+		_ = json.Unmarshal(data, &cache)
+	}
+	return cache
+}
+
+// lookup returns the cached describe result while the plugin binary is unchanged
+func (c manifestCache) lookup(path string, info os.FileInfo) (manifestCacheEntry, bool) {
+	entry, ok := c[path]
+	if !ok || !entry.ModTime.Equal(info.ModTime()) || entry.Size != info.Size() {
+		return manifestCacheEntry{}, false
+	}
+	return entry, true
+}
+
+func (c manifestCache) store(path string, info os.FileInfo, manifest Manifest, describeErr error) {
+	entry := manifestCacheEntry{ModTime: info.ModTime(), Size: info.Size(), Manifest: manifest}
+	if describeErr != nil {
+		entry.Error = describeErr.Error()
+	}
+	c[path] = entry
+}
+
+// save writes the cache; it only saves time, so errors are ignored
+func (c manifestCache) save() {
+	path, err := manifestCachePath()
+	if err != nil {
+		return
+	}
+	data, err := json.MarshalIndent(c, "", "  ")
+	if err != nil {
+		return
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return
+	}
+	_ = os.WriteFile(path, data, 0644)
+}
@@ -0,0 +1,312 @@
+// Package plugins runs third-party extensions installed in ~/.clauderock/plugins
+//
+// A plugin is an executable that reads one JSON request on stdin. Asked to describe
+// itself it answers with a JSON manifest on stdout naming the subcommands and launch
+// hooks it provides; hooks answer with a JSON response, while subcommands write to
+// the terminal directly.
+package plugins
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"runtime"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/OlaHulleberg/clauderock/internal/policy"
+)
+
+// ProtocolVersion is sent with every request so plugins can detect incompatible clauderock versions
+const ProtocolVersion = 1
+
+// Request types
+const (
+	RequestDescribe = "describe"
+	RequestCommand  = "command"
+	RequestHook     = "hook"
+)
+
+// Launch hooks
+const (
+	HookPreLaunch  = "pre-launch"
+	HookPostLaunch = "post-launch"
+)
+
+const (
+	// describeTimeout bounds a plugin answering a describe request
+	describeTimeout = 5 * time.Second
+	// hookTimeout bounds a launch hook, so a hung plugin can't hold up Claude
+	hookTimeout = 10 * time.Second
+)
+
+// Command is a subcommand a plugin adds under 'clauderock manage'
+type Command struct {
+	Name  string `json:"name"`
+	Short string `json:"short,omitempty"`
+}
+
+// Manifest is a plugin's answer to a describe request
+type Manifest struct {
+	Name        string    `json:"name"`
+	Description string    `json:"description,omitempty"`
+	Commands    []Command `json:"commands,omitempty"`
+	Hooks       []string  `json:"hooks,omitempty"`
+}
+
+// Plugin is an executable found in the plugins directory
+type Plugin struct {
+	Path string
+	Manifest
+}
+
+// HasHook reports whether the plugin asked to be run for a launch hook
+func (p Plugin) HasHook(hook string) bool {
+	for _, h := range p.Hooks {
+		if h == hook {
+			return true
+		}
+	}
+	return false
+}
+
+// Request is the JSON sent to a plugin on stdin
+type Request struct {
+	Protocol int         `json:"protocol"`
+	Type     string      `json:"type"`
+	Command  string      `json:"command,omitempty"`
+	Args     []string    `json:"args,omitempty"`
+	Hook     string      `json:"hook,omitempty"`
+	Launch   *LaunchInfo `json:"launch,omitempty"`
+}
+
+// LaunchInfo describes a Claude Code session to launch hooks
+// End and ExitCode are only set for post-launch
+type LaunchInfo struct {
+	Profile          string    `json:"profile"`
+	ProfileType      string    `json:"profile-type"`
+	WorkingDirectory string    `json:"working-directory"`
+	Region           string    `json:"region,omitempty"`
+	Model            string    `json:"model"`
+	FastModel        string    `json:"fast-model"`
+	HeavyModel       string    `json:"heavy-model"`
+	Start            time.Time `json:"start"`
+	End              time.Time `json:"end,omitzero"`
+	ExitCode         *int      `json:"exit-code,omitempty"`
+}
+
+// HookResponse is a plugin's answer to a hook request
+// Env is only honoured for pre-launch
+type HookResponse struct {
+	Env     map[string]string `json:"env,omitempty"`
+	Message string            `json:"message,omitempty"`
+}
+
+// Dir returns the directory plugins are installed in
+func Dir() (string, error) {
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return "", fmt.Errorf("failed to get home directory: %w", err)
+	}
+	return filepath.Join(home, ".clauderock", "plugins"), nil
+}
+
+var (
+	discoverOnce     sync.Once
+	discovered       []Plugin
+	discoverProblems []error
+)
+
+// Discover finds the plugins in the plugins directory, once per process
+// Plugins that fail to describe themselves are reported as problems and left out until their file changes
+// In kiosk mode no plugin is run at all, so none are found
+func Discover() ([]Plugin, []error) {
+	discoverOnce.Do(func() {
+		kiosk, source, err := policy.KioskMode()
+		switch {
+		case err != nil:
+			discoverProblems = []error{fmt.Errorf("plugins are disabled until the organisation policy loads: %w", err)}
+		case kiosk:
+			discoverProblems = []error{fmt.Errorf("plugins are disabled in kiosk mode (enabled by %s)", source)}
+		default:
+			discovered, discoverProblems = discover()
+		}
+	})
+	return discovered, discoverProblems
+}
+
+func discover() ([]Plugin, []error) {
+	dir, err := Dir()
+	if err != nil {
+		return nil, []error{err}
+	}
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+		return nil, []error{fmt.Errorf("failed to read plugins directory: %w", err)}
+	}
+
+	cache := loadManifestCache()
+	fresh := make(manifestCache)
+	var found []Plugin
+	var problems []error
+	for _, entry := range entries {
+		path := filepath.Join(dir, entry.Name())
+		info, err := os.Stat(path)
+		if err != nil || !info.Mode().IsRegular() || !isExecutable(entry.Name(), info) {
+			continue
+		}
+
+		var manifest Manifest
+		if cached, ok := cache.lookup(path, info); ok {
+			manifest = cached.Manifest
+			if cached.Error != "" {
+				err = errors.New(cached.Error)
+			}
+		} else {
+			manifest, err = describe(path)
+		}
+		fresh.store(path, info, manifest, err)
+		if err != nil {
+			problems = append(problems, fmt.Errorf("plugin %s: %w", entry.Name(), err))
+			continue
+		}
+		found = append(found, Plugin{Path: path, Manifest: manifest})
+	}
+	fresh.save()
+
+	sort.Slice(found, func(i, j int) bool { return found[i].Name < found[j].Name })
+	return found, problems
+}
+
+// isExecutable reports whether a file in the plugins directory can be run: by its execute
+// bits, or on Windows, which has none, by an extension listed in PATHEXT
+func isExecutable(name string, info os.FileInfo) bool {
+	if runtime.GOOS != "windows" {
+		return info.Mode().Perm()&0111 != 0
+	}
+	pathext := os.Getenv("PATHEXT")
+	if pathext == "" {
+		pathext = ".com;.exe;.bat;.cmd"
+	}
+	ext := filepath.Ext(name)
+	for _, e := range filepath.SplitList(pathext) {
+		if e != "" && strings.EqualFold(e, ext) {
+			return true
+		}
+	}
+	return false
+}
+
+// describe asks a plugin for its manifest
+func describe(path string) (Manifest, error) {
+	var manifest Manifest
+	out, err := call(path, Request{Type: RequestDescribe}, describeTimeout)
+	if err != nil {
+		return manifest, err
+	}
+	if err := json.Unmarshal(out, &manifest); err != nil {
+		return manifest, fmt.Errorf("invalid describe response: %w", err)
+	}
+	if manifest.Name == "" {
+		manifest.Name = filepath.Base(path)
+	}
+	return manifest, nil
+}
+
+// call sends a request to a plugin and returns what it wrote to stdout
+// The plugin's stderr goes to the terminal, so it can explain failures
+func call(path string, req Request, timeout time.Duration) ([]byte, error) {
+	req.Protocol = ProtocolVersion
+	input, err := json.Marshal(req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal request: %w", err)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), timeout)
+	defer cancel()
+
+	var stdout bytes.Buffer
+	cmd := exec.CommandContext(ctx, path)
+	cmd.Stdin = bytes.NewReader(input)
+	cmd.Stdout = &stdout
+	cmd.Stderr = os.Stderr
+	if err := cmd.Run(); err != nil {
+		if ctx.Err() != nil {
+			return nil, fmt.Errorf("timed out after %s", timeout)
+		}
+		return nil, err
+	}
+	return stdout.Bytes(), nil
+}
+
+// RunCommand runs a plugin subcommand attached to the terminal
+func RunCommand(p Plugin, command string, args []string) error {
+	input, err := json.Marshal(Request{Protocol: ProtocolVersion, Type: RequestCommand, Command: command, Args: args})
+	if err != nil {
+		return fmt.Errorf("failed to marshal request: %w", err)
+	}
+
+	cmd := exec.Command(p.Path)
+	cmd.Stdin = bytes.NewReader(input)
+	cmd.Stdout = os.Stdout
+	cmd.Stderr = os.Stderr
+	if err := cmd.Run(); err != nil {
+		return fmt.Errorf("plugin %s: %w", p.Name, err)
+	}
+	return nil
+}
+
+// RunHook runs a launch hook in every plugin that registered it, in name order
+// Returns the environment variables the plugins asked for as KEY=VALUE pairs
+// A failing plugin is reported and skipped, so one broken integration never blocks a launch
+func RunHook(hook string, info LaunchInfo) []string {
+	found, _ := Discover()
+
+	var env []string
+	for _, p := range found {
+		if !p.HasHook(hook) {
+			continue
+		}
+		out, err := call(p.Path, Request{Type: RequestHook, Hook: hook, Launch: &info}, hookTimeout)
+		if err != nil {
+			fmt.Printf("Warning: plugin %s %s hook failed: %v\n", p.Name, hook, err)
+			continue
+		}
+
+		var resp HookResponse
+		if len(bytes.TrimSpace(out)) > 0 {
+			if err := json.Unmarshal(out, &resp); err != nil {
+				fmt.Printf("Warning: plugin %s %s hook returned invalid JSON: %v\n", p.Name, hook, err)
+				continue
+			}
+		}
+		if resp.Message != "" {
+			fmt.Printf("[%s] %s\n", p.Name, strings.TrimSpace(resp.Message))
+		}
+		if hook == HookPreLaunch {
+			keys := make([]string, 0, len(resp.Env))
+			for key := range resp.Env {
+				keys = append(keys, key)
+			}
+			sort.Strings(keys)
+			for _, key := range keys {
+				if key == "" || strings.Contains(key, "=") {
+					fmt.Printf("Warning: plugin %s set invalid variable name '%s'\n", p.Name, key)
+					continue
+				}
+				env = append(env, key+"="+resp.Env[key])
+			}
+		}
+	}
+	return env
+}
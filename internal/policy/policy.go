@@ -0,0 +1,262 @@
+package policy
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/OlaHulleberg/clauderock/internal/aws"
+	"github.com/OlaHulleberg/clauderock/internal/config"
+)
+
+// SystemDir holds the organisation policy; only administrators can write to it, so
+// nothing users control can replace or loosen the policy
+const SystemDir = "/etc/clauderock"
+
+// DefaultPath is where administrators install the organisation policy
+const DefaultPath = SystemDir + "/policy.json"
+
+// URLPath names an HTTPS URL to fetch the policy from when DefaultPath doesn't exist
+const URLPath = SystemDir + "/policy-url"
+
+// EnvVar selects another policy file in SystemDir instead of DefaultPath
+// Files outside SystemDir are rejected, since any user can set the variable
+const EnvVar = "CLAUDEROCK_POLICY"
+
+// KioskEnvVar enables kiosk mode without a policy file when set to 1, true, or yes
+const KioskEnvVar = "CLAUDEROCK_KIOSK"
+
+// fetchTimeout bounds how long fetching a remote policy may take
+const fetchTimeout = 10 * time.Second
+
+// Policy restricts what profiles may be configured and launched
+// Empty lists allow everything; entries may use shell-style wildcards (e.g., "eu-*")
+type Policy struct {
+	AllowedProfileTypes []string `json:"allowed-profile-types,omitempty"`
+	AllowedRegions      []string `json:"allowed-regions,omitempty"`
+	AllowedProviders    []string `json:"allowed-providers,omitempty"`
+	AllowedModels       []string `json:"allowed-models,omitempty"`
+
+	// Kiosk disables every command but launching with the pre-provisioned profiles and
+	// read-only views, along with plugins (for shared lab or classroom machines)
+	Kiosk bool `json:"kiosk,omitempty"`
+
+	// Source is the file or URL the policy was loaded from
+	Source string `json:"-"`
+}
+
+// Violation describes one setting that the policy does not allow
+type Violation struct {
+	Field   string
+	Value   string
+	Allowed []string
+}
+
+func (v Violation) String() string {
+	return fmt.Sprintf("%s '%s' is not allowed (allowed: %s)", v.Field, v.Value, strings.Join(v.Allowed, ", "))
+}
+
+var (
+	loadOnce  sync.Once
+	loaded    *Policy
+	loadedErr error
+)
+
+// Load reads the organisation policy, returning nil when none is configured
+// A remote policy is fetched once per process; when it can't be, Load fails rather than
+// fall back to a copy users could edit
+func Load() (*Policy, error) {
+	loadOnce.Do(func() {
+		loaded, loadedErr = load()
+	})
+	return loaded, loadedErr
+}
+
+func load() (*Policy, error) {
+	source, err := policySource()
+	if err != nil || source == "" {
+		return nil, err
+	}
+
+	var data []byte
+	if strings.HasPrefix(source, "https://") {
+		data, err = download(source)
+	} else {
+		data, err = os.ReadFile(source)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to load policy from %s: %w", source, err)
+	}
+
+	var p Policy
+	if err := json.Unmarshal(data, &p); err != nil {
+		return nil, fmt.Errorf("failed to parse policy from %s: %w", source, err)
+	}
+	p.Source = source
+	return &p, nil
+}
+
+// policySource returns the policy file or URL to load, or "" when none is installed
+func policySource() (string, error) {
+	if env := strings.TrimSpace(os.Getenv(EnvVar)); env != "" {
+		path, err := filepath.Abs(env)
+		if err != nil {
+			return "", err
+		}
+		if rel, err := filepath.Rel(SystemDir, path); err != nil || rel == "." || strings.HasPrefix(rel, "..") {
+			return "", fmt.Errorf("%s must name a policy file in %s, not %s", EnvVar, SystemDir, env)
+		}
+		return path, nil
+	}
+
+	if _, err := os.Stat(DefaultPath); err == nil || !os.IsNotExist(err) {
+		return DefaultPath, nil
+	}
+
+	data, err := os.ReadFile(URLPath)
+	if os.IsNotExist(err) {
+		return "", nil
+	}
+	if err != nil {
+		return "", fmt.Errorf("failed to read %s: %w", URLPath, err)
+	}
+	url := strings.TrimSpace(string(data))
+	if !strings.HasPrefix(url, "https://") {
+		return "", fmt.Errorf("policy URL %s in %s must use https", url, URLPath)
+	}
+	return url, nil
+}
+
+// KioskMode reports whether kiosk mode is enabled and what enabled it
+func KioskMode() (bool, string, error) {
+	switch strings.ToLower(strings.TrimSpace(os.Getenv(KioskEnvVar))) {
+	case "1", "true", "yes":
+		return true, KioskEnvVar, nil
+	}
+
+	p, err := Load()
+	if err != nil {
+		return false, "", err
+	}
+	if p != nil && p.Kiosk {
+		return true, p.Source, nil
+	}
+	return false, "", nil
+}
+
+func download(url string) ([]byte, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), fetchTimeout)
+	defer cancel()
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return nil, err
+	}
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("unexpected status %s", resp.Status)
+	}
+	return io.ReadAll(io.LimitReader(resp.Body, 1<<20))
+}
+
+// Check returns every setting in cfg that the policy does not allow
+func (p *Policy) Check(cfg *config.Config) []Violation {
+	var violations []Violation
+
+	if !allowed(p.AllowedProfileTypes, cfg.ProfileType) {
+		violations = append(violations, Violation{"profile type", cfg.ProfileType, p.AllowedProfileTypes})
+	}
+
+	if cfg.ProfileType == "bedrock" && cfg.Region != "" && !allowed(p.AllowedRegions, cfg.Region) {
+		violations = append(violations, Violation{"region", cfg.Region, p.AllowedRegions})
+	}
+	if cfg.ProfileType == "vertex" && cfg.VertexRegion != "" && !allowed(p.AllowedRegions, cfg.VertexRegion) {
+		violations = append(violations, Violation{"vertex region", cfg.VertexRegion, p.AllowedRegions})
+	}
+
+	models := []struct{ slot, id string }{
+		{"main", cfg.Model},
+		{"fast", cfg.FastModel},
+		{"heavy", cfg.HeavyModel},
+	}
+	for _, m := range models {
+		if m.id == "" {
+			continue
+		}
+		friendly := aws.ExtractFriendlyModelName(m.id)
+		if !allowed(p.AllowedProviders, modelProvider(friendly)) {
+			violations = append(violations, Violation{m.slot + " model provider", modelProvider(friendly), p.AllowedProviders})
+		}
+		if !allowed(p.AllowedModels, friendly) && !allowed(p.AllowedModels, m.id) {
+			violations = append(violations, Violation{m.slot + " model", friendly, p.AllowedModels})
+		}
+	}
+
+	return violations
+}
+
+// Enforce loads the policy and returns an error explaining every violation in cfg
+func Enforce(cfg *config.Config) error {
+	p, err := Load()
+	if err != nil {
+		return err
+	}
+	if p == nil {
+		return nil
+	}
+	return p.Err(cfg)
+}
+
+// Err returns an error listing the violations in cfg, or nil when cfg complies
+func (p *Policy) Err(cfg *config.Config) error {
+	violations := p.Check(cfg)
+	if len(violations) == 0 {
+		return nil
+	}
+
+	lines := make([]string, len(violations))
+	for i, v := range violations {
+		lines[i] = "  - " + v.String()
+	}
+	return fmt.Errorf("%w (%s):\n%s", ErrViolation, p.Source, strings.Join(lines, "\n"))
+}
+
+// ErrViolation is returned when a configuration breaks the organisation policy
+var ErrViolation = errors.New("configuration violates organisation policy")
+
+// modelProvider returns the provider of a friendly model name
+// Bare API model IDs have no provider prefix and are assumed to be Anthropic models
+func modelProvider(model string) string {
+	if provider, _, ok := strings.Cut(model, "."); ok {
+		return provider
+	}
+	return "anthropic"
+}
+
+// allowed reports whether value matches one of the patterns; an empty list allows everything
+func allowed(patterns []string, value string) bool {
+	if len(patterns) == 0 {
+		return true
+	}
+	value = strings.ToLower(value)
+	for _, pattern := range patterns {
+		if ok, err := path.Match(strings.ToLower(pattern), value); err == nil && ok {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,39 @@
+package policy
+
+import (
+	"testing"
+
+	"github.com/OlaHulleberg/clauderock/internal/config"
+)
+
+func TestCheckRegions(t *testing.T) {
+	p := &Policy{AllowedRegions: []string{"eu-*", "europe-*"}}
+
+	tests := []struct {
+		name  string
+		cfg   config.Config
+		field string // Field of the expected violation; empty when the profile complies
+	}{
+		{"bedrock allowed", config.Config{ProfileType: "bedrock", Region: "eu-west-1"}, ""},
+		{"bedrock denied", config.Config{ProfileType: "bedrock", Region: "us-east-1"}, "region"},
+		{"vertex allowed", config.Config{ProfileType: "vertex", VertexRegion: "europe-west1"}, ""},
+		{"vertex denied", config.Config{ProfileType: "vertex", VertexRegion: "us-east5"}, "vertex region"},
+		{"vertex ignores the bedrock region", config.Config{ProfileType: "vertex", Region: "us-east-1", VertexRegion: "europe-west4"}, ""},
+		{"api has no region", config.Config{ProfileType: "api", Region: "us-east-1"}, ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			violations := p.Check(&tt.cfg)
+			if tt.field == "" {
+				if len(violations) != 0 {
+					t.Fatalf("Check() = %v, want no violations", violations)
+				}
+				return
+			}
+			if len(violations) != 1 || violations[0].Field != tt.field {
+				t.Fatalf("Check() = %v, want one %q violation", violations, tt.field)
+			}
+		})
+	}
+}
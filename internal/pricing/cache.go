@@ -0,0 +1,236 @@
+package pricing
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/OlaHulleberg/clauderock/internal/fileutil"
+)
+
+// CacheTTL is how long fetched prices are used before they're refreshed
+const CacheTTL = 7 * 24 * time.Hour
+
+// Cache is the price list saved on disk by Refresh
+// Prices are the default for regions without their own; Regions holds each fetched region's prices
+type Cache struct {
+	Updated time.Time               `json:"updated"` // When prices were last fetched successfully
+	Checked time.Time               `json:"checked"` // When a fetch was last attempted
+	Source  string                  `json:"source"`
+	Region  string                  `json:"region"` // Region the default prices were fetched for
+	Prices  map[string]ModelPrice   `json:"prices"`
+	Regions map[string]RegionPrices `json:"regions,omitempty"`
+}
+
+// RegionPrices is the price list fetched for one region
+type RegionPrices struct {
+	Updated time.Time             `json:"updated"`
+	Checked time.Time             `json:"checked"`
+	Prices  map[string]ModelPrice `json:"prices"`
+}
+
+// checked returns when prices for region were last attempted, or the zero time
+func (c *Cache) checked(region string) time.Time {
+	if entry, ok := c.Regions[region]; ok {
+		return entry.Checked
+	}
+	// Caches written before per-region prices only have the default region
+	if region == c.Region {
+		return c.Checked
+	}
+	return time.Time{}
+}
+
+var (
+	tableMu      sync.Mutex
+	table        map[string]ModelPrice
+	regionTables map[string]map[string]ModelPrice
+)
+
+// currentTable returns the built-in prices overlaid with the cached price list
+func currentTable() map[string]ModelPrice {
+	tableMu.Lock()
+	defer tableMu.Unlock()
+
+	if table == nil {
+		table = make(map[string]ModelPrice, len(defaultPrices))
+		for key, price := range defaultPrices {
+			table[key] = price
+		}
+		// A missing or unreadable cache leaves the built-in prices in place
+		if cache, err := LoadCache(); err == nil && cache != nil {
+			for key, price := range cache.Prices {
+				table[key] = price
+			}
+		}
+	}
+	return table
+}
+
+// regionTable returns the cached prices fetched for region, or nil when it has none
+func regionTable(region string) map[string]ModelPrice {
+	if region == "" {
+		return nil
+	}
+	tableMu.Lock()
+	defer tableMu.Unlock()
+
+	if regionTables == nil {
+		regionTables = make(map[string]map[string]ModelPrice)
+		if cache, err := LoadCache(); err == nil && cache != nil {
+			for name, entry := range cache.Regions {
+				regionTables[name] = entry.Prices
+			}
+		}
+	}
+	return regionTables[region]
+}
+
+// Prices returns a copy of the default prices currently in effect, keyed by model
+func Prices() map[string]ModelPrice {
+	return PricesInRegion("")
+}
+
+// PricesInRegion returns a copy of the prices in effect for region, keyed by model
+func PricesInRegion(region string) map[string]ModelPrice {
+	current := currentTable()
+	prices := make(map[string]ModelPrice, len(current))
+	for key, price := range current {
+		prices[key] = price
+	}
+	for key, price := range regionTable(region) {
+		prices[key] = price
+	}
+	return prices
+}
+
+// SourceBuiltin is the source of prices clauderock ships with
+const SourceBuiltin = "built-in"
+
+// PriceEntry is a price in effect and where it came from
+type PriceEntry struct {
+	Key string `json:"key"`
+	ModelPrice
+	Source  string    `json:"source"`           // SourceBuiltin or the price list it was fetched from
+	Region  string    `json:"region,omitempty"` // Region a fetched price is for
+	Fetched time.Time `json:"fetched,omitzero"` // When a fetched price was downloaded
+}
+
+// PriceTable returns the prices in effect for region with their sources, sorted by key
+func PriceTable(region string) ([]PriceEntry, error) {
+	cache, err := LoadCache()
+	if err != nil {
+		return nil, err
+	}
+
+	entries := make(map[string]PriceEntry, len(defaultPrices))
+	for key, price := range defaultPrices {
+		entries[key] = PriceEntry{Key: key, ModelPrice: price, Source: SourceBuiltin}
+	}
+	if cache != nil {
+		for key, price := range cache.Prices {
+			entries[key] = PriceEntry{Key: key, ModelPrice: price, Source: cache.Source, Region: cache.Region, Fetched: cache.Updated}
+		}
+		if regional, ok := cache.Regions[region]; ok && region != "" {
+			for key, price := range regional.Prices {
+				entries[key] = PriceEntry{Key: key, ModelPrice: price, Source: cache.Source, Region: region, Fetched: regional.Updated}
+			}
+		}
+	}
+
+	table := make([]PriceEntry, 0, len(entries))
+	for _, entry := range entries {
+		table = append(table, entry)
+	}
+	sort.Slice(table, func(i, j int) bool {
+		return table[i].Key < table[j].Key
+	})
+	return table, nil
+}
+
+func cachePath() (string, error) {
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return "", fmt.Errorf("failed to get home directory: %w", err)
+	}
+	return filepath.Join(home, ".clauderock", "pricing-cache.json"), nil
+}
+
+// LoadCache reads the saved price list; it returns nil when prices were never fetched
+func LoadCache() (*Cache, error) {
+	path, err := cachePath()
+	if err != nil {
+		return nil, err
+	}
+	var cache Cache
+	found, err := fileutil.LoadJSON(path, &cache)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read pricing cache: %w", err)
+	}
+	if !found {
+		return nil, nil
+	}
+	return &cache, nil
+}
+
+// Refresh fetches prices for region from source and saves them, replacing that region's prices
+// The first region fetched also becomes the default for regions without their own prices
+func Refresh(ctx context.Context, source Source, region string) (*Cache, error) {
+	path, err := cachePath()
+	if err != nil {
+		return nil, err
+	}
+	cache, err := fileutil.RefreshCache(path, func(cache *Cache) error {
+		if cache.Regions == nil {
+			cache.Regions = make(map[string]RegionPrices)
+		}
+		isDefault := cache.Region == "" || cache.Region == region
+
+		now := time.Now()
+		entry := cache.Regions[region]
+		entry.Checked = now
+		if isDefault {
+			cache.Checked = now
+		}
+		defer func() { cache.Regions[region] = entry }()
+
+		prices, err := source.Fetch(ctx, region)
+		if err == nil && len(prices) == 0 {
+			err = fmt.Errorf("%s returned no recognised model prices for %s", source.Name(), region)
+		}
+		if err != nil {
+			return err
+		}
+		entry.Updated = now
+		entry.Prices = prices
+		cache.Source = source.Name()
+		if isDefault {
+			cache.Updated = now
+			cache.Region = region
+			cache.Prices = prices
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	tableMu.Lock()
+	table = nil
+	regionTables = nil
+	tableMu.Unlock()
+	return cache, nil
+}
+
+// RefreshIfStale refreshes the cached prices for region when they're older than CacheTTL
+func RefreshIfStale(ctx context.Context, source Source, region string) error {
+	if cache, err := LoadCache(); err == nil && cache != nil && time.Since(cache.checked(region)) < CacheTTL {
+		return nil
+	}
+	_, err := Refresh(ctx, source, region)
+	return err
+}
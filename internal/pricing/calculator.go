@@ -3,20 +3,22 @@ package pricing
 import (
 	"strings"
 
+	"github.com/OlaHulleberg/clauderock/internal/config"
 	"golang.org/x/text/cases"
 	"golang.org/x/text/language"
 )
 
 type ModelPrice struct {
-	Provider   string
-	Model      string
-	InputCost  float64 // Cost per 1M input tokens
-	OutputCost float64 // Cost per 1M output tokens
+	Provider   string  `json:"provider"`
+	Model      string  `json:"model"`
+	InputCost  float64 `json:"input-cost"`  // Cost per 1M input tokens
+	OutputCost float64 `json:"output-cost"` // Cost per 1M output tokens
 }
 
-// PricingTable contains AWS Bedrock pricing as of October 2025
+// defaultPrices contains AWS Bedrock pricing as of October 2025, used for models the
+// cached price list doesn't cover or before it has been fetched
 // Prices are per 1M tokens
-var PricingTable = map[string]ModelPrice{
+var defaultPrices = map[string]ModelPrice{
 	"anthropic.claude-opus-4": {
 		Provider:   "anthropic",
 		Model:      "claude-opus-4",
@@ -68,11 +70,38 @@ var PricingTable = map[string]ModelPrice{
 }
 
 // GetModelPrice looks up pricing for a model
+// Dated snapshot suffixes are ignored ("anthropic.claude-sonnet-4-5-20250929" prices as "anthropic.claude-sonnet-4-5")
 func GetModelPrice(model string) (ModelPrice, bool) {
-	price, ok := PricingTable[model]
+	table := currentTable()
+	if price, ok := table[model]; ok {
+		return price, true
+	}
+	price, ok := table[stripSnapshot(model)]
 	return price, ok
 }
 
+// GetModelPriceInRegion looks up pricing for a model in a region
+// Regions without their own fetched prices, and models missing from them, use the default prices
+func GetModelPriceInRegion(model, region string) (ModelPrice, bool) {
+	if regional := regionTable(region); regional != nil {
+		if price, ok := regional[model]; ok {
+			return price, true
+		}
+		if price, ok := regional[stripSnapshot(model)]; ok {
+			return price, true
+		}
+	}
+	return GetModelPrice(model)
+}
+
+// lookupPrice returns the price of a model in a region, estimated from its family when it isn't in the table
+func lookupPrice(model, region string) (ModelPrice, bool) {
+	if price, ok := GetModelPriceInRegion(model, region); ok {
+		return price, true
+	}
+	return EstimateModelPrice(model)
+}
+
 // EstimateCostPerLaunch estimates average cost per launch
 // This is a rough estimate based on typical usage patterns
 func EstimateCostPerLaunch(model string) float64 {
@@ -98,9 +127,15 @@ func EstimateCostPerLaunch(model string) float64 {
 	return inputCost + outputCost
 }
 
-// CalculateCost calculates exact cost given token counts
+// CalculateCost calculates exact cost given token counts, at the default prices
+// Models missing from the table are priced by family, see EstimateModelPrice
 func CalculateCost(model string, inputTokens, outputTokens int64) float64 {
-	price, ok := GetModelPrice(model)
+	return CalculateCostInRegion(model, "", inputTokens, outputTokens)
+}
+
+// CalculateCostInRegion calculates exact cost given token counts, at the prices for region
+func CalculateCostInRegion(model, region string, inputTokens, outputTokens int64) float64 {
+	price, ok := lookupPrice(model, region)
 	if !ok {
 		return 0.0
 	}
@@ -111,6 +146,45 @@ func CalculateCost(model string, inputTokens, outputTokens int64) float64 {
 	return inputCost + outputCost
 }
 
+// BatchDiscountMultiplier is the share of on-demand prices Bedrock batch inference costs
+const BatchDiscountMultiplier = 0.50
+
+// TierMultiplier returns the share of on-demand token prices paid on a pricing tier
+// Provisioned Throughput is paid per hour rather than per token, so its tokens cost nothing extra
+func TierMultiplier(tier string) float64 {
+	switch tier {
+	case config.PricingTierBatch:
+		return BatchDiscountMultiplier
+	case config.PricingTierProvisioned:
+		return 0
+	}
+	return 1
+}
+
+// Prompt cache token prices relative to the model's input price (Anthropic models)
+const (
+	CacheWriteMultiplier = 1.25
+	CacheReadMultiplier  = 0.10
+)
+
+// CalculateCacheCost calculates the cost of prompt cache reads and writes given token counts
+func CalculateCacheCost(model string, readTokens, writeTokens int64) float64 {
+	return CalculateCacheCostInRegion(model, "", readTokens, writeTokens)
+}
+
+// CalculateCacheCostInRegion calculates the cost of prompt cache reads and writes at the prices for region
+func CalculateCacheCostInRegion(model, region string, readTokens, writeTokens int64) float64 {
+	price, ok := lookupPrice(model, region)
+	if !ok {
+		return 0.0
+	}
+
+	readCost := (float64(readTokens) / 1_000_000.0) * price.InputCost * CacheReadMultiplier
+	writeCost := (float64(writeTokens) / 1_000_000.0) * price.InputCost * CacheWriteMultiplier
+
+	return readCost + writeCost
+}
+
 // GetProviderName extracts provider name from model string
 func GetProviderName(model string) string {
 	parts := strings.SplitN(model, ".", 2)
@@ -0,0 +1,52 @@
+package pricing
+
+import (
+	"strings"
+
+	"github.com/OlaHulleberg/clauderock/internal/aws"
+	"github.com/OlaHulleberg/clauderock/internal/config"
+)
+
+// EstimateModelPrice prices a model missing from the table like the newest known model of
+// the same family (opus, sonnet, haiku), so new versions aren't reported as free
+// The returned price's Model names the model it was based on
+func EstimateModelPrice(model string) (ModelPrice, bool) {
+	family, _, ok := aws.ModelFamilyVersion(model)
+	if !ok {
+		return ModelPrice{}, false
+	}
+
+	var best ModelPrice
+	var bestVersion []int
+	found := false
+	for key, price := range currentTable() {
+		keyFamily, keyVersion, ok := aws.ModelFamilyVersion(key)
+		if !ok || keyFamily != family {
+			continue
+		}
+		if !found || aws.CompareModelVersions(keyVersion, bestVersion) > 0 {
+			best, bestVersion, found = price, keyVersion, true
+		}
+	}
+	return best, found
+}
+
+// IsEstimated reports whether a model's cost is a family estimate rather than a listed price
+func IsEstimated(model string) bool {
+	if _, ok := GetModelPrice(model); ok {
+		return false
+	}
+	_, ok := EstimateModelPrice(model)
+	return ok
+}
+
+// stripSnapshot removes a dated snapshot and version suffix from a model name
+// Input: "anthropic.claude-sonnet-4-5-20250929-v1:0"
+// Output: "anthropic.claude-sonnet-4-5"
+func stripSnapshot(model string) string {
+	date := config.SnapshotDate(model)
+	if date == "" {
+		return model
+	}
+	return model[:strings.Index(model+"-", "-"+date+"-")]
+}
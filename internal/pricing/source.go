@@ -0,0 +1,172 @@
+package pricing
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// Source provides current model prices, keyed like the built-in table (e.g., "anthropic.claude-sonnet-4-5")
+type Source interface {
+	Name() string
+	Fetch(ctx context.Context, region string) (map[string]ModelPrice, error)
+}
+
+// priceListURL is the AWS Price List bulk API offer file for Bedrock in a region
+// It's public, so no AWS credentials are needed
+const priceListURL = "https://pricing.us-east-1.amazonaws.com/offers/v1.0/aws/AmazonBedrock/current/%s/index.json"
+
+// AWSPriceList fetches Bedrock on-demand prices from the AWS Price List API
+type AWSPriceList struct {
+	Client *http.Client // Defaults to http.DefaultClient
+}
+
+func (AWSPriceList) Name() string {
+	return "aws-price-list"
+}
+
+// priceListOffer is the subset of an offer file used to read token prices
+type priceListOffer struct {
+	Products map[string]struct {
+		Attributes map[string]string `json:"attributes"`
+	} `json:"products"`
+	Terms struct {
+		OnDemand map[string]map[string]priceListTerm `json:"OnDemand"` // SKU -> offer term code -> term
+	} `json:"terms"`
+}
+
+type priceListTerm struct {
+	PriceDimensions map[string]struct {
+		Unit         string            `json:"unit"`
+		PricePerUnit map[string]string `json:"pricePerUnit"`
+	} `json:"priceDimensions"`
+}
+
+func (s AWSPriceList) Fetch(ctx context.Context, region string) (map[string]ModelPrice, error) {
+	client := s.Client
+	if client == nil {
+		client = http.DefaultClient
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, fmt.Sprintf(priceListURL, region), nil)
+	if err != nil {
+		return nil, err
+	}
+	resp, err := client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch price list: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("price list API returned status %d for region %s", resp.StatusCode, region)
+	}
+
+	var offer priceListOffer
+	if err := json.NewDecoder(resp.Body).Decode(&offer); err != nil {
+		return nil, fmt.Errorf("failed to parse price list: %w", err)
+	}
+	return parseOffer(offer), nil
+}
+
+// parseOffer turns the on-demand input/output token prices of an offer into model prices
+func parseOffer(offer priceListOffer) map[string]ModelPrice {
+	prices := make(map[string]ModelPrice)
+	for sku, product := range offer.Products {
+		key, ok := priceKeyFromListing(product.Attributes["model"])
+		if !ok {
+			continue
+		}
+		if feature := strings.ToLower(product.Attributes["feature"]); feature != "" && !strings.Contains(feature, "on-demand") {
+			continue // Batch, provisioned throughput, ...
+		}
+		inferenceType := strings.ToLower(product.Attributes["inferenceType"])
+		if strings.Contains(inferenceType, "cache") || strings.Contains(inferenceType, "batch") {
+			continue
+		}
+
+		perMillion, ok := onDemandPerMillion(offer.Terms.OnDemand[sku])
+		if !ok {
+			continue
+		}
+
+		price := prices[key]
+		price.Provider = "anthropic"
+		price.Model = strings.TrimPrefix(key, "anthropic.")
+		// Regional and global listings can differ slightly; keep the higher price so
+		// estimates don't understate cost
+		switch {
+		case strings.Contains(inferenceType, "input"):
+			if perMillion > price.InputCost {
+				price.InputCost = perMillion
+			}
+		case strings.Contains(inferenceType, "output"):
+			if perMillion > price.OutputCost {
+				price.OutputCost = perMillion
+			}
+		default:
+			continue
+		}
+		prices[key] = price
+	}
+
+	// A model is only usable with both prices
+	for key, price := range prices {
+		if price.InputCost == 0 || price.OutputCost == 0 {
+			delete(prices, key)
+		}
+	}
+	return prices
+}
+
+// onDemandPerMillion returns a SKU's USD price per 1M tokens
+func onDemandPerMillion(terms map[string]priceListTerm) (float64, bool) {
+	for _, term := range terms {
+		for _, dimension := range term.PriceDimensions {
+			usd, err := strconv.ParseFloat(dimension.PricePerUnit["USD"], 64)
+			if err != nil || usd <= 0 {
+				continue
+			}
+			unit := strings.ToLower(dimension.Unit)
+			switch {
+			case strings.Contains(unit, "1m") || strings.Contains(unit, "million"):
+				return usd, true
+			case strings.Contains(unit, "1k"):
+				return usd * 1000, true
+			case strings.Contains(unit, "token"):
+				return usd * 1_000_000, true
+			}
+		}
+	}
+	return 0, false
+}
+
+var listingVersion = regexp.MustCompile(`\d+(\.\d+)?`)
+
+// priceKeyFromListing maps a price list model name to a price key
+// Input: "Claude Sonnet 4.5" or "Claude 3.5 Haiku"
+// Output: "anthropic.claude-sonnet-4-5" or "anthropic.claude-haiku-3-5"
+func priceKeyFromListing(model string) (string, bool) {
+	lower := strings.ToLower(model)
+	if !strings.Contains(lower, "claude") || strings.Contains(lower, "long context") {
+		return "", false
+	}
+
+	var family string
+	for _, f := range []string{"opus", "sonnet", "haiku"} {
+		if strings.Contains(lower, f) {
+			family = f
+			break
+		}
+	}
+	version := listingVersion.FindString(lower)
+	if family == "" || version == "" {
+		return "", false
+	}
+
+	return fmt.Sprintf("anthropic.claude-%s-%s", family, strings.ReplaceAll(version, ".", "-")), true
+}
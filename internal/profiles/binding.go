@@ -0,0 +1,118 @@
+package profiles
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/OlaHulleberg/clauderock/internal/fileutil"
+)
+
+// ProjectFileName is the per-directory file that binds a repository to a profile
+const ProjectFileName = ".clauderock"
+
+// ProjectBinding is the content of a .clauderock project file
+type ProjectBinding struct {
+	Profile string `json:"profile"`
+	Path    string `json:"-"` // File the binding was read from
+}
+
+// FindProjectBinding looks for a .clauderock file in dir and its parents
+// Returns nil when no directory up to the filesystem root has one
+// Directories named .clauderock (such as ~/.clauderock) are not project files and are skipped
+func FindProjectBinding(dir string) (*ProjectBinding, error) {
+	dir, err := filepath.Abs(dir)
+	if err != nil {
+		return nil, fmt.Errorf("failed to resolve %s: %w", dir, err)
+	}
+
+	for {
+		path := filepath.Join(dir, ProjectFileName)
+		if info, err := os.Stat(path); err == nil && info.Mode().IsRegular() {
+			return readProjectBinding(path)
+		}
+
+		parent := filepath.Dir(dir)
+		if parent == dir {
+			return nil, nil
+		}
+		dir = parent
+	}
+}
+
+func readProjectBinding(path string) (*ProjectBinding, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read %s: %w", path, err)
+	}
+
+	var binding ProjectBinding
+	if err := json.Unmarshal(data, &binding); err != nil {
+		return nil, fmt.Errorf("failed to parse %s: %w", path, err)
+	}
+	binding.Profile = strings.TrimSpace(binding.Profile)
+	if binding.Profile == "" {
+		return nil, fmt.Errorf("%s doesn't name a profile", path)
+	}
+	binding.Path = path
+	return &binding, nil
+}
+
+// ProjectProfile returns the binding that applies to a launch from dir, or nil when there is none
+// CLAUDEROCK_PROFILE is an explicit choice for the shell, so it wins over a binding
+func (m *Manager) ProjectProfile(dir string) (*ProjectBinding, error) {
+	if m.IsCurrentFromEnv() {
+		return nil, nil
+	}
+	binding, err := FindProjectBinding(dir)
+	if err != nil || binding == nil {
+		return nil, err
+	}
+	if !m.Exists(binding.Profile) {
+		return nil, fmt.Errorf("profile '%s' bound by %s does not exist", binding.Profile, binding.Path)
+	}
+	return binding, nil
+}
+
+// BindProject writes a .clauderock file in dir binding it to a profile
+func (m *Manager) BindProject(dir, name string) (string, error) {
+	if !m.Exists(name) {
+		return "", fmt.Errorf("profile '%s' does not exist", name)
+	}
+
+	path := filepath.Join(dir, ProjectFileName)
+	if info, err := os.Stat(path); err == nil && info.IsDir() {
+		return "", fmt.Errorf("%s is a directory", path)
+	}
+
+	data, err := json.MarshalIndent(ProjectBinding{Profile: name}, "", "  ")
+	if err != nil {
+		return "", fmt.Errorf("failed to marshal binding: %w", err)
+	}
+	if err := fileutil.WriteFileAtomic(path, append(data, '\n'), 0644); err != nil {
+		return "", fmt.Errorf("failed to write %s: %w", path, err)
+	}
+	return path, nil
+}
+
+// UnbindProject removes the .clauderock file in dir
+// Parent directories are left alone, so a nested repository can't unbind its parent by accident
+func UnbindProject(dir string) (string, error) {
+	path := filepath.Join(dir, ProjectFileName)
+	info, err := os.Stat(path)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return "", fmt.Errorf("%s has no %s file", dir, ProjectFileName)
+		}
+		return "", fmt.Errorf("failed to check %s: %w", path, err)
+	}
+	if !info.Mode().IsRegular() {
+		return "", fmt.Errorf("%s is not a project file", path)
+	}
+	if err := os.Remove(path); err != nil {
+		return "", fmt.Errorf("failed to remove %s: %w", path, err)
+	}
+	return path, nil
+}
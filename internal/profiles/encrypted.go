@@ -0,0 +1,62 @@
+package profiles
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+
+	"github.com/OlaHulleberg/clauderock/internal/keyring"
+)
+
+// encryptedRef is all profiles/<name>.json holds for an encrypted profile
+type encryptedRef struct {
+	KeyringID string `json:"encrypted-profile"`
+}
+
+// parseEncryptedRef returns the keyring entry an encrypted profile file points at,
+// or "" for a plaintext profile
+func parseEncryptedRef(data []byte) string {
+	var ref encryptedRef
+	if json.Unmarshal(data, &ref) != nil {
+		return ""
+	}
+	return ref.KeyringID
+}
+
+// storedEncryptedRef returns the keyring entry of a profile as currently stored, or ""
+func (m *Manager) storedEncryptedRef(name string) string {
+	data, err := os.ReadFile(m.profilePath(name))
+	if err != nil {
+		return ""
+	}
+	return parseEncryptedRef(data)
+}
+
+// decryptProfile returns the profile JSON of a profile file, reading encrypted profiles from the keyring
+func decryptProfile(name string, data []byte) ([]byte, error) {
+	id := parseEncryptedRef(data)
+	if id == "" {
+		return data, nil
+	}
+	plaintext, err := keyring.Get(id)
+	if err != nil {
+		return nil, fmt.Errorf("failed to decrypt profile '%s': %w", name, err)
+	}
+	return []byte(plaintext), nil
+}
+
+// encryptProfile stores profile JSON in the keyring, reusing the profile's existing entry,
+// and returns the reference to write to its file
+func (m *Manager) encryptProfile(name string, data []byte) ([]byte, error) {
+	id := m.storedEncryptedRef(name)
+	if id == "" {
+		var err error
+		if id, err = keyring.GenerateID(); err != nil {
+			return nil, err
+		}
+	}
+	if err := keyring.Store(id, string(data)); err != nil {
+		return nil, fmt.Errorf("failed to encrypt profile '%s': %w", name, err)
+	}
+	return json.MarshalIndent(encryptedRef{KeyringID: id}, "", "  ")
+}
@@ -0,0 +1,149 @@
+package profiles
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/OlaHulleberg/clauderock/internal/fileutil"
+)
+
+// Group is a set of profiles, typically one per Bedrock account, that launches rotate across
+type Group struct {
+	Name       string               `json:"name"`
+	Members    []string             `json:"members"`
+	LastPicked map[string]time.Time `json:"last-picked,omitempty"`
+}
+
+// groupsDir returns the directory holding profile groups
+func (m *Manager) groupsDir() string {
+	return filepath.Join(filepath.Dir(m.profilesDir), "groups")
+}
+
+func (m *Manager) groupPath(name string) string {
+	return filepath.Join(m.groupsDir(), name+".json")
+}
+
+// ListGroups returns all profile group names
+func (m *Manager) ListGroups() ([]string, error) {
+	entries, err := os.ReadDir(m.groupsDir())
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to read groups directory: %w", err)
+	}
+
+	var groups []string
+	for _, entry := range entries {
+		if !entry.IsDir() && strings.HasSuffix(entry.Name(), ".json") {
+			groups = append(groups, strings.TrimSuffix(entry.Name(), ".json"))
+		}
+	}
+	sort.Strings(groups)
+	return groups, nil
+}
+
+// LoadGroup loads a profile group by name
+func (m *Manager) LoadGroup(name string) (*Group, error) {
+	if err := checkPathSafe("group", name); err != nil {
+		return nil, err
+	}
+	data, err := os.ReadFile(m.groupPath(name))
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, fmt.Errorf("group '%s' does not exist", name)
+		}
+		return nil, fmt.Errorf("failed to read group: %w", err)
+	}
+
+	var group Group
+	if err := json.Unmarshal(data, &group); err != nil {
+		return nil, fmt.Errorf("failed to parse group: %w", err)
+	}
+	group.Name = name
+	return &group, nil
+}
+
+// SaveGroup saves a profile group, checking that every member profile exists
+func (m *Manager) SaveGroup(group *Group) error {
+	unlock, err := m.lock()
+	if err != nil {
+		return err
+	}
+	defer unlock()
+
+	return m.writeGroup(group)
+}
+
+// writeGroup validates and atomically replaces a group's file; callers hold the lock
+func (m *Manager) writeGroup(group *Group) error {
+	if err := checkPathSafe("group", group.Name); err != nil {
+		return err
+	}
+	if _, err := os.Stat(m.groupPath(group.Name)); os.IsNotExist(err) {
+		if err := ValidateGroupName(group.Name); err != nil {
+			return err
+		}
+	}
+	if len(group.Members) == 0 {
+		return fmt.Errorf("group '%s' needs at least one member profile", group.Name)
+	}
+	for _, member := range group.Members {
+		if !m.Exists(member) {
+			return fmt.Errorf("profile '%s' does not exist", member)
+		}
+	}
+
+	if err := os.MkdirAll(m.groupsDir(), 0755); err != nil {
+		return fmt.Errorf("failed to create groups directory: %w", err)
+	}
+
+	data, err := json.MarshalIndent(group, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to marshal group: %w", err)
+	}
+	if err := fileutil.WriteFileAtomic(m.groupPath(group.Name), data, 0644); err != nil {
+		return fmt.Errorf("failed to write group: %w", err)
+	}
+	return nil
+}
+
+// DeleteGroup removes a profile group; member profiles are left untouched
+func (m *Manager) DeleteGroup(name string) error {
+	if err := checkPathSafe("group", name); err != nil {
+		return err
+	}
+	if err := os.Remove(m.groupPath(name)); err != nil {
+		if os.IsNotExist(err) {
+			return fmt.Errorf("group '%s' does not exist", name)
+		}
+		return fmt.Errorf("failed to delete group: %w", err)
+	}
+	return nil
+}
+
+// MarkPicked records that a member was chosen for a launch, so ties rotate to the next member
+// The group is reloaded under the lock, so launches picking at the same time all count
+func (m *Manager) MarkPicked(group *Group, member string) error {
+	unlock, err := m.lock()
+	if err != nil {
+		return err
+	}
+	defer unlock()
+
+	stored, err := m.LoadGroup(group.Name)
+	if err != nil {
+		return err
+	}
+	if stored.LastPicked == nil {
+		stored.LastPicked = make(map[string]time.Time)
+	}
+	stored.LastPicked[member] = time.Now()
+	group.LastPicked = stored.LastPicked
+	return m.writeGroup(stored)
+}
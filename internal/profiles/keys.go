@@ -0,0 +1,64 @@
+package profiles
+
+import (
+	"os"
+
+	"github.com/OlaHulleberg/clauderock/internal/keyring"
+)
+
+// keyReferences returns the keyring entries profiles refer to, API keys and encrypted
+// profiles alike; ok is false when a profile can't be read, since an entry it refers to
+// can't be ruled out
+func (m *Manager) keyReferences() (refs map[string]bool, ok bool) {
+	names, err := m.List()
+	if err != nil {
+		return nil, false
+	}
+
+	refs = make(map[string]bool)
+	for _, name := range names {
+		data, err := os.ReadFile(m.profilePath(name))
+		if err != nil {
+			return nil, false
+		}
+		if id := parseEncryptedRef(data); id != "" {
+			refs[id] = true
+		}
+		cfg, err := m.Load(name)
+		if err != nil {
+			return nil, false
+		}
+		if cfg.APIKeyID != "" {
+			refs[cfg.APIKeyID] = true
+		}
+	}
+	return refs, true
+}
+
+// releaseKey deletes a keyring entry unless a profile still refers to it, which copies made
+// by hand or imported before keys were duplicated can; callers hold the lock and have
+// already written or removed the profile that stopped referring to it
+func (m *Manager) releaseKey(id string) error {
+	if id == "" {
+		return nil
+	}
+	refs, ok := m.keyReferences()
+	if !ok || refs[id] {
+		return nil
+	}
+	return keyring.Delete(id)
+}
+
+// ReleaseAPIKey deletes an API key stored for a save that then failed, unless a profile
+// refers to it after all
+// It takes the profile lock, so another process can't save a profile referring to the key
+// between the check and the deletion
+func (m *Manager) ReleaseAPIKey(id string) error {
+	unlock, err := m.lock()
+	if err != nil {
+		return err
+	}
+	defer unlock()
+
+	return m.releaseKey(id)
+}
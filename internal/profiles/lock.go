@@ -0,0 +1,17 @@
+package profiles
+
+import (
+	"path/filepath"
+
+	"github.com/OlaHulleberg/clauderock/internal/fileutil"
+)
+
+// lock takes the advisory lock serializing changes to the profile store across clauderock processes
+// The lock is not reentrant: methods holding it must use the unlocked helpers (writeProfile, writeCurrent)
+// Readers don't take it, since files are replaced atomically
+func (m *Manager) lock() (unlock func(), err error) {
+	if err := m.ensureBaseDir(); err != nil {
+		return nil, err
+	}
+	return fileutil.Lock(filepath.Join(filepath.Dir(m.profilesDir), "profiles.lock"), "profiles")
+}
@@ -8,10 +8,16 @@ import (
 	"strings"
 
 	"github.com/OlaHulleberg/clauderock/internal/config"
+	"github.com/OlaHulleberg/clauderock/internal/fileutil"
 	"github.com/OlaHulleberg/clauderock/internal/keyring"
 	"github.com/OlaHulleberg/clauderock/internal/migrations"
+	"github.com/OlaHulleberg/clauderock/internal/policy"
 )
 
+// CurrentProfileEnvVar overrides current-profile.txt for the shell it is set in,
+// so separate terminals can use different current profiles at the same time
+const CurrentProfileEnvVar = "CLAUDEROCK_PROFILE"
+
 type Manager struct {
 	profilesDir     string
 	currentFilePath string
@@ -57,6 +63,9 @@ func (m *Manager) List() ([]string, error) {
 
 // Load loads a specific profile by name
 func (m *Manager) Load(name string) (*config.Config, error) {
+	if err := checkPathSafe("profile", name); err != nil {
+		return nil, err
+	}
 	if err := m.ensureProfilesDir(); err != nil {
 		return nil, err
 	}
@@ -69,6 +78,9 @@ func (m *Manager) Load(name string) (*config.Config, error) {
 		}
 		return nil, fmt.Errorf("failed to read profile: %w", err)
 	}
+	if data, err = decryptProfile(name, data); err != nil {
+		return nil, err
+	}
 
 	var cfg config.Config
 	if err := json.Unmarshal(data, &cfg); err != nil {
@@ -78,8 +90,63 @@ func (m *Manager) Load(name string) (*config.Config, error) {
 	return &cfg, nil
 }
 
+// ReadRaw returns a profile's JSON as stored (decrypted for encrypted profiles), for validation
+func (m *Manager) ReadRaw(name string) ([]byte, error) {
+	if err := checkPathSafe("profile", name); err != nil {
+		return nil, err
+	}
+	data, err := os.ReadFile(m.profilePath(name))
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, fmt.Errorf("profile '%s' does not exist", name)
+		}
+		return nil, fmt.Errorf("failed to read profile: %w", err)
+	}
+	return decryptProfile(name, data)
+}
+
 // Save saves a configuration as a named profile
+// The configuration must also comply with the organisation policy, if one is installed
 func (m *Manager) Save(name string, cfg *config.Config) error {
+	if err := policy.Enforce(cfg); err != nil {
+		return err
+	}
+
+	return m.saveValidated(name, cfg)
+}
+
+// Update applies change to the stored profile and saves it, holding the lock from reading the
+// profile to writing it, so concurrent updates don't overwrite each other
+// Slow work such as prompts or network calls belongs before Update, which only applies the result
+func (m *Manager) Update(name string, change func(cfg *config.Config) error) (*config.Config, error) {
+	unlock, err := m.lock()
+	if err != nil {
+		return nil, err
+	}
+	defer unlock()
+
+	cfg, err := m.Load(name)
+	if err != nil {
+		return nil, err
+	}
+	if err := change(cfg); err != nil {
+		return nil, err
+	}
+	if err := policy.Enforce(cfg); err != nil {
+		return nil, err
+	}
+	if err := cfg.Validate(); err != nil {
+		return nil, fmt.Errorf("invalid configuration: %w", err)
+	}
+	if err := m.writeProfile(name, cfg); err != nil {
+		return nil, err
+	}
+	return cfg, nil
+}
+
+// saveValidated saves a validated config without checking the policy, so migrations
+// of existing profiles never lock users out of fixing them
+func (m *Manager) saveValidated(name string, cfg *config.Config) error {
 	if err := m.ensureProfilesDir(); err != nil {
 		return err
 	}
@@ -93,6 +160,21 @@ func (m *Manager) Save(name string, cfg *config.Config) error {
 
 // saveWithoutValidation saves a config without validation (used internally)
 func (m *Manager) saveWithoutValidation(name string, cfg *config.Config) error {
+	unlock, err := m.lock()
+	if err != nil {
+		return err
+	}
+	defer unlock()
+
+	return m.writeProfile(name, cfg)
+}
+
+// writeProfile atomically replaces a profile's file; callers hold the lock
+// Every save ends here, so this is where names are checked before they become paths
+func (m *Manager) writeProfile(name string, cfg *config.Config) error {
+	if err := m.checkSaveName(name); err != nil {
+		return err
+	}
 	if err := m.ensureProfilesDir(); err != nil {
 		return err
 	}
@@ -103,21 +185,83 @@ func (m *Manager) saveWithoutValidation(name string, cfg *config.Config) error {
 		return fmt.Errorf("failed to marshal config: %w", err)
 	}
 
-	if err := os.WriteFile(path, data, 0644); err != nil {
+	// An API key the profile stops referring to, such as one the wizard replaced, is released
+	// once the new version is written
+	var droppedKeyID string
+	if stored, err := m.Load(name); err == nil && stored.APIKeyID != cfg.APIKeyID {
+		droppedKeyID = stored.APIKeyID
+	}
+
+	// Encrypted profiles go to the keyring; a profile that stops being encrypted drops its entry
+	staleKeyringID := m.storedEncryptedRef(name)
+	if cfg.Encrypted {
+		if data, err = m.encryptProfile(name, data); err != nil {
+			return err
+		}
+		staleKeyringID = ""
+	}
+
+	// Profiles reference AWS profiles and keyring entries, so only the user may read them
+	if err := fileutil.WriteFileAtomic(path, data, 0600); err != nil {
 		return fmt.Errorf("failed to write profile: %w", err)
 	}
 
+	if err := m.releaseKey(staleKeyringID); err != nil {
+		fmt.Printf("Warning: failed to delete encrypted copy of profile '%s': %v\n", name, err)
+	}
+	if err := m.releaseKey(droppedKeyID); err != nil {
+		fmt.Printf("Warning: failed to delete the replaced API key of profile '%s': %v\n", name, err)
+	}
+
 	return nil
 }
 
+// MarkVerified clears the unverified flag of a profile once its models were validated
+// It is a no-op when the stored models differ from the validated ones (e.g., launch overrides)
+func (m *Manager) MarkVerified(name string, modelIDs ...string) error {
+	unlock, err := m.lock()
+	if err != nil {
+		return err
+	}
+	defer unlock()
+
+	cfg, err := m.Load(name)
+	if err != nil {
+		return err
+	}
+	if !cfg.Unverified {
+		return nil
+	}
+
+	stored := map[string]bool{cfg.Model: true, cfg.FastModel: true, cfg.HeavyModel: true}
+	for _, id := range modelIDs {
+		if !stored[id] {
+			return nil
+		}
+	}
+
+	cfg.Unverified = false
+	return m.writeProfile(name, cfg)
+}
+
 // Delete removes a profile and its associated keyring entry (if API profile)
 func (m *Manager) Delete(name string) error {
+	if err := checkPathSafe("profile", name); err != nil {
+		return err
+	}
 	if name == "default" {
 		return fmt.Errorf("cannot delete default profile")
 	}
 
-	current, _ := m.GetCurrent()
-	if current == name {
+	unlock, err := m.lock()
+	if err != nil {
+		return err
+	}
+	defer unlock()
+
+	// The global pointer, not this shell's CLAUDEROCK_PROFILE, is what deleting would leave dangling
+	global, _ := m.globalCurrent()
+	if global == name {
 		return fmt.Errorf("cannot delete active profile, switch to another profile first")
 	}
 
@@ -127,15 +271,8 @@ func (m *Manager) Delete(name string) error {
 		return fmt.Errorf("failed to load profile for cleanup: %w", err)
 	}
 
-	// If it's an API profile, delete the keyring entry
-	if cfg != nil && cfg.ProfileType == "api" && cfg.APIKeyID != "" {
-		if err := keyring.Delete(cfg.APIKeyID); err != nil {
-			// Log warning but don't fail deletion
-			fmt.Printf("Warning: failed to delete keyring entry: %v\n", err)
-		}
-	}
-
 	path := m.profilePath(name)
+	encryptedID := m.storedEncryptedRef(name)
 	if err := os.Remove(path); err != nil {
 		if os.IsNotExist(err) {
 			return fmt.Errorf("profile '%s' does not exist", name)
@@ -143,18 +280,41 @@ func (m *Manager) Delete(name string) error {
 		return fmt.Errorf("failed to delete profile: %w", err)
 	}
 
+	// Keyring entries go with the profile, unless another profile still refers to them
+	if cfg != nil && cfg.ProfileType == "api" {
+		if err := m.releaseKey(cfg.APIKeyID); err != nil {
+			// Log warning but don't fail deletion
+			fmt.Printf("Warning: failed to delete keyring entry: %v\n", err)
+		}
+	}
+	if err := m.releaseKey(encryptedID); err != nil {
+		fmt.Printf("Warning: failed to delete encrypted profile from keyring: %v\n", err)
+	}
+
 	return nil
 }
 
 // Exists checks if a profile exists
 func (m *Manager) Exists(name string) bool {
+	if checkPathSafe("profile", name) != nil {
+		return false
+	}
 	path := m.profilePath(name)
 	_, err := os.Stat(path)
 	return err == nil
 }
 
 // GetCurrent returns the name of the current active profile
+// CLAUDEROCK_PROFILE takes precedence over the global current-profile.txt pointer
 func (m *Manager) GetCurrent() (string, error) {
+	if name := strings.TrimSpace(os.Getenv(CurrentProfileEnvVar)); name != "" {
+		return name, nil
+	}
+	return m.globalCurrent()
+}
+
+// globalCurrent returns the profile current-profile.txt points at, ignoring CLAUDEROCK_PROFILE
+func (m *Manager) globalCurrent() (string, error) {
 	data, err := os.ReadFile(m.currentFilePath)
 	if err != nil {
 		if os.IsNotExist(err) {
@@ -172,8 +332,24 @@ func (m *Manager) GetCurrent() (string, error) {
 	return name, nil
 }
 
+// IsCurrentFromEnv reports whether the current profile comes from CLAUDEROCK_PROFILE
+func (m *Manager) IsCurrentFromEnv() bool {
+	return strings.TrimSpace(os.Getenv(CurrentProfileEnvVar)) != ""
+}
+
 // SetCurrent sets the current active profile
 func (m *Manager) SetCurrent(name string) error {
+	unlock, err := m.lock()
+	if err != nil {
+		return err
+	}
+	defer unlock()
+
+	return m.writeCurrent(name)
+}
+
+// writeCurrent atomically points current-profile.txt at an existing profile; callers hold the lock
+func (m *Manager) writeCurrent(name string) error {
 	if !m.Exists(name) {
 		return fmt.Errorf("profile '%s' does not exist", name)
 	}
@@ -182,7 +358,7 @@ func (m *Manager) SetCurrent(name string) error {
 		return err
 	}
 
-	if err := os.WriteFile(m.currentFilePath, []byte(name), 0644); err != nil {
+	if err := fileutil.WriteFileAtomic(m.currentFilePath, []byte(name), 0644); err != nil {
 		return fmt.Errorf("failed to set current profile: %w", err)
 	}
 
@@ -201,6 +377,11 @@ func (m *Manager) GetCurrentConfig(cliVersion string) (*config.Config, error) {
 		return nil, err
 	}
 
+	// A per-shell override must name an existing profile rather than silently creating one
+	if m.IsCurrentFromEnv() && !m.Exists(current) {
+		return nil, fmt.Errorf("profile '%s' from %s does not exist", current, CurrentProfileEnvVar)
+	}
+
 	// If current profile doesn't exist, create default with current CLI version
 	if !m.Exists(current) {
 		cfg := m.createDefaultConfig(cliVersion)
@@ -228,13 +409,13 @@ func (m *Manager) GetCurrentConfig(cliVersion string) (*config.Config, error) {
 
 	if needsMigration {
 		oldVersion := cfg.Version
-		if err := migMgr.MigrateProfile(current, oldVersion, cfg, m); err != nil {
+		if err := migMgr.MigrateProfile(current, oldVersion, cfg, migrationSaver{m}); err != nil {
 			return nil, fmt.Errorf("failed to migrate profile from %s to %s: %w\nPlease run: clauderock manage config", oldVersion, cliVersion, err)
 		}
 		// Update config version to current CLI version (but never "dev")
 		if cliVersion != "dev" {
 			cfg.Version = cliVersion
-			if err := m.Save(current, cfg); err != nil {
+			if err := m.saveValidated(current, cfg); err != nil {
 				return nil, fmt.Errorf("failed to save migrated config: %w", err)
 			}
 		}
@@ -243,16 +424,34 @@ func (m *Manager) GetCurrentConfig(cliVersion string) (*config.Config, error) {
 	return cfg, nil
 }
 
+// migrationSaver saves migrated profiles without policy enforcement
+type migrationSaver struct {
+	m *Manager
+}
+
+func (s migrationSaver) Save(name string, cfg *config.Config) error {
+	return s.m.saveValidated(name, cfg)
+}
+
 // Rename renames a profile
 func (m *Manager) Rename(oldName, newName string) error {
 	if oldName == "default" {
 		return fmt.Errorf("cannot rename default profile")
 	}
 
+	unlock, err := m.lock()
+	if err != nil {
+		return err
+	}
+	defer unlock()
+
 	if !m.Exists(oldName) {
 		return fmt.Errorf("profile '%s' does not exist", oldName)
 	}
 
+	if err := ValidateName(newName); err != nil {
+		return err
+	}
 	if m.Exists(newName) {
 		return fmt.Errorf("profile '%s' already exists", newName)
 	}
@@ -264,10 +463,10 @@ func (m *Manager) Rename(oldName, newName string) error {
 		return fmt.Errorf("failed to rename profile: %w", err)
 	}
 
-	// Update current profile if it was the renamed one
-	current, _ := m.GetCurrent()
-	if current == oldName {
-		if err := m.SetCurrent(newName); err != nil {
+	// Update the global pointer if it was the renamed one; CLAUDEROCK_PROFILE is the shell's to change
+	global, _ := m.globalCurrent()
+	if global == oldName {
+		if err := m.writeCurrent(newName); err != nil {
 			return fmt.Errorf("failed to update current profile: %w", err)
 		}
 	}
@@ -281,15 +480,24 @@ func (m *Manager) Copy(sourceName, destName string) error {
 		return fmt.Errorf("profile '%s' does not exist", sourceName)
 	}
 
-	if m.Exists(destName) {
-		return fmt.Errorf("profile '%s' already exists", destName)
-	}
-
 	cfg, err := m.Load(sourceName)
 	if err != nil {
 		return err
 	}
 
+	return m.SaveAs(destName, cfg)
+}
+
+// SaveAs saves a configuration as a new profile, refusing to overwrite an existing one
+// API profiles get their own keychain entry, so deleting either profile keeps the other's key
+func (m *Manager) SaveAs(name string, cfg *config.Config) error {
+	if err := m.checkSaveName(name); err != nil {
+		return err
+	}
+	if m.Exists(name) {
+		return fmt.Errorf("profile '%s' already exists", name)
+	}
+
 	// If it's an API profile, duplicate the keyring entry with a new ID
 	if cfg.ProfileType == "api" && cfg.APIKeyID != "" {
 		// Get the API key from keyring
@@ -309,11 +517,39 @@ func (m *Manager) Copy(sourceName, destName string) error {
 			return fmt.Errorf("failed to store API key in keyring: %w", err)
 		}
 
-		// Update config with new ID
-		cfg.APIKeyID = newID
+		// Update a copy of the config, so the caller's keeps its own entry
+		copied := *cfg
+		copied.APIKeyID = newID
+		if err := m.create(name, &copied); err != nil {
+			_ = m.ReleaseAPIKey(newID)
+			return err
+		}
+		return nil
 	}
 
-	return m.Save(destName, cfg)
+	return m.create(name, cfg)
+}
+
+// create saves a new profile, checking under the lock that the name is still free, so two
+// processes saving the same name can't both succeed
+func (m *Manager) create(name string, cfg *config.Config) error {
+	if err := policy.Enforce(cfg); err != nil {
+		return err
+	}
+	if err := cfg.Validate(); err != nil {
+		return fmt.Errorf("invalid configuration: %w", err)
+	}
+
+	unlock, err := m.lock()
+	if err != nil {
+		return err
+	}
+	defer unlock()
+
+	if m.Exists(name) {
+		return fmt.Errorf("profile '%s' already exists", name)
+	}
+	return m.writeProfile(name, cfg)
 }
 
 // MigrateFromLegacyConfig migrates old config.json to profiles/default.json
@@ -335,6 +571,13 @@ func (m *Manager) MigrateFromLegacyConfig(version string) error {
 		return err
 	}
 
+	// Another process may be migrating at the same time; the second one finds the default profile
+	unlock, err := m.lock()
+	if err != nil {
+		return err
+	}
+	defer unlock()
+
 	// Check if default profile already exists (migration already done)
 	if m.Exists("default") {
 		return nil
@@ -355,12 +598,15 @@ func (m *Manager) MigrateFromLegacyConfig(version string) error {
 	// This is handled internally by config, we just need to save it
 
 	// Save as default profile
-	if err := m.Save("default", &cfg); err != nil {
+	if err := cfg.Validate(); err != nil {
+		return fmt.Errorf("failed to save default profile: invalid configuration: %w", err)
+	}
+	if err := m.writeProfile("default", &cfg); err != nil {
 		return fmt.Errorf("failed to save default profile: %w", err)
 	}
 
 	// Set as current profile
-	if err := m.SetCurrent("default"); err != nil {
+	if err := m.writeCurrent("default"); err != nil {
 		return fmt.Errorf("failed to set current profile: %w", err)
 	}
 
@@ -384,7 +630,7 @@ func (m *Manager) ensureBaseDir() error {
 }
 
 func (m *Manager) ensureProfilesDir() error {
-	return os.MkdirAll(m.profilesDir, 0755)
+	return os.MkdirAll(m.profilesDir, 0700)
 }
 
 func (m *Manager) profilePath(name string) string {
@@ -0,0 +1,86 @@
+package profiles
+
+import (
+	"fmt"
+	"strings"
+	"unicode"
+)
+
+// MaxNameLength bounds profile and group names, well within file name limits
+const MaxNameLength = 64
+
+// windowsReserved are device names Windows won't create files for, with or without an extension
+var windowsReserved = map[string]bool{
+	"CON": true, "PRN": true, "AUX": true, "NUL": true,
+	"COM1": true, "COM2": true, "COM3": true, "COM4": true, "COM5": true, "COM6": true, "COM7": true, "COM8": true, "COM9": true,
+	"LPT1": true, "LPT2": true, "LPT3": true, "LPT4": true, "LPT5": true, "LPT6": true, "LPT7": true, "LPT8": true, "LPT9": true,
+}
+
+// ValidateName checks a name for a new profile: it becomes a file name on every platform
+// profiles may be exported to, so it can't hold path separators or characters Windows
+// rejects, be a reserved device name, or start like a hidden file or a flag; spaces
+// inside are fine, with the name quoted in commands
+func ValidateName(name string) error {
+	return validateName("profile", name)
+}
+
+// ValidateGroupName checks a name for a new profile group, by the same rules as profiles
+func ValidateGroupName(name string) error {
+	return validateName("group", name)
+}
+
+func validateName(kind, name string) error {
+	if err := checkPathSafe(kind, name); err != nil {
+		return err
+	}
+	invalid := func(reason string) error {
+		return fmt.Errorf("invalid %s name '%s': %s", kind, name, reason)
+	}
+
+	if len(name) > MaxNameLength {
+		return invalid(fmt.Sprintf("use at most %d characters", MaxNameLength))
+	}
+	if strings.TrimSpace(name) != name {
+		return invalid("remove the leading or trailing spaces")
+	}
+	if strings.HasPrefix(name, ".") || strings.HasPrefix(name, "-") {
+		return invalid("it can't start with '.' or '-'")
+	}
+	if strings.HasSuffix(name, ".") {
+		return invalid("it can't end with '.'")
+	}
+	for _, r := range name {
+		if unicode.IsControl(r) || strings.ContainsRune(`<>:"|?*`, r) {
+			return invalid(`it can't contain control characters or any of < > : " | ? *`)
+		}
+	}
+	base, _, _ := strings.Cut(name, ".")
+	if windowsReserved[strings.ToUpper(strings.TrimSpace(base))] {
+		return invalid("it is a reserved device name on Windows")
+	}
+	return nil
+}
+
+// checkSaveName checks a name a profile is about to be saved under: existing profiles keep
+// their names, new ones must pass ValidateName
+func (m *Manager) checkSaveName(name string) error {
+	if err := checkPathSafe("profile", name); err != nil {
+		return err
+	}
+	if m.Exists(name) {
+		return nil
+	}
+	return ValidateName(name)
+}
+
+// checkPathSafe rejects names that would reach outside their directory once turned into
+// a path; profiles created before names were validated still pass, so they stay usable
+func checkPathSafe(kind, name string) error {
+	switch {
+	case strings.TrimSpace(name) == "":
+		return fmt.Errorf("%s name cannot be empty", kind)
+	case name == "." || name == ".." || strings.ContainsAny(name, `/\`) || strings.ContainsRune(name, 0):
+		return fmt.Errorf("invalid %s name '%s': it can't contain path separators or be '.' or '..'", kind, name)
+	}
+	return nil
+}
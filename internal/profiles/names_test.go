@@ -0,0 +1,85 @@
+package profiles
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestValidateName(t *testing.T) {
+	tests := []struct {
+		name    string
+		input   string
+		wantErr string // Substring of the error; empty when the name is valid
+	}{
+		{"simple", "work", ""},
+		{"inner spaces and dots", "team prod.eu", ""},
+		{"unicode", "lønn", ""},
+		{"max length", strings.Repeat("a", MaxNameLength), ""},
+		{"reserved name inside a longer one", "console", ""},
+
+		{"empty", "", "cannot be empty"},
+		{"only spaces", "   ", "cannot be empty"},
+		{"slash", "team/prod", "path separators"},
+		{"backslash", `team\prod`, "path separators"},
+		{"nul byte", "team\x00prod", "path separators"},
+		{"dot", ".", "path separators"},
+		{"dot dot", "..", "path separators"},
+		{"traversal", "../prod", "path separators"},
+
+		{"leading dot", ".hidden", "can't start with"},
+		{"leading dash", "-rf", "can't start with"},
+		{"trailing dot", "prod.", "can't end with '.'"},
+		{"leading space", " prod", "leading or trailing spaces"},
+		{"trailing space", "prod ", "leading or trailing spaces"},
+		{"too long", strings.Repeat("a", MaxNameLength+1), "at most"},
+
+		{"colon", "prod:eu", "can't contain"},
+		{"question mark", "prod?", "can't contain"},
+		{"control character", "prod\teu", "can't contain"},
+
+		{"CON", "CON", "reserved device name"},
+		{"nul lower case", "nul", "reserved device name"},
+		{"COM1", "COM1", "reserved device name"},
+		{"reserved with extension", "com1.json", "reserved device name"},
+		{"LPT9", "Lpt9", "reserved device name"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := ValidateName(tt.input)
+			if tt.wantErr == "" {
+				if err != nil {
+					t.Fatalf("ValidateName(%q) = %v, want nil", tt.input, err)
+				}
+				return
+			}
+			if err == nil {
+				t.Fatalf("ValidateName(%q) = nil, want an error containing %q", tt.input, tt.wantErr)
+			}
+			if !strings.Contains(err.Error(), tt.wantErr) {
+				t.Fatalf("ValidateName(%q) = %v, want an error containing %q", tt.input, err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestValidateGroupName(t *testing.T) {
+	err := ValidateGroupName("../team")
+	if err == nil || !strings.Contains(err.Error(), "group name") {
+		t.Fatalf("ValidateGroupName(%q) = %v, want a group name error", "../team", err)
+	}
+}
+
+func TestCheckPathSafe(t *testing.T) {
+	// Names created before validation existed stay usable as long as they are path safe
+	for _, name := range []string{"old name ", ".legacy", "CON", "a:b"} {
+		if err := checkPathSafe("profile", name); err != nil {
+			t.Errorf("checkPathSafe(%q) = %v, want nil", name, err)
+		}
+	}
+	for _, name := range []string{"", " ", ".", "..", "a/b", `a\b`, "a\x00b"} {
+		if err := checkPathSafe("profile", name); err == nil {
+			t.Errorf("checkPathSafe(%q) = nil, want an error", name)
+		}
+	}
+}
@@ -0,0 +1,332 @@
+package profiles
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/pbkdf2"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/OlaHulleberg/clauderock/internal/config"
+	"github.com/OlaHulleberg/clauderock/internal/keyring"
+	"github.com/OlaHulleberg/clauderock/internal/policy"
+)
+
+// ExportFormat identifies a profile export file
+const ExportFormat = "clauderock-profiles"
+
+// exportVersion is the current export file layout
+// Version 2 binds each encrypted API key to the key derivation settings and its profile name
+const exportVersion = 2
+
+// errMalformedExport is returned for an export file that was corrupted or edited by hand
+var errMalformedExport = errors.New("malformed export file")
+
+// kdfIterations is the PBKDF2-SHA256 work factor for export passphrases
+const kdfIterations = 600_000
+
+// Work factors accepted from an export file: a file can't weaken the derivation far below
+// kdfIterations, or make importing it take minutes
+const (
+	minKDFIterations = kdfIterations / 2
+	maxKDFIterations = kdfIterations * 10
+)
+
+// MinPassphraseLength is the shortest passphrase accepted for encrypting API keys
+const MinPassphraseLength = 8
+
+// ExportFile is a portable set of profiles written by Export
+type ExportFile struct {
+	Format            string            `json:"format"`
+	Version           int               `json:"version"`
+	Exported          time.Time         `json:"exported"`
+	ClauderockVersion string            `json:"clauderock-version,omitempty"`
+	Encryption        *ExportEncryption `json:"encryption,omitempty"` // Set when API keys are included
+	Profiles          []ExportedProfile `json:"profiles"`
+}
+
+// ExportEncryption describes how the passphrase key for included API keys is derived
+type ExportEncryption struct {
+	KDF        string `json:"kdf"`
+	Iterations int    `json:"iterations"`
+	Salt       []byte `json:"salt"`
+}
+
+// ExportedProfile is one profile in an export; the machine-specific keyring reference is dropped
+type ExportedProfile struct {
+	Name   string         `json:"name"`
+	Config *config.Config `json:"config"`
+	APIKey *SealedSecret  `json:"api-key,omitempty"` // API key encrypted with the export passphrase
+}
+
+// SealedSecret is a value encrypted with AES-256-GCM
+type SealedSecret struct {
+	Nonce      []byte `json:"nonce"`
+	Ciphertext []byte `json:"ciphertext"`
+}
+
+// NeedsPassphrase reports whether the export includes encrypted API keys
+func (f *ExportFile) NeedsPassphrase() bool {
+	return f.Encryption != nil
+}
+
+// ImportOptions controls how Import handles existing profiles and secrets
+type ImportOptions struct {
+	Passphrase string // Decrypts included API keys
+	Overwrite  bool   // Replace profiles that already exist
+	// APIKey supplies the key for an API profile exported without one
+	APIKey func(profile string) (string, error)
+}
+
+// Export bundles the named profiles into an export file
+// With a passphrase, API keys are included, encrypted with a key derived from it; without one they're left out
+func (m *Manager) Export(names []string, passphrase, cliVersion string) (*ExportFile, error) {
+	file := &ExportFile{
+		Format:            ExportFormat,
+		Version:           exportVersion,
+		Exported:          time.Now().UTC(),
+		ClauderockVersion: cliVersion,
+	}
+
+	var gcm cipher.AEAD
+	if passphrase != "" {
+		if len(passphrase) < MinPassphraseLength {
+			return nil, fmt.Errorf("passphrase must be at least %d characters", MinPassphraseLength)
+		}
+		salt := make([]byte, 16)
+		if _, err := rand.Read(salt); err != nil {
+			return nil, fmt.Errorf("failed to generate salt: %w", err)
+		}
+		file.Encryption = &ExportEncryption{KDF: "pbkdf2-sha256", Iterations: kdfIterations, Salt: salt}
+
+		var err error
+		if gcm, err = file.Encryption.aead(passphrase); err != nil {
+			return nil, err
+		}
+	}
+
+	for _, name := range names {
+		cfg, err := m.Load(name)
+		if err != nil {
+			if os.IsNotExist(err) {
+				return nil, fmt.Errorf("profile '%s' does not exist", name)
+			}
+			return nil, fmt.Errorf("failed to load profile '%s': %w", name, err)
+		}
+
+		exported := ExportedProfile{Name: name, Config: cfg}
+		if cfg.ProfileType == "api" && cfg.APIKeyID != "" && gcm != nil {
+			apiKey, err := keyring.Get(cfg.APIKeyID)
+			if err != nil {
+				return nil, fmt.Errorf("failed to retrieve API key for profile '%s': %w", name, err)
+			}
+			if exported.APIKey, err = sealSecret(gcm, apiKey, file.additionalData(name)); err != nil {
+				return nil, err
+			}
+		}
+		cfg.APIKeyID = ""
+		file.Profiles = append(file.Profiles, exported)
+	}
+
+	return file, nil
+}
+
+// ReadExportFile reads and checks an export file
+func ReadExportFile(path string) (*ExportFile, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read export file: %w", err)
+	}
+
+	var file ExportFile
+	if err := json.Unmarshal(data, &file); err != nil {
+		return nil, fmt.Errorf("failed to parse export file: %w", err)
+	}
+	if file.Format != ExportFormat {
+		return nil, fmt.Errorf("%s is not a clauderock profile export", path)
+	}
+	if file.Version > exportVersion {
+		return nil, fmt.Errorf("export file version %d is newer than this clauderock supports (%d); update clauderock first", file.Version, exportVersion)
+	}
+	if file.Version != exportVersion {
+		return nil, fmt.Errorf("%w: version %d is not a clauderock export version", errMalformedExport, file.Version)
+	}
+	for _, p := range file.Profiles {
+		if p.Name == "" || p.Config == nil {
+			return nil, fmt.Errorf("export file has a profile without a name or configuration")
+		}
+	}
+	return &file, nil
+}
+
+// SensitiveSettings lists the settings in the export that decide where requests, usage
+// records, or environment variables go, one line per setting, for review before importing:
+// a shared file could otherwise route traffic or usage to someone else
+func (f *ExportFile) SensitiveSettings() []string {
+	var lines []string
+	for _, p := range f.Profiles {
+		if p.Config.BaseURL != "" {
+			lines = append(lines, fmt.Sprintf("%s: base-url %s", p.Name, p.Config.BaseURL))
+		}
+		if p.Config.TeamSync != "" {
+			lines = append(lines, fmt.Sprintf("%s: team-sync %s", p.Name, p.Config.TeamSync))
+		}
+		if len(p.Config.EnvAllowlist) > 0 {
+			lines = append(lines, fmt.Sprintf("%s: env-allowlist %s", p.Name, strings.Join(p.Config.EnvAllowlist, ", ")))
+		}
+	}
+	return lines
+}
+
+// Import saves the profiles of an export file, storing their API keys in the keyring
+// Every profile is validated, and its API key obtained, before any is saved
+func (m *Manager) Import(file *ExportFile, opts ImportOptions) ([]string, error) {
+	var gcm cipher.AEAD
+	if file.NeedsPassphrase() {
+		var err error
+		if gcm, err = file.Encryption.aead(opts.Passphrase); err != nil {
+			return nil, err
+		}
+	}
+
+	apiKeys := make(map[string]string)
+	for _, p := range file.Profiles {
+		// Names come from the file, so they must not lead outside the profiles directory
+		if err := m.checkSaveName(p.Name); err != nil {
+			return nil, err
+		}
+		if m.Exists(p.Name) && !opts.Overwrite {
+			return nil, fmt.Errorf("profile '%s' already exists (use --overwrite to replace it)", p.Name)
+		}
+
+		// API profiles only get their keyring entry once every profile has passed
+		check := *p.Config
+		if check.ProfileType == "api" {
+			check.APIKeyID = "pending"
+		}
+		if err := policy.Enforce(&check); err != nil {
+			return nil, fmt.Errorf("profile '%s': %w", p.Name, err)
+		}
+		if err := check.Validate(); err != nil {
+			return nil, fmt.Errorf("profile '%s' in the export is invalid: %w", p.Name, err)
+		}
+
+		if p.Config.ProfileType != "api" {
+			continue
+		}
+
+		switch {
+		case p.APIKey != nil && gcm != nil:
+			apiKey, err := openSecret(gcm, p.APIKey, file.additionalData(p.Name))
+			if errors.Is(err, errMalformedExport) {
+				return nil, fmt.Errorf("API key for profile '%s': %w", p.Name, err)
+			}
+			if err != nil {
+				return nil, fmt.Errorf("failed to decrypt API key for profile '%s': wrong passphrase?", p.Name)
+			}
+			apiKeys[p.Name] = apiKey
+		case opts.APIKey != nil:
+			apiKey, err := opts.APIKey(p.Name)
+			if err != nil {
+				return nil, err
+			}
+			if apiKey == "" {
+				return nil, fmt.Errorf("API key for profile '%s' cannot be empty", p.Name)
+			}
+			apiKeys[p.Name] = apiKey
+		default:
+			return nil, fmt.Errorf("profile '%s' was exported without its API key", p.Name)
+		}
+	}
+
+	var imported []string
+	for _, p := range file.Profiles {
+		cfg := p.Config
+
+		cfg.APIKeyID = ""
+		if apiKey, ok := apiKeys[p.Name]; ok {
+			id, err := keyring.GenerateID()
+			if err != nil {
+				return imported, fmt.Errorf("failed to generate keyring ID: %w", err)
+			}
+			if err := keyring.Store(id, apiKey); err != nil {
+				return imported, fmt.Errorf("failed to store API key for profile '%s': %w", p.Name, err)
+			}
+			cfg.APIKeyID = id
+		}
+
+		// Saving releases the key of a profile it replaces, unless another profile shares it
+		if err := m.Save(p.Name, cfg); err != nil {
+			_ = m.ReleaseAPIKey(cfg.APIKeyID)
+			return imported, fmt.Errorf("failed to save profile '%s': %w", p.Name, err)
+		}
+		imported = append(imported, p.Name)
+	}
+
+	return imported, nil
+}
+
+// aead derives the AES-256-GCM cipher for a passphrase
+func (e *ExportEncryption) aead(passphrase string) (cipher.AEAD, error) {
+	if e.KDF != "pbkdf2-sha256" {
+		return nil, fmt.Errorf("unsupported key derivation '%s'", e.KDF)
+	}
+	if e.Iterations < minKDFIterations || e.Iterations > maxKDFIterations {
+		return nil, fmt.Errorf("export file asks for %d key derivation iterations; only %d to %d are accepted", e.Iterations, minKDFIterations, maxKDFIterations)
+	}
+	if len(e.Salt) < 16 || len(e.Salt) > 64 {
+		return nil, fmt.Errorf("export file has a %d-byte salt; 16 to 64 bytes are accepted", len(e.Salt))
+	}
+	if passphrase == "" {
+		return nil, fmt.Errorf("a passphrase is required to decrypt the API keys in this export")
+	}
+
+	key, err := pbkdf2.Key(sha256.New, passphrase, e.Salt, e.Iterations, 32)
+	if err != nil {
+		return nil, fmt.Errorf("failed to derive key: %w", err)
+	}
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, err
+	}
+	return cipher.NewGCM(block)
+}
+
+// additionalData is what an encrypted API key is bound to: the key derivation settings and the
+// profile it belongs to, so neither can be changed, nor keys swapped between profiles, without
+// decryption failing
+func (f *ExportFile) additionalData(profile string) []byte {
+	header, _ := json.Marshal(struct {
+		Format     string            `json:"format"`
+		Version    int               `json:"version"`
+		Encryption *ExportEncryption `json:"encryption"`
+		Profile    string            `json:"profile"`
+	}{f.Format, f.Version, f.Encryption, profile})
+	return header
+}
+
+func sealSecret(gcm cipher.AEAD, plaintext string, additionalData []byte) (*SealedSecret, error) {
+	nonce := make([]byte, gcm.NonceSize())
+	if _, err := rand.Read(nonce); err != nil {
+		return nil, fmt.Errorf("failed to generate nonce: %w", err)
+	}
+	return &SealedSecret{Nonce: nonce, Ciphertext: gcm.Seal(nil, nonce, []byte(plaintext), additionalData)}, nil
+}
+
+func openSecret(gcm cipher.AEAD, secret *SealedSecret, additionalData []byte) (string, error) {
+	// gcm.Open panics on a nonce of the wrong size
+	if len(secret.Nonce) != gcm.NonceSize() {
+		return "", fmt.Errorf("%w: the nonce is %d bytes, not %d", errMalformedExport, len(secret.Nonce), gcm.NonceSize())
+	}
+	plaintext, err := gcm.Open(nil, secret.Nonce, secret.Ciphertext, additionalData)
+	if err != nil {
+		return "", err
+	}
+	return string(plaintext), nil
+}
@@ -0,0 +1,272 @@
+package telemetry
+
+import (
+	"bytes"
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+)
+
+// Endpoint receives telemetry batches; set at build time with
+// -ldflags "-X github.com/OlaHulleberg/clauderock/internal/telemetry.Endpoint=https://..."
+// When empty, events are only aggregated locally and never sent
+var Endpoint = ""
+
+// EndpointEnvVar overrides Endpoint
+const EndpointEnvVar = "CLAUDEROCK_TELEMETRY_URL"
+
+const (
+	batchSize     = 50             // Events buffered before a batch is sent
+	batchMaxAge   = 24 * time.Hour // Oldest buffered event age before a batch is sent
+	uploadTimeout = 5 * time.Second
+)
+
+// Settings holds the user's telemetry choice
+type Settings struct {
+	Enabled   bool      `json:"enabled"`
+	InstallID string    `json:"install-id,omitempty"` // Random, not derived from anything on the machine
+	DecidedAt time.Time `json:"decided-at,omitempty"`
+}
+
+// Event is one anonymous usage record; it never contains arguments, paths, or error messages
+type Event struct {
+	Version    string `json:"version"`
+	OS         string `json:"os"`
+	Arch       string `json:"arch"`
+	Command    string `json:"command"`
+	ErrorClass string `json:"error-class"`
+}
+
+// aggregate counts identical events so the buffer stays small
+type aggregate struct {
+	Event
+	Count int `json:"count"`
+}
+
+// Buffer holds aggregated events waiting to be sent
+type Buffer struct {
+	FirstRecorded time.Time   `json:"first-recorded"`
+	Events        []aggregate `json:"events"`
+}
+
+// Total returns the number of buffered events
+func (b *Buffer) Total() int {
+	total := 0
+	for _, e := range b.Events {
+		total += e.Count
+	}
+	return total
+}
+
+func baseDir() (string, error) {
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return "", fmt.Errorf("failed to get home directory: %w", err)
+	}
+	return filepath.Join(home, ".clauderock"), nil
+}
+
+func settingsPath() (string, error) {
+	dir, err := baseDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(dir, "telemetry.json"), nil
+}
+
+func bufferPath() (string, error) {
+	dir, err := baseDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(dir, "telemetry-buffer.json"), nil
+}
+
+// ResolvedEndpoint returns the endpoint batches are sent to, or "" when sending is off
+func ResolvedEndpoint() string {
+	if url := strings.TrimSpace(os.Getenv(EndpointEnvVar)); url != "" {
+		return url
+	}
+	return Endpoint
+}
+
+// DoNotTrack reports whether the DO_NOT_TRACK convention disables telemetry regardless of settings
+func DoNotTrack() bool {
+	v := strings.TrimSpace(os.Getenv("DO_NOT_TRACK"))
+	return v != "" && v != "0" && !strings.EqualFold(v, "false")
+}
+
+// LoadSettings returns the saved telemetry choice; telemetry is off until explicitly enabled
+func LoadSettings() (Settings, error) {
+	path, err := settingsPath()
+	if err != nil {
+		return Settings{}, err
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return Settings{}, nil
+		}
+		return Settings{}, fmt.Errorf("failed to read telemetry settings: %w", err)
+	}
+
+	var s Settings
+	if err := json.Unmarshal(data, &s); err != nil {
+		return Settings{}, fmt.Errorf("failed to parse telemetry settings: %w", err)
+	}
+	return s, nil
+}
+
+// SetEnabled saves the telemetry choice; disabling also drops buffered events and the install ID
+func SetEnabled(enabled bool) error {
+	s := Settings{Enabled: enabled, DecidedAt: time.Now()}
+	if enabled {
+		current, err := LoadSettings()
+		if err != nil {
+			return err
+		}
+		s.InstallID = current.InstallID
+		if s.InstallID == "" {
+			id := make([]byte, 16)
+			if _, err := rand.Read(id); err != nil {
+				return fmt.Errorf("failed to generate install ID: %w", err)
+			}
+			s.InstallID = hex.EncodeToString(id)
+		}
+	} else {
+		if path, err := bufferPath(); err == nil {
+			os.Remove(path)
+		}
+	}
+
+	path, err := settingsPath()
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return fmt.Errorf("failed to create directory: %w", err)
+	}
+	data, err := json.MarshalIndent(s, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to marshal telemetry settings: %w", err)
+	}
+	if err := os.WriteFile(path, data, 0644); err != nil {
+		return fmt.Errorf("failed to write telemetry settings: %w", err)
+	}
+	return nil
+}
+
+// LoadBuffer returns the events waiting to be sent
+func LoadBuffer() (*Buffer, error) {
+	path, err := bufferPath()
+	if err != nil {
+		return nil, err
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return &Buffer{}, nil
+		}
+		return nil, fmt.Errorf("failed to read telemetry buffer: %w", err)
+	}
+
+	var b Buffer
+	if err := json.Unmarshal(data, &b); err != nil {
+		// A corrupt buffer is only telemetry; start over
+		return &Buffer{}, nil
+	}
+	return &b, nil
+}
+
+func saveBuffer(b *Buffer) error {
+	path, err := bufferPath()
+	if err != nil {
+		return err
+	}
+	data, err := json.Marshal(b)
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(path, data, 0644)
+}
+
+// Record buffers an event when telemetry is enabled and sends a batch when one is due
+// Failures are silent: telemetry must never get in the way of the command itself
+func Record(e Event) {
+	settings, err := LoadSettings()
+	if err != nil || !settings.Enabled || DoNotTrack() {
+		return
+	}
+
+	b, err := LoadBuffer()
+	if err != nil {
+		return
+	}
+	if len(b.Events) == 0 {
+		b.FirstRecorded = time.Now()
+	}
+
+	found := false
+	for i := range b.Events {
+		if b.Events[i].Event == e {
+			b.Events[i].Count++
+			found = true
+			break
+		}
+	}
+	if !found {
+		b.Events = append(b.Events, aggregate{Event: e, Count: 1})
+	}
+
+	if b.Total() >= batchSize || time.Since(b.FirstRecorded) >= batchMaxAge {
+		if err := send(settings.InstallID, b); err == nil {
+			b = &Buffer{}
+		}
+	}
+	saveBuffer(b)
+}
+
+// send uploads a batch to the configured endpoint
+func send(installID string, b *Buffer) error {
+	endpoint := ResolvedEndpoint()
+	if endpoint == "" {
+		return fmt.Errorf("no telemetry endpoint configured")
+	}
+
+	payload, err := json.Marshal(struct {
+		InstallID string      `json:"install-id"`
+		Events    []aggregate `json:"events"`
+	}{installID, b.Events})
+	if err != nil {
+		return err
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), uploadTimeout)
+	defer cancel()
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, endpoint, bytes.NewReader(payload))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return err
+	}
+	resp.Body.Close()
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return fmt.Errorf("unexpected status %s", resp.Status)
+	}
+	return nil
+}
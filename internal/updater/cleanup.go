@@ -0,0 +1,81 @@
+package updater
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+)
+
+// StaleAge is how old a temporary file must be before cleanup treats it as left behind;
+// younger ones may belong to an update or profile save that is still running
+const StaleAge = time.Hour
+
+// Artifact is a file clauderock left behind: an update download, an extracted binary,
+// a binary an earlier version replaced on Windows, or an unfinished profile write
+type Artifact struct {
+	Path    string
+	Size    int64
+	ModTime time.Time
+}
+
+// FindStaleArtifacts lists clauderock's leftover temporary files older than minAge
+// Unreadable locations are skipped, since they can't hold anything cleanup could remove
+func FindStaleArtifacts(minAge time.Duration) []Artifact {
+	cutoff := time.Now().Add(-minAge)
+	var found []Artifact
+	add := func(path string) {
+		info, err := os.Lstat(path)
+		if err != nil || !info.Mode().IsRegular() || info.ModTime().After(cutoff) {
+			return
+		}
+		found = append(found, Artifact{Path: path, Size: info.Size(), ModTime: info.ModTime()})
+	}
+
+	// Downloads and extracted binaries of updates that failed or were interrupted
+	for _, pattern := range []string{"clauderock-archive-*", "clauderock-binary-*"} {
+		matches, _ := filepath.Glob(filepath.Join(os.TempDir(), pattern))
+		for _, path := range matches {
+			add(path)
+		}
+	}
+
+	// Earlier versions left the binary an update replaced on Windows as .old; the backup
+	// kept for rollback is not an artifact
+	if exe, err := os.Executable(); err == nil {
+		add(exe + ".old")
+	}
+
+	// Profile and state writes go through a temporary file that a crash can leave behind
+	if home, err := os.UserHomeDir(); err == nil {
+		for _, dir := range []string{".clauderock", filepath.Join(".clauderock", "profiles")} {
+			entries, _ := os.ReadDir(filepath.Join(home, dir))
+			for _, e := range entries {
+				if strings.HasPrefix(e.Name(), ".") && strings.HasSuffix(e.Name(), ".tmp") {
+					add(filepath.Join(home, dir, e.Name()))
+				}
+			}
+		}
+	}
+	return found
+}
+
+// RemoveArtifacts deletes the artifacts and returns the bytes reclaimed, along with
+// the artifacts that couldn't be removed, such as a .old binary still running
+func RemoveArtifacts(artifacts []Artifact) (int64, []Artifact) {
+	var reclaimed int64
+	var failed []Artifact
+	for _, a := range artifacts {
+		if err := os.Remove(a.Path); err != nil && !os.IsNotExist(err) {
+			failed = append(failed, a)
+			continue
+		}
+		reclaimed += a.Size
+	}
+	return reclaimed, failed
+}
+
+// CleanupStaleArtifacts removes leftover temporary files quietly, for running at startup
+func CleanupStaleArtifacts() {
+	RemoveArtifacts(FindStaleArtifacts(StaleAge))
+}
@@ -0,0 +1,315 @@
+package updater
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+)
+
+// Ways clauderock can be installed; each keeps its binary up to date differently
+const (
+	InstallHomebrew    = "homebrew"
+	InstallScoop       = "scoop"
+	InstallGo          = "go-install"
+	InstallScript      = "install-script"
+	InstallArchive     = "release-archive"
+	InstallDevelopment = "development"
+)
+
+const (
+	githubTagAPIURL = "https://api.github.com/repos/OlaHulleberg/clauderock/releases/tags/"
+	checksumsAsset  = "checksums.txt"
+	goInstallPath   = "github.com/OlaHulleberg/clauderock@latest"
+)
+
+// Markers around the pinned release block of install.sh
+const (
+	pinBeginMarker = "# --- pinned release (managed by 'clauderock manage install pin-script') ---"
+	pinEndMarker   = "# --- end pinned release ---"
+)
+
+// packageManagers names the install methods whose tool owns the binary, which 'manage update'
+// must leave alone
+var packageManagers = map[string]string{
+	InstallHomebrew: "Homebrew",
+	InstallScoop:    "Scoop",
+	InstallGo:       "go install",
+}
+
+// InstallInfo describes how the running binary was installed
+type InstallInfo struct {
+	Method        string `json:"method"`
+	Path          string `json:"path"`
+	UpdateCommand string `json:"update-command"`
+}
+
+// PackageManager returns the name of the tool that installed and updates the binary, or ""
+// when clauderock updates itself
+func (i InstallInfo) PackageManager() string {
+	return packageManagers[i.Method]
+}
+
+// DetectInstall works out how the running binary was installed from where it lives
+func DetectInstall(currentVersion string) (InstallInfo, error) {
+	path, err := os.Executable()
+	if err != nil {
+		return InstallInfo{}, fmt.Errorf("failed to find the clauderock binary: %w", err)
+	}
+	if resolved, err := filepath.EvalSymlinks(path); err == nil {
+		path = resolved
+	}
+
+	info := InstallInfo{Method: installMethod(path, currentVersion), Path: path}
+	switch info.Method {
+	case InstallHomebrew:
+		info.UpdateCommand = "brew upgrade clauderock"
+	case InstallScoop:
+		info.UpdateCommand = "scoop update clauderock"
+	case InstallGo:
+		info.UpdateCommand = "go install " + goInstallPath
+	case InstallDevelopment:
+		info.UpdateCommand = "git pull && go build -o clauderock"
+	default:
+		info.UpdateCommand = "clauderock manage update"
+	}
+	return info, nil
+}
+
+// installMethod classifies a resolved binary path
+func installMethod(path, currentVersion string) string {
+	slashed := filepath.ToSlash(path)
+	if strings.Contains(slashed, "/Cellar/") || strings.Contains(slashed, "/homebrew/") || strings.Contains(slashed, "/linuxbrew/") {
+		return InstallHomebrew
+	}
+	if isScoopPath(path) {
+		return InstallScoop
+	}
+
+	dir := filepath.Dir(path)
+	for _, goBin := range goBinDirs() {
+		if dir == goBin {
+			return InstallGo
+		}
+	}
+
+	if currentVersion == "dev" {
+		return InstallDevelopment
+	}
+
+	// install.sh installs to /usr/local/bin, falling back to ~/.local/bin
+	if dir == "/usr/local/bin" {
+		return InstallScript
+	}
+	if home, err := os.UserHomeDir(); err == nil && dir == filepath.Join(home, ".local", "bin") {
+		return InstallScript
+	}
+	return InstallArchive
+}
+
+// isScoopPath reports whether path is inside a Scoop app directory: scoop/apps under the
+// user's profile or ProgramData, or the custom roots set in SCOOP and SCOOP_GLOBAL
+// Scoop's shims start the real binary, so the running path is the one under apps
+func isScoopPath(path string) bool {
+	slashed := strings.ToLower(filepath.ToSlash(path))
+	if strings.Contains(slashed, "/scoop/apps/") {
+		return true
+	}
+	for _, env := range []string{"SCOOP", "SCOOP_GLOBAL"} {
+		root := os.Getenv(env)
+		if root == "" {
+			continue
+		}
+		apps := strings.ToLower(filepath.ToSlash(filepath.Join(root, "apps")))
+		if strings.HasPrefix(slashed, apps+"/") {
+			return true
+		}
+	}
+	return false
+}
+
+// goBinDirs returns the directories 'go install' puts binaries in
+func goBinDirs() []string {
+	if gobin := os.Getenv("GOBIN"); gobin != "" {
+		return []string{gobin}
+	}
+	gopath := os.Getenv("GOPATH")
+	if gopath == "" {
+		home, err := os.UserHomeDir()
+		if err != nil {
+			return nil
+		}
+		gopath = filepath.Join(home, "go")
+	}
+	var dirs []string
+	for _, p := range filepath.SplitList(gopath) {
+		dirs = append(dirs, filepath.Join(p, "bin"))
+	}
+	return dirs
+}
+
+// checkSelfUpdate refuses to replace a binary another package manager owns
+func checkSelfUpdate(currentVersion string) error {
+	info, err := DetectInstall(currentVersion)
+	if err != nil {
+		return nil
+	}
+	if manager := info.PackageManager(); manager != "" {
+		return fmt.Errorf("clauderock was installed with %s, which owns the binary; update it with: %s", manager, info.UpdateCommand)
+	}
+	return nil
+}
+
+// getRelease fetches a release by tag, or the latest release when tag is empty
+func getRelease(tag string) (*GitHubRelease, error) {
+	if tag == "" {
+		return getLatestRelease()
+	}
+
+	resp, err := http.Get(githubTagAPIURL + tag)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode == http.StatusNotFound {
+		return nil, fmt.Errorf("release %s does not exist", tag)
+	}
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("GitHub API returned status %d", resp.StatusCode)
+	}
+
+	var release GitHubRelease
+	if err := json.NewDecoder(resp.Body).Decode(&release); err != nil {
+		return nil, err
+	}
+	return &release, nil
+}
+
+// ReleaseChecksums are the SHA-256 checksums of a release's archives, by archive name
+type ReleaseChecksums struct {
+	Tag       string
+	Checksums map[string]string
+}
+
+// FetchReleaseChecksums downloads checksums.txt of a release (the latest when tag is empty)
+// and checks it covers every released platform
+func FetchReleaseChecksums(tag string) (*ReleaseChecksums, error) {
+	release, err := getRelease(tag)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch release: %w", err)
+	}
+
+	checksums, err := releaseChecksums(release)
+	if err != nil {
+		return nil, err
+	}
+	for _, platform := range releasedPlatforms {
+		goos, goarch, _ := strings.Cut(platform, "/")
+		name, _ := getBinaryAssetName(goos, goarch)
+		if checksums[name] == "" {
+			return nil, fmt.Errorf("release %s has no checksum for %s", release.TagName, name)
+		}
+	}
+	return &ReleaseChecksums{Tag: release.TagName, Checksums: checksums}, nil
+}
+
+var checksumLine = regexp.MustCompile(`^([0-9a-f]{64})\s+\*?(\S+)$`)
+
+// parseChecksums reads sha256sum output: "<hex digest>  <file name>" per line
+func parseChecksums(r io.Reader) (map[string]string, error) {
+	checksums := make(map[string]string)
+	scanner := bufio.NewScanner(r)
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" {
+			continue
+		}
+		m := checksumLine.FindStringSubmatch(line)
+		if m == nil {
+			return nil, fmt.Errorf("unexpected line: %q", line)
+		}
+		checksums[m[2]] = m[1]
+	}
+	return checksums, scanner.Err()
+}
+
+// BrewFormula renders a Homebrew formula installing the release's prebuilt archives
+func BrewFormula(rc *ReleaseChecksums) string {
+	version := strings.TrimPrefix(rc.Tag, "v")
+	asset := func(goos, goarch string) string {
+		name, _ := getBinaryAssetName(goos, goarch)
+		return fmt.Sprintf("      url \"%s/releases/download/%s/%s\"\n      sha256 \"%s\"\n",
+			githubRepoURL, rc.Tag, name, rc.Checksums[name])
+	}
+
+	var b strings.Builder
+	fmt.Fprintf(&b, "# Generated by 'clauderock manage install formula' for %s; regenerate it instead of editing\n", rc.Tag)
+	b.WriteString("class Clauderock < Formula\n")
+	b.WriteString("  desc \"Launch Claude Code with AWS Bedrock, Google Vertex AI, or custom API endpoints\"\n")
+	fmt.Fprintf(&b, "  homepage \"%s\"\n", githubRepoURL)
+	fmt.Fprintf(&b, "  version \"%s\"\n", version)
+	b.WriteString("  license \"MIT\"\n\n")
+	for _, goos := range []string{"darwin", "linux"} {
+		block := "on_macos"
+		if goos == "linux" {
+			block = "on_linux"
+		}
+		fmt.Fprintf(&b, "  %s do\n", block)
+		b.WriteString("    on_arm do\n")
+		b.WriteString(asset(goos, "arm64"))
+		b.WriteString("    end\n")
+		b.WriteString("    on_intel do\n")
+		b.WriteString(asset(goos, "amd64"))
+		b.WriteString("    end\n")
+		b.WriteString("  end\n\n")
+	}
+	b.WriteString("  def install\n")
+	b.WriteString("    bin.install \"clauderock\"\n")
+	b.WriteString("  end\n\n")
+	b.WriteString("  test do\n")
+	b.WriteString("    assert_match version.to_s, shell_output(\"#{bin}/clauderock manage version\")\n")
+	b.WriteString("  end\n")
+	b.WriteString("end\n")
+	return b.String()
+}
+
+// PinInstallScript rewrites the pinned release block of install.sh, so the script installs
+// exactly that release and verifies its archive against the pinned checksums
+func PinInstallScript(script []byte, rc *ReleaseChecksums) ([]byte, error) {
+	begin := bytes.Index(script, []byte(pinBeginMarker))
+	end := bytes.Index(script, []byte(pinEndMarker))
+	if begin < 0 || end < begin {
+		return nil, fmt.Errorf("install script has no pinned release block (%q ... %q)", pinBeginMarker, pinEndMarker)
+	}
+
+	names := make([]string, 0, len(rc.Checksums))
+	for name := range rc.Checksums {
+		if strings.HasPrefix(name, "clauderock_") {
+			names = append(names, name)
+		}
+	}
+	sort.Strings(names)
+
+	var block bytes.Buffer
+	block.WriteString(pinBeginMarker + "\n")
+	fmt.Fprintf(&block, "PINNED_VERSION=\"%s\"\n", rc.Tag)
+	block.WriteString("PINNED_CHECKSUMS=\"\n")
+	for _, name := range names {
+		fmt.Fprintf(&block, "%s  %s\n", rc.Checksums[name], name)
+	}
+	block.WriteString("\"\n")
+
+	var out bytes.Buffer
+	out.Write(script[:begin])
+	out.Write(block.Bytes())
+	out.Write(script[end:])
+	return out.Bytes(), nil
+}
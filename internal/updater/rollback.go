@@ -0,0 +1,70 @@
+package updater
+
+import (
+	"fmt"
+	"os"
+)
+
+// BackupPath is where the binary an update replaced is kept, next to the running one
+func BackupPath(exe string) string {
+	return exe + ".previous"
+}
+
+// swapBinary moves newPath into place as the running binary and keeps the one it replaces
+// as the backup; a running binary can be renamed even on Windows, where it can't be deleted
+func swapBinary(newPath, currentPath string) error {
+	backupPath := BackupPath(currentPath)
+	if err := os.Rename(currentPath, backupPath); err != nil {
+		return err
+	}
+	if err := os.Rename(newPath, currentPath); err != nil {
+		os.Rename(backupPath, currentPath)
+		return err
+	}
+	return nil
+}
+
+// Rollback restores the binary the last update replaced and returns its version, or "" when
+// it's unknown; the binary it replaces becomes the backup, so a second rollback undoes the first
+func Rollback(currentVersion string) (string, error) {
+	if err := checkSelfUpdate(currentVersion); err != nil {
+		return "", err
+	}
+
+	currentPath, err := os.Executable()
+	if err != nil {
+		return "", err
+	}
+	backupPath := BackupPath(currentPath)
+	if _, err := os.Stat(backupPath); err != nil {
+		if os.IsNotExist(err) {
+			return "", fmt.Errorf("no previous version to roll back to; one is kept once 'clauderock manage update' installs a release")
+		}
+		return "", err
+	}
+
+	settings, err := LoadSettings()
+	if err != nil {
+		return "", err
+	}
+
+	// The running binary steps aside, the backup takes its place, and it becomes the new backup
+	asidePath := currentPath + ".rollback"
+	if err := os.Rename(currentPath, asidePath); err != nil {
+		return "", fmt.Errorf("failed to move the current binary aside: %w", err)
+	}
+	if err := os.Rename(backupPath, currentPath); err != nil {
+		os.Rename(asidePath, currentPath)
+		return "", fmt.Errorf("failed to restore the previous binary: %w", err)
+	}
+	if err := os.Rename(asidePath, backupPath); err != nil {
+		return "", fmt.Errorf("restored the previous binary, but failed to keep the current one as a backup: %w", err)
+	}
+
+	restored := settings.PreviousVersion
+	settings.PreviousVersion = currentVersion
+	if err := SaveSettings(settings); err != nil {
+		return restored, err
+	}
+	return restored, nil
+}
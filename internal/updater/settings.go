@@ -0,0 +1,105 @@
+package updater
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+)
+
+// Settings holds the saved update preferences and what the last update replaced
+type Settings struct {
+	PinnedVersion   string `json:"pinned-version,omitempty"`   // Release to stay on; no upgrade notices while set
+	PreviousVersion string `json:"previous-version,omitempty"` // Version of the backup binary rollback restores
+}
+
+var versionPattern = regexp.MustCompile(`^v\d+\.\d+\.\d+(-[0-9A-Za-z.-]+)?$`)
+
+// NormalizeVersion checks a release version and adds the leading v releases are tagged with
+func NormalizeVersion(version string) (string, error) {
+	version = strings.TrimSpace(version)
+	if !strings.HasPrefix(version, "v") {
+		version = "v" + version
+	}
+	if !versionPattern.MatchString(version) {
+		return "", fmt.Errorf("invalid version '%s': use a release version like v1.2.3", strings.TrimPrefix(version, "v"))
+	}
+	return version, nil
+}
+
+func settingsPath() (string, error) {
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return "", fmt.Errorf("failed to get home directory: %w", err)
+	}
+	return filepath.Join(home, ".clauderock", "update.json"), nil
+}
+
+// LoadSettings returns the saved settings; nothing is pinned until a version is
+func LoadSettings() (Settings, error) {
+	path, err := settingsPath()
+	if err != nil {
+		return Settings{}, err
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return Settings{}, nil
+		}
+		return Settings{}, fmt.Errorf("failed to read update settings: %w", err)
+	}
+
+	var s Settings
+	if err := json.Unmarshal(data, &s); err != nil {
+		return Settings{}, fmt.Errorf("failed to parse update settings: %w", err)
+	}
+	return s, nil
+}
+
+// SaveSettings saves the update settings
+func SaveSettings(s Settings) error {
+	path, err := settingsPath()
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return fmt.Errorf("failed to create directory: %w", err)
+	}
+	data, err := json.MarshalIndent(s, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to marshal update settings: %w", err)
+	}
+	if err := os.WriteFile(path, data, 0644); err != nil {
+		return fmt.Errorf("failed to write update settings: %w", err)
+	}
+	return nil
+}
+
+// Pin keeps clauderock on version: update installs it and upgrade notices stop
+// The release must exist, unless it's the running version
+func Pin(version, currentVersion string) error {
+	if version != currentVersion {
+		if _, err := getRelease(version); err != nil {
+			return fmt.Errorf("failed to find release %s: %w", version, err)
+		}
+	}
+	settings, err := LoadSettings()
+	if err != nil {
+		return err
+	}
+	settings.PinnedVersion = version
+	return SaveSettings(settings)
+}
+
+// Unpin lets update install the latest release again
+func Unpin() error {
+	settings, err := LoadSettings()
+	if err != nil {
+		return err
+	}
+	settings.PinnedVersion = ""
+	return SaveSettings(settings)
+}
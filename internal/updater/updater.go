@@ -4,6 +4,8 @@ import (
 	"archive/tar"
 	"archive/zip"
 	"compress/gzip"
+	"crypto/sha256"
+	"encoding/hex"
 	"encoding/json"
 	"fmt"
 	"io"
@@ -28,10 +30,14 @@ type GitHubRelease struct {
 }
 
 // CheckForUpdates checks for updates in the background and notifies the user
+// A pinned version means the user chose to stay on it, so there is nothing to announce
 func CheckForUpdates(currentVersion string) {
 	if currentVersion == "dev" {
 		return // Skip update check for development builds
 	}
+	if settings, err := LoadSettings(); err != nil || settings.PinnedVersion != "" {
+		return
+	}
 
 	latestVersion, err := getLatestVersion()
 	if err != nil {
@@ -40,34 +46,68 @@ func CheckForUpdates(currentVersion string) {
 	}
 
 	if latestVersion != currentVersion && latestVersion != "" {
+		// Binaries a package manager owns are upgraded with its command instead
+		command := "clauderock manage update"
+		if info, err := DetectInstall(currentVersion); err == nil {
+			command = info.UpdateCommand
+		}
 		fmt.Fprintf(os.Stderr, "\n⚠️  New version available: %s (current: %s)\n", latestVersion, currentVersion)
-		fmt.Fprintf(os.Stderr, "   Run 'clauderock manage update' to upgrade\n\n")
+		fmt.Fprintf(os.Stderr, "   Run '%s' to upgrade\n\n", command)
 	}
 }
 
-// Update checks for and installs the latest version
-func Update(currentVersion string) error {
+// Update installs version, or the pinned version, or else the latest one; the binary it
+// replaces is kept for Rollback
+func Update(currentVersion, version string) error {
 	if currentVersion == "dev" {
 		return fmt.Errorf("cannot update development build")
 	}
+	if err := checkSelfUpdate(currentVersion); err != nil {
+		return err
+	}
+
+	settings, err := LoadSettings()
+	if err != nil {
+		return err
+	}
+	if version == "" && settings.PinnedVersion != "" {
+		if settings.PinnedVersion == currentVersion {
+			fmt.Printf("Pinned to %s; unpin with 'clauderock manage update unpin' to upgrade\n", currentVersion)
+			return nil
+		}
+		version = settings.PinnedVersion
+		fmt.Printf("Installing pinned version %s\n", version)
+	}
 
 	fmt.Println("Checking for updates...")
 
-	release, err := getLatestRelease()
+	release, err := getRelease(version)
 	if err != nil {
 		return fmt.Errorf("failed to check for updates: %w", err)
 	}
 
 	latestVersion := release.TagName
 	if latestVersion == currentVersion {
-		fmt.Printf("Already on latest version: %s\n", currentVersion)
+		if version != "" {
+			fmt.Printf("Already on version %s\n", currentVersion)
+		} else {
+			fmt.Printf("Already on latest version: %s\n", currentVersion)
+		}
 		return nil
 	}
 
-	fmt.Printf("New version available: %s (current: %s)\n", latestVersion, currentVersion)
+	if version != "" {
+		fmt.Printf("Installing %s (current: %s)\n", latestVersion, currentVersion)
+	} else {
+		fmt.Printf("New version available: %s (current: %s)\n", latestVersion, currentVersion)
+	}
 
 	// Find the appropriate binary for the current platform
-	assetName := getBinaryAssetName()
+	assetName, ok := getBinaryAssetName(runtime.GOOS, runtime.GOARCH)
+	if !ok {
+		return fmt.Errorf("clauderock doesn't publish binaries for %s/%s (available: %s); build from source: %s",
+			runtime.GOOS, runtime.GOARCH, strings.Join(releasedPlatforms, ", "), githubRepoURL)
+	}
 	var downloadURL string
 	for _, asset := range release.Assets {
 		if asset.Name == assetName {
@@ -77,15 +117,31 @@ func Update(currentVersion string) error {
 	}
 
 	if downloadURL == "" {
-		return fmt.Errorf("no binary found for platform %s/%s", runtime.GOOS, runtime.GOARCH)
+		return fmt.Errorf("release %s has no %s yet; its binaries may still be uploading, try again in a few minutes", latestVersion, assetName)
+	}
+
+	// The archive is checked against the release's checksums before it replaces anything
+	checksums, err := releaseChecksums(release)
+	if err != nil {
+		return fmt.Errorf("failed to verify release: %w", err)
+	}
+	checksum := checksums[assetName]
+	if checksum == "" {
+		return fmt.Errorf("release %s has no checksum for %s", latestVersion, assetName)
 	}
 
 	fmt.Printf("Downloading %s...\n", assetName)
-	if err := downloadAndReplace(downloadURL); err != nil {
+	if err := downloadAndReplace(downloadURL, checksum); err != nil {
 		return fmt.Errorf("failed to update: %w", err)
 	}
 
+	settings.PreviousVersion = currentVersion
+	if err := SaveSettings(settings); err != nil {
+		fmt.Printf("Warning: %v\n", err)
+	}
+
 	fmt.Printf("Successfully updated to version %s\n", latestVersion)
+	fmt.Printf("Restore %s with: clauderock manage update rollback\n", currentVersion)
 	return nil
 }
 
@@ -116,23 +172,47 @@ func getLatestRelease() (*GitHubRelease, error) {
 	return &release, nil
 }
 
-func getBinaryAssetName() string {
+// releasedPlatforms lists the os/arch pairs every release has a binary for
+// Keep in sync with the builds in .goreleaser.yml
+var releasedPlatforms = []string{
+	"linux/amd64",
+	"linux/arm64",
+	"darwin/amd64",
+	"darwin/arm64",
+	"windows/amd64",
+	"windows/arm64",
+}
+
+// getBinaryAssetName returns the release archive holding the binary for goos/goarch,
+// and false when releases don't include that platform
+func getBinaryAssetName(goos, goarch string) (string, bool) {
 	// Expected archive format from GoReleaser
 	// tar.gz for linux/darwin, zip for windows
-	// Examples: clauderock_darwin_arm64.tar.gz, clauderock_windows_amd64.zip
-	os := runtime.GOOS
-	arch := runtime.GOARCH
+	// Examples: clauderock_darwin_arm64.tar.gz, clauderock_windows_arm64.zip
+	platform := goos + "/" + goarch
+	released := false
+	for _, p := range releasedPlatforms {
+		if p == platform {
+			released = true
+			break
+		}
+	}
+	if !released {
+		return "", false
+	}
 
-	name := fmt.Sprintf("clauderock_%s_%s", os, arch)
-	if os == "windows" {
+	name := fmt.Sprintf("clauderock_%s_%s", goos, goarch)
+	if goos == "windows" {
 		name += ".zip"
 	} else {
 		name += ".tar.gz"
 	}
-	return name
+	return name, true
 }
 
-func downloadAndReplace(url string) error {
+// downloadAndReplace downloads a release archive, checks it against its SHA-256 checksum,
+// and replaces the running binary with the one inside, keeping the old one as the backup
+func downloadAndReplace(url, checksum string) error {
 	// Download the archive
 	resp, err := http.Get(url)
 	if err != nil {
@@ -152,12 +232,16 @@ func downloadAndReplace(url string) error {
 	tmpPath := tmpFile.Name()
 	defer os.Remove(tmpPath)
 
-	// Write the downloaded archive to the temp file
-	if _, err := io.Copy(tmpFile, resp.Body); err != nil {
+	// Write the downloaded archive to the temp file, hashing it on the way
+	hash := sha256.New()
+	if _, err := io.Copy(io.MultiWriter(tmpFile, hash), resp.Body); err != nil {
 		tmpFile.Close()
 		return err
 	}
 	tmpFile.Close()
+	if actual := hex.EncodeToString(hash.Sum(nil)); actual != checksum {
+		return fmt.Errorf("checksum mismatch: expected %s, got %s; the download is corrupt or was tampered with", checksum, actual)
+	}
 
 	// Extract the binary from the archive
 	var binaryPath string
@@ -184,24 +268,7 @@ func downloadAndReplace(url string) error {
 		return err
 	}
 
-	// Replace the current binary with the new one
-	if runtime.GOOS == "windows" {
-		backupPath := currentPath + ".old"
-		if err := os.Rename(currentPath, backupPath); err != nil {
-			return err
-		}
-		if err := os.Rename(binaryPath, currentPath); err != nil {
-			os.Rename(backupPath, currentPath)
-			return err
-		}
-		os.Remove(backupPath)
-	} else {
-		if err := os.Rename(binaryPath, currentPath); err != nil {
-			return err
-		}
-	}
-
-	return nil
+	return swapBinary(binaryPath, currentPath)
 }
 
 func extractFromTarGz(archivePath string) (string, error) {
@@ -0,0 +1,126 @@
+package updater
+
+import (
+	"bytes"
+	"crypto/ed25519"
+	"encoding/base64"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+)
+
+// signatureAsset is the minisign signature of checksums.txt, so one signature covers every archive
+const signatureAsset = checksumsAsset + ".minisig"
+
+// MinisignPublicKey is the base64 minisign public key releases are signed with, set at build time:
+// -ldflags "-X github.com/OlaHulleberg/clauderock/internal/updater.MinisignPublicKey=RWQ..."
+// When set, checksums.txt must carry a valid signature from it; builds without it check checksums only
+var MinisignPublicKey string
+
+// fetchAsset downloads a release asset, returning false when the release doesn't have it
+func fetchAsset(release *GitHubRelease, name string) ([]byte, bool, error) {
+	var url string
+	for _, asset := range release.Assets {
+		if asset.Name == name {
+			url = asset.BrowserDownloadURL
+			break
+		}
+	}
+	if url == "" {
+		return nil, false, nil
+	}
+
+	resp, err := http.Get(url)
+	if err != nil {
+		return nil, true, fmt.Errorf("failed to download %s: %w", name, err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return nil, true, fmt.Errorf("failed to download %s: status %d", name, resp.StatusCode)
+	}
+	data, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, true, fmt.Errorf("failed to download %s: %w", name, err)
+	}
+	return data, true, nil
+}
+
+// releaseChecksums downloads a release's checksums.txt, verifies its signature when this build
+// knows the signing key, and returns the checksums by archive name
+func releaseChecksums(release *GitHubRelease) (map[string]string, error) {
+	data, ok, err := fetchAsset(release, checksumsAsset)
+	if err != nil {
+		return nil, err
+	}
+	if !ok {
+		return nil, fmt.Errorf("release %s has no %s", release.TagName, checksumsAsset)
+	}
+
+	if MinisignPublicKey != "" {
+		signature, ok, err := fetchAsset(release, signatureAsset)
+		if err != nil {
+			return nil, err
+		}
+		if !ok {
+			return nil, fmt.Errorf("release %s has no %s; refusing unsigned checksums", release.TagName, signatureAsset)
+		}
+		if err := verifyMinisign(MinisignPublicKey, data, signature); err != nil {
+			return nil, fmt.Errorf("signature of %s in release %s is invalid: %w", checksumsAsset, release.TagName, err)
+		}
+	}
+
+	checksums, err := parseChecksums(bytes.NewReader(data))
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse %s: %w", checksumsAsset, err)
+	}
+	return checksums, nil
+}
+
+// verifyMinisign checks a minisign signature file over message with a base64 public key
+// Only legacy (non-prehashed) signatures are supported, made with 'minisign -S -l'; the
+// prehashed kind needs BLAKE2b, which the standard library doesn't have
+func verifyMinisign(publicKey string, message, signatureFile []byte) error {
+	key, err := base64.StdEncoding.DecodeString(strings.TrimSpace(publicKey))
+	if err != nil || len(key) != 42 || string(key[:2]) != "Ed" {
+		return fmt.Errorf("invalid minisign public key")
+	}
+	keyID, pub := key[2:10], ed25519.PublicKey(key[10:])
+
+	// untrusted comment, signature, trusted comment, global signature
+	lines := strings.Split(strings.TrimSpace(strings.ReplaceAll(string(signatureFile), "\r\n", "\n")), "\n")
+	if len(lines) < 4 {
+		return fmt.Errorf("malformed signature file")
+	}
+	sig, err := base64.StdEncoding.DecodeString(strings.TrimSpace(lines[1]))
+	if err != nil || len(sig) != 74 {
+		return fmt.Errorf("malformed signature")
+	}
+	switch string(sig[:2]) {
+	case "Ed":
+	case "ED":
+		return fmt.Errorf("prehashed signatures are not supported; sign with 'minisign -S -l'")
+	default:
+		return fmt.Errorf("unknown signature algorithm")
+	}
+	if !bytes.Equal(sig[2:10], keyID) {
+		return fmt.Errorf("signed with a different key")
+	}
+	if !ed25519.Verify(pub, message, sig[10:]) {
+		return fmt.Errorf("signature does not match")
+	}
+
+	// The trusted comment is signed too, so it can't be swapped between signatures
+	trustedComment, ok := strings.CutPrefix(lines[2], "trusted comment: ")
+	if !ok {
+		return fmt.Errorf("malformed trusted comment")
+	}
+	globalSig, err := base64.StdEncoding.DecodeString(strings.TrimSpace(lines[3]))
+	if err != nil || len(globalSig) != ed25519.SignatureSize {
+		return fmt.Errorf("malformed trusted comment signature")
+	}
+	if !ed25519.Verify(pub, append(append([]byte{}, sig[10:]...), trustedComment...), globalSig) {
+		return fmt.Errorf("trusted comment signature does not match")
+	}
+	return nil
+}
@@ -0,0 +1,130 @@
+package usage
+
+import (
+	"fmt"
+	"math"
+	"strings"
+
+	"github.com/OlaHulleberg/clauderock/internal/aws"
+	"github.com/OlaHulleberg/clauderock/internal/pricing"
+)
+
+// DefaultAnomalySigma is how many standard deviations above a profile's baseline
+// a session has to be before it is flagged
+const DefaultAnomalySigma = 3.0
+
+// CheckAnomalySigma rejects thresholds of zero or less, which would flag sessions at or even
+// below their profile's baseline
+func CheckAnomalySigma(sigma float64) error {
+	if !(sigma > 0) {
+		return fmt.Errorf("invalid anomaly threshold %g: it must be a number of standard deviations above 0", sigma)
+	}
+	return nil
+}
+
+// minBaselineSessions is the history needed before a profile has a meaningful baseline
+const minBaselineSessions = 5
+
+// Anomaly describes a session whose usage is far above its profile's baseline
+type Anomaly struct {
+	Session Session
+	Metric  string // "tokens" or "cost"
+	Value   float64
+	Mean    float64
+	StdDev  float64
+	Sigma   float64 // Standard deviations above the mean
+}
+
+// PriceKey maps a tracked model ID to its pricing table key
+// Bedrock profile IDs use their friendly name; bare API and Vertex AI model IDs (whose
+// "@20250929" snapshot suffix is dropped) are assumed to be Anthropic models
+func PriceKey(model string) string {
+	if !strings.Contains(model, ".") {
+		model, _, _ = strings.Cut(model, "@")
+		return "anthropic." + model
+	}
+	return aws.ExtractFriendlyModelName(model)
+}
+
+// SessionCost estimates the cost of a session from its token counts, at its region's prices
+// and its pricing tier, including the prompt cache like CostsByModel and CallCost. Tokens sent
+// to the heavy model are priced at the heavy model's rate; cache tokens aren't recorded per
+// model, so they are priced at the main model's
+// Every cost shown or exported per session goes through here, so totals always agree
+func SessionCost(s Session) float64 {
+	key := PriceKey(s.Model)
+	cost := pricing.CalculateCostInRegion(key, s.Region, s.TotalInputTokens-s.HeavyInputTokens, s.TotalOutputTokens-s.HeavyOutputTokens) +
+		pricing.CalculateCacheCostInRegion(key, s.Region, s.CacheReadTokens, s.CacheCreationTokens)
+	return cost*pricing.TierMultiplier(s.PricingTier) + HeavyCost(s)
+}
+
+// anomalyMetrics are the per-session values checked against the baseline
+var anomalyMetrics = []struct {
+	name  string
+	value func(Session) float64
+}{
+	{"tokens", func(s Session) float64 { return float64(s.TotalInputTokens + s.TotalOutputTokens) }},
+	{"cost", SessionCost},
+}
+
+// FindAnomalies flags candidates whose tokens or cost exceed sigma standard deviations
+// above the mean of the other sessions of the same profile in history
+func FindAnomalies(candidates, history []Session, sigma float64) []Anomaly {
+	byProfile := make(map[string][]Session)
+	for _, s := range history {
+		byProfile[s.ProfileName] = append(byProfile[s.ProfileName], s)
+	}
+
+	var anomalies []Anomaly
+	for _, candidate := range candidates {
+		// The candidate itself is never part of its own baseline
+		var baseline []Session
+		for _, s := range byProfile[candidate.ProfileName] {
+			if candidate.ID == 0 || s.ID != candidate.ID {
+				baseline = append(baseline, s)
+			}
+		}
+		if len(baseline) < minBaselineSessions {
+			continue
+		}
+
+		for _, metric := range anomalyMetrics {
+			values := make([]float64, len(baseline))
+			for i, s := range baseline {
+				values[i] = metric.value(s)
+			}
+			mean, stdDev := meanStdDev(values)
+			if stdDev == 0 {
+				continue
+			}
+
+			value := metric.value(candidate)
+			if score := (value - mean) / stdDev; score > sigma {
+				anomalies = append(anomalies, Anomaly{
+					Session: candidate,
+					Metric:  metric.name,
+					Value:   value,
+					Mean:    mean,
+					StdDev:  stdDev,
+					Sigma:   score,
+				})
+			}
+		}
+	}
+	return anomalies
+}
+
+// meanStdDev returns the mean and population standard deviation of values
+func meanStdDev(values []float64) (float64, float64) {
+	var sum float64
+	for _, v := range values {
+		sum += v
+	}
+	mean := sum / float64(len(values))
+
+	var variance float64
+	for _, v := range values {
+		variance += (v - mean) * (v - mean)
+	}
+	return mean, math.Sqrt(variance / float64(len(values)))
+}
@@ -0,0 +1,52 @@
+package usage
+
+import (
+	"fmt"
+	"time"
+)
+
+// BudgetStatus is a profile's spend against one of its budgets
+type BudgetStatus struct {
+	Period string // "daily" or "monthly"
+	Limit  float64
+	Spent  float64
+}
+
+// Percent returns the share of the budget spent
+func (b BudgetStatus) Percent() float64 {
+	return b.Spent / b.Limit * 100
+}
+
+// Exceeded reports whether the budget is used up
+func (b BudgetStatus) Exceeded() bool {
+	return b.Spent >= b.Limit
+}
+
+// CheckBudgets returns the estimated spend of a profile for today and this month,
+// for each budget that is set (greater than 0)
+func (t *Tracker) CheckBudgets(profile string, daily, monthly float64, now time.Time) ([]BudgetStatus, error) {
+	var statuses []BudgetStatus
+	budgets := []struct {
+		period string
+		limit  float64
+		start  time.Time
+	}{
+		{"daily", daily, time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, now.Location())},
+		{"monthly", monthly, time.Date(now.Year(), now.Month(), 1, 0, 0, 0, 0, now.Location())},
+	}
+	for _, b := range budgets {
+		if b.limit <= 0 {
+			continue
+		}
+		sessions, err := t.db.QuerySessions(QueryFilter{ProfileName: profile, StartDate: b.start})
+		if err != nil {
+			return nil, fmt.Errorf("failed to query sessions: %w", err)
+		}
+		status := BudgetStatus{Period: b.period, Limit: b.limit}
+		for _, s := range sessions {
+			status.Spent += SessionCost(s)
+		}
+		statuses = append(statuses, status)
+	}
+	return statuses, nil
+}
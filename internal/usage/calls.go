@@ -0,0 +1,82 @@
+package usage
+
+import (
+	"sort"
+
+	"github.com/OlaHulleberg/clauderock/internal/monitoring"
+	"github.com/OlaHulleberg/clauderock/internal/pricing"
+)
+
+// ModelCost is the estimated cost of the API calls that went to one model
+type ModelCost struct {
+	Model               string // Pricing key, e.g. "anthropic.claude-sonnet-4-5"
+	Requests            int64
+	InputTokens         int64
+	OutputTokens        int64
+	CacheReadTokens     int64
+	CacheCreationTokens int64
+	TokenCost           float64 // Input and output tokens
+	CacheCost           float64 // Prompt cache reads and writes
+}
+
+// Cost returns the total estimated cost, including the prompt cache
+func (m ModelCost) Cost() float64 {
+	return m.TokenCost + m.CacheCost
+}
+
+// CostsByModel prices summed model usage, most expensive first
+// Each row is priced for its sessions' region and pricing tier; rows of regional profile IDs
+// of the same model are combined under its pricing key
+func CostsByModel(usage []ModelUsage) []ModelCost {
+	byModel := make(map[string]*ModelCost)
+	for _, u := range usage {
+		key := PriceKey(u.Model)
+		m := byModel[key]
+		if m == nil {
+			m = &ModelCost{Model: key}
+			byModel[key] = m
+		}
+		m.Requests += u.Requests
+		m.InputTokens += u.InputTokens
+		m.OutputTokens += u.OutputTokens
+		m.CacheReadTokens += u.CacheReadTokens
+		m.CacheCreationTokens += u.CacheCreationTokens
+		tier := pricing.TierMultiplier(u.PricingTier)
+		m.TokenCost += pricing.CalculateCostInRegion(key, u.Region, u.InputTokens, u.OutputTokens) * tier
+		m.CacheCost += pricing.CalculateCacheCostInRegion(key, u.Region, u.CacheReadTokens, u.CacheCreationTokens) * tier
+	}
+
+	costs := make([]ModelCost, 0, len(byModel))
+	for _, m := range byModel {
+		costs = append(costs, *m)
+	}
+	sort.Slice(costs, func(i, j int) bool {
+		if costs[i].Cost() != costs[j].Cost() {
+			return costs[i].Cost() > costs[j].Cost()
+		}
+		return costs[i].Model < costs[j].Model
+	})
+	return costs
+}
+
+// CallCost estimates the cost of one API call, including the prompt cache, at its session's
+// region and pricing tier
+func CallCost(s Session, c APICall) float64 {
+	key := PriceKey(c.Model)
+	cost := pricing.CalculateCostInRegion(key, s.Region, c.InputTokens, c.OutputTokens) +
+		pricing.CalculateCacheCostInRegion(key, s.Region, c.CacheReadTokens, c.CacheCreationTokens)
+	return cost * pricing.TierMultiplier(s.PricingTier)
+}
+
+// LiveCost estimates the cost of a session in progress, including the prompt cache, pricing each
+// model's calls at its own rate
+// approximate is set when a model's price was estimated from its family
+func LiveCost(m monitoring.LiveMetrics) (cost float64, approximate bool) {
+	for model, tokens := range m.ByModel {
+		key := PriceKey(model)
+		cost += pricing.CalculateCost(key, tokens.InputTokens, tokens.OutputTokens) +
+			pricing.CalculateCacheCost(key, tokens.CacheReadTokens, tokens.CacheCreationTokens)
+		approximate = approximate || pricing.IsEstimated(key)
+	}
+	return cost, approximate
+}
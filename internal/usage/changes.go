@@ -0,0 +1,54 @@
+package usage
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+)
+
+// ChangeWatcher detects sessions recorded by other processes, so a view that polls the
+// database only rebuilds after something changed
+type ChangeWatcher struct {
+	conn    *sql.Conn
+	version int64
+}
+
+// WatchChanges starts watching the database; the first Changed call reports false
+// SQLite's data_version only moves for commits made on other connections, so the watcher
+// keeps one connection of the pool for itself until it's closed
+func (d *Database) WatchChanges() (*ChangeWatcher, error) {
+	conn, err := d.db.Conn(context.Background())
+	if err != nil {
+		return nil, fmt.Errorf("failed to open database connection: %w", err)
+	}
+	w := &ChangeWatcher{conn: conn}
+	if w.version, err = w.dataVersion(); err != nil {
+		conn.Close()
+		return nil, err
+	}
+	return w, nil
+}
+
+// Changed reports whether the database changed since the previous call
+func (w *ChangeWatcher) Changed() (bool, error) {
+	version, err := w.dataVersion()
+	if err != nil {
+		return false, err
+	}
+	changed := version != w.version
+	w.version = version
+	return changed, nil
+}
+
+func (w *ChangeWatcher) dataVersion() (int64, error) {
+	var version int64
+	if err := w.conn.QueryRowContext(context.Background(), "PRAGMA data_version").Scan(&version); err != nil {
+		return 0, fmt.Errorf("failed to check the database for changes: %w", err)
+	}
+	return version, nil
+}
+
+// Close returns the watcher's connection to the pool
+func (w *ChangeWatcher) Close() error {
+	return w.conn.Close()
+}
@@ -0,0 +1,87 @@
+package usage
+
+import (
+	"fmt"
+	"sort"
+)
+
+// PeriodSummary aggregates the sessions of one time period for comparisons
+type PeriodSummary struct {
+	Sessions     int
+	InputTokens  int64
+	OutputTokens int64
+	Cost         float64
+	CacheHitRate float64            // Average over sessions, in percent
+	ModelMix     map[string]float64 // Share of tokens per model, in percent
+}
+
+// Tokens returns input plus output tokens
+func (p *PeriodSummary) Tokens() int64 {
+	return p.InputTokens + p.OutputTokens
+}
+
+// SummarizePeriod aggregates the sessions matching filter
+func (t *Tracker) SummarizePeriod(filter QueryFilter) (*PeriodSummary, error) {
+	sessions, err := t.db.QuerySessions(filter)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query sessions: %w", err)
+	}
+	return summarize(sessions), nil
+}
+
+func summarize(sessions []Session) *PeriodSummary {
+	summary := &PeriodSummary{Sessions: len(sessions), ModelMix: make(map[string]float64)}
+	if len(sessions) == 0 {
+		return summary
+	}
+
+	modelTokens := make(map[string]int64)
+	var totalCacheHitRate float64
+	for _, s := range sessions {
+		summary.InputTokens += s.TotalInputTokens
+		summary.OutputTokens += s.TotalOutputTokens
+		summary.Cost += SessionCost(s)
+		totalCacheHitRate += s.CacheHitRate
+		modelTokens[PriceKey(s.Model)] += s.TotalInputTokens + s.TotalOutputTokens
+	}
+
+	summary.CacheHitRate = totalCacheHitRate / float64(len(sessions))
+	if total := summary.Tokens(); total > 0 {
+		for model, tokens := range modelTokens {
+			summary.ModelMix[model] = float64(tokens) / float64(total) * 100
+		}
+	}
+	return summary
+}
+
+// MixModels returns the models present in either summary, largest combined share first
+func MixModels(a, b *PeriodSummary) []string {
+	combined := make(map[string]float64)
+	for model, share := range a.ModelMix {
+		combined[model] += share
+	}
+	for model, share := range b.ModelMix {
+		combined[model] += share
+	}
+
+	models := make([]string, 0, len(combined))
+	for model := range combined {
+		models = append(models, model)
+	}
+	sort.Slice(models, func(i, j int) bool {
+		if combined[models[i]] != combined[models[j]] {
+			return combined[models[i]] > combined[models[j]]
+		}
+		return models[i] < models[j]
+	})
+	return models
+}
+
+// PercentChange returns the relative change from before to after in percent
+// ok is false when before is zero and a percentage is meaningless
+func PercentChange(before, after float64) (change float64, ok bool) {
+	if before == 0 {
+		return 0, false
+	}
+	return (after - before) / before * 100, true
+}
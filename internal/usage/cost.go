@@ -0,0 +1,182 @@
+package usage
+
+import (
+	"fmt"
+	"math"
+	"sort"
+	"time"
+)
+
+// CostBucket is the estimated spend of one day or week of a cost report
+type CostBucket struct {
+	Start time.Time
+	Cost  float64
+}
+
+// GroupCost is the estimated spend of one profile, model, or project over a cost report's range
+type GroupCost struct {
+	Name     string
+	Cost     float64
+	Sessions int
+	Trend    []float64 // Spend per bucket, aligned with CostReport.Buckets
+}
+
+// CostReport breaks the estimated spend of the sessions in a range down by day or week,
+// by profile, by model, and by project, with the current month's spend and where it is heading
+type CostReport struct {
+	Start          time.Time
+	End            time.Time
+	Weekly         bool
+	Buckets        []CostBucket // Every day or week of the range, including ones without sessions
+	Profiles       []GroupCost  // Most expensive first
+	Models         []GroupCost  // Most expensive first
+	Projects       []GroupCost  // By git repository, most expensive first; sessions outside one are left out
+	Total          float64
+	MonthToDate    float64
+	ProjectedMonth float64 // Month-to-date spend extrapolated to the whole month
+}
+
+// CostReport builds a cost report for the sessions matching filter in the days ending with now's day;
+// weekly buckets start on Monday, so the range is extended back to the first Monday
+// filter's dates are ignored; its other conditions also apply to the month-to-date totals
+func (t *Tracker) CostReport(filter QueryFilter, days int, weekly bool, now time.Time) (*CostReport, error) {
+	if days < 1 {
+		return nil, fmt.Errorf("days must be at least 1")
+	}
+
+	today := startOfDay(now)
+	start := today.AddDate(0, 0, -(days - 1))
+	step := 1
+	if weekly {
+		start = startOfWeek(start)
+		step = 7
+	}
+	report := &CostReport{Start: start, End: now, Weekly: weekly}
+	for day := start; !day.After(today); day = day.AddDate(0, 0, step) {
+		report.Buckets = append(report.Buckets, CostBucket{Start: day})
+	}
+
+	monthStart := time.Date(now.Year(), now.Month(), 1, 0, 0, 0, 0, now.Location())
+	queryStart := start
+	if monthStart.Before(queryStart) {
+		queryStart = monthStart
+	}
+	filter.StartDate, filter.EndDate = queryStart, now
+	sessions, err := t.db.QuerySessions(filter)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query sessions: %w", err)
+	}
+
+	profiles := make(map[string]*GroupCost)
+	models := make(map[string]*GroupCost)
+	projects := make(map[string]*GroupCost)
+	group := func(groups map[string]*GroupCost, name string) *GroupCost {
+		g := groups[name]
+		if g == nil {
+			g = &GroupCost{Name: name, Trend: make([]float64, len(report.Buckets))}
+			groups[name] = g
+		}
+		return g
+	}
+
+	for _, s := range sessions {
+		cost := SessionCost(s)
+		if !s.StartTime.Before(monthStart) {
+			report.MonthToDate += cost
+		}
+		if s.StartTime.Before(start) {
+			continue
+		}
+
+		// Rounded, since a day across a daylight saving change isn't 24 hours
+		daysIn := int(math.Round(startOfDay(s.StartTime.In(now.Location())).Sub(start).Hours() / 24))
+		bucket := daysIn / step
+		if bucket >= len(report.Buckets) {
+			bucket = len(report.Buckets) - 1
+		}
+		report.Buckets[bucket].Cost += cost
+		report.Total += cost
+
+		p := group(profiles, s.ProfileName)
+		p.Cost += cost
+		p.Sessions++
+		p.Trend[bucket] += cost
+
+		if s.Repo != "" {
+			r := group(projects, s.Repo)
+			r.Cost += cost
+			r.Sessions++
+			r.Trend[bucket] += cost
+		}
+
+		// Heavy model calls are priced separately from the rest of the session
+		heavy := HeavyCost(s)
+		m := group(models, PriceKey(s.Model))
+		m.Cost += cost - heavy
+		m.Sessions++
+		m.Trend[bucket] += cost - heavy
+		if heavy > 0 {
+			h := group(models, PriceKey(s.HeavyModel))
+			h.Cost += heavy
+			h.Trend[bucket] += heavy
+		}
+	}
+
+	report.Profiles = sortedGroups(profiles)
+	report.Models = sortedGroups(models)
+	report.Projects = sortedGroups(projects)
+
+	daysInMonth := monthStart.AddDate(0, 1, 0).Sub(monthStart).Hours() / 24
+	if elapsed := now.Sub(monthStart).Hours() / 24; elapsed > 0 {
+		report.ProjectedMonth = report.MonthToDate / elapsed * daysInMonth
+	}
+	return report, nil
+}
+
+// MonthToDateByProfile returns each profile's sessions and estimated spend since the start of
+// now's month, by profile name; profiles without sessions this month are left out
+func (t *Tracker) MonthToDateByProfile(now time.Time) (map[string]GroupCost, error) {
+	monthStart := time.Date(now.Year(), now.Month(), 1, 0, 0, 0, 0, now.Location())
+	sessions, err := t.db.QuerySessions(QueryFilter{StartDate: monthStart, EndDate: now})
+	if err != nil {
+		return nil, fmt.Errorf("failed to query sessions: %w", err)
+	}
+
+	profiles := make(map[string]GroupCost)
+	for _, s := range sessions {
+		g := profiles[s.ProfileName]
+		g.Name = s.ProfileName
+		g.Sessions++
+		g.Cost += SessionCost(s)
+		profiles[s.ProfileName] = g
+	}
+	return profiles, nil
+}
+
+// sortedGroups returns the groups most expensive first
+func sortedGroups(groups map[string]*GroupCost) []GroupCost {
+	sorted := make([]GroupCost, 0, len(groups))
+	for _, g := range groups {
+		sorted = append(sorted, *g)
+	}
+	sort.Slice(sorted, func(i, j int) bool {
+		if sorted[i].Cost != sorted[j].Cost {
+			return sorted[i].Cost > sorted[j].Cost
+		}
+		return sorted[i].Name < sorted[j].Name
+	})
+	return sorted
+}
+
+func startOfDay(t time.Time) time.Time {
+	return time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, t.Location())
+}
+
+// startOfWeek returns the Monday starting t's week
+func startOfWeek(t time.Time) time.Time {
+	weekday := int(t.Weekday())
+	if weekday == 0 {
+		weekday = 7 // Sunday
+	}
+	return startOfDay(t).AddDate(0, 0, -(weekday - 1))
+}
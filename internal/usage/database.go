@@ -5,13 +5,45 @@ import (
 	"fmt"
 	"os"
 	"path/filepath"
+	"strings"
+	"sync"
 	"time"
 
-	_ "github.com/mattn/go-sqlite3"
+	"github.com/OlaHulleberg/clauderock/internal/monitoring"
+	_ "modernc.org/sqlite"
+)
+
+// Pool limits; SQLite allows one writer at a time, so more connections only help readers
+const (
+	maxOpenConns    = 4
+	connMaxIdleTime = 5 * time.Minute
+	busyTimeoutMs   = 5000 // How long a write waits for another clauderock's transaction
+)
+
+// callBatchSize is how many API calls a single INSERT statement writes,
+// well under SQLite's limit on statement parameters
+const callBatchSize = 100
+
+// pool is the connection pool and prepared statements every Database in the process shares,
+// so commands that open the database several times connect and migrate it only once
+type pool struct {
+	db   *sql.DB
+	refs int
+
+	mu    sync.Mutex
+	stmts map[string]*sql.Stmt
+}
+
+var (
+	sharedMu   sync.Mutex
+	sharedPool *pool
 )
 
 type Database struct {
-	db *sql.DB
+	db   *sql.DB
+	pool *pool
+
+	closeOnce sync.Once
 }
 
 type Session struct {
@@ -19,9 +51,11 @@ type Session struct {
 	StartTime           time.Time
 	EndTime             time.Time
 	DurationSeconds     int
+	ActiveSeconds       int // Time spent working rather than idle; -1 when unknown
 	ProfileName         string
 	WorkingDirectory    string
 	Model               string
+	Region              string // Empty for non-Bedrock profiles
 	SessionUUID         string
 	TotalRequests       int
 	TotalInputTokens    int64
@@ -35,10 +69,42 @@ type Session struct {
 	PeakRPM             float64
 	P95RPM              float64
 	CacheHitRate        float64
+	ThrottleEvents      int
+	HeavyRequests       int // API calls that went to the heavy model
+	HeavyInputTokens    int64
+	HeavyOutputTokens   int64
+	HeavyModel          string // Model ID the heavy calls reported
+	AvgLatencyMs        float64
+	P95LatencyMs        float64
+	LatencySamples      int    // Calls with a known first-token latency
+	PricingTier         string // Profile's pricing tier when the session ran; empty means standard
+	Name                string // Set with --clauderock-session-name; empty for unnamed sessions
+	Note                string // Added when a named session ends
+	Repo                string // Git repository of the working directory; empty outside one
+	RepoURL             string // The repository's origin remote, without credentials
+	Branch              string // Branch checked out at launch; empty when detached
+	Tags                string // key=value pairs from --clauderock-tag, sorted and comma-separated
 	ExitCode            int
 }
 
+// NewDatabase returns a handle on the usage database, opening the shared pool on first use
+// Close each handle when done; the pool closes with the last one
 func NewDatabase() (*Database, error) {
+	sharedMu.Lock()
+	defer sharedMu.Unlock()
+
+	if sharedPool == nil {
+		p, err := openPool()
+		if err != nil {
+			return nil, err
+		}
+		sharedPool = p
+	}
+	sharedPool.refs++
+	return &Database{db: sharedPool.db, pool: sharedPool}, nil
+}
+
+func openPool() (*pool, error) {
 	home, err := os.UserHomeDir()
 	if err != nil {
 		return nil, fmt.Errorf("failed to get home directory: %w", err)
@@ -52,19 +118,42 @@ func NewDatabase() (*Database, error) {
 		return nil, fmt.Errorf("failed to create directory: %w", err)
 	}
 
-	db, err := sql.Open("sqlite3", dbPath)
+	// WAL lets stats and the dashboard read while a session is being recorded; immediate
+	// transactions take the write lock up front, so concurrent writers wait instead of failing
+	// The pure Go driver keeps release builds free of cgo; stored times use SQLite's own format
+	dsn := fmt.Sprintf("file:%s?_pragma=busy_timeout(%d)&_pragma=journal_mode(WAL)&_txlock=immediate&_time_format=sqlite",
+		filepath.ToSlash(dbPath), busyTimeoutMs)
+	db, err := sql.Open("sqlite", dsn)
 	if err != nil {
 		return nil, fmt.Errorf("failed to open database: %w", err)
 	}
+	db.SetMaxOpenConns(maxOpenConns)
+	db.SetMaxIdleConns(maxOpenConns)
+	db.SetConnMaxIdleTime(connMaxIdleTime)
 
 	d := &Database{db: db}
-
 	if err := d.Init(); err != nil {
 		db.Close()
 		return nil, fmt.Errorf("failed to initialize database: %w", err)
 	}
 
-	return d, nil
+	return &pool{db: db, stmts: make(map[string]*sql.Stmt)}, nil
+}
+
+// stmt returns a prepared statement for query, preparing it on first use
+func (d *Database) stmt(query string) (*sql.Stmt, error) {
+	d.pool.mu.Lock()
+	defer d.pool.mu.Unlock()
+
+	if stmt, ok := d.pool.stmts[query]; ok {
+		return stmt, nil
+	}
+	stmt, err := d.db.Prepare(query)
+	if err != nil {
+		return nil, err
+	}
+	d.pool.stmts[query] = stmt
+	return stmt, nil
 }
 
 func (d *Database) Init() error {
@@ -94,89 +183,442 @@ func (d *Database) Init() error {
 	);
 
 	CREATE INDEX IF NOT EXISTS idx_session_start_time ON sessions(start_time);
-	CREATE INDEX IF NOT EXISTS idx_session_profile_name ON sessions(profile_name);
-	CREATE INDEX IF NOT EXISTS idx_session_model ON sessions(model);
 	CREATE INDEX IF NOT EXISTS idx_session_uuid ON sessions(session_uuid);
 	`
 
-	_, err := d.db.Exec(schema)
+	if _, err := d.db.Exec(schema); err != nil {
+		return err
+	}
+
+	// Columns added after the initial schema
+	columns := []struct{ name, definition string }{
+		{"throttle_events", "INTEGER DEFAULT 0"},
+		{"heavy_requests", "INTEGER DEFAULT 0"},
+		{"heavy_input_tokens", "INTEGER DEFAULT 0"},
+		{"heavy_output_tokens", "INTEGER DEFAULT 0"},
+		{"heavy_model", "TEXT DEFAULT ''"},
+		{"active_seconds", "INTEGER DEFAULT -1"},
+		{"region", "TEXT DEFAULT ''"},
+		{"avg_latency_ms", "REAL DEFAULT 0"},
+		{"p95_latency_ms", "REAL DEFAULT 0"},
+		{"latency_samples", "INTEGER DEFAULT 0"},
+		{"pricing_tier", "TEXT DEFAULT ''"},
+		{"name", "TEXT DEFAULT ''"},
+		{"note", "TEXT DEFAULT ''"},
+		{"repo", "TEXT DEFAULT ''"},
+		{"repo_url", "TEXT DEFAULT ''"},
+		{"branch", "TEXT DEFAULT ''"},
+		{"tags", "TEXT DEFAULT ''"},
+	}
+	for _, c := range columns {
+		if err := d.ensureColumn("sessions", c.name, c.definition); err != nil {
+			return err
+		}
+	}
+
+	// Individual API calls of each session, for per-model costs and hourly activity
+	calls := `
+	CREATE TABLE IF NOT EXISTS api_calls (
+		id INTEGER PRIMARY KEY AUTOINCREMENT,
+		session_id INTEGER NOT NULL REFERENCES sessions(id) ON DELETE CASCADE,
+		timestamp DATETIME NOT NULL,
+		model TEXT NOT NULL,
+		input_tokens INTEGER DEFAULT 0,
+		output_tokens INTEGER DEFAULT 0,
+		cache_read_tokens INTEGER DEFAULT 0,
+		cache_creation_tokens INTEGER DEFAULT 0,
+		latency_ms INTEGER DEFAULT 0
+	);
+
+	CREATE INDEX IF NOT EXISTS idx_api_call_timestamp ON api_calls(timestamp);
+	`
+	if _, err := d.db.Exec(calls); err != nil {
+		return err
+	}
+	// Region that served the call; empty for calls recorded before, which used the session's
+	if err := d.ensureColumn("api_calls", "region", "TEXT DEFAULT ''"); err != nil {
+		return err
+	}
+
+	// Stats group sessions by profile, model and region over a date range, and load each
+	// session's calls in order; these indices cover those queries and replace the
+	// single-column ones earlier versions created
+	indices := `
+	DROP INDEX IF EXISTS idx_session_profile_name;
+	DROP INDEX IF EXISTS idx_session_model;
+	DROP INDEX IF EXISTS idx_api_call_session_id;
+
+	CREATE INDEX IF NOT EXISTS idx_session_profile_start ON sessions(profile_name, start_time);
+	CREATE INDEX IF NOT EXISTS idx_session_model_start ON sessions(model, start_time);
+	CREATE INDEX IF NOT EXISTS idx_session_region_model ON sessions(region, model);
+	CREATE INDEX IF NOT EXISTS idx_api_call_session_timestamp ON api_calls(session_id, timestamp);
+	`
+	_, err := d.db.Exec(indices)
 	return err
 }
 
+// ensureColumn adds a column to an existing table when it is missing
+func (d *Database) ensureColumn(table, column, definition string) error {
+	rows, err := d.db.Query(fmt.Sprintf("PRAGMA table_info(%s)", table))
+	if err != nil {
+		return fmt.Errorf("failed to inspect %s: %w", table, err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var (
+			cid        int
+			name, kind string
+			notNull    int
+			dflt       sql.NullString
+			pk         int
+		)
+		if err := rows.Scan(&cid, &name, &kind, &notNull, &dflt, &pk); err != nil {
+			return fmt.Errorf("failed to inspect %s: %w", table, err)
+		}
+		if name == column {
+			return nil
+		}
+	}
+	if err := rows.Err(); err != nil {
+		return fmt.Errorf("failed to inspect %s: %w", table, err)
+	}
+
+	if _, err := d.db.Exec(fmt.Sprintf("ALTER TABLE %s ADD COLUMN %s %s", table, column, definition)); err != nil {
+		return fmt.Errorf("failed to add column %s.%s: %w", table, column, err)
+	}
+	return nil
+}
+
+// APICall is a single API call of a tracked session
+type APICall struct {
+	ID                  int64
+	SessionID           int64
+	Timestamp           time.Time
+	Model               string
+	InputTokens         int64
+	OutputTokens        int64
+	CacheReadTokens     int64
+	CacheCreationTokens int64
+	LatencyMs           int64 // Time to first token; 0 when unknown
+}
+
 type QueryFilter struct {
 	ProfileName string
 	StartDate   time.Time
 	EndDate     time.Time
 	Model       string
+	SessionName string // Matches session names containing it
+	Repo        string // Git repository the session ran in
+	Tag         string // key=value, or a bare key matching sessions tagged with it at all
 }
 
-func (d *Database) InsertSession(session Session) error {
-	query := `
+// SessionRecord is a session together with its individual API calls, for InsertSessions
+type SessionRecord struct {
+	Session Session
+	Calls   []monitoring.APICall
+	CallLog string // Claude Code log the calls are streamed from while inserting, instead of Calls
+
+	// Calls to FastModel were served in FastModelRegion rather than the session's region
+	FastModel       string
+	FastModelRegion string
+}
+
+// callRegion returns the region that served a call of the record's session
+func (r SessionRecord) callRegion(call monitoring.APICall) string {
+	if r.FastModelRegion != "" && PriceKey(call.Model) == PriceKey(r.FastModel) {
+		return r.FastModelRegion
+	}
+	return r.Session.Region
+}
+
+const insertSessionQuery = `
 	INSERT INTO sessions (
 		start_time, end_time, duration_seconds, profile_name, working_directory,
 		model, session_uuid, total_requests, total_input_tokens, total_output_tokens,
 		cache_read_tokens, cache_creation_tokens, avg_tpm, peak_tpm, p95_tpm,
-		avg_rpm, peak_rpm, p95_rpm, cache_hit_rate, exit_code
-	) VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)
+		avg_rpm, peak_rpm, p95_rpm, cache_hit_rate, throttle_events,
+		heavy_requests, heavy_input_tokens, heavy_output_tokens, heavy_model, active_seconds,
+		region, avg_latency_ms, p95_latency_ms, latency_samples, pricing_tier, name, note, repo, repo_url, branch, tags, exit_code
+	) VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)
 	`
 
-	_, err := d.db.Exec(query,
-		session.StartTime,
-		session.EndTime,
-		session.DurationSeconds,
-		session.ProfileName,
-		session.WorkingDirectory,
-		session.Model,
-		session.SessionUUID,
-		session.TotalRequests,
-		session.TotalInputTokens,
-		session.TotalOutputTokens,
-		session.CacheReadTokens,
-		session.CacheCreationTokens,
-		session.AvgTPM,
-		session.PeakTPM,
-		session.P95TPM,
-		session.AvgRPM,
-		session.PeakRPM,
-		session.P95RPM,
-		session.CacheHitRate,
-		session.ExitCode,
-	)
-
-	if err != nil {
-		return fmt.Errorf("failed to insert session: %w", err)
+// insertCallsQuery inserts rows API calls with one statement
+func insertCallsQuery(rows int) string {
+	values := strings.TrimSuffix(strings.Repeat("(?, ?, ?, ?, ?, ?, ?, ?, ?), ", rows), ", ")
+	return `
+	INSERT INTO api_calls (
+		session_id, timestamp, model, input_tokens, output_tokens,
+		cache_read_tokens, cache_creation_tokens, latency_ms, region
+	) VALUES ` + values
+}
+
+func (d *Database) InsertSession(session Session) error {
+	return d.InsertSessionWithCalls(session, nil)
+}
+
+// InsertSessionWithCalls records a session together with its individual API calls
+func (d *Database) InsertSessionWithCalls(session Session, calls []monitoring.APICall) error {
+	return d.InsertSessions([]SessionRecord{{Session: session, Calls: calls}})
+}
+
+// InsertSessions records sessions and their API calls in a single transaction,
+// writing the calls in batches; either every session is recorded or none is
+// Each record's Session.ID is set to the ID it was recorded with
+func (d *Database) InsertSessions(records []SessionRecord) error {
+	if len(records) == 0 {
+		return nil
+	}
+
+	insertSession, err := d.stmt(insertSessionQuery)
+	if err != nil {
+		return fmt.Errorf("failed to prepare session insert: %w", err)
+	}
+	insertBatch, err := d.stmt(insertCallsQuery(callBatchSize))
+	if err != nil {
+		return fmt.Errorf("failed to prepare api call insert: %w", err)
+	}
+	insertCall, err := d.stmt(insertCallsQuery(1))
+	if err != nil {
+		return fmt.Errorf("failed to prepare api call insert: %w", err)
+	}
+
+	tx, err := d.db.Begin()
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	sessionStmt := tx.Stmt(insertSession)
+	batchStmt := tx.Stmt(insertBatch)
+	callStmt := tx.Stmt(insertCall)
+
+	for i, record := range records {
+		session := record.Session
+		result, err := sessionStmt.Exec(
+			session.StartTime,
+			session.EndTime,
+			session.DurationSeconds,
+			session.ProfileName,
+			session.WorkingDirectory,
+			session.Model,
+			session.SessionUUID,
+			session.TotalRequests,
+			session.TotalInputTokens,
+			session.TotalOutputTokens,
+			session.CacheReadTokens,
+			session.CacheCreationTokens,
+			session.AvgTPM,
+			session.PeakTPM,
+			session.P95TPM,
+			session.AvgRPM,
+			session.PeakRPM,
+			session.P95RPM,
+			session.CacheHitRate,
+			session.ThrottleEvents,
+			session.HeavyRequests,
+			session.HeavyInputTokens,
+			session.HeavyOutputTokens,
+			session.HeavyModel,
+			session.ActiveSeconds,
+			session.Region,
+			session.AvgLatencyMs,
+			session.P95LatencyMs,
+			session.LatencySamples,
+			session.PricingTier,
+			session.Name,
+			session.Note,
+			session.Repo,
+			session.RepoURL,
+			session.Branch,
+			session.Tags,
+			session.ExitCode,
+		)
+		if err != nil {
+			return fmt.Errorf("failed to insert session: %w", err)
+		}
+		sessionID, err := result.LastInsertId()
+		if err != nil {
+			return fmt.Errorf("failed to get session id: %w", err)
+		}
+		records[i].Session.ID = sessionID
+		if len(record.Calls) == 0 && record.CallLog == "" {
+			continue
+		}
+
+		w := callWriter{record: record, sessionID: sessionID, batch: batchStmt, single: callStmt}
+		for _, call := range record.Calls {
+			w.add(call)
+		}
+		if record.CallLog != "" {
+			if _, err := monitoring.StreamSessionJSONL(record.CallLog, w.add); err != nil {
+				return err
+			}
+		}
+		if err := w.flush(); err != nil {
+			return fmt.Errorf("failed to insert api calls: %w", err)
+		}
 	}
 
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("failed to commit sessions: %w", err)
+	}
 	return nil
 }
 
-func (d *Database) QuerySessions(filter QueryFilter) ([]Session, error) {
-	query := "SELECT id, start_time, end_time, duration_seconds, profile_name, working_directory, model, session_uuid, total_requests, total_input_tokens, total_output_tokens, cache_read_tokens, cache_creation_tokens, avg_tpm, peak_tpm, p95_tpm, avg_rpm, peak_rpm, p95_rpm, cache_hit_rate, exit_code FROM sessions WHERE 1=1"
-	args := []interface{}{}
+// callWriter writes a session's API calls as they arrive: full batches share one statement
+// and the remainder is written a row at a time, so only one batch is held in memory
+type callWriter struct {
+	record    SessionRecord
+	sessionID int64
+	batch     *sql.Stmt
+	single    *sql.Stmt
+	pending   []monitoring.APICall
+	err       error
+}
+
+// add queues a call, writing the batch once it is full; after a failure calls are dropped
+func (w *callWriter) add(call monitoring.APICall) {
+	if w.err != nil {
+		return
+	}
+	w.pending = append(w.pending, call)
+	if len(w.pending) == callBatchSize {
+		w.err = w.exec(w.batch, w.pending)
+		w.pending = w.pending[:0]
+	}
+}
+
+// flush writes the calls still queued and returns the first error writing any of them
+func (w *callWriter) flush() error {
+	for _, call := range w.pending {
+		if w.err != nil {
+			break
+		}
+		w.err = w.exec(w.single, []monitoring.APICall{call})
+	}
+	w.pending = nil
+	return w.err
+}
+
+func (w *callWriter) exec(stmt *sql.Stmt, calls []monitoring.APICall) error {
+	args := make([]any, 0, len(calls)*9)
+	for _, call := range calls {
+		args = append(args,
+			w.sessionID,
+			call.Timestamp,
+			call.Model,
+			call.InputTokens,
+			call.OutputTokens,
+			call.CacheReadTokens,
+			call.CacheCreationTokens,
+			call.Latency.Milliseconds(),
+			w.record.callRegion(call),
+		)
+	}
+	_, err := stmt.Exec(args...)
+	return err
+}
+
+// filterClause returns the WHERE conditions and arguments selecting the sessions matching filter,
+// with columns qualified by alias when it is set
+func filterClause(filter QueryFilter, alias string) (string, []any) {
+	prefix := ""
+	if alias != "" {
+		prefix = alias + "."
+	}
+	var conditions []string
+	var args []any
 
 	if filter.ProfileName != "" {
-		query += " AND profile_name = ?"
+		conditions = append(conditions, prefix+"profile_name = ?")
 		args = append(args, filter.ProfileName)
 	}
 
 	if !filter.StartDate.IsZero() {
-		query += " AND start_time >= ?"
+		conditions = append(conditions, prefix+"start_time >= ?")
 		args = append(args, filter.StartDate)
 	}
 
 	if !filter.EndDate.IsZero() {
-		query += " AND start_time <= ?"
+		conditions = append(conditions, prefix+"start_time <= ?")
 		args = append(args, filter.EndDate)
 	}
 
 	if filter.Model != "" {
-		query += " AND model = ?"
+		conditions = append(conditions, prefix+"model = ?")
 		args = append(args, filter.Model)
 	}
 
-	query += " ORDER BY start_time DESC"
+	if filter.SessionName != "" {
+		conditions = append(conditions, prefix+"name LIKE ? ESCAPE '\\'")
+		args = append(args, "%"+escapeLike(filter.SessionName)+"%")
+	}
+
+	if filter.Repo != "" {
+		conditions = append(conditions, prefix+"repo = ?")
+		args = append(args, filter.Repo)
+	}
+
+	if filter.Tag != "" {
+		conditions = append(conditions, "',' || "+prefix+"tags || ',' LIKE ? ESCAPE '\\'")
+		args = append(args, tagPattern(filter.Tag))
+	}
+
+	if len(conditions) == 0 {
+		return "", nil
+	}
+	return " WHERE " + strings.Join(conditions, " AND "), args
+}
+
+// SetSessionNote sets the note of a recorded session
+func (d *Database) SetSessionNote(id int64, note string) error {
+	if _, err := d.db.Exec("UPDATE sessions SET note = ? WHERE id = ?", note, id); err != nil {
+		return fmt.Errorf("failed to save session note: %w", err)
+	}
+	return nil
+}
+
+// escapeLike escapes the LIKE wildcards in s, for patterns using ESCAPE '\'
+func escapeLike(s string) string {
+	return strings.NewReplacer(`\`, `\\`, "%", `\%`, "_", `\_`).Replace(s)
+}
+
+// sessionColumns are the columns querySessions reads into a Session
+const sessionColumns = "id, start_time, end_time, duration_seconds, profile_name, working_directory, model, session_uuid, total_requests, total_input_tokens, total_output_tokens, cache_read_tokens, cache_creation_tokens, avg_tpm, peak_tpm, p95_tpm, avg_rpm, peak_rpm, p95_rpm, cache_hit_rate, throttle_events, heavy_requests, heavy_input_tokens, heavy_output_tokens, heavy_model, active_seconds, region, avg_latency_ms, p95_latency_ms, latency_samples, pricing_tier, name, note, repo, repo_url, branch, tags, exit_code"
+
+// sessionOrders are the ORDER BY expressions QueryTopSessions ranks sessions by; active time
+// falls back to the duration for sessions recorded before it was (see ActiveSeconds)
+var sessionOrders = map[string]string{
+	TopByTPM:      "avg_tpm",
+	TopByTokens:   "total_input_tokens + total_output_tokens",
+	TopByDuration: "CASE WHEN active_seconds < 0 THEN duration_seconds ELSE active_seconds END",
+}
+
+func (d *Database) QuerySessions(filter QueryFilter) ([]Session, error) {
+	where, args := filterClause(filter, "")
+	return d.querySessions("SELECT "+sessionColumns+" FROM sessions"+where+" ORDER BY start_time DESC", args)
+}
+
+// QueryTopSessions returns up to limit sessions matching filter with the highest TPM, tokens,
+// or active time, newest first among equals; cost depends on prices, so TopSessions ranks it
+func (d *Database) QueryTopSessions(filter QueryFilter, by string, limit int) ([]Session, error) {
+	order, ok := sessionOrders[by]
+	if !ok {
+		return nil, fmt.Errorf("sessions can't be ranked by '%s' in the database", by)
+	}
+	where, args := filterClause(filter, "")
+	return d.querySessions("SELECT "+sessionColumns+" FROM sessions"+where+" ORDER BY "+order+" DESC, start_time DESC LIMIT ?", append(args, limit))
+}
+
+// querySessions runs a query selecting sessionColumns
+func (d *Database) querySessions(query string, args []any) ([]Session, error) {
+	stmt, err := d.stmt(query)
+	if err != nil {
+		return nil, fmt.Errorf("failed to prepare session query: %w", err)
+	}
 
-	rows, err := d.db.Query(query, args...)
+	rows, err := stmt.Query(args...)
 	if err != nil {
 		return nil, fmt.Errorf("failed to query sessions: %w", err)
 	}
@@ -206,6 +648,23 @@ func (d *Database) QuerySessions(filter QueryFilter) ([]Session, error) {
 			&s.PeakRPM,
 			&s.P95RPM,
 			&s.CacheHitRate,
+			&s.ThrottleEvents,
+			&s.HeavyRequests,
+			&s.HeavyInputTokens,
+			&s.HeavyOutputTokens,
+			&s.HeavyModel,
+			&s.ActiveSeconds,
+			&s.Region,
+			&s.AvgLatencyMs,
+			&s.P95LatencyMs,
+			&s.LatencySamples,
+			&s.PricingTier,
+			&s.Name,
+			&s.Note,
+			&s.Repo,
+			&s.RepoURL,
+			&s.Branch,
+			&s.Tags,
 			&s.ExitCode,
 		)
 		if err != nil {
@@ -214,28 +673,242 @@ func (d *Database) QuerySessions(filter QueryFilter) ([]Session, error) {
 		sessions = append(sessions, s)
 	}
 
-	return sessions, nil
+	return sessions, rows.Err()
+}
+
+// ModelUsage is the usage one model served for sessions of one region and pricing tier,
+// summed by QueryModelUsage
+type ModelUsage struct {
+	Model               string
+	Region              string
+	PricingTier         string
+	Requests            int64
+	InputTokens         int64
+	OutputTokens        int64
+	CacheReadTokens     int64
+	CacheCreationTokens int64
+}
+
+// andWhere adds a condition to a WHERE clause from filterClause
+func andWhere(where, condition string) string {
+	if where == "" {
+		return " WHERE " + condition
+	}
+	return where + " AND " + condition
+}
+
+// QueryModelUsage sums the tokens of the sessions matching filter by the model that served them,
+// grouped in a single query. Sessions with recorded API calls are summed per call; older sessions
+// are split between their main and heavy model from their totals
+func (d *Database) QueryModelUsage(filter QueryFilter) ([]ModelUsage, error) {
+	where, args := filterClause(filter, "s")
+	noCalls := "NOT EXISTS (SELECT 1 FROM api_calls c WHERE c.session_id = s.id)"
+
+	stmt, err := d.stmt(`
+	SELECT c.model, s.region, s.pricing_tier, COUNT(*), SUM(c.input_tokens), SUM(c.output_tokens),
+		SUM(c.cache_read_tokens), SUM(c.cache_creation_tokens)
+	FROM api_calls c JOIN sessions s ON s.id = c.session_id` + where + `
+	GROUP BY c.model, s.region, s.pricing_tier
+	UNION ALL
+	SELECT s.model, s.region, s.pricing_tier, SUM(s.total_requests - s.heavy_requests),
+		SUM(s.total_input_tokens - s.heavy_input_tokens), SUM(s.total_output_tokens - s.heavy_output_tokens),
+		SUM(s.cache_read_tokens), SUM(s.cache_creation_tokens)
+	FROM sessions s` + andWhere(where, noCalls) + `
+	GROUP BY s.model, s.region, s.pricing_tier
+	UNION ALL
+	SELECT s.heavy_model, s.region, s.pricing_tier, SUM(s.heavy_requests),
+		SUM(s.heavy_input_tokens), SUM(s.heavy_output_tokens), 0, 0
+	FROM sessions s` + andWhere(where, "s.heavy_requests > 0 AND "+noCalls) + `
+	GROUP BY s.heavy_model, s.region, s.pricing_tier`)
+	if err != nil {
+		return nil, fmt.Errorf("failed to prepare model usage query: %w", err)
+	}
+
+	rows, err := stmt.Query(append(append(append([]any{}, args...), args...), args...)...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query model usage: %w", err)
+	}
+	defer rows.Close()
+
+	var usage []ModelUsage
+	for rows.Next() {
+		var u ModelUsage
+		if err := rows.Scan(
+			&u.Model,
+			&u.Region,
+			&u.PricingTier,
+			&u.Requests,
+			&u.InputTokens,
+			&u.OutputTokens,
+			&u.CacheReadTokens,
+			&u.CacheCreationTokens,
+		); err != nil {
+			return nil, fmt.Errorf("failed to scan row: %w", err)
+		}
+		usage = append(usage, u)
+	}
+
+	return usage, rows.Err()
+}
+
+// QueryHourlyRequests counts the recorded API calls of the sessions matching filter
+// by the local hour of day they were made
+func (d *Database) QueryHourlyRequests(filter QueryFilter) ([24]int, error) {
+	var hours [24]int
+	where, args := filterClause(filter, "s")
+	stmt, err := d.stmt(`SELECT CAST(strftime('%H', c.timestamp, 'localtime') AS INTEGER), COUNT(*)
+		FROM api_calls c JOIN sessions s ON s.id = c.session_id` + where + " GROUP BY 1")
+	if err != nil {
+		return hours, fmt.Errorf("failed to prepare hourly request query: %w", err)
+	}
+
+	rows, err := stmt.Query(args...)
+	if err != nil {
+		return hours, fmt.Errorf("failed to query hourly requests: %w", err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var hour sql.NullInt64
+		var count int
+		if err := rows.Scan(&hour, &count); err != nil {
+			return hours, fmt.Errorf("failed to scan row: %w", err)
+		}
+		if hour.Valid && hour.Int64 >= 0 && hour.Int64 < 24 {
+			hours[hour.Int64] += count
+		}
+	}
+
+	return hours, rows.Err()
+}
+
+// QueryAPICalls returns the API calls of the sessions matching filter, oldest first
+func (d *Database) QueryAPICalls(filter QueryFilter) ([]APICall, error) {
+	// Filters apply to the sessions, like QuerySessions
+	where, args := filterClause(filter, "s")
+	stmt, err := d.stmt(`SELECT c.id, c.session_id, c.timestamp, c.model, c.input_tokens, c.output_tokens,
+		c.cache_read_tokens, c.cache_creation_tokens, c.latency_ms
+		FROM api_calls c JOIN sessions s ON s.id = c.session_id` + where + " ORDER BY c.timestamp")
+	if err != nil {
+		return nil, fmt.Errorf("failed to prepare api call query: %w", err)
+	}
+
+	rows, err := stmt.Query(args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query api calls: %w", err)
+	}
+	defer rows.Close()
+
+	var calls []APICall
+	for rows.Next() {
+		var c APICall
+		err := rows.Scan(
+			&c.ID,
+			&c.SessionID,
+			&c.Timestamp,
+			&c.Model,
+			&c.InputTokens,
+			&c.OutputTokens,
+			&c.CacheReadTokens,
+			&c.CacheCreationTokens,
+			&c.LatencyMs,
+		)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan row: %w", err)
+		}
+		calls = append(calls, c)
+	}
+
+	return calls, rows.Err()
+}
+
+// CallLatency is the first-token latency of one recorded API call
+type CallLatency struct {
+	Model     string
+	Region    string // Region that served the call
+	LatencyMs int64
+}
+
+// QueryCallLatencies returns the known first-token latencies of the API calls of the
+// sessions matching filter
+func (d *Database) QueryCallLatencies(filter QueryFilter) ([]CallLatency, error) {
+	where, args := filterClause(filter, "s")
+	stmt, err := d.stmt(`SELECT c.model, COALESCE(NULLIF(c.region, ''), s.region, ''), c.latency_ms
+		FROM api_calls c JOIN sessions s ON s.id = c.session_id` + andWhere(where, "c.latency_ms > 0"))
+	if err != nil {
+		return nil, fmt.Errorf("failed to prepare latency query: %w", err)
+	}
+
+	rows, err := stmt.Query(args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query latencies: %w", err)
+	}
+	defer rows.Close()
+
+	var latencies []CallLatency
+	for rows.Next() {
+		var l CallLatency
+		if err := rows.Scan(&l.Model, &l.Region, &l.LatencyMs); err != nil {
+			return nil, fmt.Errorf("failed to scan row: %w", err)
+		}
+		latencies = append(latencies, l)
+	}
+
+	return latencies, rows.Err()
 }
 
+// Close releases the handle; the shared pool and its statements close with the last handle
 func (d *Database) Close() error {
-	return d.db.Close()
+	var err error
+	d.closeOnce.Do(func() {
+		sharedMu.Lock()
+		defer sharedMu.Unlock()
+
+		d.pool.refs--
+		if d.pool.refs > 0 {
+			return
+		}
+		for _, stmt := range d.pool.stmts {
+			stmt.Close()
+		}
+		err = d.pool.db.Close()
+		if sharedPool == d.pool {
+			sharedPool = nil
+		}
+	})
+	return err
 }
 
 // CountSessions returns the total number of sessions in the database
 func (d *Database) CountSessions() (int, error) {
-	var count int
-	err := d.db.QueryRow("SELECT COUNT(*) FROM sessions").Scan(&count)
+	stmt, err := d.stmt("SELECT COUNT(*) FROM sessions")
 	if err != nil {
+		return 0, fmt.Errorf("failed to prepare session count: %w", err)
+	}
+
+	var count int
+	if err := stmt.QueryRow().Scan(&count); err != nil {
 		return 0, fmt.Errorf("failed to count sessions: %w", err)
 	}
 	return count, nil
 }
 
-// ClearSessions deletes all session records from the database
+// ClearSessions deletes all session records, and their API calls, from the database
 func (d *Database) ClearSessions() error {
-	_, err := d.db.Exec("DELETE FROM sessions")
+	tx, err := d.db.Begin()
 	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	if _, err := tx.Exec("DELETE FROM api_calls"); err != nil {
+		return fmt.Errorf("failed to clear api calls: %w", err)
+	}
+	if _, err := tx.Exec("DELETE FROM sessions"); err != nil {
 		return fmt.Errorf("failed to clear sessions: %w", err)
 	}
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("failed to commit: %w", err)
+	}
 	return nil
 }
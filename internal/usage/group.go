@@ -0,0 +1,76 @@
+package usage
+
+import (
+	"fmt"
+	"sort"
+)
+
+// Ways of grouping sessions for GroupSessions
+const (
+	GroupByRepo   = "repo"
+	GroupByBranch = "branch"
+)
+
+// SessionGroup sums the sessions of one repository or branch
+type SessionGroup struct {
+	Name          string // Repository, or repository@branch
+	RepoURL       string // The repository's origin remote, when its sessions recorded one
+	Sessions      int
+	ActiveSeconds int
+	Requests      int
+	Tokens        int64 // Input and output
+	Cost          float64
+}
+
+// GroupSessions sums the sessions matching filter per repository, or per branch of each repository,
+// most expensive first; sessions outside a repository, or recorded before repositories were, form
+// one group, as do a repository's sessions on a detached HEAD
+func (t *Tracker) GroupSessions(filter QueryFilter, by string) ([]SessionGroup, error) {
+	if by != GroupByRepo && by != GroupByBranch {
+		return nil, fmt.Errorf("invalid grouping '%s': use %s or %s", by, GroupByRepo, GroupByBranch)
+	}
+	sessions, err := t.db.QuerySessions(filter)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query sessions: %w", err)
+	}
+
+	groups := make(map[string]*SessionGroup)
+	for _, s := range sessions {
+		name := s.Repo
+		if name == "" {
+			name = "(no repository)"
+		} else if by == GroupByBranch {
+			branch := s.Branch
+			if branch == "" {
+				branch = "(detached)"
+			}
+			name += "@" + branch
+		}
+
+		g := groups[name]
+		if g == nil {
+			g = &SessionGroup{Name: name}
+			groups[name] = g
+		}
+		if g.RepoURL == "" {
+			g.RepoURL = s.RepoURL
+		}
+		g.Sessions++
+		g.ActiveSeconds += ActiveSeconds(s)
+		g.Requests += s.TotalRequests
+		g.Tokens += s.TotalInputTokens + s.TotalOutputTokens
+		g.Cost += SessionCost(s)
+	}
+
+	sorted := make([]SessionGroup, 0, len(groups))
+	for _, g := range groups {
+		sorted = append(sorted, *g)
+	}
+	sort.Slice(sorted, func(i, j int) bool {
+		if sorted[i].Cost != sorted[j].Cost {
+			return sorted[i].Cost > sorted[j].Cost
+		}
+		return sorted[i].Name < sorted[j].Name
+	})
+	return sorted, nil
+}
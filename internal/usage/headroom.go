@@ -0,0 +1,34 @@
+package usage
+
+import (
+	"fmt"
+	"time"
+)
+
+// HeadroomWindow is how far back usage counts as recent load when comparing profiles
+const HeadroomWindow = time.Hour
+
+// ProfileLoad summarizes a profile's recent usage, used to judge its remaining quota headroom
+type ProfileLoad struct {
+	Profile        string
+	Sessions       int
+	ThrottleEvents int
+	PeakTPM        float64
+}
+
+// RecentLoad returns the usage of a profile's sessions that started since the given time
+func (t *Tracker) RecentLoad(profile string, since time.Time) (ProfileLoad, error) {
+	sessions, err := t.db.QuerySessions(QueryFilter{ProfileName: profile, StartDate: since})
+	if err != nil {
+		return ProfileLoad{}, fmt.Errorf("failed to query sessions: %w", err)
+	}
+
+	load := ProfileLoad{Profile: profile, Sessions: len(sessions)}
+	for _, s := range sessions {
+		load.ThrottleEvents += s.ThrottleEvents
+		if s.PeakTPM > load.PeakTPM {
+			load.PeakTPM = s.PeakTPM
+		}
+	}
+	return load, nil
+}
@@ -0,0 +1,52 @@
+package usage
+
+import (
+	"strings"
+
+	"github.com/OlaHulleberg/clauderock/internal/monitoring"
+	"github.com/OlaHulleberg/clauderock/internal/pricing"
+)
+
+// IsHeavyModel reports whether a model used by an API call is the expensive heavy model:
+// any Opus model, or the profile's configured heavy model when it differs from the main model
+func IsHeavyModel(model, heavyModel, mainModel string) bool {
+	if model == "" {
+		return false
+	}
+	if strings.Contains(strings.ToLower(model), "opus") {
+		return true
+	}
+	if heavyModel == "" || PriceKey(heavyModel) == PriceKey(mainModel) {
+		return false
+	}
+	return PriceKey(model) == PriceKey(heavyModel)
+}
+
+// addHeavyUsage counts an API call of a session toward its heavy model totals when it went to the heavy model
+func addHeavyUsage(session *Session, call monitoring.APICall, info SessionInfo) {
+	if !IsHeavyModel(call.Model, info.HeavyModel, info.Model) {
+		return
+	}
+	session.HeavyRequests++
+	session.HeavyInputTokens += call.InputTokens
+	session.HeavyOutputTokens += call.OutputTokens
+	session.HeavyModel = call.Model
+}
+
+// HeavyCost estimates the part of a session's cost spent on the heavy model
+func HeavyCost(s Session) float64 {
+	if s.HeavyRequests == 0 {
+		return 0
+	}
+	cost := pricing.CalculateCostInRegion(PriceKey(s.HeavyModel), s.Region, s.HeavyInputTokens, s.HeavyOutputTokens)
+	return cost * pricing.TierMultiplier(s.PricingTier)
+}
+
+// HeavyCostShare returns the heavy model's share of a session's cost, in percent
+func HeavyCostShare(s Session) float64 {
+	total := SessionCost(s)
+	if total == 0 {
+		return 0
+	}
+	return HeavyCost(s) / total * 100
+}
@@ -0,0 +1,50 @@
+package usage
+
+import (
+	"sort"
+
+	"github.com/OlaHulleberg/clauderock/internal/monitoring"
+)
+
+// LatencyStats is the first-token latency of one model in one region
+type LatencyStats struct {
+	Model   string
+	Region  string // Region that served the calls; empty for non-Bedrock profiles
+	AvgMs   float64
+	P95Ms   float64 // Over every call's latency, not per session
+	Samples int
+}
+
+// LatencyByModelRegion pools call latencies per model and the region that served them,
+// sorted by model and then fastest region first
+func LatencyByModelRegion(calls []CallLatency) []LatencyStats {
+	type key struct{ model, region string }
+	samples := make(map[key][]float64)
+	for _, c := range calls {
+		k := key{PriceKey(c.Model), c.Region}
+		samples[k] = append(samples[k], float64(c.LatencyMs))
+	}
+
+	result := make([]LatencyStats, 0, len(samples))
+	for k, values := range samples {
+		var sum float64
+		for _, v := range values {
+			sum += v
+		}
+		_, p95 := monitoring.PeakAndP95(values)
+		result = append(result, LatencyStats{
+			Model:   k.model,
+			Region:  k.region,
+			AvgMs:   sum / float64(len(values)),
+			P95Ms:   p95,
+			Samples: len(values),
+		})
+	}
+	sort.Slice(result, func(i, j int) bool {
+		if result[i].Model != result[j].Model {
+			return result[i].Model < result[j].Model
+		}
+		return result[i].AvgMs < result[j].AvgMs
+	})
+	return result
+}
@@ -0,0 +1,504 @@
+package usage
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"os"
+	"os/user"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/OlaHulleberg/clauderock/internal/fileutil"
+)
+
+// otlpTimeout bounds a push to the OpenTelemetry collector, which runs as Claude Code exits
+const otlpTimeout = 5 * time.Second
+
+// MetricsExportSettings configures the opt-in metrics exporter; either target or both may be set
+type MetricsExportSettings struct {
+	OTLPEndpoint   string            `json:"otlp-endpoint,omitempty"`   // OTLP/HTTP base URL, e.g. http://collector:4318
+	OTLPHeaders    map[string]string `json:"otlp-headers,omitempty"`    // Sent with every push, e.g. for authentication
+	PrometheusFile string            `json:"prometheus-file,omitempty"` // Written for node_exporter's textfile collector
+	Developer      string            `json:"developer,omitempty"`       // Label identifying whose usage it is; defaults to the OS user
+}
+
+// Enabled reports whether any export target is configured
+func (s MetricsExportSettings) Enabled() bool {
+	return s.OTLPEndpoint != "" || s.PrometheusFile != ""
+}
+
+// DeveloperName returns the developer label, falling back to the OS user name
+func (s MetricsExportSettings) DeveloperName() string {
+	if s.Developer != "" {
+		return s.Developer
+	}
+	if u, err := user.Current(); err == nil && u.Username != "" {
+		return u.Username
+	}
+	return "unknown"
+}
+
+func metricsExportPath() (string, error) {
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return "", fmt.Errorf("failed to get home directory: %w", err)
+	}
+	return filepath.Join(home, ".clauderock", "metrics-export.json"), nil
+}
+
+// LoadMetricsExportSettings returns the saved exporter settings; exporting is off until configured
+func LoadMetricsExportSettings() (MetricsExportSettings, error) {
+	path, err := metricsExportPath()
+	if err != nil {
+		return MetricsExportSettings{}, err
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return MetricsExportSettings{}, nil
+		}
+		return MetricsExportSettings{}, fmt.Errorf("failed to read metrics export settings: %w", err)
+	}
+
+	var s MetricsExportSettings
+	if err := json.Unmarshal(data, &s); err != nil {
+		return MetricsExportSettings{}, fmt.Errorf("failed to parse metrics export settings: %w", err)
+	}
+	return s, nil
+}
+
+// SaveMetricsExportSettings saves the exporter settings; settings without a target turn exporting off
+func SaveMetricsExportSettings(s MetricsExportSettings) error {
+	path, err := metricsExportPath()
+	if err != nil {
+		return err
+	}
+	if !s.Enabled() {
+		if err := os.Remove(path); err != nil && !os.IsNotExist(err) {
+			return fmt.Errorf("failed to remove metrics export settings: %w", err)
+		}
+		return nil
+	}
+
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return fmt.Errorf("failed to create directory: %w", err)
+	}
+	data, err := json.MarshalIndent(s, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to marshal metrics export settings: %w", err)
+	}
+	// Headers may carry credentials for the collector
+	if err := os.WriteFile(path, data, 0600); err != nil {
+		return fmt.Errorf("failed to write metrics export settings: %w", err)
+	}
+	return nil
+}
+
+// usageSeries is the cumulative usage of one profile, model and region
+type usageSeries struct {
+	Profile             string
+	Model               string // Pricing key, so regional profile IDs of one model share a series
+	Region              string
+	Sessions            int64
+	Requests            int64
+	InputTokens         int64
+	OutputTokens        int64
+	CacheReadTokens     int64
+	CacheCreationTokens int64
+	CostUSD             float64
+	ActiveSeconds       int64
+	First               time.Time // Start of the first session, where the counters start
+	Start               time.Time // Start of the OTLP counters, moved forward when they are reset
+	Last                Session   // Most recent session, for the TPM gauges
+}
+
+// key identifies the series across exports
+func (s usageSeries) key() string {
+	return s.Profile + "\x00" + s.Model + "\x00" + s.Region
+}
+
+// counters lists the series' cumulative values, in a fixed order
+func (s usageSeries) counters() []float64 {
+	return []float64{
+		float64(s.Sessions), float64(s.Requests),
+		float64(s.InputTokens), float64(s.OutputTokens), float64(s.CacheReadTokens), float64(s.CacheCreationTokens),
+		s.CostUSD, float64(s.ActiveSeconds),
+	}
+}
+
+// summarizeSeries groups sessions into cumulative series, ordered by their labels
+func summarizeSeries(sessions []Session) []usageSeries {
+	byKey := make(map[string]*usageSeries)
+	for _, s := range sessions {
+		series := &usageSeries{Profile: s.ProfileName, Model: PriceKey(s.Model), Region: s.Region, First: s.StartTime, Start: s.StartTime, Last: s}
+		if existing := byKey[series.key()]; existing != nil {
+			series = existing
+		} else {
+			byKey[series.key()] = series
+		}
+		series.Sessions++
+		series.Requests += int64(s.TotalRequests)
+		series.InputTokens += s.TotalInputTokens
+		series.OutputTokens += s.TotalOutputTokens
+		series.CacheReadTokens += s.CacheReadTokens
+		series.CacheCreationTokens += s.CacheCreationTokens
+		series.CostUSD += SessionCost(s)
+		series.ActiveSeconds += int64(max(ActiveSeconds(s), 0))
+		if s.StartTime.Before(series.First) {
+			series.First = s.StartTime
+			series.Start = s.StartTime
+		}
+		if s.StartTime.After(series.Last.StartTime) {
+			series.Last = s
+		}
+	}
+
+	all := make([]usageSeries, 0, len(byKey))
+	for _, series := range byKey {
+		all = append(all, *series)
+	}
+	sort.Slice(all, func(i, j int) bool {
+		a, b := all[i], all[j]
+		if a.Profile != b.Profile {
+			return a.Profile < b.Profile
+		}
+		if a.Model != b.Model {
+			return a.Model < b.Model
+		}
+		return a.Region < b.Region
+	})
+	return all
+}
+
+// ExportMetrics exports the cumulative usage of all tracked sessions to the configured targets
+// Counters are cumulative, so a failed or repeated export never double-counts; when they go down,
+// OTLP counters restart so the collector sees the reset
+// Does nothing when exporting isn't enabled
+func (t *Tracker) ExportMetrics() error {
+	settings, err := LoadMetricsExportSettings()
+	if err != nil || !settings.Enabled() {
+		return err
+	}
+
+	sessions, err := t.db.QuerySessions(QueryFilter{})
+	if err != nil {
+		return err
+	}
+	series := summarizeSeries(sessions)
+	developer := settings.DeveloperName()
+	now := time.Now()
+
+	var errs []error
+	if settings.PrometheusFile != "" {
+		if err := writePrometheusFile(settings.PrometheusFile, developer, series); err != nil {
+			errs = append(errs, fmt.Errorf("failed to write %s: %w", settings.PrometheusFile, err))
+		}
+	}
+	if settings.OTLPEndpoint != "" {
+		if err := startCounters(series, now); err != nil {
+			errs = append(errs, fmt.Errorf("failed to update metrics export state: %w", err))
+		}
+		if err := pushOTLP(settings, developer, series, now); err != nil {
+			errs = append(errs, fmt.Errorf("failed to push to %s: %w", settings.OTLPEndpoint, err))
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// exportedSeries is what the last OTLP export sent for a series
+type exportedSeries struct {
+	Start    time.Time `json:"start"`
+	Counters []float64 `json:"counters"`
+}
+
+func metricsExportStatePath() (string, error) {
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return "", fmt.Errorf("failed to get home directory: %w", err)
+	}
+	return filepath.Join(home, ".clauderock", "metrics-export-state.json"), nil
+}
+
+// startCounters sets when each series' OTLP counters start: kept from the last export while they
+// only grow, and moved to now when any went down (e.g., after stats clear or retention pruning),
+// so collectors see a reset rather than a cumulative sum going backwards
+func startCounters(series []usageSeries, now time.Time) error {
+	path, err := metricsExportStatePath()
+	if err != nil {
+		return err
+	}
+	var last map[string]exportedSeries
+	if _, err := fileutil.LoadJSON(path, &last); err != nil {
+		// Without the last export to compare with, the counters start at the first session as before
+		last = nil
+	}
+
+	next := make(map[string]exportedSeries, len(series))
+	for i := range series {
+		s := &series[i]
+		counters := s.counters()
+		if prev, ok := last[s.key()]; ok {
+			s.Start = prev.Start
+			if countersDecreased(prev.Counters, counters) {
+				s.Start = now
+			}
+		}
+		next[s.key()] = exportedSeries{Start: s.Start, Counters: counters}
+	}
+	return fileutil.SaveJSON(path, next, 0644)
+}
+
+// countersDecreased reports whether any counter is lower than it was
+func countersDecreased(prev, counters []float64) bool {
+	if len(prev) != len(counters) {
+		return true
+	}
+	for i := range counters {
+		if counters[i] < prev[i] {
+			return true
+		}
+	}
+	return false
+}
+
+// promMetric describes one Prometheus metric family and how to read it from a series
+type promMetric struct {
+	name, kind, help string
+	labels           string // Extra label, e.g. `type="input"`
+	value            func(s usageSeries) float64
+}
+
+var promMetrics = []promMetric{
+	{"clauderock_sessions_total", "counter", "Claude Code sessions launched through clauderock", "", func(s usageSeries) float64 { return float64(s.Sessions) }},
+	{"clauderock_requests_total", "counter", "API requests made by Claude Code", "", func(s usageSeries) float64 { return float64(s.Requests) }},
+	{"clauderock_tokens_total", "counter", "Tokens used, by type", `type="input"`, func(s usageSeries) float64 { return float64(s.InputTokens) }},
+	{"clauderock_tokens_total", "", "", `type="output"`, func(s usageSeries) float64 { return float64(s.OutputTokens) }},
+	{"clauderock_tokens_total", "", "", `type="cache_read"`, func(s usageSeries) float64 { return float64(s.CacheReadTokens) }},
+	{"clauderock_tokens_total", "", "", `type="cache_creation"`, func(s usageSeries) float64 { return float64(s.CacheCreationTokens) }},
+	{"clauderock_cost_usd_total", "counter", "Estimated cost in USD", "", func(s usageSeries) float64 { return s.CostUSD }},
+	{"clauderock_active_seconds_total", "counter", "Time spent actively working in Claude Code", "", func(s usageSeries) float64 { return float64(s.ActiveSeconds) }},
+	{"clauderock_last_session_avg_tpm", "gauge", "Average tokens per minute of the most recent session", "", func(s usageSeries) float64 { return s.Last.AvgTPM }},
+	{"clauderock_last_session_peak_tpm", "gauge", "Peak tokens per minute of the most recent session", "", func(s usageSeries) float64 { return s.Last.PeakTPM }},
+	{"clauderock_last_session_timestamp_seconds", "gauge", "When the most recent session started", "", func(s usageSeries) float64 { return float64(s.Last.StartTime.Unix()) }},
+}
+
+// writePrometheusFile writes the series in the Prometheus text format, replacing the file atomically
+// so the textfile collector never reads it half-written
+func writePrometheusFile(path, developer string, series []usageSeries) error {
+	var b bytes.Buffer
+	for _, m := range promMetrics {
+		if m.kind != "" {
+			fmt.Fprintf(&b, "# HELP %s %s\n# TYPE %s %s\n", m.name, m.help, m.name, m.kind)
+		}
+		for _, s := range series {
+			labels := fmt.Sprintf(`developer="%s",profile="%s",model="%s",region="%s"`,
+				promEscape(developer), promEscape(s.Profile), promEscape(s.Model), promEscape(s.Region))
+			if m.labels != "" {
+				labels += "," + m.labels
+			}
+			fmt.Fprintf(&b, "%s{%s} %s\n", m.name, labels, strconv.FormatFloat(m.value(s), 'g', -1, 64))
+		}
+	}
+
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return err
+	}
+	tmp, err := os.CreateTemp(filepath.Dir(path), ".clauderock-*.prom.tmp")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmp.Name())
+	if _, err := tmp.Write(b.Bytes()); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		return err
+	}
+	if err := os.Chmod(tmp.Name(), 0644); err != nil {
+		return err
+	}
+	return os.Rename(tmp.Name(), path)
+}
+
+// promEscape escapes a Prometheus label value
+func promEscape(v string) string {
+	return strings.NewReplacer(`\`, `\\`, `"`, `\"`, "\n", `\n`).Replace(v)
+}
+
+// OTLP/JSON types; only the fields clauderock sends
+type (
+	otlpRequest struct {
+		ResourceMetrics []otlpResourceMetrics `json:"resourceMetrics"`
+	}
+	otlpResourceMetrics struct {
+		Resource     otlpResource       `json:"resource"`
+		ScopeMetrics []otlpScopeMetrics `json:"scopeMetrics"`
+	}
+	otlpResource struct {
+		Attributes []otlpAttribute `json:"attributes"`
+	}
+	otlpScopeMetrics struct {
+		Scope   otlpScope    `json:"scope"`
+		Metrics []otlpMetric `json:"metrics"`
+	}
+	otlpScope struct {
+		Name string `json:"name"`
+	}
+	otlpMetric struct {
+		Name        string     `json:"name"`
+		Description string     `json:"description,omitempty"`
+		Unit        string     `json:"unit,omitempty"`
+		Sum         *otlpSum   `json:"sum,omitempty"`
+		Gauge       *otlpGauge `json:"gauge,omitempty"`
+	}
+	otlpSum struct {
+		DataPoints             []otlpDataPoint `json:"dataPoints"`
+		AggregationTemporality int             `json:"aggregationTemporality"`
+		IsMonotonic            bool            `json:"isMonotonic"`
+	}
+	otlpGauge struct {
+		DataPoints []otlpDataPoint `json:"dataPoints"`
+	}
+	otlpDataPoint struct {
+		Attributes        []otlpAttribute `json:"attributes"`
+		StartTimeUnixNano string          `json:"startTimeUnixNano,omitempty"`
+		TimeUnixNano      string          `json:"timeUnixNano"`
+		AsInt             string          `json:"asInt,omitempty"`
+		AsDouble          *float64        `json:"asDouble,omitempty"`
+	}
+	otlpAttribute struct {
+		Key   string         `json:"key"`
+		Value otlpAttrString `json:"value"`
+	}
+	otlpAttrString struct {
+		StringValue string `json:"stringValue"`
+	}
+)
+
+// otlpCumulative is AGGREGATION_TEMPORALITY_CUMULATIVE
+const otlpCumulative = 2
+
+func otlpAttr(key, value string) otlpAttribute {
+	return otlpAttribute{Key: key, Value: otlpAttrString{StringValue: value}}
+}
+
+// buildOTLPRequest turns the series into cumulative OTLP sums and gauges
+func buildOTLPRequest(developer string, series []usageSeries, now time.Time) otlpRequest {
+	timestamp := strconv.FormatInt(now.UnixNano(), 10)
+	point := func(s usageSeries, extra ...otlpAttribute) otlpDataPoint {
+		attrs := []otlpAttribute{
+			otlpAttr("developer", developer),
+			otlpAttr("profile", s.Profile),
+			otlpAttr("model", s.Model),
+			otlpAttr("region", s.Region),
+		}
+		return otlpDataPoint{
+			Attributes:        append(attrs, extra...),
+			StartTimeUnixNano: strconv.FormatInt(s.Start.UnixNano(), 10),
+			TimeUnixNano:      timestamp,
+		}
+	}
+	intSum := func(name, unit, description string, value func(usageSeries) int64) otlpMetric {
+		sum := &otlpSum{AggregationTemporality: otlpCumulative, IsMonotonic: true}
+		for _, s := range series {
+			p := point(s)
+			p.AsInt = strconv.FormatInt(value(s), 10)
+			sum.DataPoints = append(sum.DataPoints, p)
+		}
+		return otlpMetric{Name: name, Unit: unit, Description: description, Sum: sum}
+	}
+	gauge := func(name, unit, description string, value func(usageSeries) float64) otlpMetric {
+		g := &otlpGauge{}
+		for _, s := range series {
+			p := point(s)
+			p.StartTimeUnixNano = ""
+			v := value(s)
+			p.AsDouble = &v
+			g.DataPoints = append(g.DataPoints, p)
+		}
+		return otlpMetric{Name: name, Unit: unit, Description: description, Gauge: g}
+	}
+
+	tokens := &otlpSum{AggregationTemporality: otlpCumulative, IsMonotonic: true}
+	cost := &otlpSum{AggregationTemporality: otlpCumulative, IsMonotonic: true}
+	for _, s := range series {
+		for _, t := range []struct {
+			kind  string
+			value int64
+		}{
+			{"input", s.InputTokens},
+			{"output", s.OutputTokens},
+			{"cache_read", s.CacheReadTokens},
+			{"cache_creation", s.CacheCreationTokens},
+		} {
+			p := point(s, otlpAttr("type", t.kind))
+			p.AsInt = strconv.FormatInt(t.value, 10)
+			tokens.DataPoints = append(tokens.DataPoints, p)
+		}
+		p := point(s)
+		usd := s.CostUSD
+		p.AsDouble = &usd
+		cost.DataPoints = append(cost.DataPoints, p)
+	}
+
+	metrics := []otlpMetric{
+		intSum("clauderock.sessions", "{session}", "Claude Code sessions launched through clauderock", func(s usageSeries) int64 { return s.Sessions }),
+		intSum("clauderock.requests", "{request}", "API requests made by Claude Code", func(s usageSeries) int64 { return s.Requests }),
+		{Name: "clauderock.tokens", Unit: "{token}", Description: "Tokens used, by type", Sum: tokens},
+		{Name: "clauderock.cost", Unit: "USD", Description: "Estimated cost", Sum: cost},
+		intSum("clauderock.active_time", "s", "Time spent actively working in Claude Code", func(s usageSeries) int64 { return s.ActiveSeconds }),
+		gauge("clauderock.session.avg_tpm", "{token}/min", "Average tokens per minute of the most recent session", func(s usageSeries) float64 { return s.Last.AvgTPM }),
+		gauge("clauderock.session.peak_tpm", "{token}/min", "Peak tokens per minute of the most recent session", func(s usageSeries) float64 { return s.Last.PeakTPM }),
+	}
+
+	host, _ := os.Hostname()
+	return otlpRequest{ResourceMetrics: []otlpResourceMetrics{{
+		Resource: otlpResource{Attributes: []otlpAttribute{
+			otlpAttr("service.name", "clauderock"),
+			otlpAttr("host.name", host),
+		}},
+		ScopeMetrics: []otlpScopeMetrics{{Scope: otlpScope{Name: "clauderock"}, Metrics: metrics}},
+	}}}
+}
+
+// pushOTLP sends the series to an OpenTelemetry collector over OTLP/HTTP with JSON encoding
+func pushOTLP(settings MetricsExportSettings, developer string, series []usageSeries, now time.Time) error {
+	body, err := json.Marshal(buildOTLPRequest(developer, series, now))
+	if err != nil {
+		return err
+	}
+
+	url := strings.TrimRight(settings.OTLPEndpoint, "/")
+	if !strings.HasSuffix(url, "/v1/metrics") {
+		url += "/v1/metrics"
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), otlpTimeout)
+	defer cancel()
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, url, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	for k, v := range settings.OTLPHeaders {
+		req.Header.Set(k, v)
+	}
+
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode/100 != 2 {
+		return fmt.Errorf("collector returned %s", resp.Status)
+	}
+	return nil
+}
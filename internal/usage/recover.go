@@ -0,0 +1,77 @@
+package usage
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"github.com/OlaHulleberg/clauderock/internal/monitoring"
+)
+
+// InterruptedExitCode is recorded for sessions whose clauderock was killed before Claude Code exited,
+// since the real exit code is unknown
+const InterruptedExitCode = -1
+
+// RecoverSessions records the journaled sessions of clauderock runs that were killed before they
+// could track them, ending each at its last heartbeat
+// All recovered sessions are written in one batch; if that fails, none are and their entries are kept
+// Returns how many sessions were recorded
+func (t *Tracker) RecoverSessions() (int, error) {
+	entries, err := monitoring.InterruptedSessions()
+	if err != nil {
+		return 0, err
+	}
+
+	var claimed []string
+	var records []SessionRecord
+	// Entries claimed so far go back to the journal if anything fails
+	release := func(recorded bool) error {
+		var firstErr error
+		for _, id := range claimed {
+			if err := monitoring.ReleaseJournalEntry(id, recorded); err != nil && firstErr == nil {
+				firstErr = err
+			}
+		}
+		return firstErr
+	}
+
+	for _, entry := range entries {
+		ok, err := monitoring.ClaimJournalEntry(entry.ID)
+		if err != nil {
+			_ = release(false)
+			return 0, err
+		}
+		if !ok {
+			continue
+		}
+
+		var info SessionInfo
+		if err := json.Unmarshal(entry.Session, &info); err != nil {
+			// An unreadable entry would fail the same way every time, so drop it
+			_ = monitoring.ReleaseJournalEntry(entry.ID, true)
+			continue
+		}
+		info.StartTime = entry.Start
+		info.EndTime = entry.LastSeen
+		info.ExitCode = InterruptedExitCode
+
+		claimed = append(claimed, entry.ID)
+		records = append(records, buildSessionRecord(info))
+	}
+	if len(records) == 0 {
+		return 0, nil
+	}
+
+	if err := t.db.InsertSessions(records); err != nil {
+		_ = release(false)
+		return 0, fmt.Errorf("failed to record %d interrupted session(s): %w", len(records), err)
+	}
+	if err := release(true); err != nil {
+		return len(records), err
+	}
+
+	// Exporting is best-effort; the sessions are already recorded
+	if err := t.ExportMetrics(); err != nil {
+		fmt.Printf("Warning: failed to export usage metrics: %v\n", err)
+	}
+	return len(records), nil
+}
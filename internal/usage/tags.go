@@ -0,0 +1,56 @@
+package usage
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// FormatTags validates key=value tags and joins them the way they are stored: sorted and
+// comma-separated; a key given twice keeps its last value
+func FormatTags(tags []string) (string, error) {
+	values := make(map[string]string, len(tags))
+	for _, tag := range tags {
+		key, value, err := parseTag(tag)
+		if err != nil {
+			return "", err
+		}
+		if value == "" {
+			return "", fmt.Errorf("invalid tag '%s': use key=value", tag)
+		}
+		values[key] = value
+	}
+
+	pairs := make([]string, 0, len(values))
+	for key, value := range values {
+		pairs = append(pairs, key+"="+value)
+	}
+	sort.Strings(pairs)
+	return strings.Join(pairs, ","), nil
+}
+
+// parseTag splits a key=value tag, or a bare key; neither part may contain a comma
+func parseTag(tag string) (key, value string, err error) {
+	key, value, _ = strings.Cut(strings.TrimSpace(tag), "=")
+	key, value = strings.TrimSpace(key), strings.TrimSpace(value)
+	if key == "" || strings.Contains(tag, ",") {
+		return "", "", fmt.Errorf("invalid tag '%s': use key=value without commas", tag)
+	}
+	return key, value, nil
+}
+
+// CheckTagFilter reports whether a tag can filter sessions: key=value, or a bare key matching any value
+func CheckTagFilter(tag string) error {
+	_, _, err := parseTag(tag)
+	return err
+}
+
+// tagPattern is the LIKE pattern matching stored tags that contain a key=value tag, or any value
+// of a bare key; stored tags are wrapped in commas before matching, so keys can't match partway
+func tagPattern(tag string) string {
+	key, value, _ := parseTag(tag)
+	if value == "" {
+		return "%," + escapeLike(key) + "=%"
+	}
+	return "%," + escapeLike(key) + "=" + escapeLike(value) + ",%"
+}
@@ -0,0 +1,271 @@
+package usage
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"time"
+
+	"github.com/OlaHulleberg/clauderock/internal/aws"
+	"github.com/OlaHulleberg/clauderock/internal/config"
+)
+
+// teamSyncTimeout bounds one sync, which runs as Claude Code exits
+const teamSyncTimeout = 15 * time.Second
+
+// TeamRecord is the anonymized form of a session uploaded for team-wide reports
+// It leaves out anything identifying the developer or their work: the working directory,
+// session name and note, Claude's session ID, and the profile name
+type TeamRecord struct {
+	RecordID            string  `json:"record-id"`    // Unique per session, so re-uploading overwrites rather than duplicates
+	DeveloperID         string  `json:"developer-id"` // Random per installation, so sessions can be counted per developer but not attributed
+	Date                string  `json:"date"`         // Session start, UTC
+	Model               string  `json:"model"`
+	HeavyModel          string  `json:"heavy-model,omitempty"`
+	Region              string  `json:"region,omitempty"`
+	PricingTier         string  `json:"pricing-tier"`
+	DurationSeconds     int     `json:"duration-seconds"`
+	ActiveSeconds       int     `json:"active-seconds"`
+	Requests            int     `json:"requests"`
+	InputTokens         int64   `json:"input-tokens"`
+	OutputTokens        int64   `json:"output-tokens"`
+	CacheReadTokens     int64   `json:"cache-read-tokens"`
+	CacheCreationTokens int64   `json:"cache-creation-tokens"`
+	HeavyRequests       int     `json:"heavy-requests"`
+	HeavyInputTokens    int64   `json:"heavy-input-tokens"`
+	HeavyOutputTokens   int64   `json:"heavy-output-tokens"`
+	ThrottleEvents      int     `json:"throttle-events"`
+	EstimatedCostUSD    float64 `json:"estimated-cost-usd"`
+}
+
+// newTeamRecord anonymizes a session for upload
+func newTeamRecord(s Session, developerID string) TeamRecord {
+	tier := s.PricingTier
+	if tier == "" {
+		tier = config.PricingTierStandard
+	}
+	record := TeamRecord{
+		RecordID:            fmt.Sprintf("%s-%d", developerID, s.ID),
+		DeveloperID:         developerID,
+		Date:                s.StartTime.UTC().Format("2006-01-02"),
+		Model:               PriceKey(s.Model),
+		Region:              s.Region,
+		PricingTier:         tier,
+		DurationSeconds:     s.DurationSeconds,
+		ActiveSeconds:       ActiveSeconds(s),
+		Requests:            s.TotalRequests,
+		InputTokens:         s.TotalInputTokens,
+		OutputTokens:        s.TotalOutputTokens,
+		CacheReadTokens:     s.CacheReadTokens,
+		CacheCreationTokens: s.CacheCreationTokens,
+		HeavyRequests:       s.HeavyRequests,
+		HeavyInputTokens:    s.HeavyInputTokens,
+		HeavyOutputTokens:   s.HeavyOutputTokens,
+		ThrottleEvents:      s.ThrottleEvents,
+		EstimatedCostUSD:    SessionCost(s),
+	}
+	if s.HeavyRequests > 0 {
+		record.HeavyModel = PriceKey(s.HeavyModel)
+	}
+	return record
+}
+
+// teamSyncState remembers what has been uploaded, in ~/.clauderock/team-sync.json
+type teamSyncState struct {
+	DeveloperID string           `json:"developer-id"`
+	Synced      map[string]int64 `json:"synced,omitempty"` // Last uploaded session ID per profile and target
+}
+
+// syncKey keys the progress of a profile's sync; a new target starts over, so it gets every session
+func syncKey(profileName, target string) string {
+	return profileName + " " + target
+}
+
+func teamSyncStatePath() (string, error) {
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return "", fmt.Errorf("failed to get home directory: %w", err)
+	}
+	return filepath.Join(home, ".clauderock", "team-sync.json"), nil
+}
+
+// loadTeamSyncState returns the sync progress, generating the developer ID on first use
+func loadTeamSyncState() (*teamSyncState, error) {
+	path, err := teamSyncStatePath()
+	if err != nil {
+		return nil, err
+	}
+	state := &teamSyncState{}
+	data, err := os.ReadFile(path)
+	if err != nil && !os.IsNotExist(err) {
+		return nil, fmt.Errorf("failed to read team sync state: %w", err)
+	}
+	if err == nil {
+		if err := json.Unmarshal(data, state); err != nil {
+			return nil, fmt.Errorf("failed to parse team sync state: %w", err)
+		}
+	}
+	if state.DeveloperID == "" {
+		id := make([]byte, 8)
+		if _, err := rand.Read(id); err != nil {
+			return nil, fmt.Errorf("failed to generate developer ID: %w", err)
+		}
+		state.DeveloperID = hex.EncodeToString(id)
+	}
+	if state.Synced == nil {
+		state.Synced = make(map[string]int64)
+	}
+	return state, nil
+}
+
+func saveTeamSyncState(state *teamSyncState) error {
+	path, err := teamSyncStatePath()
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return fmt.Errorf("failed to create directory: %w", err)
+	}
+	data, err := json.MarshalIndent(state, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to marshal team sync state: %w", err)
+	}
+	if err := os.WriteFile(path, data, 0644); err != nil {
+		return fmt.Errorf("failed to write team sync state: %w", err)
+	}
+	return nil
+}
+
+// TeamDeveloperID returns the anonymous ID this installation's records are uploaded under
+func TeamDeveloperID() (string, error) {
+	state, err := loadTeamSyncState()
+	if err != nil {
+		return "", err
+	}
+	if err := saveTeamSyncState(state); err != nil {
+		return "", err
+	}
+	return state.DeveloperID, nil
+}
+
+// pendingTeamSessions returns the profile's sessions not yet uploaded to its target, oldest first
+func (t *Tracker) pendingTeamSessions(state *teamSyncState, profileName string, cfg *config.Config) ([]Session, error) {
+	sessions, err := t.db.QuerySessions(QueryFilter{ProfileName: profileName})
+	if err != nil {
+		return nil, fmt.Errorf("failed to query sessions: %w", err)
+	}
+	last := state.Synced[syncKey(profileName, cfg.TeamSync)]
+	var pending []Session
+	for _, s := range sessions {
+		if s.ID > last {
+			pending = append(pending, s)
+		}
+	}
+	sort.Slice(pending, func(i, j int) bool { return pending[i].ID < pending[j].ID })
+	return pending, nil
+}
+
+// PendingTeamSync returns how many of the profile's sessions haven't been uploaded to its target yet
+func (t *Tracker) PendingTeamSync(profileName string, cfg *config.Config) (int, error) {
+	state, err := loadTeamSyncState()
+	if err != nil {
+		return 0, err
+	}
+	pending, err := t.pendingTeamSessions(state, profileName, cfg)
+	if err != nil {
+		return 0, err
+	}
+	return len(pending), nil
+}
+
+// SyncTeamUsage uploads the profile's sessions recorded since its last sync to its team-sync target
+// and returns how many were uploaded; it does nothing for profiles without a target
+// Progress is kept after a failure, so the next sync picks up where this one stopped
+func (t *Tracker) SyncTeamUsage(profileName string, cfg *config.Config) (int, error) {
+	target, ok, err := cfg.TeamSyncTarget()
+	if err != nil || !ok {
+		return 0, err
+	}
+
+	state, err := loadTeamSyncState()
+	if err != nil {
+		return 0, err
+	}
+	pending, err := t.pendingTeamSessions(state, profileName, cfg)
+	if err != nil {
+		return 0, err
+	}
+	if len(pending) == 0 {
+		return 0, saveTeamSyncState(state)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), teamSyncTimeout)
+	defer cancel()
+
+	uploaded := 0
+	var uploadErr error
+	for _, s := range pending {
+		if uploadErr = uploadTeamRecord(ctx, cfg.AWSProfile(), target, newTeamRecord(s, state.DeveloperID)); uploadErr != nil {
+			break
+		}
+		state.Synced[syncKey(profileName, cfg.TeamSync)] = s.ID
+		uploaded++
+	}
+	if err := saveTeamSyncState(state); err != nil {
+		return uploaded, err
+	}
+	return uploaded, uploadErr
+}
+
+// uploadTeamRecord writes one record to an S3 object or DynamoDB item keyed by its record ID
+func uploadTeamRecord(ctx context.Context, awsProfile string, target config.TeamSyncTarget, record TeamRecord) error {
+	if target.Kind == config.TeamSyncDynamoDB {
+		item, err := dynamoDBItem(record)
+		if err != nil {
+			return err
+		}
+		return aws.PutDynamoDBItem(ctx, awsProfile, target.Region, target.Name, item)
+	}
+
+	body, err := json.MarshalIndent(record, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to marshal team record: %w", err)
+	}
+	// Partitioned by date, so Athena or Glue can prune by it
+	key := fmt.Sprintf("date=%s/%s.json", record.Date, record.RecordID)
+	if target.Prefix != "" {
+		key = target.Prefix + "/" + key
+	}
+	return aws.PutS3Object(ctx, awsProfile, target.Region, target.Name, key, body)
+}
+
+// dynamoDBItem converts a record to DynamoDB's attribute value JSON, leaving out empty strings
+func dynamoDBItem(record TeamRecord) (map[string]any, error) {
+	data, err := json.Marshal(record)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal team record: %w", err)
+	}
+	var fields map[string]any
+	if err := json.Unmarshal(data, &fields); err != nil {
+		return nil, fmt.Errorf("failed to convert team record: %w", err)
+	}
+
+	item := make(map[string]any, len(fields))
+	for name, value := range fields {
+		switch v := value.(type) {
+		case string:
+			if v != "" {
+				item[name] = map[string]string{"S": v}
+			}
+		case float64:
+			item[name] = map[string]string{"N": strconv.FormatFloat(v, 'f', -1, 64)}
+		}
+	}
+	return item, nil
+}
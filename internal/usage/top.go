@@ -0,0 +1,60 @@
+package usage
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// Ways of ranking sessions for TopSessions
+const (
+	TopByTPM      = "tpm"
+	TopByTokens   = "tokens"
+	TopByCost     = "cost"
+	TopByDuration = "duration"
+)
+
+// TopByOptions are the rankings TopSessions accepts, in the order they are offered
+var TopByOptions = []string{TopByTPM, TopByTokens, TopByCost, TopByDuration}
+
+// CheckTopBy reports whether sessions can be ranked by by
+func CheckTopBy(by string) error {
+	for _, option := range TopByOptions {
+		if by == option {
+			return nil
+		}
+	}
+	return fmt.Errorf("invalid ranking '%s': use %s", by, strings.Join(TopByOptions, ", "))
+}
+
+// TopSessions returns up to limit sessions matching filter, ranked by average TPM, tokens,
+// estimated cost, or active time
+func (t *Tracker) TopSessions(filter QueryFilter, by string, limit int) ([]Session, error) {
+	if err := CheckTopBy(by); err != nil {
+		return nil, err
+	}
+	if by != TopByCost {
+		sessions, err := t.db.QueryTopSessions(filter, by, limit)
+		if err != nil {
+			return nil, fmt.Errorf("failed to query top sessions: %w", err)
+		}
+		return sessions, nil
+	}
+
+	sessions, err := t.db.QuerySessions(filter)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query sessions: %w", err)
+	}
+	costs := make(map[int64]float64, len(sessions))
+	for _, s := range sessions {
+		costs[s.ID] = SessionCost(s)
+	}
+	// Sessions come newest first; a stable sort keeps that order among equal costs
+	sort.SliceStable(sessions, func(i, j int) bool {
+		return costs[sessions[i].ID] > costs[sessions[j].ID]
+	})
+	if len(sessions) > limit {
+		sessions = sessions[:limit]
+	}
+	return sessions, nil
+}
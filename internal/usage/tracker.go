@@ -2,8 +2,11 @@ package usage
 
 import (
 	"fmt"
+	"slices"
+	"sort"
 	"time"
 
+	"github.com/OlaHulleberg/clauderock/internal/config"
 	"github.com/OlaHulleberg/clauderock/internal/monitoring"
 )
 
@@ -21,29 +24,71 @@ func NewTracker() (*Tracker, error) {
 }
 
 type SessionInfo struct {
-	StartTime           time.Time
-	EndTime             time.Time
-	ProfileName         string
-	WorkingDirectory    string
-	AWSProfile          string
-	Region              string
-	CrossRegion         string
-	Model               string
-	ModelProfileID      string
-	FastModel           string
-	FastModelProfileID  string
-	HeavyModel          string
-	HeavyModelProfileID string
-	ExitCode            int
+	StartTime           time.Time `json:"start-time"`
+	EndTime             time.Time `json:"end-time"`
+	ProfileName         string    `json:"profile-name"`
+	WorkingDirectory    string    `json:"working-directory"`
+	AWSProfile          string    `json:"aws-profile"`
+	Region              string    `json:"region"`
+	CrossRegion         string    `json:"cross-region"`
+	Model               string    `json:"model"`
+	ModelProfileID      string    `json:"model-profile-id"`
+	FastModel           string    `json:"fast-model"`
+	FastModelProfileID  string    `json:"fast-model-profile-id"`
+	FastModelRegion     string    `json:"fast-model-region,omitempty"` // Region fast model requests go to, when not Region
+	HeavyModel          string    `json:"heavy-model"`
+	HeavyModelProfileID string    `json:"heavy-model-profile-id"`
+	PricingTier         string    `json:"pricing-tier"`
+	Name                string    `json:"name,omitempty"`
+	Note                string    `json:"note,omitempty"`
+	Repo                string    `json:"repo,omitempty"`
+	RepoURL             string    `json:"repo-url,omitempty"`
+	Branch              string    `json:"branch,omitempty"`
+	Tags                string    `json:"tags,omitempty"`
+	ExitCode            int       `json:"exit-code"`
 }
 
-func (t *Tracker) TrackSession(info SessionInfo) error {
+// TrackSession records a finished session and returns the ID it was recorded with and any
+// usage anomalies it shows compared to earlier sessions of the same profile
+func (t *Tracker) TrackSession(info SessionInfo) (id int64, anomalies []Anomaly, err error) {
+	record := buildSessionRecord(info)
+
+	// Compare against the profile's history before this session becomes part of it
+	if history, err := t.db.QuerySessions(QueryFilter{ProfileName: record.Session.ProfileName}); err == nil {
+		anomalies = FindAnomalies([]Session{record.Session}, history, DefaultAnomalySigma)
+	}
+
+	records := []SessionRecord{record}
+	if err := t.db.InsertSessions(records); err != nil {
+		return 0, anomalies, err
+	}
+
+	// Exporting is best-effort; the session is already recorded
+	if err := t.ExportMetrics(); err != nil {
+		fmt.Printf("Warning: failed to export usage metrics: %v\n", err)
+	}
+	return records[0].Session.ID, anomalies, nil
+}
+
+// SetSessionNote adds a note to a session after it was recorded
+func (t *Tracker) SetSessionNote(id int64, note string) error {
+	return t.db.SetSessionNote(id, note)
+}
+
+// buildSessionRecord assembles a session's record from its launch info and Claude Code log
+func buildSessionRecord(info SessionInfo) SessionRecord {
+	var heavy Session // Heavy model totals, gathered while the log is read
+
 	// Try to find and parse the JSONL file
 	var metrics *monitoring.SessionMetrics
+	var jsonlPath string
 	if info.WorkingDirectory != "" {
-		jsonlPath, err := monitoring.FindSessionJSONL(info.WorkingDirectory, info.StartTime)
+		var err error
+		jsonlPath, err = monitoring.FindSessionJSONL(info.WorkingDirectory, info.StartTime)
 		if err == nil {
-			metrics, err = monitoring.ParseSessionJSONL(jsonlPath)
+			metrics, err = monitoring.StreamSessionJSONL(jsonlPath, func(call monitoring.APICall) {
+				addHeavyUsage(&heavy, call, info)
+			})
 			if err != nil {
 				// Log error but don't fail - we can still track basic session info
 				fmt.Printf("Warning: failed to parse session JSONL: %v\n", err)
@@ -58,9 +103,18 @@ func (t *Tracker) TrackSession(info SessionInfo) error {
 		StartTime:        info.StartTime,
 		EndTime:          info.EndTime,
 		DurationSeconds:  int(info.EndTime.Sub(info.StartTime).Seconds()),
+		ActiveSeconds:    -1,
 		ProfileName:      info.ProfileName,
 		WorkingDirectory: info.WorkingDirectory,
 		Model:            info.Model,
+		Region:           info.Region,
+		PricingTier:      info.PricingTier,
+		Name:             info.Name,
+		Note:             info.Note,
+		Repo:             info.Repo,
+		RepoURL:          info.RepoURL,
+		Branch:           info.Branch,
+		Tags:             info.Tags,
 		ExitCode:         info.ExitCode,
 	}
 
@@ -79,15 +133,44 @@ func (t *Tracker) TrackSession(info SessionInfo) error {
 		session.PeakRPM = metrics.PeakRPM
 		session.P95RPM = metrics.P95RPM
 		session.CacheHitRate = metrics.CacheHitRate
+		session.ThrottleEvents = metrics.ThrottleEvents
+		session.ActiveSeconds = int(metrics.ActiveDuration.Seconds())
+		session.AvgLatencyMs = metrics.AvgLatencyMs
+		session.P95LatencyMs = metrics.P95LatencyMs
+		session.LatencySamples = metrics.LatencySamples
+		session.HeavyRequests = heavy.HeavyRequests
+		session.HeavyInputTokens = heavy.HeavyInputTokens
+		session.HeavyOutputTokens = heavy.HeavyOutputTokens
+		session.HeavyModel = heavy.HeavyModel
+	}
+
+	// The calls are read from the log again as they are written, rather than held until then
+	record := SessionRecord{Session: session}
+	if metrics != nil {
+		record.CallLog = jsonlPath
 	}
+	if info.FastModelRegion != "" {
+		record.FastModel = info.FastModelProfileID
+		record.FastModelRegion = info.FastModelRegion
+	}
+	return record
+}
 
-	return t.db.InsertSession(session)
+// ActiveSeconds returns the time a session was actively used, falling back to its
+// wall-clock duration for sessions recorded without per-call timestamps
+func ActiveSeconds(s Session) int {
+	if s.ActiveSeconds < 0 {
+		return s.DurationSeconds
+	}
+	return s.ActiveSeconds
 }
 
 type SessionStats struct {
 	TotalSessions      int
-	TotalDurationHours float64
+	TotalDurationHours float64 // Wall-clock time, including idle gaps
 	AvgSessionMinutes  float64
+	TotalActiveHours   float64 // Time spent working, see ActiveSeconds
+	AvgActiveMinutes   float64
 	TotalRequests      int64
 	TotalInputTokens   int64
 	TotalOutputTokens  int64
@@ -98,12 +181,25 @@ type SessionStats struct {
 	PeakRPM            float64
 	P95RPM             float64
 	AvgCacheHitRate    float64
+	HeavyRequests      int64   // API calls that went to the heavy model
+	HeavyCost          float64 // Estimated cost of the heavy calls
+	TotalCost          float64
 	ModelBreakdown     map[string]int
 	ProfileBreakdown   map[string]int
 	TopSessions        []Session
+	HeavySessions      []Session // Sessions with the most heavy model cost
+	Latency            []LatencyStats
+	ModelCosts         []ModelCost // Per model that served the calls, including prompt cache costs
+	HourlyRequests     [24]int     // API calls by local hour of day; only sessions with recorded calls
+	RecordedCalls      int         // API calls stored individually (see QueryModelUsage)
+	FirstStart         time.Time   // Start of the earliest session
+	// Profiles with sessions on Provisioned Throughput, whose tokens are covered by the commitment
+	ProvisionedProfiles []string
 }
 
-func (t *Tracker) GetSessionStats(filter QueryFilter) (*SessionStats, error) {
+// GetSessionStats summarizes the sessions matching filter, listing up to topSessions of them as
+// top sessions, ranked by topBy (see TopSessions)
+func (t *Tracker) GetSessionStats(filter QueryFilter, topBy string, topSessions int) (*SessionStats, error) {
 	sessions, err := t.db.QuerySessions(filter)
 	if err != nil {
 		return nil, fmt.Errorf("failed to query sessions: %w", err)
@@ -121,20 +217,32 @@ func (t *Tracker) GetSessionStats(filter QueryFilter) (*SessionStats, error) {
 	}
 
 	var totalDurationSeconds int64
+	var totalActiveSeconds int64
 	var totalCacheHitRate float64
 	var allTPMs []float64
 	var allRPMs []float64
 
 	for _, session := range sessions {
 		totalDurationSeconds += int64(session.DurationSeconds)
+		totalActiveSeconds += int64(ActiveSeconds(session))
 		stats.TotalRequests += int64(session.TotalRequests)
 		stats.TotalInputTokens += session.TotalInputTokens
 		stats.TotalOutputTokens += session.TotalOutputTokens
 		totalCacheHitRate += session.CacheHitRate
+		stats.HeavyRequests += int64(session.HeavyRequests)
+		stats.HeavyCost += HeavyCost(session)
+		stats.TotalCost += SessionCost(session)
 
 		stats.ModelBreakdown[session.Model]++
 		stats.ProfileBreakdown[session.ProfileName]++
 
+		if stats.FirstStart.IsZero() || session.StartTime.Before(stats.FirstStart) {
+			stats.FirstStart = session.StartTime
+		}
+		if session.PricingTier == config.PricingTierProvisioned && !slices.Contains(stats.ProvisionedProfiles, session.ProfileName) {
+			stats.ProvisionedProfiles = append(stats.ProvisionedProfiles, session.ProfileName)
+		}
+
 		// Collect TPM and RPM values for aggregation
 		if session.AvgTPM > 0 {
 			allTPMs = append(allTPMs, session.AvgTPM)
@@ -155,6 +263,8 @@ func (t *Tracker) GetSessionStats(filter QueryFilter) (*SessionStats, error) {
 	// Calculate averages
 	stats.TotalDurationHours = float64(totalDurationSeconds) / 3600.0
 	stats.AvgSessionMinutes = float64(totalDurationSeconds) / float64(len(sessions)) / 60.0
+	stats.TotalActiveHours = float64(totalActiveSeconds) / 3600.0
+	stats.AvgActiveMinutes = float64(totalActiveSeconds) / float64(len(sessions)) / 60.0
 	stats.AvgCacheHitRate = totalCacheHitRate / float64(len(sessions))
 
 	// Calculate average TPM/RPM
@@ -174,6 +284,8 @@ func (t *Tracker) GetSessionStats(filter QueryFilter) (*SessionStats, error) {
 		stats.AvgRPM = sum / float64(len(allRPMs))
 	}
 
+	sort.Strings(stats.ProvisionedProfiles)
+
 	// Calculate P95 from all sessions
 	if len(sessions) > 0 {
 		var allP95TPMs []float64
@@ -204,16 +316,80 @@ func (t *Tracker) GetSessionStats(filter QueryFilter) (*SessionStats, error) {
 		}
 	}
 
-	// Get top 5 sessions by TPM
-	if len(sessions) >= 5 {
-		stats.TopSessions = sessions[:5]
-	} else {
-		stats.TopSessions = sessions
+	if stats.TopSessions, err = t.TopSessions(filter, topBy, topSessions); err != nil {
+		return nil, err
+	}
+
+	latencies, err := t.db.QueryCallLatencies(filter)
+	if err != nil {
+		return nil, err
+	}
+	stats.Latency = LatencyByModelRegion(latencies)
+
+	if stats.ModelCosts, err = t.ModelCosts(filter); err != nil {
+		return nil, err
+	}
+	if stats.HourlyRequests, err = t.db.QueryHourlyRequests(filter); err != nil {
+		return nil, err
+	}
+	for _, n := range stats.HourlyRequests {
+		stats.RecordedCalls += n
+	}
+
+	// Get top 5 sessions by heavy model cost
+	for _, session := range sessions {
+		if session.HeavyRequests > 0 {
+			stats.HeavySessions = append(stats.HeavySessions, session)
+		}
+	}
+	sort.SliceStable(stats.HeavySessions, func(i, j int) bool {
+		return HeavyCost(stats.HeavySessions[i]) > HeavyCost(stats.HeavySessions[j])
+	})
+	if len(stats.HeavySessions) > 5 {
+		stats.HeavySessions = stats.HeavySessions[:5]
 	}
 
 	return stats, nil
 }
 
+// ModelCosts returns the estimated cost of the sessions matching filter per model that served them,
+// summed in the database rather than call by call
+func (t *Tracker) ModelCosts(filter QueryFilter) ([]ModelCost, error) {
+	usage, err := t.db.QueryModelUsage(filter)
+	if err != nil {
+		return nil, err
+	}
+	return CostsByModel(usage), nil
+}
+
+// Sessions returns the sessions matching filter, newest first
+func (t *Tracker) Sessions(filter QueryFilter) ([]Session, error) {
+	return t.db.QuerySessions(filter)
+}
+
+// HeavyCostShare returns the heavy model's share of the total estimated cost, in percent
+func (s *SessionStats) HeavyCostShare() float64 {
+	if s.TotalCost == 0 {
+		return 0
+	}
+	return s.HeavyCost / s.TotalCost * 100
+}
+
+// FindAnomalies flags sessions matching filter whose usage is far above their profile's full history
+func (t *Tracker) FindAnomalies(filter QueryFilter, sigma float64) ([]Anomaly, error) {
+	candidates, err := t.db.QuerySessions(filter)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query sessions: %w", err)
+	}
+
+	history, err := t.db.QuerySessions(QueryFilter{ProfileName: filter.ProfileName})
+	if err != nil {
+		return nil, fmt.Errorf("failed to query session history: %w", err)
+	}
+
+	return FindAnomalies(candidates, history, sigma), nil
+}
+
 func (t *Tracker) Close() error {
 	return t.db.Close()
 }
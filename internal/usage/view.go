@@ -0,0 +1,126 @@
+package usage
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"strconv"
+	"strings"
+)
+
+// DefaultTopSessions is how many sessions stats lists under Top Sessions unless set otherwise
+const DefaultTopSessions = 5
+
+// MaxTopSessions bounds the top-sessions setting, to keep the list a summary
+const MaxTopSessions = 50
+
+// Time ranges stats can default to; a number of days is written as e.g. "30d"
+const (
+	RangeAll   = "all"
+	RangeToday = "today"
+	RangeWeek  = "week"
+	RangeMonth = "month"
+)
+
+// Sections of the stats overview that can be hidden
+var Sections = []string{
+	"overview", "tokens", "tpm", "rpm", "cache", "heavy", "latency",
+	"profiles", "models", "top-sessions", "hourly", "costs", "anomalies",
+}
+
+// ViewSettings is what `manage stats` shows when no flags say otherwise
+type ViewSettings struct {
+	Range       string   `json:"range,omitempty"`        // Empty means all time
+	Hidden      []string `json:"hidden,omitempty"`       // Sections left out of the overview
+	TopSessions int      `json:"top-sessions,omitempty"` // Zero means DefaultTopSessions
+}
+
+func viewSettingsPath() (string, error) {
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return "", fmt.Errorf("failed to get home directory: %w", err)
+	}
+	return filepath.Join(home, ".clauderock", "stats-view.json"), nil
+}
+
+// LoadViewSettings returns the saved stats view; stats shows all time and every section until set
+func LoadViewSettings() (ViewSettings, error) {
+	path, err := viewSettingsPath()
+	if err != nil {
+		return ViewSettings{}, err
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return ViewSettings{}, nil
+		}
+		return ViewSettings{}, fmt.Errorf("failed to read stats view settings: %w", err)
+	}
+
+	var s ViewSettings
+	if err := json.Unmarshal(data, &s); err != nil {
+		return ViewSettings{}, fmt.Errorf("failed to parse stats view settings: %w", err)
+	}
+	return s, nil
+}
+
+// SaveViewSettings saves the stats view settings
+func SaveViewSettings(s ViewSettings) error {
+	path, err := viewSettingsPath()
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return fmt.Errorf("failed to create directory: %w", err)
+	}
+	data, err := json.MarshalIndent(s, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to marshal stats view settings: %w", err)
+	}
+	if err := os.WriteFile(path, data, 0644); err != nil {
+		return fmt.Errorf("failed to write stats view settings: %w", err)
+	}
+	return nil
+}
+
+// ParseRange checks a default time range and returns it normalized, with the number of days
+// for an "Nd" range and zero otherwise
+func ParseRange(r string) (string, int, error) {
+	r = strings.ToLower(strings.TrimSpace(r))
+	switch r {
+	case "", RangeAll:
+		return RangeAll, 0, nil
+	case RangeToday, RangeWeek, RangeMonth:
+		return r, 0, nil
+	}
+	if digits, ok := strings.CutSuffix(r, "d"); ok {
+		if days, err := strconv.Atoi(digits); err == nil && days > 0 {
+			return r, days, nil
+		}
+	}
+	return "", 0, fmt.Errorf("invalid range '%s': use all, today, week, month, or a number of days like 30d", r)
+}
+
+// CheckSection reports whether name is a section of the stats overview
+func CheckSection(name string) error {
+	if !slices.Contains(Sections, name) {
+		return fmt.Errorf("unknown section '%s' (sections: %s)", name, strings.Join(Sections, ", "))
+	}
+	return nil
+}
+
+// Shows reports whether the overview includes a section
+func (s ViewSettings) Shows(section string) bool {
+	return !slices.Contains(s.Hidden, section)
+}
+
+// TopSessionCount returns how many top sessions to list
+func (s ViewSettings) TopSessionCount() int {
+	if s.TopSessions <= 0 {
+		return DefaultTopSessions
+	}
+	return s.TopSessions
+}
@@ -0,0 +1,312 @@
+package vertex
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os/exec"
+	"regexp"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/OlaHulleberg/clauderock/internal/api"
+)
+
+const (
+	tokenTimeout    = 15 * time.Second
+	listTimeout     = 30 * time.Second
+	messagesTimeout = 60 * time.Second
+)
+
+// ModelInfo is a Claude model offered in the Vertex AI Model Garden
+type ModelInfo struct {
+	ID          string // e.g., "claude-sonnet-4-5@20250929"
+	Name        string // e.g., "Claude Sonnet 4.5 (20250929)"
+	Recommended string // Slot this is the newest model for: "main", "fast", "heavy", or ""
+}
+
+// TestResult reports the outcome of a successful test request
+type TestResult struct {
+	Latency      time.Duration
+	InputTokens  int64
+	OutputTokens int64
+}
+
+var (
+	regionPattern  = regexp.MustCompile(`^([a-z]+-[a-z]+\d+|global)$`)
+	projectPattern = regexp.MustCompile(`^[a-z][a-z0-9-]{4,28}[a-z0-9]$`)
+	versionPattern = regexp.MustCompile(`^\d{8}$`)
+)
+
+// ValidRegion reports whether region looks like a Vertex AI location, e.g. "us-east5" or "global"
+func ValidRegion(region string) bool {
+	return regionPattern.MatchString(region)
+}
+
+// ValidProject reports whether project looks like a Google Cloud project ID
+func ValidProject(project string) bool {
+	return projectPattern.MatchString(project)
+}
+
+// endpoint returns the Vertex AI API host serving a region
+func endpoint(region string) string {
+	if region == "global" {
+		return "https://aiplatform.googleapis.com"
+	}
+	return fmt.Sprintf("https://%s-aiplatform.googleapis.com", region)
+}
+
+// AccessToken returns an OAuth access token for the Application Default Credentials Claude Code uses,
+// falling back to the gcloud user login
+func AccessToken() (string, error) {
+	if _, err := exec.LookPath("gcloud"); err != nil {
+		return "", fmt.Errorf("gcloud not found in PATH; install the Google Cloud CLI and run: gcloud auth application-default login")
+	}
+
+	var lastErr error
+	for _, args := range [][]string{
+		{"auth", "application-default", "print-access-token"},
+		{"auth", "print-access-token"},
+	} {
+		ctx, cancel := context.WithTimeout(context.Background(), tokenTimeout)
+		out, err := exec.CommandContext(ctx, "gcloud", args...).Output()
+		cancel()
+		if token := strings.TrimSpace(string(out)); err == nil && token != "" {
+			return token, nil
+		}
+		lastErr = err
+	}
+	return "", fmt.Errorf("failed to get Google Cloud credentials (run: gcloud auth application-default login): %w", lastErr)
+}
+
+// publisherModel is one entry of the Model Garden publisher model listing
+type publisherModel struct {
+	Name      string `json:"name"`      // e.g., "publishers/anthropic/models/claude-sonnet-4-5"
+	VersionID string `json:"versionId"` // e.g., "20250929"
+}
+
+// FetchAvailableModels lists the Claude models the Model Garden offers in a region
+func FetchAvailableModels(project, region string) ([]ModelInfo, error) {
+	token, err := AccessToken()
+	if err != nil {
+		return nil, err
+	}
+	return listModels(project, region, token)
+}
+
+func listModels(project, region, token string) ([]ModelInfo, error) {
+	seen := make(map[string]bool)
+	var models []ModelInfo
+	pageToken := ""
+	for {
+		query := url.Values{"pageSize": {"100"}, "listAllVersions": {"true"}}
+		if pageToken != "" {
+			query.Set("pageToken", pageToken)
+		}
+		var page struct {
+			PublisherModels []publisherModel `json:"publisherModels"`
+			NextPageToken   string           `json:"nextPageToken"`
+		}
+		if err := getJSON(endpoint(region)+"/v1beta1/publishers/anthropic/models?"+query.Encode(), project, token, &page); err != nil {
+			return nil, fmt.Errorf("failed to list Model Garden models: %w", err)
+		}
+
+		for _, m := range page.PublisherModels {
+			name := m.Name[strings.LastIndex(m.Name, "/")+1:]
+			if !strings.HasPrefix(name, "claude-") {
+				continue
+			}
+			id := name
+			if versionPattern.MatchString(m.VersionID) {
+				id = name + "@" + m.VersionID
+			}
+			if !seen[id] {
+				seen[id] = true
+				models = append(models, ModelInfo{ID: id, Name: FriendlyName(id)})
+			}
+		}
+
+		pageToken = page.NextPageToken
+		if pageToken == "" {
+			break
+		}
+	}
+
+	if len(models) == 0 {
+		return nil, fmt.Errorf("no Claude models found in the Vertex AI Model Garden")
+	}
+	sort.Slice(models, func(i, j int) bool { return models[i].ID > models[j].ID })
+	markRecommended(models)
+	return models, nil
+}
+
+// markRecommended flags the newest Sonnet, Haiku, and Opus model for the main, fast, and heavy slots
+func markRecommended(models []ModelInfo) {
+	for slot, family := range map[string]string{"main": "sonnet", "fast": "haiku", "heavy": "opus"} {
+		best := -1
+		for i, m := range models {
+			if strings.Contains(m.ID, family) && (best < 0 || newer(m.ID, models[best].ID)) {
+				best = i
+			}
+		}
+		if best >= 0 {
+			models[best].Recommended = slot
+		}
+	}
+}
+
+// newer reports whether model a is a later release than b, by snapshot date when both have one
+func newer(a, b string) bool {
+	_, va, _ := strings.Cut(a, "@")
+	_, vb, _ := strings.Cut(b, "@")
+	if va != vb && va != "" && vb != "" {
+		return va > vb
+	}
+	return a > b
+}
+
+// FriendlyName turns a Vertex model ID into a display name
+// e.g., "claude-sonnet-4-5@20250929" -> "Claude Sonnet 4.5 (20250929)"
+func FriendlyName(modelID string) string {
+	base, version, _ := strings.Cut(modelID, "@")
+	var words []string
+	var digits []string
+	for _, part := range strings.Split(base, "-") {
+		if part == "" {
+			continue
+		}
+		if part[0] >= '0' && part[0] <= '9' {
+			digits = append(digits, part)
+			continue
+		}
+		if len(digits) > 0 {
+			words = append(words, strings.Join(digits, "."))
+			digits = nil
+		}
+		words = append(words, strings.ToUpper(part[:1])+part[1:])
+	}
+	if len(digits) > 0 {
+		words = append(words, strings.Join(digits, "."))
+	}
+	name := strings.Join(words, " ")
+	if version != "" {
+		name += " (" + version + ")"
+	}
+	return name
+}
+
+// ValidateModels checks that the given model IDs are offered in the region
+// Validation is skipped when no Google Cloud credentials can be read, leaving the error to Claude Code
+func ValidateModels(project, region string, modelIDs ...string) error {
+	token, err := AccessToken()
+	if err != nil {
+		return nil
+	}
+	models, err := listModels(project, region, token)
+	if err != nil {
+		return fmt.Errorf("failed to fetch models for validation: %w", err)
+	}
+
+	available := make(map[string]bool)
+	for _, m := range models {
+		available[m.ID] = true
+		// Models can also be addressed without their snapshot date
+		base, _, _ := strings.Cut(m.ID, "@")
+		available[base] = true
+	}
+
+	var missing []string
+	for _, id := range modelIDs {
+		if !available[id] {
+			missing = append(missing, id)
+		}
+	}
+	if len(missing) > 0 {
+		return fmt.Errorf("models not available: %v", missing)
+	}
+	return nil
+}
+
+// TestModel sends a minimal rawPredict request to verify the model is reachable
+// Returns the round-trip latency and token usage of the request
+func TestModel(project, region, modelID string) (TestResult, error) {
+	token, err := AccessToken()
+	if err != nil {
+		return TestResult{}, err
+	}
+
+	body, err := json.Marshal(map[string]any{
+		"anthropic_version": "vertex-2023-10-16",
+		"max_tokens":        5,
+		"messages": []map[string]string{
+			{"role": "user", "content": "Reply with OK."},
+		},
+	})
+	if err != nil {
+		return TestResult{}, fmt.Errorf("failed to build request: %w", err)
+	}
+
+	target := fmt.Sprintf("%s/v1/projects/%s/locations/%s/publishers/anthropic/models/%s:rawPredict",
+		endpoint(region), url.PathEscape(project), url.PathEscape(region), url.PathEscape(modelID))
+	req, err := http.NewRequest("POST", target, bytes.NewReader(body))
+	if err != nil {
+		return TestResult{}, fmt.Errorf("failed to create request: %w", err)
+	}
+	req.Header.Set("Authorization", "Bearer "+token)
+	req.Header.Set("Content-Type", "application/json")
+
+	client := &http.Client{Timeout: messagesTimeout}
+	start := time.Now()
+	resp, err := client.Do(req)
+	result := TestResult{Latency: time.Since(start)}
+	if err != nil {
+		return result, fmt.Errorf("request failed: %w", err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		data, _ := io.ReadAll(resp.Body)
+		return result, &api.HTTPError{StatusCode: resp.StatusCode, Body: string(data)}
+	}
+
+	var message struct {
+		Usage struct {
+			InputTokens  int64 `json:"input_tokens"`
+			OutputTokens int64 `json:"output_tokens"`
+		} `json:"usage"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&message); err == nil {
+		result.InputTokens = message.Usage.InputTokens
+		result.OutputTokens = message.Usage.OutputTokens
+	}
+	return result, nil
+}
+
+// getJSON sends an authenticated GET request billed to project and decodes the response into v
+func getJSON(target, project, token string, v any) error {
+	req, err := http.NewRequest("GET", target, nil)
+	if err != nil {
+		return fmt.Errorf("failed to create request: %w", err)
+	}
+	req.Header.Set("Authorization", "Bearer "+token)
+	req.Header.Set("x-goog-user-project", project)
+
+	client := &http.Client{Timeout: listTimeout}
+	resp, err := client.Do(req)
+	if err != nil {
+		return fmt.Errorf("request failed: %w", err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		data, _ := io.ReadAll(resp.Body)
+		return &api.HTTPError{StatusCode: resp.StatusCode, Body: string(data)}
+	}
+	if err := json.NewDecoder(resp.Body).Decode(v); err != nil {
+		return fmt.Errorf("failed to parse response: %w", err)
+	}
+	return nil
+}
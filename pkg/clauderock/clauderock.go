@@ -0,0 +1,119 @@
+// Package clauderock exposes clauderock's profile resolution, model resolution, launch
+// environment and usage history to other Go programs, such as IDE plugins and bots.
+//
+// It reads and writes the same files as the clauderock command (~/.clauderock), so a
+// program using it sees the user's profiles, bindings and tracked sessions.
+//
+//	name, profile, err := clauderock.ResolveProfile(".")
+//	env, err := clauderock.LaunchEnv(profile, os.Environ())
+//	cmd := exec.Command("claude")
+//	cmd.Env = env
+package clauderock
+
+import (
+	"fmt"
+
+	"github.com/OlaHulleberg/clauderock/internal/aws"
+	"github.com/OlaHulleberg/clauderock/internal/config"
+	"github.com/OlaHulleberg/clauderock/internal/launcher"
+	"github.com/OlaHulleberg/clauderock/internal/profiles"
+	"github.com/OlaHulleberg/clauderock/internal/usage"
+)
+
+// Profile is a clauderock profile's configuration
+type Profile = config.Config
+
+// Session is a tracked Claude Code session
+type Session = usage.Session
+
+// SessionFilter narrows a usage query; zero fields match everything
+type SessionFilter = usage.QueryFilter
+
+// Model slots accepted by ResolveModel
+const (
+	SlotMain  = "main"
+	SlotFast  = "fast"
+	SlotHeavy = "heavy"
+)
+
+// ProfileNames returns the names of all saved profiles
+func ProfileNames() ([]string, error) {
+	mgr, err := profiles.NewManager()
+	if err != nil {
+		return nil, fmt.Errorf("failed to create profile manager: %w", err)
+	}
+	return mgr.List()
+}
+
+// LoadProfile loads a saved profile by name
+func LoadProfile(name string) (*Profile, error) {
+	mgr, err := profiles.NewManager()
+	if err != nil {
+		return nil, fmt.Errorf("failed to create profile manager: %w", err)
+	}
+	return mgr.Load(name)
+}
+
+// ResolveProfile returns the profile a launch from dir would use, the same way the
+// clauderock command picks it: CLAUDEROCK_PROFILE, then a .clauderock binding in dir
+// or a parent, then the active profile
+func ResolveProfile(dir string) (string, *Profile, error) {
+	mgr, err := profiles.NewManager()
+	if err != nil {
+		return "", nil, fmt.Errorf("failed to create profile manager: %w", err)
+	}
+
+	binding, err := mgr.ProjectProfile(dir)
+	if err != nil {
+		return "", nil, err
+	}
+	var name string
+	if binding != nil {
+		name = binding.Profile
+	} else if name, err = mgr.GetCurrent(); err != nil {
+		return "", nil, err
+	}
+
+	profile, err := mgr.Load(name)
+	if err != nil {
+		return "", nil, fmt.Errorf("failed to load profile '%s': %w", name, err)
+	}
+	return name, profile, nil
+}
+
+// ResolveModel turns a friendly model name (e.g. "anthropic.claude-sonnet-4-5") into the ID
+// a profile sends to its provider, honouring the slot's pinned snapshot
+// A pin whose snapshot is no longer available is dropped from profile (in memory only)
+// Bedrock profiles resolve to an inference profile ID; API profiles use model names as given
+func ResolveModel(profile *Profile, slot, model string) (string, error) {
+	if _, err := profile.ModelForSlot(slot); err != nil {
+		return "", err
+	}
+	if profile.ProfileType != "bedrock" {
+		return model, nil
+	}
+	id, _, err := aws.ResolveSlotModel(profile, slot, model)
+	return id, err
+}
+
+// LaunchEnv returns the environment clauderock would start Claude Code with for a profile:
+// inherited (usually os.Environ()) filtered by the profile's env-policy and cleared of
+// variables that would override it, plus the profile's provider and model variables
+func LaunchEnv(profile *Profile, inherited []string) ([]string, error) {
+	return launcher.LaunchEnv(profile, inherited, profile.Model, profile.FastModel, profile.HeavyModel)
+}
+
+// Sessions returns the tracked sessions matching filter, newest first
+func Sessions(filter SessionFilter) ([]Session, error) {
+	db, err := usage.NewDatabase()
+	if err != nil {
+		return nil, err
+	}
+	defer db.Close()
+	return db.QuerySessions(filter)
+}
+
+// SessionCost estimates a session's cost in USD from its tokens, region and pricing tier
+func SessionCost(s Session) float64 {
+	return usage.SessionCost(s)
+}